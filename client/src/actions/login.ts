@@ -7,6 +7,11 @@ import { HTML_PAGES } from '../helpers/constants';
 export const processLoginRequest = createAction('PROCESS_LOGIN_REQUEST');
 export const processLoginFailure = createAction('PROCESS_LOGIN_FAILURE');
 export const processLoginSuccess = createAction('PROCESS_LOGIN_SUCCESS');
+export const processLoginTotpRequired = createAction('PROCESS_LOGIN_TOTP_REQUIRED');
+
+// TOTP_REQUIRED_ERROR is the error returned by the server when the user has the
+// two-factor authentication enabled and no code has been sent.
+const TOTP_REQUIRED_ERROR = 'totp code required';
 
 export const processLogin = (values: any) => async (dispatch: any) => {
     dispatch(processLoginRequest());
@@ -17,6 +22,12 @@ export const processLogin = (values: any) => async (dispatch: any) => {
         window.location.replace(dashboardUrl);
         dispatch(processLoginSuccess());
     } catch (error) {
+        if (!values.totp && error?.message?.includes(TOTP_REQUIRED_ERROR)) {
+            dispatch(processLoginTotpRequired());
+
+            return;
+        }
+
         dispatch(addErrorToast({ error }));
         dispatch(processLoginFailure());
     }
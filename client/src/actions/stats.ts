@@ -67,6 +67,8 @@ export const getStats = () => async (dispatch: any) => {
     }
 };
 
+export const updateSystemInfo = createAction('UPDATE_SYSTEM_INFO');
+
 export const resetStatsRequest = createAction('RESET_STATS_REQUEST');
 export const resetStatsFailure = createAction('RESET_STATS_FAILURE');
 export const resetStatsSuccess = createAction('RESET_STATS_SUCCESS');
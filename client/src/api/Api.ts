@@ -7,6 +7,11 @@ import { QUERY_LOGS_PAGE_LIMIT, HTML_PAGES, R_PATH_LAST_PART, THEMES } from '../
 import i18n from '../i18n';
 import { LANGUAGES } from '../helpers/twosky';
 
+// The server issues the CSRF token of the session in a cookie and requires it
+// in a header of the state-changing requests.
+axios.defaults.xsrfCookieName = 'agh_csrf';
+axios.defaults.xsrfHeaderName = 'X-CSRF-Token';
+
 class Api {
     baseUrl = BASE_URL;
 
@@ -621,6 +626,32 @@ class Api {
         return this.makeRequest(path, method);
     }
 
+    SYSTEM_INFO_STREAM_PATH = 'systeminfo/stream';
+
+    /**
+     * Returns the Server-Sent Events URL that pushes the snapshots of the host
+     * system metrics.
+     */
+    getSystemInfoStreamUrl() {
+        return `${this.baseUrl}/${this.SYSTEM_INFO_STREAM_PATH}`;
+    }
+
+    LIVE_EVENTS_PATH = 'events/ws';
+
+    /**
+     * Returns the WebSocket URL that pushes the live updates, like the changes
+     * of the statistics.  types is the optional list of the event types.
+     */
+    getLiveEventsUrl(types: string[] = []) {
+        const url = new URL(`${this.baseUrl}/${this.LIVE_EVENTS_PATH}`, window.location.href);
+        url.protocol = url.protocol === 'https:' ? 'wss:' : 'ws:';
+        if (types.length > 0) {
+            url.searchParams.set('types', types.join(','));
+        }
+
+        return url.toString();
+    }
+
     getStatsConfig() {
         const { path, method } = this.STATS.CONFIG;
 
@@ -742,6 +773,121 @@ class Api {
         return this.makeRequest(path, method, config);
     }
 
+    // API Tokens
+    API_TOKENS = { path: 'api_tokens', method: 'GET' };
+
+    API_TOKENS_CREATE = { path: 'api_tokens/create', method: 'POST' };
+
+    API_TOKENS_REVOKE = { path: 'api_tokens/revoke', method: 'POST' };
+
+    getApiTokens() {
+        const { path, method } = this.API_TOKENS;
+
+        return this.makeRequest(path, method);
+    }
+
+    createApiToken(data: { name: string; scope: string }) {
+        const { path, method } = this.API_TOKENS_CREATE;
+        const config = { data };
+
+        return this.makeRequest(path, method, config);
+    }
+
+    revokeApiToken(data: { id: string }) {
+        const { path, method } = this.API_TOKENS_REVOKE;
+        const config = { data };
+
+        return this.makeRequest(path, method, config);
+    }
+
+    // Web sessions
+    SESSIONS = { path: 'sessions', method: 'GET' };
+
+    SESSIONS_REVOKE = { path: 'sessions/revoke', method: 'POST' };
+
+    SESSIONS_REVOKE_OTHERS = { path: 'sessions/revoke_others', method: 'POST' };
+
+    getSessions() {
+        const { path, method } = this.SESSIONS;
+
+        return this.makeRequest(path, method);
+    }
+
+    revokeSession(data: { id: string }) {
+        const { path, method } = this.SESSIONS_REVOKE;
+        const config = { data };
+
+        return this.makeRequest(path, method, config);
+    }
+
+    revokeOtherSessions() {
+        const { path, method } = this.SESSIONS_REVOKE_OTHERS;
+
+        return this.makeRequest(path, method);
+    }
+
+    // Two-factor authentication
+    TOTP = { path: 'totp', method: 'GET' };
+
+    TOTP_SETUP = { path: 'totp/setup', method: 'POST' };
+
+    TOTP_ENABLE = { path: 'totp/enable', method: 'POST' };
+
+    TOTP_DISABLE = { path: 'totp/disable', method: 'POST' };
+
+    getTotp() {
+        const { path, method } = this.TOTP;
+
+        return this.makeRequest(path, method);
+    }
+
+    setupTotp(data: { password: string }) {
+        const { path, method } = this.TOTP_SETUP;
+        const config = { data };
+
+        return this.makeRequest(path, method, config);
+    }
+
+    enableTotp(data: { code: string }) {
+        const { path, method } = this.TOTP_ENABLE;
+        const config = { data };
+
+        return this.makeRequest(path, method, config);
+    }
+
+    disableTotp(data: { code: string }) {
+        const { path, method } = this.TOTP_DISABLE;
+        const config = { data };
+
+        return this.makeRequest(path, method, config);
+    }
+
+    // Configuration history
+    CONFIG_HISTORY = { path: 'config/history', method: 'GET' };
+
+    CONFIG_HISTORY_DIFF = { path: 'config/history/diff', method: 'GET' };
+
+    CONFIG_HISTORY_ROLLBACK = { path: 'config/history/rollback', method: 'POST' };
+
+    getConfigHistory() {
+        const { path, method } = this.CONFIG_HISTORY;
+
+        return this.makeRequest(path, method);
+    }
+
+    getConfigHistoryDiff(id: number) {
+        const { path, method } = this.CONFIG_HISTORY_DIFF;
+
+        return this.makeRequest(`${path}?id=${id}`, method);
+    }
+
+    rollbackConfig(data: { id: number }) {
+        const { path, method } = this.CONFIG_HISTORY_ROLLBACK;
+        const config = { data };
+
+        return this.makeRequest(path, method, config);
+    }
+
     // Settings Export/Import
     SETTINGS_EXPORT = { path: 'settings/export', method: 'GET' };
 
@@ -26,6 +26,7 @@ import GafamDominance from './GafamDominance';
 import EncryptedDns from './EncryptedDns';
 import DnssecStats from './DnssecStats';
 import { AccessData, DashboardData, StatsData } from '../../initialState';
+import apiClient from '../../api/Api';
 
 const STATS_POLLING_INTERVAL_MS = 5000;
 
@@ -87,13 +88,52 @@ const Dashboard = ({
     const getStatsRef = useRef(getStats);
     getStatsRef.current = getStats;
 
+    const getFilteringStatusRef = useRef(getFilteringStatus);
+    getFilteringStatusRef.current = getFilteringStatus;
+
     useEffect(() => {
-        const intervalId = setInterval(() => {
-            getStatsRef.current();
-        }, STATS_POLLING_INTERVAL_MS);
+        let intervalId: ReturnType<typeof setInterval> | null = null;
+
+        // Fall back to polling if the browser or a proxy doesn't support
+        // WebSockets.
+        const startPolling = () => {
+            if (!intervalId) {
+                intervalId = setInterval(() => {
+                    getStatsRef.current();
+                }, STATS_POLLING_INTERVAL_MS);
+            }
+        };
+
+        let ws: WebSocket | null = null;
+        if (typeof WebSocket === 'undefined') {
+            startPolling();
+        } else {
+            ws = new WebSocket(apiClient.getLiveEventsUrl(['stats', 'filter_update']));
+
+            ws.onmessage = (event: MessageEvent) => {
+                try {
+                    const { type, data } = JSON.parse(event.data);
+                    if (type === 'stats') {
+                        getStatsRef.current();
+                    } else if (type === 'filter_update' && data?.done === data?.total) {
+                        getFilteringStatusRef.current();
+                    }
+                } catch {
+                    // Ignore malformed events.
+                }
+            };
+
+            ws.onclose = startPolling;
+        }
 
         return () => {
-            clearInterval(intervalId);
+            if (ws) {
+                ws.onclose = null;
+                ws.close();
+            }
+            if (intervalId) {
+                clearInterval(intervalId);
+            }
             if (refreshTimeoutRef.current) {
                 clearTimeout(refreshTimeoutRef.current);
             }
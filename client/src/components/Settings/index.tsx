@@ -81,6 +81,49 @@ interface SettingsProps {
     };
 }
 
+interface ApiToken {
+    id: string;
+    name: string;
+    scope: string;
+    user: string;
+    created_at: string;
+}
+
+const API_TOKEN_SCOPES = ['read', 'stats', 'admin'];
+
+interface WebSession {
+    id: string;
+    user: string;
+    remote_ip?: string;
+    user_agent: string;
+    last_activity: string;
+    expires: string;
+    current: boolean;
+}
+
+interface TotpSetup {
+    secret: string;
+    uri: string;
+    qr_code: string;
+    backup_codes: string[];
+}
+
+interface ConfigRevision {
+    id: number;
+    time: string;
+    source: 'file' | 'change' | 'rollback';
+    user?: string;
+    remote_ip?: string;
+    keys?: string[];
+    restored_from?: number;
+}
+
+interface ConfigChange {
+    key: string;
+    before: unknown;
+    after: unknown;
+}
+
 interface SettingsState {
     currentPassword: string;
     newPassword: string;
@@ -99,6 +142,32 @@ interface SettingsState {
     importExportMessage: string;
     importExportMessageType: 'success' | 'error' | '';
     importExportProcessing: boolean;
+    apiTokens: ApiToken[];
+    apiTokenName: string;
+    apiTokenScope: string;
+    apiTokenSecret: string;
+    apiTokenMessage: string;
+    apiTokenMessageType: 'success' | 'error' | '';
+    apiTokenProcessing: boolean;
+    sessions: WebSession[];
+    sessionMessage: string;
+    sessionProcessing: boolean;
+    totpAvailable: boolean;
+    totpEnabled: boolean;
+    totpBackupCodesLeft: number;
+    totpSetup: TotpSetup | null;
+    totpPassword: string;
+    totpCode: string;
+    totpMessage: string;
+    totpMessageType: 'success' | 'error' | '';
+    totpProcessing: boolean;
+    configHistoryEnabled: boolean;
+    configRevisions: ConfigRevision[];
+    configDiffId: number | null;
+    configDiff: ConfigChange[];
+    configHistoryMessage: string;
+    configHistoryMessageType: 'success' | 'error' | '';
+    configHistoryProcessing: boolean;
 }
 
 class Settings extends Component<SettingsProps, SettingsState> {
@@ -120,6 +189,32 @@ class Settings extends Component<SettingsProps, SettingsState> {
         importExportMessage: '',
         importExportMessageType: '',
         importExportProcessing: false,
+        apiTokens: [],
+        apiTokenName: '',
+        apiTokenScope: 'read',
+        apiTokenSecret: '',
+        apiTokenMessage: '',
+        apiTokenMessageType: '',
+        apiTokenProcessing: false,
+        sessions: [],
+        sessionMessage: '',
+        sessionProcessing: false,
+        totpAvailable: false,
+        totpEnabled: false,
+        totpBackupCodesLeft: 0,
+        totpSetup: null,
+        totpPassword: '',
+        totpCode: '',
+        totpMessage: '',
+        totpMessageType: '',
+        totpProcessing: false,
+        configHistoryEnabled: false,
+        configRevisions: [],
+        configDiffId: null,
+        configDiff: [],
+        configHistoryMessage: '',
+        configHistoryMessageType: '',
+        configHistoryProcessing: false,
     };
 
     componentDidMount() {
@@ -137,6 +232,11 @@ class Settings extends Component<SettingsProps, SettingsState> {
         if (profileName) {
             this.setState({ username: profileName });
         }
+
+        this.loadApiTokens();
+        this.loadSessions();
+        this.loadTotp();
+        this.loadConfigHistory();
     }
 
     componentDidUpdate(prevProps: SettingsProps) {
@@ -707,6 +807,540 @@ class Settings extends Component<SettingsProps, SettingsState> {
         );
     };
 
+    loadApiTokens = async () => {
+        try {
+            const data = await apiClient.getApiTokens();
+            this.setState({ apiTokens: data?.tokens || [] });
+        } catch (error) {
+            this.setState({ apiTokens: [] });
+        }
+    };
+
+    onCreateApiToken = async (e: React.FormEvent) => {
+        e.preventDefault();
+        const { t } = this.props;
+        const { apiTokenName, apiTokenScope } = this.state;
+
+        this.setState({
+            apiTokenProcessing: true,
+            apiTokenSecret: '',
+            apiTokenMessage: '',
+            apiTokenMessageType: '',
+        });
+
+        try {
+            const data = await apiClient.createApiToken({ name: apiTokenName.trim(), scope: apiTokenScope });
+            this.setState({
+                apiTokenName: '',
+                apiTokenSecret: data.token,
+                apiTokenMessage: t('api_token_created') as string,
+                apiTokenMessageType: 'success',
+            });
+            await this.loadApiTokens();
+        } catch (error) {
+            this.setState({
+                apiTokenMessage: t('api_token_create_error') as string,
+                apiTokenMessageType: 'error',
+            });
+        }
+
+        this.setState({ apiTokenProcessing: false });
+    };
+
+    onRevokeApiToken = async (token: ApiToken) => {
+        const { t } = this.props;
+        if (!window.confirm(t('api_token_revoke_confirm', { name: token.name }) as string)) {
+            return;
+        }
+
+        this.setState({ apiTokenProcessing: true, apiTokenMessage: '', apiTokenMessageType: '' });
+
+        try {
+            await apiClient.revokeApiToken({ id: token.id });
+            await this.loadApiTokens();
+        } catch (error) {
+            this.setState({
+                apiTokenMessage: t('api_token_revoke_error') as string,
+                apiTokenMessageType: 'error',
+            });
+        }
+
+        this.setState({ apiTokenProcessing: false });
+    };
+
+    renderApiTokensCard = () => {
+        const { t } = this.props;
+        const {
+            apiTokens,
+            apiTokenName,
+            apiTokenScope,
+            apiTokenSecret,
+            apiTokenMessage,
+            apiTokenMessageType,
+            apiTokenProcessing,
+        } = this.state;
+
+        return (
+            <Card title={t('api_tokens') as string} bodyType="card-body box-body--settings">
+                <p className="form__desc form__desc--top">{t('api_tokens_desc')}</p>
+                {apiTokens.length === 0 ? (
+                    <p className="form__desc">{t('api_tokens_empty')}</p>
+                ) : (
+                    <ul className="list-unstyled">
+                        {apiTokens.map((token) => (
+                            <li key={token.id} className="d-flex align-items-center mb-2">
+                                <span className="mr-auto">
+                                    <strong>{token.name}</strong> ({t(`api_token_scope_${token.scope}`)},{' '}
+                                    {token.user})
+                                </span>
+                                <button
+                                    type="button"
+                                    className="btn btn-sm btn-outline-danger"
+                                    onClick={() => this.onRevokeApiToken(token)}
+                                    disabled={apiTokenProcessing}>
+                                    {t('api_token_revoke')}
+                                </button>
+                            </li>
+                        ))}
+                    </ul>
+                )}
+                <hr />
+                <form onSubmit={this.onCreateApiToken}>
+                    <div className="form-group">
+                        <label className="form__label" htmlFor="apiTokenName">
+                            {t('api_token_name')}
+                        </label>
+                        <input
+                            type="text"
+                            id="apiTokenName"
+                            className="form-control"
+                            value={apiTokenName}
+                            onChange={(e) => this.setState({ apiTokenName: e.target.value })}
+                            disabled={apiTokenProcessing}
+                        />
+                    </div>
+                    <div className="form-group">
+                        <label className="form__label" htmlFor="apiTokenScope">
+                            {t('api_token_scope')}
+                        </label>
+                        <select
+                            id="apiTokenScope"
+                            className="form-control custom-select"
+                            value={apiTokenScope}
+                            onChange={(e) => this.setState({ apiTokenScope: e.target.value })}
+                            disabled={apiTokenProcessing}>
+                            {API_TOKEN_SCOPES.map((scope) => (
+                                <option key={scope} value={scope}>
+                                    {t(`api_token_scope_${scope}`)}
+                                </option>
+                            ))}
+                        </select>
+                    </div>
+                    <button
+                        type="submit"
+                        className="btn btn-success btn-standard"
+                        disabled={apiTokenProcessing || !apiTokenName.trim()}>
+                        {t('api_token_create')}
+                    </button>
+                </form>
+                {apiTokenMessage && (
+                    <div
+                        className={cn('settings__message', {
+                            'settings__message--success': apiTokenMessageType === 'success',
+                            'settings__message--error': apiTokenMessageType === 'error',
+                        })}>
+                        {apiTokenMessage}
+                        {apiTokenSecret && <code className="d-block mt-2">{apiTokenSecret}</code>}
+                    </div>
+                )}
+            </Card>
+        );
+    };
+
+    loadSessions = async () => {
+        try {
+            const data = await apiClient.getSessions();
+            this.setState({ sessions: data?.sessions || [] });
+        } catch (error) {
+            this.setState({ sessions: [] });
+        }
+    };
+
+    revokeSessions = async (revoke: () => Promise<unknown>) => {
+        const { t } = this.props;
+
+        this.setState({ sessionProcessing: true, sessionMessage: '' });
+
+        try {
+            await revoke();
+            await this.loadSessions();
+        } catch (error) {
+            this.setState({ sessionMessage: t('session_revoke_error') as string });
+        }
+
+        this.setState({ sessionProcessing: false });
+    };
+
+    renderSessionsCard = () => {
+        const { t } = this.props;
+        const { sessions, sessionMessage, sessionProcessing } = this.state;
+
+        return (
+            <Card title={t('sessions') as string} bodyType="card-body box-body--settings">
+                <p className="form__desc form__desc--top">{t('sessions_desc')}</p>
+                <ul className="list-unstyled">
+                    {sessions.map((session) => (
+                        <li key={session.id} className="d-flex align-items-center mb-2">
+                            <span className="mr-auto">
+                                <strong>{session.user}</strong>
+                                {session.remote_ip && `, ${session.remote_ip}`}
+                                {session.current && ` (${t('session_current')})`}
+                                <br />
+                                <small className="text-muted">
+                                    {session.user_agent || t('session_unknown_client')},{' '}
+                                    {t('session_last_activity', {
+                                        time: new Date(session.last_activity).toLocaleString(),
+                                    })}
+                                </small>
+                            </span>
+                            {!session.current && (
+                                <button
+                                    type="button"
+                                    className="btn btn-sm btn-outline-danger"
+                                    onClick={() =>
+                                        this.revokeSessions(() => apiClient.revokeSession({ id: session.id }))
+                                    }
+                                    disabled={sessionProcessing}>
+                                    {t('session_revoke')}
+                                </button>
+                            )}
+                        </li>
+                    ))}
+                </ul>
+                <button
+                    type="button"
+                    className="btn btn-outline-danger btn-standard"
+                    onClick={() => this.revokeSessions(() => apiClient.revokeOtherSessions())}
+                    disabled={sessionProcessing || sessions.every((session) => session.current)}>
+                    {t('session_revoke_others')}
+                </button>
+                {sessionMessage && (
+                    <div className="settings__message settings__message--error">{sessionMessage}</div>
+                )}
+            </Card>
+        );
+    };
+
+    loadTotp = async () => {
+        try {
+            const data = await apiClient.getTotp();
+            this.setState({
+                totpAvailable: !!data?.available,
+                totpEnabled: !!data?.enabled,
+                totpBackupCodesLeft: data?.backup_codes_left || 0,
+            });
+        } catch (error) {
+            this.setState({ totpAvailable: false });
+        }
+    };
+
+    onSetupTotp = async (e: React.FormEvent) => {
+        e.preventDefault();
+        const { t } = this.props;
+
+        this.setState({ totpProcessing: true, totpMessage: '' });
+
+        try {
+            const totpSetup = await apiClient.setupTotp({ password: this.state.totpPassword });
+            this.setState({ totpSetup, totpPassword: '' });
+        } catch (error) {
+            this.setState({
+                totpMessage: t('totp_setup_error') as string,
+                totpMessageType: 'error',
+            });
+        }
+
+        this.setState({ totpProcessing: false });
+    };
+
+    onConfirmTotp = async (e: React.FormEvent) => {
+        e.preventDefault();
+        const { t } = this.props;
+        const { totpEnabled, totpCode } = this.state;
+
+        this.setState({ totpProcessing: true, totpMessage: '' });
+
+        try {
+            if (totpEnabled) {
+                await apiClient.disableTotp({ code: totpCode });
+            } else {
+                await apiClient.enableTotp({ code: totpCode });
+            }
+
+            this.setState({
+                totpSetup: null,
+                totpCode: '',
+                totpMessage: t(totpEnabled ? 'totp_disabled' : 'totp_enabled') as string,
+                totpMessageType: 'success',
+            });
+            await this.loadTotp();
+        } catch (error) {
+            this.setState({
+                totpMessage: t('totp_code_error') as string,
+                totpMessageType: 'error',
+            });
+        }
+
+        this.setState({ totpProcessing: false });
+    };
+
+    renderTotpCard = () => {
+        const { t } = this.props;
+        const {
+            totpAvailable,
+            totpEnabled,
+            totpBackupCodesLeft,
+            totpSetup,
+            totpPassword,
+            totpCode,
+            totpMessage,
+            totpMessageType,
+            totpProcessing,
+        } = this.state;
+
+        if (!totpAvailable) {
+            return null;
+        }
+
+        const codeForm = (
+            <form onSubmit={this.onConfirmTotp}>
+                <div className="form-group">
+                    <label className="form__label" htmlFor="totpCode">
+                        {t(totpEnabled ? 'totp_disable_code' : 'totp_code_label')}
+                    </label>
+                    <input
+                        type="text"
+                        id="totpCode"
+                        className="form-control"
+                        value={totpCode}
+                        onChange={(e) => this.setState({ totpCode: e.target.value })}
+                        autoComplete="one-time-code"
+                        disabled={totpProcessing}
+                    />
+                </div>
+                <button
+                    type="submit"
+                    className={cn('btn btn-standard', totpEnabled ? 'btn-outline-danger' : 'btn-success')}
+                    disabled={totpProcessing || !totpCode.trim()}>
+                    {t(totpEnabled ? 'totp_disable' : 'totp_enable')}
+                </button>
+            </form>
+        );
+
+        return (
+            <Card title={t('totp') as string} bodyType="card-body box-body--settings">
+                <p className="form__desc form__desc--top">{t('totp_desc')}</p>
+                {totpEnabled && (
+                    <Fragment>
+                        <p className="form__desc">
+                            {t('totp_status_enabled', { count: totpBackupCodesLeft })}
+                        </p>
+                        {codeForm}
+                    </Fragment>
+                )}
+                {!totpEnabled && !totpSetup && (
+                    <form onSubmit={this.onSetupTotp}>
+                        <div className="form-group">
+                            <label className="form__label" htmlFor="totpPassword">
+                                {t('current_password')}
+                            </label>
+                            <input
+                                type="password"
+                                id="totpPassword"
+                                className="form-control"
+                                value={totpPassword}
+                                onChange={(e) => this.setState({ totpPassword: e.target.value })}
+                                autoComplete="current-password"
+                                disabled={totpProcessing}
+                            />
+                        </div>
+                        <button
+                            type="submit"
+                            className="btn btn-success btn-standard"
+                            disabled={totpProcessing || !totpPassword}>
+                            {t('totp_setup')}
+                        </button>
+                    </form>
+                )}
+                {!totpEnabled && totpSetup && (
+                    <Fragment>
+                        <p className="form__desc">{t('totp_scan')}</p>
+                        {totpSetup.qr_code && (
+                            <img
+                                className="d-block mb-3"
+                                width={200}
+                                height={200}
+                                alt={totpSetup.uri}
+                                src={`data:image/svg+xml;charset=utf-8,${encodeURIComponent(totpSetup.qr_code)}`}
+                            />
+                        )}
+                        <p className="form__desc">
+                            {t('totp_secret')} <code>{totpSetup.secret}</code>
+                        </p>
+                        <p className="form__desc">{t('totp_backup_codes')}</p>
+                        <pre>{totpSetup.backup_codes.join('\n')}</pre>
+                        {codeForm}
+                    </Fragment>
+                )}
+                {totpMessage && (
+                    <div
+                        className={cn('settings__message', {
+                            'settings__message--success': totpMessageType === 'success',
+                            'settings__message--error': totpMessageType === 'error',
+                        })}>
+                        {totpMessage}
+                    </div>
+                )}
+            </Card>
+        );
+    };
+
+    loadConfigHistory = async () => {
+        try {
+            const data = await apiClient.getConfigHistory();
+            this.setState({
+                configHistoryEnabled: !!data?.enabled,
+                configRevisions: data?.revisions || [],
+            });
+        } catch (error) {
+            this.setState({ configHistoryEnabled: false });
+        }
+    };
+
+    toggleConfigDiff = async (id: number) => {
+        const { t } = this.props;
+
+        if (this.state.configDiffId === id) {
+            this.setState({ configDiffId: null, configDiff: [] });
+            return;
+        }
+
+        try {
+            const data = await apiClient.getConfigHistoryDiff(id);
+            this.setState({ configDiffId: id, configDiff: data?.changes || [], configHistoryMessage: '' });
+        } catch (error) {
+            this.setState({
+                configHistoryMessage: t('config_history_diff_error') as string,
+                configHistoryMessageType: 'error',
+            });
+        }
+    };
+
+    onRollbackConfig = async (id: number) => {
+        const { t } = this.props;
+
+        if (!window.confirm(t('config_history_rollback_confirm', { id }) as string)) {
+            return;
+        }
+
+        this.setState({ configHistoryProcessing: true, configHistoryMessage: '' });
+
+        try {
+            await apiClient.rollbackConfig({ id });
+            this.setState({
+                configHistoryMessage: t('config_history_rollback_started') as string,
+                configHistoryMessageType: 'success',
+            });
+            setTimeout(() => window.location.reload(), 10000);
+        } catch (error) {
+            // The message of the error is "path | response | status".
+            const [, reason = error.message] = String(error.message).split(' | ');
+
+            this.setState({
+                configHistoryMessage: t('config_history_rollback_error', { error: reason }) as string,
+                configHistoryMessageType: 'error',
+                configHistoryProcessing: false,
+            });
+        }
+    };
+
+    renderConfigHistoryCard = () => {
+        const { t } = this.props;
+        const {
+            configHistoryEnabled,
+            configRevisions,
+            configDiffId,
+            configDiff,
+            configHistoryMessage,
+            configHistoryMessageType,
+            configHistoryProcessing,
+        } = this.state;
+
+        if (!configHistoryEnabled) {
+            return null;
+        }
+
+        const formatValue = (v: unknown) => (v === null || v === undefined ? '—' : JSON.stringify(v));
+
+        return (
+            <Card title={t('config_history') as string} bodyType="card-body box-body--settings">
+                <p className="form__desc form__desc--top">{t('config_history_desc')}</p>
+                {configRevisions.length === 0 && <p className="form__desc">{t('config_history_empty')}</p>}
+                <ul className="list-unstyled">
+                    {configRevisions.map((rev, i) => (
+                        <li key={rev.id} className="mb-2">
+                            <div className="d-flex align-items-center">
+                                <span className="mr-auto">
+                                    <strong>#{rev.id}</strong> {new Date(rev.time).toLocaleString()}
+                                    {rev.user && `, ${rev.user}`}
+                                    {rev.remote_ip && ` (${rev.remote_ip})`}
+                                    <br />
+                                    <small className="text-muted">
+                                        {t(`config_history_source_${rev.source}`, { id: rev.restored_from })}
+                                        {rev.keys?.length ? `: ${rev.keys.join(', ')}` : ''}
+                                    </small>
+                                </span>
+                                <button
+                                    type="button"
+                                    className="btn btn-sm btn-outline-secondary mr-2"
+                                    onClick={() => this.toggleConfigDiff(rev.id)}>
+                                    {t('config_history_changes')}
+                                </button>
+                                {i > 0 && (
+                                    <button
+                                        type="button"
+                                        className="btn btn-sm btn-outline-danger"
+                                        onClick={() => this.onRollbackConfig(rev.id)}
+                                        disabled={configHistoryProcessing}>
+                                        {t('config_history_rollback')}
+                                    </button>
+                                )}
+                            </div>
+                            {configDiffId === rev.id && (
+                                <pre className="mt-2 mb-0">
+                                    {configDiff.length === 0
+                                        ? t('config_history_no_changes')
+                                        : configDiff
+                                              .map((c) => `${c.key}: ${formatValue(c.before)} → ${formatValue(c.after)}`)
+                                              .join('\n')}
+                                </pre>
+                            )}
+                        </li>
+                    ))}
+                </ul>
+                {configHistoryMessage && (
+                    <div
+                        className={cn('settings__message', {
+                            'settings__message--success': configHistoryMessageType === 'success',
+                            'settings__message--error': configHistoryMessageType === 'error',
+                        })}>
+                        {configHistoryMessage}
+                    </div>
+                )}
+            </Card>
+        );
+    };
+
     render() {
         const {
             settings,
@@ -787,6 +1421,22 @@ class Settings extends Component<SettingsProps, SettingsState> {
                             <div className="col-md-12">
                                 {this.renderImportExportCard()}
                             </div>
+
+                            <div className="col-md-12">
+                                {this.renderApiTokensCard()}
+                            </div>
+
+                            <div className="col-md-12">
+                                {this.renderSessionsCard()}
+                            </div>
+
+                            <div className="col-md-12">
+                                {this.renderTotpCard()}
+                            </div>
+
+                            <div className="col-md-12">
+                                {this.renderConfigHistoryCard()}
+                            </div>
                         </div>
                     </div>
                 )}
@@ -12,6 +12,7 @@ import {
     renderUsage,
 } from '../../helpers/systemInfoHelpers';
 import { SystemInfoData } from '../../initialState';
+import apiClient from '../../api/Api';
 import './SystemOverview.css';
 
 const RELOAD_INTERVAL_MS = 1000;
@@ -20,6 +21,7 @@ interface SystemOverviewProps {
     systemInfo: SystemInfoData | null;
     processing: boolean;
     getStats: () => void;
+    updateSystemInfo: (systemInfo: any) => void;
 }
 
 const getTier = (percent: number) => {
@@ -257,18 +259,48 @@ const InfoCard = ({ title, rows }: InfoCardProps) => (
     </div>
 );
 
-const SystemOverview = ({ systemInfo, processing, getStats }: SystemOverviewProps) => {
+const SystemOverview = ({ systemInfo, processing, getStats, updateSystemInfo }: SystemOverviewProps) => {
     const { t } = useTranslation();
     const intervalRef = useRef<ReturnType<typeof setInterval> | null>(null);
 
     useEffect(() => {
         getStats();
 
-        intervalRef.current = setInterval(() => {
-            getStats();
-        }, RELOAD_INTERVAL_MS);
+        // Fall back to polling if the browser or a proxy doesn't support
+        // Server-Sent Events.
+        const startPolling = () => {
+            if (!intervalRef.current) {
+                intervalRef.current = setInterval(() => {
+                    getStats();
+                }, RELOAD_INTERVAL_MS);
+            }
+        };
+
+        let es: EventSource | null = null;
+        if (typeof EventSource === 'undefined') {
+            startPolling();
+        } else {
+            es = new EventSource(apiClient.getSystemInfoStreamUrl());
+
+            es.addEventListener('systeminfo', (event: MessageEvent) => {
+                try {
+                    updateSystemInfo(JSON.parse(event.data));
+                } catch {
+                    // Ignore malformed snapshots.
+                }
+            });
+
+            es.onerror = () => {
+                // The browser reconnects by itself unless the stream has been
+                // closed for good.
+                if (es?.readyState === EventSource.CLOSED) {
+                    startPolling();
+                }
+            };
+        }
 
         return () => {
+            es?.close();
             if (intervalRef.current) {
                 clearInterval(intervalRef.current);
             }
@@ -378,6 +410,13 @@ const SystemOverview = ({ systemInfo, processing, getStats }: SystemOverviewProp
                             icon: DISK_ICON,
                         },
                         { label: t('system_overview_disk_path'), value: systemInfo.diskPath || '–', icon: PATH_ICON },
+                        ...(systemInfo.disks || [])
+                            .filter((disk) => disk.path !== systemInfo.diskPath)
+                            .map((disk) => ({
+                                label: disk.path,
+                                value: renderUsage(disk.used, disk.total, disk.usagePercent),
+                                icon: DISK_ICON,
+                            })),
                     ]}
                 />
 
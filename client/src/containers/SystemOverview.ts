@@ -1,5 +1,5 @@
 import { connect } from 'react-redux';
-import { getStats } from '../actions/stats';
+import { getStats, updateSystemInfo } from '../actions/stats';
 import SystemOverview from '../components/SystemOverview';
 import { RootState } from '../initialState';
 
@@ -8,6 +8,6 @@ const mapStateToProps = (state: RootState) => ({
     processing: state.stats.processingStats,
 });
 
-const mapDispatchToProps = { getStats };
+const mapDispatchToProps = { getStats, updateSystemInfo };
 
 export default connect(mapStateToProps, mapDispatchToProps)(SystemOverview);
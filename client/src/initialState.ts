@@ -207,6 +207,15 @@ export type NormalizedTopClients = {
     configured: Record<string, number>;
 };
 
+export type SystemDiskData = {
+    path: string;
+    total: number;
+    used: number;
+    free: number;
+    usagePercent: number;
+    filesystem: string;
+};
+
 export type SystemInfoData = {
     os: string;
     osVersion: string;
@@ -225,6 +234,7 @@ export type SystemInfoData = {
     diskUsage: number;
     uptimeSeconds: number;
     diskFree: number;
+    disks: SystemDiskData[];
     localIps: string[];
     publicIp: string;
     isContainer: boolean;
@@ -513,6 +523,7 @@ export type InstallState = {
 export type LoginState = {
     login: {
         processingLogin: false;
+        totpRequired: boolean;
         email: string;
         password: string;
     };
@@ -7,14 +7,16 @@ import { validateRequiredValue } from '../../helpers/validators';
 export type LoginFormValues = {
     username: string;
     password: string;
+    totp: string;
 };
 
 type LoginFormProps = {
     onSubmit: (data: LoginFormValues) => void;
     processing: boolean;
+    totpRequired: boolean;
 };
 
-const Form = ({ onSubmit, processing }: LoginFormProps) => {
+const Form = ({ onSubmit, processing, totpRequired }: LoginFormProps) => {
     const { t } = useTranslation();
     const {
         handleSubmit,
@@ -25,6 +27,7 @@ const Form = ({ onSubmit, processing }: LoginFormProps) => {
         defaultValues: {
             username: '',
             password: '',
+            totp: '',
         },
     });
 
@@ -72,6 +75,30 @@ const Form = ({ onSubmit, processing }: LoginFormProps) => {
                     />
                 </div>
 
+                {totpRequired && (
+                    <div className="form__group form__group--settings">
+                        <Controller
+                            name="totp"
+                            control={control}
+                            rules={{ validate: validateRequiredValue }}
+                            render={({ field, fieldState }) => (
+                                <Input
+                                    {...field}
+                                    data-testid="totp"
+                                    type="text"
+                                    label={t('totp_code_label')}
+                                    placeholder={t('totp_code_placeholder')}
+                                    error={fieldState.error?.message}
+                                    autoComplete="one-time-code"
+                                    inputMode="numeric"
+                                    autoFocus
+                                    disabled={processing}
+                                />
+                            )}
+                        />
+                    </div>
+                )}
+
                 <div className="form-footer">
                     <button
                         data-testid="sign_in"
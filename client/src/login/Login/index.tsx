@@ -16,11 +16,11 @@ import { LoginState } from '../../initialState';
 
 export const Login = () => {
     const dispatch = useDispatch();
-    const { processingLogin } = useSelector((state: LoginState) => state.login);
+    const { processingLogin, totpRequired } = useSelector((state: LoginState) => state.login);
     const [isForgotPasswordVisible, setIsForgotPasswordVisible] = useState(false);
 
-    const handleSubmit = ({ username: name, password }: LoginFormValues) => {
-        dispatch(actionCreators.processLogin({ name, password }));
+    const handleSubmit = ({ username: name, password, totp }: LoginFormValues) => {
+        dispatch(actionCreators.processLogin({ name, password, ...(totpRequired && { totp }) }));
     };
 
     const toggleText = () => {
@@ -34,7 +34,7 @@ export const Login = () => {
                     <Logo className="login__logo" />
                 </div>
 
-                <Form onSubmit={handleSubmit} processing={processingLogin} />
+                <Form onSubmit={handleSubmit} processing={processingLogin} totpRequired={totpRequired} />
 
                 <div className="login__info">
                     <button type="button" className="btn btn-link login__link" onClick={toggleText}>
@@ -20,9 +20,15 @@ const login = handleActions(
             ...payload,
             processingLogin: false,
         }),
+        [actions.processLoginTotpRequired.toString()]: (state: any) => ({
+            ...state,
+            processingLogin: false,
+            totpRequired: true,
+        }),
     },
     {
         processingLogin: false,
+        totpRequired: false,
         email: '',
         password: '',
     },
@@ -49,6 +49,16 @@ const normalizeSystemInfo = (system: any) => {
         diskUsed: system.disk_used || 0,
         diskUsage: system.disk_usage || 0,
         diskFree: system.disk_free || 0,
+        disks: Array.isArray(system.all_disks)
+            ? system.all_disks.map((disk: any) => ({
+                  path: disk.path || '',
+                  total: disk.total || 0,
+                  used: disk.used || 0,
+                  free: disk.free || 0,
+                  usagePercent: disk.usage_percent || 0,
+                  filesystem: disk.filesystem || '',
+              }))
+            : [],
         uptimeSeconds: system.uptime_seconds || 0,
         localIps: Array.isArray(system.local_ips) ? system.local_ips : [],
         publicIp: system.public_ip || '',
@@ -152,6 +162,10 @@ const stats = handleActions(
 
             return newState;
         },
+        [actions.updateSystemInfo.toString()]: (state: any, { payload }: any) => ({
+            ...state,
+            systemInfo: normalizeSystemInfo(payload),
+        }),
         [actions.resetStatsRequest.toString()]: (state: any) => ({
             ...state,
             processingReset: true,
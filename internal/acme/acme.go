@@ -25,7 +25,6 @@ import (
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/challenge/http01"
 	"github.com/go-acme/lego/v4/lego"
-	"github.com/go-acme/lego/v4/providers/dns/cloudflare"
 	"github.com/go-acme/lego/v4/registration"
 )
 
@@ -44,6 +43,11 @@ const (
 	// record via the Cloudflare API.  It supports wildcard domains and does
 	// not require inbound HTTP access.
 	ChallengeCloudflareDNS01 ChallengeType = "dns-01-cloudflare"
+
+	// ChallengeDNS01 proves control of a domain by creating a TXT record via
+	// the API of the DNS provider named in [Request.DNSProvider].  See
+	// [DNSProviders] for the list of supported providers.
+	ChallengeDNS01 ChallengeType = "dns-01"
 )
 
 // Request describes a certificate to issue or renew.  Requesting a
@@ -67,6 +71,14 @@ type Request struct {
 	// the requested domains' zones.
 	CloudflareAPIToken string
 
+	// DNSProvider is the name of the DNS provider used for [ChallengeDNS01].
+	// It must be one of [DNSProviders].
+	DNSProvider string
+
+	// DNSCredentials are the provider-specific credentials used for
+	// [ChallengeDNS01], such as API tokens.
+	DNSCredentials map[string]string
+
 	// AccountKeyPEM is a previously persisted ACME account private key.  If
 	// empty, a new key is generated and returned in [Result.AccountKeyPEM].
 	AccountKeyPEM string
@@ -79,7 +91,7 @@ type Request struct {
 	// DNSResolvers are the nameservers (as "host" or "host:port", port
 	// defaults to 53) used to check that a DNS-01 TXT record has propagated
 	// before asking the CA to validate it.  Only used for
-	// [ChallengeCloudflareDNS01].  If empty, the host's own system resolver
+	// [ChallengeCloudflareDNS01] and [ChallengeDNS01].  If empty, the host's own system resolver
 	// (e.g. /etc/resolv.conf) is used, same as the rest of AdGuard Home.
 	DNSResolvers []string
 
@@ -264,26 +276,37 @@ func (m *Manager) setChallengeProvider(client *lego.Client, req *Request) (err e
 			return errors.Error("acme: cloudflare api token is required for dns-01-cloudflare challenge")
 		}
 
-		cfCfg := cloudflare.NewDefaultConfig()
-		cfCfg.AuthToken = req.CloudflareAPIToken
-
-		var provider *cloudflare.DNSProvider
-		provider, err = cloudflare.NewDNSProviderConfig(cfCfg)
-		if err != nil {
-			return fmt.Errorf("acme: creating cloudflare dns provider: %w", err)
-		}
-
-		var opts []dns01.ChallengeOption
-		if len(req.DNSResolvers) > 0 {
-			opts = append(opts, dns01.AddRecursiveNameservers(req.DNSResolvers))
-		}
-
-		return client.Challenge.SetDNS01Provider(provider, opts...)
+		return setDNS01Provider(client, req, DNSProviderCloudflare, map[string]string{
+			"api_token": req.CloudflareAPIToken,
+		})
+	case ChallengeDNS01:
+		return setDNS01Provider(client, req, req.DNSProvider, req.DNSCredentials)
 	default:
 		return fmt.Errorf("acme: unsupported challenge type %q", req.Challenge)
 	}
 }
 
+// setDNS01Provider configures client to solve DNS-01 challenges using the
+// provider registered under name.
+func setDNS01Provider(
+	client *lego.Client,
+	req *Request,
+	name string,
+	creds map[string]string,
+) (err error) {
+	provider, err := newDNSProvider(name, creds)
+	if err != nil {
+		return err
+	}
+
+	var opts []dns01.ChallengeOption
+	if len(req.DNSResolvers) > 0 {
+		opts = append(opts, dns01.AddRecursiveNameservers(req.DNSResolvers))
+	}
+
+	return client.Challenge.SetDNS01Provider(provider, opts...)
+}
+
 // acmeUser implements [registration.User], the account identity lego uses to
 // interact with the ACME server.
 type acmeUser struct {
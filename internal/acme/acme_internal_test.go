@@ -3,6 +3,7 @@ package acme
 import (
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"testing"
 
 	"github.com/go-acme/lego/v4/challenge/http01"
@@ -49,3 +50,70 @@ func TestHTTPProvider(t *testing.T) {
 		t.Errorf("after CleanUp: got status %d, want %d", w.Code, http.StatusNotFound)
 	}
 }
+
+func TestNewDNSProvider(t *testing.T) {
+	testCases := []struct {
+		creds    map[string]string
+		name     string
+		provider string
+		wantErr  bool
+	}{{
+		creds:    map[string]string{"token": "test-token"},
+		name:     "duckdns",
+		provider: DNSProviderDuckDNS,
+		wantErr:  false,
+	}, {
+		creds:    map[string]string{"endpoint": "https://dns.example/acme"},
+		name:     "httpreq",
+		provider: DNSProviderHTTPReq,
+		wantErr:  false,
+	}, {
+		creds:    nil,
+		name:     "missing_creds",
+		provider: DNSProviderCloudflare,
+		wantErr:  true,
+	}, {
+		creds:    map[string]string{"token": "test-token"},
+		name:     "wrong_creds",
+		provider: DNSProviderGandi,
+		wantErr:  true,
+	}, {
+		creds:    map[string]string{"program": "/bin/sh"},
+		name:     "exec",
+		provider: "exec",
+		wantErr:  true,
+	}, {
+		creds:    map[string]string{"token": "test-token"},
+		name:     "unknown",
+		provider: "unknown",
+		wantErr:  true,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := newDNSProvider(tc.provider, tc.creds)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("got provider %T, want error", p)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Errorf("got error %q, want provider", err)
+			}
+		})
+	}
+}
+
+func TestDNSProviders(t *testing.T) {
+	names := DNSProviders()
+	if !slices.IsSorted(names) {
+		t.Errorf("got unsorted names %q", names)
+	}
+
+	if !slices.Contains(names, DNSProviderCloudflare) {
+		t.Errorf("got names %q, want %q among them", names, DNSProviderCloudflare)
+	}
+}
@@ -0,0 +1,177 @@
+package acme
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"sync"
+
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/providers/dns/cloudflare"
+	"github.com/go-acme/lego/v4/providers/dns/duckdns"
+	"github.com/go-acme/lego/v4/providers/dns/gandiv5"
+	"github.com/go-acme/lego/v4/providers/dns/httpreq"
+)
+
+// DNSProviderFactory creates a DNS-01 challenge provider from the credentials
+// configured by the user.  The meaning of the keys in creds is specific to
+// each provider.
+type DNSProviderFactory func(creds map[string]string) (p challenge.Provider, err error)
+
+// Names of the built-in DNS providers for [ChallengeDNS01].
+const (
+	DNSProviderCloudflare = "cloudflare"
+	DNSProviderDuckDNS    = "duckdns"
+	DNSProviderGandi      = "gandiv5"
+	DNSProviderHTTPReq    = "httpreq"
+)
+
+// dnsProvider is a registered DNS-01 challenge provider.
+type dnsProvider struct {
+	// newProvider creates the provider from the credentials.
+	newProvider DNSProviderFactory
+
+	// required are the keys of the credentials, which must not be empty.
+	required []string
+}
+
+// dnsProvidersMu protects dnsProviders.
+var dnsProvidersMu = &sync.RWMutex{}
+
+// dnsProviders are the registered DNS-01 challenge providers by name.
+var dnsProviders = map[string]*dnsProvider{
+	DNSProviderCloudflare: {
+		newProvider: newCloudflareProvider,
+		required:    []string{"api_token"},
+	},
+	DNSProviderDuckDNS: {
+		newProvider: newDuckDNSProvider,
+		required:    []string{"token"},
+	},
+	DNSProviderGandi: {
+		newProvider: newGandiProvider,
+		required:    []string{"personal_access_token"},
+	},
+	DNSProviderHTTPReq: {
+		newProvider: newHTTPReqProvider,
+		required:    []string{"endpoint"},
+	},
+}
+
+// RegisterDNSProvider makes a DNS-01 challenge provider available under name,
+// replacing any previously registered provider with the same name.  required
+// are the keys of the credentials, which must not be empty.  name must not be
+// empty and f must not be nil.
+func RegisterDNSProvider(name string, f DNSProviderFactory, required ...string) {
+	dnsProvidersMu.Lock()
+	defer dnsProvidersMu.Unlock()
+
+	dnsProviders[name] = &dnsProvider{
+		newProvider: f,
+		required:    slices.Clone(required),
+	}
+}
+
+// DNSProviders returns the sorted names of all registered DNS-01 challenge
+// providers.
+func DNSProviders() (names []string) {
+	dnsProvidersMu.RLock()
+	defer dnsProvidersMu.RUnlock()
+
+	return slices.Sorted(maps.Keys(dnsProviders))
+}
+
+// ValidateDNSProvider returns an error if no DNS-01 challenge provider is
+// registered under name or if any of its required credentials is missing from
+// creds.
+func ValidateDNSProvider(name string, creds map[string]string) (err error) {
+	_, err = validDNSProvider(name, creds)
+
+	return err
+}
+
+// validDNSProvider returns the provider registered under name, if creds
+// contain all its required credentials.
+func validDNSProvider(name string, creds map[string]string) (p *dnsProvider, err error) {
+	dnsProvidersMu.RLock()
+	defer dnsProvidersMu.RUnlock()
+
+	p, ok := dnsProviders[name]
+	if !ok {
+		return nil, fmt.Errorf("acme: unsupported dns provider %q", name)
+	}
+
+	for _, key := range p.required {
+		if creds[key] == "" {
+			return nil, fmt.Errorf(
+				"acme: %s dns provider: credential %q: %w",
+				name,
+				key,
+				errors.ErrEmptyValue,
+			)
+		}
+	}
+
+	return p, nil
+}
+
+// newDNSProvider creates the DNS-01 challenge provider registered under name.
+func newDNSProvider(name string, creds map[string]string) (p challenge.Provider, err error) {
+	dp, err := validDNSProvider(name, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err = dp.newProvider(creds)
+	if err != nil {
+		return nil, fmt.Errorf("acme: creating %s dns provider: %w", name, err)
+	}
+
+	return p, nil
+}
+
+// newCloudflareProvider is a [DNSProviderFactory] for Cloudflare.  It
+// requires the "api_token" credential.
+func newCloudflareProvider(creds map[string]string) (p challenge.Provider, err error) {
+	conf := cloudflare.NewDefaultConfig()
+	conf.AuthToken = creds["api_token"]
+
+	return cloudflare.NewDNSProviderConfig(conf)
+}
+
+// newDuckDNSProvider is a [DNSProviderFactory] for DuckDNS.  It requires the
+// "token" credential.
+func newDuckDNSProvider(creds map[string]string) (p challenge.Provider, err error) {
+	conf := duckdns.NewDefaultConfig()
+	conf.Token = creds["token"]
+
+	return duckdns.NewDNSProviderConfig(conf)
+}
+
+// newGandiProvider is a [DNSProviderFactory] for Gandi LiveDNS.  It requires
+// the "personal_access_token" credential.
+func newGandiProvider(creds map[string]string) (p challenge.Provider, err error) {
+	conf := gandiv5.NewDefaultConfig()
+	conf.PersonalAccessToken = creds["personal_access_token"]
+
+	return gandiv5.NewDNSProviderConfig(conf)
+}
+
+// newHTTPReqProvider is a [DNSProviderFactory] that sends the TXT records to
+// an HTTP endpoint.  It requires the "endpoint" credential, and accepts
+// optional "mode", "username", and "password", see the lego documentation.
+func newHTTPReqProvider(creds map[string]string) (p challenge.Provider, err error) {
+	conf := httpreq.NewDefaultConfig()
+	conf.Endpoint, err = url.Parse(creds["endpoint"])
+	if err != nil {
+		return nil, fmt.Errorf("credential %q: %w", "endpoint", err)
+	}
+
+	conf.Mode = creds["mode"]
+	conf.Username = creds["username"]
+	conf.Password = creds["password"]
+
+	return httpreq.NewDNSProviderConfig(conf)
+}
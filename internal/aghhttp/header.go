@@ -2,9 +2,18 @@ package aghhttp
 
 // HTTP headers
 
+// HTTP header name constants, which aren't in [httphdr].
+const (
+	HdrNameAllow       = "Allow"
+	HdrNameDeprecation = "Deprecation"
+	HdrNameLink        = "Link"
+)
+
 // HTTP header value constants.
 const (
 	HdrValApplicationJSON         = "application/json"
+	HdrValDeprecationTrue         = "true"
+	HdrValPrometheusText          = "text/plain; version=0.0.4; charset=utf-8"
 	HdrValStrictTransportSecurity = "max-age=31536000; includeSubDomains"
 	HdrValTextPlain               = "text/plain"
 )
@@ -0,0 +1,178 @@
+package aghhttp
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// durationBuckets are the upper bounds of the buckets of the request duration
+// histograms in seconds.  These are the default buckets of the Prometheus
+// client libraries.
+var durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// RouteMetrics collects the numbers and the durations of the HTTP requests by
+// route.  It's safe for concurrent use.
+type RouteMetrics struct {
+	// mu protects routes.
+	mu *sync.Mutex
+
+	// routes are the statistics by the route pattern and the HTTP method of
+	// the requests.
+	routes map[routeKey]*routeStats
+}
+
+// routeKey is the key of the statistics of a route.
+type routeKey struct {
+	method  string
+	pattern string
+}
+
+// routeStats are the statistics of a route.
+type routeStats struct {
+	// codes are the numbers of the responses by the status code.
+	codes map[int]uint64
+
+	// buckets are the numbers of the requests by the bucket of
+	// [durationBuckets], which they fall into.  The last one is for the
+	// requests exceeding all the buckets.
+	buckets []uint64
+
+	// sum is the total duration of the requests in seconds.
+	sum float64
+
+	// count is the total number of the requests.
+	count uint64
+}
+
+// NewRouteMetrics returns a new properly initialized *RouteMetrics.
+func NewRouteMetrics() (m *RouteMetrics) {
+	return &RouteMetrics{
+		mu:     &sync.Mutex{},
+		routes: map[routeKey]*routeStats{},
+	}
+}
+
+// Wrap returns h, which also records the metrics of the requests under
+// pattern.  pattern should be the pattern of h in the [http.ServeMux] so that
+// the cardinality of the metrics is limited.  If m is nil, h is returned as is.
+func (m *RouteMetrics) Wrap(pattern string, h http.Handler) (wrapped http.Handler) {
+	if m == nil {
+		return h
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &StatusRecorder{ResponseWriter: w}
+		defer func() {
+			m.observe(r.Method, pattern, rec.Status(), time.Since(start))
+		}()
+
+		h.ServeHTTP(rec, r)
+	})
+}
+
+// observe records a request to the route.
+func (m *RouteMetrics) observe(method, pattern string, code int, dur time.Duration) {
+	secs := dur.Seconds()
+	bucket, _ := slices.BinarySearch(durationBuckets, secs)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	k := routeKey{method: method, pattern: pattern}
+	s := m.routes[k]
+	if s == nil {
+		s = &routeStats{
+			codes:   map[int]uint64{},
+			buckets: make([]uint64, len(durationBuckets)+1),
+		}
+		m.routes[k] = s
+	}
+
+	s.codes[code]++
+	s.buckets[bucket]++
+	s.sum += secs
+	s.count++
+}
+
+// Metric names.
+const (
+	metricRequestsTotal   = "adguardhome_http_requests_total"
+	metricRequestDuration = "adguardhome_http_request_duration_seconds"
+)
+
+// WritePrometheus writes the metrics to w in the Prometheus text exposition
+// format.
+func (m *RouteMetrics) WritePrometheus(w io.Writer) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := slices.SortedFunc(maps.Keys(m.routes), func(a, b routeKey) (res int) {
+		return cmp.Or(strings.Compare(a.pattern, b.pattern), strings.Compare(a.method, b.method))
+	})
+
+	bw := bufio.NewWriter(w)
+
+	_, _ = fmt.Fprintf(bw, "# HELP %s The number of the HTTP requests by route.\n", metricRequestsTotal)
+	_, _ = fmt.Fprintf(bw, "# TYPE %s counter\n", metricRequestsTotal)
+	for _, k := range keys {
+		s := m.routes[k]
+		for _, code := range slices.Sorted(maps.Keys(s.codes)) {
+			_, _ = fmt.Fprintf(
+				bw,
+				"%s{%s,code=\"%d\"} %d\n",
+				metricRequestsTotal,
+				k.labels(),
+				code,
+				s.codes[code],
+			)
+		}
+	}
+
+	_, _ = fmt.Fprintf(bw, "# HELP %s The duration of the HTTP requests by route.\n", metricRequestDuration)
+	_, _ = fmt.Fprintf(bw, "# TYPE %s histogram\n", metricRequestDuration)
+	for _, k := range keys {
+		s := m.routes[k]
+		labels := k.labels()
+
+		var cumulative uint64
+		for i, n := range s.buckets {
+			cumulative += n
+
+			le := "+Inf"
+			if i < len(durationBuckets) {
+				le = strconv.FormatFloat(durationBuckets[i], 'g', -1, 64)
+			}
+
+			_, _ = fmt.Fprintf(bw, "%s_bucket{%s,le=%q} %d\n", metricRequestDuration, labels, le, cumulative)
+		}
+
+		_, _ = fmt.Fprintf(bw, "%s_sum{%s} %g\n", metricRequestDuration, labels, s.sum)
+		_, _ = fmt.Fprintf(bw, "%s_count{%s} %d\n", metricRequestDuration, labels, s.count)
+	}
+
+	return bw.Flush()
+}
+
+// labels returns the Prometheus labels of the route.
+func (k routeKey) labels() (s string) {
+	return fmt.Sprintf(`method="%s",route="%s"`, escapeLabel(k.method), escapeLabel(k.pattern))
+}
+
+// labelEscaper escapes the values of the labels in the Prometheus text
+// exposition format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel returns the escaped label value v.
+func escapeLabel(v string) (escaped string) {
+	return labelEscaper.Replace(v)
+}
@@ -0,0 +1,68 @@
+package aghhttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteMetrics(t *testing.T) {
+	mux := http.NewServeMux()
+	reg := aghhttp.NewDefaultRegistrar(mux, func(_ string, h http.HandlerFunc) (wrapped http.Handler) {
+		return h
+	})
+
+	reg.Register(http.MethodGet, "/control/test", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	reg.Register(http.MethodGet, "/control/items/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	for _, p := range []string{"/control/test", "/control/test", "/control/items/1", "/control/items/2"} {
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, p, nil))
+	}
+
+	doh := reg.Metrics().Wrap("/dns-query", http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+	doh.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/dns-query", nil))
+
+	b := &strings.Builder{}
+	require.NoError(t, reg.Metrics().WritePrometheus(b))
+
+	out := b.String()
+	for _, want := range []string{
+		"# TYPE adguardhome_http_requests_total counter\n",
+		`adguardhome_http_requests_total{method="GET",route="/control/test",code="418"} 2` + "\n",
+		`adguardhome_http_requests_total{method="GET",route="/control/items/{id}",code="200"} 2` + "\n",
+		`adguardhome_http_requests_total{method="POST",route="/dns-query",code="200"} 1` + "\n",
+		"# TYPE adguardhome_http_request_duration_seconds histogram\n",
+		`adguardhome_http_request_duration_seconds_bucket{method="GET",route="/control/test",le="+Inf"} 2` + "\n",
+		`adguardhome_http_request_duration_seconds_count{method="GET",route="/control/test"} 2` + "\n",
+	} {
+		assert.Contains(t, out, want)
+	}
+
+	var nilMetrics *aghhttp.RouteMetrics
+	h := http.RedirectHandler("/", http.StatusFound)
+	assert.Same(t, h, nilMetrics.Wrap("/", h))
+}
+
+func TestRouteMetrics_flush(t *testing.T) {
+	m := aghhttp.NewRouteMetrics()
+	h := m.Wrap("/events", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		f, ok := w.(http.Flusher)
+		require.True(t, ok)
+
+		f.Flush()
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/events", nil))
+
+	assert.True(t, w.Flushed)
+}
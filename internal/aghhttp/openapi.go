@@ -0,0 +1,368 @@
+package aghhttp
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Route is a registered HTTP route.
+type Route struct {
+	// Info is the optional description of the route.  It may be nil.
+	Info *RouteInfo
+
+	// Method is the HTTP method of the route.
+	Method string
+
+	// Path is the path pattern of the route, like "/control/clients/{id}".
+	Path string
+}
+
+// RouteInfo is the description of a route used in the generated OpenAPI
+// document.
+type RouteInfo struct {
+	// Request is a value of the type of the JSON request body, if any.  Its
+	// schema is generated from the type using the JSON struct tags.
+	Request any
+
+	// Response is a value of the type of the JSON response body of a
+	// successful request, if any.  Its schema is generated like the one of
+	// Request.
+	Response any
+
+	// Summary is the short description of the route.
+	Summary string
+
+	// Description is the long description of the route.
+	Description string
+
+	// Deprecated is true if the route is deprecated.
+	Deprecated bool
+}
+
+// OpenAPIVersion is the version of the OpenAPI specification of the documents
+// returned by [NewOpenAPIDocument].
+const OpenAPIVersion = "3.0.3"
+
+// OpenAPIDocument is an OpenAPI 3 document describing the HTTP API.
+type OpenAPIDocument struct {
+	// Paths are the operations by the path and by the lowercase HTTP method.
+	Paths map[string]map[string]*openAPIOperation `json:"paths"`
+
+	Components openAPIComponents `json:"components"`
+
+	Info openAPIInfo `json:"info"`
+
+	OpenAPI string `json:"openapi"`
+}
+
+// openAPIInfo is the metadata of an OpenAPI document.
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// openAPIComponents contains the reusable schemas of an OpenAPI document.
+type openAPIComponents struct {
+	Schemas map[string]*jsonSchema `json:"schemas"`
+}
+
+// openAPIOperation is an operation of an OpenAPI document.
+type openAPIOperation struct {
+	RequestBody *openAPIBody            `json:"requestBody,omitempty"`
+	Responses   map[string]*openAPIBody `json:"responses"`
+	Summary     string                  `json:"summary,omitempty"`
+	Description string                  `json:"description,omitempty"`
+	Parameters  []*openAPIParameter     `json:"parameters,omitempty"`
+	Deprecated  bool                    `json:"deprecated,omitempty"`
+}
+
+// openAPIBody is a request or a response body of an operation.
+type openAPIBody struct {
+	Content     map[string]*openAPIMediaType `json:"content,omitempty"`
+	Description string                       `json:"description"`
+}
+
+// openAPIMediaType is the content of a body of a specific media type.
+type openAPIMediaType struct {
+	Schema *jsonSchema `json:"schema"`
+}
+
+// openAPIParameter is a parameter of an operation.
+type openAPIParameter struct {
+	Schema   *jsonSchema `json:"schema"`
+	Name     string      `json:"name"`
+	In       string      `json:"in"`
+	Required bool        `json:"required"`
+}
+
+// jsonSchema is a subset of the JSON Schema used in OpenAPI documents.
+type jsonSchema struct {
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	AdditionalProperties *jsonSchema            `json:"additionalProperties,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+}
+
+// NewOpenAPIDocument returns the OpenAPI document describing routes.  The
+// routes without [RouteInfo] are described only by their methods, paths, and
+// path parameters.
+func NewOpenAPIDocument(title, version string, routes []Route) (doc *OpenAPIDocument) {
+	doc = &OpenAPIDocument{
+		Paths: map[string]map[string]*openAPIOperation{},
+		Components: openAPIComponents{
+			Schemas: map[string]*jsonSchema{},
+		},
+		Info: openAPIInfo{
+			Title:   title,
+			Version: version,
+		},
+		OpenAPI: OpenAPIVersion,
+	}
+
+	g := &schemaGenerator{
+		schemas: doc.Components.Schemas,
+		names:   map[reflect.Type]string{},
+	}
+
+	for _, r := range routes {
+		p, params := openAPIPath(r.Path)
+		ops := doc.Paths[p]
+		if ops == nil {
+			ops = map[string]*openAPIOperation{}
+			doc.Paths[p] = ops
+		}
+
+		ops[strings.ToLower(r.Method)] = g.operation(r.Info, params)
+	}
+
+	return doc
+}
+
+// pathWildcard matches the wildcards of the [http.ServeMux] patterns, like
+// "{id}" and "{path...}".
+var pathWildcard = regexp.MustCompile(`\{([^}.]+)(?:\.\.\.)?\}`)
+
+// openAPIPath converts the [http.ServeMux] pattern p into an OpenAPI path and
+// returns the path parameters.
+func openAPIPath(pattern string) (p string, params []*openAPIParameter) {
+	// Remove the end-of-path marker.
+	p = strings.Replace(pattern, "{$}", "", 1)
+	p = pathWildcard.ReplaceAllStringFunc(p, func(w string) (repl string) {
+		name := pathWildcard.FindStringSubmatch(w)[1]
+		params = append(params, &openAPIParameter{
+			Schema:   &jsonSchema{Type: "string"},
+			Name:     name,
+			In:       "path",
+			Required: true,
+		})
+
+		return "{" + name + "}"
+	})
+
+	return p, params
+}
+
+// schemaGenerator generates the JSON schemas of Go types.
+type schemaGenerator struct {
+	// schemas are the generated schemas of the named struct types by the
+	// component name.
+	schemas map[string]*jsonSchema
+
+	// names are the component names of the named struct types.
+	names map[reflect.Type]string
+}
+
+// operation returns the operation described by info, which may be nil.
+func (g *schemaGenerator) operation(info *RouteInfo, params []*openAPIParameter) (op *openAPIOperation) {
+	resp := &openAPIBody{Description: http.StatusText(http.StatusOK)}
+	op = &openAPIOperation{
+		Responses:  map[string]*openAPIBody{"200": resp},
+		Parameters: params,
+	}
+
+	if info == nil {
+		return op
+	}
+
+	op.Summary, op.Description, op.Deprecated = info.Summary, info.Description, info.Deprecated
+	if info.Request != nil {
+		op.RequestBody = &openAPIBody{
+			Content: g.jsonContent(info.Request),
+		}
+	}
+
+	if info.Response != nil {
+		resp.Content = g.jsonContent(info.Response)
+	}
+
+	return op
+}
+
+// jsonContent returns the JSON content with the schema of the type of v.
+func (g *schemaGenerator) jsonContent(v any) (c map[string]*openAPIMediaType) {
+	return map[string]*openAPIMediaType{
+		HdrValApplicationJSON: {Schema: g.schema(reflect.TypeOf(v))},
+	}
+}
+
+// Types with the special JSON encoding.
+var (
+	typeTime          = reflect.TypeFor[time.Time]()
+	typeJSONTime      = reflect.TypeFor[JSONTime]()
+	typeJSONDuration  = reflect.TypeFor[JSONDuration]()
+	typeJSONMarshaler = reflect.TypeFor[json.Marshaler]()
+	typeTextMarshaler = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// schema returns the schema of t.  The named struct types are added to the
+// components and referenced.
+func (g *schemaGenerator) schema(t reflect.Type) (s *jsonSchema) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == typeTime:
+		return &jsonSchema{Type: "string", Format: "date-time"}
+	case t == typeJSONTime, t == typeJSONDuration:
+		return &jsonSchema{Type: "number"}
+	case implements(t, typeJSONMarshaler):
+		// The encoding is unknown.
+		return &jsonSchema{}
+	case implements(t, typeTextMarshaler):
+		return &jsonSchema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &jsonSchema{Type: "boolean"}
+	case
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &jsonSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &jsonSchema{Type: "number"}
+	case reflect.String:
+		return &jsonSchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &jsonSchema{Type: "string", Format: "byte"}
+		}
+
+		return &jsonSchema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &jsonSchema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		return g.structSchema(t)
+	default:
+		return &jsonSchema{}
+	}
+}
+
+// implements returns true if t or the pointer to t implements iface.
+func implements(t, iface reflect.Type) (ok bool) {
+	return t.Implements(iface) || reflect.PointerTo(t).Implements(iface)
+}
+
+// structSchema returns the schema of the struct type t, which is a reference
+// to the component for the named types.
+func (g *schemaGenerator) structSchema(t reflect.Type) (s *jsonSchema) {
+	if t.Name() == "" {
+		s = &jsonSchema{Type: "object", Properties: map[string]*jsonSchema{}}
+		g.addProperties(s, t)
+
+		return s
+	}
+
+	ref := &jsonSchema{}
+	if name, ok := g.names[t]; ok {
+		ref.Ref = "#/components/schemas/" + name
+
+		return ref
+	}
+
+	name := g.componentName(t)
+	g.names[t] = name
+	ref.Ref = "#/components/schemas/" + name
+
+	// Store the schema before generating the properties to support the
+	// recursive types.
+	s = &jsonSchema{Type: "object", Properties: map[string]*jsonSchema{}}
+	g.schemas[name] = s
+	g.addProperties(s, t)
+
+	return ref
+}
+
+// componentName returns the unique component name of the named type t.
+func (g *schemaGenerator) componentName(t reflect.Type) (name string) {
+	name = sanitizeName(t.Name())
+	if _, ok := g.schemas[name]; !ok {
+		return name
+	}
+
+	name = sanitizeName(path.Base(t.PkgPath())) + "_" + name
+	for i, base := 2, name; ; i++ {
+		if _, ok := g.schemas[name]; !ok {
+			return name
+		}
+
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+}
+
+// nonNameChars matches the characters not allowed in the component names.
+var nonNameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// sanitizeName replaces the characters of the name of a Go type, like the
+// brackets of the generic types, which aren't allowed in the component names.
+func sanitizeName(name string) (s string) {
+	return strings.Trim(nonNameChars.ReplaceAllString(name, "_"), "_")
+}
+
+// addProperties adds the properties of the exported fields of the struct type
+// t to s, using the JSON struct tags.  The fields of the embedded structs
+// without the tags are added as the properties of t.
+func (g *schemaGenerator) addProperties(s *jsonSchema, t reflect.Type) {
+	for f := range t.Fields() {
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+
+			if ft.Kind() == reflect.Struct {
+				g.addProperties(s, ft)
+
+				continue
+			}
+		}
+
+		if !f.IsExported() {
+			continue
+		} else if name == "" {
+			name = f.Name
+		}
+
+		if strings.Contains(opts, "string") {
+			s.Properties[name] = &jsonSchema{Type: "string"}
+		} else {
+			s.Properties[name] = g.schema(f.Type)
+		}
+	}
+}
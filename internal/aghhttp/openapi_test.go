@@ -0,0 +1,93 @@
+package aghhttp_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testNode is a recursive type for tests.
+type testNode struct {
+	Child *testNode `json:"child,omitempty"`
+	Name  string    `json:"name"`
+}
+
+// testResponse is a response type for tests.
+type testResponse struct {
+	testNode
+
+	Time     time.Time         `json:"time"`
+	Labels   map[string]string `json:"labels"`
+	Start    aghhttp.JSONTime  `json:"start"`
+	Counts   []uint64          `json:"counts"`
+	Secret   string            `json:"-"`
+	Quoted   int               `json:"quoted,string"`
+	Disabled bool              `json:"disabled"`
+}
+
+func TestNewOpenAPIDocument(t *testing.T) {
+	mux := http.NewServeMux()
+	reg := aghhttp.NewDefaultRegistrar(mux, func(_ string, h http.HandlerFunc) (wrapped http.Handler) {
+		return h
+	})
+
+	h := func(_ http.ResponseWriter, _ *http.Request) {}
+	aghhttp.RegisterWithInfo(reg, http.MethodGet, "/control/test", h, &aghhttp.RouteInfo{
+		Summary:  "Get the test",
+		Response: testResponse{},
+	})
+	reg.Register(http.MethodPost, "/control/clients/{id}/wake", h)
+
+	doc := aghhttp.NewOpenAPIDocument("Test", "v1.2.3", reg.Routes())
+
+	b, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	var got struct {
+		Paths      map[string]map[string]json.RawMessage `json:"paths"`
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+		OpenAPI string `json:"openapi"`
+	}
+	require.NoError(t, json.Unmarshal(b, &got))
+
+	assert.Equal(t, aghhttp.OpenAPIVersion, got.OpenAPI)
+	assert.JSONEq(t, `{
+		"summary": "Get the test",
+		"responses": {"200": {
+			"description": "OK",
+			"content": {"application/json": {"schema": {
+				"$ref": "#/components/schemas/testResponse"
+			}}}
+		}}
+	}`, string(got.Paths["/control/test"]["get"]))
+	assert.JSONEq(t, `{
+		"responses": {"200": {"description": "OK"}},
+		"parameters": [{
+			"name": "id",
+			"in": "path",
+			"required": true,
+			"schema": {"type": "string"}
+		}]
+	}`, string(got.Paths["/control/clients/{id}/wake"]["post"]))
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"child": {"$ref": "#/components/schemas/testNode"},
+			"name": {"type": "string"},
+			"time": {"type": "string", "format": "date-time"},
+			"labels": {"type": "object", "additionalProperties": {"type": "string"}},
+			"start": {"type": "number"},
+			"counts": {"type": "array", "items": {"type": "integer"}},
+			"quoted": {"type": "string"},
+			"disabled": {"type": "boolean"}
+		}
+	}`, string(got.Components.Schemas["testResponse"]))
+	assert.Contains(t, got.Components.Schemas, "testNode")
+}
@@ -1,7 +1,15 @@
 package aghhttp
 
 import (
+	"fmt"
+	"maps"
 	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/AdguardTeam/golibs/errors"
 )
 
 // Registrar registers an HTTP handler for a method and path.
@@ -11,6 +19,40 @@ type Registrar interface {
 	Register(method, path string, h http.HandlerFunc)
 }
 
+// InfoRegistrar is a [Registrar], which also accepts the descriptions of the
+// routes.
+type InfoRegistrar interface {
+	Registrar
+
+	// RegisterWithInfo is like [Registrar.Register], but also stores the
+	// description of the route.  info may be nil.
+	RegisterWithInfo(method, path string, h http.HandlerFunc, info *RouteInfo)
+}
+
+// RouteLister is implemented by the registrars, which keep the list of the
+// registered routes.
+type RouteLister interface {
+	// Routes returns the registered routes in the order of registration.
+	Routes() (routes []Route)
+}
+
+// MetricsCollector is implemented by the registrars, which collect the metrics
+// of the requests to the registered routes.
+type MetricsCollector interface {
+	// Metrics returns the metrics of the requests.  It must not be nil.
+	Metrics() (m *RouteMetrics)
+}
+
+// RegisterWithInfo registers h for method and path with reg, adding info to
+// the description of the route, if reg is an [InfoRegistrar].
+func RegisterWithInfo(reg Registrar, method, path string, h http.HandlerFunc, info *RouteInfo) {
+	if ir, ok := reg.(InfoRegistrar); ok {
+		ir.RegisterWithInfo(method, path, h, info)
+	} else {
+		reg.Register(method, path, h)
+	}
+}
+
 // EmptyRegistrar is an implementation of [Registrar] that does nothing.
 type EmptyRegistrar struct{}
 
@@ -24,26 +66,187 @@ func (EmptyRegistrar) Register(_, _ string, _ http.HandlerFunc) {}
 type WrapFunc func(method string, h http.HandlerFunc) (wrapped http.Handler)
 
 // DefaultRegistrar is an implementation of [Registrar] that registers handlers
-// after applying a user-provided wrapper function.
+// after applying a user-provided wrapper function.  It also keeps the list of
+// the registered routes, which can be unregistered at runtime, and collects
+// their metrics.
 type DefaultRegistrar struct {
-	mux    *http.ServeMux
-	wrapFn WrapFunc
+	mux     *http.ServeMux
+	wrapFn  WrapFunc
+	metrics *RouteMetrics
+
+	// mu protects routes and handlers.
+	mu *sync.Mutex
+
+	// routes are the registered routes in the order of registration.
+	routes []Route
+
+	// handlers are the handlers registered in mux by path.  They are never
+	// removed from mux, since it doesn't support that, but disabled instead.
+	// Each of them dispatches the requests to the handlers of the methods
+	// registered for the path.
+	handlers map[string]*routeHandler
 }
 
 // NewDefaultRegistrar returns a new properly initialized *DefaultRegistrar.
 // mux and wrap must not be nil.
 func NewDefaultRegistrar(mux *http.ServeMux, wrap WrapFunc) (r *DefaultRegistrar) {
 	return &DefaultRegistrar{
-		mux:    mux,
-		wrapFn: wrap,
+		mux:      mux,
+		wrapFn:   wrap,
+		metrics:  NewRouteMetrics(),
+		mu:       &sync.Mutex{},
+		handlers: map[string]*routeHandler{},
 	}
 }
 
 // type check
-var _ Registrar = (*DefaultRegistrar)(nil)
+var (
+	_ InfoRegistrar    = (*DefaultRegistrar)(nil)
+	_ RouteLister      = (*DefaultRegistrar)(nil)
+	_ MetricsCollector = (*DefaultRegistrar)(nil)
+)
 
 // Register implements the [Registrar] interface.
 func (r *DefaultRegistrar) Register(method, path string, h http.HandlerFunc) {
+	r.RegisterWithInfo(method, path, h, nil)
+}
+
+// RegisterWithInfo implements the [InfoRegistrar] interface.  It panics if
+// method is already registered for path, like [http.ServeMux.Handle].  The
+// route unregistered with [DefaultRegistrar.Unregister] may be registered
+// again.
+//
+// The requests to path with the methods, which aren't registered, are answered
+// with 405 Method Not Allowed and the Allow header.  The OPTIONS requests are
+// answered automatically, unless a handler for OPTIONS is registered.
+func (r *DefaultRegistrar) RegisterWithInfo(method, path string, h http.HandlerFunc, info *RouteInfo) {
 	wrapped := r.wrapFn(method, h)
-	r.mux.Handle(path, wrapped)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rh, ok := r.handlers[path]
+	if !ok {
+		rh = &routeHandler{}
+		r.mux.Handle(path, r.metrics.Wrap(path, rh))
+		r.handlers[path] = rh
+	} else if _, ok = rh.load()[method]; ok {
+		panic(fmt.Errorf("aghhttp: route %s %s: %w", method, path, errors.ErrDuplicated))
+	}
+
+	rh.set(method, wrapped)
+
+	r.routes = append(r.routes, Route{
+		Info:   info,
+		Method: method,
+		Path:   path,
+	})
+}
+
+// Unregister removes the route registered for method and path.  The requests
+// to path are answered with 405 Method Not Allowed afterwards or, if there are
+// no other methods for path, with 404 Not Found, while the requests already
+// being handled aren't interrupted.  It does nothing if there is no
+// such route.  It's safe for concurrent use with the other methods and with the
+// handling of the requests.
+func (r *DefaultRegistrar) Unregister(method, path string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.routes, func(rt Route) (ok bool) {
+		return rt.Method == method && rt.Path == path
+	})
+	if i < 0 {
+		return
+	}
+
+	r.routes = slices.Delete(r.routes, i, i+1)
+	r.handlers[path].set(method, nil)
+}
+
+// Routes implements the [RouteLister] interface.
+func (r *DefaultRegistrar) Routes() (routes []Route) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.routes)
+}
+
+// Metrics implements the [MetricsCollector] interface.
+func (r *DefaultRegistrar) Metrics() (m *RouteMetrics) {
+	return r.metrics
+}
+
+// routeHandler is the handler of a path registered by [DefaultRegistrar],
+// which dispatches the requests by method.
+type routeHandler struct {
+	// handlers are the current handlers of the path by method.  The map is
+	// never modified but replaced, so that it's read without locking.  If it's
+	// nil or empty, the path is unregistered.
+	handlers atomic.Pointer[map[string]http.Handler]
+}
+
+// load returns the current handlers of the path by method.  It must not be
+// modified.
+func (rh *routeHandler) load() (handlers map[string]http.Handler) {
+	if p := rh.handlers.Load(); p != nil {
+		return *p
+	}
+
+	return nil
+}
+
+// set sets the handler of method.  If h is nil, the handler is removed.  It
+// must only be called with the lock of the registrar held.
+func (rh *routeHandler) set(method string, h http.Handler) {
+	handlers := maps.Clone(rh.load())
+	if h == nil {
+		delete(handlers, method)
+	} else {
+		if handlers == nil {
+			handlers = map[string]http.Handler{}
+		}
+
+		handlers[method] = h
+	}
+
+	rh.handlers.Store(&handlers)
+}
+
+// type check
+var _ http.Handler = (*routeHandler)(nil)
+
+// ServeHTTP implements the [http.Handler] interface for *routeHandler.
+func (rh *routeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	handlers := rh.load()
+	if h, ok := handlers[r.Method]; ok {
+		h.ServeHTTP(w, r)
+
+		return
+	} else if len(handlers) == 0 {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	methods := slices.Sorted(maps.Keys(handlers))
+	allowed := methods
+	if !slices.Contains(methods, http.MethodOptions) {
+		allowed = append(slices.Clip(methods), http.MethodOptions)
+	}
+
+	w.Header().Set(HdrNameAllow, strings.Join(allowed, ", "))
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+
+		return
+	}
+
+	// Use the same message as the method checks of the handlers.
+	msg := "only method " + methods[0] + " is allowed"
+	if len(methods) > 1 {
+		msg = "only methods " + strings.Join(methods, ", ") + " are allowed"
+	}
+
+	http.Error(w, msg, http.StatusMethodNotAllowed)
 }
@@ -0,0 +1,153 @@
+package aghhttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultRegistrar_Unregister(t *testing.T) {
+	mux := http.NewServeMux()
+	reg := aghhttp.NewDefaultRegistrar(mux, func(_ string, h http.HandlerFunc) (wrapped http.Handler) {
+		return h
+	})
+
+	const path = "/control/test"
+
+	teapot := func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}
+
+	serve := func(t *testing.T, wantCode int) {
+		t.Helper()
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, wantCode, w.Code)
+	}
+
+	reg.Register(http.MethodGet, path, teapot)
+	serve(t, http.StatusTeapot)
+
+	reg.Unregister(http.MethodPost, path)
+	serve(t, http.StatusTeapot)
+
+	reg.Unregister(http.MethodGet, path)
+	serve(t, http.StatusNotFound)
+	assert.Empty(t, reg.Routes())
+
+	reg.Register(http.MethodGet, path, teapot)
+	serve(t, http.StatusTeapot)
+	assert.Len(t, reg.Routes(), 1)
+
+	assert.Panics(t, func() { reg.Register(http.MethodGet, path, teapot) })
+}
+
+func TestDefaultRegistrar_Unregister_concurrent(t *testing.T) {
+	mux := http.NewServeMux()
+	reg := aghhttp.NewDefaultRegistrar(mux, func(_ string, h http.HandlerFunc) (wrapped http.Handler) {
+		return h
+	})
+
+	const path = "/control/test"
+
+	h := func(w http.ResponseWriter, _ *http.Request) {}
+	reg.Register(http.MethodGet, path, h)
+
+	wg := &sync.WaitGroup{}
+	for range 10 {
+		wg.Go(func() {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		})
+	}
+
+	wg.Go(func() {
+		reg.Unregister(http.MethodGet, path)
+		reg.Register(http.MethodGet, path, h)
+	})
+
+	wg.Wait()
+
+	assert.Len(t, reg.Routes(), 1)
+}
+
+func TestDefaultRegistrar_methods(t *testing.T) {
+	mux := http.NewServeMux()
+	reg := aghhttp.NewDefaultRegistrar(mux, func(_ string, h http.HandlerFunc) (wrapped http.Handler) {
+		return h
+	})
+
+	const path = "/control/test"
+
+	newHandler := func(code int) (h http.HandlerFunc) {
+		return func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(code)
+		}
+	}
+
+	reg.Register(http.MethodPut, path, newHandler(http.StatusAccepted))
+	reg.Register(http.MethodGet, path, newHandler(http.StatusTeapot))
+
+	testCases := []struct {
+		name      string
+		method    string
+		wantAllow string
+		wantCode  int
+	}{{
+		name:      "get",
+		method:    http.MethodGet,
+		wantAllow: "",
+		wantCode:  http.StatusTeapot,
+	}, {
+		name:      "put",
+		method:    http.MethodPut,
+		wantAllow: "",
+		wantCode:  http.StatusAccepted,
+	}, {
+		name:      "not_allowed",
+		method:    http.MethodPost,
+		wantAllow: "GET, PUT, OPTIONS",
+		wantCode:  http.StatusMethodNotAllowed,
+	}, {
+		name:      "options",
+		method:    http.MethodOptions,
+		wantAllow: "GET, PUT, OPTIONS",
+		wantCode:  http.StatusNoContent,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(tc.method, path, nil))
+
+			assert.Equal(t, tc.wantCode, w.Code)
+			assert.Equal(t, tc.wantAllow, w.Header().Get(aghhttp.HdrNameAllow))
+		})
+	}
+
+	reg.Register(http.MethodOptions, path, newHandler(http.StatusOK))
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodOptions, path, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	reg.Unregister(http.MethodOptions, path)
+	reg.Unregister(http.MethodPut, path)
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPut, path, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET, OPTIONS", w.Header().Get(aghhttp.HdrNameAllow))
+	assert.Equal(t, "only method GET is allowed\n", w.Body.String())
+
+	reg.Unregister(http.MethodGet, path)
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodOptions, path, nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
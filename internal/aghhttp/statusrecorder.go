@@ -0,0 +1,69 @@
+package aghhttp
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+)
+
+// StatusRecorder is an [http.ResponseWriter], which records the status code of
+// the response.
+type StatusRecorder struct {
+	http.ResponseWriter
+
+	// code is the status code of the response.  Zero means that the header
+	// hasn't been written yet.
+	code int
+}
+
+// type check
+var (
+	_ http.ResponseWriter = (*StatusRecorder)(nil)
+	_ http.Flusher        = (*StatusRecorder)(nil)
+	_ http.Hijacker       = (*StatusRecorder)(nil)
+)
+
+// WriteHeader implements the [http.ResponseWriter] interface for
+// *StatusRecorder.
+func (rec *StatusRecorder) WriteHeader(code int) {
+	if rec.code == 0 {
+		rec.code = code
+	}
+
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+// Write implements the [http.ResponseWriter] interface for *StatusRecorder.
+func (rec *StatusRecorder) Write(b []byte) (n int, err error) {
+	if rec.code == 0 {
+		rec.code = http.StatusOK
+	}
+
+	return rec.ResponseWriter.Write(b)
+}
+
+// Flush implements the [http.Flusher] interface for *StatusRecorder.  Some
+// middlewares, like the gzip one, only flush the writers implementing it.
+func (rec *StatusRecorder) Flush() {
+	_ = http.NewResponseController(rec.ResponseWriter).Flush()
+}
+
+// Hijack implements the [http.Hijacker] interface for *StatusRecorder.
+func (rec *StatusRecorder) Hijack() (conn net.Conn, rw *bufio.ReadWriter, err error) {
+	return http.NewResponseController(rec.ResponseWriter).Hijack()
+}
+
+// Unwrap returns the underlying writer for [http.ResponseController].
+func (rec *StatusRecorder) Unwrap() (w http.ResponseWriter) {
+	return rec.ResponseWriter
+}
+
+// Status returns the status code of the response.  The responses without
+// the written header are reported as 200 OK, like [http.Server] does.
+func (rec *StatusRecorder) Status() (code int) {
+	if rec.code == 0 {
+		return http.StatusOK
+	}
+
+	return rec.code
+}
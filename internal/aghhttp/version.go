@@ -0,0 +1,130 @@
+package aghhttp
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ControlPrefix is the prefix of the paths of the control API.
+const ControlPrefix = "/control/"
+
+// APIVersion is a version of the control API.
+type APIVersion uint
+
+// The versions of the control API.
+const (
+	// APIVersionLegacy is the version of the unversioned paths, like
+	// "/control/status".
+	APIVersionLegacy APIVersion = 0
+
+	// APIVersion1 is the first version of the versioned paths, like
+	// "/control/v1/status".
+	APIVersion1 APIVersion = 1
+)
+
+// type check
+var _ fmt.Stringer = APIVersion(0)
+
+// String implements the [fmt.Stringer] interface for APIVersion.
+func (v APIVersion) String() (s string) {
+	if v == APIVersionLegacy {
+		return "legacy"
+	}
+
+	return "v" + strconv.FormatUint(uint64(v), 10)
+}
+
+// VersionedPath returns the path of the control API path under v, like
+// "/control/v1/status" for "/control/status".  The paths, which don't start
+// with [ControlPrefix], as well as all paths of [APIVersionLegacy], are
+// returned as is.
+func VersionedPath(v APIVersion, path string) (versioned string) {
+	rest, ok := strings.CutPrefix(path, ControlPrefix)
+	if !ok || v == APIVersionLegacy {
+		return path
+	}
+
+	return ControlPrefix + v.String() + "/" + rest
+}
+
+// Versions describes the versions of the control API, under which a handler is
+// mounted by [RegisterVersions].
+type Versions struct {
+	// Info is the description of the route.  It may be nil.
+	Info *RouteInfo
+
+	// Mounted are the versions, under which the handler is mounted.  It must
+	// not be empty.
+	Mounted []APIVersion
+
+	// Deprecated are the versions from Mounted, in which the route is
+	// deprecated.  The responses of the deprecated routes have the Deprecation
+	// header and, if there is a later version, which isn't deprecated, the Link
+	// header pointing to its path.
+	Deprecated []APIVersion
+}
+
+// RegisterVersions registers h for method and path under each of the versions
+// in vers with reg.  vers must not be nil.  It panics if vers.Mounted is empty.
+func RegisterVersions(reg Registrar, method, path string, h http.HandlerFunc, vers *Versions) {
+	if len(vers.Mounted) == 0 {
+		panic(fmt.Errorf("aghhttp: route %s %s: no versions", method, path))
+	}
+
+	successor := successorPath(path, vers)
+	for _, v := range vers.Mounted {
+		vh, info := h, vers.Info
+		if slices.Contains(vers.Deprecated, v) {
+			vh, info = deprecatedHandler(h, successor), deprecatedInfo(info)
+		}
+
+		RegisterWithInfo(reg, method, VersionedPath(v, path), vh, info)
+	}
+}
+
+// successorPath returns the path of the latest not deprecated version of
+// path from vers or an empty string, if all versions are deprecated.
+func successorPath(path string, vers *Versions) (successor string) {
+	var latest APIVersion
+	found := false
+	for _, v := range vers.Mounted {
+		if !slices.Contains(vers.Deprecated, v) && (!found || v > latest) {
+			latest, found = v, true
+		}
+	}
+
+	if !found {
+		return ""
+	}
+
+	return VersionedPath(latest, path)
+}
+
+// deprecatedHandler returns h, which also adds the deprecation headers to the
+// responses.  successor is the path of the replacement, if any.
+func deprecatedHandler(h http.HandlerFunc, successor string) (wrapped http.HandlerFunc) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		respHdr := w.Header()
+		respHdr.Set(HdrNameDeprecation, HdrValDeprecationTrue)
+		if successor != "" {
+			respHdr.Add(HdrNameLink, fmt.Sprintf("<%s>; rel=%q", successor, "successor-version"))
+		}
+
+		h(w, r)
+	}
+}
+
+// deprecatedInfo returns a copy of info marked as deprecated.  info may be nil.
+func deprecatedInfo(info *RouteInfo) (dep *RouteInfo) {
+	if info == nil {
+		return &RouteInfo{Deprecated: true}
+	}
+
+	cloned := *info
+	cloned.Deprecated = true
+
+	return &cloned
+}
@@ -0,0 +1,116 @@
+package aghhttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionedPath(t *testing.T) {
+	testCases := []struct {
+		name string
+		path string
+		want string
+		ver  aghhttp.APIVersion
+	}{{
+		name: "v1",
+		path: "/control/status",
+		want: "/control/v1/status",
+		ver:  aghhttp.APIVersion1,
+	}, {
+		name: "legacy",
+		path: "/control/status",
+		want: "/control/status",
+		ver:  aghhttp.APIVersionLegacy,
+	}, {
+		name: "wildcard",
+		path: "/control/clients/{id}/wake",
+		want: "/control/v2/clients/{id}/wake",
+		ver:  2,
+	}, {
+		name: "not_control",
+		path: "/dns-query",
+		want: "/dns-query",
+		ver:  aghhttp.APIVersion1,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, aghhttp.VersionedPath(tc.ver, tc.path))
+		})
+	}
+}
+
+func TestRegisterVersions(t *testing.T) {
+	mux := http.NewServeMux()
+	reg := aghhttp.NewDefaultRegistrar(mux, func(_ string, h http.HandlerFunc) (wrapped http.Handler) {
+		return h
+	})
+
+	teapot := func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}
+
+	aghhttp.RegisterVersions(reg, http.MethodGet, "/control/test", teapot, &aghhttp.Versions{
+		Info: &aghhttp.RouteInfo{
+			Summary: "Get the test",
+		},
+		Mounted: []aghhttp.APIVersion{
+			aghhttp.APIVersionLegacy,
+			aghhttp.APIVersion1,
+			2,
+		},
+		Deprecated: []aghhttp.APIVersion{aghhttp.APIVersionLegacy, aghhttp.APIVersion1},
+	})
+
+	testCases := []struct {
+		name     string
+		path     string
+		wantDep  string
+		wantLink string
+	}{{
+		name:     "legacy",
+		path:     "/control/test",
+		wantDep:  aghhttp.HdrValDeprecationTrue,
+		wantLink: `</control/v2/test>; rel="successor-version"`,
+	}, {
+		name:     "v1",
+		path:     "/control/v1/test",
+		wantDep:  aghhttp.HdrValDeprecationTrue,
+		wantLink: `</control/v2/test>; rel="successor-version"`,
+	}, {
+		name:     "v2",
+		path:     "/control/v2/test",
+		wantDep:  "",
+		wantLink: "",
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			assert.Equal(t, http.StatusTeapot, w.Code)
+			assert.Equal(t, tc.wantDep, w.Header().Get(aghhttp.HdrNameDeprecation))
+			assert.Equal(t, tc.wantLink, w.Header().Get(aghhttp.HdrNameLink))
+		})
+	}
+
+	routes := reg.Routes()
+	require.Len(t, routes, 3)
+
+	for i, wantDep := range []bool{true, true, false} {
+		require.NotNil(t, routes[i].Info)
+
+		assert.Equal(t, "Get the test", routes[i].Info.Summary)
+		assert.Equal(t, wantDep, routes[i].Info.Deprecated)
+	}
+
+	assert.Panics(t, func() {
+		aghhttp.RegisterVersions(reg, http.MethodGet, "/control/other", teapot, &aghhttp.Versions{})
+	})
+}
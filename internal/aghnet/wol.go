@@ -0,0 +1,72 @@
+package aghnet
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// WakeOnLANPort is the conventional UDP port for Wake-on-LAN magic packets.
+const WakeOnLANPort uint16 = 9
+
+// wakeOnLANRepeats is the number of times the hardware address is repeated in
+// a magic packet.
+const wakeOnLANRepeats = 16
+
+// ErrBadWakeOnLANMAC is returned by [NewWakeOnLANPacket] when the hardware
+// address is not a 48-bit EUI.
+const ErrBadWakeOnLANMAC errors.Error = "wake-on-lan requires a 48-bit mac address"
+
+// NewWakeOnLANPacket returns the Wake-on-LAN magic packet for mac: six bytes of
+// 0xFF followed by sixteen repetitions of the hardware address.
+func NewWakeOnLANPacket(mac net.HardwareAddr) (pkt []byte, err error) {
+	if len(mac) != 6 {
+		return nil, fmt.Errorf("%w: got %d bytes", ErrBadWakeOnLANMAC, len(mac))
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, 6+wakeOnLANRepeats*len(mac)))
+	buf.Write(bytes.Repeat([]byte{0xFF}, 6))
+	for range wakeOnLANRepeats {
+		buf.Write(mac)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// SendWakeOnLAN sends the Wake-on-LAN magic packet for mac to the broadcast
+// address bcast over UDP.  If bcast has a zero port, [WakeOnLANPort] is used.
+// If bcast has an invalid address, the limited broadcast address is used.
+func SendWakeOnLAN(ctx context.Context, mac net.HardwareAddr, bcast netip.AddrPort) (err error) {
+	pkt, err := NewWakeOnLANPacket(mac)
+	if err != nil {
+		// Don't wrap the error, since it's informative enough as is.
+		return err
+	}
+
+	addr, port := bcast.Addr(), bcast.Port()
+	if !addr.IsValid() {
+		addr = netip.AddrFrom4([4]byte{255, 255, 255, 255})
+	}
+
+	if port == 0 {
+		port = WakeOnLANPort
+	}
+
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "udp", netip.AddrPortFrom(addr, port).String())
+	if err != nil {
+		return fmt.Errorf("dialing: %w", err)
+	}
+	defer func() { err = errors.WithDeferred(err, conn.Close()) }()
+
+	_, err = conn.Write(pkt)
+	if err != nil {
+		return fmt.Errorf("writing magic packet: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package aghnet_test
+
+import (
+	"bytes"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghnet"
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWakeOnLANPacket(t *testing.T) {
+	mac := errors.Must(net.ParseMAC("aa:bb:cc:dd:ee:ff"))
+
+	pkt, err := aghnet.NewWakeOnLANPacket(mac)
+	require.NoError(t, err)
+	require.Len(t, pkt, 102)
+
+	assert.Equal(t, bytes.Repeat([]byte{0xFF}, 6), pkt[:6])
+	assert.Equal(t, bytes.Repeat(mac, 16), pkt[6:])
+
+	t.Run("bad_mac", func(t *testing.T) {
+		eui64 := errors.Must(net.ParseMAC("aa:bb:cc:dd:ee:ff:00:11"))
+
+		_, err = aghnet.NewWakeOnLANPacket(eui64)
+		assert.ErrorIs(t, err, aghnet.ErrBadWakeOnLANMAC)
+	})
+}
+
+func TestSendWakeOnLAN(t *testing.T) {
+	mac := errors.Must(net.ParseMAC("aa:bb:cc:dd:ee:ff"))
+
+	conn, err := net.ListenUDP("udp4", net.UDPAddrFromAddrPort(netip.MustParseAddrPort(
+		"127.0.0.1:0",
+	)))
+	require.NoError(t, err)
+	testutil.CleanupAndRequireSuccess(t, conn.Close)
+
+	addr := conn.LocalAddr().(*net.UDPAddr).AddrPort()
+
+	ctx := testutil.ContextWithTimeout(t, testTimeout)
+	err = aghnet.SendWakeOnLAN(ctx, mac, addr)
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(testTimeout)))
+
+	buf := make([]byte, 256)
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+
+	want, err := aghnet.NewWakeOnLANPacket(mac)
+	require.NoError(t, err)
+
+	assert.Equal(t, want, buf[:n])
+}
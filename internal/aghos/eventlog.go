@@ -0,0 +1,127 @@
+package aghos
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"slices"
+)
+
+// eventLogEntryWriter writes the messages to the Windows Event Log.
+type eventLogEntryWriter interface {
+	// writeEntry writes msg as an entry with the severity and the event
+	// identifier matching lvl.
+	writeEntry(lvl slog.Level, msg string) (err error)
+}
+
+// NewEventLogHandler returns a [slog.Handler] that writes the records to the
+// Windows Event Log as the entries of the source serviceName.  The severities
+// and the event identifiers of the entries match the levels of the records,
+// and their attributes are written in the logfmt format after the message.  On
+// the other systems, it returns an error wrapping [errors.ErrUnsupported].
+func NewEventLogHandler(serviceName string, lvl slog.Leveler) (h slog.Handler, err error) {
+	w, err := newEventLogWriter(serviceName)
+	if err != nil {
+		// Don't wrap the error, because it's informative enough as is.
+		return nil, err
+	}
+
+	return newEventLogHandler(w, lvl), nil
+}
+
+// eventLogHandler is a [slog.Handler] writing to an [eventLogEntryWriter].
+type eventLogHandler struct {
+	writer eventLogEntryWriter
+	level  slog.Leveler
+
+	// ops are the calls of WithAttrs and WithGroup in the order they were
+	// made.  They are replayed for each record, since the text handler writes
+	// all of its output into the same writer.
+	ops []func(h slog.Handler) (res slog.Handler)
+}
+
+// newEventLogHandler returns a new properly initialized *eventLogHandler.
+func newEventLogHandler(w eventLogEntryWriter, lvl slog.Leveler) (h *eventLogHandler) {
+	return &eventLogHandler{
+		writer: w,
+		level:  lvl,
+	}
+}
+
+// type check
+var _ slog.Handler = (*eventLogHandler)(nil)
+
+// Enabled implements the [slog.Handler] interface for *eventLogHandler.
+func (h *eventLogHandler) Enabled(_ context.Context, lvl slog.Level) (ok bool) {
+	return lvl >= h.level.Level()
+}
+
+// Handle implements the [slog.Handler] interface for *eventLogHandler.
+func (h *eventLogHandler) Handle(ctx context.Context, r slog.Record) (err error) {
+	buf := &bytes.Buffer{}
+	buf.WriteString(r.Message)
+
+	attrs := &bytes.Buffer{}
+	var th slog.Handler = slog.NewTextHandler(attrs, &slog.HandlerOptions{
+		ReplaceAttr: removeTopLevelAttrs,
+	})
+	for _, op := range h.ops {
+		th = op(th)
+	}
+
+	// Remove the message, so that only the attributes are formatted.
+	ar := slog.NewRecord(r.Time, r.Level, "", r.PC)
+	r.Attrs(func(a slog.Attr) (cont bool) {
+		ar.AddAttrs(a)
+
+		return true
+	})
+
+	err = th.Handle(ctx, ar)
+	if err != nil {
+		// Don't wrap the error, because it's informative enough as is.
+		return err
+	}
+
+	if line := bytes.TrimSpace(attrs.Bytes()); len(line) > 0 {
+		buf.WriteByte(' ')
+		buf.Write(line)
+	}
+
+	return h.writer.writeEntry(r.Level, buf.String())
+}
+
+// WithAttrs implements the [slog.Handler] interface for *eventLogHandler.
+func (h *eventLogHandler) WithAttrs(attrs []slog.Attr) (res slog.Handler) {
+	return h.with(func(th slog.Handler) (res slog.Handler) { return th.WithAttrs(attrs) })
+}
+
+// WithGroup implements the [slog.Handler] interface for *eventLogHandler.
+func (h *eventLogHandler) WithGroup(name string) (res slog.Handler) {
+	return h.with(func(th slog.Handler) (res slog.Handler) { return th.WithGroup(name) })
+}
+
+// with returns a copy of h with op added.
+func (h *eventLogHandler) with(op func(th slog.Handler) (res slog.Handler)) (res *eventLogHandler) {
+	return &eventLogHandler{
+		writer: h.writer,
+		level:  h.level,
+		ops:    append(slices.Clip(h.ops), op),
+	}
+}
+
+// removeTopLevelAttrs is a [slog.HandlerOptions.ReplaceAttr] function that
+// removes the time, the level, and the message, since the Event Log has its
+// own fields for them.
+func removeTopLevelAttrs(groups []string, a slog.Attr) (res slog.Attr) {
+	if len(groups) > 0 {
+		return a
+	}
+
+	switch a.Key {
+	case slog.TimeKey, slog.LevelKey, slog.MessageKey:
+		return slog.Attr{}
+	default:
+		return a
+	}
+}
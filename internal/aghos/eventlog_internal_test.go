@@ -0,0 +1,67 @@
+package aghos
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testTimeout is the common timeout for tests.
+const testTimeout = 1 * time.Second
+
+// testEntry is an entry written by [testEntryWriter].
+type testEntry struct {
+	msg string
+	lvl slog.Level
+}
+
+// testEntryWriter is an [eventLogEntryWriter] for tests.
+type testEntryWriter struct {
+	entries []testEntry
+}
+
+// type check
+var _ eventLogEntryWriter = (*testEntryWriter)(nil)
+
+// writeEntry implements the [eventLogEntryWriter] interface for
+// *testEntryWriter.
+func (w *testEntryWriter) writeEntry(lvl slog.Level, msg string) (err error) {
+	w.entries = append(w.entries, testEntry{
+		msg: msg,
+		lvl: lvl,
+	})
+
+	return nil
+}
+
+func TestEventLogHandler(t *testing.T) {
+	t.Parallel()
+
+	w := &testEntryWriter{}
+	l := slog.New(newEventLogHandler(w, slog.LevelInfo))
+	ctx := testutil.ContextWithTimeout(t, testTimeout)
+
+	l.DebugContext(ctx, "debug")
+	l.InfoContext(ctx, "info")
+	l.WarnContext(ctx, "warning", "key", "value")
+
+	gl := l.With("prefix", "test").WithGroup("grp")
+	gl.ErrorContext(ctx, "error", "key", "with space", "num", 1)
+
+	require.Len(t, w.entries, 3)
+
+	assert.Equal(t, []testEntry{{
+		msg: "info",
+		lvl: slog.LevelInfo,
+	}, {
+		msg: "warning key=value",
+		lvl: slog.LevelWarn,
+	}, {
+		msg: `error prefix=test grp.key="with space" grp.num=1`,
+		lvl: slog.LevelError,
+	}}, w.entries)
+}
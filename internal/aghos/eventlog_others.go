@@ -0,0 +1,21 @@
+//go:build !windows
+
+package aghos
+
+import (
+	"fmt"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// newEventLogWriter returns an error, since the Event Log is only available on
+// Windows.
+func newEventLogWriter(_ string) (w eventLogEntryWriter, err error) {
+	return nil, fmt.Errorf("event log: %w", errors.ErrUnsupported)
+}
+
+// installEventLog does nothing, since the Event Log is only available on
+// Windows.
+func installEventLog(_ string) (err error) {
+	return nil
+}
@@ -0,0 +1,101 @@
+package aghos
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// Service manager notification states, see sd_notify(3).
+const (
+	SDNotifyReady     = "READY=1"
+	SDNotifyStopping  = "STOPPING=1"
+	SDNotifyReloading = "RELOADING=1"
+	SDNotifyWatchdog  = "WATCHDOG=1"
+)
+
+// Environment variables set by systemd for the notifications, see
+// sd_notify(3) and sd_watchdog_enabled(3).
+const (
+	envNotifySocket = "NOTIFY_SOCKET"
+	envWatchdogUsec = "WATCHDOG_USEC"
+	envWatchdogPID  = "WATCHDOG_PID"
+)
+
+// SDNotify sends the newline-separated state assignments to the service
+// manager.  sent is false if the service manager doesn't expect notifications,
+// for example if AdGuard Home isn't running as a systemd service with
+// Type=notify.
+func SDNotify(state string) (sent bool, err error) {
+	sockPath := os.Getenv(envNotifySocket)
+	if sockPath == "" {
+		return false, nil
+	}
+
+	// Abstract socket addresses start with "@" in the environment.
+	if sockPath[0] == '@' {
+		sockPath = "\x00" + sockPath[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: sockPath, Net: "unixgram"})
+	if err != nil {
+		return false, fmt.Errorf("connecting to notify socket: %w", err)
+	}
+	defer func() { err = errors.WithDeferred(err, conn.Close()) }()
+
+	_, err = conn.Write([]byte(state))
+	if err != nil {
+		return false, fmt.Errorf("writing notification: %w", err)
+	}
+
+	return true, nil
+}
+
+// SDReloadingState returns the state to send to the service manager before
+// AdGuard Home reloads its configuration or restarts in place.  It has the
+// monotonic timestamp required by the services with Type=notify-reload, if the
+// OS provides one.  [SDNotifyReady] must be sent once the reload is complete.
+func SDReloadingState() (state string) {
+	usec, ok := monotonicUsec()
+	if !ok {
+		return SDNotifyReloading
+	}
+
+	return SDNotifyReloading + "\nMONOTONIC_USEC=" + strconv.FormatUint(usec, 10)
+}
+
+// SDWatchdogInterval returns the watchdog timeout configured by the service
+// manager for the current process.  ok is false if the watchdog is disabled.
+// The process must send [SDNotifyWatchdog] more often than every d.
+func SDWatchdogInterval() (d time.Duration, ok bool, err error) {
+	usecStr := os.Getenv(envWatchdogUsec)
+	if usecStr == "" {
+		return 0, false, nil
+	}
+
+	if pidStr := os.Getenv(envWatchdogPID); pidStr != "" {
+		var pid int
+		pid, err = strconv.Atoi(pidStr)
+		if err != nil {
+			return 0, false, fmt.Errorf("parsing %s: %w", envWatchdogPID, err)
+		}
+
+		if pid != os.Getpid() {
+			// The watchdog is meant for another process.
+			return 0, false, nil
+		}
+	}
+
+	usec, err := strconv.ParseUint(usecStr, 10, 63)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing %s: %w", envWatchdogUsec, err)
+	} else if usec == 0 {
+		return 0, false, nil
+	}
+
+	return time.Duration(usec) * time.Microsecond, true, nil
+}
@@ -0,0 +1,20 @@
+//go:build linux
+
+package aghos
+
+import (
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// monotonicUsec returns the current value of CLOCK_MONOTONIC in microseconds.
+func monotonicUsec() (usec uint64, ok bool) {
+	var ts unix.Timespec
+	err := unix.ClockGettime(unix.CLOCK_MONOTONIC, &ts)
+	if err != nil {
+		return 0, false
+	}
+
+	return uint64(ts.Nano()) / uint64(time.Microsecond), true
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package aghos
+
+// monotonicUsec returns false, since only systemd, which is Linux-only, uses
+// the monotonic timestamps.
+func monotonicUsec() (usec uint64, ok bool) {
+	return 0, false
+}
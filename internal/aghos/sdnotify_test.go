@@ -0,0 +1,123 @@
+package aghos_test
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghos"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSDNotify(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+
+	sent, err := aghos.SDNotify(aghos.SDNotifyReady)
+	require.NoError(t, err)
+
+	assert.False(t, sent)
+
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping unix socket test on windows")
+	}
+
+	sockPath := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: sockPath, Net: "unixgram"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	t.Setenv("NOTIFY_SOCKET", sockPath)
+
+	sent, err = aghos.SDNotify(aghos.SDNotifyReady)
+	require.NoError(t, err)
+
+	assert.True(t, sent)
+
+	buf := make([]byte, 64)
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+
+	assert.Equal(t, aghos.SDNotifyReady, string(buf[:n]))
+}
+
+func TestSDReloadingState(t *testing.T) {
+	t.Parallel()
+
+	state := aghos.SDReloadingState()
+	if runtime.GOOS != "linux" {
+		assert.Equal(t, aghos.SDNotifyReloading, state)
+
+		return
+	}
+
+	reloading, usecStr, ok := strings.Cut(state, "\nMONOTONIC_USEC=")
+	require.True(t, ok)
+
+	assert.Equal(t, aghos.SDNotifyReloading, reloading)
+
+	usec, err := strconv.ParseUint(usecStr, 10, 64)
+	require.NoError(t, err)
+
+	assert.Positive(t, usec)
+}
+
+func TestSDWatchdogInterval(t *testing.T) {
+	pid := strconv.Itoa(os.Getpid())
+
+	testCases := []struct {
+		name       string
+		usec       string
+		pid        string
+		wantErrMsg string
+		want       time.Duration
+		wantOK     bool
+	}{{
+		name:       "disabled",
+		usec:       "",
+		pid:        "",
+		wantErrMsg: "",
+		want:       0,
+		wantOK:     false,
+	}, {
+		name:       "enabled",
+		usec:       "30000000",
+		pid:        pid,
+		wantErrMsg: "",
+		want:       30 * time.Second,
+		wantOK:     true,
+	}, {
+		name:       "other_process",
+		usec:       "30000000",
+		pid:        "1",
+		wantErrMsg: "",
+		want:       0,
+		wantOK:     false,
+	}, {
+		name:       "bad_usec",
+		usec:       "bad",
+		pid:        "",
+		wantErrMsg: `parsing WATCHDOG_USEC: strconv.ParseUint: parsing "bad": invalid syntax`,
+		want:       0,
+		wantOK:     false,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("WATCHDOG_USEC", tc.usec)
+			t.Setenv("WATCHDOG_PID", tc.pid)
+
+			d, ok, err := aghos.SDWatchdogInterval()
+			testutil.AssertErrorMsg(t, tc.wantErrMsg, err)
+
+			assert.Equal(t, tc.want, d)
+			assert.Equal(t, tc.wantOK, ok)
+		})
+	}
+}
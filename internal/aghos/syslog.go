@@ -4,3 +4,17 @@ package aghos
 func ConfigureSyslog(serviceName string) (err error) {
 	return configureSyslog(serviceName)
 }
+
+// InstallSystemLog registers serviceName as the source of the entries of the
+// system log, if needed.  The registration requires the administrative
+// permissions, so it should be made when the service is installed, since the
+// service may run under an account without them.
+func InstallSystemLog(serviceName string) (err error) {
+	return installEventLog(serviceName)
+}
+
+// LogCrash writes msg about a crash of the service to the system log, which is
+// the Event Log on Windows and syslog on other systems.
+func LogCrash(serviceName, msg string) (err error) {
+	return logCrash(serviceName, msg)
+}
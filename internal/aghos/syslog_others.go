@@ -5,6 +5,7 @@ package aghos
 import (
 	"log/syslog"
 
+	"github.com/AdguardTeam/golibs/errors"
 	"github.com/AdguardTeam/golibs/log"
 )
 
@@ -20,3 +21,15 @@ func configureSyslog(serviceName string) (err error) {
 
 	return nil
 }
+
+// logCrash writes the error message about a crash to syslog.
+func logCrash(serviceName, msg string) (err error) {
+	w, err := syslog.New(syslog.LOG_ERR|syslog.LOG_USER, serviceName)
+	if err != nil {
+		// Don't wrap the error, because it's informative enough as is.
+		return err
+	}
+	defer func() { err = errors.WithDeferred(err, w.Close()) }()
+
+	return w.Err(msg)
+}
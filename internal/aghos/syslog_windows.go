@@ -3,8 +3,11 @@
 package aghos
 
 import (
+	"fmt"
+	"log/slog"
 	"strings"
 
+	"github.com/AdguardTeam/golibs/errors"
 	"github.com/AdguardTeam/golibs/log"
 	"golang.org/x/sys/windows"
 	"golang.org/x/sys/windows/svc/eventlog"
@@ -16,11 +19,86 @@ type eventLogWriter struct {
 
 // Write implements io.Writer interface for eventLogWriter.
 func (w *eventLogWriter) Write(b []byte) (int, error) {
-	return len(b), w.el.Info(1, string(b))
+	return len(b), w.el.Info(infoEventID, string(b))
 }
 
 // configureSyslog sets standard log output to event log.
 func configureSyslog(serviceName string) (err error) {
+	el, err := openEventLog(serviceName)
+	if err != nil {
+		// Don't wrap the error, because it's informative enough as is.
+		return err
+	}
+
+	log.SetOutput(&eventLogWriter{el: el})
+
+	return nil
+}
+
+// logCrash writes the error message about a crash to the event log.
+func logCrash(serviceName, msg string) (err error) {
+	el, err := openEventLog(serviceName)
+	if err != nil {
+		// Don't wrap the error, because it's informative enough as is.
+		return err
+	}
+	defer func() { err = errors.WithDeferred(err, el.Close()) }()
+
+	return el.Error(crashEventID, msg)
+}
+
+// Identifiers of the event log entries.
+const (
+	infoEventID    = 1
+	crashEventID   = 2
+	warningEventID = 3
+	errorEventID   = 4
+)
+
+// entryWriter is the [eventLogEntryWriter] that uses the event log.
+type entryWriter struct {
+	el *eventlog.Log
+}
+
+// type check
+var _ eventLogEntryWriter = (*entryWriter)(nil)
+
+// writeEntry implements the [eventLogEntryWriter] interface for *entryWriter.
+func (w *entryWriter) writeEntry(lvl slog.Level, msg string) (err error) {
+	switch {
+	case lvl >= slog.LevelError:
+		return w.el.Error(errorEventID, msg)
+	case lvl >= slog.LevelWarn:
+		return w.el.Warning(warningEventID, msg)
+	default:
+		return w.el.Info(infoEventID, msg)
+	}
+}
+
+// newEventLogWriter opens the event log for serviceName.
+func newEventLogWriter(serviceName string) (w eventLogEntryWriter, err error) {
+	el, err := openEventLog(serviceName)
+	if err != nil {
+		// Don't wrap the error, because it's informative enough as is.
+		return nil, err
+	}
+
+	return &entryWriter{el: el}, nil
+}
+
+// installEventLog registers the event log source for serviceName.
+func installEventLog(serviceName string) (err error) {
+	err = eventlog.InstallAsEventCreate(serviceName, eventlog.Info|eventlog.Warning|eventlog.Error)
+	if err != nil && !strings.Contains(err.Error(), "registry key already exists") {
+		return fmt.Errorf("installing event log source: %w", err)
+	}
+
+	return nil
+}
+
+// openEventLog registers the event log source for serviceName, if needed, and
+// opens it.
+func openEventLog(serviceName string) (el *eventlog.Log, err error) {
 	// Note that the eventlog src is the same as the service name, otherwise we
 	// will get "the description for event id cannot be found" warning in every
 	// log record.
@@ -33,16 +111,9 @@ func configureSyslog(serviceName string) (err error) {
 		!strings.Contains(err.Error(), "registry key already exists") &&
 		err != windows.ERROR_ACCESS_DENIED {
 		// Don't wrap the error, because it's informative enough as is.
-		return err
+		return nil, err
 	}
 
-	el, err := eventlog.Open(serviceName)
-	if err != nil {
-		// Don't wrap the error, because it's informative enough as is.
-		return err
-	}
-
-	log.SetOutput(&eventLogWriter{el: el})
-
-	return nil
+	// Don't wrap the error, because it's informative enough as is.
+	return eventlog.Open(serviceName)
 }
@@ -0,0 +1,311 @@
+package aghtls
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+	"github.com/AdguardTeam/golibs/service"
+	"golang.org/x/crypto/ocsp"
+)
+
+// OCSP stapling timings.
+const (
+	// ocspRetryInterval is the time to wait before the next attempt to fetch
+	// an OCSP response after a failure.
+	ocspRetryInterval = 1 * time.Minute
+
+	// ocspMinRefreshInterval is the minimum time between two successful
+	// fetches of the OCSP response.
+	ocspMinRefreshInterval = 1 * time.Minute
+
+	// ocspDefaultRefreshInterval is used when the OCSP response doesn't
+	// specify the time of the next update.
+	ocspDefaultRefreshInterval = 12 * time.Hour
+
+	// ocspMaxRespSize is the maximum size of an OCSP response.
+	ocspMaxRespSize = 64 * 1024
+)
+
+// OCSPStaplerConfig is the configuration structure for [NewOCSPStapler].
+type OCSPStaplerConfig struct {
+	// Logger is used for logging the operation of the stapler.  It must not
+	// be nil.
+	Logger *slog.Logger
+
+	// HTTPClient is used to send requests to the OCSP responders.  It must
+	// not be nil.
+	HTTPClient *http.Client
+}
+
+// OCSPStapler fetches and caches the OCSP response for the current
+// certificate, refreshing it in the background.  A nil *OCSPStapler is valid
+// and never staples anything.
+type OCSPStapler struct {
+	logger *slog.Logger
+	client *http.Client
+
+	// updates signals the refresh loop that the certificate has changed.
+	updates chan struct{}
+
+	// done is closed on shutdown.
+	done chan struct{}
+
+	// mu protects leaf, issuer, staple, and nextUpdate.
+	mu         *sync.Mutex
+	leaf       *x509.Certificate
+	issuer     *x509.Certificate
+	staple     []byte
+	nextUpdate time.Time
+}
+
+// NewOCSPStapler returns a new properly initialized *OCSPStapler.  c must not
+// be nil.
+func NewOCSPStapler(c *OCSPStaplerConfig) (s *OCSPStapler) {
+	return &OCSPStapler{
+		logger:  c.Logger,
+		client:  c.HTTPClient,
+		updates: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		mu:      &sync.Mutex{},
+	}
+}
+
+// type check
+var _ service.Interface = (*OCSPStapler)(nil)
+
+// Start implements the [service.Interface] interface for *OCSPStapler.
+func (s *OCSPStapler) Start(ctx context.Context) (err error) {
+	go s.refreshLoop(ctx)
+
+	return nil
+}
+
+// Shutdown implements the [service.Interface] interface for *OCSPStapler.
+func (s *OCSPStapler) Shutdown(_ context.Context) (err error) {
+	close(s.done)
+
+	return nil
+}
+
+// Set sets the PEM-encoded certificate chain to fetch the OCSP responses for.
+// The chain must contain the issuer certificate right after the leaf one.  If
+// chainPEM is empty, or the certificate doesn't specify an OCSP responder,
+// nothing is stapled.  s may be nil.
+func (s *OCSPStapler) Set(ctx context.Context, chainPEM []byte) {
+	if s == nil {
+		return
+	}
+
+	leaf, issuer, err := parseOCSPChain(chainPEM)
+	if err != nil {
+		s.logger.DebugContext(ctx, "ocsp stapling disabled", slogutil.KeyError, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if leaf != nil && s.leaf != nil && leaf.Equal(s.leaf) {
+		return
+	}
+
+	s.leaf, s.issuer = leaf, issuer
+	s.staple, s.nextUpdate = nil, time.Time{}
+
+	select {
+	case s.updates <- struct{}{}:
+	default:
+	}
+}
+
+// parseOCSPChain returns the leaf and issuer certificates from the
+// PEM-encoded chain.  It returns nil certificates if stapling isn't possible
+// for the chain.
+func parseOCSPChain(chainPEM []byte) (leaf, issuer *x509.Certificate, err error) {
+	var ders [][]byte
+	for rest := chainPEM; len(ders) < 2; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		if block.Type == "CERTIFICATE" {
+			ders = append(ders, block.Bytes)
+		}
+	}
+
+	if len(ders) < 2 {
+		return nil, nil, errors.Error("no issuer certificate in chain")
+	}
+
+	leaf, err = x509.ParseCertificate(ders[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing leaf certificate: %w", err)
+	}
+
+	if len(leaf.OCSPServer) == 0 {
+		return nil, nil, errors.Error("no ocsp responder in certificate")
+	}
+
+	issuer, err = x509.ParseCertificate(ders[1])
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing issuer certificate: %w", err)
+	}
+
+	return leaf, issuer, nil
+}
+
+// Staple returns a shallow copy of cert with the cached OCSP response for it,
+// or cert itself if there is none.  s may be nil.
+func (s *OCSPStapler) Staple(cert *tls.Certificate) (stapled *tls.Certificate) {
+	if s == nil || cert == nil || len(cert.Certificate) == 0 {
+		return cert
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.staple == nil || !bytes.Equal(cert.Certificate[0], s.leaf.Raw) {
+		return cert
+	}
+
+	if !s.nextUpdate.IsZero() && time.Now().After(s.nextUpdate) {
+		// Don't staple an expired response, since clients reject those.
+		return cert
+	}
+
+	cp := *cert
+	cp.OCSPStaple = s.staple
+
+	return &cp
+}
+
+// refreshLoop fetches the OCSP response whenever the certificate changes or
+// the cached response is about to expire.  It is intended to be used as a
+// goroutine.
+func (s *OCSPStapler) refreshLoop(ctx context.Context) {
+	defer slogutil.RecoverAndLog(ctx, s.logger)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ctx.Done():
+			return
+		case <-s.updates:
+			timer.Reset(0)
+		case <-timer.C:
+			if next := s.refresh(ctx); next > 0 {
+				timer.Reset(next)
+			}
+		}
+	}
+}
+
+// refresh fetches the OCSP response for the current certificate and returns
+// the time to wait before the next refresh.  It returns zero if there is
+// nothing to refresh.
+func (s *OCSPStapler) refresh(ctx context.Context) (next time.Duration) {
+	s.mu.Lock()
+	leaf, issuer := s.leaf, s.issuer
+	s.mu.Unlock()
+
+	if leaf == nil {
+		return 0
+	}
+
+	raw, resp, err := s.fetch(ctx, leaf, issuer)
+	if err != nil {
+		// Keep the previous response, if any, since it's still valid until
+		// its next update time.
+		s.logger.WarnContext(ctx, "fetching ocsp response", slogutil.KeyError, err)
+
+		return ocspRetryInterval
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !leaf.Equal(s.leaf) {
+		// The certificate has changed during the request.
+		return 0
+	}
+
+	if resp.Status != ocsp.Good {
+		s.logger.WarnContext(ctx, "certificate is not good", "ocsp_status", resp.Status)
+		s.staple, s.nextUpdate = nil, time.Time{}
+
+		return ocspRetryInterval
+	}
+
+	s.staple, s.nextUpdate = raw, resp.NextUpdate
+	s.logger.DebugContext(ctx, "updated ocsp staple", "next_update", resp.NextUpdate)
+
+	if resp.NextUpdate.IsZero() {
+		return ocspDefaultRefreshInterval
+	}
+
+	// Refresh in the middle of the validity period to have enough time to
+	// retry on failures.
+	return max(time.Until(resp.NextUpdate)/2, ocspMinRefreshInterval)
+}
+
+// fetch sends the OCSP request for leaf to its responder.
+func (s *OCSPStapler) fetch(
+	ctx context.Context,
+	leaf *x509.Certificate,
+	issuer *x509.Certificate,
+) (raw []byte, resp *ocsp.Response, err error) {
+	reqData, err := ocsp.CreateRequest(leaf, issuer, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		leaf.OCSPServer[0],
+		bytes.NewReader(reqData),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating http request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/ocsp-request")
+
+	httpResp, err := s.client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("requesting: %w", err)
+	}
+	defer func() { err = errors.WithDeferred(err, httpResp.Body.Close()) }()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("unexpected status code %d", httpResp.StatusCode)
+	}
+
+	raw, err = io.ReadAll(io.LimitReader(httpResp.Body, ocspMaxRespSize))
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	resp, err = ocsp.ParseResponseForCert(raw, leaf, issuer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	return raw, resp, nil
+}
@@ -0,0 +1,131 @@
+package aghtls_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghtls"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+// newTestCert creates a certificate from tmpl signed by parent with
+// parentKey, or a self-signed one if parent is nil.
+func newTestCert(
+	tb testing.TB,
+	tmpl *x509.Certificate,
+	parent *x509.Certificate,
+	parentKey *ecdsa.PrivateKey,
+) (cert *x509.Certificate, key *ecdsa.PrivateKey) {
+	tb.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(tb, err)
+
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	require.NoError(tb, err)
+
+	cert, err = x509.ParseCertificate(der)
+	require.NoError(tb, err)
+
+	return cert, key
+}
+
+func TestOCSPStapler(t *testing.T) {
+	now := time.Now()
+
+	issuer, issuerKey := newTestCert(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, nil)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		req, err := ocsp.ParseRequest(body)
+		require.NoError(t, err)
+
+		resp, err := ocsp.CreateResponse(issuer, issuer, ocsp.Response{
+			Status:       ocsp.Good,
+			SerialNumber: req.SerialNumber,
+			ThisUpdate:   now,
+			NextUpdate:   now.Add(time.Hour),
+		}, issuerKey)
+		require.NoError(t, err)
+
+		_, _ = w.Write(resp)
+	}))
+	t.Cleanup(srv.Close)
+
+	leaf, leafKey := newTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "example.org"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+		OCSPServer:   []string{srv.URL},
+	}, issuer, issuerKey)
+
+	chainPEM := append(
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Raw}),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: issuer.Raw})...,
+	)
+
+	cert := &tls.Certificate{
+		Certificate: [][]byte{leaf.Raw, issuer.Raw},
+		PrivateKey:  leafKey,
+	}
+
+	s := aghtls.NewOCSPStapler(&aghtls.OCSPStaplerConfig{
+		Logger:     slogutil.NewDiscardLogger(),
+		HTTPClient: srv.Client(),
+	})
+
+	ctx := testutil.ContextWithTimeout(t, testTimeout)
+	require.NoError(t, s.Start(ctx))
+	testutil.CleanupAndRequireSuccess(t, func() (err error) { return s.Shutdown(ctx) })
+
+	assert.Empty(t, s.Staple(cert).OCSPStaple)
+
+	s.Set(ctx, chainPEM)
+
+	assert.Eventually(t, func() (ok bool) {
+		return len(s.Staple(cert).OCSPStaple) > 0
+	}, testTimeout, testTimeout/10)
+
+	otherCert := &tls.Certificate{Certificate: [][]byte{issuer.Raw}}
+	assert.Same(t, otherCert, s.Staple(otherCert))
+
+	s.Set(ctx, nil)
+	assert.Empty(t, s.Staple(cert).OCSPStaple)
+}
+
+func TestOCSPStapler_nil(t *testing.T) {
+	var s *aghtls.OCSPStapler
+
+	cert := &tls.Certificate{}
+	assert.Same(t, cert, s.Staple(cert))
+}
@@ -0,0 +1,221 @@
+package aghtls
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"fmt"
+	"hash"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// ErrBadPassphrase is returned by [DecryptPrivateKeyPEM] when the passphrase
+// doesn't match the key.
+const ErrBadPassphrase errors.Error = "incorrect private key passphrase"
+
+// pemTypeEncryptedPrivateKey is the type of PEM blocks containing encrypted
+// PKCS #8 private keys.
+const pemTypeEncryptedPrivateKey = "ENCRYPTED PRIVATE KEY"
+
+// Object identifiers of the supported PKCS #5 algorithms, see RFC 8018.
+var (
+	oidPBES2  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}
+	oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+
+	oidAES128CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+// encryptedPrivateKeyInfo is the EncryptedPrivateKeyInfo structure from
+// RFC 5208.
+type encryptedPrivateKeyInfo struct {
+	Algo          pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+// pbes2Params is the PBES2-params structure from RFC 8018.
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+// pbkdf2Params is the PBKDF2-params structure from RFC 8018.
+type pbkdf2Params struct {
+	Salt       []byte
+	Iterations int
+	KeyLength  int                      `asn1:"optional"`
+	PRF        pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// IsEncryptedPrivateKeyPEM returns true if data contains a PEM-encoded
+// encrypted PKCS #8 private key.
+func IsEncryptedPrivateKeyPEM(data []byte) (ok bool) {
+	return findPEMBlock(data, pemTypeEncryptedPrivateKey) != nil
+}
+
+// DecryptPrivateKeyPEM decrypts the first PEM-encoded encrypted PKCS #8
+// private key in data using passphrase and returns it as a PEM-encoded
+// unencrypted PKCS #8 private key.  Only PBES2 with PBKDF2 and AES-CBC is
+// supported, which is the default for OpenSSL 1.1.0 and later.
+func DecryptPrivateKeyPEM(data, passphrase []byte) (keyPEM []byte, err error) {
+	block := findPEMBlock(data, pemTypeEncryptedPrivateKey)
+	if block == nil {
+		return nil, errors.Error("no encrypted private key found")
+	}
+
+	der, err := decryptPKCS8(block.Bytes, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure that the result is actually a key, since a wrong passphrase
+	// can still produce a correct padding by chance.
+	_, err = x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, ErrBadPassphrase
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// findPEMBlock returns the first PEM block of type typ in data, if any.
+func findPEMBlock(data []byte, typ string) (block *pem.Block) {
+	for rest := data; ; {
+		block, rest = pem.Decode(rest)
+		if block == nil || block.Type == typ {
+			return block
+		}
+	}
+}
+
+// decryptPKCS8 decrypts the DER-encoded EncryptedPrivateKeyInfo.
+func decryptPKCS8(der, passphrase []byte) (keyDER []byte, err error) {
+	info := &encryptedPrivateKeyInfo{}
+	_, err = asn1.Unmarshal(der, info)
+	if err != nil {
+		return nil, fmt.Errorf("parsing encrypted private key: %w", err)
+	}
+
+	if !info.Algo.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("unsupported encryption algorithm %s", info.Algo.Algorithm)
+	}
+
+	params := &pbes2Params{}
+	_, err = asn1.Unmarshal(info.Algo.Parameters.FullBytes, params)
+	if err != nil {
+		return nil, fmt.Errorf("parsing pbes2 parameters: %w", err)
+	}
+
+	keyLen, err := aesKeyLen(params.EncryptionScheme.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := deriveKey(params.KeyDerivationFunc, passphrase, keyLen)
+	if err != nil {
+		return nil, err
+	}
+
+	var iv []byte
+	_, err = asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv)
+	if err != nil {
+		return nil, fmt.Errorf("parsing iv: %w", err)
+	}
+
+	return decryptAESCBC(key, iv, info.EncryptedData)
+}
+
+// aesKeyLen returns the key length for the AES-CBC encryption scheme.
+func aesKeyLen(oid asn1.ObjectIdentifier) (n int, err error) {
+	switch {
+	case oid.Equal(oidAES128CBC):
+		return 16, nil
+	case oid.Equal(oidAES192CBC):
+		return 24, nil
+	case oid.Equal(oidAES256CBC):
+		return 32, nil
+	default:
+		return 0, fmt.Errorf("unsupported encryption scheme %s", oid)
+	}
+}
+
+// deriveKey derives the encryption key of length keyLen from passphrase
+// using the key derivation function kdf.
+func deriveKey(
+	kdf pkix.AlgorithmIdentifier,
+	passphrase []byte,
+	keyLen int,
+) (key []byte, err error) {
+	if !kdf.Algorithm.Equal(oidPBKDF2) {
+		return nil, fmt.Errorf("unsupported key derivation function %s", kdf.Algorithm)
+	}
+
+	params := &pbkdf2Params{}
+	_, err = asn1.Unmarshal(kdf.Parameters.FullBytes, params)
+	if err != nil {
+		return nil, fmt.Errorf("parsing pbkdf2 parameters: %w", err)
+	}
+
+	var h func() hash.Hash
+	switch prf := params.PRF.Algorithm; {
+	case len(prf) == 0, prf.Equal(oidHMACWithSHA1):
+		h = sha1.New
+	case prf.Equal(oidHMACWithSHA256):
+		h = sha256.New
+	case prf.Equal(oidHMACWithSHA384):
+		h = sha512.New384
+	case prf.Equal(oidHMACWithSHA512):
+		h = sha512.New
+	default:
+		return nil, fmt.Errorf("unsupported pbkdf2 prf %s", prf)
+	}
+
+	return pbkdf2.Key(h, string(passphrase), params.Salt, params.Iterations, keyLen)
+}
+
+// decryptAESCBC decrypts data with key and iv and removes the PKCS #7
+// padding.
+func decryptAESCBC(key, iv, data []byte) (plain []byte, err error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+
+	if len(iv) != block.BlockSize() {
+		return nil, fmt.Errorf("bad iv length %d", len(iv))
+	}
+
+	if len(data) == 0 || len(data)%block.BlockSize() != 0 {
+		return nil, fmt.Errorf("bad encrypted data length %d", len(data))
+	}
+
+	plain = make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, data)
+
+	padLen := int(plain[len(plain)-1])
+	if padLen == 0 || padLen > block.BlockSize() {
+		return nil, ErrBadPassphrase
+	}
+
+	pad := plain[len(plain)-padLen:]
+	if !bytes.Equal(pad, bytes.Repeat([]byte{byte(padLen)}, padLen)) {
+		return nil, ErrBadPassphrase
+	}
+
+	return plain[:len(plain)-padLen], nil
+}
@@ -2,6 +2,7 @@ package aghuser
 
 import (
 	"crypto/rand"
+	"net/netip"
 	"time"
 )
 
@@ -25,6 +26,18 @@ type Session struct {
 	// Expire indicates when the session will expire.
 	Expire time.Time
 
+	// LastActivity is the time of the latest request within the session.  It's
+	// zero if it's unknown.
+	LastActivity time.Time
+
+	// RemoteIP is the address of the client of the latest request within the
+	// session, if known.
+	RemoteIP netip.Addr
+
+	// UserAgent is the User-Agent header of the latest request within the
+	// session, if any.
+	UserAgent string
+
 	// UserLogin is the login of the web user associated with the session.
 	//
 	// TODO(s.chzhen):  Remove this field and associate the user by UserID.
@@ -5,6 +5,8 @@ import (
 	"encoding/binary"
 	"fmt"
 	"log/slog"
+	"net/netip"
+	"strings"
 	"sync"
 	"time"
 
@@ -34,6 +36,15 @@ type SessionStorage interface {
 	// DeleteByToken removes a stored web user session by the provided token.
 	DeleteByToken(ctx context.Context, t SessionToken) (err error)
 
+	// All returns all stored unexpired web user sessions.  The returned
+	// sessions must not be modified.
+	All(ctx context.Context) (sessions []*Session, err error)
+
+	// Touch records a request within the session with the token t from the
+	// client with ip and userAgent.  It does nothing if there is no such
+	// session.
+	Touch(ctx context.Context, t SessionToken, ip netip.Addr, userAgent string) (err error)
+
 	// Close releases the web user sessions database resources.
 	Close() (err error)
 }
@@ -59,6 +70,11 @@ type DefaultSessionStorageConfig struct {
 	// SessionTTL is the default Time-To-Live duration for web user sessions.
 	// It specifies how long a session should last and is a required field.
 	SessionTTL time.Duration
+
+	// IdleTimeout is the duration of inactivity, after which a web user
+	// session expires.  Zero means that the sessions don't expire due to the
+	// inactivity.
+	IdleTimeout time.Duration
 }
 
 // DefaultSessionStorage is the default bbolt database implementation of the
@@ -85,6 +101,10 @@ type DefaultSessionStorage struct {
 
 	// sessionTTL is the default Time-To-Live value for web user sessions.
 	sessionTTL time.Duration
+
+	// idleTimeout is the duration of inactivity, after which a session
+	// expires.  Zero means no idle timeout.
+	idleTimeout time.Duration
 }
 
 // dbOpenTimeout is the maximum duration to wait for opening the bbolt database.
@@ -104,7 +124,8 @@ func NewDefaultSessionStorage(
 		logger:     conf.Logger,
 		mu:         &sync.Mutex{},
 		sessions:   map[SessionToken]*Session{},
-		sessionTTL: conf.SessionTTL,
+		sessionTTL:  conf.SessionTTL,
+		idleTimeout: conf.IdleTimeout,
 	}
 
 	dbFilename := conf.DBPath
@@ -237,7 +258,7 @@ func (ds *DefaultSessionStorage) bboltSessionHandler(
 			return nil
 		}
 
-		if now.After(s.Expire) {
+		if ds.isExpired(s, now) {
 			*invalidSessions = append(*invalidSessions, k)
 
 			return nil
@@ -277,9 +298,27 @@ const (
 	// bboltSessionNameLen is the length of the name field in the binary entry
 	// stored in bbolt.
 	bboltSessionNameLen = 2
+
+	// bboltSessionActivityLen is the length of the last activity field in the
+	// binary entry stored in bbolt.
+	bboltSessionActivityLen = 4
+
+	// bboltSessionIPLen is the length of the length of the IP address field in
+	// the binary entry stored in bbolt.
+	bboltSessionIPLen = 1
+
+	// bboltSessionUALen is the length of the length of the User-Agent field in
+	// the binary entry stored in bbolt.
+	bboltSessionUALen = 2
 )
 
-// bboltDecode deserializes decodes a binary data into a session.
+// MaxUserAgentLen is the maximum length of [Session.UserAgent] in bytes.  The
+// longer User-Agent headers are truncated.
+const MaxUserAgentLen = 256
+
+// bboltDecode deserializes decodes a binary data into a session.  The activity
+// fields following the login are optional, since the sessions stored by the
+// previous versions don't have them.
 func bboltDecode(data []byte) (s *Session, err error) {
 	if len(data) < bboltSessionExpireLen+bboltSessionNameLen {
 		return nil, fmt.Errorf("length of the data is less than expected: got %d", len(data))
@@ -290,44 +329,121 @@ func bboltDecode(data []byte) (s *Session, err error) {
 	nameData := data[bboltSessionExpireLen+bboltSessionNameLen:]
 
 	nameLen := binary.BigEndian.Uint16(nameLenData)
-	if len(nameData) != int(nameLen) {
+	if len(nameData) < int(nameLen) {
 		return nil, fmt.Errorf("login: expected length %d, got %d", nameLen, len(nameData))
 	}
 
 	expire := binary.BigEndian.Uint32(expireData)
-
-	return &Session{
+	s = &Session{
 		Expire:    time.Unix(int64(expire), 0),
-		UserLogin: Login(nameData),
-	}, nil
+		UserLogin: Login(nameData[:nameLen]),
+	}
+
+	rest := nameData[nameLen:]
+	if len(rest) == 0 {
+		return s, nil
+	}
+
+	err = decodeActivity(s, rest)
+	if err != nil {
+		return nil, fmt.Errorf("activity: %w", err)
+	}
+
+	return s, nil
+}
+
+// decodeActivity decodes the activity fields of s from data.
+func decodeActivity(s *Session, data []byte) (err error) {
+	if len(data) < bboltSessionActivityLen+bboltSessionIPLen {
+		return fmt.Errorf("length of the data is less than expected: got %d", len(data))
+	}
+
+	if sec := binary.BigEndian.Uint32(data[:bboltSessionActivityLen]); sec != 0 {
+		s.LastActivity = time.Unix(int64(sec), 0)
+	}
+
+	data = data[bboltSessionActivityLen:]
+	ipLen := int(data[0])
+	data = data[bboltSessionIPLen:]
+	if len(data) < ipLen+bboltSessionUALen {
+		return fmt.Errorf("ip: expected length %d, got %d", ipLen, len(data))
+	}
+
+	if ipLen > 0 {
+		var ok bool
+		s.RemoteIP, ok = netip.AddrFromSlice(data[:ipLen])
+		if !ok {
+			return fmt.Errorf("ip: bad length %d", ipLen)
+		}
+	}
+
+	data = data[ipLen:]
+	uaLen := int(binary.BigEndian.Uint16(data[:bboltSessionUALen]))
+	data = data[bboltSessionUALen:]
+	if len(data) != uaLen {
+		return fmt.Errorf("user agent: expected length %d, got %d", uaLen, len(data))
+	}
+
+	s.UserAgent = string(data)
+
+	return nil
 }
 
 // bboltEncode serializes a session properties into a binary data.
 func bboltEncode(s *Session) (data []byte) {
-	data = make([]byte, bboltSessionExpireLen+bboltSessionNameLen+len(s.UserLogin))
+	ip := s.RemoteIP.AsSlice()
+	ua := s.UserAgent
+
+	data = make([]byte, 0, bboltSessionExpireLen+
+		bboltSessionNameLen+
+		len(s.UserLogin)+
+		bboltSessionActivityLen+
+		bboltSessionIPLen+
+		len(ip)+
+		bboltSessionUALen+
+		len(ua),
+	)
 
-	expireData := data[:bboltSessionExpireLen]
-	nameLenData := data[bboltSessionExpireLen : bboltSessionExpireLen+bboltSessionNameLen]
-	nameData := data[bboltSessionExpireLen+bboltSessionNameLen:]
+	data = binary.BigEndian.AppendUint32(data, uint32(s.Expire.Unix()))
+	data = binary.BigEndian.AppendUint16(data, uint16(len(s.UserLogin)))
+	data = append(data, s.UserLogin...)
 
-	expire := uint32(s.Expire.Unix())
-	binary.BigEndian.PutUint32(expireData, expire)
-	binary.BigEndian.PutUint16(nameLenData, uint16(len(s.UserLogin)))
-	copy(nameData, []byte(s.UserLogin))
+	var activity uint32
+	if !s.LastActivity.IsZero() {
+		activity = uint32(s.LastActivity.Unix())
+	}
+
+	data = binary.BigEndian.AppendUint32(data, activity)
+	data = append(data, byte(len(ip)))
+	data = append(data, ip...)
+	data = binary.BigEndian.AppendUint16(data, uint16(len(ua)))
+	data = append(data, ua...)
 
 	return data
 }
 
+// isExpired returns true if s has expired at now either due to its TTL or due
+// to the inactivity.
+func (ds *DefaultSessionStorage) isExpired(s *Session, now time.Time) (ok bool) {
+	if now.After(s.Expire) {
+		return true
+	}
+
+	return ds.idleTimeout > 0 && !s.LastActivity.IsZero() && now.Sub(s.LastActivity) > ds.idleTimeout
+}
+
 // type check
 var _ SessionStorage = (*DefaultSessionStorage)(nil)
 
 // New implements the [SessionStorage] interface for *DefaultSessionStorage.
 func (ds *DefaultSessionStorage) New(ctx context.Context, u *User) (s *Session, err error) {
+	now := ds.clock.Now()
 	s = &Session{
-		Token:     NewSessionToken(),
-		UserID:    u.ID,
-		UserLogin: u.Login,
-		Expire:    ds.clock.Now().Add(ds.sessionTTL),
+		Token:        NewSessionToken(),
+		UserID:       u.ID,
+		UserLogin:    u.Login,
+		Expire:       now.Add(ds.sessionTTL),
+		LastActivity: now,
 	}
 
 	err = ds.store(s)
@@ -387,7 +503,7 @@ func (ds *DefaultSessionStorage) FindByToken(ctx context.Context, t SessionToken
 	}
 
 	now := ds.clock.Now()
-	if now.After(s.Expire) {
+	if ds.isExpired(s, now) {
 		err = ds.deleteByToken(ctx, t)
 		if err != nil {
 			return nil, fmt.Errorf("expired session: %w", err)
@@ -409,6 +525,69 @@ func (ds *DefaultSessionStorage) DeleteByToken(ctx context.Context, t SessionTok
 	return ds.deleteByToken(ctx, t)
 }
 
+// All implements the [SessionStorage] interface for *DefaultSessionStorage.
+func (ds *DefaultSessionStorage) All(ctx context.Context) (sessions []*Session, err error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	now := ds.clock.Now()
+	sessions = make([]*Session, 0, len(ds.sessions))
+	for _, s := range ds.sessions {
+		if ds.isExpired(s, now) {
+			continue
+		}
+
+		sessCopy := *s
+		sessions = append(sessions, &sessCopy)
+	}
+
+	return sessions, nil
+}
+
+// activityPrecision is the precision of [Session.LastActivity] stored in the
+// database.  The activity within the same interval isn't written to the
+// database, unless the client has changed.
+const activityPrecision = time.Minute
+
+// Touch implements the [SessionStorage] interface for *DefaultSessionStorage.
+func (ds *DefaultSessionStorage) Touch(
+	ctx context.Context,
+	t SessionToken,
+	ip netip.Addr,
+	userAgent string,
+) (err error) {
+	if len(userAgent) > MaxUserAgentLen {
+		userAgent = strings.ToValidUTF8(userAgent[:MaxUserAgentLen], "")
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	s, ok := ds.sessions[t]
+	if !ok {
+		return nil
+	}
+
+	now := ds.clock.Now()
+	needStore := s.RemoteIP != ip ||
+		s.UserAgent != userAgent ||
+		!s.LastActivity.Truncate(activityPrecision).Equal(now.Truncate(activityPrecision))
+
+	s.LastActivity, s.RemoteIP, s.UserAgent = now, ip, userAgent
+	if !needStore {
+		return nil
+	}
+
+	// Store the session under the lock, so that a concurrent deletion doesn't
+	// leave it in the database.
+	err = ds.store(s)
+	if err != nil {
+		return fmt.Errorf("storing session: %w", err)
+	}
+
+	return nil
+}
+
 // deleteByToken removes stored session by token.  ds.mu is expected to be
 // locked.
 func (ds *DefaultSessionStorage) deleteByToken(ctx context.Context, t SessionToken) (err error) {
@@ -2,7 +2,9 @@ package aghuser_test
 
 import (
 	"context"
+	"net/netip"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -160,3 +162,84 @@ func TestDefaultSessionStorage(t *testing.T) {
 		assert.Nil(t, got)
 	}))
 }
+
+func TestDefaultSessionStorage_Touch(t *testing.T) {
+	const (
+		login aghuser.Login = "user"
+
+		idleTimeout = 10 * time.Minute
+	)
+
+	var (
+		ctx    = testutil.ContextWithTimeout(t, testTimeout)
+		logger = slogutil.NewDiscardLogger()
+	)
+
+	date := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &faketime.Clock{
+		OnNow: func() (now time.Time) {
+			return date
+		},
+	}
+
+	dbPath := filepath.Join(t.TempDir(), "sessions.db")
+
+	userDB := aghuser.NewDefaultDB()
+	err := userDB.Create(ctx, &aghuser.User{
+		Login: login,
+		ID:    aghuser.MustNewUserID(),
+	})
+	require.NoError(t, err)
+
+	conf := &aghuser.DefaultSessionStorageConfig{
+		Clock:       clock,
+		UserDB:      userDB,
+		Logger:      logger,
+		DBPath:      dbPath,
+		SessionTTL:  time.Hour,
+		IdleTimeout: idleTimeout,
+	}
+
+	ds, err := aghuser.NewDefaultSessionStorage(ctx, conf)
+	require.NoError(t, err)
+
+	sess := addSession(t, ctx, ds, login)
+
+	const ua = "Mozilla/5.0"
+	ip := netip.MustParseAddr("192.0.2.1")
+
+	date = date.Add(idleTimeout / 2)
+	err = ds.Touch(ctx, sess.Token, ip, ua)
+	require.NoError(t, err)
+
+	err = ds.Close()
+	require.NoError(t, err)
+
+	ds, err = aghuser.NewDefaultSessionStorage(ctx, conf)
+	require.NoError(t, err)
+	testutil.CleanupAndRequireSuccess(t, ds.Close)
+
+	sessions, err := ds.All(ctx)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+
+	got := sessions[0]
+	assert.Equal(t, sess.Token, got.Token)
+	assert.Equal(t, ip, got.RemoteIP)
+	assert.Equal(t, ua, got.UserAgent)
+	assert.Equal(t, date, got.LastActivity.UTC())
+
+	// The session is active, although more than idleTimeout has passed since
+	// its creation.
+	date = date.Add(idleTimeout / 2)
+	found, err := ds.FindByToken(ctx, sess.Token)
+	require.NoError(t, err)
+
+	assert.NotNil(t, found)
+
+	date = date.Add(idleTimeout)
+	found, err = ds.FindByToken(ctx, sess.Token)
+	require.NoError(t, err)
+
+	assert.Nil(t, found)
+}
@@ -9,11 +9,13 @@ import (
 	"net/netip"
 	"slices"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/AdguardTeam/AdGuardHome/internal/aghslog"
 	"github.com/AdguardTeam/AdGuardHome/internal/filtering"
 	"github.com/AdguardTeam/dnsproxy/proxy"
 	"github.com/AdguardTeam/dnsproxy/upstream"
+	"github.com/AdguardTeam/golibs/container"
 	"github.com/AdguardTeam/golibs/errors"
 	"github.com/AdguardTeam/golibs/logutil/slogutil"
 	"github.com/google/uuid"
@@ -68,9 +70,17 @@ type Persistent struct {
 	// Name of the persistent client.  Must not be empty.
 	Name string
 
+	// Notes is a free-form description of the client.
+	Notes string
+
 	// Tags is a list of client tags that categorize the client.
 	Tags []string
 
+	// CustomTags is a list of arbitrary user-defined tags of the client.
+	// Unlike Tags, these aren't restricted to the predefined set and aren't
+	// used by the filtering rules.
+	CustomTags []string
+
 	// Upstreams is a list of custom upstream DNS servers for the client.  If
 	// it's empty, the custom upstream cache is disabled, regardless of the
 	// value of UpstreamsCacheEnabled.
@@ -94,6 +104,11 @@ type Persistent struct {
 	// (IP, subnet, MAC, or ClientID).
 	ClientIDs []ClientID
 
+	// WakeOnLANBroadcast is the broadcast address to which Wake-on-LAN magic
+	// packets for the client are sent.  If it's not valid, the limited
+	// broadcast address is used.
+	WakeOnLANBroadcast netip.Addr
+
 	// UID is the unique identifier of the persistent client.
 	UID UID
 
@@ -165,6 +180,48 @@ func (c *Persistent) validate(ctx context.Context, l *slog.Logger, allTags []str
 	// TODO(s.chzhen):  Move to the constructor.
 	slices.Sort(c.Tags)
 
+	if l := utf8.RuneCountInString(c.Notes); l > MaxNotesLen {
+		return fmt.Errorf("notes: length %d is greater than %d", l, MaxNotesLen)
+	}
+
+	err = validateCustomTags(c.CustomTags)
+	if err != nil {
+		return fmt.Errorf("custom tags: %w", err)
+	}
+
+	slices.Sort(c.CustomTags)
+
+	return nil
+}
+
+const (
+	// MaxNotesLen is the maximum length of the persistent client notes in
+	// runes.
+	MaxNotesLen = 1024
+
+	// MaxCustomTagLen is the maximum length of a single custom tag in runes.
+	MaxCustomTagLen = 64
+)
+
+// validateCustomTags returns an error if tags contain empty, too long, or
+// duplicated values.
+func validateCustomTags(tags []string) (err error) {
+	set := container.NewMapSet[string]()
+	for i, t := range tags {
+		switch l := utf8.RuneCountInString(t); {
+		case strings.TrimSpace(t) != t:
+			return fmt.Errorf("at index %d: tag %q has surrounding spaces", i, t)
+		case l == 0:
+			return fmt.Errorf("at index %d: %w", i, errors.ErrEmptyValue)
+		case l > MaxCustomTagLen:
+			return fmt.Errorf("at index %d: length %d is greater than %d", i, l, MaxCustomTagLen)
+		case set.Has(t):
+			return fmt.Errorf("at index %d: %w: %q", i, errors.ErrDuplicated, t)
+		default:
+			set.Add(t)
+		}
+	}
+
 	return nil
 }
 
@@ -295,6 +352,7 @@ func (c *Persistent) ShallowClone() (clone *Persistent) {
 
 	clone.BlockedServices = c.BlockedServices.Clone()
 	clone.Tags = slices.Clone(c.Tags)
+	clone.CustomTags = slices.Clone(c.CustomTags)
 	clone.Upstreams = slices.Clone(c.Upstreams)
 
 	clone.IPs = slices.Clone(c.IPs)
@@ -1,8 +1,10 @@
 package client
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/AdguardTeam/golibs/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -122,3 +124,38 @@ func TestPersistent_EqualIDs(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateCustomTags(t *testing.T) {
+	testCases := []struct {
+		name       string
+		wantErrMsg string
+		tags       []string
+	}{{
+		name:       "valid",
+		wantErrMsg: "",
+		tags:       []string{"office", "Family", "floor 2"},
+	}, {
+		name:       "empty",
+		wantErrMsg: "at index 1: empty value",
+		tags:       []string{"office", ""},
+	}, {
+		name:       "spaces",
+		wantErrMsg: `at index 0: tag " office" has surrounding spaces`,
+		tags:       []string{" office"},
+	}, {
+		name:       "duplicated",
+		wantErrMsg: `at index 1: duplicated value: "office"`,
+		tags:       []string{"office", "office"},
+	}, {
+		name:       "too_long",
+		wantErrMsg: "at index 0: length 65 is greater than 64",
+		tags:       []string{strings.Repeat("a", MaxCustomTagLen+1)},
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateCustomTags(tc.tags)
+			testutil.AssertErrorMsg(t, tc.wantErrMsg, err)
+		})
+	}
+}
@@ -150,6 +150,12 @@ type Storage struct {
 	// arpDB is used to update [SourceARP] runtime client information.
 	arpDB arpdb.Interface
 
+	// arpMACs maps the IP addresses reported by the network neighborhood to
+	// their hardware addresses.  It is used to match persistent clients
+	// identified by MAC when their addresses are not leased by the DHCP server.
+	// It is protected by mu.
+	arpMACs map[netip.Addr]net.HardwareAddr
+
 	// done is the shutdown signaling channel.
 	done chan struct{}
 
@@ -182,6 +188,7 @@ func NewStorage(ctx context.Context, conf *StorageConfig) (s *Storage, err error
 		dhcp:                   conf.DHCP,
 		etcHosts:               conf.EtcHosts,
 		arpDB:                  conf.ARPDB,
+		arpMACs:                map[netip.Addr]net.HardwareAddr{},
 		done:                   make(chan struct{}),
 		allowedTags:            tags,
 		arpClientsUpdatePeriod: conf.ARPClientsUpdatePeriod,
@@ -251,6 +258,7 @@ func (s *Storage) addFromSystemARP(ctx context.Context) {
 
 	if err := s.arpDB.Refresh(ctx); err != nil {
 		s.arpDB = arpdb.Empty{}
+		clear(s.arpMACs)
 		s.logger.ErrorContext(ctx, "refreshing arp container", slogutil.KeyError, err)
 
 		return
@@ -258,6 +266,9 @@ func (s *Storage) addFromSystemARP(ctx context.Context) {
 
 	ns := s.arpDB.Neighbors()
 	if len(ns) == 0 {
+		// Don't match the persistent clients by the MAC addresses, which are
+		// no longer in the network neighborhood.
+		clear(s.arpMACs)
 		s.logger.DebugContext(ctx, "refreshing arp container: the update is empty")
 
 		return
@@ -265,9 +276,14 @@ func (s *Storage) addFromSystemARP(ctx context.Context) {
 
 	src := SourceARP
 	s.runtimeIndex.clearSource(src)
+	clear(s.arpMACs)
 
 	for _, n := range ns {
 		s.runtimeIndex.setInfo(n.IP, src, []string{n.Name})
+
+		if len(n.MAC) > 0 {
+			s.arpMACs[n.IP] = n.MAC
+		}
 	}
 
 	removed := s.runtimeIndex.removeEmpty()
@@ -567,7 +583,7 @@ func (s *Storage) findByIP(addr netip.Addr) (p *Persistent, ok bool) {
 		return p, true
 	}
 
-	foundMAC := s.dhcp.MACByIP(addr)
+	foundMAC := s.macByIP(addr)
 	if foundMAC != nil {
 		return s.index.findByMAC(foundMAC)
 	}
@@ -575,6 +591,21 @@ func (s *Storage) findByIP(addr netip.Addr) (p *Persistent, ok bool) {
 	return nil, false
 }
 
+// macByIP returns the hardware address of the client with the given IP
+// address.  The DHCP leases take precedence over the network neighborhood,
+// since those are more reliable.  mac is nil if the address is unknown.  s.mu
+// is expected to be locked.
+func (s *Storage) macByIP(addr netip.Addr) (mac net.HardwareAddr) {
+	if s.dhcp != nil {
+		mac = s.dhcp.MACByIP(addr)
+		if mac != nil {
+			return mac
+		}
+	}
+
+	return s.arpMACs[addr]
+}
+
 // FindLoose is like [Storage.Find] but it also tries to find a persistent
 // client by IP address without zone.  It strips the IPv6 zone index from the
 // stored IP addresses before comparing, because querylog entries don't have it.
@@ -593,9 +624,12 @@ func (s *Storage) FindLoose(ip netip.Addr, id string) (p *Persistent, ok bool) {
 		return p.ShallowClone(), ok
 	}
 
-	foundMAC := s.dhcp.MACByIP(ip)
+	foundMAC := s.macByIP(ip)
 	if foundMAC != nil {
-		return s.index.findByMAC(foundMAC)
+		p, ok = s.index.findByMAC(foundMAC)
+		if ok {
+			return p.ShallowClone(), true
+		}
 	}
 
 	p = s.index.findByIPWithoutZone(ip)
@@ -606,6 +640,20 @@ func (s *Storage) FindLoose(ip netip.Addr, id string) (p *Persistent, ok bool) {
 	return nil, false
 }
 
+// FindByName returns a copy of the persistent client with the given name.  ok
+// is false if no such client exists.
+func (s *Storage) FindByName(name string) (p *Persistent, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok = s.index.findByName(name)
+	if !ok {
+		return nil, false
+	}
+
+	return p.ShallowClone(), true
+}
+
 // RemoveByName removes persistent client information.  ok is false if no such
 // client exists by that name.
 func (s *Storage) RemoveByName(ctx context.Context, name string) (ok bool) {
@@ -768,16 +816,12 @@ func (s *Storage) ClearUpstreamCache() {
 // ClientID or client IP address, and applies it to the filtering settings.
 // setts must not be nil.
 func (s *Storage) ApplyClientFiltering(id string, addr netip.Addr, setts *filtering.Settings) {
-	c, ok := s.index.findByClientID(ClientID(id))
-	if !ok {
-		c, ok = s.index.findByIP(addr)
-	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
+	c, ok := s.index.findByClientID(ClientID(id))
 	if !ok {
-		foundMAC := s.dhcp.MACByIP(addr)
-		if foundMAC != nil {
-			c, ok = s.index.findByMAC(foundMAC)
-		}
+		c, ok = s.findByIP(addr)
 	}
 
 	if !ok {
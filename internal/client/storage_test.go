@@ -15,6 +15,7 @@ import (
 	"github.com/AdguardTeam/AdGuardHome/internal/dhcpd"
 	"github.com/AdguardTeam/AdGuardHome/internal/dhcpsvc"
 	"github.com/AdguardTeam/AdGuardHome/internal/dnsforward"
+	"github.com/AdguardTeam/AdGuardHome/internal/filtering"
 	"github.com/AdguardTeam/AdGuardHome/internal/whois"
 	"github.com/AdguardTeam/golibs/errors"
 	"github.com/AdguardTeam/golibs/hostsfile"
@@ -275,6 +276,96 @@ func TestStorage_Add_arp(t *testing.T) {
 	})
 }
 
+func TestStorage_Find_arpMAC(t *testing.T) {
+	var (
+		cliIP1 = netip.MustParseAddr("192.0.2.1")
+		cliIP2 = netip.MustParseAddr("192.0.2.2")
+		cliMAC = errors.Must(net.ParseMAC("aa:bb:cc:dd:ee:ff"))
+	)
+
+	arpCh := make(chan []arpdb.Neighbor, 1)
+	arpDB := &testARPDB{
+		onRefresh: func(_ context.Context) (err error) { return nil },
+		onNeighbors: func() (ns []arpdb.Neighbor) {
+			select {
+			case ns = <-arpCh:
+				return ns
+			default:
+				return nil
+			}
+		},
+	}
+
+	ctx := testutil.ContextWithTimeout(t, testTimeout)
+	storage, err := client.NewStorage(ctx, &client.StorageConfig{
+		BaseLogger:             testLogger,
+		Logger:                 testLogger,
+		DHCP:                   client.EmptyDHCP{},
+		ARPDB:                  arpDB,
+		ARPClientsUpdatePeriod: testTimeout,
+	})
+	require.NoError(t, err)
+
+	prs := &client.Persistent{
+		Name: "client_with_mac",
+		UID:  client.MustNewUID(),
+		MACs: []net.HardwareAddr{cliMAC},
+	}
+
+	err = storage.Add(ctx, prs)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name    string
+		ip      netip.Addr
+		neighIP netip.Addr
+	}{{
+		name:    "first_address",
+		ip:      cliIP1,
+		neighIP: cliIP1,
+	}, {
+		name:    "changed_address",
+		ip:      cliIP2,
+		neighIP: cliIP2,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			testutil.RequireSend(t, arpCh, []arpdb.Neighbor{{
+				IP:  tc.neighIP,
+				MAC: cliMAC,
+			}}, testTimeout)
+
+			storage.ReloadARP(testutil.ContextWithTimeout(t, testTimeout))
+
+			params := &client.FindParams{RemoteIP: tc.ip}
+			c, ok := storage.Find(params)
+			require.True(t, ok)
+
+			assert.Equal(t, prs.Name, c.Name)
+
+			setts := &filtering.Settings{}
+			storage.ApplyClientFiltering("", tc.ip, setts)
+
+			assert.Equal(t, prs.Name, setts.ClientName)
+		})
+	}
+
+	t.Run("stale_address", func(t *testing.T) {
+		_, ok := storage.Find(&client.FindParams{RemoteIP: cliIP1})
+		assert.False(t, ok)
+	})
+
+	t.Run("empty_neighborhood", func(t *testing.T) {
+		testutil.RequireSend(t, arpCh, []arpdb.Neighbor{}, testTimeout)
+
+		storage.ReloadARP(testutil.ContextWithTimeout(t, testTimeout))
+
+		_, ok := storage.Find(&client.FindParams{RemoteIP: cliIP2})
+		assert.False(t, ok)
+	})
+}
+
 func TestStorage_Add_whois(t *testing.T) {
 	var (
 		cliIP1 = netip.MustParseAddr("1.1.1.1")
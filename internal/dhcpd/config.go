@@ -33,6 +33,9 @@ type ServerConfig struct {
 	// Register an HTTP handler
 	HTTPReg aghhttp.Registrar `yaml:"-" json:"-"`
 
+	// OnLeaseEvent, if not nil, is called on the DHCPv4 lease events.
+	OnLeaseEvent LeaseEventHandler `yaml:"-" json:"-"`
+
 	Enabled       bool   `yaml:"enabled"`
 	InterfaceName string `yaml:"interface_name"`
 
@@ -149,6 +152,10 @@ type V4ServerConf struct {
 	// TODO(a.garipov): This is utter madness and must be refactored.  It just
 	// begs for deadlock bugs and other nastiness.
 	notify func(uint32)
+
+	// onLeaseEvent, if not nil, is called on the lease events.  It must be
+	// called outside of locked sections.
+	onLeaseEvent LeaseEventHandler
 }
 
 // errNilConfig is an error returned by validation method if the config is nil.
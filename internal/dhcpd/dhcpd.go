@@ -84,6 +84,10 @@ type Interface interface {
 	// due to an assumption that a DHCP client must always have an IP address.
 	IPByHost(host string) (ip netip.Addr)
 
+	// AddStaticLease adds the static lease l to the DHCPv4 or the DHCPv6
+	// server depending on its IP address.  l must not be nil.
+	AddStaticLease(l *dhcpsvc.Lease) (err error)
+
 	WriteDiskConfig(c *ServerConfig)
 }
 
@@ -114,6 +118,8 @@ func Create(ctx context.Context, conf *ServerConfig) (s *server, err error) {
 
 			HTTPReg: conf.HTTPReg,
 
+			OnLeaseEvent: conf.OnLeaseEvent,
+
 			Enabled:       conf.Enabled,
 			InterfaceName: conf.InterfaceName,
 
@@ -168,6 +174,7 @@ func (s *server) setServers(
 	v4conf.Logger = s.conf.Logger.With("ip_version", "4")
 	v4conf.InterfaceName = s.conf.InterfaceName
 	v4conf.notify = s.onNotify
+	v4conf.onLeaseEvent = conf.OnLeaseEvent
 	v4conf.Enabled = s.conf.Enabled && v4conf.RangeStart.IsValid()
 
 	s.srv4, err = v4Create(&v4conf)
@@ -310,7 +317,11 @@ func (s *server) IPByHost(host string) (ip netip.Addr) {
 	return s.srv4.IPByHost(host)
 }
 
-// AddStaticLease - add static v4 lease
-func (s *server) AddStaticLease(l *dhcpsvc.Lease) error {
-	return s.srv4.AddStaticLease(l)
+// AddStaticLease implements the [Interface] interface for *server.
+func (s *server) AddStaticLease(l *dhcpsvc.Lease) (err error) {
+	if l.IP.Is4() {
+		return s.srv4.AddStaticLease(l)
+	}
+
+	return s.srv6.AddStaticLease(l)
 }
@@ -0,0 +1,62 @@
+package dhcpd
+
+import (
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/dhcpsvc"
+)
+
+// LeaseEventType is the type of a DHCP lease event.
+type LeaseEventType string
+
+// LeaseEventType values.
+const (
+	// LeaseEventGranted means that a new dynamic lease has been granted to a
+	// client.
+	LeaseEventGranted LeaseEventType = "granted"
+
+	// LeaseEventRenewed means that a client has extended its dynamic lease
+	// before it has expired.
+	LeaseEventRenewed LeaseEventType = "renewed"
+
+	// LeaseEventExpired means that a dynamic lease hasn't been renewed in time.
+	LeaseEventExpired LeaseEventType = "expired"
+
+	// LeaseEventStaticConflict means that a static lease couldn't be added or
+	// updated, since it conflicts with an existing one.
+	LeaseEventStaticConflict LeaseEventType = "static_conflict"
+)
+
+// leaseExpiryCheckInterval is the interval of checking the dynamic leases for
+// expiration.
+const leaseExpiryCheckInterval = time.Minute
+
+// LeaseEvent is an event happened to a DHCP lease.
+type LeaseEvent struct {
+	// Lease is a clone of the lease the event is about.  It's never nil.
+	Lease *dhcpsvc.Lease
+
+	// Type is the type of the event.
+	Type LeaseEventType
+
+	// Reason describes the conflict for [LeaseEventStaticConflict].
+	Reason string
+}
+
+// LeaseEventHandler is called on every DHCP lease event.  It must not block,
+// since it's called from the DHCP packet handlers.
+type LeaseEventHandler func(ev LeaseEvent)
+
+// emitLeaseEvent calls h, if it's not nil, with an event of typ for a clone of
+// l.  It must be called outside of locked sections.
+func emitLeaseEvent(h LeaseEventHandler, typ LeaseEventType, l *dhcpsvc.Lease, reason string) {
+	if h == nil || l == nil {
+		return
+	}
+
+	h(LeaseEvent{
+		Lease:  l.Clone(),
+		Type:   typ,
+		Reason: reason,
+	})
+}
@@ -57,6 +57,10 @@ type v4Server struct {
 
 	// ipIndex is an index of leases by their IP addresses.
 	ipIndex map[netip.Addr]*dhcpsvc.Lease
+
+	// expiryDone is closed to stop checking the leases for expiration.  It's
+	// nil if the leases aren't checked.
+	expiryDone chan struct{}
 }
 
 func (s *v4Server) enabled() (ok bool) {
@@ -294,7 +298,7 @@ func (s *v4Server) rmDynamicLease(lease *dhcpsvc.Lease) (err error) {
 
 		if bytes.Equal(l.HWAddr, lease.HWAddr) || l.IP == lease.IP {
 			if isStatic {
-				return errors.Error("static lease already exists")
+				return errStaticLeaseExists
 			}
 
 			s.rmLeaseByIndex(i)
@@ -321,8 +325,20 @@ const (
 	// ErrDupIP is returned by addLease, validateStaticLease when the modified
 	// lease has a non-unique IP address.
 	ErrDupIP = errors.Error("ip address is not unique")
+
+	// errStaticLeaseExists is returned by rmDynamicLease when there is a static
+	// lease with the same properties.
+	errStaticLeaseExists = errors.Error("static lease already exists")
 )
 
+// isLeaseConflict returns true if err means that the lease conflicts with an
+// existing one.
+func isLeaseConflict(err error) (ok bool) {
+	return errors.Is(err, ErrDupHostname) ||
+		errors.Is(err, ErrDupIP) ||
+		errors.Is(err, errStaticLeaseExists)
+}
+
 // addLease adds a dynamic or static lease.
 func (s *v4Server) addLease(l *dhcpsvc.Lease) (err error) {
 	r := s.conf.ipRange
@@ -428,6 +444,10 @@ func (s *v4Server) AddStaticLease(l *dhcpsvc.Lease) (err error) {
 
 	err = s.updateStaticLease(l)
 	if err != nil {
+		if isLeaseConflict(err) {
+			emitLeaseEvent(s.conf.onLeaseEvent, LeaseEventStaticConflict, l, err.Error())
+		}
+
 		// Don't wrap the error, because it's informative enough as is.
 		return err
 	}
@@ -442,6 +462,10 @@ func (s *v4Server) AddStaticLease(l *dhcpsvc.Lease) (err error) {
 func (s *v4Server) UpdateStaticLease(l *dhcpsvc.Lease) (err error) {
 	defer func() {
 		if err != nil {
+			if isLeaseConflict(err) {
+				emitLeaseEvent(s.conf.onLeaseEvent, LeaseEventStaticConflict, l, err.Error())
+			}
+
 			err = errors.Annotate(err, "dhcpv4: updating static lease: %w")
 
 			return
@@ -968,9 +992,14 @@ func (s *v4Server) handleRequest(req, resp *dhcpv4.DHCPv4) (lease *dhcpsvc.Lease
 	hostname := req.HostName()
 	isRequested := hostname != "" || req.ParameterRequestList().Has(dhcpv4.OptionHostName)
 
+	var evType LeaseEventType
 	defer func() {
 		s.conf.notify(LeaseChangedAdded)
 		s.conf.notify(LeaseChangedDBStore)
+
+		if evType != "" {
+			emitLeaseEvent(s.conf.onLeaseEvent, evType, lease, "")
+		}
 	}()
 
 	s.leasesLock.Lock()
@@ -987,6 +1016,11 @@ func (s *v4Server) handleRequest(req, resp *dhcpv4.DHCPv4) (lease *dhcpsvc.Lease
 		return lease, needsReply
 	}
 
+	evType = LeaseEventGranted
+	if lease.Expiry.After(time.Now()) {
+		evType = LeaseEventRenewed
+	}
+
 	s.commitLease(lease, hostname)
 
 	if isRequested {
@@ -1364,9 +1398,51 @@ func (s *v4Server) Start(ctx context.Context) (err error) {
 	// it should reload the DHCP clients.
 	s.conf.notify(LeaseChangedAdded)
 
+	if s.conf.onLeaseEvent != nil {
+		s.expiryDone = make(chan struct{})
+		go s.checkExpiry(s.expiryDone)
+	}
+
 	return nil
 }
 
+// checkExpiry reports the dynamic leases expired since the previous check
+// until done is closed.  It's intended to be used as a goroutine.
+func (s *v4Server) checkExpiry(done <-chan struct{}) {
+	defer log.OnPanic("dhcpv4: checking lease expiry")
+
+	ticker := time.NewTicker(leaseExpiryCheckInterval)
+	defer ticker.Stop()
+
+	prev := time.Now()
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			for _, l := range s.expiredSince(prev, now) {
+				emitLeaseEvent(s.conf.onLeaseEvent, LeaseEventExpired, l, "")
+			}
+
+			prev = now
+		}
+	}
+}
+
+// expiredSince returns the dynamic leases expired within (since, now].
+func (s *v4Server) expiredSince(since, now time.Time) (expired []*dhcpsvc.Lease) {
+	s.leasesLock.Lock()
+	defer s.leasesLock.Unlock()
+
+	for _, l := range s.leases {
+		if !l.IsStatic && l.Expiry.After(since) && !l.Expiry.After(now) {
+			expired = append(expired, l.Clone())
+		}
+	}
+
+	return expired
+}
+
 // configureDNSIPAddrs updates v4Server configuration with provided slice of
 // dns IP addresses.
 func (s *v4Server) configureDNSIPAddrs(dnsIPAddrs []net.IP) {
@@ -1396,6 +1472,11 @@ func (s *v4Server) Stop() (err error) {
 	}
 
 	log.Debug("dhcpv4: stopping")
+	if s.expiryDone != nil {
+		close(s.expiryDone)
+		s.expiryDone = nil
+	}
+
 	err = s.srv.Close()
 	if err != nil {
 		return fmt.Errorf("closing dhcpv4 srv: %w", err)
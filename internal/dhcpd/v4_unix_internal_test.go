@@ -914,3 +914,59 @@ func TestV4Server_handleRelease(t *testing.T) {
 
 	require.Equal(t, wantResp, resp)
 }
+
+func TestV4Server_leaseEvents(t *testing.T) {
+	var evs []LeaseEvent
+
+	conf := defaultV4ServerConf()
+	conf.onLeaseEvent = func(ev LeaseEvent) {
+		evs = append(evs, ev)
+	}
+
+	s, err := v4Create(conf)
+	require.NoError(t, err)
+
+	ip := netip.MustParseAddr("192.168.10.10")
+	err = s.AddStaticLease(&dhcpsvc.Lease{
+		Hostname: "static-client",
+		HWAddr:   net.HardwareAddr{0xAA, 0xAA, 0xAA, 0xAA, 0xAA, 0xAA},
+		IP:       ip,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, evs)
+
+	err = s.AddStaticLease(&dhcpsvc.Lease{
+		Hostname: "another-client",
+		HWAddr:   net.HardwareAddr{0xBB, 0xBB, 0xBB, 0xBB, 0xBB, 0xBB},
+		IP:       ip,
+	})
+	testutil.AssertErrorMsg(
+		t,
+		"dhcpv4: adding static lease: removing dynamic leases for 192.168.10.10 "+
+			"(bb:bb:bb:bb:bb:bb): static lease already exists",
+		err,
+	)
+
+	require.Len(t, evs, 1)
+
+	assert.Equal(t, LeaseEventStaticConflict, evs[0].Type)
+	assert.Equal(t, ip, evs[0].Lease.IP)
+	assert.NotEmpty(t, evs[0].Reason)
+
+	now := time.Now()
+	dynamic := &dhcpsvc.Lease{
+		Expiry:   now.Add(-time.Second),
+		Hostname: "dynamic-client",
+		HWAddr:   net.HardwareAddr{0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC},
+		IP:       DefaultRangeStart,
+	}
+
+	err = s.addLease(dynamic)
+	require.NoError(t, err)
+
+	expired := s.expiredSince(now.Add(-time.Minute), now)
+	require.Len(t, expired, 1)
+
+	assert.Equal(t, dynamic.IP, expired[0].IP)
+	assert.Empty(t, s.expiredSince(now, now.Add(time.Minute)))
+}
@@ -195,6 +195,9 @@ type TLSConfig struct {
 	// encryption is disabled.
 	Cert *tls.Certificate
 
+	// OCSPStapler staples the OCSP responses to Cert.  It may be nil.
+	OCSPStapler *aghtls.OCSPStapler
+
 	// TLSListenAddrs are the addresses to listen on for DoT connections.  Each
 	// item in the list must be non-nil if Cert is not nil.
 	TLSListenAddrs []*net.TCPAddr
@@ -804,7 +807,7 @@ func (s *Server) onGetCertificate(ch *tls.ClientHelloInfo) (*tls.Certificate, er
 		return nil, fmt.Errorf("invalid SNI")
 	}
 
-	return s.conf.TLSConf.Cert, nil
+	return s.conf.TLSConf.OCSPStapler.Staple(s.conf.TLSConf.Cert), nil
 }
 
 // preparePlain prepares the plain-DNS configuration for the DNS proxy. The
@@ -110,6 +110,12 @@ type Server struct {
 	// etcHosts contains the current data from the system's hosts files.
 	etcHosts upstream.Resolver
 
+	// hook, if not nil, is called for each request and response.
+	hook Hook
+
+	// metrics collects the metrics of the queries.  It is never nil.
+	metrics *Metrics
+
 	// privateNets is the configured set of IP networks considered private.
 	privateNets netutil.SubnetSet
 
@@ -207,6 +213,9 @@ type DNSCreateParams struct {
 	Anonymizer  *aghnet.IPMut
 	EtcHosts    *aghnet.HostsContainer
 
+	// Hook, if not nil, is called for each request and response.
+	Hook Hook
+
 	// Logger is used as a base logger.  It must not be nil.
 	Logger *slog.Logger
 
@@ -251,6 +260,8 @@ func NewServer(p DNSCreateParams) (s *Server, err error) {
 		// TODO(e.burkov):  Use some case-insensitive string comparison.
 		localDomainSuffix: strings.ToLower(localDomainSuffix),
 		etcHosts:          etcHosts,
+		hook:              p.Hook,
+		metrics:           NewMetrics(),
 		anonymizer:        p.Anonymizer,
 		conf: ServerConfig{
 			ServePlainDNS: true,
@@ -336,6 +347,11 @@ func (s *Server) UpstreamTimeout() (t time.Duration) {
 	return s.conf.UpstreamTimeout
 }
 
+// Metrics returns the metrics of the DNS queries.
+func (s *Server) Metrics() (m *Metrics) {
+	return s.metrics
+}
+
 // Resolve gets IP addresses by host name from an upstream server.  No
 // request/response filtering is performed.  Query log and Stats are not
 // updated.  This method may be called before [Server.Start].
@@ -0,0 +1,110 @@
+package dnsforward
+
+import (
+	"context"
+	"log/slog"
+	"net/netip"
+
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+	"github.com/miekg/dns"
+)
+
+// Hook is the interface for the external handlers of DNS requests and
+// responses, such as plugins.  All methods must be safe for concurrent use.
+// The errors are logged and otherwise ignored, so that a broken hook doesn't
+// break the resolving, but the returned responses are used even if the errors
+// aren't nil.
+type Hook interface {
+	// HandleRequest is called for each request before it's filtered.  If resp
+	// isn't nil, it's used as the response to q.Request.  q.Request must not be
+	// modified.
+	HandleRequest(ctx context.Context, q *HookQuery) (resp *dns.Msg, err error)
+
+	// HandleResponse is called for each response after it's filtered.  If
+	// resp isn't nil, it replaces q.Response.  q.Request and q.Response must
+	// not be modified.
+	HandleResponse(ctx context.Context, q *HookQuery) (resp *dns.Msg, err error)
+}
+
+// HookQuery is the data of a DNS query passed to a [Hook].
+type HookQuery struct {
+	// Request is the DNS request.  It must not be nil.
+	Request *dns.Msg
+
+	// Response is the DNS response.  It's only set for
+	// [Hook.HandleResponse].
+	Response *dns.Msg
+
+	// ClientIP is the IP address of the client.
+	ClientIP netip.Addr
+
+	// ClientID is the ClientID from DoH, DoQ, or DoT, if any.
+	ClientID string
+}
+
+// processHookRequest passes the request to the hook, if any, and sets the
+// response returned by it.  l and dctx must not be nil.
+func (s *Server) processHookRequest(
+	ctx context.Context,
+	l *slog.Logger,
+	dctx *dnsContext,
+) (rc resultCode) {
+	pctx := dctx.proxyCtx
+	if s.hook == nil || pctx.Res != nil {
+		return resultCodeSuccess
+	}
+
+	l.DebugContext(ctx, "started processing hook request")
+	defer l.DebugContext(ctx, "finished processing hook request")
+
+	resp, err := s.hook.HandleRequest(ctx, &HookQuery{
+		Request:  pctx.Req,
+		ClientIP: pctx.Addr.Addr(),
+		ClientID: dctx.clientID,
+	})
+	if err != nil {
+		l.ErrorContext(ctx, "hook request", slogutil.KeyError, err)
+	}
+
+	if resp != nil {
+		pctx.Res = resp
+	}
+
+	return resultCodeSuccess
+}
+
+// processHookResponse passes the response to the hook, if any, and replaces it
+// with the one returned by the hook.  l and dctx must not be nil.
+func (s *Server) processHookResponse(
+	ctx context.Context,
+	l *slog.Logger,
+	dctx *dnsContext,
+) (rc resultCode) {
+	pctx := dctx.proxyCtx
+	if s.hook == nil || pctx.Res == nil {
+		return resultCodeSuccess
+	}
+
+	l.DebugContext(ctx, "started processing hook response")
+	defer l.DebugContext(ctx, "finished processing hook response")
+
+	resp, err := s.hook.HandleResponse(ctx, &HookQuery{
+		Request:  pctx.Req,
+		Response: pctx.Res,
+		ClientIP: pctx.Addr.Addr(),
+		ClientID: dctx.clientID,
+	})
+	if err != nil {
+		l.ErrorContext(ctx, "hook response", slogutil.KeyError, err)
+	}
+
+	if resp != nil {
+		if dctx.origResp == nil {
+			dctx.origResp = pctx.Res
+		}
+
+		pctx.Res = resp
+	}
+
+	return resultCodeSuccess
+}
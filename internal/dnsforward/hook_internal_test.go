@@ -0,0 +1,141 @@
+package dnsforward
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AdguardTeam/dnsproxy/proxy"
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testHook is a [Hook] for tests.
+type testHook struct {
+	onHandleRequest  func(ctx context.Context, q *HookQuery) (resp *dns.Msg, err error)
+	onHandleResponse func(ctx context.Context, q *HookQuery) (resp *dns.Msg, err error)
+}
+
+// type check
+var _ Hook = (*testHook)(nil)
+
+// HandleRequest implements the [Hook] interface for *testHook.
+func (h *testHook) HandleRequest(ctx context.Context, q *HookQuery) (resp *dns.Msg, err error) {
+	return h.onHandleRequest(ctx, q)
+}
+
+// HandleResponse implements the [Hook] interface for *testHook.
+func (h *testHook) HandleResponse(ctx context.Context, q *HookQuery) (resp *dns.Msg, err error) {
+	return h.onHandleResponse(ctx, q)
+}
+
+func TestServer_processHookRequest(t *testing.T) {
+	t.Parallel()
+
+	const (
+		hookedFQDN = "hooked.example."
+		brokenFQDN = "broken.example."
+	)
+
+	s := &Server{
+		hook: &testHook{
+			onHandleRequest: func(_ context.Context, q *HookQuery) (resp *dns.Msg, err error) {
+				assert.Equal(t, testClientAddrPort.Addr(), q.ClientIP)
+				assert.Equal(t, "cli", q.ClientID)
+
+				switch q.Request.Question[0].Name {
+				case hookedFQDN:
+					return (&dns.Msg{}).SetRcode(q.Request, dns.RcodeRefused), nil
+				case brokenFQDN:
+					return nil, errors.Error("test error")
+				default:
+					return nil, nil
+				}
+			},
+		},
+	}
+
+	testCases := []struct {
+		name      string
+		fqdn      string
+		wantRCode int
+		wantRes   bool
+	}{{
+		name:      "hooked",
+		fqdn:      hookedFQDN,
+		wantRCode: dns.RcodeRefused,
+		wantRes:   true,
+	}, {
+		name:    "broken",
+		fqdn:    brokenFQDN,
+		wantRes: false,
+	}, {
+		name:    "not_hooked",
+		fqdn:    "other.example.",
+		wantRes: false,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			dctx := &dnsContext{
+				proxyCtx: &proxy.DNSContext{
+					Req:  createTestMessage(tc.fqdn),
+					Addr: testClientAddrPort,
+				},
+				clientID: "cli",
+			}
+
+			ctx := testutil.ContextWithTimeout(t, testTimeout)
+			rc := s.processHookRequest(ctx, testLogger, dctx)
+			require.Equal(t, resultCodeSuccess, rc)
+
+			res := dctx.proxyCtx.Res
+			if !tc.wantRes {
+				assert.Nil(t, res)
+
+				return
+			}
+
+			require.NotNil(t, res)
+
+			assert.Equal(t, tc.wantRCode, res.Rcode)
+		})
+	}
+}
+
+func TestServer_processHookResponse(t *testing.T) {
+	t.Parallel()
+
+	req := createTestMessage("hooked.example.")
+	resp := (&dns.Msg{}).SetReply(req)
+	modified := (&dns.Msg{}).SetRcode(req, dns.RcodeNameError)
+
+	s := &Server{
+		hook: &testHook{
+			onHandleResponse: func(_ context.Context, q *HookQuery) (res *dns.Msg, err error) {
+				assert.Same(t, resp, q.Response)
+
+				return modified, nil
+			},
+		},
+	}
+
+	dctx := &dnsContext{
+		proxyCtx: &proxy.DNSContext{
+			Req:  req,
+			Res:  resp,
+			Addr: testClientAddrPort,
+		},
+	}
+
+	ctx := testutil.ContextWithTimeout(t, testTimeout)
+	rc := s.processHookResponse(ctx, testLogger, dctx)
+	require.Equal(t, resultCodeSuccess, rc)
+
+	assert.Same(t, modified, dctx.proxyCtx.Res)
+	assert.Same(t, resp, dctx.origResp)
+}
@@ -338,10 +338,17 @@ func (req *jsonDNSConfig) checkBootstrap() (err error) {
 		return nil
 	}
 
+	// Don't wrap the error because it's informative enough as is.
+	return ValidateBootstraps(*req.Bootstraps)
+}
+
+// ValidateBootstraps returns an error if any of the bootstrap DNS server
+// addresses is invalid.
+func ValidateBootstraps(bootstraps []string) (err error) {
 	var b string
 	defer func() { err = errors.Annotate(err, "checking bootstrap %s: %w", b) }()
 
-	for _, b = range *req.Bootstraps {
+	for _, b = range bootstraps {
 		if b == "" {
 			return errors.Error("empty")
 		}
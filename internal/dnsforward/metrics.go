@@ -0,0 +1,415 @@
+package dnsforward
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/filtering"
+	"github.com/AdguardTeam/dnsproxy/proxy"
+	"github.com/miekg/dns"
+)
+
+// metricBuckets are the upper bounds of the buckets of the duration histograms
+// in seconds.  These are the default buckets of the Prometheus client
+// libraries with the smaller ones added, since most of the DNS queries are
+// answered within milliseconds.
+var metricBuckets = []float64{
+	0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10,
+}
+
+// Query statuses of the metrics.
+const (
+	metricStatusBlocked      = "blocked"
+	metricStatusParental     = "parental"
+	metricStatusProcessed    = "processed"
+	metricStatusRewritten    = "rewritten"
+	metricStatusSafeBrowsing = "safebrowsing"
+	metricStatusSafeSearch   = "safesearch"
+)
+
+// metricTypeOther is the type label of the queries with the types unknown to
+// [dns.TypeToString].
+const metricTypeOther = "OTHER"
+
+// Metric names.
+const (
+	metricQueriesTotal      = "adguardhome_dns_queries_total"
+	metricResponsesTotal    = "adguardhome_dns_responses_total"
+	metricCacheHitsTotal    = "adguardhome_dns_cache_hits_total"
+	metricCacheMissesTotal  = "adguardhome_dns_cache_misses_total"
+	metricQueryDuration     = "adguardhome_dns_query_duration_seconds"
+	metricFilteringDuration = "adguardhome_dns_filtering_duration_seconds"
+	metricUpstreamDuration  = "adguardhome_dns_upstream_duration_seconds"
+	metricUpstreamErrors    = "adguardhome_dns_upstream_errors_total"
+)
+
+// Metrics collects the metrics of the DNS queries.  It's safe for concurrent
+// use.
+type Metrics struct {
+	// mu protects all fields below.
+	mu *sync.Mutex
+
+	// queries are the numbers of the queries by the type and the status.
+	queries map[queryMetricKey]uint64
+
+	// rcodes are the numbers of the responses by the response code.
+	rcodes map[string]uint64
+
+	// upstreams are the statistics of the upstreams by the address.
+	upstreams map[string]*upstreamMetrics
+
+	// processing is the histogram of the durations of the processing of the
+	// queries.
+	processing *histogram
+
+	// filtering is the histogram of the durations of the filtering of the
+	// queries and the responses.
+	filtering *histogram
+
+	// cacheHits is the number of the queries answered from the cache.
+	cacheHits uint64
+
+	// cacheMisses is the number of the queries sent to the upstreams.
+	cacheMisses uint64
+}
+
+// queryMetricKey is the key of the number of the queries.
+type queryMetricKey struct {
+	qtype  string
+	status string
+}
+
+// upstreamMetrics are the statistics of an upstream.
+type upstreamMetrics struct {
+	// durations is the histogram of the durations of the successful lookups.
+	durations *histogram
+
+	// errors is the number of the failed lookups.
+	errors uint64
+}
+
+// NewMetrics returns a new properly initialized *Metrics.
+func NewMetrics() (m *Metrics) {
+	return &Metrics{
+		mu:         &sync.Mutex{},
+		queries:    map[queryMetricKey]uint64{},
+		rcodes:     map[string]uint64{},
+		upstreams:  map[string]*upstreamMetrics{},
+		processing: newHistogram(),
+		filtering:  newHistogram(),
+	}
+}
+
+// observe records the processed query.  m may be nil.  dctx must not be nil.
+func (m *Metrics) observe(dctx *dnsContext, processingTime time.Duration) {
+	if m == nil {
+		return
+	}
+
+	pctx := dctx.proxyCtx
+	key := queryMetricKey{
+		qtype:  metricType(pctx.Req.Question[0].Qtype),
+		status: metricStatus(dctx.result),
+	}
+
+	var rcode string
+	if pctx.Res != nil {
+		rcode = dns.RcodeToString[pctx.Res.Rcode]
+	}
+
+	var upstreamStats []*proxy.UpstreamStatistics
+	if qs := pctx.QueryStatistics(); qs != nil {
+		upstreamStats = append(upstreamStats, qs.Main()...)
+		upstreamStats = append(upstreamStats, qs.Fallback()...)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.queries[key]++
+	if rcode != "" {
+		m.rcodes[rcode]++
+	}
+
+	m.processing.observe(processingTime)
+	m.filtering.observe(dctx.filteringDuration)
+
+	if len(upstreamStats) == 1 && upstreamStats[0].IsCached {
+		m.cacheHits++
+
+		return
+	} else if len(upstreamStats) > 0 {
+		m.cacheMisses++
+	}
+
+	for _, us := range upstreamStats {
+		m.observeUpstream(us)
+	}
+}
+
+// observeUpstream records the lookup of an upstream.  m.mu must be locked.
+func (m *Metrics) observeUpstream(us *proxy.UpstreamStatistics) {
+	addr := metricUpstream(us.Address)
+	um := m.upstreams[addr]
+	if um == nil {
+		um = &upstreamMetrics{
+			durations: newHistogram(),
+		}
+		m.upstreams[addr] = um
+	}
+
+	if us.Error != nil {
+		um.errors++
+	} else {
+		um.durations.observe(us.QueryDuration)
+	}
+}
+
+// metricUpstream returns the upstream label of the upstream address addr with
+// the user information, the query, and the fragment removed, since these may
+// contain the credentials, like the ones of the DNS-over-HTTPS upstreams.
+func metricUpstream(addr string) (label string) {
+	scheme, rest, ok := strings.Cut(addr, "://")
+	if !ok {
+		return addr
+	}
+
+	if i := strings.IndexAny(rest, "?#"); i >= 0 {
+		rest = rest[:i]
+	}
+
+	authority, path, hasPath := strings.Cut(rest, "/")
+	if i := strings.LastIndexByte(authority, '@'); i >= 0 {
+		authority = authority[i+1:]
+	}
+
+	label = scheme + "://" + authority
+	if hasPath {
+		label += "/" + path
+	}
+
+	return label
+}
+
+// metricType returns the type label of the query type qt.
+func metricType(qt uint16) (label string) {
+	label, ok := dns.TypeToString[qt]
+	if !ok {
+		return metricTypeOther
+	}
+
+	return label
+}
+
+// metricStatus returns the status label of the filtering result res.
+func metricStatus(res *filtering.Result) (label string) {
+	if res == nil {
+		return metricStatusProcessed
+	}
+
+	switch res.Reason {
+	case filtering.FilteredSafeBrowsing:
+		return metricStatusSafeBrowsing
+	case filtering.FilteredParental:
+		return metricStatusParental
+	case filtering.FilteredSafeSearch:
+		return metricStatusSafeSearch
+	case
+		filtering.FilteredBlockList,
+		filtering.FilteredInvalid,
+		filtering.FilteredBlockedService:
+		return metricStatusBlocked
+	case
+		filtering.Rewritten,
+		filtering.RewrittenAutoHosts,
+		filtering.RewrittenRule:
+		return metricStatusRewritten
+	default:
+		return metricStatusProcessed
+	}
+}
+
+// WritePrometheus writes the metrics to w in the Prometheus text exposition
+// format.  m may be nil, in which case nothing is written.
+func (m *Metrics) WritePrometheus(w io.Writer) (err error) {
+	if m == nil {
+		return nil
+	}
+
+	// Render the metrics while holding the lock and write them after releasing
+	// it, so that a slow client doesn't block the processing of the queries.
+	buf := &bytes.Buffer{}
+	func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		m.render(buf)
+	}()
+
+	_, err = buf.WriteTo(w)
+
+	// Don't wrap the error since it's informative enough as is.
+	return err
+}
+
+// render writes the metrics to buf.  m.mu must be locked.
+func (m *Metrics) render(buf *bytes.Buffer) {
+	writeMetricHeader(
+		buf,
+		metricQueriesTotal,
+		"counter",
+		"The number of the DNS queries by type and status.",
+	)
+	keys := slices.SortedFunc(maps.Keys(m.queries), func(a, b queryMetricKey) (res int) {
+		return cmp.Or(strings.Compare(a.qtype, b.qtype), strings.Compare(a.status, b.status))
+	})
+	for _, k := range keys {
+		_, _ = fmt.Fprintf(
+			buf,
+			"%s{type=%q,status=%q} %d\n",
+			metricQueriesTotal,
+			k.qtype,
+			k.status,
+			m.queries[k],
+		)
+	}
+
+	writeMetricHeader(
+		buf,
+		metricResponsesTotal,
+		"counter",
+		"The number of the DNS responses by code.",
+	)
+	for _, rcode := range slices.Sorted(maps.Keys(m.rcodes)) {
+		_, _ = fmt.Fprintf(buf, "%s{rcode=%q} %d\n", metricResponsesTotal, rcode, m.rcodes[rcode])
+	}
+
+	writeMetricHeader(buf, metricCacheHitsTotal, "counter", "The number of the DNS cache hits.")
+	_, _ = fmt.Fprintf(buf, "%s %d\n", metricCacheHitsTotal, m.cacheHits)
+
+	writeMetricHeader(buf, metricCacheMissesTotal, "counter", "The number of the DNS cache misses.")
+	_, _ = fmt.Fprintf(buf, "%s %d\n", metricCacheMissesTotal, m.cacheMisses)
+
+	writeMetricHeader(buf, metricQueryDuration, "histogram", "The duration of the DNS queries.")
+	m.processing.write(buf, metricQueryDuration, "")
+
+	writeMetricHeader(
+		buf,
+		metricFilteringDuration,
+		"histogram",
+		"The duration of the filtering of the DNS queries.",
+	)
+	m.filtering.write(buf, metricFilteringDuration, "")
+
+	m.writeUpstreams(buf)
+}
+
+// writeUpstreams writes the metrics of the upstreams to buf.  m.mu must be
+// locked.
+func (m *Metrics) writeUpstreams(buf *bytes.Buffer) {
+	addrs := slices.Sorted(maps.Keys(m.upstreams))
+
+	writeMetricHeader(
+		buf,
+		metricUpstreamDuration,
+		"histogram",
+		"The duration of the successful lookups by upstream.",
+	)
+	for _, addr := range addrs {
+		labels := fmt.Sprintf(`upstream="%s"`, escapeLabel(addr))
+		m.upstreams[addr].durations.write(buf, metricUpstreamDuration, labels)
+	}
+
+	writeMetricHeader(
+		buf,
+		metricUpstreamErrors,
+		"counter",
+		"The number of the failed lookups by upstream.",
+	)
+	for _, addr := range addrs {
+		_, _ = fmt.Fprintf(
+			buf,
+			"%s{upstream=\"%s\"} %d\n",
+			metricUpstreamErrors,
+			escapeLabel(addr),
+			m.upstreams[addr].errors,
+		)
+	}
+}
+
+// labelEscaper escapes the values of the labels in the Prometheus text
+// exposition format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel returns the escaped label value v.
+func escapeLabel(v string) (escaped string) {
+	return labelEscaper.Replace(v)
+}
+
+// writeMetricHeader writes the help and the type lines of the metric to buf.
+func writeMetricHeader(buf *bytes.Buffer, name, typ, help string) {
+	_, _ = fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// histogram is a Prometheus histogram with [metricBuckets].
+type histogram struct {
+	// buckets are the numbers of the observations by the bucket of
+	// [metricBuckets], which they fall into.  The last one is for the
+	// observations exceeding all the buckets.
+	buckets []uint64
+
+	// sum is the total of the observations in seconds.
+	sum float64
+
+	// count is the number of the observations.
+	count uint64
+}
+
+// newHistogram returns a new properly initialized *histogram.
+func newHistogram() (h *histogram) {
+	return &histogram{
+		buckets: make([]uint64, len(metricBuckets)+1),
+	}
+}
+
+// observe records d.
+func (h *histogram) observe(d time.Duration) {
+	secs := d.Seconds()
+	bucket, _ := slices.BinarySearch(metricBuckets, secs)
+
+	h.buckets[bucket]++
+	h.sum += secs
+	h.count++
+}
+
+// write writes the samples of h to buf.  labels are the comma-separated labels
+// of the samples, if any.
+func (h *histogram) write(buf *bytes.Buffer, name, labels string) {
+	sep, braced := "", ""
+	if labels != "" {
+		sep, braced = ",", "{"+labels+"}"
+	}
+
+	var cumulative uint64
+	for i, n := range h.buckets {
+		cumulative += n
+
+		le := "+Inf"
+		if i < len(metricBuckets) {
+			le = strconv.FormatFloat(metricBuckets[i], 'g', -1, 64)
+		}
+
+		_, _ = fmt.Fprintf(buf, "%s_bucket{%s%sle=%q} %d\n", name, labels, sep, le, cumulative)
+	}
+
+	_, _ = fmt.Fprintf(buf, "%s_sum%s %g\n", name, braced, h.sum)
+	_, _ = fmt.Fprintf(buf, "%s_count%s %d\n", name, braced, h.count)
+}
@@ -39,6 +39,10 @@ type dnsContext struct {
 	// startTime is the time at which the processing of the request has started.
 	startTime time.Time
 
+	// filteringDuration is the total time spent on the filtering of the
+	// request and the response.
+	filteringDuration time.Duration
+
 	// origQuestion is the question received from the client.  It is set
 	// when the request is modified by rewrites.
 	origQuestion dns.Question
@@ -417,6 +421,9 @@ func (s *Server) processFilteringBeforeRequest(
 	s.serverLock.RLock()
 	defer s.serverLock.RUnlock()
 
+	start := time.Now()
+	defer func() { dctx.filteringDuration += time.Since(start) }()
+
 	var err error
 	if dctx.result, err = s.filterDNSRequest(ctx, l, dctx); err != nil {
 		dctx.err = err
@@ -588,7 +595,9 @@ func (s *Server) filterAfterResponse(
 		return resultCodeSuccess
 	}
 
+	start := time.Now()
 	err := s.filterDNSResponse(ctx, l, dctx)
+	dctx.filteringDuration += time.Since(start)
 	if err != nil {
 		dctx.err = err
 
@@ -35,9 +35,11 @@ func (s *Server) ServeDNS(ctx context.Context, _ *proxy.Proxy, pctx *proxy.DNSCo
 		s.processDDRQuery,
 		s.processDHCPHosts,
 		s.processDHCPAddrs,
+		s.processHookRequest,
 		s.processFilteringBeforeRequest,
 		s.processUpstream,
 		s.processFilteringAfterResponse,
+		s.processHookResponse,
 		s.ipset.process,
 		s.processQueryLogsAndStats,
 	}
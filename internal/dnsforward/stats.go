@@ -29,6 +29,8 @@ func (s *Server) processQueryLogsAndStats(
 	host := aghnet.NormalizeDomain(q.Name)
 	processingTime := time.Since(dctx.startTime)
 
+	s.metrics.observe(dctx, processingTime)
+
 	ip := pctx.Addr.Addr().AsSlice()
 	s.anonymizer.Load()(ip)
 	ipStr := net.IP(ip).String()
@@ -162,6 +164,7 @@ func (s *Server) updateStats(dctx *dnsContext, clientIP string, processingTime t
 		ProcessingTime: processingTime,
 		IsEncrypted:    isEncrypted,
 		DNSSEC:         dctx.responseAD,
+		ServFail:       pctx.Res != nil && pctx.Res.Rcode == dns.RcodeServerFailure,
 	}
 
 	if clientID := dctx.clientID; clientID != "" {
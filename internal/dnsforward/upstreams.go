@@ -8,8 +8,11 @@ import (
 	"time"
 
 	"github.com/AdguardTeam/AdGuardHome/internal/aghnet"
+	"github.com/AdguardTeam/AdGuardHome/internal/aghslog"
 	"github.com/AdguardTeam/dnsproxy/proxy"
 	"github.com/AdguardTeam/dnsproxy/upstream"
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
 	"github.com/AdguardTeam/golibs/netutil"
 	"github.com/AdguardTeam/golibs/stringutil"
 )
@@ -53,6 +56,101 @@ func newBootstrap(
 	return r, boots, nil
 }
 
+// ValidateUpstreams returns an error if any of the upstream configuration
+// lines is invalid.  It doesn't check the availability of the upstreams.
+func ValidateUpstreams(upstreams []string) (err error) {
+	uc, err := proxy.ParseUpstreamsConfig(upstreams, &upstream.Options{
+		Logger: slogutil.NewDiscardLogger(),
+	})
+
+	// Don't wrap the error because it's informative enough as is.
+	return errors.WithDeferred(err, uc.Close())
+}
+
+// ErrUpstreamsFile is returned when the upstreams are added to the server,
+// which reads them from a file.
+const ErrUpstreamsFile errors.Error = "upstreams are set by upstream_dns_file"
+
+// AddUpstreams adds the upstream configuration lines, which s doesn't have
+// yet, saves the configuration, and restarts s.  It returns the number of the
+// added lines.
+func (s *Server) AddUpstreams(ctx context.Context, upstreams []string) (added int, err error) {
+	err = ValidateUpstreams(upstreams)
+	if err != nil {
+		// Don't wrap the error because it's informative enough as is.
+		return 0, err
+	}
+
+	added, err = s.addUpstreams(upstreams)
+	if err != nil || added == 0 {
+		return 0, err
+	}
+
+	s.conf.ConfModifier.Apply(ctx)
+
+	err = s.Reconfigure(ctx, nil)
+	if err != nil {
+		return added, fmt.Errorf("reconfiguring: %w", err)
+	}
+
+	return added, nil
+}
+
+// addUpstreams adds the upstream configuration lines, which s doesn't have
+// yet, and returns the number of the added lines.
+func (s *Server) addUpstreams(upstreams []string) (added int, err error) {
+	s.serverLock.Lock()
+	defer s.serverLock.Unlock()
+
+	if s.conf.UpstreamDNSFileName != "" {
+		return 0, ErrUpstreamsFile
+	}
+
+	for _, u := range upstreams {
+		if !slices.Contains(s.conf.UpstreamDNS, u) {
+			s.conf.UpstreamDNS = append(s.conf.UpstreamDNS, u)
+			added++
+		}
+	}
+
+	return added, nil
+}
+
+// CheckUpstreams checks the availability of the configured general, fallback,
+// and private upstreams.  results are the same as the ones of the POST
+// /control/test_upstream_dns HTTP API, the statuses by the upstream addresses.
+func (s *Server) CheckUpstreams(ctx context.Context) (results map[string]string, err error) {
+	s.serverLock.RLock()
+	upstreams, err := s.conf.loadUpstreams(ctx, s.logger)
+	bootstraps := stringutil.FilterOut(s.conf.BootstrapDNS, aghnet.IsCommentOrEmpty)
+	fallbacks := slices.Clone(s.conf.FallbackDNS)
+	private := slices.Clone(s.conf.LocalPTRResolvers)
+	opts := &upstream.Options{
+		Logger:     aghslog.NewForUpstream(s.baseLogger, aghslog.UpstreamTypeTest),
+		Timeout:    s.conf.UpstreamTimeout,
+		PreferIPv6: s.conf.BootstrapPreferIPv6,
+	}
+	s.serverLock.RUnlock()
+
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return nil, err
+	}
+
+	var boots []*upstream.UpstreamResolver
+	opts.Bootstrap, boots, err = newBootstrap(bootstraps, s.etcHosts, opts)
+	if err != nil {
+		return nil, fmt.Errorf("parsing bootstrap servers: %w", err)
+	}
+	defer closeBoots(ctx, s.logger, boots)
+
+	cv := newUpstreamConfigValidator(ctx, upstreams, fallbacks, private, opts)
+	cv.check(ctx, s.logger)
+	cv.close()
+
+	return cv.status(ctx, s.logger), nil
+}
+
 // newUpstreamConfig returns the upstream configuration based on upstreams.  If
 // upstreams slice specifies no default upstreams, defaultUpstreams are used to
 // create upstreams with no domain specifications.  opts are used when creating
@@ -46,6 +46,28 @@ type ListUpdateEvent struct {
 	Type         ListType
 }
 
+// ListUpdateProgressEvent describes the progress of a refresh of the filter
+// lists of a single type.  It's reported after each list is processed.
+type ListUpdateProgressEvent struct {
+	// Err is the error of the update of the list, if any.
+	Err error
+
+	Name string
+	URL  string
+	ID   uint64
+
+	// Done is the number of the lists processed so far, including this one.
+	Done int
+
+	// Total is the number of the lists being refreshed.
+	Total int
+
+	Type ListType
+
+	// Updated is true if the contents of the list have changed.
+	Updated bool
+}
+
 // FilterYAML represents a filter list in the configuration file.
 //
 // TODO(e.burkov):  Investigate if the field ordering is important.
@@ -338,6 +360,11 @@ func (d *DNSFilter) refreshFiltersArray(
 		return 0, nil, nil, false
 	}
 
+	white := filters == &d.conf.WhitelistFilters
+	for i := range updateFilters {
+		updateFilters[i].white = white
+	}
+
 	failNum, updateFlags := d.updateFilterList(ctx, updateFilters)
 	if failNum == len(updateFilters) {
 		return 0, nil, nil, true
@@ -360,17 +387,53 @@ func (d *DNSFilter) updateFilterList(
 ) (failNum int, updateFlags []bool) {
 	for i := range updateFilters {
 		uf := &updateFilters[i]
+		lastSuccess := d.lastUpdateTime(uf)
 		updated, err := d.update(uf)
 		updateFlags = append(updateFlags, updated)
 		if err != nil {
 			failNum++
 			d.logger.ErrorContext(ctx, "updating filter", "url", uf.URL, slogutil.KeyError, err)
+			d.notifyListUpdateFailure(ctx, uf, lastSuccess, err)
 		}
+
+		d.notifyListUpdateProgress(ctx, uf, i+1, len(updateFilters), updated, err)
 	}
 
 	return failNum, updateFlags
 }
 
+// notifyListUpdateProgress reports that flt, which is the done-th of total
+// lists being refreshed, has been processed with err.
+func (d *DNSFilter) notifyListUpdateProgress(
+	ctx context.Context,
+	flt *FilterYAML,
+	done int,
+	total int,
+	updated bool,
+	err error,
+) {
+	notify := d.conf.ListUpdateProgressNotifier
+	if notify == nil {
+		return
+	}
+
+	listType := ListTypeBlock
+	if flt.white {
+		listType = ListTypeAllow
+	}
+
+	notify(ctx, ListUpdateProgressEvent{
+		Err:     err,
+		Name:    flt.Name,
+		URL:     flt.URL,
+		ID:      uint64(flt.ID),
+		Done:    done,
+		Total:   total,
+		Type:    listType,
+		Updated: updated,
+	})
+}
+
 // syncUpdatedFilters syncs updated filters back to the original filters slice
 // and returns the updateCount.  filters must not be nil.  updateFlags must
 // align with updateFilters.  d.conf.filtersMu must be locked.
@@ -494,13 +557,26 @@ func removeOldFilterFile(ctx context.Context, l *slog.Logger, fltPath string) {
 	l.Log(ctx, lvl, "removing old filter", "path", fltPath, slogutil.KeyError, err)
 }
 
-// update refreshes filter's content and a/mtimes of it's file.
+// lastUpdateTime returns the time of the last successful update of flt, which
+// is the modification time of its file.  It's zero if there is no file.
+func (d *DNSFilter) lastUpdateTime(flt *FilterYAML) (t time.Time) {
+	fi, err := os.Stat(flt.Path(d.conf.DataDir))
+	if err != nil {
+		return time.Time{}
+	}
+
+	return fi.ModTime()
+}
+
+// update refreshes filter's content and a/mtimes of it's file.  The times
+// aren't changed on failure, so that they keep the time of the last successful
+// update.
 func (d *DNSFilter) update(filter *FilterYAML) (b bool, err error) {
 	ctx := context.TODO()
 
 	b, err = d.updateIntl(ctx, filter)
 	filter.LastUpdated = time.Now()
-	if !b {
+	if !b && err == nil {
 		chErr := os.Chtimes(
 			filter.Path(d.conf.DataDir),
 			filter.LastUpdated,
@@ -537,6 +613,9 @@ func (d *DNSFilter) updateIntl(ctx context.Context, flt *FilterYAML) (ok bool, e
 	if err != nil {
 		// Don't wrap the error because it's informative enough as is.
 		return false, err
+	} else if res.RulesCount == 0 && flt.checksum != 0 {
+		// Keep the previous contents of a list, which has become empty.
+		return false, withFailureReason(errEmptyList, ListUpdateFailureEmpty)
 	}
 
 	return res.Checksum != flt.checksum, nil
@@ -551,12 +630,14 @@ func (d *DNSFilter) readFromHTTP(
 	resp, err := d.conf.HTTPClient.Get(urlStr)
 	if err != nil {
 		// Don't wrap the error because it's informative enough as is.
-		return nil, err
+		return nil, withFailureReason(err, ListUpdateFailureHTTP)
 	}
 	defer func() { err = errors.WithDeferred(err, resp.Body.Close()) }()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("got status code %d, want %d", resp.StatusCode, http.StatusOK)
+		err = fmt.Errorf("got status code %d, want %d", resp.StatusCode, http.StatusOK)
+
+		return nil, withFailureReason(err, ListUpdateFailureHTTP)
 	}
 
 	bufPtr := d.bufPool.Get()
@@ -565,7 +646,9 @@ func (d *DNSFilter) readFromHTTP(
 	p := rulelist.NewParser()
 	httpBody := ioutil.LimitReader(resp.Body, d.conf.MaxHTTPSize.Bytes())
 
-	return p.Parse(tmpFile, httpBody, *bufPtr)
+	res, err = p.Parse(tmpFile, httpBody, *bufPtr)
+
+	return res, withFailureReason(err, ListUpdateFailureParse)
 }
 
 // readFromFile reads filter data from a file located at path and parses it
@@ -593,7 +676,9 @@ func (d *DNSFilter) readFromFile(
 
 	p := rulelist.NewParser()
 
-	return p.Parse(tmpFile, file, *bufPtr)
+	res, err = p.Parse(tmpFile, file, *bufPtr)
+
+	return res, withFailureReason(err, ListUpdateFailureParse)
 }
 
 // finalizeUpdate closes and gets rid of temporary file f with filter's content
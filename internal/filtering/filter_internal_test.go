@@ -180,3 +180,44 @@ func TestFilterYAML_EnsureName(t *testing.T) {
 		assert.Equal(t, "List 0", f.Name)
 	})
 }
+
+func TestDNSFilter_updateFilterList_failures(t *testing.T) {
+	ctx := testutil.ContextWithTimeout(t, testTimeout)
+
+	var evs []ListUpdateFailureEvent
+	dnsFilter := newDNSFilter(t)
+	dnsFilter.conf.ListUpdateFailureNotifier = func(_ context.Context, ev ListUpdateFailureEvent) {
+		evs = append(evs, ev)
+	}
+
+	f := FilterYAML{
+		URL:    serveFiltersLocally(t, []byte("||example.org^\n")),
+		Name:   "test-filter",
+		Filter: Filter{ID: 1},
+	}
+
+	ok, err := dnsFilter.update(&f)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	lastSuccess := dnsFilter.lastUpdateTime(&f)
+	require.False(t, lastSuccess.IsZero())
+
+	notFound := serveHTTPLocally(t, http.NotFoundHandler())
+	empty := serveFiltersLocally(t, []byte("! Only a comment\n"))
+
+	for _, u := range []string{notFound, empty} {
+		flt := f
+		flt.URL = u
+
+		failNum, _ := dnsFilter.updateFilterList(ctx, []FilterYAML{flt})
+		assert.Equal(t, 1, failNum)
+	}
+
+	require.Len(t, evs, 2)
+
+	assert.Equal(t, ListUpdateFailureHTTP, evs[0].Reason)
+	assert.Equal(t, ListUpdateFailureEmpty, evs[1].Reason)
+	assert.Equal(t, lastSuccess, evs[1].LastSuccess)
+	assert.Equal(t, lastSuccess, dnsFilter.lastUpdateTime(&f))
+}
@@ -120,6 +120,14 @@ type Config struct {
 	// refreshed successfully.
 	ListUpdateNotifier func(ctx context.Context, ev ListUpdateEvent) `yaml:"-"`
 
+	// ListUpdateFailureNotifier is called after a scheduled refresh of a
+	// filter or allowlist has failed.
+	ListUpdateFailureNotifier func(ctx context.Context, ev ListUpdateFailureEvent) `yaml:"-"`
+
+	// ListUpdateProgressNotifier is called after each filter or allowlist has
+	// been processed during a refresh.
+	ListUpdateProgressNotifier func(ctx context.Context, ev ListUpdateProgressEvent) `yaml:"-"`
+
 	// filtersMu protects filter lists.
 	filtersMu *sync.RWMutex
 
@@ -24,6 +24,13 @@ import (
 
 // validateFilterURL validates the filter list URL or file name.
 func (d *DNSFilter) validateFilterURL(urlStr string) (err error) {
+	// Don't wrap the error since it's informative enough as is.
+	return ValidateFilterURL(urlStr, d.safeFSPatterns)
+}
+
+// ValidateFilterURL validates the filter list URL or the absolute path to the
+// file, which must match any of the safeFSPatterns.
+func ValidateFilterURL(urlStr string, safeFSPatterns []string) (err error) {
 	defer func() { err = errors.Annotate(err, "checking filter: %w") }()
 
 	if filepath.IsAbs(urlStr) {
@@ -34,7 +41,7 @@ func (d *DNSFilter) validateFilterURL(urlStr string) (err error) {
 			return err
 		}
 
-		if !pathMatchesAny(d.safeFSPatterns, urlStr) {
+		if !pathMatchesAny(safeFSPatterns, urlStr) {
 			return fmt.Errorf("path %q does not match safe patterns", urlStr)
 		}
 
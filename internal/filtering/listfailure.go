@@ -0,0 +1,113 @@
+package filtering
+
+import (
+	"context"
+	"time"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// ListUpdateFailureReason is the kind of a filter list update failure.
+type ListUpdateFailureReason string
+
+// ListUpdateFailureReason values.
+const (
+	// ListUpdateFailureHTTP means that the list couldn't be downloaded.
+	ListUpdateFailureHTTP ListUpdateFailureReason = "http"
+
+	// ListUpdateFailureRead means that the local list file couldn't be read.
+	ListUpdateFailureRead ListUpdateFailureReason = "read"
+
+	// ListUpdateFailureParse means that the list contents couldn't be parsed.
+	ListUpdateFailureParse ListUpdateFailureReason = "parse"
+
+	// ListUpdateFailureEmpty means that a previously non-empty list contains no
+	// rules anymore.  The previous contents of such a list are kept.
+	ListUpdateFailureEmpty ListUpdateFailureReason = "empty"
+)
+
+// errEmptyList is returned when the updated filter list contains no rules.
+const errEmptyList errors.Error = "list contains no rules"
+
+// ListUpdateFailureEvent describes a failed refresh of a filter list.
+type ListUpdateFailureEvent struct {
+	// LastSuccess is the time of the last successful update of the list.  It's
+	// zero if the list has never been updated successfully.
+	LastSuccess time.Time
+
+	// Err is the error of the update.
+	Err error
+
+	Name   string
+	URL    string
+	Reason ListUpdateFailureReason
+	Type   ListType
+	ID     uint64
+}
+
+// listUpdateError is an error of a filter list update of a known kind.
+type listUpdateError struct {
+	err    error
+	reason ListUpdateFailureReason
+}
+
+// type check
+var _ error = (*listUpdateError)(nil)
+
+// Error implements the error interface for *listUpdateError.
+func (e *listUpdateError) Error() (msg string) {
+	return e.err.Error()
+}
+
+// Unwrap implements the errors.Wrapper interface for *listUpdateError.
+func (e *listUpdateError) Unwrap() (unwrapped error) {
+	return e.err
+}
+
+// withFailureReason wraps err, if it's not nil, into a *listUpdateError with
+// reason.
+func withFailureReason(err error, reason ListUpdateFailureReason) (wrapped error) {
+	if err == nil {
+		return nil
+	}
+
+	return &listUpdateError{err: err, reason: reason}
+}
+
+// failureReason returns the kind of the filter list update error err.
+func failureReason(err error) (reason ListUpdateFailureReason) {
+	if luErr, ok := errors.AsType[*listUpdateError](err); ok {
+		return luErr.reason
+	}
+
+	return ListUpdateFailureRead
+}
+
+// notifyListUpdateFailure reports the failed update of flt with err.
+// lastSuccess is the time of the last successful update, if any.
+func (d *DNSFilter) notifyListUpdateFailure(
+	ctx context.Context,
+	flt *FilterYAML,
+	lastSuccess time.Time,
+	err error,
+) {
+	notify := d.conf.ListUpdateFailureNotifier
+	if notify == nil {
+		return
+	}
+
+	listType := ListTypeBlock
+	if flt.white {
+		listType = ListTypeAllow
+	}
+
+	notify(ctx, ListUpdateFailureEvent{
+		LastSuccess: lastSuccess,
+		Err:         err,
+		Name:        flt.Name,
+		URL:         flt.URL,
+		Reason:      failureReason(err),
+		Type:        listType,
+		ID:          uint64(flt.ID),
+	})
+}
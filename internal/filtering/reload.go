@@ -0,0 +1,92 @@
+package filtering
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+)
+
+// ApplyConfig replaces the settings of d stored in the configuration file with
+// the ones from c and rebuilds the filtering engines.  The properties set at
+// the initialization, like the checkers, the callbacks, the safe search, the
+// caches, and the safe file-system patterns, aren't changed.  The filter lists
+// which haven't been downloaded yet are downloaded in the background.  c must
+// not be nil.
+func (d *DNSFilter) ApplyConfig(ctx context.Context, c *Config) (err error) {
+	if c.BlockedServices != nil {
+		c.BlockedServices.FilterUnknownIDs(ctx, d.logger)
+		err = c.BlockedServices.Validate()
+		if err != nil {
+			return fmt.Errorf("blocked services: %w", err)
+		}
+	}
+
+	rewrites := cloneRewrites(c.Rewrites)
+	for i, r := range rewrites {
+		err = r.normalize(ctx, d.logger)
+		if err != nil {
+			return fmt.Errorf("rewrites: at index %d: %w", i, err)
+		}
+	}
+
+	filters := deduplicateFilters(slices.Clone(c.Filters))
+	allowFilters := deduplicateFilters(slices.Clone(c.WhitelistFilters))
+
+	d.loadFilters(ctx, filters)
+	d.loadFilters(ctx, allowFilters)
+
+	d.idGen.fix(filters)
+	d.idGen.fix(allowFilters)
+
+	d.applySettings(c, rewrites)
+
+	started := d.IsStarted()
+	func() {
+		d.conf.filtersMu.Lock()
+		defer d.conf.filtersMu.Unlock()
+
+		d.conf.Filters = filters
+		d.conf.WhitelistFilters = allowFilters
+		d.conf.UserRules = slices.Clone(c.UserRules)
+
+		// The engines can only be replaced asynchronously once the updates loop
+		// is running.
+		d.enableFiltersLocked(ctx, started)
+	}()
+
+	if started {
+		go func() {
+			defer slogutil.RecoverAndLog(ctx, d.logger)
+
+			_, _, _ = d.tryRefreshFilters(true, true, false)
+		}()
+	}
+
+	return nil
+}
+
+// applySettings sets the settings of d other than the filter lists from c.
+func (d *DNSFilter) applySettings(c *Config, rewrites []*LegacyRewrite) {
+	d.confMu.Lock()
+	defer d.confMu.Unlock()
+
+	conf := d.conf
+	conf.BlockingIPv4 = c.BlockingIPv4
+	conf.BlockingIPv6 = c.BlockingIPv6
+	conf.BlockedServices = c.BlockedServices
+	conf.ProtectionDisabledUntil = c.ProtectionDisabledUntil
+	conf.BlockingMode = c.BlockingMode
+	conf.ParentalBlockHost = c.ParentalBlockHost
+	conf.SafeBrowsingBlockHost = c.SafeBrowsingBlockHost
+	conf.Rewrites = rewrites
+	conf.MaxHTTPSize = c.MaxHTTPSize
+	conf.FiltersUpdateIntervalHours = c.FiltersUpdateIntervalHours
+	conf.BlockedResponseTTL = c.BlockedResponseTTL
+	conf.FilteringEnabled = c.FilteringEnabled
+	conf.RewritesEnabled = c.RewritesEnabled
+	conf.ParentalEnabled = c.ParentalEnabled
+	conf.SafeBrowsingEnabled = c.SafeBrowsingEnabled
+	conf.ProtectionEnabled = c.ProtectionEnabled
+}
@@ -0,0 +1,56 @@
+package filtering
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDNSFilter_ApplyConfig(t *testing.T) {
+	t.Parallel()
+
+	d, setts := newForTest(t, &Config{
+		DataDir:          t.TempDir(),
+		FilteringEnabled: true,
+		RewritesEnabled:  true,
+		UserRules:        []string{"||old.example^"},
+	}, nil)
+	t.Cleanup(d.Close)
+
+	d.EnableFilters(false)
+	d.checkMatch(t, "old.example", setts)
+
+	ctx := testutil.ContextWithTimeout(t, testTimeout)
+	err := d.ApplyConfig(ctx, &Config{
+		FilteringEnabled: true,
+		RewritesEnabled:  true,
+		UserRules:        []string{"||new.example^"},
+		Rewrites: []*LegacyRewrite{{
+			Domain:  "rewritten.example",
+			Answer:  "192.0.2.1",
+			Enabled: true,
+		}},
+	})
+	require.NoError(t, err)
+
+	d.checkMatchEmpty(t, "old.example", setts)
+	d.checkMatch(t, "new.example", setts)
+
+	res, err := d.CheckHost("rewritten.example", dns.TypeA, setts)
+	require.NoError(t, err)
+
+	assert.Equal(t, Rewritten, res.Reason)
+	assert.Equal(t, []netip.Addr{netip.MustParseAddr("192.0.2.1")}, res.IPList)
+
+	c := &Config{}
+	d.WriteDiskConfig(c)
+
+	assert.Equal(t, []string{"||new.example^"}, c.UserRules)
+	require.Len(t, c.Rewrites, 1)
+
+	assert.Equal(t, netip.MustParseAddr("192.0.2.1"), c.Rewrites[0].IP)
+}
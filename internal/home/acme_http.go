@@ -5,7 +5,9 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
@@ -19,15 +21,38 @@ import (
 // acmeConfigJSON is the JSON representation of the ACME ("SSL/TLS issue")
 // configuration used by the settings HTTP API.
 type acmeConfigJSON struct {
+	// DNSCredentials are the credentials of the DNS provider.  They're never
+	// returned in responses, see DNSCredentialKeys.
+	DNSCredentials map[string]string `json:"dns_credentials,omitempty"`
+
 	Email              string   `json:"email"`
 	Challenge          string   `json:"challenge"`
 	CloudflareAPIToken string   `json:"cloudflare_api_token"`
+	DNSProvider        string   `json:"dns_provider"`
 	Domains            []string `json:"domains"`
 	DNSResolvers       []string `json:"dns_resolvers"`
 
+	// DNSCredentialKeys are the sorted keys of the configured credentials of
+	// the DNS provider.  It's only set in responses.
+	DNSCredentialKeys []string `json:"dns_credential_keys,omitempty"`
+
+	// DNSProviders are the names of the supported providers for the
+	// "dns-01" challenge.  It's only set in responses.
+	DNSProviders []string `json:"dns_providers,omitempty"`
+
 	// LastIssuedAt is RFC 3339-formatted, or empty if a certificate has
 	// never been issued via ACME.
-	LastIssuedAt    string `json:"last_issued_at"`
+	LastIssuedAt string `json:"last_issued_at"`
+
+	// NotAfter is the RFC 3339-formatted expiration time of the active
+	// certificate, or empty if there is none.  It's only set in responses.
+	NotAfter string `json:"not_after,omitempty"`
+
+	// NextRenewalAt is the RFC 3339-formatted time after which the active
+	// certificate is renewed or a reminder is sent.  It's only set in
+	// responses.
+	NextRenewalAt string `json:"next_renewal_at,omitempty"`
+
 	LastError       string `json:"last_error"`
 	RenewBeforeDays int    `json:"renew_before_days"`
 	Enabled         bool   `json:"enabled"`
@@ -68,6 +93,8 @@ func toACMEConfigJSON(c *acmeConfig) (j acmeConfigJSON) {
 		Domains:            c.Domains,
 		Challenge:          c.Challenge,
 		CloudflareAPIToken: c.CloudflareAPIToken,
+		DNSProvider:        c.DNSProvider,
+		DNSCredentials:     c.DNSCredentials,
 		DNSResolvers:       c.DNSResolvers,
 		AutoRenew:          c.AutoRenew,
 		RenewBeforeDays:    c.RenewBeforeDays,
@@ -76,6 +103,13 @@ func toACMEConfigJSON(c *acmeConfig) (j acmeConfigJSON) {
 	}
 }
 
+// hideCredentials replaces the credentials of the DNS provider in j with their
+// keys, so that the credentials aren't returned in responses.
+func (j *acmeConfigJSON) hideCredentials() {
+	j.DNSCredentialKeys = slices.Sorted(maps.Keys(j.DNSCredentials))
+	j.DNSCredentials = nil
+}
+
 // validate returns an error if the ACME configuration in j is not valid
 // enough to attempt an issuance.
 func (j *acmeConfigJSON) validate() (err error) {
@@ -90,6 +124,11 @@ func (j *acmeConfigJSON) validate() (err error) {
 		if j.CloudflareAPIToken == "" {
 			return errors.Error("cloudflare api token is required for the dns-01-cloudflare challenge")
 		}
+	case acme.ChallengeDNS01:
+		err = acme.ValidateDNSProvider(j.DNSProvider, j.DNSCredentials)
+		if err != nil {
+			return err
+		}
 	default:
 		return fmt.Errorf("unsupported challenge type %q", j.Challenge)
 	}
@@ -107,6 +146,17 @@ func (m *tlsManager) handleACMEStatus(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	resp := toACMEConfigJSON(acmeConfigSnapshot())
+	resp.hideCredentials()
+	resp.DNSProviders = acme.DNSProviders()
+
+	m.mu.Lock()
+	notAfter := m.status.NotAfter
+	m.mu.Unlock()
+
+	if !notAfter.IsZero() {
+		resp.NotAfter = notAfter.Format(time.RFC3339)
+		resp.NextRenewalAt = renewalTime(notAfter, resp.RenewBeforeDays).Format(time.RFC3339)
+	}
 
 	aghhttp.WriteJSONResponseOK(ctx, m.logger, w, r, resp)
 }
@@ -125,6 +175,12 @@ func (m *tlsManager) handleACMEConfigure(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// The credentials of the DNS provider aren't returned by the status HTTP
+	// API, so keep the configured ones, unless new ones are sent.
+	if req.DNSCredentials == nil {
+		req.DNSCredentials = acmeConfigSnapshot().DNSCredentials
+	}
+
 	if req.Enabled {
 		if err = req.validate(); err != nil {
 			aghhttp.ErrorAndLog(ctx, m.logger, r, w, http.StatusBadRequest, "%s", err)
@@ -144,6 +200,10 @@ func (m *tlsManager) handleACMEConfigure(w http.ResponseWriter, r *http.Request)
 	if req.CloudflareAPIToken != "" {
 		config.ACME.CloudflareAPIToken = req.CloudflareAPIToken
 	}
+	config.ACME.DNSProvider = req.DNSProvider
+	if req.DNSCredentials != nil {
+		config.ACME.DNSCredentials = req.DNSCredentials
+	}
 	config.ACME.DNSResolvers = req.DNSResolvers
 	config.ACME.AutoRenew = req.AutoRenew
 	config.ACME.RenewBeforeDays = req.RenewBeforeDays
@@ -151,6 +211,8 @@ func (m *tlsManager) handleACMEConfigure(w http.ResponseWriter, r *http.Request)
 	resp := toACMEConfigJSON(config.ACME)
 	config.Unlock()
 
+	resp.hideCredentials()
+
 	m.confModifier.Apply(ctx)
 
 	aghhttp.WriteJSONResponseOK(ctx, m.logger, w, r, resp)
@@ -230,6 +292,8 @@ func (m *tlsManager) runACMEIssueJob(
 		Domains:            cfgJSON.Domains,
 		Challenge:          acme.ChallengeType(cfgJSON.Challenge),
 		CloudflareAPIToken: cfgJSON.CloudflareAPIToken,
+		DNSProvider:        cfgJSON.DNSProvider,
+		DNSCredentials:     cfgJSON.DNSCredentials,
 		DNSResolvers:       cfgJSON.DNSResolvers,
 		AccountKeyPEM:      accountKeyPEM,
 		AccountURI:         accountURI,
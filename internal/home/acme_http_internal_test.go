@@ -0,0 +1,69 @@
+package home
+
+import (
+	"testing"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/acme"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestACMEConfigJSON_validate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		creds      map[string]string
+		name       string
+		provider   string
+		wantErrMsg string
+	}{{
+		creds:      map[string]string{"token": "test-token"},
+		name:       "valid",
+		provider:   acme.DNSProviderDuckDNS,
+		wantErrMsg: "",
+	}, {
+		creds:    map[string]string{"token": "test-token"},
+		name:     "wrong_creds",
+		provider: acme.DNSProviderCloudflare,
+		wantErrMsg: `acme: cloudflare dns provider: credential "api_token": ` +
+			`empty value`,
+	}, {
+		creds:      map[string]string{"program": "/bin/sh"},
+		name:       "exec",
+		provider:   "exec",
+		wantErrMsg: `acme: unsupported dns provider "exec"`,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			j := &acmeConfigJSON{
+				DNSCredentials:  tc.creds,
+				Challenge:       string(acme.ChallengeDNS01),
+				DNSProvider:     tc.provider,
+				Domains:         []string{"example.org"},
+				RenewBeforeDays: 30,
+			}
+
+			testutil.AssertErrorMsg(t, tc.wantErrMsg, j.validate())
+		})
+	}
+}
+
+func TestACMEConfigJSON_hideCredentials(t *testing.T) {
+	t.Parallel()
+
+	j := &acmeConfigJSON{
+		DNSCredentials: map[string]string{
+			"username": "user",
+			"endpoint": "https://dns.example/acme",
+			"password": "secret",
+		},
+	}
+
+	j.hideCredentials()
+
+	assert.Nil(t, j.DNSCredentials)
+	assert.Equal(t, []string{"endpoint", "password", "username"}, j.DNSCredentialKeys)
+}
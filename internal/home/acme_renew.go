@@ -55,15 +55,11 @@ func (m *tlsManager) checkCertExpiry(ctx context.Context) {
 	accountKeyPEM := snap.AccountKeyPEM
 	accountURI := snap.AccountURI
 
-	renewBeforeDays := acmeCfg.RenewBeforeDays
-	if renewBeforeDays <= 0 {
-		renewBeforeDays = defaultRenewBeforeDays
+	if time.Now().Before(renewalTime(notAfter, acmeCfg.RenewBeforeDays)) {
+		return
 	}
 
 	daysLeft := int(time.Until(notAfter).Hours() / 24)
-	if daysLeft > renewBeforeDays {
-		return
-	}
 
 	if !acmeCfg.Enabled || !acmeCfg.AutoRenew {
 		m.notifyCertReminder(ctx, acmeCfg.Domains, notAfter, daysLeft)
@@ -78,6 +74,8 @@ func (m *tlsManager) checkCertExpiry(ctx context.Context) {
 		Domains:            acmeCfg.Domains,
 		Challenge:          acme.ChallengeType(acmeCfg.Challenge),
 		CloudflareAPIToken: acmeCfg.CloudflareAPIToken,
+		DNSProvider:        acmeCfg.DNSProvider,
+		DNSCredentials:     acmeCfg.DNSCredentials,
 		DNSResolvers:       acmeCfg.DNSResolvers,
 		AccountKeyPEM:      accountKeyPEM,
 		AccountURI:         accountURI,
@@ -117,6 +115,17 @@ func (m *tlsManager) checkCertExpiry(ctx context.Context) {
 	m.notifyRenewalResult(ctx, acmeCfg.Domains, status.NotAfter, nil)
 }
 
+// renewalTime returns the time after which a certificate expiring at notAfter
+// is due for renewal.  If renewBeforeDays is not positive,
+// [defaultRenewBeforeDays] is used.
+func renewalTime(notAfter time.Time, renewBeforeDays int) (t time.Time) {
+	if renewBeforeDays <= 0 {
+		renewBeforeDays = defaultRenewBeforeDays
+	}
+
+	return notAfter.AddDate(0, 0, -renewBeforeDays)
+}
+
 // notifyCertReminder sends a Telegram reminder for a certificate that is
 // nearing expiration but not configured for auto-renewal.  It's a no-op if
 // no notifier is configured.
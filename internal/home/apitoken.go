@@ -0,0 +1,294 @@
+package home
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghuser"
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// apiTokenScope is the scope of an API token, which defines the requests it
+// may be used for.
+type apiTokenScope string
+
+// Valid API token scopes.
+const (
+	// apiTokenScopeAdmin allows all requests.
+	apiTokenScopeAdmin apiTokenScope = "admin"
+
+	// apiTokenScopeRead only allows the requests, which don't modify anything
+	// and don't return any secrets.
+	apiTokenScopeRead apiTokenScope = "read"
+
+	// apiTokenScopeStats only allows reading the statistics, the status, and
+	// the metrics.
+	apiTokenScopeStats apiTokenScope = "stats"
+)
+
+// validate returns an error if s isn't a valid scope.
+func (s apiTokenScope) validate() (err error) {
+	switch s {
+	case apiTokenScopeAdmin, apiTokenScopeRead, apiTokenScopeStats:
+		return nil
+	default:
+		return fmt.Errorf("scope: %w: %q", errors.ErrBadEnumValue, s)
+	}
+}
+
+// statsScopePaths are the paths allowed for [apiTokenScopeStats] without the
+// API version prefix.
+var statsScopePaths = []string{
+	"/control/status",
+	"/control/stats",
+	"/control/stats/config",
+	"/control/stats_info",
+	"/metrics",
+}
+
+// readScopePaths are the paths allowed for [apiTokenScopeRead] in addition to
+// [statsScopePaths] without the API version prefix.  The HTTP APIs returning
+// the secrets, like the tokens and the passwords of the notification channels,
+// or the whole configuration, must not be added here.
+var readScopePaths = []string{
+	"/control/access/list",
+	"/control/blocked_services/all",
+	"/control/blocked_services/get",
+	"/control/blocked_services/list",
+	"/control/blocked_services/services",
+	"/control/clients",
+	"/control/clients/find",
+	"/control/dhcp/interfaces",
+	"/control/dhcp/status",
+	"/control/dns_info",
+	"/control/filtering/check_host",
+	"/control/filtering/status",
+	"/control/i18n/current_language",
+	"/control/notifications/alerts",
+	"/control/notifications/history",
+	"/control/parental/status",
+	"/control/plugins",
+	"/control/profile",
+	"/control/querylog",
+	"/control/querylog/config",
+	"/control/querylog_info",
+	"/control/rewrite/list",
+	"/control/rewrite/settings",
+	"/control/safebrowsing/status",
+	"/control/safesearch/status",
+	"/control/sync/snapshot",
+	"/control/sync/status",
+	"/control/systeminfo",
+	"/control/systeminfo/history",
+	"/control/systeminfo/stream",
+	"/control/tls/status",
+	"/control/update/versions",
+	"/control/youtube/status",
+}
+
+// apiVersionPrefix matches the version prefix of the control API paths, like
+// "/control/v1/".
+var apiVersionPrefix = regexp.MustCompile(`^/control/v\d+/`)
+
+// allows returns true if s allows a request with method to path.  Only
+// [apiTokenScopeAdmin] allows the requests to the paths, which aren't listed
+// explicitly, like the debug HTTP API.
+func (s apiTokenScope) allows(method, path string) (ok bool) {
+	if s == apiTokenScopeAdmin {
+		return true
+	}
+
+	if method != http.MethodGet && method != http.MethodHead {
+		return false
+	}
+
+	path = apiVersionPrefix.ReplaceAllString(path, "/control/")
+	if slices.Contains(statsScopePaths, path) {
+		return s == apiTokenScopeRead || s == apiTokenScopeStats
+	}
+
+	return s == apiTokenScopeRead && slices.Contains(readScopePaths, path)
+}
+
+// apiTokenPrefix is the prefix of the API token secrets, which makes them easy
+// to recognize, for example, by the secret scanners.
+const apiTokenPrefix = "agh_"
+
+// apiTokenSecretLen is the length of the random part of the API token secrets
+// in bytes.
+const apiTokenSecretLen = 32
+
+// apiToken is a long-lived token, which authenticates the requests to the API
+// on behalf of a web user.  Only the hash of the secret is stored.
+type apiToken struct {
+	// CreatedAt is the time of the creation of the token.
+	CreatedAt time.Time `yaml:"created_at" json:"created_at"`
+
+	// ID is the unique identifier of the token, which is used to revoke it.
+	ID string `yaml:"id" json:"id"`
+
+	// Name is the human-readable name of the token, like "grafana".
+	Name string `yaml:"name" json:"name"`
+
+	// Hash is the hexadecimal SHA-256 hash of the secret.
+	Hash string `yaml:"hash" json:"-"`
+
+	// Scope is the scope of the token.
+	Scope apiTokenScope `yaml:"scope" json:"scope"`
+
+	// User is the login of the web user, on behalf of which the token acts.
+	User string `yaml:"user" json:"user"`
+}
+
+// validate returns an error if t is invalid.
+func (t *apiToken) validate() (err error) {
+	var errs []error
+	if t.ID == "" {
+		errs = append(errs, fmt.Errorf("id: %w", errors.ErrEmptyValue))
+	}
+
+	if t.Name == "" {
+		errs = append(errs, fmt.Errorf("name: %w", errors.ErrEmptyValue))
+	}
+
+	if _, err = hex.DecodeString(t.Hash); err != nil || len(t.Hash) != sha256.Size*2 {
+		errs = append(errs, fmt.Errorf("hash: bad value %q", t.Hash))
+	}
+
+	if t.User == "" {
+		errs = append(errs, fmt.Errorf("user: %w", errors.ErrEmptyValue))
+	}
+
+	errs = append(errs, t.Scope.validate())
+
+	return errors.Join(errs...)
+}
+
+// hashAPITokenSecret returns the hexadecimal SHA-256 hash of secret.  The
+// secrets are random, so a slow password hash isn't necessary.
+func hashAPITokenSecret(secret string) (hash string) {
+	sum := sha256.Sum256([]byte(secret))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// newAPIToken returns a new token for user with the secret, which is only
+// returned once.  If an error occurs during random generation, it will cause
+// the program to crash.
+func newAPIToken(name string, scope apiTokenScope, user aghuser.Login) (t *apiToken, secret string) {
+	b := make([]byte, apiTokenSecretLen)
+	_, _ = rand.Read(b)
+	secret = apiTokenPrefix + hex.EncodeToString(b)
+
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+
+	return &apiToken{
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		ID:        hex.EncodeToString(id),
+		Name:      name,
+		Hash:      hashAPITokenSecret(secret),
+		Scope:     scope,
+		User:      string(user),
+	}, secret
+}
+
+// apiTokenStore is the in-memory storage of the API tokens.  It's safe for
+// concurrent use.
+type apiTokenStore struct {
+	// mu protects tokens.
+	mu *sync.Mutex
+
+	// tokens are the tokens in the order of creation.
+	tokens []*apiToken
+}
+
+// newAPITokenStore returns a new properly initialized *apiTokenStore with the
+// tokens from the configuration file.
+func newAPITokenStore(tokens []*apiToken) (s *apiTokenStore, err error) {
+	for i, t := range tokens {
+		if err = t.validate(); err != nil {
+			return nil, fmt.Errorf("at index %d: %w", i, err)
+		}
+	}
+
+	return &apiTokenStore{
+		mu:     &sync.Mutex{},
+		tokens: slices.Clone(tokens),
+	}, nil
+}
+
+// list returns the copies of the tokens.
+func (s *apiTokenStore) list() (tokens []*apiToken) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens = make([]*apiToken, 0, len(s.tokens))
+	for _, t := range s.tokens {
+		cloned := *t
+		tokens = append(tokens, &cloned)
+	}
+
+	return tokens
+}
+
+// add stores t.
+func (s *apiTokenStore) add(t *apiToken) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens = append(s.tokens, t)
+}
+
+// revoke removes the token with id.  ok is false if there is no such token.
+func (s *apiTokenStore) revoke(id string) (ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := len(s.tokens)
+	s.tokens = slices.DeleteFunc(s.tokens, func(t *apiToken) (del bool) { return t.ID == id })
+
+	return len(s.tokens) < n
+}
+
+// bySecret returns the copy of the token with secret or nil, if there is none.
+func (s *apiTokenStore) bySecret(secret string) (t *apiToken) {
+	if !strings.HasPrefix(secret, apiTokenPrefix) {
+		return nil
+	}
+
+	hash := hashAPITokenSecret(secret)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.tokens, func(t *apiToken) (ok bool) { return t.Hash == hash })
+	if i < 0 {
+		return nil
+	}
+
+	cloned := *s.tokens[i]
+
+	return &cloned
+}
+
+// renameUser updates the owner of the tokens of the web user, which login has
+// changed.
+func (s *apiTokenStore) renameUser(oldLogin, newLogin aghuser.Login) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, t := range s.tokens {
+		if t.User == string(oldLogin) {
+			t.User = string(newLogin)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package home
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghuser"
+	"github.com/AdguardTeam/golibs/httphdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPITokenScope_allows(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scope  apiTokenScope
+		method string
+		path   string
+		want   assert.BoolAssertionFunc
+	}{{
+		scope:  apiTokenScopeAdmin,
+		method: http.MethodPost,
+		path:   "/control/filtering/refresh",
+		want:   assert.True,
+	}, {
+		scope:  apiTokenScopeRead,
+		method: http.MethodGet,
+		path:   "/control/filtering/status",
+		want:   assert.True,
+	}, {
+		scope:  apiTokenScopeRead,
+		method: http.MethodPost,
+		path:   "/control/filtering/refresh",
+		want:   assert.False,
+	}, {
+		scope:  apiTokenScopeStats,
+		method: http.MethodGet,
+		path:   "/control/stats",
+		want:   assert.True,
+	}, {
+		scope:  apiTokenScopeStats,
+		method: http.MethodGet,
+		path:   "/control/v1/status",
+		want:   assert.True,
+	}, {
+		scope:  apiTokenScopeStats,
+		method: http.MethodGet,
+		path:   "/metrics",
+		want:   assert.True,
+	}, {
+		scope:  apiTokenScopeStats,
+		method: http.MethodGet,
+		path:   "/control/querylog",
+		want:   assert.False,
+	}, {
+		scope:  apiTokenScopeStats,
+		method: http.MethodPost,
+		path:   "/control/stats_reset",
+		want:   assert.False,
+	}, {
+		scope:  apiTokenScopeAdmin,
+		method: http.MethodGet,
+		path:   "/control/debug/pprof/heap",
+		want:   assert.True,
+	}, {
+		scope:  apiTokenScopeRead,
+		method: http.MethodGet,
+		path:   "/control/debug/goroutines",
+		want:   assert.False,
+	}, {
+		scope:  apiTokenScopeRead,
+		method: http.MethodGet,
+		path:   "/control/v1/debug/vars",
+		want:   assert.False,
+	}, {
+		scope:  apiTokenScopeRead,
+		method: http.MethodGet,
+		path:   "/control/support_bundle",
+		want:   assert.False,
+	}, {
+		scope:  apiTokenScopeRead,
+		method: http.MethodGet,
+		path:   "/control/v1/querylog",
+		want:   assert.True,
+	}, {
+		scope:  apiTokenScopeRead,
+		method: http.MethodGet,
+		path:   "/metrics",
+		want:   assert.True,
+	}, {
+		scope:  apiTokenScopeRead,
+		method: http.MethodGet,
+		path:   "/control/unknown",
+		want:   assert.False,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.scope)+"_"+tc.method+"_"+tc.path, func(t *testing.T) {
+			t.Parallel()
+
+			tc.want(t, tc.scope.allows(tc.method, tc.path))
+		})
+	}
+}
+
+func TestAPITokenScope_allows_secrets(t *testing.T) {
+	t.Parallel()
+
+	// secretPaths are the paths of the HTTP APIs returning the secrets, like
+	// the tokens and the passwords of the notification channels, the DNS
+	// provider credentials, and the whole configuration.
+	secretPaths := []string{
+		"/control/api_tokens",
+		"/control/config/history",
+		"/control/config/history/diff",
+		"/control/notifications/alertmanager",
+		"/control/notifications/gotify",
+		"/control/notifications/heartbeat",
+		"/control/notifications/matrix",
+		"/control/notifications/mqtt",
+		"/control/notifications/opsgenie",
+		"/control/notifications/pagerduty",
+		"/control/notifications/signal",
+		"/control/notifications/teams",
+		"/control/notifications/telegram",
+		"/control/sessions",
+		"/control/settings/export",
+		"/control/tls/acme/status",
+		"/control/totp",
+		"/control/v1/notifications/telegram",
+		"/control/v1/settings/export",
+	}
+
+	for _, scope := range []apiTokenScope{apiTokenScopeRead, apiTokenScopeStats} {
+		for _, p := range secretPaths {
+			assert.Falsef(t, scope.allows(http.MethodGet, p), "scope %q, path %q", scope, p)
+		}
+	}
+}
+
+func TestAPITokenStore(t *testing.T) {
+	t.Parallel()
+
+	s, err := newAPITokenStore(nil)
+	require.NoError(t, err)
+
+	tok, secret := newAPIToken("test", apiTokenScopeRead, testUsername)
+	require.NoError(t, tok.validate())
+
+	s.add(tok)
+
+	got := s.bySecret(secret)
+	require.NotNil(t, got)
+
+	assert.Equal(t, tok.ID, got.ID)
+	assert.Nil(t, s.bySecret(apiTokenPrefix+"bad"))
+	assert.Nil(t, s.bySecret("bad"))
+
+	s.renameUser(testUsername, "new_name")
+	assert.Equal(t, "new_name", s.list()[0].User)
+
+	assert.True(t, s.revoke(tok.ID))
+	assert.False(t, s.revoke(tok.ID))
+	assert.Nil(t, s.bySecret(secret))
+	assert.Empty(t, s.list())
+
+	_, err = newAPITokenStore([]*apiToken{{ID: "1", Name: "bad"}})
+	assert.Error(t, err)
+}
+
+func TestAuthMiddlewareDefault_apiToken(t *testing.T) {
+	t.Parallel()
+
+	const login = aghuser.Login(testUsername)
+
+	user := newTestUser(t, testPassword, login)
+
+	usersDB := newTestUsersDB()
+	usersDB.onAll = func(_ context.Context) (us []*aghuser.User, err error) {
+		return []*aghuser.User{user}, nil
+	}
+	usersDB.onByLogin = func(_ context.Context, l aghuser.Login) (u *aghuser.User, err error) {
+		if l == login {
+			return user, nil
+		}
+
+		return nil, nil
+	}
+
+	tokens, err := newAPITokenStore(nil)
+	require.NoError(t, err)
+
+	statsTok, statsSecret := newAPIToken("stats", apiTokenScopeStats, login)
+	tokens.add(statsTok)
+
+	orphanTok, orphanSecret := newAPIToken("orphan", apiTokenScopeAdmin, "deleted")
+	tokens.add(orphanTok)
+
+	mw := newAuthMiddlewareDefault(&authMiddlewareDefaultConfig{
+		logger:      testLogger,
+		mux:         http.NewServeMux(),
+		rateLimiter: emptyRateLimiter{},
+		sessions:    newTestSessionStorage(),
+		users:       usersDB,
+		apiTokens:   tokens,
+	})
+
+	testCases := []struct {
+		wantUser *aghuser.User
+		name     string
+		method   string
+		path     string
+		secret   string
+		wantCode int
+	}{{
+		wantUser: user,
+		name:     "allowed",
+		method:   http.MethodGet,
+		path:     "/control/stats",
+		secret:   statsSecret,
+		wantCode: http.StatusOK,
+	}, {
+		wantUser: nil,
+		name:     "out_of_scope",
+		method:   http.MethodGet,
+		path:     "/control/querylog",
+		secret:   statsSecret,
+		wantCode: http.StatusForbidden,
+	}, {
+		wantUser: nil,
+		name:     "unknown",
+		method:   http.MethodGet,
+		path:     "/control/stats",
+		secret:   apiTokenPrefix + "0000",
+		wantCode: http.StatusUnauthorized,
+	}, {
+		wantUser: nil,
+		name:     "no_user",
+		method:   http.MethodGet,
+		path:     "/control/stats",
+		secret:   orphanSecret,
+		wantCode: http.StatusUnauthorized,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := &testAuthHandler{}
+			r := httptest.NewRequest(tc.method, tc.path, nil)
+			r.Header.Set(httphdr.Authorization, bearerTokenPrefix+tc.secret)
+
+			w := httptest.NewRecorder()
+			mw.Wrap(h).ServeHTTP(w, r)
+
+			assert.Equal(t, tc.wantCode, w.Code)
+			assert.Equal(t, tc.wantUser, h.user)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package home
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+)
+
+// registerAPITokenHandlers registers the HTTP handlers of the API tokens.
+func (web *webAPI) registerAPITokenHandlers() {
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodGet,
+		"/control/api_tokens",
+		web.handleGetAPITokens,
+		&aghhttp.RouteInfo{
+			Summary:  "List the API tokens",
+			Response: apiTokensResp{},
+		},
+	)
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodPost,
+		"/control/api_tokens/create",
+		web.handleCreateAPIToken,
+		&aghhttp.RouteInfo{
+			Summary:  "Create an API token",
+			Request:  createAPITokenReq{},
+			Response: createAPITokenResp{},
+		},
+	)
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodPost,
+		"/control/api_tokens/revoke",
+		web.handleRevokeAPIToken,
+		&aghhttp.RouteInfo{
+			Summary: "Revoke an API token",
+			Request: revokeAPITokenReq{},
+		},
+	)
+}
+
+// apiTokensResp is the response to the GET /control/api_tokens HTTP API.
+type apiTokensResp struct {
+	Tokens []*apiToken `json:"tokens"`
+}
+
+// createAPITokenReq is the request to the POST /control/api_tokens/create
+// HTTP API.
+type createAPITokenReq struct {
+	Name  string        `json:"name"`
+	Scope apiTokenScope `json:"scope"`
+}
+
+// createAPITokenResp is the response to the POST /control/api_tokens/create
+// HTTP API.  Token is the secret, which isn't shown again.
+type createAPITokenResp struct {
+	*apiToken
+
+	Token string `json:"token"`
+}
+
+// revokeAPITokenReq is the request to the POST /control/api_tokens/revoke
+// HTTP API.
+type revokeAPITokenReq struct {
+	ID string `json:"id"`
+}
+
+// handleGetAPITokens is the handler for the GET /control/api_tokens HTTP API.
+func (web *webAPI) handleGetAPITokens(w http.ResponseWriter, r *http.Request) {
+	aghhttp.WriteJSONResponseOK(r.Context(), web.logger, w, r, &apiTokensResp{
+		Tokens: web.auth.apiTokens.list(),
+	})
+}
+
+// handleCreateAPIToken is the handler for the POST /control/api_tokens/create
+// HTTP API.  The token acts on behalf of the web user, who creates it.
+func (web *webAPI) handleCreateAPIToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	l := web.logger
+
+	if web.auth.isUserless {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusForbidden, "no users configured")
+
+		return
+	}
+
+	u, ok := webUserFromContext(ctx)
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+
+		return
+	}
+
+	req := &createAPITokenReq{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusBadRequest, "reading req: %s", err)
+
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusBadRequest, "name must not be empty")
+
+		return
+	}
+
+	err = req.Scope.validate()
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusBadRequest, "%s", err)
+
+		return
+	}
+
+	t, secret := newAPIToken(req.Name, req.Scope, u.Login)
+	web.auth.apiTokens.add(t)
+	web.confModifier.Apply(ctx)
+
+	l.InfoContext(ctx, "api token created", "id", t.ID, "name", t.Name, "scope", t.Scope)
+
+	aghhttp.WriteJSONResponseOK(ctx, l, w, r, &createAPITokenResp{
+		apiToken: t,
+		Token:    secret,
+	})
+}
+
+// handleRevokeAPIToken is the handler for the POST /control/api_tokens/revoke
+// HTTP API.
+func (web *webAPI) handleRevokeAPIToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	l := web.logger
+
+	req := &revokeAPITokenReq{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusBadRequest, "reading req: %s", err)
+
+		return
+	}
+
+	if !web.auth.apiTokens.revoke(req.ID) {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusNotFound, "no api token with id %q", req.ID)
+
+		return
+	}
+
+	web.confModifier.Apply(ctx)
+
+	l.InfoContext(ctx, "api token revoked", "id", req.ID)
+
+	aghhttp.OK(ctx, l, w)
+}
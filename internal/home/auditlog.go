@@ -0,0 +1,474 @@
+package home
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/aghos"
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+	"github.com/google/renameio/v2/maybe"
+)
+
+// auditLogFilename is the name of the file of the audit log in the data
+// directory.  Each line of the file is a JSON-encoded [auditEntry].
+const auditLogFilename = "audit_log.jsonl"
+
+// defaultAuditLogMaxEntries is the default maximum number of the entries kept
+// in the audit log.
+const defaultAuditLogMaxEntries = 10_000
+
+// auditLogConfig is the configuration of the audit log of the state-changing
+// requests to the control API.
+type auditLogConfig struct {
+	// MaxEntries is the maximum number of the entries kept.  The oldest
+	// entries are removed first.  It must be positive if Enabled is true.
+	MaxEntries int `yaml:"max_entries"`
+
+	// Enabled defines if the requests are recorded.
+	Enabled bool `yaml:"enabled"`
+}
+
+// validate returns an error if c is invalid.  c may be nil.
+func (c *auditLogConfig) validate() (err error) {
+	if c == nil || !c.Enabled {
+		return nil
+	}
+
+	if c.MaxEntries <= 0 {
+		return fmt.Errorf("max_entries: %w: %d", errors.ErrNotPositive, c.MaxEntries)
+	}
+
+	return nil
+}
+
+// auditEntry is a record about a state-changing request to the control API.
+type auditEntry struct {
+	// Time is the time of the start of the request.
+	Time time.Time `json:"time"`
+
+	// User is the login of the web user, who made the request.  It's empty if
+	// there are no users.
+	User string `json:"user,omitempty"`
+
+	// Method is the HTTP method of the request.
+	Method string `json:"method"`
+
+	// Route is the route pattern of the request, like
+	// "/control/clients/{id}".
+	Route string `json:"route"`
+
+	// RemoteIP is the address of the web client.  It's empty if it's
+	// unknown, for example, for the requests received on the Unix socket.
+	RemoteIP string `json:"remote_ip,omitempty"`
+
+	// Changes are the changes of the configuration file made by the request.
+	// The values of the secrets are redacted.
+	Changes []*configChange `json:"changes,omitempty"`
+
+	// Status is the HTTP status code of the response.
+	Status int `json:"status"`
+}
+
+// auditRecord collects the changes of the configuration file made during an
+// audited request.  It's safe for concurrent use, since some handlers apply
+// the configuration in the background.
+type auditRecord struct {
+	// mu protects changes.
+	mu *sync.Mutex
+
+	// changes are the changes of the configuration file.
+	changes []*configChange
+}
+
+// add appends the changes to rec.
+func (rec *auditRecord) add(changes []*configChange) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	rec.changes = append(rec.changes, changes...)
+}
+
+// collected returns a copy of the collected changes.
+func (rec *auditRecord) collected() (changes []*configChange) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	return slices.Clone(rec.changes)
+}
+
+// auditLog is the persistent log of the state-changing requests to the control
+// API.  It's safe for concurrent use.
+type auditLog struct {
+	// mu protects entries, written, and the file.
+	mu *sync.Mutex
+
+	// path is the path to the file with the entries.  The log is only kept in
+	// memory if it's empty.
+	path string
+
+	// entries are the entries from the oldest to the newest.
+	entries []*auditEntry
+
+	// maxEntries is the maximum length of entries.
+	maxEntries int
+
+	// written is the number of the lines in the file.  The file is compacted
+	// once it's twice as long as maxEntries.
+	written int
+}
+
+// newAuditLog returns a new audit log stored in the file at path and loads the
+// existing entries.  l is nil if c is nil or disabled.
+func newAuditLog(path string, c *auditLogConfig) (l *auditLog, err error) {
+	if c == nil || !c.Enabled {
+		return nil, nil
+	}
+
+	l = &auditLog{
+		mu:         &sync.Mutex{},
+		path:       path,
+		maxEntries: c.MaxEntries,
+	}
+
+	if path == "" {
+		return l, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return l, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading audit log: %w", err)
+	}
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(nil, len(data)+1)
+	for sc.Scan() {
+		l.written++
+
+		e := &auditEntry{}
+		// Skip the lines, which may have been truncated by a crash.
+		if json.Unmarshal(sc.Bytes(), e) == nil {
+			l.entries = append(l.entries, e)
+		}
+	}
+
+	if n := len(l.entries) - l.maxEntries; n > 0 {
+		l.entries = slices.Delete(l.entries, 0, n)
+	}
+
+	return l, nil
+}
+
+// add appends e to the log and stores it.
+func (l *auditLog) add(e *auditEntry) (err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = append(l.entries, e)
+	if n := len(l.entries) - l.maxEntries; n > 0 {
+		l.entries = slices.Delete(l.entries, 0, n)
+	}
+
+	if l.path == "" {
+		return nil
+	}
+
+	if l.written >= 2*l.maxEntries {
+		return l.compact()
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encoding audit entry: %w", err)
+	}
+
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, aghos.DefaultPermFile)
+	if err != nil {
+		return fmt.Errorf("opening audit log: %w", err)
+	}
+	defer func() { err = errors.WithDeferred(err, f.Close()) }()
+
+	_, err = f.Write(append(line, '\n'))
+	if err != nil {
+		return fmt.Errorf("writing audit log: %w", err)
+	}
+
+	l.written++
+
+	return nil
+}
+
+// compact rewrites the file with the current entries only.  l.mu must be
+// locked.
+func (l *auditLog) compact() (err error) {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	for _, e := range l.entries {
+		err = enc.Encode(e)
+		if err != nil {
+			return fmt.Errorf("encoding audit entry: %w", err)
+		}
+	}
+
+	err = maybe.WriteFile(l.path, buf.Bytes(), aghos.DefaultPermFile)
+	if err != nil {
+		return fmt.Errorf("writing audit log: %w", err)
+	}
+
+	l.written = len(l.entries)
+
+	return nil
+}
+
+// auditLogQuery is the filter and the page of the audit log entries.  Empty
+// fields match all entries.
+type auditLogQuery struct {
+	Since time.Time
+
+	// User is the login of the web user.
+	User string
+
+	// Route is the prefix of the route pattern.
+	Route string
+
+	Offset int
+	Limit  int
+}
+
+// matches returns true if e matches q.
+func (q *auditLogQuery) matches(e *auditEntry) (ok bool) {
+	return (q.Since.IsZero() || !e.Time.Before(q.Since)) &&
+		(q.User == "" || e.User == q.User) &&
+		strings.HasPrefix(e.Route, q.Route)
+}
+
+// query returns the page of the entries matching q from the newest to the
+// oldest and the total number of the matching entries.
+func (l *auditLog) query(q *auditLogQuery) (page []*auditEntry, total int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	page = []*auditEntry{}
+	for _, e := range slices.Backward(l.entries) {
+		if !q.matches(e) {
+			continue
+		}
+
+		if total >= q.Offset && (q.Limit <= 0 || len(page) < q.Limit) {
+			page = append(page, e)
+		}
+
+		total++
+	}
+
+	return page, total
+}
+
+// serveAudited serves the state-changing request r with h and records it in
+// the audit log, if it's enabled.
+func (web *webAPI) serveAudited(w http.ResponseWriter, r *http.Request, h http.HandlerFunc) {
+	l := web.auditLog
+	if l == nil {
+		h(w, r)
+
+		return
+	}
+
+	ctx := r.Context()
+	e := &auditEntry{
+		Time:   time.Now().UTC(),
+		Method: r.Method,
+		Route:  cmp.Or(r.Pattern, r.URL.Path),
+	}
+
+	if u, ok := webUserFromContext(ctx); ok {
+		e.User = string(u.Login)
+	}
+
+	if ip, ok := remoteIPFromContext(ctx); ok {
+		e.RemoteIP = ip.String()
+	}
+
+	rec := &auditRecord{
+		mu: &sync.Mutex{},
+	}
+	sw := &aghhttp.StatusRecorder{ResponseWriter: w}
+
+	h(sw, r.WithContext(withAuditRecord(ctx, rec)))
+
+	e.Changes = rec.collected()
+	e.Status = sw.Status()
+
+	err := l.add(e)
+	if err != nil {
+		web.logger.ErrorContext(ctx, "recording audit entry", slogutil.KeyError, err)
+	}
+}
+
+// recordConfigChange adds the difference between the configuration file
+// contents prev and the current contents of the file at confPath to rec.
+func (cm *defaultConfigModifier) recordConfigChange(
+	ctx context.Context,
+	rec *auditRecord,
+	prev []byte,
+	confPath string,
+) {
+	cur, err := os.ReadFile(confPath)
+	if err != nil {
+		cm.logger.DebugContext(ctx, "reading config for audit", slogutil.KeyError, err)
+
+		return
+	}
+
+	changes, err := configDiff(prev, cur)
+	if err != nil {
+		cm.logger.DebugContext(ctx, "comparing configs", slogutil.KeyError, err)
+
+		return
+	}
+
+	for _, c := range changes {
+		c.Before = redactSecrets(c.Key, c.Before)
+		c.After = redactSecrets(c.Key, c.After)
+	}
+
+	rec.add(changes)
+}
+
+// redactedValue replaces the values of the secrets in the audit log.
+const redactedValue = "<redacted>"
+
+// redactSecrets returns a copy of the decoded configuration value v at the
+// dotted path with the values of the secrets, as defined by
+// [isSecretConfigValue], replaced.
+func redactSecrets(path string, v any) (redacted any) {
+	if v == nil {
+		return nil
+	}
+
+	s, _ := v.(string)
+	if isSecretConfigValue(path, s) {
+		return redactedValue
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, val := range v {
+			m[k] = redactSecrets(joinConfigPath(path, k), val)
+		}
+
+		return m
+	case []any:
+		items := make([]any, 0, len(v))
+		for _, val := range v {
+			items = append(items, redactSecrets(joinConfigPath(path, "[]"), val))
+		}
+
+		return items
+	default:
+		return v
+	}
+}
+
+// Audit log page limits.
+const (
+	defaultAuditLogLimit = 50
+	maxAuditLogLimit     = 500
+)
+
+// auditLogResp is the response of the GET /control/audit_log HTTP API.
+type auditLogResp struct {
+	Entries []*auditEntry `json:"entries"`
+	Total   int           `json:"total"`
+}
+
+// registerAuditLogHandlers registers the HTTP handlers of the audit log.
+func (web *webAPI) registerAuditLogHandlers() {
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodGet,
+		"/control/audit_log",
+		web.handleGetAuditLog,
+		&aghhttp.RouteInfo{
+			Summary: "Get the audit log of the state-changing requests",
+			Description: "The entries are returned from the newest to the oldest.  The " +
+				"\"limit\", \"offset\", \"user\", \"route\", and \"since\" query " +
+				"parameters filter them.",
+			Response: auditLogResp{},
+		},
+	)
+}
+
+// handleGetAuditLog is the handler for the GET /control/audit_log HTTP API.
+func (web *webAPI) handleGetAuditLog(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if web.auditLog == nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusServiceUnavailable, "audit log disabled")
+
+		return
+	}
+
+	q, err := parseAuditLogQuery(r.URL.Query())
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusBadRequest, "%s", err)
+
+		return
+	}
+
+	resp := &auditLogResp{}
+	resp.Entries, resp.Total = web.auditLog.query(q)
+
+	aghhttp.WriteJSONResponseOK(ctx, web.logger, w, r, resp)
+}
+
+// parseAuditLogQuery parses the audit log query from the URL parameters.
+func parseAuditLogQuery(params url.Values) (q *auditLogQuery, err error) {
+	q = &auditLogQuery{
+		User:  params.Get("user"),
+		Route: params.Get("route"),
+		Limit: defaultAuditLogLimit,
+	}
+
+	for name, dst := range map[string]*int{"limit": &q.Limit, "offset": &q.Offset} {
+		v := params.Get(name)
+		if v == "" {
+			continue
+		}
+
+		*dst, err = strconv.Atoi(v)
+		if err != nil || *dst < 0 {
+			return nil, fmt.Errorf("%s: bad value %q", name, v)
+		}
+	}
+
+	if q.Limit == 0 || q.Limit > maxAuditLogLimit {
+		q.Limit = maxAuditLogLimit
+	}
+
+	if since := params.Get("since"); since != "" {
+		q.Since, err = time.Parse(time.RFC3339Nano, since)
+		if err != nil {
+			return nil, fmt.Errorf("since: %w", err)
+		}
+	}
+
+	return q, nil
+}
@@ -30,6 +30,10 @@ type webUser struct {
 	// PasswordHash is the hashed representation of the web user password.
 	PasswordHash string `yaml:"password"`
 
+	// TOTP is the two-factor authentication configuration of the web user.  If
+	// it's nil, the two-factor authentication is disabled.
+	TOTP *totpConfig `yaml:"totp,omitempty"`
+
 	// UserID is the unique identifier of the web user.
 	UserID aghuser.UserID `yaml:"-"`
 }
@@ -78,9 +82,16 @@ type authConfig struct {
 	// users contains web user information from the configuration file.
 	users []webUser
 
+	// apiTokens are the API tokens from the configuration file.
+	apiTokens []*apiToken
+
 	// sessionTTL is the TTL (Time To Live) for web user sessions.
 	sessionTTL time.Duration
 
+	// sessionIdleTimeout is the duration of inactivity, after which a web user
+	// session expires.  Zero means no idle timeout.
+	sessionIdleTimeout time.Duration
+
 	// isGLiNet indicates whether GLiNet mode is enabled.
 	isGLiNet bool
 }
@@ -109,6 +120,15 @@ type auth struct {
 	// users stores user credentials.
 	users aghuser.DB
 
+	// apiTokens stores the API tokens.
+	apiTokens *apiTokenStore
+
+	// csrf verifies the CSRF tokens of the web UI.
+	csrf *csrfProtector
+
+	// totp stores the two-factor authentication configurations of the users.
+	totp *totpStore
+
 	// doHRoutes is a list of DoH routes for public access.
 	doHRoutes []string
 
@@ -130,12 +150,23 @@ func newAuth(ctx context.Context, conf *authConfig) (a *auth, err error) {
 		}
 	}
 
+	tokens, err := newAPITokenStore(conf.apiTokens)
+	if err != nil {
+		return nil, fmt.Errorf("api_tokens: %w", err)
+	}
+
+	totp, err := newTOTPStore(conf.users)
+	if err != nil {
+		return nil, fmt.Errorf("users: totp: %w", err)
+	}
+
 	s, err := aghuser.NewDefaultSessionStorage(ctx, &aghuser.DefaultSessionStorageConfig{
-		Logger:     conf.baseLogger.With(slogutil.KeyPrefix, "session_storage"),
-		Clock:      timeutil.SystemClock{},
-		UserDB:     userDB,
-		DBPath:     conf.dbFilename,
-		SessionTTL: conf.sessionTTL,
+		Logger:      conf.baseLogger.With(slogutil.KeyPrefix, "session_storage"),
+		Clock:       timeutil.SystemClock{},
+		UserDB:      userDB,
+		DBPath:      conf.dbFilename,
+		SessionTTL:  conf.sessionTTL,
+		IdleTimeout: conf.sessionIdleTimeout,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("creating session storage: %w", err)
@@ -149,6 +180,9 @@ func newAuth(ctx context.Context, conf *authConfig) (a *auth, err error) {
 		gliNetTokenRoot: conf.gliNetTokenRoot,
 		sessions:        s,
 		users:           userDB,
+		apiTokens:       tokens,
+		csrf:            newCSRFProtector(),
+		totp:            totp,
 		doHRoutes:       conf.doHRoutes,
 		isGLiNet:        conf.isGLiNet,
 		isUserless:      len(conf.users) == 0,
@@ -176,6 +210,9 @@ func (a *auth) middleware() (mw httputil.Middleware) {
 		trustedProxies: a.trustedProxies,
 		sessions:       a.sessions,
 		users:          a.users,
+		apiTokens:      a.apiTokens,
+		csrf:           a.csrf,
+		totp:           a.totp,
 		doHRoutes:      a.doHRoutes,
 	})
 }
@@ -193,6 +230,7 @@ func (a *auth) usersList(ctx context.Context) (webUsers []webUser) {
 		webUsers = append(webUsers, webUser{
 			Name:         string(u.Login),
 			PasswordHash: string(u.Password.Hash()),
+			TOTP:         a.totp.config(u.Login),
 			UserID:       u.ID,
 		})
 	}
@@ -35,6 +35,10 @@ const sessionCookieName = "agh_session"
 type loginJSON struct {
 	Name     string `json:"name"`
 	Password string `json:"password"`
+
+	// TOTP is the two-factor authentication code or a backup code.  It's only
+	// required for the users with the enabled two-factor authentication.
+	TOTP string `json:"totp,omitempty"`
 }
 
 // realIP extracts the real IP address of the client from an HTTP request using
@@ -179,7 +183,7 @@ func (web *webAPI) handleLogin(w http.ResponseWriter, r *http.Request) {
 		logIP = ip.String()
 	}
 
-	cookie, err := newCookie(ctx, web.auth, req, remoteIPStr)
+	cookie, usedBackup, err := newCookie(ctx, web.auth, req, remoteIPStr)
 	if err != nil {
 		web.writeErrorWithIP(ctx, err, r, w, http.StatusForbidden, logIP)
 
@@ -188,6 +192,11 @@ func (web *webAPI) handleLogin(w http.ResponseWriter, r *http.Request) {
 
 	web.logger.InfoContext(ctx, "successful login", "user", req.Name, "ip", logIP)
 
+	if usedBackup {
+		web.logger.InfoContext(ctx, "totp backup code used", "user", req.Name)
+		web.confModifier.Apply(ctx)
+	}
+
 	http.SetCookie(w, cookie)
 
 	h := w.Header()
@@ -198,13 +207,15 @@ func (web *webAPI) handleLogin(w http.ResponseWriter, r *http.Request) {
 	aghhttp.OK(ctx, web.logger, w)
 }
 
-// newCookie creates a new authentication cookie.  rateLimiter must not be nil.
+// newCookie creates a new authentication cookie.  usedBackup is true if the user
+// has logged in with a two-factor authentication backup code, which has been
+// consumed.  rateLimiter must not be nil.
 func newCookie(
 	ctx context.Context,
 	auth *auth,
 	req loginJSON,
 	addr string,
-) (c *http.Cookie, err error) {
+) (c *http.Cookie, usedBackup bool, err error) {
 	user, err := auth.users.ByLogin(ctx, aghuser.Login(req.Name))
 	if err != nil {
 		// Should not happen.
@@ -215,21 +226,34 @@ func newCookie(
 	if user == nil {
 		rateLimiter.inc(addr)
 
-		return nil, errInvalidLogin
+		return nil, false, errInvalidLogin
 	}
 
 	ok := user.Password.Authenticate(ctx, req.Password)
 	if !ok {
 		rateLimiter.inc(addr)
 
-		return nil, errInvalidLogin
+		return nil, false, errInvalidLogin
+	}
+
+	if auth.totp.isEnabled(user.Login) {
+		if req.TOTP == "" {
+			return nil, false, errTOTPRequired
+		}
+
+		ok, usedBackup = auth.totp.verify(user.Login, req.TOTP, time.Now())
+		if !ok {
+			rateLimiter.inc(addr)
+
+			return nil, false, errTOTPInvalid
+		}
 	}
 
 	rateLimiter.remove(addr)
 
 	sess, err := auth.sessions.New(ctx, user)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	return &http.Cookie{
@@ -239,7 +263,7 @@ func newCookie(
 		Expires:  time.Now().Add(cookieTTL),
 		HttpOnly: true,
 		SameSite: http.SameSiteLaxMode,
-	}, nil
+	}, usedBackup, nil
 }
 
 // handleLogout is the handler for the GET /control/logout HTTP API.
@@ -365,6 +389,18 @@ type authMiddlewareDefaultConfig struct {
 	// users contains web user information.  It must not be nil.
 	users aghuser.DB
 
+	// apiTokens contains the API tokens.  It must not be nil.
+	apiTokens *apiTokenStore
+
+	// csrf verifies the CSRF tokens of the requests authenticated with the
+	// session cookie.  If it's nil, the tokens aren't verified.
+	csrf *csrfProtector
+
+	// totp stores the two-factor authentication configurations of the users.
+	// The users with the enabled two-factor authentication can't use the basic
+	// authentication.  If it's nil, no user has it enabled.
+	totp *totpStore
+
 	// doHRoutes is a list of DoH routes for public access.
 	doHRoutes []string
 }
@@ -379,6 +415,9 @@ type authMiddlewareDefault struct {
 	trustedProxies netutil.SubnetSet
 	sessions       aghuser.SessionStorage
 	users          aghuser.DB
+	apiTokens      *apiTokenStore
+	csrf           *csrfProtector
+	totp           *totpStore
 	doHRoutes      []string
 }
 
@@ -392,6 +431,9 @@ func newAuthMiddlewareDefault(c *authMiddlewareDefaultConfig) (mw *authMiddlewar
 		trustedProxies: c.trustedProxies,
 		sessions:       c.sessions,
 		users:          c.users,
+		apiTokens:      c.apiTokens,
+		csrf:           c.csrf,
+		totp:           c.totp,
 		doHRoutes:      c.doHRoutes,
 	}
 }
@@ -404,6 +446,10 @@ var _ httputil.Middleware = (*authMiddlewareDefault)(nil)
 func (mw *authMiddlewareDefault) Wrap(h http.Handler) (wrapped http.Handler) {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+		if ip, err := realIP(r); err == nil {
+			ctx = withRemoteIP(ctx, ip)
+			r = r.WithContext(ctx)
+		}
 
 		if !mw.needsAuthentication(ctx) {
 			h.ServeHTTP(w, r)
@@ -412,6 +458,12 @@ func (mw *authMiddlewareDefault) Wrap(h http.Handler) (wrapped http.Handler) {
 		}
 
 		path := r.URL.Path
+		if secret, ok := bearerToken(r); ok {
+			mw.handleAPIToken(ctx, w, r, h, secret)
+
+			return
+		}
+
 		if mw.handleAuthenticatedUser(ctx, w, r, h, path) {
 			return
 		}
@@ -450,11 +502,92 @@ func (mw *authMiddlewareDefault) handleAuthenticatedUser(
 		return true
 	}
 
+	err = mw.checkCSRF(ctx, w, r)
+	if err != nil {
+		mw.logger.DebugContext(ctx, "checking csrf token", slogutil.KeyError, err)
+		http.Error(w, err.Error(), http.StatusForbidden)
+
+		return true
+	}
+
 	h.ServeHTTP(w, r.WithContext(withWebUser(ctx, u)))
 
 	return true
 }
 
+// checkCSRF verifies the CSRF token of r, if it's authenticated with the
+// session cookie.  r must be authenticated.
+func (mw *authMiddlewareDefault) checkCSRF(
+	ctx context.Context,
+	w http.ResponseWriter,
+	r *http.Request,
+) (err error) {
+	if isUnixSocket(ctx) {
+		return nil
+	}
+
+	// The session cookie takes precedence over the basic authentication, see
+	// [authMiddlewareDefault.userFromRequest].
+	c, err := r.Cookie(sessionCookieName)
+	if err != nil {
+		return nil
+	}
+
+	t, err := sessionTokenFromHex(c.Value)
+	if err != nil {
+		// Should not happen, since the user has been found by the token.
+		return err
+	}
+
+	return mw.csrf.protect(w, r, t)
+}
+
+// bearerTokenPrefix is the prefix of the value of the Authorization header with
+// a bearer token.
+const bearerTokenPrefix = "Bearer "
+
+// bearerToken returns the bearer token from the Authorization header of r, if
+// any.
+func bearerToken(r *http.Request) (token string, ok bool) {
+	token, ok = strings.CutPrefix(r.Header.Get(httphdr.Authorization), bearerTokenPrefix)
+
+	return strings.TrimSpace(token), ok
+}
+
+// handleAPIToken authenticates the request with the API token secret and
+// processes it, if the scope of the token allows it.
+func (mw *authMiddlewareDefault) handleAPIToken(
+	ctx context.Context,
+	w http.ResponseWriter,
+	r *http.Request,
+	h http.Handler,
+	secret string,
+) {
+	t := mw.apiTokens.bySecret(secret)
+	if t == nil {
+		mw.logger.DebugContext(ctx, "unknown api token")
+		w.WriteHeader(http.StatusUnauthorized)
+
+		return
+	}
+
+	u, err := mw.users.ByLogin(ctx, aghuser.Login(t.User))
+	if err != nil || u == nil {
+		mw.logger.DebugContext(ctx, "no user of api token", "id", t.ID, "user", t.User)
+		w.WriteHeader(http.StatusUnauthorized)
+
+		return
+	}
+
+	if !t.Scope.allows(r.Method, r.URL.Path) {
+		http.Error(w, fmt.Sprintf("api token scope %q does not allow this request", t.Scope), http.StatusForbidden)
+
+		return
+	}
+
+	h.ServeHTTP(w, r.WithContext(withWebUser(ctx, u)))
+}
+
 // handlePublicAccess handles request if user is trying to access public or root
 // pages.
 func (mw *authMiddlewareDefault) handlePublicAccess(
@@ -500,15 +633,17 @@ func (mw *authMiddlewareDefault) userFromRequest(
 
 	cookie, err := r.Cookie(sessionCookieName)
 	if err == nil {
-		return mw.userFromCookie(ctx, cookie.Value)
+		return mw.userFromCookie(ctx, r, cookie.Value)
 	}
 
 	return mw.userFromRequestBasicAuth(ctx, r)
 }
 
-// userFromCookie tries to retrieve a user based on the provided cookie value.
+// userFromCookie tries to retrieve a user based on the provided cookie value
+// and records the activity of the session.  r must not be nil.
 func (mw *authMiddlewareDefault) userFromCookie(
 	ctx context.Context,
+	r *http.Request,
 	val string,
 ) (u *aghuser.User, err error) {
 	t, err := sessionTokenFromHex(val)
@@ -529,6 +664,15 @@ func (mw *authMiddlewareDefault) userFromCookie(
 	u, err = mw.users.ByLogin(ctx, s.UserLogin)
 	if err != nil {
 		return nil, fmt.Errorf("searching user by login %q: %w", s.UserLogin, err)
+	} else if u == nil {
+		return nil, nil
+	}
+
+	ip, _ := remoteIPFromContext(ctx)
+	err = mw.sessions.Touch(ctx, t, ip, r.UserAgent())
+	if err != nil {
+		// Don't fail the request, since the activity is only informational.
+		mw.logger.DebugContext(ctx, "recording session activity", slogutil.KeyError, err)
 	}
 
 	return u, nil
@@ -597,5 +741,9 @@ func (mw *authMiddlewareDefault) userFromRequestBasicAuth(
 		return nil, errInvalidLogin
 	}
 
+	if mw.totp.isEnabled(user.Login) {
+		return nil, errTOTPBasicAuth
+	}
+
 	return user, nil
 }
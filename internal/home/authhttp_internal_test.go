@@ -45,7 +45,14 @@ type testSessionStorage struct {
 		t aghuser.SessionToken,
 	) (s *aghuser.Session, err error)
 	onDeleteByToken func(ctx context.Context, t aghuser.SessionToken) (err error)
-	onClose         func() (err error)
+	onAll           func(ctx context.Context) (sessions []*aghuser.Session, err error)
+	onTouch         func(
+		ctx context.Context,
+		t aghuser.SessionToken,
+		ip netip.Addr,
+		userAgent string,
+	) (err error)
+	onClose func() (err error)
 }
 
 // type check
@@ -67,6 +74,17 @@ func newTestSessionStorage() (ts *testSessionStorage) {
 		onDeleteByToken: func(ctx context.Context, t aghuser.SessionToken) (_ error) {
 			panic(testutil.UnexpectedCall(ctx, t))
 		},
+		onAll: func(ctx context.Context) (_ []*aghuser.Session, _ error) {
+			panic(testutil.UnexpectedCall(ctx))
+		},
+		onTouch: func(
+			ctx context.Context,
+			t aghuser.SessionToken,
+			ip netip.Addr,
+			userAgent string,
+		) (_ error) {
+			panic(testutil.UnexpectedCall(ctx, t, ip, userAgent))
+		},
 		onClose: func() (_ error) {
 			panic(testutil.UnexpectedCall())
 		},
@@ -100,6 +118,23 @@ func (ts *testSessionStorage) DeleteByToken(
 	return ts.onDeleteByToken(ctx, t)
 }
 
+// All implements the [aghuser.SessionStorage] interface for
+// *testSessionStorage.
+func (ts *testSessionStorage) All(ctx context.Context) (sessions []*aghuser.Session, err error) {
+	return ts.onAll(ctx)
+}
+
+// Touch implements the [aghuser.SessionStorage] interface for
+// *testSessionStorage.
+func (ts *testSessionStorage) Touch(
+	ctx context.Context,
+	t aghuser.SessionToken,
+	ip netip.Addr,
+	userAgent string,
+) (err error) {
+	return ts.onTouch(ctx, t, ip, userAgent)
+}
+
 // Close implements the [aghuser.SessionStorage] interface for
 // *testSessionStorage.
 func (ts *testSessionStorage) Close() (err error) {
@@ -206,6 +241,14 @@ func TestAuthMiddlewareDefault(t *testing.T) {
 	) (s *aghuser.Session, err error) {
 		return sessions[t], nil
 	}
+	ts.onTouch = func(
+		_ context.Context,
+		_ aghuser.SessionToken,
+		_ netip.Addr,
+		_ string,
+	) (err error) {
+		return nil
+	}
 
 	mw := newAuthMiddlewareDefault(&authMiddlewareDefaultConfig{
 		logger:      testLogger,
@@ -565,6 +608,11 @@ func TestAuth_ServeHTTP_auth(t *testing.T) {
 
 	loginCookie := generateAuthCookie(t, mux, testUsername, testPassword)
 
+	sessToken, err := sessionTokenFromHex(loginCookie.Value)
+	require.NoError(t, err)
+
+	csrfToken := auth.csrf.token(sessToken)
+
 	testCases := []struct {
 		name     string
 		path     string
@@ -613,6 +661,7 @@ func TestAuth_ServeHTTP_auth(t *testing.T) {
 
 			r = httptest.NewRequest(tc.method, tc.path, nil)
 			r.AddCookie(loginCookie)
+			r.Header.Set(csrfHeaderName, csrfToken)
 			assertHandlerStatusCode(t, mux, r, tc.wantCode)
 
 			r.AddCookie(&http.Cookie{Name: glCookieName, Value: "test"})
@@ -0,0 +1,461 @@
+package home
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghalg"
+	"github.com/AdguardTeam/AdGuardHome/internal/updater"
+	"github.com/AdguardTeam/AdGuardHome/internal/version"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+	"github.com/AdguardTeam/golibs/netutil"
+	"github.com/AdguardTeam/golibs/netutil/urlutil"
+	"github.com/AdguardTeam/golibs/osutil/executil"
+	"github.com/AdguardTeam/golibs/timeutil"
+)
+
+// autoUpdateConfig is the configuration of the scheduled automatic updates.
+type autoUpdateConfig struct {
+	// Enabled defines if AdGuard Home should update itself automatically
+	// during the maintenance window.
+	Enabled bool `yaml:"enabled"`
+
+	// Days are the English names of the weekdays on which the maintenance
+	// window starts.  If empty, the window starts every day.
+	Days []string `yaml:"days"`
+
+	// WindowStart is the local start time of the maintenance window in the
+	// "15:04" format.
+	WindowStart string `yaml:"window_start"`
+
+	// WindowEnd is the local end time of the maintenance window in the
+	// "15:04" format.
+	WindowEnd string `yaml:"window_end"`
+
+	// HealthCheckTimeout is the time the updated version has to become
+	// healthy before it's rolled back to the previous one.
+	HealthCheckTimeout timeutil.Duration `yaml:"health_check_timeout"`
+
+	// Channel is the update channel, see [updater.ChannelStable] and the
+	// related constants.
+	Channel string `yaml:"channel"`
+}
+
+// Default values of the automatic update configuration.
+const (
+	defaultAutoUpdateWindowStart   = "03:00"
+	defaultAutoUpdateWindowEnd     = "04:00"
+	defaultAutoUpdateHealthTimeout = 5 * time.Minute
+)
+
+// defaultAutoUpdateConfig returns the default automatic update configuration.
+func defaultAutoUpdateConfig() (c *autoUpdateConfig) {
+	return &autoUpdateConfig{
+		Enabled:            false,
+		Days:               []string{time.Sunday.String()},
+		WindowStart:        defaultAutoUpdateWindowStart,
+		WindowEnd:          defaultAutoUpdateWindowEnd,
+		HealthCheckTimeout: timeutil.Duration(defaultAutoUpdateHealthTimeout),
+		Channel:            defaultUpdateChannel(),
+	}
+}
+
+// defaultUpdateChannel returns the update channel matching the channel of the
+// current build.
+func defaultUpdateChannel() (ch string) {
+	switch version.Channel() {
+	case version.ChannelBeta:
+		return updater.ChannelBeta
+	case version.ChannelEdge:
+		return updater.ChannelEdge
+	default:
+		return updater.ChannelStable
+	}
+}
+
+// applyDefaults fills in zero-valued fields of c that must never be empty.
+// c may be nil.
+func (c *autoUpdateConfig) applyDefaults() {
+	if c == nil {
+		return
+	}
+
+	if c.WindowStart == "" {
+		c.WindowStart = defaultAutoUpdateWindowStart
+	}
+
+	if c.WindowEnd == "" {
+		c.WindowEnd = defaultAutoUpdateWindowEnd
+	}
+
+	if c.HealthCheckTimeout <= 0 {
+		c.HealthCheckTimeout = timeutil.Duration(defaultAutoUpdateHealthTimeout)
+	}
+
+	if c.Channel == "" {
+		c.Channel = defaultUpdateChannel()
+	}
+}
+
+// validate returns an error if c is invalid.  c may be nil.
+func (c *autoUpdateConfig) validate() (err error) {
+	if c == nil {
+		return nil
+	}
+
+	return updater.ValidateChannel(c.Channel)
+}
+
+// Automatic update timings.
+const (
+	// autoUpdateCheckInterval is the interval between the checks of whether
+	// the maintenance window has come.
+	autoUpdateCheckInterval = 10 * time.Minute
+
+	// healthCheckInterval is the interval between the health checks of the
+	// updated version.
+	healthCheckInterval = 1 * time.Second
+
+	// maxUpdateStartAttempts is the number of starts the updated version is
+	// allowed to make without passing the health check.
+	maxUpdateStartAttempts = 1
+)
+
+// updateDataFiles returns the paths to the databases to back up before an
+// update.  conf must not be nil.
+func updateDataFiles(conf *configuration, workDir string) (paths []string) {
+	dataDirPath := filepath.Join(workDir, dataDir)
+	statsDir := conf.Stats.DirPath
+	if statsDir == "" {
+		statsDir = dataDirPath
+	}
+
+	return []string{
+		filepath.Join(dataDirPath, sessionsDBName),
+		filepath.Join(statsDir, "stats.db"),
+	}
+}
+
+// checkPendingUpdate handles the update installed by the previous run, if any.
+// If the update has already failed to start, it's rolled back and AdGuard Home
+// is restarted.  verify is true if the running version must pass the health
+// check.  l, upd, and cmdCons must not be nil.
+func checkPendingUpdate(
+	ctx context.Context,
+	l *slog.Logger,
+	upd *updater.Updater,
+	cmdCons executil.CommandConstructor,
+	runningAsService bool,
+) (verify bool) {
+	pu, err := upd.PendingUpdate(ctx)
+	if err != nil {
+		l.ErrorContext(ctx, "checking pending update", slogutil.KeyError, err)
+
+		return false
+	} else if pu == nil {
+		return false
+	}
+
+	if pu.ToVersion != version.Version() {
+		l.WarnContext(
+			ctx,
+			"discarding stale update state",
+			"to", pu.ToVersion,
+			"running", version.Version(),
+		)
+		confirmUpdate(ctx, l, upd)
+
+		return false
+	}
+
+	if pu.Attempts <= maxUpdateStartAttempts {
+		l.InfoContext(ctx, "verifying update", "from", pu.FromVersion, "to", pu.ToVersion)
+
+		return true
+	}
+
+	l.ErrorContext(ctx, "updated version failed to start", "attempts", pu.Attempts)
+	rollbackUpdate(ctx, l, upd, cmdCons, runningAsService, false)
+
+	return false
+}
+
+// verifyUpdate waits for the updated version to become healthy, that is to run
+// the DNS server and to serve the web API, and rolls it back if it doesn't
+// within timeout.  It is intended to be used as a goroutine.  l, web, upd, and
+// cmdCons must not be nil.
+func verifyUpdate(
+	ctx context.Context,
+	l *slog.Logger,
+	web *webAPI,
+	upd *updater.Updater,
+	cmdCons executil.CommandConstructor,
+	timeout time.Duration,
+	runningAsService bool,
+) {
+	defer slogutil.RecoverAndLog(ctx, l)
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline.C:
+			l.ErrorContext(ctx, "updated version failed health check", "timeout", timeout)
+			rollbackUpdate(ctx, l, upd, cmdCons, runningAsService, true)
+
+			return
+		case <-ticker.C:
+			if !isRunning() {
+				continue
+			}
+
+			err := web.probe(ctx)
+			if err != nil {
+				l.DebugContext(ctx, "probing web api", slogutil.KeyError, err)
+
+				continue
+			}
+
+			confirmUpdate(ctx, l, upd)
+
+			return
+		}
+	}
+}
+
+// probe returns an error if the web API of web doesn't respond to an HTTP
+// request.  It's probed on the Unix socket if the TCP servers are disabled.
+func (web *webAPI) probe(ctx context.Context) (err error) {
+	if web.tcpDisabled() {
+		return probeUnixSocket(ctx, web.conf.unixSocket.socketPath(web.conf.workDir))
+	}
+
+	config.RLock()
+	addr := config.HTTPConfig.Address
+	config.RUnlock()
+
+	return probeWeb(ctx, addr)
+}
+
+// probeWeb returns an error if the web API doesn't respond to an HTTP request
+// on addr.  The unspecified addresses are probed on the loopback interface.
+func probeWeb(ctx context.Context, addr netip.AddrPort) (err error) {
+	ip := addr.Addr()
+	if ip.IsUnspecified() {
+		ip = netutil.IPv4Localhost()
+		if addr.Addr().Is6() {
+			ip = netutil.IPv6Localhost()
+		}
+	}
+
+	u := &url.URL{
+		Scheme: urlutil.SchemeHTTP,
+		Host:   netip.AddrPortFrom(ip, addr.Port()).String(),
+		Path:   "/",
+	}
+
+	return probeHTTP(ctx, http.DefaultTransport, u)
+}
+
+// probeUnixSocket returns an error if the web API doesn't respond to an HTTP
+// request on the Unix socket at sockPath.
+func probeUnixSocket(ctx context.Context, sockPath string) (err error) {
+	tr := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (conn net.Conn, err error) {
+			d := &net.Dialer{}
+
+			return d.DialContext(ctx, "unix", sockPath)
+		},
+	}
+	defer tr.CloseIdleConnections()
+
+	u := &url.URL{
+		Scheme: urlutil.SchemeHTTP,
+		Host:   "localhost",
+		Path:   "/",
+	}
+
+	return probeHTTP(ctx, tr, u)
+}
+
+// probeHTTP returns an error if there is no response to an HTTP request to u
+// sent using tr.  Any response, including a redirect or an error status, means
+// that the web API is serving.
+func probeHTTP(ctx context.Context, tr http.RoundTripper, u *url.URL) (err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u.String(), http.NoBody)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	client := &http.Client{
+		Transport: tr,
+		CheckRedirect: func(_ *http.Request, _ []*http.Request) (err error) {
+			return http.ErrUseLastResponse
+		},
+		Timeout: healthCheckInterval,
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// confirmUpdate marks the pending update as verified.  l and upd must not be
+// nil.
+func confirmUpdate(ctx context.Context, l *slog.Logger, upd *updater.Updater) {
+	err := upd.Confirm(ctx)
+	if err != nil {
+		l.ErrorContext(ctx, "confirming update", slogutil.KeyError, err)
+	}
+}
+
+// rollbackUpdate restores the previous version and restarts AdGuard Home.  If
+// started is true, all the running modules are stopped first.  l, upd, and
+// cmdCons must not be nil.
+func rollbackUpdate(
+	ctx context.Context,
+	l *slog.Logger,
+	upd *updater.Updater,
+	cmdCons executil.CommandConstructor,
+	runningAsService bool,
+	started bool,
+) {
+	// Retain the current absolute path of the executable, since the rollback
+	// moves the backup in its place.
+	execPath, err := os.Executable()
+	if err != nil {
+		l.ErrorContext(ctx, "getting executable path", slogutil.KeyError, err)
+
+		return
+	}
+
+	err = upd.Rollback(ctx)
+	if err != nil {
+		l.ErrorContext(ctx, "rolling back update", slogutil.KeyError, err)
+
+		return
+	}
+
+	if started {
+		finishUpdate(ctx, l, cmdCons, execPath, runningAsService)
+	} else {
+		restart(ctx, l, cmdCons, execPath, runningAsService)
+	}
+}
+
+// runAutoUpdate performs the update to the latest version during the
+// maintenance window.  It is intended to be used as a goroutine.
+func (web *webAPI) runAutoUpdate(ctx context.Context) {
+	defer slogutil.RecoverAndLog(ctx, web.logger)
+
+	if web.conf.disableUpdate {
+		return
+	}
+
+	ticker := time.NewTicker(autoUpdateCheckInterval)
+	defer ticker.Stop()
+
+	var lastAttempt time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			lastAttempt = web.autoUpdateInWindow(ctx, lastAttempt)
+		}
+	}
+}
+
+// autoUpdateInWindow performs the automatic update, if it's enabled and the
+// maintenance window has come.  lastAttempt is the time of the previous
+// attempt, and attempt is the time of the latest one.
+func (web *webAPI) autoUpdateInWindow(
+	ctx context.Context,
+	lastAttempt time.Time,
+) (attempt time.Time) {
+	config.RLock()
+	c := *config.AutoUpdate
+	config.RUnlock()
+
+	if !c.Enabled {
+		return lastAttempt
+	}
+
+	w, err := updater.ParseMaintenanceWindow(c.Days, c.WindowStart, c.WindowEnd)
+	if err != nil {
+		web.logger.ErrorContext(ctx, "parsing maintenance window", slogutil.KeyError, err)
+
+		return lastAttempt
+	}
+
+	// Only make a single attempt during each window and don't retry failed
+	// updates until the next one.
+	now := time.Now()
+	if !w.Contains(now) || (w.Contains(lastAttempt) && now.Sub(lastAttempt) < 24*time.Hour) {
+		return lastAttempt
+	}
+
+	err = web.autoUpdate(ctx)
+	if err != nil {
+		web.logger.ErrorContext(ctx, "automatic update", slogutil.KeyError, err)
+	}
+
+	return now
+}
+
+// autoUpdate updates AdGuard Home to the latest version, if there is one, and
+// restarts it.
+func (web *webAPI) autoUpdate(ctx context.Context) (err error) {
+	resp := &versionResponse{}
+	err = web.requestVersionInfo(ctx, resp, true)
+	if err != nil {
+		// Don't wrap the error, because it's informative enough as is.
+		return err
+	}
+
+	err = resp.setAllowedToAutoUpdate(ctx, web.logger, web.tlsManager)
+	if err != nil {
+		// Don't wrap the error, because it's informative enough as is.
+		return err
+	}
+
+	if resp.CanAutoUpdate != aghalg.NBTrue {
+		web.logger.DebugContext(ctx, "no automatic update available", "version", resp.NewVersion)
+
+		return nil
+	}
+
+	execPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("getting executable path: %w", err)
+	}
+
+	web.logger.InfoContext(ctx, "updating automatically", "to", resp.NewVersion)
+
+	err = web.conf.updater.Update(ctx, false)
+	if err != nil {
+		return fmt.Errorf("updating: %w", err)
+	}
+
+	finishUpdate(ctx, web.logger, web.cmdCons, execPath, web.conf.runningAsService)
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package home
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"path/filepath"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbeWeb(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://example.org/", http.StatusFound)
+	}))
+	t.Cleanup(srv.Close)
+
+	addr, err := netip.ParseAddrPort(srv.Listener.Addr().String())
+	require.NoError(t, err)
+
+	ctx := testutil.ContextWithTimeout(t, testTimeout)
+
+	t.Run("serving", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NoError(t, probeWeb(ctx, addr))
+	})
+
+	t.Run("unspecified", func(t *testing.T) {
+		t.Parallel()
+
+		unspec := netip.AddrPortFrom(netip.IPv4Unspecified(), addr.Port())
+		assert.NoError(t, probeWeb(ctx, unspec))
+	})
+
+	t.Run("not_serving", func(t *testing.T) {
+		t.Parallel()
+
+		l, lErr := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, lErr)
+
+		closedAddr, pErr := netip.ParseAddrPort(l.Addr().String())
+		require.NoError(t, pErr)
+		require.NoError(t, l.Close())
+
+		assert.Error(t, probeWeb(ctx, closedAddr))
+	})
+}
+
+func TestWebAPI_probe_disableTCP(t *testing.T) {
+	t.Parallel()
+
+	workDir := t.TempDir()
+	web := &webAPI{
+		conf: &webAPIConfig{
+			unixSocket: &unixSocketConfig{
+				Path:       "agh.sock",
+				DisableTCP: true,
+			},
+			workDir: workDir,
+		},
+	}
+
+	ctx := testutil.ContextWithTimeout(t, testTimeout)
+
+	assert.Error(t, web.probe(ctx))
+
+	l, err := net.Listen("unix", filepath.Join(workDir, "agh.sock"))
+	require.NoError(t, err)
+
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}),
+	}
+	go func() { _ = srv.Serve(l) }()
+	testutil.CleanupAndRequireSuccess(t, srv.Close)
+
+	assert.NoError(t, web.probe(ctx))
+}
@@ -0,0 +1,155 @@
+package home
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/netutil/httputil"
+	"github.com/go-acme/lego/v4/challenge/http01"
+)
+
+// clientAuthConfig is the configuration of the authentication of the web
+// clients with the TLS client certificates.
+type clientAuthConfig struct {
+	// CAPath is the path to the PEM file with the certificates of the CAs,
+	// which issue the client certificates.  It must not be empty if Enabled is
+	// true.
+	CAPath string `yaml:"ca_path"`
+
+	// Enabled, if true, requires a client certificate issued by one of the CAs
+	// from CAPath for the requests to the web UI and the control API over
+	// HTTPS.  The plain HTTP requests to them are rejected, unless they are
+	// received on the Unix socket.  The DNS-over-HTTPS routes and the ACME
+	// challenges don't require the certificates.
+	Enabled bool `yaml:"enabled"`
+}
+
+// validate returns an error if c is invalid.  c may be nil.
+func (c *clientAuthConfig) validate() (err error) {
+	if c == nil || !c.Enabled {
+		return nil
+	}
+
+	if c.CAPath == "" {
+		return fmt.Errorf("ca_path: %w", errors.ErrEmptyValue)
+	}
+
+	return nil
+}
+
+// loadClientCAs returns the pool of the CAs from the PEM file at path.
+func loadClientCAs(path string) (pool *x509.CertPool, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		// Don't wrap the error, since it's informative enough as is.
+		return nil, err
+	}
+
+	pool = x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates in %q", path)
+	}
+
+	return pool, nil
+}
+
+// clientCertMiddleware requires the verified TLS client certificates for the
+// requests to the web UI and the control API.
+type clientCertMiddleware struct {
+	logger *slog.Logger
+	mux    *http.ServeMux
+
+	// cas are the CAs of the client certificates.  If it's nil, the
+	// middleware does nothing.
+	cas *x509.CertPool
+
+	// doHRoutes are the patterns of the DoH routes, which don't require the
+	// certificates.
+	doHRoutes []string
+}
+
+// newClientCertMiddleware returns a new properly initialized
+// *clientCertMiddleware.  c must be valid, it may be nil.  logger and mux must
+// not be nil.
+func newClientCertMiddleware(
+	logger *slog.Logger,
+	mux *http.ServeMux,
+	c *clientAuthConfig,
+	doHRoutes []string,
+) (mw *clientCertMiddleware, err error) {
+	mw = &clientCertMiddleware{
+		logger:    logger,
+		mux:       mux,
+		doHRoutes: doHRoutes,
+	}
+
+	if c == nil || !c.Enabled {
+		return mw, nil
+	}
+
+	mw.cas, err = loadClientCAs(c.CAPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading client cas: %w", err)
+	}
+
+	return mw, nil
+}
+
+// setTLSConfig sets the verification of the client certificates in conf, if
+// enabled.  The connections without the certificates are accepted, since the
+// DoH clients don't have them.
+func (mw *clientCertMiddleware) setTLSConfig(conf *tls.Config) {
+	if mw.cas == nil {
+		return
+	}
+
+	conf.ClientAuth = tls.VerifyClientCertIfGiven
+	conf.ClientCAs = mw.cas
+}
+
+// type check
+var _ httputil.Middleware = (*clientCertMiddleware)(nil)
+
+// Wrap implements the [httputil.Middleware] interface for
+// *clientCertMiddleware.
+func (mw *clientCertMiddleware) Wrap(h http.Handler) (wrapped http.Handler) {
+	if mw.cas == nil {
+		return h
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if mw.isExempt(r) {
+			h.ServeHTTP(w, r)
+
+			return
+		}
+
+		msg := "client certificate required"
+		if r.TLS == nil {
+			msg = "https with client certificate required"
+		}
+
+		aghhttp.ErrorAndLog(r.Context(), mw.logger, r, w, http.StatusForbidden, "%s", msg)
+	})
+}
+
+// isExempt returns true if r doesn't require a client certificate.
+func (mw *clientCertMiddleware) isExempt(r *http.Request) (ok bool) {
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		return true
+	} else if isUnixSocket(r.Context()) || strings.HasPrefix(r.URL.Path, http01.PathPrefix) {
+		return true
+	}
+
+	_, pattern := mw.mux.Handler(r)
+
+	return pattern != "" && slices.Contains(mw.doHRoutes, pattern)
+}
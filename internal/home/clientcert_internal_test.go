@@ -0,0 +1,135 @@
+package home
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/go-acme/lego/v4/challenge/http01"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientCertMiddleware(t *testing.T) {
+	certDER, _ := newCertAndKey(t, 1)
+
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	err := os.WriteFile(caPath, pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: certDER,
+	}), 0o600)
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	teapot := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	mux.Handle("GET /dns-query", teapot)
+	mux.Handle("/", teapot)
+
+	const doHRoute = "GET /dns-query"
+
+	mw, err := newClientCertMiddleware(
+		slog.New(slog.DiscardHandler),
+		mux,
+		&clientAuthConfig{CAPath: caPath, Enabled: true},
+		[]string{doHRoute},
+	)
+	require.NoError(t, err)
+
+	tlsConf := &tls.Config{}
+	mw.setTLSConfig(tlsConf)
+
+	assert.Equal(t, tls.VerifyClientCertIfGiven, tlsConf.ClientAuth)
+	assert.NotNil(t, tlsConf.ClientCAs)
+
+	h := mw.Wrap(mux)
+
+	verified := &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{}}}
+
+	testCases := []struct {
+		tls      *tls.ConnectionState
+		name     string
+		path     string
+		unix     bool
+		wantCode int
+	}{{
+		tls:      verified,
+		name:     "verified",
+		path:     "/control/status",
+		unix:     false,
+		wantCode: http.StatusTeapot,
+	}, {
+		tls:      &tls.ConnectionState{},
+		name:     "no_certificate",
+		path:     "/control/status",
+		unix:     false,
+		wantCode: http.StatusForbidden,
+	}, {
+		tls:      nil,
+		name:     "plain",
+		path:     "/control/status",
+		unix:     false,
+		wantCode: http.StatusForbidden,
+	}, {
+		tls:      nil,
+		name:     "unix_socket",
+		path:     "/control/status",
+		unix:     true,
+		wantCode: http.StatusTeapot,
+	}, {
+		tls:      &tls.ConnectionState{},
+		name:     "doh",
+		path:     "/dns-query",
+		unix:     false,
+		wantCode: http.StatusTeapot,
+	}, {
+		tls:      nil,
+		name:     "acme",
+		path:     http01.ChallengePath("token"),
+		unix:     false,
+		wantCode: http.StatusTeapot,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			r.TLS = tc.tls
+			if tc.unix {
+				r = r.WithContext(withUnixSocket(r.Context()))
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.wantCode, w.Code)
+		})
+	}
+}
+
+func TestNewClientCertMiddleware_errors(t *testing.T) {
+	badPath := filepath.Join(t.TempDir(), "bad.pem")
+	err := os.WriteFile(badPath, []byte("not a certificate"), 0o600)
+	require.NoError(t, err)
+
+	_, err = newClientCertMiddleware(
+		slog.New(slog.DiscardHandler),
+		http.NewServeMux(),
+		&clientAuthConfig{CAPath: badPath, Enabled: true},
+		nil,
+	)
+	testutil.AssertErrorMsg(t, `loading client cas: no certificates in "`+badPath+`"`, err)
+
+	mw, err := newClientCertMiddleware(slog.New(slog.DiscardHandler), http.NewServeMux(), nil, nil)
+	require.NoError(t, err)
+
+	h := http.RedirectHandler("/", http.StatusFound)
+	assert.Same(t, h, mw.Wrap(h))
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/AdguardTeam/AdGuardHome/internal/querylog"
 	"github.com/AdguardTeam/AdGuardHome/internal/schedule"
 	"github.com/AdguardTeam/AdGuardHome/internal/whois"
+	"github.com/AdguardTeam/golibs/container"
 	"github.com/AdguardTeam/golibs/errors"
 	"github.com/AdguardTeam/golibs/logutil/slogutil"
 	"github.com/AdguardTeam/golibs/timeutil"
@@ -130,7 +131,7 @@ func (clients *clientsContainer) Init(
 		DHCP:                   dhcpServer,
 		EtcHosts:               hosts,
 		ARPDB:                  arpDB,
-		ARPClientsUpdatePeriod: arpClientsUpdatePeriod,
+		ARPClientsUpdatePeriod: time.Duration(config.Clients.Sources.ARPRefreshInterval),
 		RuntimeSourceDHCP:      config.Clients.Sources.DHCP,
 	})
 	if err != nil {
@@ -169,10 +170,20 @@ type clientObject struct {
 
 	Name string `yaml:"name"`
 
+	// Notes is a free-form description of the client.
+	Notes string `yaml:"notes,omitempty"`
+
 	IDs       []string `yaml:"ids"`
 	Tags      []string `yaml:"tags"`
 	Upstreams []string `yaml:"upstreams"`
 
+	// CustomTags are the arbitrary user-defined tags of the client.
+	CustomTags []string `yaml:"custom_tags,omitempty"`
+
+	// WakeOnLANBroadcast is the broadcast address for Wake-on-LAN packets.  An
+	// empty string means the limited broadcast address.
+	WakeOnLANBroadcast string `yaml:"wol_broadcast,omitempty"`
+
 	// UID is the unique identifier of the persistent client.
 	UID client.UID `yaml:"uid"`
 
@@ -202,7 +213,8 @@ func (o *clientObject) toPersistent(
 	safeSearchCacheTTL time.Duration,
 ) (cli *client.Persistent, err error) {
 	cli = &client.Persistent{
-		Name: o.Name,
+		Name:  o.Name,
+		Notes: o.Notes,
 
 		Upstreams: o.Upstreams,
 
@@ -225,6 +237,13 @@ func (o *clientObject) toPersistent(
 		return nil, fmt.Errorf("parsing ids: %w", err)
 	}
 
+	if o.WakeOnLANBroadcast != "" {
+		cli.WakeOnLANBroadcast, err = netip.ParseAddr(o.WakeOnLANBroadcast)
+		if err != nil {
+			return nil, fmt.Errorf("parsing wol_broadcast: %w", err)
+		}
+	}
+
 	if (cli.UID == client.UID{}) {
 		cli.UID, err = client.NewUID()
 		if err != nil {
@@ -267,6 +286,7 @@ func (o *clientObject) toPersistent(
 	cli.BlockedServices = o.BlockedServices.Clone()
 
 	cli.Tags = slices.Clone(o.Tags)
+	cli.CustomTags = slices.Clone(o.CustomTags)
 
 	return cli, nil
 }
@@ -280,7 +300,8 @@ func (clients *clientsContainer) forConfig() (objs []*clientObject) {
 	objs = make([]*clientObject, 0, clients.storage.Size())
 	clients.storage.RangeByName(func(cli *client.Persistent) (cont bool) {
 		objs = append(objs, &clientObject{
-			Name: cli.Name,
+			Name:  cli.Name,
+			Notes: cli.Notes,
 
 			BlockedServices: cli.BlockedServices.Clone(),
 
@@ -288,6 +309,10 @@ func (clients *clientsContainer) forConfig() (objs []*clientObject) {
 			Tags:      slices.Clone(cli.Tags),
 			Upstreams: slices.Clone(cli.Upstreams),
 
+			CustomTags: slices.Clone(cli.CustomTags),
+
+			WakeOnLANBroadcast: addrString(cli.WakeOnLANBroadcast),
+
 			UID: cli.UID,
 
 			UseGlobalSettings:        !cli.UseOwnSettings,
@@ -308,8 +333,105 @@ func (clients *clientsContainer) forConfig() (objs []*clientObject) {
 	return objs
 }
 
-// arpClientsUpdatePeriod defines how often ARP clients are updated.
-const arpClientsUpdatePeriod = 10 * time.Minute
+// reload replaces the persistent clients with the ones from objs.  The clients
+// are matched by their names, and the ones missing from objs are removed.
+func (clients *clientsContainer) reload(ctx context.Context, objs []*clientObject) (err error) {
+	clients.lock.Lock()
+	defer clients.lock.Unlock()
+
+	persistent := make([]*client.Persistent, 0, len(objs))
+	names := container.NewMapSet[string]()
+	for i, o := range objs {
+		var p *client.Persistent
+		p, err = o.toPersistent(
+			ctx,
+			clients.baseLogger,
+			clients.safeSearchCacheSize,
+			clients.safeSearchCacheTTL,
+		)
+		if err != nil {
+			return fmt.Errorf("persistent client at index %d: %w", i, err)
+		}
+
+		persistent = append(persistent, p)
+		names.Add(p.Name)
+	}
+
+	var removed []string
+	clients.storage.RangeByName(func(c *client.Persistent) (cont bool) {
+		if !names.Has(c.Name) {
+			removed = append(removed, c.Name)
+		}
+
+		return true
+	})
+
+	// Remove the clients first, so that their identifiers can be reused.
+	for _, name := range removed {
+		clients.storage.RemoveByName(ctx, name)
+	}
+
+	var errs []error
+	for _, p := range persistent {
+		if _, ok := clients.storage.FindByName(p.Name); ok {
+			err = clients.storage.Update(ctx, p.Name, p)
+		} else {
+			err = clients.storage.Add(ctx, p)
+		}
+
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+const (
+	// errClientNotFound is returned by [clientsContainer.wake] when there is no
+	// persistent client with the given name.
+	errClientNotFound errors.Error = "client not found"
+
+	// errClientNoMAC is returned by [clientsContainer.wake] when the persistent
+	// client isn't identified by any MAC address.
+	errClientNoMAC errors.Error = "client has no mac address"
+)
+
+// wake sends a Wake-on-LAN magic packet to the persistent client with the given
+// name using its first MAC address and its broadcast address.
+func (clients *clientsContainer) wake(ctx context.Context, name string) (err error) {
+	cli, ok := clients.storage.FindByName(name)
+	if !ok {
+		return errClientNotFound
+	} else if len(cli.MACs) == 0 {
+		return errClientNoMAC
+	}
+
+	mac := cli.MACs[0]
+	bcast := netip.AddrPortFrom(cli.WakeOnLANBroadcast, aghnet.WakeOnLANPort)
+	err = aghnet.SendWakeOnLAN(ctx, mac, bcast)
+	if err != nil {
+		return fmt.Errorf("waking client %q: %w", name, err)
+	}
+
+	clients.logger.InfoContext(ctx, "sent wake-on-lan packet", "name", name, "mac", mac)
+
+	return nil
+}
+
+// addrString returns the string representation of addr or an empty string if
+// addr is not valid.
+func addrString(addr netip.Addr) (s string) {
+	if !addr.IsValid() {
+		return ""
+	}
+
+	return addr.String()
+}
+
+// defaultARPRefreshInterval defines how often ARP clients are updated, unless
+// configured otherwise.
+const defaultARPRefreshInterval = 10 * time.Minute
 
 // findMultiple is a wrapper around [clientsContainer.find] to make it a valid
 // client finder for the query log.  c is never nil; if no information about the
@@ -352,6 +474,8 @@ func (clients *clientsContainer) clientOrArtificial(
 	if ok {
 		return &querylog.Client{
 			Name:           cli.Name,
+			Notes:          cli.Notes,
+			Tags:           slices.Concat(cli.Tags, cli.CustomTags),
 			IgnoreQueryLog: cli.IgnoreQueryLog,
 		}, false
 	}
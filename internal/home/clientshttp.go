@@ -9,11 +9,13 @@ import (
 
 	"github.com/AdguardTeam/AdGuardHome/internal/aghalg"
 	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/aghnet"
 	"github.com/AdguardTeam/AdGuardHome/internal/client"
 	"github.com/AdguardTeam/AdGuardHome/internal/filtering"
 	"github.com/AdguardTeam/AdGuardHome/internal/filtering/safesearch"
 	"github.com/AdguardTeam/AdGuardHome/internal/schedule"
 	"github.com/AdguardTeam/AdGuardHome/internal/whois"
+	"github.com/AdguardTeam/golibs/errors"
 	"github.com/AdguardTeam/golibs/logutil/slogutil"
 )
 
@@ -43,12 +45,22 @@ type clientJSON struct {
 
 	Name string `json:"name"`
 
+	// Notes is a free-form description of the client.
+	Notes string `json:"notes"`
+
 	// BlockedServices is the names of blocked services.
 	BlockedServices []string `json:"blocked_services"`
 	IDs             []string `json:"ids"`
 	Tags            []string `json:"tags"`
 	Upstreams       []string `json:"upstreams"`
 
+	// CustomTags are the arbitrary user-defined tags of the client.
+	CustomTags []string `json:"custom_tags"`
+
+	// WakeOnLANBroadcast is the broadcast address for Wake-on-LAN packets.  An
+	// empty value means the limited broadcast address.
+	WakeOnLANBroadcast netip.Addr `json:"wol_broadcast"`
+
 	FilteringEnabled    bool `json:"filtering_enabled"`
 	ParentalEnabled     bool `json:"parental_enabled"`
 	SafeBrowsingEnabled bool `json:"safebrowsing_enabled"`
@@ -203,7 +215,10 @@ func (clients *clientsContainer) jsonToClient(
 
 	c.SafeSearchConf = copySafeSearch(cj.SafeSearchConf, cj.SafeSearchEnabled)
 	c.Name = cj.Name
+	c.Notes = cj.Notes
 	c.Tags = cj.Tags
+	c.CustomTags = cj.CustomTags
+	c.WakeOnLANBroadcast = cj.WakeOnLANBroadcast
 	c.Upstreams = cj.Upstreams
 	c.UseOwnSettings = !cj.UseGlobalSettings
 	c.FilteringEnabled = cj.FilteringEnabled
@@ -302,7 +317,9 @@ func clientToJSON(c *client.Persistent) (cj *clientJSON) {
 	return &clientJSON{
 		Name:                c.Name,
 		IDs:                 c.Identifiers(),
+		Notes:               c.Notes,
 		Tags:                c.Tags,
+		CustomTags:          c.CustomTags,
 		UseGlobalSettings:   !c.UseOwnSettings,
 		FilteringEnabled:    c.FilteringEnabled,
 		ParentalEnabled:     c.ParentalEnabled,
@@ -322,6 +339,8 @@ func clientToJSON(c *client.Persistent) (cj *clientJSON) {
 
 		UpstreamsCacheSize:    c.UpstreamsCacheSize,
 		UpstreamsCacheEnabled: aghalg.BoolToNullBool(c.UpstreamsCacheEnabled),
+
+		WakeOnLANBroadcast: c.WakeOnLANBroadcast,
 	}
 }
 
@@ -451,6 +470,32 @@ func (clients *clientsContainer) handleUpdateClient(w http.ResponseWriter, r *ht
 	clients.confModifier.Apply(ctx)
 }
 
+// handleWakeClient is the handler for POST /control/clients/{id}/wake HTTP API.
+// The id path segment is the name of the persistent client.
+func (clients *clientsContainer) handleWakeClient(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	l := clients.logger
+
+	name := r.PathValue("id")
+	if name == "" {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusBadRequest, "client's name must be non-empty")
+
+		return
+	}
+
+	err := clients.wake(ctx, name)
+	switch {
+	case err == nil:
+		aghhttp.OK(ctx, l, w)
+	case errors.Is(err, errClientNotFound):
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusNotFound, "%s", err)
+	case errors.Is(err, errClientNoMAC), errors.Is(err, aghnet.ErrBadWakeOnLANMAC):
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusUnprocessableEntity, "%s", err)
+	default:
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusInternalServerError, "%s", err)
+	}
+}
+
 // handleFindClient is the handler for GET /control/clients/find HTTP API.
 //
 // Deprecated:  Remove it when migration to the new API is over.
@@ -616,6 +661,8 @@ func (clients *clientsContainer) registerWebHandlers() {
 	clients.httpReg.Register(http.MethodPost, "/control/clients/update", clients.handleUpdateClient)
 	clients.httpReg.Register(http.MethodPost, "/control/clients/search", clients.handleSearchClient)
 
+	clients.httpReg.Register(http.MethodPost, "/control/clients/{id}/wake", clients.handleWakeClient)
+
 	// Deprecated handler.
 	clients.httpReg.Register(http.MethodGet, "/control/clients/find", clients.handleFindClient)
 }
@@ -540,3 +540,51 @@ func TestClientsContainer_HandleSearchClient(t *testing.T) {
 		})
 	}
 }
+
+func TestClientsContainer_HandleWakeClient(t *testing.T) {
+	clients := newClientsContainer(t)
+	ctx := testutil.ContextWithTimeout(t, testTimeout)
+
+	clientMAC := newPersistentClientWithIDs(t, "client_mac", []string{"aa:bb:cc:dd:ee:ff"})
+	clientMAC.WakeOnLANBroadcast = netip.MustParseAddr("127.0.0.1")
+	err := clients.storage.Add(ctx, clientMAC)
+	require.NoError(t, err)
+
+	clientIP := newPersistentClientWithIDs(t, "client_ip", []string{testClientIP1})
+	err = clients.storage.Add(ctx, clientIP)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		id       string
+		wantCode int
+	}{{
+		name:     "success",
+		id:       clientMAC.Name,
+		wantCode: http.StatusOK,
+	}, {
+		name:     "no_mac",
+		id:       clientIP.Name,
+		wantCode: http.StatusUnprocessableEntity,
+	}, {
+		name:     "not_found",
+		id:       "unknown",
+		wantCode: http.StatusNotFound,
+	}, {
+		name:     "empty_name",
+		id:       "",
+		wantCode: http.StatusBadRequest,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/control/clients/"+tc.id+"/wake", nil)
+			r.SetPathValue("id", tc.id)
+
+			rw := httptest.NewRecorder()
+			clients.handleWakeClient(rw, r)
+
+			assert.Equal(t, tc.wantCode, rw.Code)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package home
+
+import (
+	"compress/gzip"
+	"fmt"
+	"net/http"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/NYTimes/gziphandler"
+	"github.com/c2h5oh/datasize"
+)
+
+// compressionConfig is the configuration of the compression of the HTTP
+// responses.  Only gzip is currently supported.
+type compressionConfig struct {
+	// MinSize is the minimum size of a response to compress.  The smaller
+	// responses are sent as is, since the compression wouldn't pay off.
+	MinSize datasize.ByteSize `yaml:"min_size"`
+
+	// Level is the gzip compression level from 1, the fastest, to 9, the best
+	// compression.  Zero means the default level.
+	Level int `yaml:"level"`
+
+	// Enabled enables the gzip compression of the responses of the control API
+	// and of the frontend assets for the clients, which accept it.
+	Enabled bool `yaml:"enabled"`
+}
+
+// defaultCompressionMinSize is the default minimum size of a response to
+// compress.
+const defaultCompressionMinSize = gziphandler.DefaultMinSize * datasize.B
+
+// validate returns an error if c is invalid.  c may be nil.
+func (c *compressionConfig) validate() (err error) {
+	if c == nil {
+		return nil
+	}
+
+	var errs []error
+	if c.Level != 0 && (c.Level < gzip.BestSpeed || c.Level > gzip.BestCompression) {
+		errs = append(errs, fmt.Errorf(
+			"level: %w: must be from %d to %d, got %d",
+			errors.ErrOutOfRange,
+			gzip.BestSpeed,
+			gzip.BestCompression,
+			c.Level,
+		))
+	}
+
+	// The responses are buffered until they reach the minimum size.
+	if c.MinSize > datasize.GB {
+		errs = append(errs, fmt.Errorf("min_size: %w: %s", errors.ErrOutOfRange, c.MinSize))
+	}
+
+	return errors.Join(errs...)
+}
+
+// compressibleContentTypes are the content types of the responses, which are
+// compressed.  The others, like the images and the fonts, are usually already
+// compressed, and the event streams must not be buffered.
+var compressibleContentTypes = []string{
+	aghhttp.HdrValApplicationJSON,
+	aghhttp.HdrValTextPlain,
+	"application/javascript",
+	"application/manifest+json",
+	"application/xml",
+	"image/svg+xml",
+	"text/css",
+	"text/html",
+	"text/javascript",
+	"text/xml",
+}
+
+// newCompressionMiddleware returns the middleware compressing the responses
+// according to c.  c must be valid.  If c is nil, the responses are compressed
+// with the default settings.
+func newCompressionMiddleware(c *compressionConfig) (mw middleware, err error) {
+	if c == nil {
+		c = &compressionConfig{
+			MinSize: defaultCompressionMinSize,
+			Enabled: true,
+		}
+	}
+
+	if !c.Enabled {
+		return func(h http.Handler) (wrapped http.Handler) { return h }, nil
+	}
+
+	level := c.Level
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+
+	mw, err = gziphandler.GzipHandlerWithOpts(
+		gziphandler.CompressionLevel(level),
+		gziphandler.MinSize(int(c.MinSize.Bytes())),
+		gziphandler.ContentTypes(compressibleContentTypes),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating gzip handler: %w", err)
+	}
+
+	return mw, nil
+}
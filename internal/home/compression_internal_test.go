@@ -0,0 +1,136 @@
+package home
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/golibs/httphdr"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/c2h5oh/datasize"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressionMiddleware(t *testing.T) {
+	body := strings.Repeat("a", 2*int(defaultCompressionMinSize))
+
+	testCases := []struct {
+		conf        *compressionConfig
+		name        string
+		contentType string
+		acceptEnc   string
+		body        string
+		wantEnc     string
+	}{{
+		conf:        nil,
+		name:        "json",
+		contentType: aghhttp.HdrValApplicationJSON,
+		acceptEnc:   "gzip",
+		body:        body,
+		wantEnc:     "gzip",
+	}, {
+		conf:        nil,
+		name:        "not_accepted",
+		contentType: aghhttp.HdrValApplicationJSON,
+		acceptEnc:   "",
+		body:        body,
+		wantEnc:     "",
+	}, {
+		conf:        nil,
+		name:        "small",
+		contentType: aghhttp.HdrValApplicationJSON,
+		acceptEnc:   "gzip",
+		body:        "{}",
+		wantEnc:     "",
+	}, {
+		conf:        nil,
+		name:        "event_stream",
+		contentType: "text/event-stream",
+		acceptEnc:   "gzip",
+		body:        body,
+		wantEnc:     "",
+	}, {
+		conf:        &compressionConfig{Enabled: false},
+		name:        "disabled",
+		contentType: aghhttp.HdrValApplicationJSON,
+		acceptEnc:   "gzip",
+		body:        body,
+		wantEnc:     "",
+	}, {
+		conf: &compressionConfig{
+			MinSize: 1 * datasize.B,
+			Level:   9,
+			Enabled: true,
+		},
+		name:        "custom",
+		contentType: aghhttp.HdrValApplicationJSON,
+		acceptEnc:   "gzip",
+		body:        "{}",
+		wantEnc:     "gzip",
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mw, err := newCompressionMiddleware(tc.conf)
+			require.NoError(t, err)
+
+			h := mw(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set(httphdr.ContentType, tc.contentType)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/control/querylog", nil)
+			if tc.acceptEnc != "" {
+				r.Header.Set(httphdr.AcceptEncoding, tc.acceptEnc)
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.wantEnc, w.Header().Get(httphdr.ContentEncoding))
+		})
+	}
+}
+
+func TestCompressionConfig_validate(t *testing.T) {
+	testCases := []struct {
+		conf       *compressionConfig
+		name       string
+		wantErrMsg string
+	}{{
+		conf:       nil,
+		name:       "nil",
+		wantErrMsg: "",
+	}, {
+		conf: &compressionConfig{
+			MinSize: defaultCompressionMinSize,
+			Level:   5,
+			Enabled: true,
+		},
+		name:       "valid",
+		wantErrMsg: "",
+	}, {
+		conf: &compressionConfig{
+			Level:   10,
+			Enabled: true,
+		},
+		name:       "bad_level",
+		wantErrMsg: "level: out of range: must be from 1 to 9, got 10",
+	}, {
+		conf: &compressionConfig{
+			MinSize: 2 * datasize.GB,
+			Enabled: true,
+		},
+		name:       "bad_min_size",
+		wantErrMsg: "min_size: out of range: 2GB",
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			testutil.AssertErrorMsg(t, tc.wantErrMsg, tc.conf.validate())
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package home
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/configmigrate"
+	"github.com/AdguardTeam/AdGuardHome/internal/dnsforward"
+	"github.com/AdguardTeam/AdGuardHome/internal/filtering"
+	"github.com/AdguardTeam/dnsproxy/proxy"
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+	"github.com/AdguardTeam/golibs/timeutil"
+)
+
+// configCheckError is a problem found in a configuration while checking it.
+type configCheckError struct {
+	// Path is the path to the invalid value, like "dns.upstream_dns[1]".  It's
+	// empty if the problem isn't related to a single value.
+	Path string `json:"path,omitempty"`
+
+	// Message is the description of the problem.
+	Message string `json:"message"`
+}
+
+// newConfigCheckError returns a new *configCheckError for err at path.  err
+// must not be nil.
+func newConfigCheckError(path string, err error) (e *configCheckError) {
+	return &configCheckError{
+		Path:    path,
+		Message: err.Error(),
+	}
+}
+
+// configInvalidError is returned when the configuration file data isn't a valid
+// configuration.
+type configInvalidError struct {
+	// errs are the problems found in the data.
+	errs []*configCheckError
+}
+
+// type check
+var _ error = (*configInvalidError)(nil)
+
+// Error implements the [error] interface for *configInvalidError.
+func (e *configInvalidError) Error() (msg string) {
+	return "invalid config: " + joinConfigCheckErrors(e.errs)
+}
+
+// joinConfigCheckErrors returns the descriptions of errs joined into a single
+// message.
+func joinConfigCheckErrors(errs []*configCheckError) (msg string) {
+	msgs := make([]string, 0, len(errs))
+	for _, ce := range errs {
+		if ce.Path == "" {
+			msgs = append(msgs, ce.Message)
+		} else {
+			msgs = append(msgs, ce.Path+": "+ce.Message)
+		}
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// checkConfigData parses, upgrades, and validates the configuration file data
+// without applying it.  The included files are resolved against includeDir.  l
+// must not be nil.
+func checkConfigData(
+	ctx context.Context,
+	l *slog.Logger,
+	data []byte,
+	includeDir string,
+) (errs []*configCheckError) {
+	_, errs = loadConfigData(ctx, l, data, includeDir)
+
+	return errs
+}
+
+// loadConfigData is like [checkConfigData] but also returns the decoded
+// configuration.  conf is nil if data can't be decoded, and it's invalid if
+// errs isn't empty.
+func loadConfigData(
+	ctx context.Context,
+	l *slog.Logger,
+	data []byte,
+	includeDir string,
+) (conf *configuration, errs []*configCheckError) {
+	// Some of the old migrations remove the obsolete files from the working
+	// directory, so don't let them touch the actual one.
+	workDir, err := os.MkdirTemp("", "adguardhome-check-")
+	if err != nil {
+		err = fmt.Errorf("creating dir: %w", err)
+
+		return nil, []*configCheckError{newConfigCheckError("", err)}
+	}
+
+	defer func() {
+		rmErr := os.RemoveAll(workDir)
+		if rmErr != nil {
+			l.WarnContext(ctx, "removing temporary dir", slogutil.KeyError, rmErr)
+		}
+	}()
+
+	migrator := configmigrate.New(&configmigrate.Config{
+		Logger:     l.With(slogutil.KeyPrefix, "config_migrator"),
+		WorkingDir: workDir,
+		DataDir:    filepath.Join(workDir, dataDir),
+	})
+
+	data, _, err = migrator.Migrate(ctx, data, configmigrate.LastSchemaVersion)
+	if err != nil {
+		return nil, []*configCheckError{newConfigCheckError("", err)}
+	}
+
+	secrets, err := newSecretsCipher(os.LookupEnv)
+	if err != nil {
+		return nil, []*configCheckError{newConfigCheckError("", fmt.Errorf("secrets key: %w", err))}
+	}
+
+	data, incs, err := mergeConfigIncludes(data, includeDir, secrets)
+	if err != nil {
+		return nil, []*configCheckError{newConfigCheckError(includeKey, err)}
+	}
+
+	conf = newDefaultConfig()
+	substs, err := unmarshalConfig(data, conf, os.LookupEnv, secrets)
+	if err != nil {
+		return nil, []*configCheckError{newConfigCheckError("", err)}
+	}
+
+	conf.envSubsts = substs
+	conf.secrets = secrets
+	conf.includes = incs
+	conf.mergedData = data
+	conf.normalize()
+
+	err = validateConfig(ctx, l, conf, data)
+	if err != nil {
+		errs = append(errs, newConfigCheckError("", err))
+	}
+
+	err = validateTLSCipherIDs(conf.TLS.OverrideTLSCiphers)
+	if err != nil {
+		errs = append(errs, newConfigCheckError("tls.override_tls_ciphers", err))
+	}
+
+	if conf.DNS.UpstreamTimeout == 0 {
+		conf.DNS.UpstreamTimeout = timeutil.Duration(dnsforward.DefaultTimeout)
+	}
+
+	return conf, append(errs, checkConfigRefs(conf)...)
+}
+
+// checkConfigRefs returns the problems with the upstream servers and the filter
+// lists of conf, which aren't checked when the configuration is loaded.  conf
+// must not be nil.
+func checkConfigRefs(conf *configuration) (errs []*configCheckError) {
+	dns := &conf.DNS
+	errs = append(errs, checkUpstreams("dns.upstream_dns", dns.UpstreamDNS)...)
+	errs = append(errs, checkUpstreams("dns.fallback_dns", dns.FallbackDNS)...)
+	errs = append(errs, checkUpstreams("dns.local_ptr_upstreams", dns.PrivateRDNSResolvers)...)
+
+	err := dnsforward.ValidateBootstraps(dns.BootstrapDNS)
+	if err != nil {
+		errs = append(errs, newConfigCheckError("dns.bootstrap_dns", err))
+	}
+
+	var patterns []string
+	if conf.Filtering != nil {
+		patterns = conf.Filtering.SafeFSPatterns
+	}
+
+	for i, p := range patterns {
+		_, err = filepath.Match(p, "test")
+		if err != nil {
+			path := fmt.Sprintf("filtering.safe_fs_patterns[%d]", i)
+
+			// The local filter lists can't be checked with a bad pattern.
+			return append(errs, newConfigCheckError(path, err))
+		}
+	}
+
+	errs = append(errs, checkFilterURLs("filters", conf.Filters, patterns)...)
+
+	return append(errs, checkFilterURLs("whitelist_filters", conf.WhitelistFilters, patterns)...)
+}
+
+// checkUpstreams returns the problems with the upstream configuration lines at
+// path.
+func checkUpstreams(path string, lines []string) (errs []*configCheckError) {
+	err := dnsforward.ValidateUpstreams(lines)
+	if err == nil {
+		return nil
+	}
+
+	wrapper, ok := err.(errors.WrapperSlice)
+	if !ok {
+		return []*configCheckError{newConfigCheckError(path, err)}
+	}
+
+	for _, e := range wrapper.Unwrap() {
+		parseErr, isParseErr := errors.AsType[*proxy.ParseError](e)
+		if !isParseErr {
+			errs = append(errs, newConfigCheckError(path, e))
+
+			continue
+		}
+
+		itemPath := fmt.Sprintf("%s[%d]", path, parseErr.Idx)
+		errs = append(errs, newConfigCheckError(itemPath, parseErr.Unwrap()))
+	}
+
+	return errs
+}
+
+// checkFilterURLs returns the problems with the URLs of the filter lists at
+// path.
+func checkFilterURLs(
+	path string,
+	filters []filtering.FilterYAML,
+	safeFSPatterns []string,
+) (errs []*configCheckError) {
+	for i, f := range filters {
+		err := filtering.ValidateFilterURL(f.URL, safeFSPatterns)
+		if err != nil {
+			errs = append(errs, newConfigCheckError(fmt.Sprintf("%s[%d].url", path, i), err))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,100 @@
+package home
+
+import (
+	"testing"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/filtering"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yaml "go.yaml.in/yaml/v4"
+)
+
+func TestCheckConfigData(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		modify    func(c *configuration)
+		name      string
+		wantPaths []string
+	}{{
+		modify:    func(_ *configuration) {},
+		name:      "default",
+		wantPaths: nil,
+	}, {
+		modify: func(c *configuration) {
+			c.DNS.UpstreamDNS = []string{
+				"# comment",
+				"https://dns.example/dns-query",
+				"[/example.org/]bad://upstream",
+				"[bad",
+			}
+		},
+		name:      "upstreams",
+		wantPaths: []string{"dns.upstream_dns[2]", "dns.upstream_dns[3]"},
+	}, {
+		modify: func(c *configuration) {
+			c.DNS.FallbackDNS = []string{"1.1.1.1"}
+			c.DNS.BootstrapDNS = []string{"9.9.9.9", "https://dns.example/dns-query"}
+		},
+		name:      "bootstrap",
+		wantPaths: []string{"dns.bootstrap_dns"},
+	}, {
+		modify: func(c *configuration) {
+			c.WhitelistFilters = []filtering.FilterYAML{{
+				URL: "ftp://filters.example/list.txt",
+			}}
+		},
+		name:      "filter_url",
+		wantPaths: []string{"whitelist_filters[0].url"},
+	}, {
+		modify: func(c *configuration) {
+			c.Filtering.SafeFSPatterns = []string{"[bad"}
+		},
+		name:      "safe_fs_pattern",
+		wantPaths: []string{"filtering.safe_fs_patterns[0]"},
+	}, {
+		modify: func(c *configuration) {
+			c.DNS.Port = c.HTTPConfig.Address.Port()
+			c.TLS.Enabled = true
+			c.TLS.PortHTTPS = c.HTTPConfig.Address.Port()
+			c.TLS.OverrideTLSCiphers = []string{"BAD_CIPHER"}
+		},
+		name:      "ports_and_ciphers",
+		wantPaths: []string{"", "tls.override_tls_ciphers"},
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			conf := newDefaultConfig()
+			tc.modify(conf)
+
+			data, err := yaml.Marshal(conf)
+			require.NoError(t, err)
+
+			ctx := testutil.ContextWithTimeout(t, testTimeout)
+			errs := checkConfigData(ctx, testLogger, data, "")
+
+			var gotPaths []string
+			for _, e := range errs {
+				assert.NotEmpty(t, e.Message)
+
+				gotPaths = append(gotPaths, e.Path)
+			}
+
+			assert.Equal(t, tc.wantPaths, gotPaths)
+		})
+	}
+
+	t.Run("bad_yaml", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.ContextWithTimeout(t, testTimeout)
+		errs := checkConfigData(ctx, testLogger, []byte("dns: [\n"), "")
+		require.Len(t, errs, 1)
+
+		assert.Empty(t, errs[0].Path)
+	})
+}
@@ -0,0 +1,72 @@
+package home
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+)
+
+// registerConfigCheckHandlers registers the HTTP handlers for checking the
+// configuration.
+func (web *webAPI) registerConfigCheckHandlers() {
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodPost,
+		"/control/config/validate",
+		web.handleValidateConfig,
+		&aghhttp.RouteInfo{
+			Summary: "Check a configuration file without applying it",
+			Description: "The configuration is upgraded and validated the same way " +
+				"as it is when AdGuard Home starts.  The upstream servers and the " +
+				"filter lists are checked as well.",
+			Request:  configValidateReq{},
+			Response: configValidateResp{},
+		},
+	)
+}
+
+// configValidateReq is the request to the POST /control/config/validate HTTP
+// API.
+type configValidateReq struct {
+	// Config is the contents of the configuration file to check.
+	Config string `json:"config"`
+}
+
+// configValidateResp is the response to the POST /control/config/validate
+// HTTP API.
+type configValidateResp struct {
+	// Errors are the problems found in the configuration.  It's empty if Valid
+	// is true.
+	Errors []*configCheckError `json:"errors"`
+
+	// Valid is true if the configuration could be applied.
+	Valid bool `json:"valid"`
+}
+
+// handleValidateConfig is the handler for the POST /control/config/validate
+// HTTP API.
+func (web *webAPI) handleValidateConfig(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	l := web.logger
+
+	req := &configValidateReq{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusBadRequest, "reading req: %s", err)
+
+		return
+	}
+
+	confPath := configFilePath(ctx, l, web.conf.workDir, web.conf.confPath)
+	errs := checkConfigData(ctx, l, []byte(req.Config), filepath.Dir(confPath))
+	if errs == nil {
+		errs = []*configCheckError{}
+	}
+
+	aghhttp.WriteJSONResponseOK(ctx, l, w, r, &configValidateResp{
+		Errors: errs,
+		Valid:  len(errs) == 0,
+	})
+}
@@ -0,0 +1,299 @@
+package home
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/AdguardTeam/golibs/errors"
+	yaml "go.yaml.in/yaml/v4"
+)
+
+// envRefStart is the beginning of a reference to an environment variable in
+// the values of the configuration file.
+const envRefStart = "${"
+
+// envFileSuffix is the suffix of the environment variables, which contain the
+// path to a file with the value, like the ones of the Docker secrets.
+const envFileSuffix = "_FILE"
+
+// envSubst is a value of the configuration file with references to the
+// environment variables.  It's used to write the references back instead of
+// the secrets, which they have been expanded to.
+type envSubst struct {
+	// path is the path to the value, like "dns.upstream_dns.[]".  The indexes
+	// of the sequence items are replaced with "[]", so that the reordered
+	// items are still found by their values.
+	path string
+
+	// template is the original value with the references.
+	template string
+
+	// value is the expanded value.
+	value string
+}
+
+// lookupEnvFunc is the signature of [os.LookupEnv].
+type lookupEnvFunc func(name string) (val string, ok bool)
+
+// unmarshalConfig decodes the configuration file data into conf expanding the
+// references to the environment variables in the values.  The references look
+// like ${NAME} or ${NAME:-default}, where the default is used when the variable
+// is unset or empty.  If NAME is unset but NAME_FILE is set, the value is read
+// from the file NAME_FILE points to.  $${ is a literal ${.  The encrypted
+// values are decrypted using sc, see [secretsCipher].
+func unmarshalConfig(
+	data []byte,
+	conf any,
+	lookup lookupEnvFunc,
+	sc *secretsCipher,
+) (substs []*envSubst, err error) {
+	if !bytes.Contains(data, []byte(envRefStart)) &&
+		!bytes.Contains(data, []byte(encryptedValuePrefix)) {
+		// Don't wrap the error since it's informative enough as is.
+		return nil, yaml.Unmarshal(data, conf)
+	}
+
+	doc := &yaml.Node{}
+	err = yaml.Unmarshal(data, doc)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return nil, err
+	}
+
+	err = walkConfigScalars(doc, "", func(n *yaml.Node, path string) (wErr error) {
+		if strings.HasPrefix(n.Value, encryptedValuePrefix) {
+			return sc.decryptScalar(n, path)
+		}
+
+		substs, wErr = expandEnvScalar(n, path, lookup, substs)
+		if wErr != nil {
+			return fmt.Errorf("expanding environment variables: %w", wErr)
+		}
+
+		return nil
+	})
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return nil, err
+	}
+
+	// Decode the expanded data the same way as the original one, since
+	// [yaml.Node.Decode] uses different options.
+	expanded, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("encoding expanded config: %w", err)
+	}
+
+	// Don't wrap the error since it's informative enough as is.
+	return substs, yaml.Unmarshal(expanded, conf)
+}
+
+// walkConfigScalars calls f for each scalar value of n and its children with
+// the path to it in the format of [envSubst.path].  path is the path to n.  The
+// keys of the mappings aren't visited.
+func walkConfigScalars(
+	n *yaml.Node,
+	path string,
+	f func(n *yaml.Node, path string) (err error),
+) (err error) {
+	switch n.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		childPath := path
+		if n.Kind == yaml.SequenceNode {
+			childPath = joinConfigPath(path, "[]")
+		}
+
+		for _, c := range n.Content {
+			err = walkConfigScalars(c, childPath, f)
+			if err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			err = walkConfigScalars(n.Content[i+1], joinConfigPath(path, n.Content[i].Value), f)
+			if err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		return f(n, path)
+	default:
+		// Aliases refer to the nodes, which are visited on their own.
+	}
+
+	return nil
+}
+
+// expandEnvScalar expands the references in the scalar node n at path.
+func expandEnvScalar(
+	n *yaml.Node,
+	path string,
+	lookup lookupEnvFunc,
+	substs []*envSubst,
+) (res []*envSubst, err error) {
+	if !strings.Contains(n.Value, envRefStart) {
+		return substs, nil
+	}
+
+	val, err := expandEnvString(n.Value, lookup)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	substs = append(substs, &envSubst{
+		path:     path,
+		template: n.Value,
+		value:    val,
+	})
+
+	// Let the values, like ${PORT:-53}, be resolved into any type.  The string
+	// fields accept the scalars of any type as is.
+	n.Value = val
+	n.Tag = ""
+	n.Style = 0
+
+	return substs, nil
+}
+
+// joinConfigPath appends key to the configuration file path.
+func joinConfigPath(path, key string) (res string) {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+// expandEnvString returns s with the references to the environment variables
+// replaced with their values.
+func expandEnvString(s string, lookup lookupEnvFunc) (res string, err error) {
+	b := &strings.Builder{}
+	for {
+		i := strings.Index(s, envRefStart)
+		if i < 0 {
+			b.WriteString(s)
+
+			return b.String(), nil
+		}
+
+		if i > 0 && s[i-1] == '$' {
+			// An escaped reference.
+			b.WriteString(s[:i-1])
+			b.WriteString(envRefStart)
+			s = s[i+len(envRefStart):]
+
+			continue
+		}
+
+		b.WriteString(s[:i])
+		s = s[i+len(envRefStart):]
+
+		ref, rest, ok := strings.Cut(s, "}")
+		if !ok {
+			return "", fmt.Errorf("unclosed reference %q", envRefStart+s)
+		}
+
+		var val string
+		val, err = resolveEnvRef(ref, lookup)
+		if err != nil {
+			return "", err
+		}
+
+		b.WriteString(val)
+		s = rest
+	}
+}
+
+// resolveEnvRef returns the value of the reference ref without the braces.
+func resolveEnvRef(ref string, lookup lookupEnvFunc) (val string, err error) {
+	name, def, hasDef := strings.Cut(ref, ":-")
+	if !isValidEnvName(name) {
+		return "", fmt.Errorf("bad environment variable name %q", name)
+	}
+
+	if val, ok := lookup(name); ok && (val != "" || !hasDef) {
+		return val, nil
+	}
+
+	if p, ok := lookup(name + envFileSuffix); ok {
+		var data []byte
+		data, err = os.ReadFile(p)
+		if err != nil {
+			return "", fmt.Errorf("reading %s: %w", name+envFileSuffix, err)
+		}
+
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+
+	if hasDef {
+		return def, nil
+	}
+
+	return "", fmt.Errorf("environment variable %s: %w", name, errors.ErrNoValue)
+}
+
+// isValidEnvName returns true if name is a valid name of an environment
+// variable for the references.
+func isValidEnvName(name string) (ok bool) {
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		return false
+	}
+
+	for _, c := range name {
+		if c != '_' && (c < 'A' || c > 'Z') && (c < 'a' || c > 'z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// restoreEnvRefs replaces the expanded values in the YAML data with the
+// references from substs, so that the secrets from the environment aren't
+// written to the configuration file.  The values, which have been changed
+// since they were expanded, are kept.
+func restoreEnvRefs(data []byte, substs []*envSubst) (res []byte, err error) {
+	if len(substs) == 0 {
+		return data, nil
+	}
+
+	doc := &yaml.Node{}
+	err = yaml.Unmarshal(data, doc)
+	if err != nil {
+		return nil, fmt.Errorf("decoding config: %w", err)
+	}
+
+	byPath := map[string][]*envSubst{}
+	for _, s := range substs {
+		byPath[s.path] = append(byPath[s.path], s)
+	}
+
+	// The function never returns an error.
+	_ = walkConfigScalars(doc, "", func(n *yaml.Node, path string) (_ error) {
+		for _, sub := range byPath[path] {
+			if sub.value == n.Value {
+				n.Value = sub.template
+				n.Tag = ""
+				n.Style = 0
+
+				break
+			}
+		}
+
+		return nil
+	})
+
+	buf := &bytes.Buffer{}
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+
+	err = enc.Encode(doc)
+	if err != nil {
+		return nil, fmt.Errorf("encoding config: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,480 @@
+package home
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghos"
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+	"github.com/google/renameio/v2/maybe"
+)
+
+// configHistoryDir is the name of the directory with the revisions of the
+// configuration file in the data directory.
+const configHistoryDir = "config_history"
+
+// configHistoryIndexFilename is the name of the file with the JSON-encoded
+// [configRevision] list in [configHistoryDir].  The contents of each revision
+// are stored next to it in a file named after the ID of the revision.
+const configHistoryIndexFilename = "index.json"
+
+// defaultConfigHistoryMaxRevisions is the default maximum number of the
+// revisions kept in the configuration history.
+const defaultConfigHistoryMaxRevisions = 20
+
+// configHistoryConfig is the configuration of the history of the changes of
+// the configuration file.
+type configHistoryConfig struct {
+	// MaxRevisions is the maximum number of the revisions kept.  The oldest
+	// revisions are removed first.  It must be greater than one if Enabled is
+	// true.
+	MaxRevisions int `yaml:"max_revisions"`
+
+	// Enabled defines if the revisions are recorded.
+	Enabled bool `yaml:"enabled"`
+}
+
+// defaultConfigHistoryConfig returns the default configuration of the
+// configuration history.
+func defaultConfigHistoryConfig() (c *configHistoryConfig) {
+	return &configHistoryConfig{
+		MaxRevisions: defaultConfigHistoryMaxRevisions,
+		Enabled:      true,
+	}
+}
+
+// validate returns an error if c is invalid.  c may be nil.
+func (c *configHistoryConfig) validate() (err error) {
+	if c == nil || !c.Enabled {
+		return nil
+	}
+
+	if c.MaxRevisions < 2 {
+		return fmt.Errorf("max_revisions: %w: must be at least 2", errors.ErrOutOfRange)
+	}
+
+	return nil
+}
+
+// configRevisionSource is the origin of a revision of the configuration file.
+type configRevisionSource string
+
+// Valid configRevisionSource values.
+const (
+	// configRevisionSourceFile means that the revision has been found in the
+	// configuration file, for example, on the first change after the start or
+	// after the file has been edited manually.
+	configRevisionSourceFile configRevisionSource = "file"
+
+	// configRevisionSourceChange means that the revision has been written by
+	// AdGuard Home after a change of the settings.
+	configRevisionSourceChange configRevisionSource = "change"
+
+	// configRevisionSourceRollback means that the revision has been restored
+	// from a previous one.
+	configRevisionSourceRollback configRevisionSource = "rollback"
+
+	// configRevisionSourceSync means that the revision has been pulled from the
+	// primary instance, see [syncReplica].
+	configRevisionSourceSync configRevisionSource = "sync"
+)
+
+// configRevision is a recorded revision of the configuration file.
+type configRevision struct {
+	// Time is the time, when the revision was recorded.
+	Time time.Time `json:"time"`
+
+	// Source is the origin of the revision.
+	Source configRevisionSource `json:"source"`
+
+	// User is the login of the web user, who made the change.  It's empty if
+	// it's unknown.
+	User string `json:"user,omitempty"`
+
+	// RemoteIP is the address of the web client, which made the change.  It's
+	// empty if it's unknown.
+	RemoteIP string `json:"remote_ip,omitempty"`
+
+	// Hash is the hex-encoded SHA-256 hash of the contents.
+	Hash string `json:"hash"`
+
+	// Keys are the keys of the configuration file changed since the previous
+	// revision, like "dns.upstream_dns".
+	Keys []string `json:"keys,omitempty"`
+
+	// ID is the unique identifier of the revision.  The newer revisions have
+	// the greater IDs.
+	ID uint64 `json:"id"`
+
+	// RestoredFrom is the ID of the revision, which has been restored, if
+	// Source is [configRevisionSourceRollback].
+	RestoredFrom uint64 `json:"restored_from,omitempty"`
+}
+
+// errConfigRevisionNotFound is returned when the requested revision isn't in
+// the history.
+const errConfigRevisionNotFound errors.Error = "revision not found"
+
+// configHistory is the bounded history of the revisions of the configuration
+// file.  It's safe for concurrent use.
+type configHistory struct {
+	// writeMu serializes the writes of the configuration file.  It's shared
+	// with the [defaultConfigModifier] and protects rolledBack.
+	writeMu *sync.Mutex
+
+	// mu protects revisions.
+	mu *sync.Mutex
+
+	// dir is the directory with the revisions.
+	dir string
+
+	// revisions are the revisions from the oldest to the newest.
+	revisions []*configRevision
+
+	// maxRevisions is the maximum length of revisions.
+	maxRevisions int
+
+	// rolledBack is true if the configuration file has been restored from a
+	// revision.  The configuration in memory is outdated then, so it must not
+	// be written until AdGuard Home restarts.
+	rolledBack bool
+}
+
+// newConfigHistory returns a new configuration history stored in dir and loads
+// the existing revisions.  writeMu must not be nil.  h is nil if c is nil or
+// disabled.
+func newConfigHistory(
+	dir string,
+	c *configHistoryConfig,
+	writeMu *sync.Mutex,
+) (h *configHistory, err error) {
+	if c == nil || !c.Enabled {
+		return nil, nil
+	}
+
+	h = &configHistory{
+		writeMu:      writeMu,
+		mu:           &sync.Mutex{},
+		dir:          dir,
+		maxRevisions: c.MaxRevisions,
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, configHistoryIndexFilename))
+	if errors.Is(err, fs.ErrNotExist) {
+		return h, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading config history: %w", err)
+	}
+
+	err = json.Unmarshal(data, &h.revisions)
+	if err != nil {
+		return nil, fmt.Errorf("decoding config history: %w", err)
+	}
+
+	// Skip the revisions, the contents of which have been lost.
+	h.revisions = slices.DeleteFunc(h.revisions, func(rev *configRevision) (del bool) {
+		_, statErr := os.Stat(h.revisionPath(rev.ID))
+
+		return statErr != nil
+	})
+
+	return h, nil
+}
+
+// revisionPath returns the path to the file with the contents of the revision
+// with the given id.
+func (h *configHistory) revisionPath(id uint64) (p string) {
+	return filepath.Join(h.dir, strconv.FormatUint(id, 10)+".yaml")
+}
+
+// configHash returns the hex-encoded SHA-256 hash of the configuration file
+// contents.
+func configHash(data []byte) (hash string) {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// isRolledBack returns true if the configuration file has been restored from a
+// revision.  h.writeMu must be locked.  h may be nil.
+func (h *configHistory) isRolledBack() (ok bool) {
+	return h != nil && h.rolledBack
+}
+
+// record adds the revisions for the change of the configuration file contents
+// from prev to cur made within ctx by src.  prev is recorded as well if it
+// isn't the newest revision, since it has been changed outside of AdGuard Home
+// then.  h.writeMu must be locked.
+func (h *configHistory) record(
+	ctx context.Context,
+	prev []byte,
+	cur []byte,
+	src configRevisionSource,
+) (err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	prevHash := configHash(prev)
+	if len(h.revisions) == 0 || h.revisions[len(h.revisions)-1].Hash != prevHash {
+		err = h.addLocked(prev, &configRevision{Source: configRevisionSourceFile})
+		if err != nil {
+			return fmt.Errorf("adding previous revision: %w", err)
+		}
+	}
+
+	if configHash(cur) == prevHash {
+		return nil
+	}
+
+	rev := &configRevision{
+		Source: src,
+	}
+
+	if u, ok := webUserFromContext(ctx); ok {
+		rev.User = string(u.Login)
+	}
+
+	if ip, ok := remoteIPFromContext(ctx); ok {
+		rev.RemoteIP = ip.String()
+	}
+
+	rev.Keys, err = configChangedKeys(prev, cur)
+	if err != nil {
+		return fmt.Errorf("comparing revisions: %w", err)
+	}
+
+	return h.addLocked(cur, rev)
+}
+
+// addLocked stores data as the newest revision with the metadata from rev,
+// removes the oldest revisions exceeding the limit, and saves the index.
+// h.mu must be locked.
+func (h *configHistory) addLocked(data []byte, rev *configRevision) (err error) {
+	rev.Time = time.Now().UTC()
+	rev.Hash = configHash(data)
+	rev.ID = 1
+	if len(h.revisions) > 0 {
+		rev.ID = h.revisions[len(h.revisions)-1].ID + 1
+	}
+
+	err = os.MkdirAll(h.dir, aghos.DefaultPermDir)
+	if err != nil {
+		return fmt.Errorf("creating dir: %w", err)
+	}
+
+	err = maybe.WriteFile(h.revisionPath(rev.ID), data, aghos.DefaultPermFile)
+	if err != nil {
+		return fmt.Errorf("writing revision: %w", err)
+	}
+
+	h.revisions = append(h.revisions, rev)
+
+	var errs []error
+	if n := len(h.revisions) - h.maxRevisions; n > 0 {
+		for _, old := range h.revisions[:n] {
+			rmErr := os.Remove(h.revisionPath(old.ID))
+			if rmErr != nil && !errors.Is(rmErr, fs.ErrNotExist) {
+				errs = append(errs, rmErr)
+			}
+		}
+
+		h.revisions = slices.Delete(h.revisions, 0, n)
+	}
+
+	index, err := json.Marshal(h.revisions)
+	if err != nil {
+		return fmt.Errorf("encoding config history: %w", err)
+	}
+
+	indexPath := filepath.Join(h.dir, configHistoryIndexFilename)
+	err = maybe.WriteFile(indexPath, index, aghos.DefaultPermFile)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("writing config history: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// list returns the revisions from the newest to the oldest.
+func (h *configHistory) list() (revs []*configRevision) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	revs = slices.Clone(h.revisions)
+	slices.Reverse(revs)
+
+	return revs
+}
+
+// findLocked returns the revision with the given id and the one preceding it.  prev
+// is nil if rev is the oldest one.  h.mu must be locked.
+func (h *configHistory) findLocked(id uint64) (rev, prev *configRevision, err error) {
+	i := slices.IndexFunc(h.revisions, func(r *configRevision) (ok bool) { return r.ID == id })
+	if i < 0 {
+		return nil, nil, fmt.Errorf("revision %d: %w", id, errConfigRevisionNotFound)
+	} else if i > 0 {
+		prev = h.revisions[i-1]
+	}
+
+	return h.revisions[i], prev, nil
+}
+
+// contents returns the contents of the revision with the given id.
+func (h *configHistory) contents(id uint64) (data []byte, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, _, err = h.findLocked(id)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return nil, err
+	}
+
+	// Don't wrap the error since it's informative enough as is.
+	return os.ReadFile(h.revisionPath(id))
+}
+
+// diff returns the changes between the revision with the given base ID and the
+// revision with the given id.  If base is zero, the preceding revision is used,
+// if any.  usedBase is the ID of the revision actually compared with.  The
+// values of the secrets are redacted.
+func (h *configHistory) diff(
+	id uint64,
+	base uint64,
+) (changes []*configChange, usedBase uint64, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, prev, err := h.findLocked(id)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return nil, 0, err
+	}
+
+	var baseData []byte
+	if base == 0 && prev != nil {
+		base = prev.ID
+	}
+
+	if base != 0 {
+		if _, _, err = h.findLocked(base); err != nil {
+			return nil, 0, fmt.Errorf("base: %w", err)
+		}
+
+		baseData, err = os.ReadFile(h.revisionPath(base))
+		if err != nil {
+			return nil, 0, fmt.Errorf("reading base: %w", err)
+		}
+	}
+
+	data, err := os.ReadFile(h.revisionPath(id))
+	if err != nil {
+		return nil, 0, fmt.Errorf("reading revision: %w", err)
+	}
+
+	changes, err = configDiff(baseData, data)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return nil, 0, err
+	}
+
+	for _, c := range changes {
+		c.Before = redactSecrets(c.Key, c.Before)
+		c.After = redactSecrets(c.Key, c.After)
+	}
+
+	return changes, base, nil
+}
+
+// configRollbackError is returned when the revision to restore isn't a valid
+// configuration anymore.
+type configRollbackError struct {
+	// errs are the problems found in the revision.
+	errs []*configCheckError
+}
+
+// type check
+var _ error = (*configRollbackError)(nil)
+
+// Error implements the [error] interface for *configRollbackError.
+func (e *configRollbackError) Error() (msg string) {
+	return "invalid revision: " + joinConfigCheckErrors(e.errs)
+}
+
+// rollback validates the revision with the given id and writes it to the
+// configuration file at confPath.  After that, the configuration isn't
+// written until AdGuard Home restarts, so the caller must restart it.  l must
+// not be nil.
+func (h *configHistory) rollback(
+	ctx context.Context,
+	l *slog.Logger,
+	id uint64,
+	confPath string,
+) (err error) {
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+
+	data, err := h.contents(id)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return err
+	}
+
+	if errs := checkConfigData(ctx, l, data, filepath.Dir(confPath)); len(errs) > 0 {
+		return &configRollbackError{errs: errs}
+	}
+
+	prev, err := os.ReadFile(confPath)
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+
+	err = maybe.WriteFile(confPath, data, aghos.DefaultPermFile)
+	if err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+
+	h.rolledBack = true
+
+	rev := &configRevision{
+		Source:       configRevisionSourceRollback,
+		RestoredFrom: id,
+	}
+
+	if u, ok := webUserFromContext(ctx); ok {
+		rev.User = string(u.Login)
+	}
+
+	if ip, ok := remoteIPFromContext(ctx); ok {
+		rev.RemoteIP = ip.String()
+	}
+
+	rev.Keys, err = configChangedKeys(prev, data)
+	if err != nil {
+		l.DebugContext(ctx, "comparing revisions", slogutil.KeyError, err)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	err = h.addLocked(data, rev)
+	if err != nil {
+		// The configuration file has already been restored, so only log the
+		// error.
+		l.ErrorContext(ctx, "recording rollback", slogutil.KeyError, err)
+	}
+
+	return nil
+}
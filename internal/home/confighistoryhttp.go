@@ -0,0 +1,237 @@
+package home
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+)
+
+// registerConfigHistoryHandlers registers the HTTP handlers of the history of
+// the configuration file.
+func (web *webAPI) registerConfigHistoryHandlers() {
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodGet,
+		"/control/config/history",
+		web.handleGetConfigHistory,
+		&aghhttp.RouteInfo{
+			Summary:     "Get the revisions of the configuration file",
+			Description: "The revisions are returned from the newest to the oldest.",
+			Response:    configHistoryResp{},
+		},
+	)
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodGet,
+		"/control/config/history/diff",
+		web.handleGetConfigHistoryDiff,
+		&aghhttp.RouteInfo{
+			Summary: "Get the changes made by a revision of the configuration file",
+			Description: "The \"id\" query parameter is the ID of the revision.  The " +
+				"optional \"base\" query parameter is the ID of the revision to " +
+				"compare with, the preceding one by default.  The values of the " +
+				"secrets are redacted.",
+			Response: configHistoryDiffResp{},
+		},
+	)
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodPost,
+		"/control/config/history/rollback",
+		web.handleConfigHistoryRollback,
+		&aghhttp.RouteInfo{
+			Summary: "Restore a revision of the configuration file and restart",
+			Description: "The revision is validated first.  AdGuard Home restarts " +
+				"after the response to apply it.",
+			Request: configRollbackReq{},
+		},
+	)
+}
+
+// configHistoryResp is the response to the GET /control/config/history HTTP
+// API.
+type configHistoryResp struct {
+	Revisions []*configRevision `json:"revisions"`
+	Enabled   bool              `json:"enabled"`
+}
+
+// configHistoryDiffResp is the response to the GET
+// /control/config/history/diff HTTP API.
+type configHistoryDiffResp struct {
+	Changes []*configChange `json:"changes"`
+
+	// Base is the ID of the revision compared with.  It's zero if the revision
+	// is the oldest one.
+	Base uint64 `json:"base"`
+
+	ID uint64 `json:"id"`
+}
+
+// configRollbackReq is the request to the POST
+// /control/config/history/rollback HTTP API.
+type configRollbackReq struct {
+	ID uint64 `json:"id"`
+}
+
+// handleGetConfigHistory is the handler for the GET /control/config/history
+// HTTP API.
+func (web *webAPI) handleGetConfigHistory(w http.ResponseWriter, r *http.Request) {
+	resp := &configHistoryResp{
+		Revisions: []*configRevision{},
+	}
+
+	if h := web.conf.confHistory; h != nil {
+		resp.Revisions = h.list()
+		resp.Enabled = true
+	}
+
+	aghhttp.WriteJSONResponseOK(r.Context(), web.logger, w, r, resp)
+}
+
+// handleGetConfigHistoryDiff is the handler for the GET
+// /control/config/history/diff HTTP API.
+func (web *webAPI) handleGetConfigHistoryDiff(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	l := web.logger
+
+	h := web.conf.confHistory
+	if h == nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusServiceUnavailable, "config history disabled")
+
+		return
+	}
+
+	params := r.URL.Query()
+	id, err := parseRevisionID(params, "id")
+	if err == nil && id == 0 {
+		err = fmt.Errorf("id: %w", errors.ErrNoValue)
+	}
+
+	var base uint64
+	if err == nil {
+		base, err = parseRevisionID(params, "base")
+	}
+
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusBadRequest, "%s", err)
+
+		return
+	}
+
+	changes, base, err := h.diff(id, base)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, errConfigRevisionNotFound) {
+			code = http.StatusNotFound
+		}
+
+		aghhttp.ErrorAndLog(ctx, l, r, w, code, "%s", err)
+
+		return
+	}
+
+	if changes == nil {
+		changes = []*configChange{}
+	}
+
+	aghhttp.WriteJSONResponseOK(ctx, l, w, r, &configHistoryDiffResp{
+		Changes: changes,
+		Base:    base,
+		ID:      id,
+	})
+}
+
+// parseRevisionID parses the optional revision ID from the URL parameter with
+// the given name.  id is zero if the parameter is missing.
+func parseRevisionID(params url.Values, name string) (id uint64, err error) {
+	v := params.Get(name)
+	if v == "" {
+		return 0, nil
+	}
+
+	id, err = strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: bad value %q", name, v)
+	}
+
+	return id, nil
+}
+
+// handleConfigHistoryRollback is the handler for the POST
+// /control/config/history/rollback HTTP API.
+func (web *webAPI) handleConfigHistoryRollback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	l := web.logger
+
+	h := web.conf.confHistory
+	if h == nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusServiceUnavailable, "config history disabled")
+
+		return
+	}
+
+	req := &configRollbackReq{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusBadRequest, "reading req: %s", err)
+
+		return
+	}
+
+	// Retain the current absolute path of the executable, like the updates do.
+	execPath, err := os.Executable()
+	if err != nil {
+		aghhttp.ErrorAndLog(
+			ctx,
+			l,
+			r,
+			w,
+			http.StatusInternalServerError,
+			"getting executable path: %s",
+			err,
+		)
+
+		return
+	}
+
+	confPath := configFilePath(ctx, l, web.conf.workDir, web.conf.confPath)
+	err = h.rollback(ctx, l, req.ID, confPath)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, rollbackErrorCode(err), "rolling back config: %s", err)
+
+		return
+	}
+
+	l.InfoContext(ctx, "config rolled back; restarting", "id", req.ID)
+
+	aghhttp.OK(ctx, l, w)
+
+	rc := http.NewResponseController(w)
+	err = rc.Flush()
+	if err != nil {
+		l.WarnContext(ctx, "flushing response", slogutil.KeyError, err)
+	}
+
+	// The background context is used for the same reasons as in
+	// [webAPI.handleUpdate].
+	go finishUpdate(context.Background(), l, web.cmdCons, execPath, web.conf.runningAsService)
+}
+
+// rollbackErrorCode returns the HTTP status code for the rollback error err.
+func rollbackErrorCode(err error) (code int) {
+	if errors.Is(err, errConfigRevisionNotFound) {
+		return http.StatusNotFound
+	} else if _, ok := errors.AsType[*configRollbackError](err); ok {
+		return http.StatusUnprocessableEntity
+	}
+
+	return http.StatusInternalServerError
+}
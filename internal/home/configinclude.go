@@ -0,0 +1,402 @@
+package home
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/AdguardTeam/golibs/errors"
+	yaml "go.yaml.in/yaml/v4"
+)
+
+// includeKey is the key of the top-level property of the configuration file
+// with the paths of the included files.
+const includeKey = "include"
+
+// includedValue is a value of the configuration merged from an included file.
+// It's used to keep the included values out of the main configuration file
+// when it's written.
+type includedValue struct {
+	// orig is the value of the main file replaced by the included one.  It's
+	// nil if the main file has no such value or if items is not empty.
+	orig *yaml.Node
+
+	// keys are the keys of the mappings leading to the value.
+	keys []string
+
+	// items are the sequence items appended by the included file.  If it's
+	// empty, the included value replaces orig.
+	items []*yaml.Node
+}
+
+// mergeConfigIncludes returns the configuration file data with the files from
+// its include property merged into it.  The relative paths and glob patterns
+// are resolved against dir, and the files matching a pattern are merged in
+// the lexical order.  The mappings are merged recursively, the sequence items
+// are appended, and the other values are replaced.  The included files should
+// use the current schema of the configuration and can't include other files.
+// Their encrypted values are decrypted using sc.  merged is data if there are
+// no includes.
+func mergeConfigIncludes(
+	data []byte,
+	dir string,
+	sc *secretsCipher,
+) (merged []byte, incs []*includedValue, err error) {
+	if !bytes.Contains(data, []byte(includeKey)) {
+		return data, nil, nil
+	}
+
+	doc := &yaml.Node{}
+	err = yaml.Unmarshal(data, doc)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return nil, nil, err
+	}
+
+	root := docMapping(doc)
+	patternsNode := mappingValue(root, includeKey)
+	if patternsNode == nil {
+		return data, nil, nil
+	}
+
+	var patterns []string
+	err = patternsNode.Decode(&patterns)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", includeKey, err)
+	}
+
+	files, err := resolveIncludes(patterns, dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", includeKey, err)
+	}
+
+	for _, f := range files {
+		var src *yaml.Node
+		src, err = readIncludedFile(f, sc)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %s: %w", includeKey, f, err)
+		} else if src != nil {
+			incs = mergeConfigMappings(root, src, nil, incs)
+		}
+	}
+
+	merged, err = yaml.Marshal(doc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encoding merged config: %w", err)
+	}
+
+	return merged, incs, nil
+}
+
+// removeIncludeKey returns data without the include property, so that the
+// files aren't merged into the already merged data again.
+func removeIncludeKey(data []byte) (res []byte, err error) {
+	if !bytes.Contains(data, []byte(includeKey)) {
+		return data, nil
+	}
+
+	doc := &yaml.Node{}
+	err = yaml.Unmarshal(data, doc)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return nil, err
+	}
+
+	root := docMapping(doc)
+	i := mappingIndex(root, includeKey)
+	if i < 0 {
+		return data, nil
+	}
+
+	root.Content = slices.Delete(root.Content, i, i+2)
+
+	return yaml.Marshal(doc)
+}
+
+// resolveIncludes returns the paths of the files matching patterns.  The
+// patterns without the glob metacharacters must match an existing file.
+func resolveIncludes(patterns []string, dir string) (files []string, err error) {
+	for _, p := range patterns {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(dir, p)
+		}
+
+		if !strings.ContainsAny(p, "*?[") {
+			files = append(files, p)
+
+			continue
+		}
+
+		var matches []string
+		matches, err = filepath.Glob(p)
+		if err != nil {
+			return nil, fmt.Errorf("pattern %q: %w", p, err)
+		}
+
+		files = append(files, matches...)
+	}
+
+	return files, nil
+}
+
+// readIncludedFile reads and decodes the included file at path.  src is nil if
+// the file is empty.
+func readIncludedFile(path string, sc *secretsCipher) (src *yaml.Node, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return nil, err
+	}
+
+	doc := &yaml.Node{}
+	err = yaml.NewDecoder(bytes.NewReader(data)).Decode(doc)
+	if errors.Is(err, io.EOF) {
+		// The file is empty or only has comments.
+		return nil, nil
+	} else if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return nil, err
+	}
+
+	src = docMapping(doc)
+	if src == nil {
+		return nil, errors.Error("top-level value must be a mapping")
+	} else if mappingValue(src, includeKey) != nil {
+		return nil, errors.Error("nested includes are not supported")
+	}
+
+	// Decrypt the values here, since the included values are compared with
+	// the decrypted ones when the main file is written.
+	err = walkConfigScalars(src, "", func(n *yaml.Node, p string) (wErr error) {
+		if !strings.HasPrefix(n.Value, encryptedValuePrefix) {
+			return nil
+		}
+
+		return sc.decryptScalar(n, p)
+	})
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return nil, err
+	}
+
+	return src, nil
+}
+
+// mergeConfigMappings merges the mapping node src into the mapping node dst at
+// keys and appends the merged values to incs.
+func mergeConfigMappings(
+	dst *yaml.Node,
+	src *yaml.Node,
+	keys []string,
+	incs []*includedValue,
+) (res []*includedValue) {
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, val := src.Content[i].Value, src.Content[i+1]
+		valKeys := append(slices.Clone(keys), key)
+
+		j := mappingIndex(dst, key)
+		if j < 0 {
+			dst.Content = append(dst.Content, src.Content[i], val)
+			incs = append(incs, &includedValue{keys: valKeys})
+
+			continue
+		}
+
+		cur := dst.Content[j+1]
+		switch {
+		case cur.Kind == yaml.MappingNode && val.Kind == yaml.MappingNode:
+			incs = mergeConfigMappings(cur, val, valKeys, incs)
+		case cur.Kind == yaml.SequenceNode && val.Kind == yaml.SequenceNode:
+			if len(val.Content) > 0 {
+				cur.Content = append(cur.Content, val.Content...)
+				incs = append(incs, &includedValue{
+					keys:  valKeys,
+					items: val.Content,
+				})
+			}
+		default:
+			dst.Content[j+1] = val
+			incs = append(incs, &includedValue{
+				orig: cur,
+				keys: valKeys,
+			})
+		}
+	}
+
+	return incs
+}
+
+// removeIncludedValues returns the YAML data of the configuration without the
+// values merged from the included files, so that they aren't written to the
+// main file.  The replaced values are restored and the appended sequence items
+// are removed.  The items are matched by their contents or, if they have one,
+// by their name, so the values from the included files can only be changed in
+// these files.
+func removeIncludedValues(data []byte, incs []*includedValue) (res []byte, err error) {
+	if len(incs) == 0 {
+		return data, nil
+	}
+
+	doc := &yaml.Node{}
+	err = yaml.Unmarshal(data, doc)
+	if err != nil {
+		return nil, fmt.Errorf("decoding config: %w", err)
+	}
+
+	root := docMapping(doc)
+
+	// Go from the last included value, so that the values replaced several
+	// times are restored in the right order.
+	for _, inc := range slices.Backward(incs) {
+		removeIncludedValue(root, inc)
+	}
+
+	buf := &bytes.Buffer{}
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+
+	err = enc.Encode(doc)
+	if err != nil {
+		return nil, fmt.Errorf("encoding config: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// removeIncludedValue removes inc from the mapping node root.
+func removeIncludedValue(root *yaml.Node, inc *includedValue) {
+	parent := root
+	for _, k := range inc.keys[:len(inc.keys)-1] {
+		parent = mappingValue(parent, k)
+		if parent == nil || parent.Kind != yaml.MappingNode {
+			return
+		}
+	}
+
+	j := mappingIndex(parent, inc.keys[len(inc.keys)-1])
+	if j < 0 {
+		return
+	}
+
+	if len(inc.items) == 0 {
+		if inc.orig == nil {
+			parent.Content = slices.Delete(parent.Content, j, j+2)
+		} else {
+			parent.Content[j+1] = inc.orig
+		}
+
+		return
+	}
+
+	seq := parent.Content[j+1]
+	if seq.Kind != yaml.SequenceNode {
+		return
+	}
+
+	for _, item := range slices.Backward(inc.items) {
+		// The included items are appended, so look for them from the end.
+		for k, n := range slices.Backward(seq.Content) {
+			if matchesIncluded(n, item) {
+				seq.Content = slices.Delete(seq.Content, k, k+1)
+
+				break
+			}
+		}
+	}
+}
+
+// matchesIncluded returns true if n is the included node inc.  The values of
+// the configuration usually have more properties than the included ones, since
+// the default ones are written as well.
+func matchesIncluded(n, inc *yaml.Node) (ok bool) {
+	var got, want any
+	if n.Decode(&got) != nil || inc.Decode(&want) != nil {
+		return false
+	}
+
+	if gotMap, isMap := got.(map[string]any); isMap {
+		wantMap, _ := want.(map[string]any)
+		if name, isStr := wantMap["name"].(string); isStr && gotMap["name"] == name {
+			return true
+		}
+	}
+
+	return containsValue(got, want)
+}
+
+// containsValue returns true if got has all the properties of want with the
+// same values.
+func containsValue(got, want any) (ok bool) {
+	switch want := want.(type) {
+	case map[string]any:
+		gotMap, isMap := got.(map[string]any)
+		if !isMap {
+			return false
+		}
+
+		for k, v := range want {
+			if !containsValue(gotMap[k], v) {
+				return false
+			}
+		}
+
+		return true
+	case []any:
+		gotSlice, isSlice := got.([]any)
+		if !isSlice || len(gotSlice) != len(want) {
+			return false
+		}
+
+		for i, v := range want {
+			if !containsValue(gotSlice[i], v) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return reflect.DeepEqual(got, want)
+	}
+}
+
+// docMapping returns the top-level mapping of the document node doc or nil if
+// there is none.
+func docMapping(doc *yaml.Node) (m *yaml.Node) {
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil
+	}
+
+	return doc.Content[0]
+}
+
+// mappingIndex returns the index of key in the content of the mapping node m or
+// -1 if there is none.  m may be nil.
+func mappingIndex(m *yaml.Node, key string) (i int) {
+	if m == nil {
+		return -1
+	}
+
+	for i = 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// mappingValue returns the value of key in the mapping node m or nil if there
+// is none.  m may be nil.
+func mappingValue(m *yaml.Node, key string) (v *yaml.Node) {
+	i := mappingIndex(m, key)
+	if i < 0 {
+		return nil
+	}
+
+	return m.Content[i+1]
+}
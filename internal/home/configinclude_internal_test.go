@@ -0,0 +1,161 @@
+package home
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yaml "go.yaml.in/yaml/v4"
+)
+
+// writeTestFile writes data to the file at path relative to dir creating the
+// parent directories as necessary.
+func writeTestFile(tb testing.TB, dir, path, data string) {
+	tb.Helper()
+
+	path = filepath.Join(dir, path)
+	require.NoError(tb, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(tb, os.WriteFile(path, []byte(data), 0o600))
+}
+
+func TestMergeConfigIncludes(t *testing.T) {
+	t.Parallel()
+
+	type testClient struct {
+		Name string   `yaml:"name"`
+		IDs  []string `yaml:"ids"`
+		Tags []string `yaml:"tags"`
+	}
+
+	type testConfig struct {
+		DNS struct {
+			Upstreams []string `yaml:"upstream_dns"`
+			Port      uint16   `yaml:"port"`
+		} `yaml:"dns"`
+		Language string       `yaml:"language,omitempty"`
+		Clients  []testClient `yaml:"clients"`
+		Include  []string     `yaml:"include"`
+	}
+
+	const mainData = `dns:
+  upstream_dns:
+    - 1.1.1.1
+  port: 53
+clients:
+  - name: main
+    ids:
+      - 1.2.3.4
+include:
+  - conf.d/*.yaml
+`
+
+	dir := t.TempDir()
+	writeTestFile(t, dir, "conf.d/10-clients.yaml", `dns:
+  upstream_dns:
+    - 9.9.9.9
+  port: 5353
+clients:
+  - name: generated
+    ids:
+      - 5.6.7.8
+`)
+	writeTestFile(t, dir, "conf.d/20-port.yaml", "language: de\ndns:\n  port: 5354\n")
+	writeTestFile(t, dir, "conf.d/30-empty.yaml", "# Nothing yet.\n")
+	writeTestFile(t, dir, "conf.d/ignored.yml", "language: fr\n")
+
+	merged, incs, err := mergeConfigIncludes([]byte(mainData), dir, nil)
+	require.NoError(t, err)
+
+	conf := &testConfig{}
+	require.NoError(t, yaml.Unmarshal(merged, conf))
+
+	assert.Equal(t, []string{"1.1.1.1", "9.9.9.9"}, conf.DNS.Upstreams)
+	assert.Equal(t, uint16(5354), conf.DNS.Port)
+	assert.Equal(t, "de", conf.Language)
+	assert.Equal(t, []testClient{{
+		Name: "main",
+		IDs:  []string{"1.2.3.4"},
+	}, {
+		Name: "generated",
+		IDs:  []string{"5.6.7.8"},
+	}}, conf.Clients)
+
+	// Change both the main and the included values.
+	conf.DNS.Upstreams = append(conf.DNS.Upstreams, "8.8.8.8")
+	conf.Clients[1].IDs = append(conf.Clients[1].IDs, "5.6.7.9")
+
+	out, err := yaml.Marshal(conf)
+	require.NoError(t, err)
+
+	res, err := removeIncludedValues(out, incs)
+	require.NoError(t, err)
+
+	got := &testConfig{}
+	require.NoError(t, yaml.Unmarshal(res, got))
+
+	assert.Equal(t, []string{"1.1.1.1", "8.8.8.8"}, got.DNS.Upstreams)
+	assert.Equal(t, uint16(53), got.DNS.Port)
+	assert.Empty(t, got.Language)
+	assert.Equal(t, []testClient{{
+		Name: "main",
+		IDs:  []string{"1.2.3.4"},
+		Tags: []string{},
+	}}, got.Clients)
+	assert.Equal(t, []string{"conf.d/*.yaml"}, got.Include)
+
+	t.Run("no_includes", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte("dns:\n  port: 53\n")
+		noIncMerged, noIncs, noIncErr := mergeConfigIncludes(data, dir, nil)
+		require.NoError(t, noIncErr)
+
+		assert.Equal(t, data, noIncMerged)
+		assert.Nil(t, noIncs)
+	})
+}
+
+func TestMergeConfigIncludes_errors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestFile(t, dir, "nested.yaml", "include:\n  - other.yaml\n")
+	writeTestFile(t, dir, "list.yaml", "- 1\n- 2\n")
+
+	testCases := []struct {
+		name       string
+		include    string
+		wantErrMsg string
+	}{{
+		name:    "nested",
+		include: "nested.yaml",
+		wantErrMsg: "include: " + filepath.Join(dir, "nested.yaml") +
+			": nested includes are not supported",
+	}, {
+		name:    "not_mapping",
+		include: "list.yaml",
+		wantErrMsg: "include: " + filepath.Join(dir, "list.yaml") +
+			": top-level value must be a mapping",
+	}, {
+		name:    "missing",
+		include: "missing.yaml",
+		wantErrMsg: "include: " + filepath.Join(dir, "missing.yaml") + ": open " +
+			filepath.Join(dir, "missing.yaml") + ": no such file or directory",
+	}, {
+		name:       "bad_pattern",
+		include:    "[",
+		wantErrMsg: "include: pattern \"" + filepath.Join(dir, "[") + "\": syntax error in pattern",
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			data := []byte("include:\n  - '" + tc.include + "'\n")
+			_, _, err := mergeConfigIncludes(data, dir, nil)
+			assert.EqualError(t, err, tc.wantErrMsg)
+		})
+	}
+}
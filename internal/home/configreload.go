@@ -0,0 +1,365 @@
+package home
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghos"
+	"github.com/AdguardTeam/golibs/container"
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+	yaml "go.yaml.in/yaml/v4"
+)
+
+// Parts of AdGuard Home, which can be reconfigured without a restart.
+const (
+	reloadPartClients   = "clients"
+	reloadPartDNS       = "dns"
+	reloadPartFiltering = "filtering"
+)
+
+// reloadSplitKeys are the top-level properties of the configuration, the
+// properties inside which can be reloaded.
+var reloadSplitKeys = []string{
+	reloadPartClients,
+	reloadPartDNS,
+	reloadPartFiltering,
+}
+
+// reloadRestartPaths are the paths of the properties inside [reloadSplitKeys],
+// which are only applied on start.
+var reloadRestartPaths = container.NewMapSet(
+	"clients.runtime_sources",
+	"dns.anonymize_client_ip",
+	"dns.bind_hosts",
+	"dns.hostsfile_enabled",
+	"dns.port",
+	"dns.private_networks",
+	"filtering.cache_time",
+	"filtering.parental_cache_size",
+	"filtering.safe_fs_patterns",
+	"filtering.safe_search",
+	"filtering.safebrowsing_cache_size",
+	"filtering.safesearch_cache_size",
+)
+
+// errRestartPending is returned when the configuration file has been replaced
+// and AdGuard Home is about to restart.
+const errRestartPending errors.Error = "config replaced; restart pending"
+
+// configReloadResult is the result of a reload of the configuration file.
+type configReloadResult struct {
+	// Changed are the paths of the changed properties, like "dns.upstream_dns".
+	Changed []string `json:"changed"`
+
+	// Reloaded are the parts of AdGuard Home, which have been reconfigured.
+	Reloaded []string `json:"reloaded"`
+
+	// Restart is true if some of the changes are only applied on start, so
+	// AdGuard Home must be restarted.
+	Restart bool `json:"restart"`
+}
+
+// configReloader reloads the configuration file and reconfigures the parts of
+// AdGuard Home with the changed settings.  The other parts keep running.
+type configReloader struct {
+	// logger is used for logging the reloads.  It must not be nil.
+	logger *slog.Logger
+
+	// confModifier serializes the reloads with the writes of the
+	// configuration file.  It must not be nil.
+	confModifier *defaultConfigModifier
+
+	// tlsMgr is used to reconfigure the DNS server.  It must not be nil.
+	tlsMgr *tlsManager
+
+	// restart restarts AdGuard Home.  It must not be nil.
+	restart func(ctx context.Context)
+
+	// confPath is the path to the configuration file.
+	confPath string
+}
+
+// newConfigReloader returns a new properly initialized *configReloader.  All
+// arguments must not be nil.
+func newConfigReloader(
+	ctx context.Context,
+	baseLogger *slog.Logger,
+	tlsMgr *tlsManager,
+	cm *defaultConfigModifier,
+	restart func(ctx context.Context),
+	workDir string,
+	confPath string,
+) (r *configReloader) {
+	l := baseLogger.With(slogutil.KeyPrefix, "config_reload")
+
+	return &configReloader{
+		logger:       l,
+		confModifier: cm,
+		tlsMgr:       tlsMgr,
+		restart:      restart,
+		confPath:     configFilePath(ctx, l, workDir, confPath),
+	}
+}
+
+// reload reads the configuration file and applies the changed settings.  If
+// some of them are only applied on start, it doesn't apply any and sets
+// res.Restart, so the caller must call r.restart.
+func (r *configReloader) reload(ctx context.Context) (res *configReloadResult, err error) {
+	cm := r.confModifier
+	cm.changesMu.Lock()
+	defer cm.changesMu.Unlock()
+
+	if cm.replaced || cm.history.isRolledBack() {
+		return nil, errRestartPending
+	}
+
+	data, err := os.ReadFile(r.confPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+
+	includeDir := filepath.Dir(r.confPath)
+	conf, errs := loadConfigData(ctx, r.logger, data, includeDir)
+	if len(errs) > 0 {
+		return nil, &configInvalidError{errs: errs}
+	}
+
+	prev, err := r.loadPrevious(ctx, includeDir)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return nil, err
+	}
+
+	changed, err := changedConfigPaths(prev, conf)
+	if err != nil {
+		return nil, fmt.Errorf("comparing configs: %w", err)
+	}
+
+	res = &configReloadResult{
+		Changed:  changed,
+		Reloaded: []string{},
+	}
+
+	parts, restart := reloadParts(changed)
+	if restart {
+		// Don't let the changes made before the restart overwrite the file.
+		cm.replaced = true
+		res.Restart = true
+
+		return res, nil
+	}
+
+	err = r.apply(ctx, conf, parts)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "applying config; restarting", slogutil.KeyError, err)
+
+		cm.replaced = true
+		res.Restart = true
+
+		return res, nil
+	}
+
+	res.Reloaded = parts
+
+	config.Lock()
+	config.Include = conf.Include
+	config.envSubsts = conf.envSubsts
+	config.includes = conf.includes
+	config.mergedData = conf.mergedData
+	config.Unlock()
+
+	if len(parts) > 0 {
+		cm.syncPrimary.notify()
+	}
+
+	return res, nil
+}
+
+// loadPrevious returns the configuration as it has been last read or written.
+// The included files are resolved against includeDir.
+func (r *configReloader) loadPrevious(
+	ctx context.Context,
+	includeDir string,
+) (prev *configuration, err error) {
+	config.RLock()
+	data := config.mergedData
+	config.RUnlock()
+
+	// The included files have already been merged into the data.
+	data, err = removeIncludeKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("decoding previous config: %w", err)
+	}
+
+	// The previous configuration may have become invalid, for example, if the
+	// upstreams file has been removed, but it's still good for comparing.
+	prev, errs := loadConfigData(ctx, r.logger, data, includeDir)
+	if prev == nil {
+		return nil, fmt.Errorf("decoding previous config: %w", &configInvalidError{errs: errs})
+	}
+
+	return prev, nil
+}
+
+// apply reconfigures parts of AdGuard Home using conf.
+func (r *configReloader) apply(
+	ctx context.Context,
+	conf *configuration,
+	parts []string,
+) (err error) {
+	for _, p := range parts {
+		switch p {
+		case reloadPartClients:
+			err = globalContext.clients.reload(ctx, conf.Clients.Persistent)
+		case reloadPartDNS:
+			config.Lock()
+			config.DNS = conf.DNS
+			config.Unlock()
+
+			err = r.tlsMgr.reconfigureDNSServer(ctx)
+		case reloadPartFiltering:
+			err = reloadFiltering(ctx, conf)
+		default:
+			panic(fmt.Errorf("reload part: %w: %q", errors.ErrBadEnumValue, p))
+		}
+
+		if err != nil {
+			return fmt.Errorf("reloading %s: %w", p, err)
+		}
+
+		r.logger.InfoContext(ctx, "reloaded", "part", p)
+	}
+
+	return nil
+}
+
+// reloadFiltering applies the filtering settings and the filter lists from
+// conf.
+func reloadFiltering(ctx context.Context, conf *configuration) (err error) {
+	c := conf.Filtering
+	c.Filters = conf.Filters
+	c.WhitelistFilters = conf.WhitelistFilters
+	c.UserRules = conf.UserRules
+
+	err = globalContext.filters.ApplyConfig(ctx, c)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return err
+	}
+
+	config.Lock()
+	defer config.Unlock()
+
+	config.Filters = conf.Filters
+	config.WhitelistFilters = conf.WhitelistFilters
+	config.UserRules = conf.UserRules
+
+	return nil
+}
+
+// reloadParts returns the sorted parts of AdGuard Home, which must be
+// reconfigured to apply the changes at paths.  restart is true if some of the
+// changes are only applied on start.
+func reloadParts(paths []string) (parts []string, restart bool) {
+	set := container.NewMapSet[string]()
+	for _, p := range paths {
+		part, ok := reloadPart(p)
+		if !ok {
+			return nil, true
+		}
+
+		set.Add(part)
+	}
+
+	parts = set.Values()
+	slices.Sort(parts)
+
+	return parts, false
+}
+
+// reloadPart returns the part of AdGuard Home, which must be reconfigured to
+// apply the change at path.  ok is false if the change is only applied on
+// start.
+func reloadPart(path string) (part string, ok bool) {
+	switch path {
+	case "filters", "whitelist_filters", "user_rules":
+		return reloadPartFiltering, true
+	}
+
+	if reloadRestartPaths.Has(path) {
+		return "", false
+	}
+
+	key, _, found := strings.Cut(path, ".")
+	if found && slices.Contains(reloadSplitKeys, key) {
+		return key, true
+	}
+
+	return "", false
+}
+
+// changedConfigPaths returns the sorted paths of the properties, which differ
+// between prev and cur, in the same format as [configChangedKeys] does.
+func changedConfigPaths(prev, cur *configuration) (paths []string, err error) {
+	prevData, err := yaml.Marshal(prev)
+	if err != nil {
+		return nil, fmt.Errorf("encoding previous config: %w", err)
+	}
+
+	curData, err := yaml.Marshal(cur)
+	if err != nil {
+		return nil, fmt.Errorf("encoding current config: %w", err)
+	}
+
+	keys, err := configChangedKeys(prevData, curData)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return nil, err
+	}
+
+	paths = []string{}
+	for _, k := range keys {
+		// The included values are compared as merged.
+		if k != includeKey {
+			paths = append(paths, k)
+		}
+	}
+
+	return paths, nil
+}
+
+// reloadOnSignal reloads the configuration file and restarts AdGuard Home if
+// necessary, notifying the service manager about the reload.
+// nil.
+func (r *configReloader) reloadOnSignal(ctx context.Context) {
+	notifyServiceManager(ctx, r.logger, aghos.SDReloadingState())
+
+	res, err := r.reload(ctx)
+	switch {
+	case err != nil:
+		r.logger.ErrorContext(ctx, "reloading config", slogutil.KeyError, err)
+	case res.Restart:
+		r.logger.InfoContext(ctx, "config changes require restart", "changed", res.Changed)
+
+		// The background context is used, since the restart shuts down the
+		// signal handler.
+		go r.restart(context.Background())
+
+		return
+	default:
+		r.logger.InfoContext(
+			ctx,
+			"config reloaded",
+			"changed", res.Changed,
+			"reloaded", res.Reloaded,
+		)
+	}
+
+	notifyServiceManager(ctx, r.logger, aghos.SDNotifyReady)
+}
@@ -0,0 +1,102 @@
+package home
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedConfigPaths(t *testing.T) {
+	t.Parallel()
+
+	prev := newDefaultConfig()
+	prev.Include = []string{"conf.d/*.yaml"}
+
+	cur := newDefaultConfig()
+	cur.UserRules = []string{"||blocked.example^"}
+	cur.DNS.UpstreamDNS = []string{"9.9.9.9"}
+	cur.Filtering.BlockedResponseTTL = 60
+
+	paths, err := changedConfigPaths(prev, cur)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"dns.upstream_dns",
+		"filtering.blocked_response_ttl",
+		"user_rules",
+	}, paths)
+
+	paths, err = changedConfigPaths(cur, cur)
+	require.NoError(t, err)
+
+	assert.Empty(t, paths)
+}
+
+func TestReloadParts(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		paths       []string
+		wantParts   []string
+		wantRestart bool
+	}{{
+		name:        "empty",
+		paths:       []string{},
+		wantParts:   []string{},
+		wantRestart: false,
+	}, {
+		name: "reloadable",
+		paths: []string{
+			"clients.persistent",
+			"dns.upstream_dns",
+			"filtering.rewrites",
+			"filters",
+			"user_rules",
+		},
+		wantParts:   []string{reloadPartClients, reloadPartDNS, reloadPartFiltering},
+		wantRestart: false,
+	}, {
+		name:        "restart_path",
+		paths:       []string{"dns.upstream_dns", "dns.port"},
+		wantParts:   nil,
+		wantRestart: true,
+	}, {
+		name:        "restart_section",
+		paths:       []string{"user_rules", "http.address"},
+		wantParts:   nil,
+		wantRestart: true,
+	}, {
+		name:        "restart_top_level",
+		paths:       []string{"language"},
+		wantParts:   nil,
+		wantRestart: true,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			parts, restart := reloadParts(tc.paths)
+			assert.Equal(t, tc.wantParts, parts)
+			assert.Equal(t, tc.wantRestart, restart)
+		})
+	}
+}
+
+func TestRemoveIncludeKey(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("include:\n  - conf.d/*.yaml\nlanguage: de\n")
+
+	res, err := removeIncludeKey(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, "language: de\n", string(res))
+
+	res, err = removeIncludeKey(res)
+	require.NoError(t, err)
+
+	assert.Equal(t, "language: de\n", string(res))
+}
@@ -0,0 +1,73 @@
+package home
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+)
+
+// registerConfigReloadHandlers registers the HTTP handlers of the reload of the
+// configuration file.
+func (web *webAPI) registerConfigReloadHandlers() {
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodPost,
+		"/control/reload",
+		web.handleReload,
+		&aghhttp.RouteInfo{
+			Summary: "Reload the configuration file",
+			Description: "Reconfigures the parts of AdGuard Home with the changed " +
+				"settings, the other parts keep running.  If some of the changes " +
+				"are only applied on start, AdGuard Home restarts instead.",
+			Response: configReloadResult{},
+		},
+	)
+}
+
+// handleReload is the handler for the POST /control/reload HTTP API.
+func (web *webAPI) handleReload(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	l := web.logger
+
+	reloader := web.conf.confReloader
+	if reloader == nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusConflict, "reload isn't available")
+
+		return
+	}
+
+	res, err := reloader.reload(ctx)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, reloadErrorCode(err), "reloading config: %s", err)
+
+		return
+	}
+
+	aghhttp.WriteJSONResponseOK(ctx, l, w, r, res)
+	if !res.Restart {
+		return
+	}
+
+	err = http.NewResponseController(w).Flush()
+	if err != nil {
+		l.WarnContext(ctx, "flushing response", slogutil.KeyError, err)
+	}
+
+	// The background context is used for the same reasons as in
+	// [webAPI.handleUpdate].
+	go reloader.restart(context.Background())
+}
+
+// reloadErrorCode returns the HTTP status code for the reload error err.
+func reloadErrorCode(err error) (code int) {
+	if errors.Is(err, errRestartPending) {
+		return http.StatusConflict
+	} else if _, ok := errors.AsType[*configInvalidError](err); ok {
+		return http.StatusUnprocessableEntity
+	}
+
+	return http.StatusInternalServerError
+}
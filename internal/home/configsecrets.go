@@ -0,0 +1,315 @@
+package home
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghos"
+	"github.com/AdguardTeam/golibs/container"
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/google/renameio/v2/maybe"
+	yaml "go.yaml.in/yaml/v4"
+)
+
+// secretsKeyEnv is the environment variable with the base64-encoded 256-bit
+// key, which is used to encrypt the secrets in the configuration file.  Like
+// with the references, ADGUARD_HOME_SECRETS_KEY_FILE may point to a file with
+// the key instead.
+const secretsKeyEnv = "ADGUARD_HOME_SECRETS_KEY"
+
+// secretsKeyLen is the length of the key of the secrets in bytes, which selects
+// AES-256.
+const secretsKeyLen = 32
+
+// encryptedValuePrefix is the prefix of the encrypted values in the
+// configuration file.  The rest of the value is the base64-encoded nonce
+// followed by the AES-GCM ciphertext.
+const encryptedValuePrefix = "enc:v1:"
+
+// errNoSecretsKey is returned when the configuration file contains encrypted
+// values, but the key isn't set.
+const errNoSecretsKey errors.Error = "encrypted value, but " + secretsKeyEnv + " is not set"
+
+// secretConfigKeys are the keys of the configuration file, the values of which
+// are secrets.  These are encrypted in the file and redacted in the audit log,
+// the configuration history, and the support bundles.
+var secretConfigKeys = container.NewMapSet(
+	"access_token",
+	"account_key_pem",
+	"api_key",
+	"api_token",
+	"app_token",
+	"bot_token",
+	"cloudflare_api_token",
+	"hash",
+	"password",
+	"ping_url",
+	"private_key",
+	"routing_key",
+	"secret",
+	"webhook_url",
+)
+
+// secretsCipher encrypts and decrypts the secrets in the configuration file.
+// A nil *secretsCipher can't decrypt anything and leaves the values as is when
+// encrypting.
+type secretsCipher struct {
+	aead cipher.AEAD
+
+	// mu protects sealed and paths.
+	mu *sync.Mutex
+
+	// sealed maps the decrypted values to the encrypted ones, so that the
+	// unchanged secrets are written back the same way instead of getting a new
+	// nonce on each write.
+	sealed map[string]string
+
+	// paths are the paths of the values, which have been encrypted in the
+	// file, in the format of [envSubst.path].  These are encrypted back even
+	// if they aren't considered secrets.
+	paths *container.MapSet[string]
+}
+
+// newSecretsCipher returns a new cipher with the key from the environment.  sc
+// is nil if the key isn't set.
+func newSecretsCipher(lookup lookupEnvFunc) (sc *secretsCipher, err error) {
+	keyStr, ok := lookup(secretsKeyEnv)
+	if !ok || keyStr == "" {
+		p, pOK := lookup(secretsKeyEnv + envFileSuffix)
+		if !pOK {
+			return nil, nil
+		}
+
+		var data []byte
+		data, err = os.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", secretsKeyEnv+envFileSuffix, err)
+		}
+
+		keyStr = string(data)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(keyStr))
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", secretsKeyEnv, err)
+	} else if len(key) != secretsKeyLen {
+		return nil, fmt.Errorf(
+			"%s: key length: got %d bytes, want %d",
+			secretsKeyEnv,
+			len(key),
+			secretsKeyLen,
+		)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("creating aead: %w", err)
+	}
+
+	return &secretsCipher{
+		aead:   aead,
+		mu:     &sync.Mutex{},
+		sealed: map[string]string{},
+		paths:  container.NewMapSet[string](),
+	}, nil
+}
+
+// encryptConfigFile writes the configuration file data with the secrets
+// encrypted, if there are any unencrypted ones, so that they are encrypted as
+// soon as the key is set.  l must not be nil.
+func encryptConfigFile(
+	ctx context.Context,
+	l *slog.Logger,
+	confPath string,
+	data []byte,
+	sc *secretsCipher,
+) (err error) {
+	encrypted, err := sc.encrypt(data)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return err
+	} else if bytes.Equal(encrypted, data) {
+		return nil
+	}
+
+	l.InfoContext(ctx, "encrypting secrets in config file", "path", confPath)
+
+	err = maybe.WriteFile(confPath, encrypted, aghos.DefaultPermFile)
+	if err != nil {
+		return fmt.Errorf("writing config with encrypted secrets: %w", err)
+	}
+
+	return nil
+}
+
+// decryptScalar replaces the encrypted value of the scalar node n at path with
+// the decrypted one.
+func (sc *secretsCipher) decryptScalar(n *yaml.Node, path string) (err error) {
+	if sc == nil {
+		return fmt.Errorf("%s: %w", path, errNoSecretsKey)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(
+		strings.TrimPrefix(n.Value, encryptedValuePrefix),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: decoding encrypted value: %w", path, err)
+	}
+
+	nonceSize := sc.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return fmt.Errorf("%s: encrypted value: %w", path, errors.ErrOutOfRange)
+	}
+
+	plain, err := sc.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return fmt.Errorf("%s: decrypting value: %w", path, err)
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.sealed[string(plain)] = n.Value
+	sc.paths.Add(path)
+
+	n.Value = string(plain)
+	n.Tag = "!!str"
+	n.Style = 0
+
+	return nil
+}
+
+// encrypt returns the YAML data with the secrets encrypted.  res is data if sc
+// is nil or there is nothing to encrypt.
+func (sc *secretsCipher) encrypt(data []byte) (res []byte, err error) {
+	if sc == nil {
+		return data, nil
+	}
+
+	doc := &yaml.Node{}
+	err = yaml.Unmarshal(data, doc)
+	if err != nil {
+		return nil, fmt.Errorf("decoding config: %w", err)
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	changed := false
+	err = walkConfigScalars(doc, "", func(n *yaml.Node, path string) (wErr error) {
+		if !sc.isSecretLocked(path, n.Value) {
+			return nil
+		}
+
+		n.Value, wErr = sc.sealLocked(n.Value)
+		if wErr != nil {
+			return fmt.Errorf("%s: %w", path, wErr)
+		}
+
+		n.Tag = ""
+		n.Style = 0
+		changed = true
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encrypting secrets: %w", err)
+	} else if !changed {
+		return data, nil
+	}
+
+	buf := &bytes.Buffer{}
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+
+	err = enc.Encode(doc)
+	if err != nil {
+		return nil, fmt.Errorf("encoding config: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// isSecretLocked returns true if the value val at path should be encrypted.
+// sc.mu must be locked.
+func (sc *secretsCipher) isSecretLocked(path, val string) (ok bool) {
+	if val == "" ||
+		strings.HasPrefix(val, encryptedValuePrefix) ||
+		strings.Contains(val, envRefStart) {
+		// The references to the environment variables aren't secrets
+		// themselves, so keep them readable.
+		return false
+	} else if sc.paths.Has(path) {
+		// Only encrypt the sequence items, which have been encrypted, since the
+		// other ones have the same path.
+		_, sealed := sc.sealed[val]
+
+		return sealed || !strings.HasSuffix(path, "[]")
+	} else if path == "users.[].password" || path == "api_tokens.[].hash" {
+		// The password hashes of the web users and the hashes of the API
+		// tokens are safe to store as is.
+		return false
+	}
+
+	return isSecretConfigValue(path, val)
+}
+
+// isSecretConfigValue returns true if the value val at path, in the format of
+// [envSubst.path], is a secret.  val is empty for the values, which aren't
+// strings.
+func isSecretConfigValue(path, val string) (ok bool) {
+	parent, key := "", path
+	if i := strings.LastIndexByte(path, '.'); i >= 0 {
+		parent, key = path[:i], path[i+1:]
+	}
+
+	return secretConfigKeys.Has(key) ||
+		strings.HasSuffix("."+parent, ".dns_credentials") ||
+		hasURLCredentials(val)
+}
+
+// sealLocked returns the encrypted form of plain.  sc.mu must be locked.
+func (sc *secretsCipher) sealLocked(plain string) (sealed string, err error) {
+	if sealed, ok := sc.sealed[plain]; ok {
+		return sealed, nil
+	}
+
+	nonce := make([]byte, sc.aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return "", fmt.Errorf("generating nonce: %w", err)
+	}
+
+	ciphertext := sc.aead.Seal(nonce, nonce, []byte(plain), nil)
+	sealed = encryptedValuePrefix + base64.StdEncoding.EncodeToString(ciphertext)
+	sc.sealed[plain] = sealed
+
+	return sealed, nil
+}
+
+// hasURLCredentials returns true if val contains a URL with the user
+// information, like the upstreams with the credentials for DNS-over-HTTPS.
+func hasURLCredentials(val string) (ok bool) {
+	_, rest, ok := strings.Cut(val, "://")
+	if !ok {
+		return false
+	}
+
+	authority, _, _ := strings.Cut(rest, "/")
+
+	return strings.Contains(authority, "@")
+}
@@ -0,0 +1,792 @@
+package home
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/aghos"
+	"github.com/AdguardTeam/golibs/container"
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/httphdr"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+	"github.com/AdguardTeam/golibs/osutil/executil"
+	"github.com/AdguardTeam/golibs/timeutil"
+	"github.com/google/renameio/v2/maybe"
+	yaml "go.yaml.in/yaml/v4"
+)
+
+// syncMode is the role of the instance in the configuration sync.
+type syncMode string
+
+// Valid syncMode values.
+const (
+	// syncModeDisabled means that the configuration isn't synced.
+	syncModeDisabled syncMode = ""
+
+	// syncModePrimary means that the instance serves its configuration to the
+	// replicas and notifies them of the changes.
+	syncModePrimary syncMode = "primary"
+
+	// syncModeReplica means that the instance pulls the configuration from the
+	// primary one.
+	syncModeReplica syncMode = "replica"
+)
+
+// Valid names of the synced sections of the configuration.
+const (
+	syncSectionBlockedServices = "blocked_services"
+	syncSectionClients         = "clients"
+	syncSectionDNS             = "dns"
+	syncSectionFilters         = "filters"
+	syncSectionRewrites        = "rewrites"
+)
+
+// syncSectionKeys are the keys of the configuration file synced for each
+// section.  The keys of [syncSectionDNS] are the ones of the primary, except
+// for [syncLocalDNSKeys].
+var syncSectionKeys = map[string][][]string{
+	syncSectionBlockedServices: {{"filtering", "blocked_services"}},
+	syncSectionClients:         {{"clients", "persistent"}},
+	syncSectionDNS:             nil,
+	syncSectionFilters:         {{"filters"}, {"whitelist_filters"}, {"user_rules"}},
+	syncSectionRewrites:        {{"filtering", "rewrites"}, {"filtering", "rewrites_enabled"}},
+}
+
+// syncLocalDNSKeys are the DNS properties specific to the instance, which
+// aren't synced.
+var syncLocalDNSKeys = container.NewMapSet(
+	"bind_hosts",
+	"port",
+	"upstream_dns_file",
+)
+
+const (
+	// defaultSyncInterval is the default interval between the pulls of the
+	// configuration by a replica.
+	defaultSyncInterval = time.Hour
+
+	// minSyncInterval is the minimum interval between the pulls of the
+	// configuration by a replica.
+	minSyncInterval = time.Minute
+
+	// maxSyncSnapshotSize is the maximum size of the response with the
+	// configuration of the primary.
+	maxSyncSnapshotSize = 16 << 20
+)
+
+// syncConfig is the configuration of the sync of the configuration between the
+// instances.
+type syncConfig struct {
+	// Primary is the instance the configuration is pulled from.  It's only used
+	// in the replica mode.  The API token should have the "read" scope.
+	Primary *syncPeerConfig `yaml:"primary,omitempty"`
+
+	// Mode is the role of this instance.
+	Mode syncMode `yaml:"mode"`
+
+	// Replicas are the instances notified of the changes in the primary mode.
+	// The API tokens must have the "admin" scope.
+	Replicas []*syncPeerConfig `yaml:"replicas,omitempty"`
+
+	// Sections are the names of the parts of the configuration pulled by the
+	// replica.
+	Sections []string `yaml:"sections"`
+
+	// Interval is the interval between the pulls in the replica mode.
+	Interval timeutil.Duration `yaml:"interval"`
+}
+
+// syncPeerConfig is another instance taking part in the sync.
+type syncPeerConfig struct {
+	// URL is the base URL of the web interface of the instance, like
+	// "https://dns1.example:3000".
+	URL string `yaml:"url"`
+
+	// APIToken is the API token, with which the requests to the instance are
+	// made.
+	APIToken string `yaml:"api_token"`
+}
+
+// defaultSyncConfig returns the default configuration of the sync.
+func defaultSyncConfig() (c *syncConfig) {
+	return &syncConfig{
+		Mode:     syncModeDisabled,
+		Sections: slices.Sorted(maps.Keys(syncSectionKeys)),
+		Interval: timeutil.Duration(defaultSyncInterval),
+	}
+}
+
+// validate returns an error if c is invalid.  c may be nil.
+func (c *syncConfig) validate() (err error) {
+	if c == nil {
+		return nil
+	}
+
+	switch c.Mode {
+	case syncModeDisabled:
+		return nil
+	case syncModePrimary:
+		for i, r := range c.Replicas {
+			err = r.validate()
+			if err != nil {
+				return fmt.Errorf("replicas: at index %d: %w", i, err)
+			}
+		}
+
+		return nil
+	case syncModeReplica:
+		return c.validateReplica()
+	default:
+		return fmt.Errorf("mode: %w: %q", errors.ErrBadEnumValue, c.Mode)
+	}
+}
+
+// validateReplica returns an error if c is an invalid configuration of a
+// replica.
+func (c *syncConfig) validateReplica() (err error) {
+	if c.Primary == nil {
+		return fmt.Errorf("primary: %w", errors.ErrNoValue)
+	}
+
+	err = c.Primary.validate()
+	if err != nil {
+		return fmt.Errorf("primary: %w", err)
+	}
+
+	err = validateSyncSections(c.Sections)
+	if err != nil {
+		return fmt.Errorf("sections: %w", err)
+	}
+
+	if time.Duration(c.Interval) < minSyncInterval {
+		return fmt.Errorf(
+			"interval: %w: must be at least %s",
+			errors.ErrOutOfRange,
+			minSyncInterval,
+		)
+	}
+
+	return nil
+}
+
+// validateSyncSections returns an error if sections are empty or contain an
+// unknown section.
+func validateSyncSections(sections []string) (err error) {
+	if len(sections) == 0 {
+		return errors.ErrEmptyValue
+	}
+
+	for _, s := range sections {
+		if _, ok := syncSectionKeys[s]; !ok {
+			return fmt.Errorf("%w: %q", errors.ErrBadEnumValue, s)
+		}
+	}
+
+	return nil
+}
+
+// validate returns an error if c is invalid.
+func (c *syncPeerConfig) validate() (err error) {
+	if c == nil {
+		return errors.ErrNoValue
+	}
+
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return fmt.Errorf("url: %w", err)
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("url: scheme: %w: %q", errors.ErrBadEnumValue, u.Scheme)
+	} else if u.Host == "" {
+		return fmt.Errorf("url: host: %w", errors.ErrNoValue)
+	}
+
+	if c.APIToken == "" {
+		return fmt.Errorf("api_token: %w", errors.ErrNoValue)
+	}
+
+	return nil
+}
+
+// newRequest returns a new request to the API of the peer at path with the API
+// token.
+func (c *syncPeerConfig) newRequest(
+	ctx context.Context,
+	method string,
+	path string,
+	body io.Reader,
+) (req *http.Request, err error) {
+	u := strings.TrimSuffix(c.URL, "/") + path
+	req, err = http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return nil, err
+	}
+
+	req.Header.Set(httphdr.Authorization, bearerTokenPrefix+c.APIToken)
+	if body != nil {
+		req.Header.Set(httphdr.ContentType, aghhttp.HdrValApplicationJSON)
+	}
+
+	return req, nil
+}
+
+// syncSectionPaths returns the keys of the configuration file synced for
+// sections.  root is the top-level mapping of the primary's configuration.
+func syncSectionPaths(root *yaml.Node, sections []string) (paths [][]string) {
+	for _, s := range sections {
+		if s != syncSectionDNS {
+			paths = append(paths, syncSectionKeys[s]...)
+
+			continue
+		}
+
+		dns := mappingValue(root, syncSectionDNS)
+		if dns == nil || dns.Kind != yaml.MappingNode {
+			continue
+		}
+
+		for i := 0; i+1 < len(dns.Content); i += 2 {
+			if k := dns.Content[i].Value; !syncLocalDNSKeys.Has(k) {
+				paths = append(paths, []string{syncSectionDNS, k})
+			}
+		}
+	}
+
+	return paths
+}
+
+// syncSnapshot returns the YAML data with the sections of the configuration
+// conf.  conf must be locked for reading.
+func syncSnapshot(conf *configuration, sections []string) (data []byte, err error) {
+	encoded, err := yaml.Marshal(conf)
+	if err != nil {
+		return nil, fmt.Errorf("encoding config: %w", err)
+	}
+
+	doc := &yaml.Node{}
+	err = yaml.Unmarshal(encoded, doc)
+	if err != nil {
+		return nil, fmt.Errorf("decoding config: %w", err)
+	}
+
+	root := docMapping(doc)
+	snapshot := &yaml.Node{Kind: yaml.MappingNode}
+	for _, keys := range syncSectionPaths(root, sections) {
+		if v := nodeAt(root, keys); v != nil {
+			setNodeAt(snapshot, keys, v)
+		}
+	}
+
+	return yaml.Marshal(snapshot)
+}
+
+// mergeSyncSnapshot returns the configuration file data with the sections
+// replaced with the ones from the snapshot of the primary.  cur is the
+// encoded current configuration, with which the snapshot is compared, since
+// fileData may contain the encrypted values and the references to the
+// environment variables.  changed is false if the sections are the same.
+func mergeSyncSnapshot(
+	fileData []byte,
+	cur []byte,
+	snapshot []byte,
+	sections []string,
+) (res []byte, changed bool, err error) {
+	fileDoc, curDoc, snapDoc := &yaml.Node{}, &yaml.Node{}, &yaml.Node{}
+	for _, d := range []struct {
+		node *yaml.Node
+		name string
+		data []byte
+	}{{
+		node: fileDoc,
+		name: "config file",
+		data: fileData,
+	}, {
+		node: curDoc,
+		name: "current config",
+		data: cur,
+	}, {
+		node: snapDoc,
+		name: "snapshot",
+		data: snapshot,
+	}} {
+		err = yaml.Unmarshal(d.data, d.node)
+		if err != nil {
+			return nil, false, fmt.Errorf("decoding %s: %w", d.name, err)
+		}
+	}
+
+	fileRoot, curRoot, snapRoot := docMapping(fileDoc), docMapping(curDoc), docMapping(snapDoc)
+	if fileRoot == nil || snapRoot == nil {
+		return nil, false, errors.Error("top-level value must be a mapping")
+	}
+
+	for _, keys := range syncSectionPaths(snapRoot, sections) {
+		v := nodeAt(snapRoot, keys)
+		if v == nil || nodesEqual(nodeAt(curRoot, keys), v) {
+			continue
+		}
+
+		setNodeAt(fileRoot, keys, v)
+		changed = true
+	}
+
+	if !changed {
+		return fileData, false, nil
+	}
+
+	buf := &bytes.Buffer{}
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+
+	err = enc.Encode(fileDoc)
+	if err != nil {
+		return nil, false, fmt.Errorf("encoding config: %w", err)
+	}
+
+	return buf.Bytes(), true, nil
+}
+
+// nodeAt returns the value at keys in the mapping node root or nil if there is
+// none.
+func nodeAt(root *yaml.Node, keys []string) (v *yaml.Node) {
+	v = root
+	for _, k := range keys {
+		if v == nil || v.Kind != yaml.MappingNode {
+			return nil
+		}
+
+		v = mappingValue(v, k)
+	}
+
+	return v
+}
+
+// setNodeAt sets the value at keys in the mapping node root to v creating the
+// mappings as necessary.
+func setNodeAt(root *yaml.Node, keys []string, v *yaml.Node) {
+	m := root
+	for _, k := range keys[:len(keys)-1] {
+		next := mappingValue(m, k)
+		if next == nil || next.Kind != yaml.MappingNode {
+			next = &yaml.Node{Kind: yaml.MappingNode}
+			setMappingValue(m, k, next)
+		}
+
+		m = next
+	}
+
+	setMappingValue(m, keys[len(keys)-1], v)
+}
+
+// setMappingValue sets the value of key in the mapping node m to v.
+func setMappingValue(m *yaml.Node, key string, v *yaml.Node) {
+	if i := mappingIndex(m, key); i >= 0 {
+		m.Content[i+1] = v
+
+		return
+	}
+
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, v)
+}
+
+// nodesEqual returns true if the nodes a and b have the same values.  a may be
+// nil.
+func nodesEqual(a, b *yaml.Node) (ok bool) {
+	if a == nil {
+		return false
+	}
+
+	var av, bv any
+	if a.Decode(&av) != nil || b.Decode(&bv) != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(av, bv)
+}
+
+// syncStatus is the status of the sync of a replica.
+type syncStatus struct {
+	// LastAttempt is the time of the last attempt to pull the configuration.
+	LastAttempt time.Time `json:"last_attempt,omitzero"`
+
+	// LastSuccess is the time of the last successful pull.
+	LastSuccess time.Time `json:"last_success,omitzero"`
+
+	// LastChange is the time of the last pull, which has changed the
+	// configuration.
+	LastChange time.Time `json:"last_change,omitzero"`
+
+	// LastError is the error of the last attempt.  It's empty if the attempt
+	// has succeeded.
+	LastError string `json:"last_error,omitempty"`
+}
+
+// syncReplica pulls the configuration from the primary instance.  Since the
+// most of the settings can't be changed without restarting the modules, the
+// replica writes the changed configuration file and restarts.
+type syncReplica struct {
+	logger       *slog.Logger
+	client       *http.Client
+	conf         *syncConfig
+	confModifier *defaultConfigModifier
+
+	// restart restarts AdGuard Home.
+	restart func(ctx context.Context)
+
+	// trigger makes the replica pull the configuration immediately.
+	trigger chan struct{}
+
+	// mu protects status.
+	mu *sync.Mutex
+
+	status *syncStatus
+
+	// confPath is the path to the configuration file.
+	confPath string
+}
+
+// syncReplicaConfig is the configuration structure for [newSyncReplica].
+type syncReplicaConfig struct {
+	// Logger is used for logging the sync.  It must not be nil.
+	Logger *slog.Logger
+
+	// Client is used for requesting the primary.  It must not be nil.
+	Client *http.Client
+
+	// Conf is the configuration of the sync.  It must be valid and in the
+	// replica mode.
+	Conf *syncConfig
+
+	// ConfModifier writes the configuration file.  It must not be nil.
+	ConfModifier *defaultConfigModifier
+
+	// Restart restarts AdGuard Home after the configuration file has been
+	// replaced.  It must not be nil.
+	Restart func(ctx context.Context)
+
+	// ConfPath is the path to the configuration file.
+	ConfPath string
+}
+
+// newSyncReplica returns a new properly initialized *syncReplica.  c must not
+// be nil.
+func newSyncReplica(c *syncReplicaConfig) (r *syncReplica) {
+	return &syncReplica{
+		logger:       c.Logger,
+		client:       c.Client,
+		conf:         c.Conf,
+		confModifier: c.ConfModifier,
+		restart:      c.Restart,
+		trigger:      make(chan struct{}, 1),
+		mu:           &sync.Mutex{},
+		status:       &syncStatus{},
+		confPath:     c.ConfPath,
+	}
+}
+
+// run pulls the configuration on start, on schedule, and when triggered.  It's
+// intended to be used as a goroutine.  r may be nil.
+func (r *syncReplica) run(ctx context.Context) {
+	if r == nil {
+		return
+	}
+
+	defer slogutil.RecoverAndLog(ctx, r.logger)
+
+	ticker := time.NewTicker(time.Duration(r.conf.Interval))
+	defer ticker.Stop()
+
+	for {
+		changed, err := r.pull(ctx)
+		r.setStatus(changed, err)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "pulling config", slogutil.KeyError, err)
+		} else if changed {
+			r.logger.InfoContext(ctx, "config pulled from primary; restarting")
+			r.restart(ctx)
+
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.trigger:
+		}
+	}
+}
+
+// triggerPull makes the replica pull the configuration as soon as possible.
+func (r *syncReplica) triggerPull() {
+	select {
+	case r.trigger <- struct{}{}:
+	default:
+		// The pull is already pending.
+	}
+}
+
+// setStatus updates the status after a pull.
+func (r *syncReplica) setStatus(changed bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.status.LastAttempt = now
+	if err != nil {
+		r.status.LastError = err.Error()
+
+		return
+	}
+
+	r.status.LastError = ""
+	r.status.LastSuccess = now
+	if changed {
+		r.status.LastChange = now
+	}
+}
+
+// currentStatus returns a copy of the status.
+func (r *syncReplica) currentStatus() (s *syncStatus) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cloned := *r.status
+
+	return &cloned
+}
+
+// pull requests the configuration from the primary and writes it to the
+// configuration file, if it has changed.
+func (r *syncReplica) pull(ctx context.Context) (changed bool, err error) {
+	snapshot, err := r.requestSnapshot(ctx)
+	if err != nil {
+		return false, fmt.Errorf("requesting primary: %w", err)
+	}
+
+	cm := r.confModifier
+	cm.changesMu.Lock()
+	defer cm.changesMu.Unlock()
+
+	if cm.replaced || cm.history.isRolledBack() {
+		// Restart is pending.
+		return false, nil
+	}
+
+	fileData, err := os.ReadFile(r.confPath)
+	if err != nil {
+		return false, fmt.Errorf("reading config: %w", err)
+	}
+
+	config.RLock()
+	cur, err := yaml.Marshal(config)
+	config.RUnlock()
+	if err != nil {
+		return false, fmt.Errorf("encoding current config: %w", err)
+	}
+
+	data, changed, err := mergeSyncSnapshot(fileData, cur, snapshot, r.conf.Sections)
+	if err != nil || !changed {
+		return false, err
+	}
+
+	data, err = config.secrets.encrypt(data)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return false, err
+	}
+
+	if errs := checkConfigData(ctx, r.logger, data, filepath.Dir(r.confPath)); len(errs) > 0 {
+		return false, &configInvalidError{errs: errs}
+	}
+
+	err = maybe.WriteFile(r.confPath, data, aghos.DefaultPermFile)
+	if err != nil {
+		return false, fmt.Errorf("writing config: %w", err)
+	}
+
+	cm.replaced = true
+
+	if cm.history != nil {
+		err = cm.history.record(ctx, fileData, data, configRevisionSourceSync)
+		if err != nil {
+			// The configuration file has already been written, so only log
+			// the error.
+			r.logger.ErrorContext(ctx, "recording synced config", slogutil.KeyError, err)
+		}
+	}
+
+	return true, nil
+}
+
+// requestSnapshot returns the YAML data with the sections of the configuration
+// of the primary.
+func (r *syncReplica) requestSnapshot(ctx context.Context) (snapshot []byte, err error) {
+	path := "/control/sync/snapshot?" + url.Values{
+		"sections": {strings.Join(r.conf.Sections, ",")},
+	}.Encode()
+
+	req, err := r.conf.Primary.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return nil, err
+	}
+	defer func() { err = errors.WithDeferred(err, resp.Body.Close()) }()
+
+	body := io.LimitReader(resp.Body, maxSyncSnapshotSize)
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(body, 512))
+
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+
+	snapResp := &syncSnapshotResp{}
+	err = json.NewDecoder(body).Decode(snapResp)
+	if err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+
+	return []byte(snapResp.Config), nil
+}
+
+// syncPrimary notifies the replicas of the changes of the configuration, so
+// that they pull it without waiting for the next scheduled pull.
+type syncPrimary struct {
+	logger   *slog.Logger
+	client   *http.Client
+	replicas []*syncPeerConfig
+
+	// notifications has a value when the replicas should be notified.
+	notifications chan struct{}
+}
+
+// newSyncPrimary returns a new properly initialized *syncPrimary.  All
+// arguments must not be nil.
+func newSyncPrimary(
+	l *slog.Logger,
+	client *http.Client,
+	replicas []*syncPeerConfig,
+) (p *syncPrimary) {
+	return &syncPrimary{
+		logger:        l,
+		client:        client,
+		replicas:      replicas,
+		notifications: make(chan struct{}, 1),
+	}
+}
+
+// notify makes p notify the replicas of a change.  It doesn't block.  p may be
+// nil.
+func (p *syncPrimary) notify() {
+	if p == nil {
+		return
+	}
+
+	select {
+	case p.notifications <- struct{}{}:
+	default:
+		// The notification is already pending.
+	}
+}
+
+// run notifies the replicas of the changes.  It's intended to be used as a
+// goroutine.  p may be nil.
+func (p *syncPrimary) run(ctx context.Context) {
+	if p == nil {
+		return
+	}
+
+	defer slogutil.RecoverAndLog(ctx, p.logger)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.notifications:
+		}
+
+		for _, r := range p.replicas {
+			err := p.push(ctx, r)
+			if err != nil {
+				p.logger.WarnContext(ctx, "notifying replica", "url", r.URL, slogutil.KeyError, err)
+			}
+		}
+	}
+}
+
+// push asks the replica to pull the configuration.
+func (p *syncPrimary) push(ctx context.Context, r *syncPeerConfig) (err error) {
+	req, err := r.newRequest(ctx, http.MethodPost, "/control/sync/trigger", strings.NewReader("{}"))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return err
+	}
+	defer func() { err = errors.WithDeferred(err, resp.Body.Close()) }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// newConfigSync returns the replica or the primary of the configuration sync
+// depending on the mode in conf.  Both are nil if the sync is disabled.  All
+// arguments must not be nil.
+func newConfigSync(
+	ctx context.Context,
+	baseLogger *slog.Logger,
+	conf *syncConfig,
+	tlsMgr *tlsManager,
+	cm *defaultConfigModifier,
+	cmdCons executil.CommandConstructor,
+	runningAsService bool,
+	workDir string,
+	confPath string,
+) (r *syncReplica, p *syncPrimary) {
+	l := baseLogger.With(slogutil.KeyPrefix, "config_sync")
+
+	switch conf.Mode {
+	case syncModePrimary:
+		p = newSyncPrimary(l, httpClient(tlsMgr), conf.Replicas)
+		cm.setSyncPrimary(p)
+
+		return nil, p
+	case syncModeReplica:
+		return newSyncReplica(&syncReplicaConfig{
+			Logger:       l,
+			Client:       httpClient(tlsMgr),
+			Conf:         conf,
+			ConfModifier: cm,
+			Restart:      newRestartFunc(l, cmdCons, runningAsService),
+			ConfPath:     configFilePath(ctx, l, workDir, confPath),
+		}), nil
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,169 @@
+package home
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/AdguardTeam/golibs/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yaml "go.yaml.in/yaml/v4"
+)
+
+func TestMergeSyncSnapshot(t *testing.T) {
+	t.Parallel()
+
+	sections := defaultSyncConfig().Sections
+
+	primary := newDefaultConfig()
+	primary.UserRules = []string{"||blocked.example^"}
+	primary.DNS.UpstreamDNS = []string{"9.9.9.9"}
+	primary.DNS.Port = 5353
+
+	snapshot, err := syncSnapshot(primary, sections)
+	require.NoError(t, err)
+
+	replica := newDefaultConfig()
+	replica.Language = "de"
+	replica.DNS.UpstreamDNS = []string{"1.1.1.1"}
+	replica.DNS.Port = 53
+
+	fileData, err := yaml.Marshal(replica)
+	require.NoError(t, err)
+
+	res, changed, err := mergeSyncSnapshot(fileData, fileData, snapshot, sections)
+	require.NoError(t, err)
+
+	assert.True(t, changed)
+
+	got := newDefaultConfig()
+	require.NoError(t, yaml.Unmarshal(res, got))
+
+	assert.Equal(t, []string{"||blocked.example^"}, got.UserRules)
+	assert.Equal(t, []string{"9.9.9.9"}, got.DNS.UpstreamDNS)
+	assert.Equal(t, uint16(53), got.DNS.Port)
+	assert.Equal(t, "de", got.Language)
+
+	t.Run("unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		unchangedRes, unchanged, mergeErr := mergeSyncSnapshot(res, res, snapshot, sections)
+		require.NoError(t, mergeErr)
+
+		assert.False(t, unchanged)
+		assert.Equal(t, res, unchangedRes)
+	})
+
+	t.Run("sections", func(t *testing.T) {
+		t.Parallel()
+
+		filtersRes, filtersChanged, mergeErr := mergeSyncSnapshot(
+			fileData,
+			fileData,
+			snapshot,
+			[]string{syncSectionFilters},
+		)
+		require.NoError(t, mergeErr)
+
+		assert.True(t, filtersChanged)
+
+		filtersGot := newDefaultConfig()
+		require.NoError(t, yaml.Unmarshal(filtersRes, filtersGot))
+
+		assert.Equal(t, []string{"||blocked.example^"}, filtersGot.UserRules)
+		assert.Equal(t, []string{"1.1.1.1"}, filtersGot.DNS.UpstreamDNS)
+	})
+}
+
+func TestSyncConfig_validate(t *testing.T) {
+	t.Parallel()
+
+	peer := &syncPeerConfig{
+		URL:      "https://primary.example:3000",
+		APIToken: "token",
+	}
+
+	testCases := []struct {
+		conf       *syncConfig
+		name       string
+		wantErrMsg string
+	}{{
+		conf:       nil,
+		name:       "nil",
+		wantErrMsg: "",
+	}, {
+		conf:       defaultSyncConfig(),
+		name:       "default",
+		wantErrMsg: "",
+	}, {
+		conf: &syncConfig{
+			Primary:  peer,
+			Mode:     syncModeReplica,
+			Sections: []string{syncSectionFilters},
+			Interval: timeutil.Duration(time.Hour),
+		},
+		name:       "replica",
+		wantErrMsg: "",
+	}, {
+		conf: &syncConfig{
+			Mode:     syncModeReplica,
+			Sections: []string{syncSectionFilters},
+			Interval: timeutil.Duration(time.Hour),
+		},
+		name:       "no_primary",
+		wantErrMsg: "primary: " + errors.ErrNoValue.Error(),
+	}, {
+		conf: &syncConfig{
+			Primary: &syncPeerConfig{
+				URL:      "ftp://primary.example",
+				APIToken: "token",
+			},
+			Mode:     syncModeReplica,
+			Sections: []string{syncSectionFilters},
+			Interval: timeutil.Duration(time.Hour),
+		},
+		name:       "bad_scheme",
+		wantErrMsg: `primary: url: scheme: bad enum value: "ftp"`,
+	}, {
+		conf: &syncConfig{
+			Primary:  peer,
+			Mode:     syncModeReplica,
+			Sections: []string{"users"},
+			Interval: timeutil.Duration(time.Hour),
+		},
+		name:       "bad_section",
+		wantErrMsg: `sections: bad enum value: "users"`,
+	}, {
+		conf: &syncConfig{
+			Primary:  peer,
+			Mode:     syncModeReplica,
+			Sections: []string{syncSectionFilters},
+			Interval: timeutil.Duration(time.Second),
+		},
+		name:       "short_interval",
+		wantErrMsg: "interval: out of range: must be at least 1m0s",
+	}, {
+		conf: &syncConfig{
+			Mode:     syncModePrimary,
+			Replicas: []*syncPeerConfig{peer, {URL: "http://replica.example"}},
+		},
+		name:       "bad_replica",
+		wantErrMsg: "replicas: at index 1: api_token: " + errors.ErrNoValue.Error(),
+	}, {
+		conf: &syncConfig{
+			Mode: "secondary",
+		},
+		name:       "bad_mode",
+		wantErrMsg: `mode: bad enum value: "secondary"`,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			testutil.AssertErrorMsg(t, tc.wantErrMsg, tc.conf.validate())
+		})
+	}
+}
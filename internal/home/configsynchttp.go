@@ -0,0 +1,157 @@
+package home
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+)
+
+// registerConfigSyncHandlers registers the HTTP handlers of the sync of the
+// configuration between the instances.
+func (web *webAPI) registerConfigSyncHandlers() {
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodGet,
+		"/control/sync/snapshot",
+		web.handleGetSyncSnapshot,
+		&aghhttp.RouteInfo{
+			Summary: "Get the synced sections of the configuration of the primary",
+			Description: "The optional \"sections\" query parameter is the " +
+				"comma-separated list of the sections, all of them by default.  " +
+				"Only available in the primary mode.",
+			Response: syncSnapshotResp{},
+		},
+	)
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodPost,
+		"/control/sync/trigger",
+		web.handleSyncTrigger,
+		&aghhttp.RouteInfo{
+			Summary: "Make the replica pull the configuration from the primary",
+			Description: "The pull is asynchronous.  AdGuard Home restarts if the " +
+				"configuration has changed.  Only available in the replica mode.",
+		},
+	)
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodGet,
+		"/control/sync/status",
+		web.handleGetSyncStatus,
+		&aghhttp.RouteInfo{
+			Summary:  "Get the status of the configuration sync",
+			Response: syncStatusResp{},
+		},
+	)
+}
+
+// syncSnapshotResp is the response to the GET /control/sync/snapshot HTTP API.
+type syncSnapshotResp struct {
+	// Config is the YAML data with the synced sections of the configuration.
+	Config string `json:"config"`
+}
+
+// handleGetSyncSnapshot is the handler for the GET /control/sync/snapshot HTTP
+// API.
+func (web *webAPI) handleGetSyncSnapshot(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	l := web.logger
+
+	config.RLock()
+	defer config.RUnlock()
+
+	if config.Sync.Mode != syncModePrimary {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusConflict, "not in primary mode")
+
+		return
+	}
+
+	sections := config.Sync.Sections
+	if s := r.URL.Query().Get("sections"); s != "" {
+		sections = strings.Split(s, ",")
+	}
+
+	err := validateSyncSections(sections)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusBadRequest, "sections: %s", err)
+
+		return
+	}
+
+	data, err := syncSnapshot(config, sections)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusInternalServerError, "%s", err)
+
+		return
+	}
+
+	aghhttp.WriteJSONResponseOK(ctx, l, w, r, &syncSnapshotResp{
+		Config: string(data),
+	})
+}
+
+// handleSyncTrigger is the handler for the POST /control/sync/trigger HTTP
+// API.
+func (web *webAPI) handleSyncTrigger(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	l := web.logger
+
+	replica := web.conf.syncReplica
+	if replica == nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusConflict, "not in replica mode")
+
+		return
+	}
+
+	replica.triggerPull()
+
+	aghhttp.OK(ctx, l, w)
+}
+
+// syncStatusResp is the response to the GET /control/sync/status HTTP API.
+type syncStatusResp struct {
+	// Status is the status of the pulls.  It's nil unless the instance is a
+	// replica.
+	Status *syncStatus `json:"status,omitempty"`
+
+	// Mode is the role of the instance.
+	Mode syncMode `json:"mode"`
+
+	// Primary is the URL of the primary instance.  It's empty unless the
+	// instance is a replica.
+	Primary string `json:"primary,omitempty"`
+
+	// Replicas are the URLs of the notified replicas.
+	Replicas []string `json:"replicas"`
+
+	// Sections are the names of the synced sections.
+	Sections []string `json:"sections"`
+}
+
+// handleGetSyncStatus is the handler for the GET /control/sync/status HTTP API.
+func (web *webAPI) handleGetSyncStatus(w http.ResponseWriter, r *http.Request) {
+	config.RLock()
+	resp := &syncStatusResp{
+		Mode:     config.Sync.Mode,
+		Replicas: []string{},
+		Sections: config.Sync.Sections,
+	}
+
+	if p := config.Sync.Primary; p != nil && resp.Mode == syncModeReplica {
+		resp.Primary = p.URL
+	}
+
+	if resp.Mode == syncModePrimary {
+		for _, rc := range config.Sync.Replicas {
+			resp.Replicas = append(resp.Replicas, rc.URL)
+		}
+	}
+	config.RUnlock()
+
+	if replica := web.conf.syncReplica; replica != nil {
+		resp.Status = replica.currentStatus()
+	}
+
+	aghhttp.WriteJSONResponseOK(r.Context(), web.logger, w, r, resp)
+}
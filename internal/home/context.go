@@ -3,6 +3,7 @@ package home
 import (
 	"context"
 	"fmt"
+	"net/netip"
 
 	"github.com/AdguardTeam/AdGuardHome/internal/aghuser"
 	"github.com/AdguardTeam/golibs/errors"
@@ -13,6 +14,9 @@ type ctxKey uint8
 
 const (
 	ctxKeyWebUser ctxKey = iota
+	ctxKeyRemoteIP
+	ctxKeyUnixSocket
+	ctxKeyAuditRecord
 )
 
 // type check
@@ -23,6 +27,12 @@ func (k ctxKey) String() (s string) {
 	switch k {
 	case ctxKeyWebUser:
 		return "ctxKeyWebUser"
+	case ctxKeyRemoteIP:
+		return "ctxKeyRemoteIP"
+	case ctxKeyUnixSocket:
+		return "ctxKeyUnixSocket"
+	case ctxKeyAuditRecord:
+		return "ctxKeyAuditRecord"
 	default:
 		panic(fmt.Errorf("ctx key: %w: %d", errors.ErrBadEnumValue, k))
 	}
@@ -39,6 +49,29 @@ func withWebUser(ctx context.Context, u *aghuser.User) (withUser context.Context
 	return context.WithValue(ctx, ctxKeyWebUser, u)
 }
 
+// withRemoteIP returns a copy of the parent context with the address of the
+// web client added.
+func withRemoteIP(ctx context.Context, ip netip.Addr) (withIP context.Context) {
+	return context.WithValue(ctx, ctxKeyRemoteIP, ip)
+}
+
+// remoteIPFromContext returns the address of the web client from the context,
+// if any.
+func remoteIPFromContext(ctx context.Context) (ip netip.Addr, ok bool) {
+	const key = ctxKeyRemoteIP
+	v := ctx.Value(key)
+	if v == nil {
+		return netip.Addr{}, false
+	}
+
+	ip, ok = v.(netip.Addr)
+	if !ok {
+		panicBadType(key, v)
+	}
+
+	return ip, true
+}
+
 // webUserFromContext returns the web user from the context, if any.
 func webUserFromContext(ctx context.Context) (u *aghuser.User, ok bool) {
 	const key = ctxKeyWebUser
@@ -54,3 +87,49 @@ func webUserFromContext(ctx context.Context) (u *aghuser.User, ok bool) {
 
 	return u, true
 }
+
+// withUnixSocket returns a copy of the parent context marked as the one of a
+// connection to the Unix socket.
+func withUnixSocket(ctx context.Context) (withUnix context.Context) {
+	return context.WithValue(ctx, ctxKeyUnixSocket, true)
+}
+
+// isUnixSocket returns true if ctx is the context of a request received on the
+// Unix socket.
+func isUnixSocket(ctx context.Context) (ok bool) {
+	const key = ctxKeyUnixSocket
+	v := ctx.Value(key)
+	if v == nil {
+		return false
+	}
+
+	ok, isBool := v.(bool)
+	if !isBool {
+		panicBadType(key, v)
+	}
+
+	return ok
+}
+
+// withAuditRecord returns a copy of the parent context with the record of the
+// configuration changes of the audited request added.
+func withAuditRecord(ctx context.Context, rec *auditRecord) (withRec context.Context) {
+	return context.WithValue(ctx, ctxKeyAuditRecord, rec)
+}
+
+// auditRecordFromContext returns the record of the configuration changes of
+// the audited request from the context, if any.
+func auditRecordFromContext(ctx context.Context) (rec *auditRecord, ok bool) {
+	const key = ctxKeyAuditRecord
+	v := ctx.Value(key)
+	if v == nil {
+		return nil, false
+	}
+
+	rec, ok = v.(*auditRecord)
+	if !ok {
+		panicBadType(key, v)
+	}
+
+	return rec, true
+}
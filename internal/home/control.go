@@ -16,7 +16,6 @@ import (
 	"github.com/AdguardTeam/golibs/httphdr"
 	"github.com/AdguardTeam/golibs/netutil"
 	"github.com/AdguardTeam/golibs/netutil/urlutil"
-	"github.com/NYTimes/gziphandler"
 )
 
 // appendDNSAddrs is a convenient helper for appending a formatted form of DNS
@@ -110,6 +109,10 @@ type statusResponse struct {
 	// StartTime is the start time of the web API server in Unix milliseconds.
 	StartTime aghhttp.JSONTime `json:"start_time"`
 
+	// LastCrashTime is the time of the latest crash in Unix milliseconds.  It's
+	// nil if there are no crash reports.
+	LastCrashTime *aghhttp.JSONTime `json:"last_crash_time,omitempty"`
+
 	ProtectionEnabled bool `json:"protection_enabled"`
 	// TODO(e.burkov): Inspect if front-end doesn't requires this field as
 	// openapi.yaml declares.
@@ -167,6 +170,11 @@ func (web *webAPI) handleStatus(w http.ResponseWriter, r *http.Request) {
 		}
 	}()
 
+	if t := globalContext.lastCrashTime; !t.IsZero() {
+		crashTime := aghhttp.JSONTime(t)
+		resp.LastCrashTime = &crashTime
+	}
+
 	// IsDHCPAvailable field is now false by default for Windows.
 	if runtime.GOOS != "windows" {
 		resp.IsDHCPAvailable = globalContext.dhcpServer != nil
@@ -185,7 +193,16 @@ func (web *webAPI) registerControlHandlers() {
 	)
 	web.httpReg.Register(http.MethodPost, "/control/update", web.handleUpdate)
 
-	web.httpReg.Register(http.MethodGet, "/control/status", web.handleStatus)
+	aghhttp.RegisterVersions(
+		web.httpReg,
+		http.MethodGet,
+		"/control/status",
+		web.handleStatus,
+		newV1Versions(&aghhttp.RouteInfo{
+			Summary:  "Get DNS server current status and general settings",
+			Response: statusResponse{},
+		}),
+	)
 	web.httpReg.Register(
 		http.MethodPost,
 		"/control/i18n/change_language",
@@ -207,6 +224,23 @@ func (web *webAPI) registerControlHandlers() {
 
 	web.registerNotificationHandlers()
 	web.registerYouTubeHandlers()
+	web.registerSystemInfoHandlers()
+	web.registerOpenAPIHandlers()
+	web.registerMetricsHandlers()
+	web.registerAPITokenHandlers()
+	web.registerAuditLogHandlers()
+	web.registerEventHubHandlers()
+	web.registerSessionHandlers()
+	web.registerTOTPHandlers()
+	web.registerConfigCheckHandlers()
+	web.registerConfigHistoryHandlers()
+	web.registerPathsHandlers()
+	web.registerConfigSyncHandlers()
+	web.registerConfigReloadHandlers()
+	web.registerUpdateChannelHandlers()
+	web.registerDebugHandlers()
+	web.registerImportHandlers()
+	web.registerSupportBundleHandlers()
 
 	mobileConfHandler := newMobileConfigHandler(&mobileConfigHandlerConfig{
 		logger: web.baseLogger,
@@ -232,8 +266,8 @@ type webMw struct {
 	// running for the first time.
 	postInstallMw func(h http.Handler) (wrapped http.Handler)
 
-	// ensureMw is like postInstallMw, but also applies gzip and enforces the
-	// HTTP method.
+	// ensureMw is like postInstallMw, but also compresses the responses and
+	// enforces the HTTP method.
 	ensureMw aghhttp.WrapFunc
 }
 
@@ -242,7 +276,7 @@ func (mw *webMw) set(web *webAPI) {
 	mw.postInstallMw = web.postInstallHandler
 
 	mw.ensureMw = func(method string, h http.HandlerFunc) (wrapped http.Handler) {
-		return web.postInstallHandler(gziphandler.GzipHandler(web.ensure(method, h)))
+		return web.postInstallHandler(web.compress(web.ensure(method, h)))
 	}
 }
 
@@ -284,6 +318,10 @@ func (web *webAPI) ensure(
 
 			globalContext.controlLock.Lock()
 			defer globalContext.controlLock.Unlock()
+
+			web.serveAudited(w, r, handler)
+
+			return
 		}
 
 		handler(w, r)
@@ -364,12 +402,11 @@ func (web *webAPI) preInstallHandler(handler http.Handler) (wrapped http.Handler
 // HTTPS-related headers.  If proceed is true, the middleware must continue
 // handling the request.
 func (web *webAPI) handleHTTPSRedirect(w http.ResponseWriter, r *http.Request) (proceed bool) {
-	if web.httpsServer.server == nil {
+	ctx := r.Context()
+	if web.httpsServer.server == nil || isUnixSocket(ctx) {
 		return true
 	}
 
-	ctx := r.Context()
-
 	host, err := netutil.SplitHost(r.Host)
 	if err != nil {
 		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusBadRequest, "bad host: %s", err)
@@ -416,6 +453,11 @@ func (web *webAPI) handleHTTPSRedirect(w http.ResponseWriter, r *http.Request) (
 		respHdr.Set(httphdr.StrictTransportSecurity, aghhttp.HdrValStrictTransportSecurity)
 	}
 
+	// Don't override the origin allowed by the CORS policy.
+	if respHdr.Get(httphdr.AccessControlAllowOrigin) != "" {
+		return true
+	}
+
 	// Allow the frontend from the HTTP origin to send requests to the HTTPS
 	// server.  This can happen when the user has just set up HTTPS with
 	// redirects.  Prevent cache-related errors by setting the Vary header.
@@ -1,19 +1,24 @@
 package home
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"log/slog"
+	"maps"
 	"net/netip"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
 	"github.com/AdguardTeam/AdGuardHome/internal/dhcpd"
 	"github.com/AdguardTeam/AdGuardHome/internal/dnsforward"
 	"github.com/AdguardTeam/AdGuardHome/internal/filtering"
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
 	"github.com/AdguardTeam/AdGuardHome/internal/querylog"
 	"github.com/AdguardTeam/AdGuardHome/internal/stats"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
 	"github.com/AdguardTeam/golibs/netutil"
 	"github.com/AdguardTeam/golibs/timeutil"
 )
@@ -153,7 +158,44 @@ type exportClientsConfig struct {
 
 // exportNotificationsConfig is the notifications portion of the export.
 type exportNotificationsConfig struct {
-	Telegram *exportTelegramConfig `json:"telegram,omitempty"`
+	Telegram  *exportTelegramConfig `json:"telegram,omitempty"`
+	Matrix    *matrixConfig         `json:"matrix,omitempty"`
+	Gotify    *gotifyConfig         `json:"gotify,omitempty"`
+	MQTT      *mqttConfig           `json:"mqtt,omitempty"`
+	Signal    *signalConfig         `json:"signal,omitempty"`
+	PagerDuty *pagerDutyConfig      `json:"pagerduty,omitempty"`
+	Opsgenie  *opsgenieConfig       `json:"opsgenie,omitempty"`
+	Teams     *teamsConfig          `json:"teams,omitempty"`
+
+	Alertmanager *alertmanagerConfig `json:"alertmanager,omitempty"`
+
+	Templates map[string]string   `json:"templates,omitempty"`
+	Routes    map[string][]string `json:"routes,omitempty"`
+
+	QuietHours *quietHoursConfig `json:"quiet_hours,omitempty"`
+	Digest     *digestConfig     `json:"digest,omitempty"`
+
+	Escalation *escalationConfig `json:"escalation,omitempty"`
+
+	ConfigChanges *configChangesConfig `json:"config_changes,omitempty"`
+
+	DHCPEvents *dhcpEventsConfig `json:"dhcp_events,omitempty"`
+
+	BlockedSpike *blockedSpikeConfig `json:"blocked_spike,omitempty"`
+
+	UpdateAvailable *updateNotificationsConfig `json:"update_available,omitempty"`
+
+	StatsReport *statsReportConfig `json:"stats_report,omitempty"`
+
+	Heartbeat *heartbeatConfig `json:"heartbeat,omitempty"`
+
+	RateLimit *rateLimitConfig `json:"rate_limit,omitempty"`
+
+	Bandwidth *bandwidthConfig `json:"bandwidth,omitempty"`
+
+	SelfMonitor *selfMonitorConfig `json:"self_monitor,omitempty"`
+
+	DNSPerf *dnsPerfConfig `json:"dns_perf,omitempty"`
 }
 
 // exportTelegramConfig is the Telegram notification config for export.
@@ -167,6 +209,26 @@ type exportTelegramConfig struct {
 	CheckInterval   timeutil.Duration `json:"check_interval,omitempty"`
 	Cooldown        timeutil.Duration `json:"cooldown,omitempty"`
 	CustomMessage   string            `json:"custom_message,omitempty"`
+
+	ParseMode       string `json:"parse_mode,omitempty"`
+	MessageThreadID int64  `json:"message_thread_id,omitempty"`
+
+	Chats []telegramChatConfig `json:"chats,omitempty"`
+
+	AlertButtons bool `json:"alert_buttons,omitempty"`
+
+	Hysteresis map[string]float64 `json:"hysteresis,omitempty"`
+
+	SustainedChecks int `json:"sustained_checks,omitempty"`
+
+	Proxy string `json:"proxy,omitempty"`
+
+	TemperatureThreshold float64           `json:"temperature_threshold,omitempty"`
+	TemperatureCooldown  timeutil.Duration `json:"temperature_cooldown,omitempty"`
+
+	DiskPaths []diskPathConfig `json:"disk_paths,omitempty"`
+
+	SwapThreshold float64 `json:"swap_threshold,omitempty"`
 }
 
 // exportACMEConfig is the ACME ("SSL/TLS issue") portion of the export.  The
@@ -384,7 +446,52 @@ func (web *webAPI) handleExportSettings(w http.ResponseWriter, r *http.Request)
 
 	// Notifications config.
 	if tg := config.Notifications.Telegram; tg != nil {
+		matrix := config.Notifications.Matrix
+		gotify := config.Notifications.Gotify
+		mqtt := config.Notifications.MQTT
+		signal := config.Notifications.Signal
+		pagerDuty := config.Notifications.PagerDuty
+		opsgenie := config.Notifications.Opsgenie
+		teams := config.Notifications.Teams
+		alertmanager := config.Notifications.Alertmanager
+		quietHours := config.Notifications.QuietHours
+		digest := config.Notifications.Digest
+		escalation := config.Notifications.Escalation
+		configChanges := config.Notifications.ConfigChanges
+		dhcpEvents := config.Notifications.DHCPEvents
+		blockedSpike := config.Notifications.BlockedSpike
+		updateAvailable := config.Notifications.UpdateAvailable
+		statsReport := config.Notifications.StatsReport
+		heartbeat := config.Notifications.Heartbeat
+		rateLimit := config.Notifications.RateLimit
+		bandwidth := config.Notifications.Bandwidth
+		selfMonitor := config.Notifications.SelfMonitor
+		dnsPerf := config.Notifications.DNSPerf
 		export.Notifications = &exportNotificationsConfig{
+			Matrix:    &matrix,
+			Gotify:    &gotify,
+			MQTT:      &mqtt,
+			Signal:    &signal,
+			PagerDuty: &pagerDuty,
+			Opsgenie:  &opsgenie,
+			Teams:     &teams,
+
+			Alertmanager:    &alertmanager,
+			Templates:       maps.Clone(config.Notifications.Templates),
+			Routes:          maps.Clone(config.Notifications.Routes),
+			QuietHours:      &quietHours,
+			Digest:          &digest,
+			Escalation:      &escalation,
+			ConfigChanges:   &configChanges,
+			DHCPEvents:      &dhcpEvents,
+			BlockedSpike:    &blockedSpike,
+			UpdateAvailable: &updateAvailable,
+			StatsReport:     &statsReport,
+			Heartbeat:       &heartbeat,
+			RateLimit:       &rateLimit,
+			Bandwidth:       &bandwidth,
+			SelfMonitor:     &selfMonitor,
+			DNSPerf:         &dnsPerf,
 			Telegram: &exportTelegramConfig{
 				Enabled:         tg.Enabled,
 				BotToken:        tg.BotToken,
@@ -395,6 +502,20 @@ func (web *webAPI) handleExportSettings(w http.ResponseWriter, r *http.Request)
 				CheckInterval:   tg.CheckInterval,
 				Cooldown:        tg.Cooldown,
 				CustomMessage:   tg.CustomMessage,
+				ParseMode:       tg.ParseMode,
+				MessageThreadID: tg.MessageThreadID,
+				Chats:           slices.Clone(tg.Chats),
+				AlertButtons:    tg.AlertButtons,
+				Hysteresis:      maps.Clone(tg.Hysteresis),
+				SustainedChecks: tg.SustainedChecks,
+				Proxy:           tg.Proxy,
+
+				TemperatureThreshold: tg.TemperatureThreshold,
+				TemperatureCooldown:  tg.TemperatureCooldown,
+
+				DiskPaths: slices.Clone(tg.DiskPaths),
+
+				SwapThreshold: tg.SwapThreshold,
 			},
 		}
 	}
@@ -435,7 +556,7 @@ func (web *webAPI) handleImportSettings(w http.ResponseWriter, r *http.Request)
 		config.Lock()
 		defer config.Unlock()
 
-		err = applyImportedSettings(l, &imp)
+		err = applyImportedSettings(ctx, l, &imp)
 	}()
 
 	if err != nil {
@@ -453,12 +574,58 @@ func (web *webAPI) handleImportSettings(w http.ResponseWriter, r *http.Request)
 		ytManager.restart(ctx)
 	}
 
+	if imp.General != nil && imp.General.Language != "" {
+		updateNotificationLocale(ctx, l, imp.General.Language)
+	}
+
 	if imp.Notifications != nil && globalContext.notifier != nil {
 		func() {
 			config.RLock()
 			defer config.RUnlock()
 
 			globalContext.notifier.UpdateTelegramConfig(buildRuntimeTelegramConfig(config.Notifications.Telegram))
+			globalContext.notifier.UpdateChannelsConfig(buildRuntimeChannelsConfig(&config.Notifications))
+			globalContext.notifier.UpdateQuietHoursConfig(
+				buildRuntimeQuietHoursConfig(&config.Notifications.QuietHours),
+			)
+			globalContext.notifier.UpdateDigestConfig(buildRuntimeDigestConfig(&config.Notifications.Digest))
+			globalContext.notifier.UpdateEscalationConfig(
+				buildRuntimeEscalationConfig(&config.Notifications.Escalation),
+			)
+			globalContext.notifier.UpdateDHCPEventsConfig(
+				buildRuntimeDHCPEventsConfig(&config.Notifications.DHCPEvents),
+			)
+			globalContext.notifier.UpdateSpikeConfig(
+				buildRuntimeSpikeConfig(&config.Notifications.BlockedSpike),
+			)
+			globalContext.notifier.UpdateStatsReportConfig(
+				buildRuntimeStatsReportConfig(&config.Notifications.StatsReport),
+			)
+			globalContext.notifier.UpdateHeartbeatConfig(
+				buildRuntimeHeartbeatConfig(&config.Notifications.Heartbeat),
+			)
+			globalContext.notifier.UpdateRateLimitConfig(
+				buildRuntimeRateLimitConfig(&config.Notifications.RateLimit),
+			)
+			globalContext.notifier.UpdateBandwidthConfig(
+				buildRuntimeBandwidthConfig(&config.Notifications.Bandwidth),
+			)
+			globalContext.notifier.UpdateSelfMonitorConfig(
+				buildRuntimeSelfMonitorConfig(&config.Notifications.SelfMonitor),
+			)
+			globalContext.notifier.UpdateDNSPerfConfig(
+				buildRuntimeDNSPerfConfig(&config.Notifications.DNSPerf),
+			)
+
+			err = globalContext.notifier.UpdateTemplates(config.Notifications.Templates)
+			if err != nil {
+				l.ErrorContext(ctx, "applying imported message templates", slogutil.KeyError, err)
+			}
+
+			err = globalContext.notifier.UpdateRoutes(config.Notifications.Routes)
+			if err != nil {
+				l.ErrorContext(ctx, "applying imported notification routes", slogutil.KeyError, err)
+			}
 		}()
 	}
 
@@ -473,7 +640,7 @@ func (web *webAPI) handleImportSettings(w http.ResponseWriter, r *http.Request)
 
 // applyImportedSettings applies all imported settings to the running
 // configuration.
-func applyImportedSettings(_ *slog.Logger, imp *exportConfig) (err error) {
+func applyImportedSettings(ctx context.Context, _ *slog.Logger, imp *exportConfig) (err error) {
 	if imp.General != nil {
 		applyGeneralImport(imp.General)
 	}
@@ -515,6 +682,7 @@ func applyImportedSettings(_ *slog.Logger, imp *exportConfig) (err error) {
 		imp.DHCP.CommandConstructor = config.DHCP.CommandConstructor
 		imp.DHCP.ConfModifier = config.DHCP.ConfModifier
 		imp.DHCP.HTTPReg = config.DHCP.HTTPReg
+		imp.DHCP.OnLeaseEvent = config.DHCP.OnLeaseEvent
 		imp.DHCP.WorkDir = config.DHCP.WorkDir
 		imp.DHCP.DataDir = config.DHCP.DataDir
 
@@ -530,7 +698,7 @@ func applyImportedSettings(_ *slog.Logger, imp *exportConfig) (err error) {
 	}
 
 	if imp.Notifications != nil {
-		applyNotificationsImport(imp.Notifications)
+		applyNotificationsImport(ctx, imp.Notifications)
 	}
 
 	if imp.YouTube != nil {
@@ -770,7 +938,99 @@ func applyTLSImport(tls *tlsConfigSettings) {
 }
 
 // applyNotificationsImport applies the imported notification settings.
-func applyNotificationsImport(notif *exportNotificationsConfig) {
+func applyNotificationsImport(ctx context.Context, notif *exportNotificationsConfig) {
+	if notif.Matrix != nil && notif.Matrix.normalize() == nil {
+		config.Notifications.Matrix = *notif.Matrix
+	}
+
+	if notif.Gotify != nil && notif.Gotify.normalize() == nil {
+		config.Notifications.Gotify = *notif.Gotify
+	}
+
+	if notif.MQTT != nil && notif.MQTT.normalize() == nil {
+		config.Notifications.MQTT = *notif.MQTT
+	}
+
+	if notif.Signal != nil && notif.Signal.normalize() == nil {
+		config.Notifications.Signal = *notif.Signal
+	}
+
+	if notif.PagerDuty != nil && notif.PagerDuty.normalize() == nil {
+		config.Notifications.PagerDuty = *notif.PagerDuty
+	}
+
+	if notif.Opsgenie != nil && notif.Opsgenie.normalize() == nil {
+		config.Notifications.Opsgenie = *notif.Opsgenie
+	}
+
+	if notif.Teams != nil && notif.Teams.normalize() == nil {
+		config.Notifications.Teams = *notif.Teams
+	}
+
+	if notif.Alertmanager != nil && notif.Alertmanager.normalize() == nil {
+		config.Notifications.Alertmanager = *notif.Alertmanager
+	}
+
+	if notif.Templates != nil && validateNotificationTemplates(ctx, notif.Templates) == nil {
+		config.Notifications.Templates = notif.Templates
+	}
+
+	if notif.Routes != nil && notifications.ValidateRoutes(notif.Routes) == nil {
+		config.Notifications.Routes = notif.Routes
+	}
+
+	if notif.QuietHours != nil && notif.QuietHours.normalize() == nil {
+		config.Notifications.QuietHours = *notif.QuietHours
+	}
+
+	if notif.Digest != nil && notif.Digest.normalize() == nil {
+		config.Notifications.Digest = *notif.Digest
+	}
+
+	if notif.Escalation != nil && notif.Escalation.normalize() == nil {
+		config.Notifications.Escalation = *notif.Escalation
+	}
+
+	if notif.ConfigChanges != nil && notif.ConfigChanges.normalize() == nil {
+		config.Notifications.ConfigChanges = *notif.ConfigChanges
+	}
+
+	if notif.DHCPEvents != nil {
+		config.Notifications.DHCPEvents = *notif.DHCPEvents
+	}
+
+	if notif.BlockedSpike != nil && notif.BlockedSpike.normalize() == nil {
+		config.Notifications.BlockedSpike = *notif.BlockedSpike
+	}
+
+	if notif.UpdateAvailable != nil {
+		config.Notifications.UpdateAvailable = *notif.UpdateAvailable
+	}
+
+	if notif.StatsReport != nil && notif.StatsReport.normalize() == nil {
+		config.Notifications.StatsReport = *notif.StatsReport
+	}
+
+	if notif.Heartbeat != nil && notif.Heartbeat.normalize() == nil {
+		config.Notifications.Heartbeat = *notif.Heartbeat
+	}
+
+	if notif.RateLimit != nil && notif.RateLimit.normalize() == nil {
+		config.Notifications.RateLimit = *notif.RateLimit
+	}
+
+	if notif.Bandwidth != nil && notif.Bandwidth.normalize() == nil {
+		config.Notifications.Bandwidth = *notif.Bandwidth
+	}
+
+	if notif.SelfMonitor != nil && notif.SelfMonitor.normalize() == nil {
+		config.Notifications.SelfMonitor = *notif.SelfMonitor
+	}
+
+	if notif.DNSPerf != nil && notif.DNSPerf.normalize() == nil {
+		config.Notifications.DNSPerf = *notif.DNSPerf
+	}
+
 	if notif.Telegram == nil {
 		return
 	}
@@ -803,6 +1063,47 @@ func applyNotificationsImport(notif *exportNotificationsConfig) {
 		config.Notifications.Telegram.Cooldown = tg.Cooldown
 	}
 	config.Notifications.Telegram.CustomMessage = tg.CustomMessage
+	if notifications.ValidateParseMode(notifications.ParseMode(tg.ParseMode)) == nil {
+		config.Notifications.Telegram.ParseMode = tg.ParseMode
+	}
+
+	if tg.MessageThreadID >= 0 {
+		config.Notifications.Telegram.MessageThreadID = tg.MessageThreadID
+	}
+
+	if normalizeTelegramChats(tg.Chats) == nil {
+		config.Notifications.Telegram.Chats = tg.Chats
+	}
+
+	config.Notifications.Telegram.AlertButtons = tg.AlertButtons
+
+	if notifications.ValidateHysteresis(tg.Hysteresis) == nil {
+		config.Notifications.Telegram.Hysteresis = tg.Hysteresis
+	}
+
+	if tg.SustainedChecks >= 0 && tg.SustainedChecks <= maxSustainedChecks {
+		config.Notifications.Telegram.SustainedChecks = tg.SustainedChecks
+	}
+
+	if notifications.ValidateProxyURL(tg.Proxy) == nil {
+		config.Notifications.Telegram.Proxy = tg.Proxy
+	}
+
+	if tg.TemperatureThreshold >= 0 && tg.TemperatureThreshold <= maxTemperatureThreshold {
+		config.Notifications.Telegram.TemperatureThreshold = tg.TemperatureThreshold
+	}
+
+	if tg.TemperatureCooldown >= 0 {
+		config.Notifications.Telegram.TemperatureCooldown = tg.TemperatureCooldown
+	}
+
+	if normalizeDiskPaths(tg.DiskPaths) == nil {
+		config.Notifications.Telegram.DiskPaths = tg.DiskPaths
+	}
+
+	if tg.SwapThreshold >= 0 && tg.SwapThreshold <= 100 {
+		config.Notifications.Telegram.SwapThreshold = tg.SwapThreshold
+	}
 }
 
 // applyACMEImport applies the imported ACME ("SSL/TLS issue") settings.
@@ -14,6 +14,7 @@ import (
 	"github.com/AdguardTeam/AdGuardHome/internal/aghalg"
 	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
 	"github.com/AdguardTeam/AdGuardHome/internal/aghnet"
+	"github.com/AdguardTeam/AdGuardHome/internal/aghos"
 	"github.com/AdguardTeam/AdGuardHome/internal/updater"
 	"github.com/AdguardTeam/golibs/errors"
 	"github.com/AdguardTeam/golibs/logutil/slogutil"
@@ -65,6 +66,8 @@ func (web *webAPI) handleVersionJSON(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	resp.Verification = web.conf.updater.Verification()
+
 	err = resp.setAllowedToAutoUpdate(ctx, l, web.tlsManager)
 	if err != nil {
 		// Don't wrap the error, because it's informative enough as is.
@@ -177,6 +180,11 @@ func (web *webAPI) handleUpdate(w http.ResponseWriter, r *http.Request) {
 // versionResponse is the response for /control/version.json endpoint.
 type versionResponse struct {
 	updater.VersionInfo
+
+	// Verification is the result of the verification of the last downloaded
+	// update, if any.
+	Verification *updater.Verification `json:"verification,omitempty"`
+
 	Disabled bool `json:"disabled"`
 }
 
@@ -235,12 +243,47 @@ func finishUpdate(
 	cleanup(ctx)
 	cleanupAlways()
 
+	restart(ctx, l, cmdCons, execPath, runningAsService)
+}
+
+// newRestartFunc returns a function that restarts AdGuard Home in the same way
+// as [finishUpdate] does.  l and cmdCons must not be nil.
+func newRestartFunc(
+	l *slog.Logger,
+	cmdCons executil.CommandConstructor,
+	runningAsService bool,
+) (f func(ctx context.Context)) {
+	return func(ctx context.Context) {
+		execPath, err := os.Executable()
+		if err != nil {
+			l.ErrorContext(ctx, "getting executable path", slogutil.KeyError, err)
+
+			return
+		}
+
+		finishUpdate(ctx, l, cmdCons, execPath, runningAsService)
+	}
+}
+
+// restart replaces the current process with the executable at execPath.  l and
+// cmdCons must not be nil.
+func restart(
+	ctx context.Context,
+	l *slog.Logger,
+	cmdCons executil.CommandConstructor,
+	execPath string,
+	runningAsService bool,
+) {
 	if runtime.GOOS == "windows" {
 		finalizeWindowsUpdate(ctx, l, cmdCons, execPath, runningAsService)
 
 		os.Exit(osutil.ExitCodeSuccess)
 	}
 
+	// The process ID stays the same, so tell the service manager to wait for
+	// the new readiness notification instead of considering the service ready.
+	notifyServiceManager(ctx, l, aghos.SDReloadingState())
+
 	var err error
 	l.InfoContext(ctx, "restarting", "exec_path", execPath, "args", os.Args[1:])
 	err = syscall.Exec(execPath, os.Args, os.Environ())
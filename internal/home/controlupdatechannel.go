@@ -0,0 +1,246 @@
+package home
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghalg"
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/updater"
+	"github.com/AdguardTeam/AdGuardHome/internal/version"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+)
+
+// updateVersionsResp is the response to the GET /control/update/versions HTTP
+// API.
+type updateVersionsResp struct {
+	// Backup is the version, to which the update can be rolled back, if any.
+	Backup *updater.BackupInfo `json:"backup"`
+
+	// Version is the current version.
+	Version string `json:"version"`
+
+	// Channel is the current update channel.
+	Channel string `json:"channel"`
+
+	// Channels are the latest versions available in the update channels.
+	Channels []*updater.ChannelVersion `json:"channels"`
+}
+
+// updateChannelReq is the request to the PUT /control/update/channel HTTP API.
+type updateChannelReq struct {
+	Channel string `json:"channel"`
+}
+
+// registerUpdateChannelHandlers registers the HTTP handlers of the update
+// channels and the rollback of updates.
+func (web *webAPI) registerUpdateChannelHandlers() {
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodGet,
+		"/control/update/versions",
+		web.handleUpdateVersions,
+		&aghhttp.RouteInfo{
+			Summary: "Get the versions available in the update channels",
+			Description: "Returns the latest versions in all update channels and " +
+				"the previously installed version, to which the update can be " +
+				"rolled back.",
+			Response: updateVersionsResp{},
+		},
+	)
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodPut,
+		"/control/update/channel",
+		web.handleUpdateChannel,
+		&aghhttp.RouteInfo{
+			Summary: "Set the update channel",
+			Request: updateChannelReq{},
+		},
+	)
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodPost,
+		"/control/update/install",
+		web.handleUpdateInstall,
+		&aghhttp.RouteInfo{
+			Summary: "Install the latest version from the update channel",
+			Description: "Checks the update channel and installs the latest " +
+				"version, after which AdGuard Home restarts.",
+		},
+	)
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodPost,
+		"/control/update/rollback",
+		web.handleUpdateRollback,
+		&aghhttp.RouteInfo{
+			Summary: "Roll back to the previously installed version",
+			Description: "Restores the executable, the configuration, and the " +
+				"data files backed up by the last update, after which AdGuard " +
+				"Home restarts.",
+		},
+	)
+}
+
+// handleUpdateVersions is the handler for the GET /control/update/versions
+// HTTP API.
+func (web *webAPI) handleUpdateVersions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	l := web.logger
+
+	if web.conf.disableUpdate {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusConflict, "updates are disabled")
+
+		return
+	}
+
+	upd := web.conf.updater
+	bi, err := upd.Backup()
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusInternalServerError, "%s", err)
+
+		return
+	}
+
+	aghhttp.WriteJSONResponseOK(ctx, l, w, r, &updateVersionsResp{
+		Backup:   bi,
+		Version:  version.Version(),
+		Channel:  upd.Channel(),
+		Channels: upd.ChannelVersions(ctx),
+	})
+}
+
+// handleUpdateChannel is the handler for the PUT /control/update/channel HTTP
+// API.
+func (web *webAPI) handleUpdateChannel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	l := web.logger
+
+	if web.conf.disableUpdate {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusConflict, "updates are disabled")
+
+		return
+	}
+
+	req := &updateChannelReq{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusBadRequest, "parsing request: %s", err)
+
+		return
+	}
+
+	err = updater.ValidateChannel(req.Channel)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusBadRequest, "%s", err)
+
+		return
+	}
+
+	func() {
+		config.Lock()
+		defer config.Unlock()
+
+		config.AutoUpdate.Channel = req.Channel
+	}()
+
+	web.conf.updater.SetChannel(req.Channel)
+	web.confModifier.Apply(ctx)
+
+	l.InfoContext(ctx, "update channel changed", "channel", req.Channel)
+
+	aghhttp.OK(ctx, l, w)
+}
+
+// handleUpdateInstall is the handler for the POST /control/update/install HTTP
+// API.
+func (web *webAPI) handleUpdateInstall(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	l := web.logger
+
+	if web.conf.disableUpdate {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusConflict, "updates are disabled")
+
+		return
+	}
+
+	resp := &versionResponse{}
+	err := web.requestVersionInfo(ctx, resp, true)
+	if err != nil {
+		// Don't wrap the error, because it's informative enough as is.
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusBadGateway, "%s", err)
+
+		return
+	}
+
+	err = resp.setAllowedToAutoUpdate(ctx, l, web.tlsManager)
+	if err != nil {
+		// Don't wrap the error, because it's informative enough as is.
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusInternalServerError, "%s", err)
+
+		return
+	}
+
+	if resp.CanAutoUpdate != aghalg.NBTrue {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusConflict, "no update can be installed")
+
+		return
+	}
+
+	web.handleUpdate(w, r)
+}
+
+// handleUpdateRollback is the handler for the POST /control/update/rollback
+// HTTP API.
+func (web *webAPI) handleUpdateRollback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	l := web.logger
+
+	if web.conf.disableUpdate {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusConflict, "updates are disabled")
+
+		return
+	}
+
+	upd := web.conf.updater
+	bi, err := upd.Backup()
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusInternalServerError, "%s", err)
+
+		return
+	} else if bi == nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusConflict, "no version to roll back to")
+
+		return
+	}
+
+	// Retain the current absolute path of the executable, since the rollback
+	// moves the backup in its place.
+	execPath, err := os.Executable()
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusInternalServerError, "getting path: %s", err)
+
+		return
+	}
+
+	err = upd.Rollback(ctx)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusInternalServerError, "%s", err)
+
+		return
+	}
+
+	aghhttp.OK(ctx, l, w)
+
+	err = http.NewResponseController(w).Flush()
+	if err != nil {
+		l.WarnContext(ctx, "flushing response", slogutil.KeyError, err)
+	}
+
+	// The background context is used for the same reasons as in
+	// [webAPI.handleUpdate].
+	go finishUpdate(context.Background(), l, web.cmdCons, execPath, web.conf.runningAsService)
+}
@@ -0,0 +1,228 @@
+package home
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AdguardTeam/golibs/container"
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/httphdr"
+	"github.com/AdguardTeam/golibs/netutil/httputil"
+	"github.com/AdguardTeam/golibs/netutil/urlutil"
+	"github.com/AdguardTeam/golibs/timeutil"
+)
+
+// The CORS headers missing from package httphdr.
+//
+// See https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers#cors.
+const (
+	hdrAccessControlAllowCredentials = "Access-Control-Allow-Credentials"
+	hdrAccessControlAllowHeaders     = "Access-Control-Allow-Headers"
+	hdrAccessControlAllowMethods     = "Access-Control-Allow-Methods"
+	hdrAccessControlMaxAge           = "Access-Control-Max-Age"
+	hdrAccessControlRequestMethod    = "Access-Control-Request-Method"
+)
+
+// corsAnyOrigin is the value of [corsConfig.AllowedOrigins], which allows any
+// origin.
+const corsAnyOrigin = "*"
+
+// corsConfig is the CORS policy of the control API.
+type corsConfig struct {
+	// AllowedOrigins are the origins allowed to call the control API, like
+	// "https://dashboard.example.org", or [corsAnyOrigin].  If it's empty, the
+	// cross-origin requests aren't allowed.
+	AllowedOrigins []string `yaml:"allowed_origins"`
+
+	// AllowedMethods are the HTTP methods allowed in the cross-origin
+	// requests.
+	AllowedMethods []string `yaml:"allowed_methods"`
+
+	// AllowedHeaders are the request headers allowed in the cross-origin
+	// requests.
+	AllowedHeaders []string `yaml:"allowed_headers"`
+
+	// MaxAge is the duration, during which the browsers may cache the results
+	// of the preflight requests.  Zero means the default of the browser.
+	MaxAge timeutil.Duration `yaml:"max_age"`
+
+	// AllowCredentials allows the cross-origin requests to include the
+	// cookies and the HTTP authentication.
+	AllowCredentials bool `yaml:"allow_credentials"`
+}
+
+// validate returns an error if c is invalid.  c may be nil.
+func (c *corsConfig) validate() (err error) {
+	if c == nil {
+		return nil
+	}
+
+	var errs []error
+	for i, o := range c.AllowedOrigins {
+		if o == corsAnyOrigin {
+			if c.AllowCredentials {
+				errs = append(errs, fmt.Errorf(
+					"allowed_origins: at index %d: %q is not allowed with allow_credentials",
+					i,
+					o,
+				))
+			}
+
+			continue
+		}
+
+		if err = validateCORSOrigin(o); err != nil {
+			errs = append(errs, fmt.Errorf("allowed_origins: at index %d: %w", i, err))
+		}
+	}
+
+	if t := time.Duration(c.MaxAge); t < 0 {
+		errs = append(errs, fmt.Errorf("max_age: %w: %s", errors.ErrNegative, t))
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateCORSOrigin returns an error if o isn't a valid origin, which is a
+// URL with the HTTP or HTTPS scheme, the host, and nothing else.
+func validateCORSOrigin(o string) (err error) {
+	u, err := url.Parse(o)
+	if err != nil {
+		// Don't wrap the error, since it's informative enough as is.
+		return err
+	}
+
+	if u.Scheme != urlutil.SchemeHTTP && u.Scheme != urlutil.SchemeHTTPS {
+		return fmt.Errorf("origin %q: bad scheme %q", o, u.Scheme)
+	} else if u.Host == "" || u.User != nil || u.RawQuery != "" || u.Fragment != "" ||
+		(u.Path != "" && u.Path != "/") {
+		return fmt.Errorf("origin %q: must only contain the scheme, the host, and the port", o)
+	}
+
+	return nil
+}
+
+// corsMiddleware applies the CORS policy to the requests to the control API.
+// It answers the preflight requests itself, so it must wrap the
+// authentication middleware.
+type corsMiddleware struct {
+	// origins are the allowed origins.  If it's empty, the middleware does
+	// nothing.
+	origins *container.MapSet[string]
+
+	// methods is the value of the Access-Control-Allow-Methods header.
+	methods string
+
+	// headers is the value of the Access-Control-Allow-Headers header.
+	headers string
+
+	// maxAge is the value of the Access-Control-Max-Age header, if any.
+	maxAge string
+
+	// anyOrigin is true if any origin is allowed.
+	anyOrigin bool
+
+	// credentials is true if the credentials are allowed.
+	credentials bool
+}
+
+// newCORSMiddleware returns a new properly initialized *corsMiddleware.  c
+// must be valid, it may be nil.
+func newCORSMiddleware(c *corsConfig) (mw *corsMiddleware) {
+	mw = &corsMiddleware{
+		origins: container.NewMapSet[string](),
+	}
+
+	if c == nil {
+		return mw
+	}
+
+	for _, o := range c.AllowedOrigins {
+		if o == corsAnyOrigin {
+			mw.anyOrigin = true
+
+			continue
+		}
+
+		// The browsers send the origins without the trailing slashes.
+		mw.origins.Add(strings.TrimSuffix(o, "/"))
+	}
+
+	mw.methods = strings.Join(c.AllowedMethods, ", ")
+	mw.headers = strings.Join(c.AllowedHeaders, ", ")
+	if c.MaxAge > 0 {
+		mw.maxAge = strconv.Itoa(int(time.Duration(c.MaxAge).Seconds()))
+	}
+
+	mw.credentials = c.AllowCredentials
+
+	return mw
+}
+
+// type check
+var _ httputil.Middleware = (*corsMiddleware)(nil)
+
+// Wrap implements the [httputil.Middleware] interface for *corsMiddleware.
+func (mw *corsMiddleware) Wrap(h http.Handler) (wrapped http.Handler) {
+	if !mw.anyOrigin && mw.origins.Len() == 0 {
+		return h
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/control/") {
+			h.ServeHTTP(w, r)
+
+			return
+		}
+
+		respHdr := w.Header()
+		respHdr.Add(httphdr.Vary, httphdr.Origin)
+
+		origin := r.Header.Get(httphdr.Origin)
+		if origin == "" || !mw.allowed(origin) {
+			h.ServeHTTP(w, r)
+
+			return
+		}
+
+		respHdr.Set(httphdr.AccessControlAllowOrigin, origin)
+		if mw.credentials {
+			respHdr.Set(hdrAccessControlAllowCredentials, "true")
+		}
+
+		if r.Method != http.MethodOptions || r.Header.Get(hdrAccessControlRequestMethod) == "" {
+			h.ServeHTTP(w, r)
+
+			return
+		}
+
+		mw.writePreflight(w)
+	})
+}
+
+// allowed returns true if the requests from origin are allowed.
+func (mw *corsMiddleware) allowed(origin string) (ok bool) {
+	return mw.anyOrigin || mw.origins.Has(origin)
+}
+
+// writePreflight writes the response to the preflight request.
+func (mw *corsMiddleware) writePreflight(w http.ResponseWriter) {
+	respHdr := w.Header()
+	if mw.methods != "" {
+		respHdr.Set(hdrAccessControlAllowMethods, mw.methods)
+	}
+
+	if mw.headers != "" {
+		respHdr.Set(hdrAccessControlAllowHeaders, mw.headers)
+	}
+
+	if mw.maxAge != "" {
+		respHdr.Set(hdrAccessControlMaxAge, mw.maxAge)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,140 @@
+package home
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/httphdr"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/AdguardTeam/golibs/timeutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	mw := newCORSMiddleware(&corsConfig{
+		AllowedOrigins:   []string{"https://dashboard.example.org/"},
+		AllowedMethods:   []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders:   []string{httphdr.Authorization, httphdr.ContentType},
+		MaxAge:           timeutil.Duration(10 * time.Minute),
+		AllowCredentials: true,
+	})
+
+	h := mw.Wrap(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	const (
+		allowed = "https://dashboard.example.org"
+		other   = "https://other.example.org"
+	)
+
+	testCases := []struct {
+		name        string
+		method      string
+		path        string
+		origin      string
+		reqMethod   string
+		wantOrigin  string
+		wantMethods string
+		wantCode    int
+	}{{
+		name:        "allowed",
+		method:      http.MethodGet,
+		path:        "/control/status",
+		origin:      allowed,
+		reqMethod:   "",
+		wantOrigin:  allowed,
+		wantMethods: "",
+		wantCode:    http.StatusTeapot,
+	}, {
+		name:        "preflight",
+		method:      http.MethodOptions,
+		path:        "/control/status",
+		origin:      allowed,
+		reqMethod:   http.MethodPost,
+		wantOrigin:  allowed,
+		wantMethods: "GET, POST",
+		wantCode:    http.StatusNoContent,
+	}, {
+		name:        "not_allowed",
+		method:      http.MethodOptions,
+		path:        "/control/status",
+		origin:      other,
+		reqMethod:   http.MethodPost,
+		wantOrigin:  "",
+		wantMethods: "",
+		wantCode:    http.StatusTeapot,
+	}, {
+		name:        "not_control",
+		method:      http.MethodGet,
+		path:        "/index.html",
+		origin:      allowed,
+		reqMethod:   "",
+		wantOrigin:  "",
+		wantMethods: "",
+		wantCode:    http.StatusTeapot,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(tc.method, "http://127.0.0.1"+tc.path, nil)
+			r.Header.Set(httphdr.Origin, tc.origin)
+			if tc.reqMethod != "" {
+				r.Header.Set(hdrAccessControlRequestMethod, tc.reqMethod)
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			hdr := w.Header()
+			assert.Equal(t, tc.wantCode, w.Code)
+			assert.Equal(t, tc.wantOrigin, hdr.Get(httphdr.AccessControlAllowOrigin))
+			assert.Equal(t, tc.wantMethods, hdr.Get(hdrAccessControlAllowMethods))
+
+			if tc.wantMethods != "" {
+				assert.Equal(t, "600", hdr.Get(hdrAccessControlMaxAge))
+				assert.Equal(t, "true", hdr.Get(hdrAccessControlAllowCredentials))
+			}
+		})
+	}
+}
+
+func TestCORSConfig_validate(t *testing.T) {
+	testCases := []struct {
+		conf       *corsConfig
+		name       string
+		wantErrMsg string
+	}{{
+		conf:       nil,
+		name:       "nil",
+		wantErrMsg: "",
+	}, {
+		conf:       &corsConfig{AllowedOrigins: []string{"https://example.org:8443", corsAnyOrigin}},
+		name:       "valid",
+		wantErrMsg: "",
+	}, {
+		conf: &corsConfig{
+			AllowedOrigins:   []string{corsAnyOrigin},
+			AllowCredentials: true,
+		},
+		name:       "any_with_credentials",
+		wantErrMsg: `allowed_origins: at index 0: "*" is not allowed with allow_credentials`,
+	}, {
+		conf: &corsConfig{AllowedOrigins: []string{"https://example.org/path"}},
+		name: "path",
+		wantErrMsg: `allowed_origins: at index 0: origin "https://example.org/path": ` +
+			`must only contain the scheme, the host, and the port`,
+	}, {
+		conf:       &corsConfig{AllowedOrigins: []string{"ftp://example.org"}},
+		name:       "bad_scheme",
+		wantErrMsg: `allowed_origins: at index 0: origin "ftp://example.org": bad scheme "ftp"`,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			testutil.AssertErrorMsg(t, tc.wantErrMsg, tc.conf.validate())
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package home
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghos"
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+)
+
+const (
+	// crashOutputName is the name of the file within the working directory,
+	// which the Go runtime writes the fatal errors and unrecovered panics of
+	// the current run into.
+	crashOutputName = "agh-crash.log"
+
+	// crashReportPrefix is the prefix of the names of the crash reports saved
+	// from the previous runs.
+	crashReportPrefix = "agh-crash-"
+
+	// crashReportExt is the extension of the crash reports.
+	crashReportExt = ".log"
+
+	// crashReportTimeFormat is the format of the crash time in the names of
+	// the crash reports.
+	crashReportTimeFormat = "20060102T150405Z"
+
+	// maxCrashReports is the maximum number of the crash reports to keep.
+	maxCrashReports = 5
+
+	// maxCrashEventSize is the maximum size of the crash report included into
+	// the system log entry.
+	maxCrashEventSize = 16 * 1024
+)
+
+// collectCrashReport saves the crash output of the previous run, if it has
+// crashed, as a separate report and removes the oldest reports.  If isService
+// is true, the crash is also reported to the system log.  lastCrash is the
+// time of the latest crash, if any.  l must not be nil.
+func collectCrashReport(
+	ctx context.Context,
+	l *slog.Logger,
+	workDir string,
+	isService bool,
+) (lastCrash time.Time) {
+	outPath := filepath.Join(workDir, crashOutputName)
+	fi, err := os.Stat(outPath)
+	if err == nil && fi.Size() > 0 {
+		crashTime := fi.ModTime().UTC()
+		reportPath := filepath.Join(
+			workDir,
+			crashReportPrefix+crashTime.Format(crashReportTimeFormat)+crashReportExt,
+		)
+
+		err = os.Rename(outPath, reportPath)
+		if err != nil {
+			l.ErrorContext(ctx, "saving crash report", slogutil.KeyError, err)
+		} else {
+			l.WarnContext(ctx, "previous run crashed", "time", crashTime, "report", reportPath)
+			reportCrash(ctx, l, reportPath, crashTime, isService)
+		}
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		l.WarnContext(ctx, "checking crash output", slogutil.KeyError, err)
+	}
+
+	return rotateCrashReports(ctx, l, workDir)
+}
+
+// reportCrash writes the beginning of the crash report to the system log, if
+// isService is true.  l must not be nil.
+func reportCrash(
+	ctx context.Context,
+	l *slog.Logger,
+	reportPath string,
+	crashTime time.Time,
+	isService bool,
+) {
+	if !isService {
+		return
+	}
+
+	var trace []byte
+	f, err := os.Open(reportPath)
+	if err == nil {
+		trace, err = io.ReadAll(io.LimitReader(f, maxCrashEventSize))
+		err = errors.WithDeferred(err, f.Close())
+	}
+	if err != nil {
+		l.WarnContext(ctx, "reading crash report", slogutil.KeyError, err)
+	}
+
+	msg := fmt.Sprintf(
+		"AdGuard Home crashed at %s, the stack trace is saved to %s\n\n%s",
+		crashTime.Format(time.RFC3339),
+		reportPath,
+		trace,
+	)
+
+	err = aghos.LogCrash(serviceName, msg)
+	if err != nil {
+		l.WarnContext(ctx, "writing crash to system log", slogutil.KeyError, err)
+	}
+}
+
+// rotateCrashReports removes all crash reports except for the most recent
+// [maxCrashReports] ones and returns the time of the latest one.  l must not
+// be nil.
+func rotateCrashReports(ctx context.Context, l *slog.Logger, workDir string) (lastCrash time.Time) {
+	reports, err := filepath.Glob(filepath.Join(workDir, crashReportPrefix+"*"+crashReportExt))
+	if err != nil {
+		l.WarnContext(ctx, "listing crash reports", slogutil.KeyError, err)
+
+		return time.Time{}
+	}
+
+	// The time format is sortable, so the latest reports are at the end.
+	slices.Sort(reports)
+	for len(reports) > maxCrashReports {
+		err = os.Remove(reports[0])
+		if err != nil {
+			l.WarnContext(ctx, "removing crash report", slogutil.KeyError, err)
+		}
+
+		reports = reports[1:]
+	}
+
+	if len(reports) == 0 {
+		return time.Time{}
+	}
+
+	name := filepath.Base(reports[len(reports)-1])
+	name = strings.TrimSuffix(strings.TrimPrefix(name, crashReportPrefix), crashReportExt)
+	lastCrash, err = time.Parse(crashReportTimeFormat, name)
+	if err != nil {
+		l.WarnContext(ctx, "parsing crash report name", slogutil.KeyError, err)
+
+		return time.Time{}
+	}
+
+	return lastCrash
+}
+
+// setCrashOutput makes the Go runtime write the fatal errors and unrecovered
+// panics into the crash output file in workDir in addition to stderr, so that
+// they are collected by [collectCrashReport] on the next start.  l must not
+// be nil.
+func setCrashOutput(ctx context.Context, l *slog.Logger, workDir string) {
+	outPath := filepath.Join(workDir, crashOutputName)
+	f, err := os.OpenFile(outPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, aghos.DefaultPermFile)
+	if err != nil {
+		l.WarnContext(ctx, "opening crash output", slogutil.KeyError, err)
+
+		return
+	}
+
+	// SetCrashOutput duplicates the file descriptor, so close the file.
+	defer func() {
+		err = f.Close()
+		if err != nil {
+			l.WarnContext(ctx, "closing crash output", slogutil.KeyError, err)
+		}
+	}()
+
+	err = debug.SetCrashOutput(f, debug.CrashOptions{})
+	if err != nil {
+		l.WarnContext(ctx, "setting crash output", slogutil.KeyError, err)
+	}
+}
@@ -0,0 +1,49 @@
+package home
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectCrashReport(t *testing.T) {
+	workDir := t.TempDir()
+	ctx := testutil.ContextWithTimeout(t, testTimeout)
+
+	lastCrash := collectCrashReport(ctx, testLogger, workDir, false)
+	assert.True(t, lastCrash.IsZero())
+
+	// Empty output means a clean shutdown.
+	outPath := filepath.Join(workDir, crashOutputName)
+	require.NoError(t, os.WriteFile(outPath, nil, 0o644))
+
+	lastCrash = collectCrashReport(ctx, testLogger, workDir, false)
+	assert.True(t, lastCrash.IsZero())
+
+	start := time.Date(2024, time.June, 2, 3, 0, 0, 0, time.UTC)
+	for i := range maxCrashReports + 2 {
+		crashTime := start.Add(time.Duration(i) * time.Hour)
+		require.NoError(t, os.WriteFile(outPath, fmt.Appendf(nil, "panic: %d", i), 0o644))
+		require.NoError(t, os.Chtimes(outPath, crashTime, crashTime))
+
+		lastCrash = collectCrashReport(ctx, testLogger, workDir, false)
+		assert.Equal(t, crashTime, lastCrash)
+	}
+
+	assert.NoFileExists(t, outPath)
+
+	reports, err := filepath.Glob(filepath.Join(workDir, crashReportPrefix+"*"))
+	require.NoError(t, err)
+	require.Len(t, reports, maxCrashReports)
+
+	data, err := os.ReadFile(reports[len(reports)-1])
+	require.NoError(t, err)
+
+	assert.Equal(t, fmt.Sprintf("panic: %d", maxCrashReports+1), string(data))
+}
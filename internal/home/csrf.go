@@ -0,0 +1,117 @@
+package home
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghuser"
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+const (
+	// csrfCookieName is the name of the cookie with the CSRF token.  Unlike
+	// the session cookie, it's readable by the web UI.
+	csrfCookieName = "agh_csrf"
+
+	// csrfHeaderName is the name of the request header, in which the web UI
+	// sends the CSRF token back.
+	csrfHeaderName = "X-CSRF-Token"
+)
+
+// errCSRFToken is returned when the CSRF token of a request is missing or
+// doesn't match the session.
+const errCSRFToken errors.Error = "csrf token missing or invalid"
+
+// csrfKeyLength is the length of the key of the CSRF tokens in bytes.
+const csrfKeyLength = 32
+
+// csrfProtector issues and verifies the CSRF tokens of the requests
+// authenticated with the session cookie.  The token is derived from the
+// session token, so it's only valid for that session, and is sent to the web
+// UI in a cookie, which the web UI returns in the [csrfHeaderName] header.  A
+// nil *csrfProtector allows all requests.
+//
+// The requests authenticated with the API tokens, the basic authentication,
+// and the client certificates without the session cookie aren't checked,
+// since the browsers don't attach those credentials to the cross-site requests
+// on their own.
+type csrfProtector struct {
+	// key is the key of the HMAC of the session tokens.  It's generated on
+	// start, so the tokens issued before a restart are reissued with the
+	// first response to the web UI.
+	key []byte
+}
+
+// newCSRFProtector returns a new properly initialized *csrfProtector.
+func newCSRFProtector() (p *csrfProtector) {
+	key := make([]byte, csrfKeyLength)
+	_, _ = rand.Read(key)
+
+	return &csrfProtector{
+		key: key,
+	}
+}
+
+// token returns the CSRF token of the session with sessToken.
+func (p *csrfProtector) token(sessToken aghuser.SessionToken) (token string) {
+	mac := hmac.New(sha256.New, p.key)
+	_, _ = mac.Write(sessToken[:])
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// isSafeMethod returns true if requests with method don't change the state and
+// thus don't require the CSRF token.
+func isSafeMethod(method string) (ok bool) {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
+
+// protect issues the CSRF token of the session with sessToken to w, if r
+// doesn't have the current one yet, and returns an error if r changes the state
+// and doesn't contain the token in the [csrfHeaderName] header.
+func (p *csrfProtector) protect(
+	w http.ResponseWriter,
+	r *http.Request,
+	sessToken aghuser.SessionToken,
+) (err error) {
+	if p == nil {
+		return nil
+	}
+
+	want := p.token(sessToken)
+	if c, cErr := r.Cookie(csrfCookieName); cErr != nil || c.Value != want {
+		http.SetCookie(w, p.cookie(r, sessToken))
+	}
+
+	if isSafeMethod(r.Method) {
+		return nil
+	}
+
+	got := r.Header.Get(csrfHeaderName)
+	if subtle.ConstantTimeCompare([]byte(got), []byte(want)) != 1 {
+		return errCSRFToken
+	}
+
+	return nil
+}
+
+// cookie returns the cookie with the CSRF token of the session with sessToken
+// for the response to r.
+func (p *csrfProtector) cookie(r *http.Request, sessToken aghuser.SessionToken) (c *http.Cookie) {
+	return &http.Cookie{
+		Name:     csrfCookieName,
+		Value:    p.token(sessToken),
+		Path:     "/",
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	}
+}
@@ -0,0 +1,140 @@
+package home
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghuser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthMiddlewareDefault_csrf(t *testing.T) {
+	t.Parallel()
+
+	const login = aghuser.Login(testUsername)
+
+	user := newTestUser(t, testPassword, login)
+
+	usersDB := newTestUsersDB()
+	usersDB.onAll = func(_ context.Context) (us []*aghuser.User, err error) {
+		return []*aghuser.User{user}, nil
+	}
+	usersDB.onByLogin = func(_ context.Context, l aghuser.Login) (u *aghuser.User, err error) {
+		if l == login {
+			return user, nil
+		}
+
+		return nil, nil
+	}
+
+	var token aghuser.SessionToken
+	_, _ = rand.Read(token[:])
+
+	ts := newTestSessionStorage()
+	ts.onFindByToken = func(
+		_ context.Context,
+		t aghuser.SessionToken,
+	) (s *aghuser.Session, err error) {
+		if t == token {
+			return &aghuser.Session{UserLogin: login}, nil
+		}
+
+		return nil, nil
+	}
+	ts.onTouch = func(
+		_ context.Context,
+		_ aghuser.SessionToken,
+		_ netip.Addr,
+		_ string,
+	) (err error) {
+		return nil
+	}
+
+	csrf := newCSRFProtector()
+	mw := newAuthMiddlewareDefault(&authMiddlewareDefaultConfig{
+		logger:      testLogger,
+		mux:         http.NewServeMux(),
+		rateLimiter: emptyRateLimiter{},
+		sessions:    ts,
+		users:       usersDB,
+		csrf:        csrf,
+	})
+
+	cookie := &http.Cookie{Name: sessionCookieName, Value: hex.EncodeToString(token[:])}
+	csrfToken := csrf.token(token)
+
+	t.Run("issue", func(t *testing.T) {
+		t.Parallel()
+
+		w := httptest.NewRecorder()
+		mw.Wrap(&testAuthHandler{}).ServeHTTP(w, authRequest("/control/status", cookie, "", ""))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var got *http.Cookie
+		for _, c := range w.Result().Cookies() {
+			if c.Name == csrfCookieName {
+				got = c
+			}
+		}
+
+		require.NotNil(t, got)
+
+		assert.Equal(t, csrfToken, got.Value)
+		assert.False(t, got.HttpOnly)
+	})
+
+	testCases := []struct {
+		cookie   *http.Cookie
+		name     string
+		header   string
+		user     string
+		wantCode int
+	}{{
+		cookie:   cookie,
+		name:     "cookie_no_token",
+		header:   "",
+		user:     "",
+		wantCode: http.StatusForbidden,
+	}, {
+		cookie:   cookie,
+		name:     "cookie_bad_token",
+		header:   "bad",
+		user:     "",
+		wantCode: http.StatusForbidden,
+	}, {
+		cookie:   cookie,
+		name:     "cookie_token",
+		header:   csrfToken,
+		user:     "",
+		wantCode: http.StatusOK,
+	}, {
+		cookie:   nil,
+		name:     "basic_auth",
+		header:   "",
+		user:     testUsername,
+		wantCode: http.StatusOK,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := authRequest("/control/protection", tc.cookie, tc.user, testPassword)
+			r.Method = http.MethodPost
+			if tc.header != "" {
+				r.Header.Set(csrfHeaderName, tc.header)
+			}
+
+			w := httptest.NewRecorder()
+			mw.Wrap(&testAuthHandler{}).ServeHTTP(w, r)
+
+			assert.Equal(t, tc.wantCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,675 @@
+package home
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/stats"
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/httphdr"
+	"github.com/AdguardTeam/golibs/netutil"
+	"github.com/AdguardTeam/golibs/osutil"
+	yaml "go.yaml.in/yaml/v4"
+)
+
+// ctlArg is the first command-line argument, which starts the local control
+// CLI instead of AdGuard Home.
+const ctlArg = "ctl"
+
+// ctlAPITokenEnv is the environment variable with the API token used by the
+// local control CLI, if the -token flag isn't set.
+const ctlAPITokenEnv = "ADGUARD_HOME_API_TOKEN"
+
+// ctlTimeout is the timeout of a command of the local control CLI.  It's long
+// enough for the filter lists to be downloaded.
+const ctlTimeout = 2 * time.Minute
+
+// maxCtlRespSize is the maximum size of a response to the local control CLI.
+const maxCtlRespSize = 16 << 20
+
+// ctlTopLen is the number of the top entries shown by the query-stats command.
+const ctlTopLen = 5
+
+// ctlCommand is a command of the local control CLI.
+type ctlCommand struct {
+	// run performs the command.  c must not be nil.
+	run func(ctx context.Context, c *ctlClient, args []string) (err error)
+
+	// args is the description of the arguments of the command, if any.
+	args string
+
+	// description is the human-readable description of the command.
+	description string
+}
+
+// ctlCommands are the commands of the local control CLI by their names.
+var ctlCommands = map[string]*ctlCommand{
+	"flush-cache": {
+		run:         ctlFlushCache,
+		description: "Clear the DNS cache.",
+	},
+	"import-dnsmasq": {
+		run:         ctlImportDnsmasq,
+		args:        "FILE",
+		description: "Import the configuration from a dnsmasq configuration FILE.",
+	},
+	"import-pihole": {
+		run:         ctlImportPihole,
+		args:        "ARCHIVE",
+		description: "Import the configuration from a Pi-hole Teleporter ARCHIVE.",
+	},
+	"pause": {
+		run:         ctlPause,
+		args:        "DURATION",
+		description: "Disable the protection for DURATION, like 30m, or until resumed.",
+	},
+	"query-stats": {
+		run:         ctlQueryStats,
+		description: "Show the query statistics.",
+	},
+	"reload": {
+		run:         ctlReload,
+		description: "Reload the configuration file.",
+	},
+	"reload-filters": {
+		run:         ctlReloadFilters,
+		description: "Update the blocklists and the allowlists.",
+	},
+	"resume": {
+		run:         ctlResume,
+		description: "Enable the protection.",
+	},
+	"status": {
+		run:         ctlStatus,
+		description: "Show the status of AdGuard Home.",
+	},
+}
+
+// runCtl runs the local control CLI with args following [ctlArg] and returns
+// the exit code.  stdout and stderr must not be nil.
+func runCtl(ctx context.Context, args []string, stdout, stderr io.Writer) (code osutil.ExitCode) {
+	flags := flag.NewFlagSet(ctlArg, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() { printCtlUsage(flags) }
+
+	confPath := flags.String("config", "", "Path to the config file, used to find the API.")
+	workDir := flags.String("work-dir", "", "Path to the working directory.")
+	sockPath := flags.String("socket", "", "Path to the Unix socket of the API.")
+	apiURL := flags.String("url", "", "URL of the API, like http://127.0.0.1:3000.")
+	token := flags.String("token", "", "API token, $"+ctlAPITokenEnv+" by default.")
+	rawJSON := flags.Bool("json", false, "Print the responses as JSON.")
+
+	err := flags.Parse(args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return osutil.ExitCodeSuccess
+		}
+
+		return osutil.ExitCodeArgumentError
+	}
+
+	args = flags.Args()
+	if len(args) == 0 {
+		printCtlUsage(flags)
+
+		return osutil.ExitCodeArgumentError
+	}
+
+	name, args := args[0], args[1:]
+	cmd, ok := ctlCommands[name]
+	if !ok {
+		_, _ = fmt.Fprintf(stderr, "unknown command %q\n", name)
+		printCtlUsage(flags)
+
+		return osutil.ExitCodeArgumentError
+	}
+
+	c, err := newCtlClient(&ctlClientConfig{
+		Output:   stdout,
+		ConfPath: *confPath,
+		WorkDir:  *workDir,
+		SockPath: *sockPath,
+		URL:      *apiURL,
+		Token:    cmp.Or(*token, os.Getenv(ctlAPITokenEnv)),
+		JSON:     *rawJSON,
+	})
+	if err == nil {
+		ctx, cancel := context.WithTimeout(ctx, ctlTimeout)
+		defer cancel()
+
+		err = cmd.run(ctx, c, args)
+	}
+
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "%s: %s\n", name, err)
+
+		return osutil.ExitCodeFailure
+	}
+
+	return osutil.ExitCodeSuccess
+}
+
+// printCtlUsage prints the help message of the local control CLI to the output
+// of flags.  flags must not be nil.
+func printCtlUsage(flags *flag.FlagSet) {
+	out := flags.Output()
+
+	_, _ = fmt.Fprintf(out, "Usage:\n\n%s %s [flags] COMMAND [ARGS]\n\n", os.Args[0], ctlArg)
+	_, _ = fmt.Fprintln(out, "Commands:")
+	for _, name := range slices.Sorted(maps.Keys(ctlCommands)) {
+		cmd := ctlCommands[name]
+		usage := strings.TrimSpace(name + " " + cmd.args)
+		_, _ = fmt.Fprintf(out, "  %-32s %s\n", usage, cmd.description)
+	}
+
+	_, _ = fmt.Fprintln(out, "\nFlags:")
+	flags.PrintDefaults()
+}
+
+// ctlClientConfig is the configuration structure for [newCtlClient].
+type ctlClientConfig struct {
+	// Output is used to print the results.  It must not be nil.
+	Output io.Writer
+
+	// ConfPath is the path to the configuration file, which is used to find
+	// the API, unless SockPath or URL is set.  If it's empty, the default one
+	// in WorkDir is used.
+	ConfPath string
+
+	// WorkDir is the working directory.  If it's empty, the directory of the
+	// executable is used.
+	WorkDir string
+
+	// SockPath is the path to the Unix socket of the API.
+	SockPath string
+
+	// URL is the base URL of the API.
+	URL string
+
+	// Token is the API token.  It's required unless the authentication is
+	// disabled.
+	Token string
+
+	// JSON, if true, makes the client print the responses as is.
+	JSON bool
+}
+
+// ctlClient sends the requests of the local control CLI to the API.
+type ctlClient struct {
+	// client is used to send the requests.
+	client *http.Client
+
+	// out is used to print the results.
+	out io.Writer
+
+	// baseURL is the base URL of the API.
+	baseURL string
+
+	// token is the API token, if any.
+	token string
+
+	// json, if true, makes the client print the responses as is.
+	json bool
+}
+
+// newCtlClient returns a new *ctlClient for the API found using c.  c must not
+// be nil.
+func newCtlClient(c *ctlClientConfig) (cli *ctlClient, err error) {
+	sockPath, baseURL := c.SockPath, strings.TrimSuffix(c.URL, "/")
+	if sockPath == "" && baseURL == "" {
+		sockPath, baseURL, err = ctlEndpoint(c.WorkDir, c.ConfPath)
+		if err != nil {
+			return nil, fmt.Errorf("finding api: %w", err)
+		}
+	}
+
+	tr := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+	}
+
+	if sockPath != "" {
+		// The host is ignored, since the requests are sent to the socket.
+		baseURL = "http://unix"
+		tr.Proxy = nil
+		tr.DialContext = func(ctx context.Context, _, _ string) (conn net.Conn, err error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", sockPath)
+		}
+	}
+
+	return &ctlClient{
+		client: &http.Client{
+			Transport: tr,
+		},
+		out:     c.Output,
+		baseURL: baseURL,
+		token:   c.Token,
+		json:    c.JSON,
+	}, nil
+}
+
+// ctlEndpoint returns the path to the Unix socket or the base URL of the API
+// from the configuration file at confPath, the socket taking precedence.  The
+// relative confPath is relative to workDir.
+func ctlEndpoint(workDir, confPath string) (sockPath, baseURL string, err error) {
+	workDir, err = initWorkingDir(options{workDir: workDir})
+	if err != nil {
+		return "", "", fmt.Errorf("working dir: %w", err)
+	}
+
+	confPath = cmp.Or(confPath, "AdGuardHome.yaml")
+	if !filepath.IsAbs(confPath) {
+		confPath = filepath.Join(workDir, confPath)
+	}
+
+	data, err := os.ReadFile(confPath)
+	if err != nil {
+		return "", "", fmt.Errorf("reading config: %w", err)
+	}
+
+	conf := &struct {
+		HTTP struct {
+			UnixSocket *unixSocketConfig `yaml:"unix_socket"`
+			Address    netip.AddrPort    `yaml:"address"`
+		} `yaml:"http"`
+	}{}
+
+	err = yaml.Unmarshal(data, conf)
+	if err != nil {
+		return "", "", fmt.Errorf("decoding config: %w", err)
+	}
+
+	if s := conf.HTTP.UnixSocket; s != nil && s.Path != "" {
+		return s.socketPath(workDir), "", nil
+	}
+
+	addr := conf.HTTP.Address
+	if !addr.IsValid() {
+		return "", "", fmt.Errorf("http.address: %w", errors.ErrNoValue)
+	}
+
+	ip := addr.Addr()
+	if ip.Is4() && ip.IsUnspecified() {
+		ip = netutil.IPv4Localhost()
+	} else if ip.IsUnspecified() {
+		ip = netutil.IPv6Localhost()
+	}
+
+	return "", "http://" + netip.AddrPortFrom(ip, addr.Port()).String(), nil
+}
+
+// do sends a request with the JSON-encoded reqBody, if any, to the API at path
+// and decodes the response into respBody, if any.  If c.json is true, it
+// prints the response instead of decoding it.
+func (c *ctlClient) do(
+	ctx context.Context,
+	method string,
+	path string,
+	reqBody any,
+	respBody any,
+) (err error) {
+	var body io.Reader = http.NoBody
+	if reqBody != nil {
+		var data []byte
+		data, err = json.Marshal(reqBody)
+		if err != nil {
+			return fmt.Errorf("encoding request: %w", err)
+		}
+
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	if reqBody != nil {
+		req.Header.Set(httphdr.ContentType, aghhttp.HdrValApplicationJSON)
+	}
+
+	if c.token != "" {
+		req.Header.Set(httphdr.Authorization, bearerTokenPrefix+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return err
+	}
+	defer func() { err = errors.WithDeferred(err, resp.Body.Close()) }()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxCtlRespSize))
+	if err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d: %s", resp.StatusCode, bytes.TrimSpace(data))
+	}
+
+	if c.json {
+		_, err = c.out.Write(data)
+
+		// Don't wrap the error since it's informative enough as is.
+		return err
+	} else if respBody == nil {
+		return nil
+	}
+
+	err = json.Unmarshal(data, respBody)
+	if err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+
+	return nil
+}
+
+// printf prints the formatted message, unless c.json is true.
+func (c *ctlClient) printf(format string, args ...any) {
+	if !c.json {
+		_, _ = fmt.Fprintf(c.out, format, args...)
+	}
+}
+
+// errCtlArgs is returned when a command of the local control CLI has the
+// wrong arguments.
+const errCtlArgs errors.Error = "wrong number of arguments"
+
+// ctlStatus performs the status command.
+func ctlStatus(ctx context.Context, c *ctlClient, args []string) (err error) {
+	if len(args) != 0 {
+		return errCtlArgs
+	}
+
+	resp := &statusResponse{}
+	err = c.do(ctx, http.MethodGet, "/control/status", nil, resp)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return err
+	}
+
+	protection := "enabled"
+	if !resp.ProtectionEnabled {
+		protection = "disabled"
+		if d := resp.ProtectionDisabledDuration; d > 0 {
+			left := time.Duration(d) * time.Millisecond
+			protection += " for " + left.Round(time.Second).String()
+		}
+	}
+
+	c.printf("version:       %s\n", resp.Version)
+	c.printf("running:       %t\n", resp.IsRunning)
+	c.printf("protection:    %s\n", protection)
+	c.printf("dns addresses: %s\n", strings.Join(resp.DNSAddrs, ", "))
+	c.printf("dns port:      %d\n", resp.DNSPort)
+	c.printf("http port:     %d\n", resp.HTTPPort)
+
+	return nil
+}
+
+// ctlFlushCache performs the flush-cache command.
+func ctlFlushCache(ctx context.Context, c *ctlClient, args []string) (err error) {
+	if len(args) != 0 {
+		return errCtlArgs
+	}
+
+	err = c.do(ctx, http.MethodPost, "/control/cache_clear", nil, nil)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return err
+	}
+
+	c.printf("dns cache cleared\n")
+
+	return nil
+}
+
+// ctlReloadFilters performs the reload-filters command.
+func ctlReloadFilters(ctx context.Context, c *ctlClient, args []string) (err error) {
+	if len(args) != 0 {
+		return errCtlArgs
+	}
+
+	for _, allowlist := range []bool{false, true} {
+		resp := &struct {
+			Updated int `json:"updated"`
+		}{}
+		err = c.do(ctx, http.MethodPost, "/control/filtering/refresh", &struct {
+			Whitelist bool `json:"whitelist"`
+		}{
+			Whitelist: allowlist,
+		}, resp)
+		if err != nil {
+			// Don't wrap the error since it's informative enough as is.
+			return err
+		}
+
+		kind := "blocklists"
+		if allowlist {
+			kind = "allowlists"
+		}
+
+		c.printf("%s updated: %d\n", kind, resp.Updated)
+	}
+
+	return nil
+}
+
+// ctlPause performs the pause command.
+func ctlPause(ctx context.Context, c *ctlClient, args []string) (err error) {
+	var dur time.Duration
+	switch len(args) {
+	case 0:
+		// Pause until resumed.
+	case 1:
+		dur, err = time.ParseDuration(args[0])
+		if err != nil {
+			return fmt.Errorf("duration: %w", err)
+		} else if dur <= 0 {
+			return fmt.Errorf("duration: %w: %s", errors.ErrNotPositive, args[0])
+		}
+	default:
+		return errCtlArgs
+	}
+
+	err = c.do(ctx, http.MethodPost, "/control/protection", &protectionReq{
+		Enabled:  false,
+		Duration: dur.Milliseconds(),
+	}, nil)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return err
+	}
+
+	if dur > 0 {
+		c.printf("protection disabled for %s\n", dur)
+	} else {
+		c.printf("protection disabled\n")
+	}
+
+	return nil
+}
+
+// ctlResume performs the resume command.
+func ctlResume(ctx context.Context, c *ctlClient, args []string) (err error) {
+	if len(args) != 0 {
+		return errCtlArgs
+	}
+
+	err = c.do(ctx, http.MethodPost, "/control/protection", &protectionReq{Enabled: true}, nil)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return err
+	}
+
+	c.printf("protection enabled\n")
+
+	return nil
+}
+
+// protectionReq is the request to the POST /control/protection HTTP API.
+type protectionReq struct {
+	// Enabled is the new state of the protection.
+	Enabled bool `json:"enabled"`
+
+	// Duration is the duration of the pause in milliseconds.  Zero means until
+	// enabled.
+	Duration int64 `json:"duration,omitempty"`
+}
+
+// ctlQueryStats performs the query-stats command.
+func ctlQueryStats(ctx context.Context, c *ctlClient, args []string) (err error) {
+	if len(args) != 0 {
+		return errCtlArgs
+	}
+
+	resp := &stats.StatsResp{}
+	err = c.do(ctx, http.MethodGet, "/control/stats", nil, resp)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return err
+	}
+
+	avgTime := time.Duration(resp.AvgProcessingTime * float64(time.Second))
+
+	c.printf("dns queries:              %d\n", resp.NumDNSQueries)
+	c.printf("blocked by filters:       %d\n", resp.NumBlockedFiltering)
+	c.printf("blocked by safe browsing: %d\n", resp.NumReplacedSafebrowsing)
+	c.printf("blocked by parental:      %d\n", resp.NumReplacedParental)
+	c.printf("safe search enforced:     %d\n", resp.NumReplacedSafesearch)
+	c.printf("avg processing time:      %s\n", avgTime.Round(time.Microsecond))
+
+	c.printTop("top queried domains", resp.TopQueried)
+	c.printTop("top blocked domains", resp.TopBlocked)
+	c.printTop("top clients", resp.TopClients)
+
+	return nil
+}
+
+// printTop prints the first [ctlTopLen] entries of top under title.
+func (c *ctlClient) printTop(title string, top []map[string]uint64) {
+	if len(top) == 0 {
+		return
+	}
+
+	c.printf("\n%s:\n", title)
+	for _, entry := range top[:min(len(top), ctlTopLen)] {
+		for name, n := range entry {
+			c.printf("  %-40s %d\n", name, n)
+		}
+	}
+}
+
+// ctlReload performs the reload command.
+func ctlReload(ctx context.Context, c *ctlClient, args []string) (err error) {
+	if len(args) != 0 {
+		return errCtlArgs
+	}
+
+	resp := &configReloadResult{}
+	err = c.do(ctx, http.MethodPost, "/control/reload", nil, resp)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return err
+	}
+
+	switch {
+	case resp.Restart:
+		c.printf("changed: %s; restarting\n", strings.Join(resp.Changed, ", "))
+	case len(resp.Changed) == 0:
+		c.printf("no changes\n")
+	default:
+		c.printf(
+			"changed: %s; reloaded: %s\n",
+			strings.Join(resp.Changed, ", "),
+			strings.Join(resp.Reloaded, ", "),
+		)
+	}
+
+	return nil
+}
+
+// ctlImportDnsmasq performs the import-dnsmasq command.
+func ctlImportDnsmasq(ctx context.Context, c *ctlClient, args []string) (err error) {
+	if len(args) != 1 {
+		return errCtlArgs
+	}
+
+	// #nosec G304 -- Trust the path explicitly given by the user.
+	conf, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+
+	resp := &importResp{}
+	err = c.do(ctx, http.MethodPost, "/control/import/dnsmasq", &importConfigReq{
+		Config: string(conf),
+	}, resp)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return err
+	}
+
+	c.printImport(resp)
+
+	return nil
+}
+
+// ctlImportPihole performs the import-pihole command.
+func ctlImportPihole(ctx context.Context, c *ctlClient, args []string) (err error) {
+	if len(args) != 1 {
+		return errCtlArgs
+	}
+
+	// #nosec G304 -- Trust the path explicitly given by the user.
+	archive, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("reading archive: %w", err)
+	}
+
+	resp := &importResp{}
+	err = c.do(ctx, http.MethodPost, "/control/import/pihole", &importArchiveReq{
+		Archive: archive,
+	}, resp)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return err
+	}
+
+	c.printImport(resp)
+
+	return nil
+}
+
+// printImport prints the result of an import command.
+func (c *ctlClient) printImport(resp *importResp) {
+	c.printf("blocklists added:     %d\n", resp.Filters)
+	c.printf("user rules added:     %d\n", resp.Rules)
+	c.printf("dns rewrites added:   %d\n", resp.Rewrites)
+	c.printf("upstreams added:      %d\n", resp.Upstreams)
+	c.printf("static leases added:  %d\n", resp.Leases)
+
+	if len(resp.Warnings) == 0 {
+		return
+	}
+
+	c.printf("\nnot imported:\n")
+	for _, w := range resp.Warnings {
+		c.printf("  %s\n", w)
+	}
+}
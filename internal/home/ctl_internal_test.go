@@ -0,0 +1,214 @@
+package home
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/osutil"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCtl(t *testing.T) {
+	t.Parallel()
+
+	const token = "agh_token"
+
+	type request struct {
+		body   map[string]any
+		method string
+		path   string
+	}
+
+	reqCh := make(chan *request, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != bearerTokenPrefix+token {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		req := &request{
+			method: r.Method,
+			path:   r.URL.Path,
+		}
+
+		data, _ := io.ReadAll(r.Body)
+		if len(data) > 0 {
+			_ = json.Unmarshal(data, &req.body)
+		}
+
+		reqCh <- req
+
+		switch r.URL.Path {
+		case "/control/status":
+			_, _ = io.WriteString(w, `{"version":"v0.107.71","protection_enabled":true}`)
+		case "/control/filtering/refresh":
+			_, _ = io.WriteString(w, `{"updated":1}`)
+		default:
+			_, _ = io.WriteString(w, "OK\n")
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	testCases := []struct {
+		name     string
+		wantReqs []*request
+		args     []string
+		wantOut  string
+		wantCode osutil.ExitCode
+	}{{
+		name: "pause",
+		wantReqs: []*request{{
+			body:   map[string]any{"enabled": false, "duration": float64(1_800_000)},
+			method: http.MethodPost,
+			path:   "/control/protection",
+		}},
+		args:     []string{"pause", "30m"},
+		wantOut:  "protection disabled for 30m0s\n",
+		wantCode: osutil.ExitCodeSuccess,
+	}, {
+		name: "flush_cache",
+		wantReqs: []*request{{
+			method: http.MethodPost,
+			path:   "/control/cache_clear",
+		}},
+		args:     []string{"flush-cache"},
+		wantOut:  "dns cache cleared\n",
+		wantCode: osutil.ExitCodeSuccess,
+	}, {
+		name: "reload_filters",
+		wantReqs: []*request{{
+			body:   map[string]any{"whitelist": false},
+			method: http.MethodPost,
+			path:   "/control/filtering/refresh",
+		}, {
+			body:   map[string]any{"whitelist": true},
+			method: http.MethodPost,
+			path:   "/control/filtering/refresh",
+		}},
+		args:     []string{"reload-filters"},
+		wantOut:  "blocklists updated: 1\nallowlists updated: 1\n",
+		wantCode: osutil.ExitCodeSuccess,
+	}, {
+		name: "status_json",
+		wantReqs: []*request{{
+			method: http.MethodGet,
+			path:   "/control/status",
+		}},
+		args:     []string{"-json", "status"},
+		wantOut:  `{"version":"v0.107.71","protection_enabled":true}`,
+		wantCode: osutil.ExitCodeSuccess,
+	}, {
+		name:     "bad_duration",
+		wantReqs: nil,
+		args:     []string{"pause", "-1m"},
+		wantOut:  "",
+		wantCode: osutil.ExitCodeFailure,
+	}, {
+		name:     "unknown_command",
+		wantReqs: nil,
+		args:     []string{"restart"},
+		wantOut:  "",
+		wantCode: osutil.ExitCodeArgumentError,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := testutil.ContextWithTimeout(t, testTimeout)
+			stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+			args := append([]string{"-url", srv.URL, "-token", token}, tc.args...)
+			code := runCtl(ctx, args, stdout, stderr)
+			require.Equal(t, tc.wantCode, code, stderr.String())
+
+			assert.Equal(t, tc.wantOut, stdout.String())
+			for _, want := range tc.wantReqs {
+				assert.Equal(t, want, <-reqCh)
+			}
+
+			assert.Empty(t, reqCh)
+		})
+	}
+}
+
+func TestRunCtl_socket(t *testing.T) {
+	t.Parallel()
+
+	sockPath := filepath.Join(t.TempDir(), "agh.sock")
+	l, err := net.Listen("unix", sockPath)
+	require.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "OK\n")
+	}))
+	srv.Listener = l
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	ctx := testutil.ContextWithTimeout(t, testTimeout)
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+	code := runCtl(ctx, []string{"-socket", sockPath, "resume"}, stdout, stderr)
+	require.Equal(t, osutil.ExitCodeSuccess, code, stderr.String())
+
+	assert.Equal(t, "protection enabled\n", stdout.String())
+}
+
+func TestCtlEndpoint(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		conf         string
+		wantSockPath string
+		wantURL      string
+	}{{
+		name:         "address",
+		conf:         "http:\n  address: 0.0.0.0:3000\n",
+		wantSockPath: "",
+		wantURL:      "http://127.0.0.1:3000",
+	}, {
+		name:         "address_ipv6",
+		conf:         "http:\n  address: '[::]:8080'\n",
+		wantSockPath: "",
+		wantURL:      "http://[::1]:8080",
+	}, {
+		name: "socket",
+		conf: "http:\n  address: 192.0.2.1:3000\n" +
+			"  unix_socket:\n    path: agh.sock\n",
+		wantSockPath: "agh.sock",
+		wantURL:      "",
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			workDir, err := filepath.EvalSymlinks(t.TempDir())
+			require.NoError(t, err)
+
+			confPath := filepath.Join(workDir, "AdGuardHome.yaml")
+			err = os.WriteFile(confPath, []byte(tc.conf), 0o600)
+			require.NoError(t, err)
+
+			sockPath, baseURL, err := ctlEndpoint(workDir, "")
+			require.NoError(t, err)
+
+			if tc.wantSockPath != "" {
+				tc.wantSockPath = filepath.Join(workDir, tc.wantSockPath)
+			}
+
+			assert.Equal(t, tc.wantSockPath, sockPath)
+			assert.Equal(t, tc.wantURL, baseURL)
+		})
+	}
+}
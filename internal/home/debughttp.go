@@ -0,0 +1,213 @@
+package home
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+	httppprof "net/http/pprof"
+	"runtime"
+	"runtime/pprof"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/golibs/httphdr"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+)
+
+// httpDebugConfig is the configuration of the debug HTTP API.
+type httpDebugConfig struct {
+	// Enabled defines if the profiling, the exported variables, and the dump
+	// of the goroutines are served within the control API.
+	Enabled bool `yaml:"enabled"`
+}
+
+// debugConfigJSON is the JSON structure of the configuration of the debug HTTP
+// API.
+type debugConfigJSON struct {
+	Enabled bool `json:"enabled"`
+}
+
+// debugPprofPathPrefix is the prefix of the paths of the pprof handlers within
+// the control API.
+const debugPprofPathPrefix = "/control/debug/pprof/"
+
+// debugPprofHandlers returns the pprof handlers by their paths relative to
+// [debugPprofPathPrefix].
+func debugPprofHandlers() (handlers map[string]http.Handler) {
+	handlers = map[string]http.Handler{
+		"cmdline": http.HandlerFunc(httppprof.Cmdline),
+		"profile": http.HandlerFunc(httppprof.Profile),
+		"symbol":  http.HandlerFunc(httppprof.Symbol),
+		"trace":   http.HandlerFunc(httppprof.Trace),
+	}
+
+	for _, name := range []string{
+		"allocs",
+		"block",
+		"goroutine",
+		"heap",
+		"mutex",
+		"threadcreate",
+	} {
+		handlers[name] = httppprof.Handler(name)
+	}
+
+	return handlers
+}
+
+// registerDebugHandlers registers the HTTP handlers of the debug HTTP API.
+func (web *webAPI) registerDebugHandlers() {
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodGet,
+		"/control/debug/config",
+		web.handleGetDebugConfig,
+		&aghhttp.RouteInfo{
+			Summary:  "Get the configuration of the debug HTTP API",
+			Response: debugConfigJSON{},
+		},
+	)
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodPut,
+		"/control/debug/config",
+		web.handlePutDebugConfig,
+		&aghhttp.RouteInfo{
+			Summary: "Set the configuration of the debug HTTP API",
+			Request: debugConfigJSON{},
+		},
+	)
+
+	// Serve the pprof handlers within the control API, so that they are
+	// protected by the same authentication.  The index isn't served, since the
+	// handler of the index only resolves the profiles under "/debug/pprof/".
+	for path, h := range debugPprofHandlers() {
+		web.httpReg.Register(http.MethodGet, debugPprofPathPrefix+path, web.debugHandler(h))
+	}
+
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodGet,
+		"/control/debug/vars",
+		web.debugHandler(expvar.Handler()),
+		&aghhttp.RouteInfo{
+			Summary: "Get the exported variables",
+			Description: "Returns the variables published with the expvar " +
+				"package.  Only available when the debug HTTP API is enabled.",
+		},
+	)
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodGet,
+		"/control/debug/goroutines",
+		web.debugHandler(http.HandlerFunc(web.handleGetGoroutines)),
+		&aghhttp.RouteInfo{
+			Summary: "Get the stack traces of all goroutines",
+			Description: "Returns the plain-text dump of the goroutines in the " +
+				"format of an unrecovered panic.  Only available when the debug " +
+				"HTTP API is enabled.",
+		},
+	)
+}
+
+// debugHandler returns a handler, which serves the requests with h only when
+// the debug HTTP API is enabled and responds with 404 Not Found otherwise.
+func (web *webAPI) debugHandler(h http.Handler) (wrapped http.HandlerFunc) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		config.RLock()
+		enabled := config.HTTPConfig.Debug.Enabled
+		config.RUnlock()
+
+		if !enabled {
+			aghhttp.ErrorAndLog(
+				r.Context(),
+				web.logger,
+				r,
+				w,
+				http.StatusNotFound,
+				"debug http api is disabled",
+			)
+
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	}
+}
+
+// handleGetDebugConfig is the handler for the GET /control/debug/config HTTP
+// API.
+func (web *webAPI) handleGetDebugConfig(w http.ResponseWriter, r *http.Request) {
+	config.RLock()
+	resp := &debugConfigJSON{
+		Enabled: config.HTTPConfig.Debug.Enabled,
+	}
+	config.RUnlock()
+
+	aghhttp.WriteJSONResponseOK(r.Context(), web.logger, w, r, resp)
+}
+
+// handlePutDebugConfig is the handler for the PUT /control/debug/config HTTP
+// API.
+func (web *webAPI) handlePutDebugConfig(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	l := web.logger
+
+	req := &debugConfigJSON{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusBadRequest, "parsing request: %s", err)
+
+		return
+	}
+
+	func() {
+		config.Lock()
+		defer config.Unlock()
+
+		config.HTTPConfig.Debug.Enabled = req.Enabled
+		setProfileRates(req.Enabled || config.HTTPConfig.Pprof.Enabled)
+	}()
+
+	web.confModifier.Apply(ctx)
+
+	l.InfoContext(ctx, "debug http api changed", "enabled", req.Enabled)
+
+	aghhttp.OK(ctx, l, w)
+}
+
+// handleGetGoroutines is the handler for the GET /control/debug/goroutines
+// HTTP API.
+func (web *webAPI) handleGetGoroutines(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(httphdr.ContentType, aghhttp.HdrValTextPlain)
+
+	// Use the debug level 2, which is the format of an unrecovered panic, since
+	// it contains the state and the waiting time of each goroutine.
+	err := pprof.Lookup("goroutine").WriteTo(w, 2)
+	if err != nil {
+		web.logger.DebugContext(r.Context(), "writing goroutines", slogutil.KeyError, err)
+	}
+}
+
+// Sampling rates of the block and the mutex profiles.  Recording every event
+// is too costly for a busy DNS server.
+const (
+	// blockProfileRate is the average time spent blocked, in nanoseconds, per
+	// a sampled blocking event.
+	blockProfileRate = 10_000
+
+	// mutexProfileFraction is the inverse of the fraction of the sampled
+	// mutex contention events.
+	mutexProfileFraction = 100
+)
+
+// setProfileRates enables or disables the block and the mutex profiles, which
+// are disabled by default, since they slow the program down.
+func setProfileRates(enabled bool) {
+	blockRate, mutexFraction := 0, 0
+	if enabled {
+		blockRate, mutexFraction = blockProfileRate, mutexProfileFraction
+	}
+
+	runtime.SetBlockProfileRate(blockRate)
+	runtime.SetMutexProfileFraction(mutexFraction)
+}
@@ -0,0 +1,83 @@
+package home
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebAPI_debugHandler(t *testing.T) {
+	storeGlobals(t)
+	t.Cleanup(func() { setProfileRates(false) })
+
+	config = newDefaultConfig()
+
+	isConfigChanged := false
+	web := newTestWeb(t, &webConfig{
+		configModifier: &aghtest.ConfigModifier{
+			OnApply: func(_ context.Context) { isConfigChanged = true },
+		},
+	})
+
+	h := web.debugHandler(http.HandlerFunc(web.handleGetGoroutines))
+
+	w := httptest.NewRecorder()
+	h(w, httptest.NewRequest(http.MethodGet, "/control/debug/goroutines", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest(
+		http.MethodPut,
+		"/control/debug/config",
+		strings.NewReader(`{"enabled":true}`),
+	)
+	web.handlePutDebugConfig(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	assert.True(t, isConfigChanged)
+	assert.True(t, config.HTTPConfig.Debug.Enabled)
+
+	w = httptest.NewRecorder()
+	h(w, httptest.NewRequest(http.MethodGet, "/control/debug/goroutines", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	assert.Contains(t, w.Body.String(), "goroutine ")
+}
+
+func TestDebugPprofHandlers(t *testing.T) {
+	handlers := debugPprofHandlers()
+
+	for _, name := range []string{"allocs", "goroutine", "heap", "threadcreate"} {
+		h := handlers[name]
+		require.NotNilf(t, h, "profile %q", name)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(
+			http.MethodGet,
+			debugPprofPathPrefix+name+"?debug=1",
+			nil,
+		))
+
+		assert.Equalf(t, http.StatusOK, w.Code, "profile %q", name)
+	}
+}
+
+func TestSetProfileRates(t *testing.T) {
+	t.Cleanup(func() { setProfileRates(false) })
+
+	setProfileRates(true)
+
+	// A negative fraction only returns the current one.
+	assert.Equal(t, mutexProfileFraction, runtime.SetMutexProfileFraction(-1))
+
+	setProfileRates(false)
+
+	assert.Zero(t, runtime.SetMutexProfileFraction(-1))
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/AdguardTeam/AdGuardHome/internal/client"
 	"github.com/AdguardTeam/AdGuardHome/internal/dnsforward"
 	"github.com/AdguardTeam/AdGuardHome/internal/filtering"
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
 	"github.com/AdguardTeam/AdGuardHome/internal/querylog"
 	"github.com/AdguardTeam/AdGuardHome/internal/stats"
 	"github.com/AdguardTeam/golibs/errors"
@@ -54,14 +55,16 @@ func initDNS(
 ) (err error) {
 	anonymizer := config.anonymizer()
 
+	statsFile := filepath.Join(statsDir, "stats.db")
 	statsConf := stats.Config{
 		Logger:            baseLogger.With(slogutil.KeyPrefix, "stats"),
-		Filename:          filepath.Join(statsDir, "stats.db"),
+		Filename:          statsFile,
 		Limit:             time.Duration(config.Stats.Interval),
 		ConfigModifier:    confModifier,
 		HTTPReg:           httpReg,
 		Enabled:           config.Stats.Enabled,
 		ShouldCountClient: globalContext.clients.shouldCountClient,
+		OnStorageError:    newStorageErrorHandler(notifications.StorageComponentStats, statsFile),
 	}
 
 	engine, err := aghnet.NewIgnoreEngine(config.Stats.Ignored, config.Stats.IgnoredEnabled)
@@ -81,6 +84,8 @@ func initDNS(
 		ConfigModifier:    confModifier,
 		HTTPReg:           httpReg,
 		FindClient:        globalContext.clients.findMultiple,
+		OnStorageError:    newStorageErrorHandler(notifications.StorageComponentQueryLog, querylogDir),
+		OnAdd:             globalContext.events.publishQueryLogEntry,
 		BaseDir:           querylogDir,
 		AnonymizeClientIP: config.DNS.AnonymizeClientIP,
 		RotationIvl:       time.Duration(config.QueryLog.Interval),
@@ -154,6 +159,7 @@ func initDNSServer(
 		Anonymizer:  anonymizer,
 		DHCPServer:  dhcpSrv,
 		EtcHosts:    globalContext.etcHosts,
+		Hook:        globalContext.plugins.Hook(),
 		LocalDomain: config.DHCP.LocalDomainName,
 	})
 	defer func() {
@@ -280,6 +286,8 @@ func newServerConfig(
 		return nil, fmt.Errorf("constructing tls config: %w", err)
 	}
 
+	intTLSConf.OCSPStapler = tlsMgr.ocsp
+
 	newConf = &dnsforward.ServerConfig{
 		UDPListenAddrs:         ipsToUDPAddrs(hosts, dnsConf.Port),
 		TCPListenAddrs:         ipsToTCPAddrs(hosts, dnsConf.Port),
@@ -596,9 +604,11 @@ func checkDir(path string) (err error) {
 	return nil
 }
 
-// registerDoHHandlers registers DoH handlers on the given routes.
+// registerDoHHandlers registers DoH handlers on the given routes.  The
+// requests are counted in the HTTP metrics under their routes.
 func registerDoHHandlers(routes []string) {
+	metrics := globalContext.web.routeMetrics()
 	for _, route := range routes {
-		globalContext.web.conf.mux.Handle(route, globalContext.dnsServer)
+		globalContext.web.conf.mux.Handle(route, metrics.Wrap(route, globalContext.dnsServer))
 	}
 }
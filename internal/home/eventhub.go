@@ -0,0 +1,463 @@
+package home
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/filtering"
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+)
+
+// liveEventType is the type of an event pushed to the subscribers of the
+// [eventHub].
+type liveEventType string
+
+// Valid live event types.
+const (
+	// liveEventQueryLog is a new entry of the query log.
+	liveEventQueryLog liveEventType = "querylog"
+
+	// liveEventStats is the change of the statistics counters.
+	liveEventStats liveEventType = "stats"
+
+	// liveEventFilterUpdate is the progress of a refresh of the filter lists.
+	liveEventFilterUpdate liveEventType = "filter_update"
+
+	// liveEventNotification is a notification, like an alert, reported
+	// before its delivery to the notification channels.
+	liveEventNotification liveEventType = "notification"
+
+	// liveEventConfigChanged is a change of the configuration file.
+	liveEventConfigChanged liveEventType = "config_changed"
+
+	// liveEventClientConnected is the first query of a client since the
+	// start.
+	liveEventClientConnected liveEventType = "client_connected"
+
+	// liveEventAlert is an alert, like the high CPU usage.  The alerts are
+	// also reported as [liveEventNotification].
+	liveEventAlert liveEventType = "alert"
+)
+
+// liveEventTypes are all valid live event types.
+var liveEventTypes = []liveEventType{
+	liveEventQueryLog,
+	liveEventStats,
+	liveEventFilterUpdate,
+	liveEventNotification,
+	liveEventConfigChanged,
+	liveEventClientConnected,
+	liveEventAlert,
+}
+
+// parseLiveEventTypes parses the comma-separated list of the live event types.
+// An empty list means all types.
+func parseLiveEventTypes(s string) (types []liveEventType, err error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	for _, v := range strings.Split(s, ",") {
+		typ := liveEventType(strings.TrimSpace(v))
+		if !slices.Contains(liveEventTypes, typ) {
+			return nil, fmt.Errorf("types: %w: %q", errors.ErrBadEnumValue, typ)
+		}
+
+		types = append(types, typ)
+	}
+
+	return types, nil
+}
+
+// liveEvent is an event pushed to the subscribers of the [eventHub].
+type liveEvent struct {
+	// Time is the time of the event.
+	Time time.Time `json:"time"`
+
+	// Data is the payload of the event, which depends on Type.
+	Data any `json:"data"`
+
+	// Type is the type of the event.
+	Type liveEventType `json:"type"`
+}
+
+// eventSubscriberBufSize is the number of the events buffered for each
+// subscriber.  The events are dropped for the subscribers, which don't keep
+// up.
+const eventSubscriberBufSize = 256
+
+// eventSubscriber is a subscriber of the [eventHub].
+type eventSubscriber struct {
+	// events receives the events.  It's never closed, since the hub may send
+	// to it concurrently with the unsubscription.
+	events chan *liveEvent
+
+	// types are the types of the events to receive.  Empty means all types.
+	types []liveEventType
+}
+
+// wants returns true if s subscribes to the events of typ.
+func (s *eventSubscriber) wants(typ liveEventType) (ok bool) {
+	return len(s.types) == 0 || slices.Contains(s.types, typ)
+}
+
+// maxSeenClients is the maximum number of the clients remembered by the
+// [eventHub].  When it's reached, the clients are forgotten, so that the
+// following queries of each client are reported as [liveEventClientConnected]
+// again.
+const maxSeenClients = 10_000
+
+// eventHub fans out the live events to the subscribers, like the web UI.  A nil
+// *eventHub drops all events.  It's safe for concurrent use.
+type eventHub struct {
+	// mu protects subs.
+	mu *sync.RWMutex
+
+	// subs are the current subscribers.
+	subs map[*eventSubscriber]struct{}
+
+	// seenMu protects seen.
+	seenMu *sync.Mutex
+
+	// seen are the clients, which have sent queries since the start.
+	seen map[string]struct{}
+}
+
+// newEventHub returns a new properly initialized *eventHub.
+func newEventHub() (h *eventHub) {
+	return &eventHub{
+		mu:     &sync.RWMutex{},
+		subs:   map[*eventSubscriber]struct{}{},
+		seenMu: &sync.Mutex{},
+		seen:   map[string]struct{}{},
+	}
+}
+
+// subscribe returns a new subscriber to the events of types.  Empty types
+// means all types.  The subscriber must be removed with [eventHub.unsubscribe].
+func (h *eventHub) subscribe(types []liveEventType) (s *eventSubscriber) {
+	s = &eventSubscriber{
+		events: make(chan *liveEvent, eventSubscriberBufSize),
+		types:  types,
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.subs[s] = struct{}{}
+
+	return s
+}
+
+// unsubscribe removes s from the subscribers.
+func (h *eventHub) unsubscribe(s *eventSubscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.subs, s)
+}
+
+// subscribed returns true if there is at least one subscriber to the events of
+// typ.
+func (h *eventHub) subscribed(typ liveEventType) (ok bool) {
+	if h == nil {
+		return false
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for s := range h.subs {
+		if s.wants(typ) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// publish sends the event of typ with data to the subscribers without
+// blocking.
+func (h *eventHub) publish(typ liveEventType, data any) {
+	if h == nil {
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if len(h.subs) == 0 {
+		return
+	}
+
+	ev := &liveEvent{
+		Time: time.Now(),
+		Data: data,
+		Type: typ,
+	}
+
+	for s := range h.subs {
+		if !s.wants(typ) {
+			continue
+		}
+
+		select {
+		case s.events <- ev:
+		default:
+			// Drop the event for the slow subscriber.
+		}
+	}
+}
+
+// queryLogEventData is the payload of the [liveEventQueryLog] events.
+type queryLogEventData struct {
+	Time      time.Time `json:"time"`
+	Domain    string    `json:"domain"`
+	Client    string    `json:"client"`
+	ElapsedMs float64   `json:"elapsed_ms"`
+	Blocked   bool      `json:"blocked"`
+}
+
+// clientConnectedEventData is the payload of the [liveEventClientConnected]
+// events.
+type clientConnectedEventData struct {
+	// Client is the IP address or the ClientID of the client.
+	Client string `json:"client"`
+}
+
+// publishQueryLogEntry publishes the query log entry e and, if it's the first
+// query of its client, the [liveEventClientConnected] event.  It's intended to
+// be used as the [querylog.Config.OnAdd] callback.
+func (h *eventHub) publishQueryLogEntry(e notifications.QueryLogEntry) {
+	if h == nil {
+		return
+	}
+
+	if h.markSeen(e.Client) {
+		h.publish(liveEventClientConnected, &clientConnectedEventData{
+			Client: e.Client,
+		})
+	}
+
+	if !h.subscribed(liveEventQueryLog) {
+		return
+	}
+
+	h.publish(liveEventQueryLog, &queryLogEventData{
+		Time:      e.Time,
+		Domain:    e.Domain,
+		Client:    e.Client,
+		ElapsedMs: float64(e.Duration.Microseconds()) / 1000,
+		Blocked:   e.Blocked,
+	})
+}
+
+// markSeen remembers client and returns true if it hasn't been seen before.
+func (h *eventHub) markSeen(client string) (isNew bool) {
+	if client == "" {
+		return false
+	}
+
+	h.seenMu.Lock()
+	defer h.seenMu.Unlock()
+
+	if _, ok := h.seen[client]; ok {
+		return false
+	}
+
+	if len(h.seen) >= maxSeenClients {
+		clear(h.seen)
+	}
+
+	h.seen[client] = struct{}{}
+
+	return true
+}
+
+// filterUpdateEventData is the payload of the [liveEventFilterUpdate] events.
+type filterUpdateEventData struct {
+	Name  string `json:"name"`
+	URL   string `json:"url"`
+	Error string `json:"error,omitempty"`
+	ID    uint64 `json:"id"`
+	Done  int    `json:"done"`
+	Total int    `json:"total"`
+
+	// Whitelist is true for the allowlists.
+	Whitelist bool `json:"whitelist"`
+
+	Updated bool `json:"updated"`
+}
+
+// publishFilterUpdate publishes the progress of a refresh of the filter lists.
+// It's intended to be used as the
+// [filtering.Config.ListUpdateProgressNotifier] callback.
+func (h *eventHub) publishFilterUpdate(_ context.Context, ev filtering.ListUpdateProgressEvent) {
+	data := &filterUpdateEventData{
+		Name:      ev.Name,
+		URL:       ev.URL,
+		ID:        ev.ID,
+		Done:      ev.Done,
+		Total:     ev.Total,
+		Whitelist: ev.Type == filtering.ListTypeAllow,
+		Updated:   ev.Updated,
+	}
+
+	if ev.Err != nil {
+		data.Error = ev.Err.Error()
+	}
+
+	h.publish(liveEventFilterUpdate, data)
+}
+
+// publishNotification publishes the notification ev and, if it's an alert, the
+// [liveEventAlert] event.  It's intended to be used as the listener of the
+// [notifications.Manager].
+func (h *eventHub) publishNotification(ev notifications.LiveEvent) {
+	h.publish(liveEventNotification, ev)
+	if ev.Type == notifications.LiveEventTypeAlert {
+		h.publish(liveEventAlert, ev)
+	}
+}
+
+// configChangedEventData is the payload of the [liveEventConfigChanged]
+// events.
+type configChangedEventData struct {
+	// User is the login of the user, who has made the change, if any.
+	User string `json:"user,omitempty"`
+
+	// RemoteIP is the address of the client, which has made the change, if
+	// any.
+	RemoteIP string `json:"remote_ip,omitempty"`
+
+	// Keys are the dot-separated paths of the changed properties, like
+	// "dns.upstream_dns".  The values aren't included, since they may
+	// contain secrets.
+	Keys []string `json:"keys"`
+}
+
+// publishConfigChange publishes the difference between the configuration
+// file contents prev and the current contents of the file at confPath.
+func (cm *defaultConfigModifier) publishConfigChange(ctx context.Context, prev []byte, confPath string) {
+	cur, err := os.ReadFile(confPath)
+	if err != nil {
+		cm.logger.DebugContext(ctx, "reading config for live events", slogutil.KeyError, err)
+
+		return
+	}
+
+	keys, err := configChangedKeys(prev, cur)
+	if err != nil {
+		cm.logger.DebugContext(ctx, "comparing configs", slogutil.KeyError, err)
+
+		return
+	} else if len(keys) == 0 {
+		return
+	}
+
+	data := &configChangedEventData{
+		Keys: keys,
+	}
+
+	if ip, ok := remoteIPFromContext(ctx); ok {
+		data.RemoteIP = ip.String()
+	}
+
+	if u, ok := webUserFromContext(ctx); ok {
+		data.User = string(u.Login)
+	}
+
+	globalContext.events.publish(liveEventConfigChanged, data)
+}
+
+// statsCounters are the counters of the statistics.
+type statsCounters struct {
+	NumDNSQueries           uint64 `json:"num_dns_queries"`
+	NumBlockedFiltering     uint64 `json:"num_blocked_filtering"`
+	NumReplacedSafebrowsing uint64 `json:"num_replaced_safebrowsing"`
+	NumReplacedParental     uint64 `json:"num_replaced_parental"`
+}
+
+// sub returns the difference between c and prev.  The counters, which have
+// decreased, like after the oldest statistics unit has been dropped, are zero.
+func (c statsCounters) sub(prev statsCounters) (d statsCounters) {
+	diff := func(cur, old uint64) (n uint64) {
+		if cur < old {
+			return 0
+		}
+
+		return cur - old
+	}
+
+	return statsCounters{
+		NumDNSQueries:           diff(c.NumDNSQueries, prev.NumDNSQueries),
+		NumBlockedFiltering:     diff(c.NumBlockedFiltering, prev.NumBlockedFiltering),
+		NumReplacedSafebrowsing: diff(c.NumReplacedSafebrowsing, prev.NumReplacedSafebrowsing),
+		NumReplacedParental:     diff(c.NumReplacedParental, prev.NumReplacedParental),
+	}
+}
+
+// statsEventData is the payload of the [liveEventStats] events.
+type statsEventData struct {
+	// Totals are the current counters for the statistics period.
+	Totals statsCounters `json:"totals"`
+
+	// Delta is the change of the counters since the previous event.
+	Delta statsCounters `json:"delta"`
+
+	// AvgProcessingTime is the average processing time of the requests in
+	// seconds.
+	AvgProcessingTime float64 `json:"avg_processing_time"`
+}
+
+// liveStatsInterval is the interval between the [liveEventStats] events.
+const liveStatsInterval = 2 * time.Second
+
+// runStats publishes the changes of the statistics every ivl until ctx is
+// canceled.  The statistics are only read while there are subscribers.  It's
+// intended to be used as a goroutine.
+func (h *eventHub) runStats(ctx context.Context, ivl time.Duration) {
+	ticker := time.NewTicker(ivl)
+	defer ticker.Stop()
+
+	var prev *statsCounters
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		s := globalContext.stats
+		if s == nil || !h.subscribed(liveEventStats) {
+			prev = nil
+
+			continue
+		}
+
+		var cur statsCounters
+		var avg float64
+		cur.NumDNSQueries,
+			cur.NumBlockedFiltering,
+			cur.NumReplacedSafebrowsing,
+			cur.NumReplacedParental,
+			avg = s.GetCurrentStats()
+
+		if prev != nil && cur != *prev {
+			h.publish(liveEventStats, &statsEventData{
+				Totals:            cur,
+				Delta:             cur.sub(*prev),
+				AvgProcessingTime: avg,
+			})
+		}
+
+		prev = &cur
+	}
+}
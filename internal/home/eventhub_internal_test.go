@@ -0,0 +1,228 @@
+package home
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+func TestEventHub(t *testing.T) {
+	t.Parallel()
+
+	hub := newEventHub()
+	assert.False(t, hub.subscribed(liveEventStats))
+
+	all := hub.subscribe(nil)
+	stats := hub.subscribe([]liveEventType{liveEventStats})
+	require.True(t, hub.subscribed(liveEventStats))
+
+	hub.publish(liveEventQueryLog, "query")
+	hub.publish(liveEventStats, "stats")
+
+	require.Len(t, all.events, 2)
+	require.Len(t, stats.events, 1)
+
+	assert.Equal(t, liveEventQueryLog, (<-all.events).Type)
+	assert.Equal(t, liveEventStats, (<-all.events).Type)
+	assert.Equal(t, "stats", (<-stats.events).Data)
+
+	// The events overflowing the buffer are dropped.
+	for range eventSubscriberBufSize + 1 {
+		hub.publish(liveEventStats, nil)
+	}
+
+	assert.Len(t, stats.events, eventSubscriberBufSize)
+
+	hub.unsubscribe(all)
+	hub.unsubscribe(stats)
+	assert.False(t, hub.subscribed(liveEventStats))
+
+	var nilHub *eventHub
+	assert.NotPanics(t, func() {
+		nilHub.publish(liveEventStats, nil)
+	})
+}
+
+func TestParseLiveEventTypes(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		in         string
+		wantErrMsg string
+		want       []liveEventType
+	}{{
+		name:       "empty",
+		in:         "",
+		wantErrMsg: "",
+		want:       nil,
+	}, {
+		name:       "valid",
+		in:         "stats, querylog",
+		wantErrMsg: "",
+		want:       []liveEventType{liveEventStats, liveEventQueryLog},
+	}, {
+		name:       "bad",
+		in:         "stats,bad",
+		wantErrMsg: `types: bad enum value: "bad"`,
+		want:       nil,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			types, err := parseLiveEventTypes(tc.in)
+			if tc.wantErrMsg != "" {
+				require.Error(t, err)
+
+				assert.Equal(t, tc.wantErrMsg, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.want, types)
+		})
+	}
+}
+
+func TestWebAPI_serveLiveEvents(t *testing.T) {
+	t.Parallel()
+
+	hub := newEventHub()
+	web := &webAPI{
+		logger: testLogger,
+		cors:   newCORSMiddleware(nil),
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.serveLiveEvents(w, r, hub)
+	}))
+	t.Cleanup(srv.Close)
+
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/control/events/ws?types=stats"
+
+	t.Run("cross_origin", func(t *testing.T) {
+		_, err := websocket.Dial(wsURL, "", "http://evil.example")
+		assert.Error(t, err)
+	})
+
+	ws, err := websocket.Dial(wsURL, "", srv.URL)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ws.Close() })
+
+	require.Eventually(t, func() (ok bool) {
+		return hub.subscribed(liveEventStats)
+	}, testTimeout, time.Millisecond)
+
+	hub.publish(liveEventQueryLog, "skipped")
+	hub.publish(liveEventStats, map[string]any{"n": 1.0})
+
+	err = ws.SetReadDeadline(time.Now().Add(testTimeout))
+	require.NoError(t, err)
+
+	var ev liveEvent
+	err = websocket.JSON.Receive(ws, &ev)
+	require.NoError(t, err)
+
+	assert.Equal(t, liveEventStats, ev.Type)
+	assert.Equal(t, map[string]any{"n": 1.0}, ev.Data)
+
+	require.NoError(t, ws.Close())
+
+	assert.Eventually(t, func() (ok bool) {
+		return !hub.subscribed(liveEventStats)
+	}, testTimeout, time.Millisecond)
+}
+
+func TestEventHub_publishQueryLogEntry(t *testing.T) {
+	t.Parallel()
+
+	hub := newEventHub()
+	sub := hub.subscribe([]liveEventType{liveEventClientConnected})
+	t.Cleanup(func() { hub.unsubscribe(sub) })
+
+	for _, client := range []string{"192.0.2.1", "192.0.2.1", "192.0.2.2", ""} {
+		hub.publishQueryLogEntry(notifications.QueryLogEntry{
+			Domain: "example.org",
+			Client: client,
+		})
+	}
+
+	require.Len(t, sub.events, 2)
+
+	ev := <-sub.events
+	assert.Equal(t, &clientConnectedEventData{Client: "192.0.2.1"}, ev.Data)
+
+	ev = <-sub.events
+	assert.Equal(t, &clientConnectedEventData{Client: "192.0.2.2"}, ev.Data)
+}
+
+func TestEventHub_publishNotification(t *testing.T) {
+	t.Parallel()
+
+	hub := newEventHub()
+	sub := hub.subscribe([]liveEventType{liveEventAlert})
+	t.Cleanup(func() { hub.unsubscribe(sub) })
+
+	hub.publishNotification(notifications.LiveEvent{Type: "recovery"})
+	hub.publishNotification(notifications.LiveEvent{
+		Type:   notifications.LiveEventTypeAlert,
+		Metric: "cpu",
+	})
+
+	require.Len(t, sub.events, 1)
+
+	ev := <-sub.events
+	assert.Equal(t, liveEventAlert, ev.Type)
+}
+
+func TestWebAPI_streamEvents(t *testing.T) {
+	t.Parallel()
+
+	hub := newEventHub()
+	web := &webAPI{
+		logger: testLogger,
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.streamEvents(w, r, hub)
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx := testutil.ContextWithTimeout(t, testTimeout)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/control/events", nil)
+	require.NoError(t, err)
+
+	resp, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.Eventually(t, func() (ok bool) {
+		return hub.subscribed(liveEventConfigChanged)
+	}, testTimeout, time.Millisecond)
+
+	// Not a system event.
+	hub.publish(liveEventStats, nil)
+	hub.publish(liveEventConfigChanged, &configChangedEventData{
+		Keys: []string{"dns.upstream_dns"},
+	})
+
+	sc := bufio.NewScanner(resp.Body)
+	require.True(t, sc.Scan())
+	assert.Equal(t, "event: config_changed", sc.Text())
+
+	require.True(t, sc.Scan())
+	assert.Contains(t, sc.Text(), `"keys":["dns.upstream_dns"]`)
+}
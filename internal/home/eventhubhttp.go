@@ -0,0 +1,247 @@
+package home
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+	"golang.org/x/net/websocket"
+)
+
+// registerEventHubHandlers registers the HTTP handlers of the live events.
+func (web *webAPI) registerEventHubHandlers() {
+	aghhttp.RegisterVersions(
+		web.httpReg,
+		http.MethodGet,
+		"/control/events",
+		web.handleEvents,
+		newV1Versions(&aghhttp.RouteInfo{
+			Summary: "Stream the system events as Server-Sent Events",
+			Description: "Pushes the configuration changes, the filter list updates, " +
+				"the newly connected clients, and the alerts.  The name of each " +
+				"Server-Sent Event is the type of the event.  The optional types " +
+				"query parameter is the comma-separated list of the event types to " +
+				"receive.",
+			Response: liveEvent{},
+		}),
+	)
+	aghhttp.RegisterVersions(
+		web.httpReg,
+		http.MethodGet,
+		"/control/events/ws",
+		web.handleEventsWS,
+		newV1Versions(&aghhttp.RouteInfo{
+			Summary: "Stream the live updates over a WebSocket",
+			Description: "Pushes the new query log entries, the changes of the statistics, " +
+				"the progress of the filter list updates, and the notifications as JSON " +
+				"messages.  The optional types query parameter is the comma-separated " +
+				"list of the event types to receive.",
+			Response: liveEvent{},
+		}),
+	)
+}
+
+// systemEventTypes are the types of the events sent by GET /control/events by
+// default.
+var systemEventTypes = []liveEventType{
+	liveEventConfigChanged,
+	liveEventFilterUpdate,
+	liveEventClientConnected,
+	liveEventAlert,
+}
+
+// sseKeepAliveInterval is the interval between the comments sent to the idle
+// Server-Sent Events streams, so that the proxies don't close them.
+const sseKeepAliveInterval = 30 * time.Second
+
+// handleEvents is the handler for the GET /control/events HTTP API.
+func (web *webAPI) handleEvents(w http.ResponseWriter, r *http.Request) {
+	web.streamEvents(w, r, globalContext.events)
+}
+
+// streamEvents sends the events of hub as Server-Sent Events until the client
+// disconnects.
+func (web *webAPI) streamEvents(w http.ResponseWriter, r *http.Request, hub *eventHub) {
+	ctx := r.Context()
+
+	types, err := parseLiveEventTypes(r.URL.Query().Get("types"))
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusBadRequest, "%s", err)
+
+		return
+	} else if hub == nil {
+		aghhttp.ErrorAndLog(
+			ctx,
+			web.logger,
+			r,
+			w,
+			http.StatusServiceUnavailable,
+			"live events are not available",
+		)
+
+		return
+	}
+
+	if len(types) == 0 {
+		types = systemEventTypes
+	}
+
+	rc := http.NewResponseController(w)
+
+	// The stream outlives the write timeout of the server.
+	if err = rc.SetWriteDeadline(time.Time{}); err != nil {
+		web.logger.DebugContext(ctx, "events stream: removing deadline", slogutil.KeyError, err)
+	}
+
+	sub := hub.subscribe(types)
+	defer hub.unsubscribe(sub)
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	h.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	ticker := time.NewTicker(sseKeepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		if err = rc.Flush(); err != nil {
+			web.logger.DebugContext(ctx, "events stream: flushing", slogutil.KeyError, err)
+
+			return
+		}
+
+		select {
+		case ev := <-sub.events:
+			writeSSEEvent(w, string(ev.Type), ev)
+		case <-ticker.C:
+			_, _ = io.WriteString(w, ": keep-alive\n\n")
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// handleEventsWS is the handler for the GET /control/events/ws HTTP API.
+func (web *webAPI) handleEventsWS(w http.ResponseWriter, r *http.Request) {
+	web.serveLiveEvents(w, r, globalContext.events)
+}
+
+// serveLiveEvents upgrades the connection to a WebSocket and sends the events
+// of hub until the client disconnects.
+func (web *webAPI) serveLiveEvents(w http.ResponseWriter, r *http.Request, hub *eventHub) {
+	ctx := r.Context()
+
+	types, err := parseLiveEventTypes(r.URL.Query().Get("types"))
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusBadRequest, "%s", err)
+
+		return
+	} else if hub == nil {
+		aghhttp.ErrorAndLog(
+			ctx,
+			web.logger,
+			r,
+			w,
+			http.StatusServiceUnavailable,
+			"live events are not available",
+		)
+
+		return
+	}
+
+	srv := websocket.Server{
+		Handshake: web.checkWebSocketOrigin,
+		Handler: func(ws *websocket.Conn) {
+			web.sendLiveEvents(ctx, ws, hub, types)
+		},
+	}
+
+	srv.ServeHTTP(w, r)
+}
+
+// checkWebSocketOrigin rejects the cross-origin WebSocket connections, unless
+// the origin is allowed by the CORS policy, since the browsers don't apply the
+// same-origin policy to them.  It's used as the [websocket.Server.Handshake].
+func (web *webAPI) checkWebSocketOrigin(conf *websocket.Config, r *http.Request) (err error) {
+	origin, err := websocket.Origin(conf, r)
+	if err != nil {
+		// Don't wrap the error, since it's informative enough as is.
+		return err
+	} else if origin == nil {
+		// Not a browser.
+		return nil
+	}
+
+	if strings.EqualFold(origin.Host, r.Host) {
+		return nil
+	}
+
+	if web.cors != nil && web.cors.allowed(strings.TrimSuffix(origin.String(), "/")) {
+		return nil
+	}
+
+	return fmt.Errorf("origin %q is not allowed", origin)
+}
+
+// sendLiveEvents subscribes to the events of types from hub and sends them to
+// ws until the client disconnects or ctx is canceled.
+func (web *webAPI) sendLiveEvents(
+	ctx context.Context,
+	ws *websocket.Conn,
+	hub *eventHub,
+	types []liveEventType,
+) {
+	defer func() {
+		err := ws.Close()
+		if err != nil {
+			web.logger.DebugContext(ctx, "closing websocket", slogutil.KeyError, err)
+		}
+	}()
+
+	// The connection outlives the timeouts of the server.
+	err := ws.SetDeadline(time.Time{})
+	if err != nil {
+		web.logger.DebugContext(ctx, "websocket: removing deadline", slogutil.KeyError, err)
+	}
+
+	sub := hub.subscribe(types)
+	defer hub.unsubscribe(sub)
+
+	// The client isn't expected to send anything, so read only to detect the
+	// closing of the connection.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+
+		var msg []byte
+		for {
+			if websocket.Message.Receive(ws, &msg) != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case ev := <-sub.events:
+			err = websocket.JSON.Send(ws, ev)
+			if err != nil {
+				web.logger.DebugContext(ctx, "websocket: sending event", slogutil.KeyError, err)
+
+				return
+			}
+		case <-closed:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -3,9 +3,12 @@ package home
 
 import (
 	"context"
+	"crypto/ed25519"
+	"encoding/base64"
 	"fmt"
 	"io/fs"
 	"log/slog"
+	"maps"
 	"net/http"
 	"net/netip"
 	"net/url"
@@ -35,8 +38,10 @@ import (
 	"github.com/AdguardTeam/AdGuardHome/internal/filtering/safesearch"
 	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
 	"github.com/AdguardTeam/AdGuardHome/internal/permcheck"
+	"github.com/AdguardTeam/AdGuardHome/internal/plugin"
 	"github.com/AdguardTeam/AdGuardHome/internal/querylog"
 	"github.com/AdguardTeam/AdGuardHome/internal/stats"
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
 	"github.com/AdguardTeam/AdGuardHome/internal/updater"
 	"github.com/AdguardTeam/AdGuardHome/internal/version"
 	"github.com/AdguardTeam/dnsproxy/upstream"
@@ -63,6 +68,14 @@ type homeContext struct {
 	dhcpServer dhcpd.Interface        // DHCP module
 	notifier   *notifications.Manager // notifications manager
 
+	// sysInfoHistory contains the recent samples of the system metrics.  It's
+	// nil until the system information is configured.
+	sysInfoHistory *systeminfo.History
+
+	// events pushes the live updates to the web UI.  It's nil until the
+	// modules are initialized.
+	events *eventHub
+
 	filters *filtering.DNSFilter // DNS filtering module
 	web     *webAPI              // Web (HTTP, HTTPS) module
 
@@ -70,10 +83,26 @@ type homeContext struct {
 	// configuration files, for example /etc/hosts.
 	etcHosts *aghnet.HostsContainer
 
+	// plugins manages the plugins.  It's nil on the first run.
+	plugins *plugin.Manager
+
 	// Runtime properties
 	// --
 
 	pidFileName string // PID file name.  Empty if no PID file was created.
+
+	// tlsKeyPassphrase is the passphrase of the encrypted TLS private key
+	// entered on the terminal at startup.  It's nil if it wasn't prompted.
+	tlsKeyPassphrase []byte
+
+	// lastCrashTime is the time of the latest crash of AdGuard Home.  It's
+	// zero if there are no crash reports.
+	lastCrashTime time.Time
+
+	// localesFS contains the web UI translation files used to localize the
+	// notifications.  It may be nil.
+	localesFS fs.FS
+
 	controlLock sync.Mutex
 }
 
@@ -86,6 +115,10 @@ var globalContext homeContext
 func Main(clientBuildFS fs.FS) {
 	ctx := context.Background()
 
+	if len(os.Args) > 1 && os.Args[1] == ctlArg {
+		os.Exit(runCtl(ctx, os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	initCmdLineOpts()
 
 	// The configuration file path can be overridden, but other command-line
@@ -123,6 +156,12 @@ func Main(clientBuildFS fs.FS) {
 		baseLogger.InfoContext(ctx, "adguard home is running as a service")
 	}
 
+	if act := opts.serviceControlAction; act == "" || act == "run" {
+		crashLogger := baseLogger.With(slogutil.KeyPrefix, "crash")
+		globalContext.lastCrashTime = collectCrashReport(ctx, crashLogger, workDir, act == "run")
+		setCrashOutput(ctx, crashLogger, workDir)
+	}
+
 	var glTokenFileRoot *os.Root
 	if opts.glinetMode {
 		glTokenFileRoot, err = os.OpenRoot("/tmp/")
@@ -138,6 +177,8 @@ func Main(clientBuildFS fs.FS) {
 	sigHdlr := newSignalHandler(sigHdlrLogger, signals, func(ctx context.Context) {
 		defer close(done)
 
+		notifyServiceManager(ctx, sigHdlrLogger, aghos.SDNotifyStopping)
+
 		cleanup(ctx)
 		cleanupAlways()
 
@@ -215,6 +256,20 @@ func setupContext(
 	}
 
 	if opts.checkConfig {
+		errs := checkConfigRefs(config)
+		for _, e := range errs {
+			baseLogger.ErrorContext(
+				ctx,
+				"invalid configuration",
+				"path", e.Path,
+				slogutil.KeyError, e.Message,
+			)
+		}
+
+		if len(errs) > 0 {
+			os.Exit(osutil.ExitCodeFailure)
+		}
+
 		baseLogger.InfoContext(ctx, "configuration file is ok")
 
 		os.Exit(osutil.ExitCodeSuccess)
@@ -277,6 +332,62 @@ func configureOS(ctx context.Context, l *slog.Logger, conf *configuration) (err
 	return nil
 }
 
+// configureSystemInfo sets the path, which disk usage is reported in the system
+// information, the top processes and power probes, and the public IP address
+// lookup, and starts collecting the history of the metrics.  The working directory is used
+// by default, since it may be on a different drive than the system one, like
+// the fallback one on Windows.  l must not be nil.
+func configureSystemInfo(
+	ctx context.Context,
+	l *slog.Logger,
+	osConf *osConfig,
+	workDir string,
+) (err error) {
+	path := workDir
+	var providers []string
+	var topProcs int
+	if osConf != nil {
+		if osConf.DiskPath != "" {
+			path = osConf.DiskPath
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(workDir, path)
+			}
+		}
+
+		providers = osConf.PublicIPProviders
+		topProcs = osConf.TopProcesses
+	}
+
+	if topProcs < 0 || topProcs > maxTopProcesses {
+		return fmt.Errorf("top_processes: must be between 0 and %d, got %d", maxTopProcesses, topProcs)
+	}
+
+	systeminfo.SetDiskPath(path)
+	systeminfo.SetTopProcesses(topProcs)
+
+	err = configurePower(osConf)
+	if err != nil {
+		return fmt.Errorf("configuring power probe: %w", err)
+	}
+
+	l.DebugContext(ctx, "system info disk path set", "path", path)
+
+	err = startSystemInfoHistory(ctx, l, osConf)
+	if err != nil {
+		return fmt.Errorf("starting system info history: %w", err)
+	}
+
+	err = systeminfo.SetPublicIPConfig(&systeminfo.PublicIPConfig{
+		Client:    publicIPHTTPClient(),
+		Providers: providers,
+	})
+	if err != nil {
+		return fmt.Errorf("configuring public ip lookup: %w", err)
+	}
+
+	return nil
+}
+
 // setupHostsContainer initializes the structures to keep up-to-date the hosts
 // provided by the OS.  baseLogger must not be nil.
 func setupHostsContainer(ctx context.Context, baseLogger *slog.Logger) (err error) {
@@ -355,6 +466,7 @@ func initContextClients(
 	config.DHCP.CommandConstructor = executil.SystemCommandConstructor{}
 	config.DHCP.Logger = logger.With(slogutil.KeyPrefix, "dhcpd")
 	config.DHCP.ConfModifier = confModifier
+	config.DHCP.OnLeaseEvent = newDHCPLeaseEventHandler(ctx)
 
 	globalContext.dhcpServer, err = dhcpd.Create(ctx, config.DHCP)
 	if globalContext.dhcpServer == nil || err != nil {
@@ -462,6 +574,7 @@ func setupDNSFilteringConf(
 	conf.WhitelistFilters = slices.Clone(config.WhitelistFilters)
 	conf.UserRules = slices.Clone(config.UserRules)
 	conf.HTTPClient = httpClient(tlsMgr)
+	conf.ListUpdateProgressNotifier = globalContext.events.publishFilterUpdate
 	conf.ListUpdateNotifier = func(ctx context.Context, ev filtering.ListUpdateEvent) {
 		n := globalContext.notifier
 		if n == nil {
@@ -483,6 +596,27 @@ func setupDNSFilteringConf(
 			ListType:     listType,
 		})
 	}
+	conf.ListUpdateFailureNotifier = func(ctx context.Context, ev filtering.ListUpdateFailureEvent) {
+		n := globalContext.notifier
+		if n == nil {
+			return
+		}
+
+		listType := notifications.FilterListTypeBlock
+		if ev.Type == filtering.ListTypeAllow {
+			listType = notifications.FilterListTypeAllow
+		}
+
+		n.NotifyFilterUpdateFailure(ctx, notifications.FilterUpdateFailure{
+			LastSuccess: ev.LastSuccess,
+			Name:        ev.Name,
+			URL:         ev.URL,
+			Reason:      string(ev.Reason),
+			Error:       ev.Err.Error(),
+			ListType:    listType,
+			ID:          ev.ID,
+		})
+	}
 
 	cacheTime := time.Duration(conf.CacheTime) * time.Minute
 
@@ -663,6 +797,17 @@ type webConfig struct {
 	// configModifier is used to update the global configuration.
 	configModifier agh.ConfigModifier
 
+	// confHistory is the history of the configuration file.  It's nil if the
+	// history is disabled.
+	confHistory *configHistory
+
+	// syncReplica pulls the configuration from the primary instance.  It's nil
+	// unless the instance is a replica.
+	syncReplica *syncReplica
+
+	// confReloader reloads the configuration file.  It's nil on the first run.
+	confReloader *configReloader
+
 	// httpReg registers HTTP handlers. It must not be nil.
 	httpReg aghhttp.Registrar
 
@@ -710,7 +855,16 @@ func newWeb(ctx context.Context, conf *webConfig) (web *webAPI, err error) {
 		auth:               conf.auth,
 		mux:                conf.mux,
 
-		clientFS: clientFS,
+		clientFS:     clientFS,
+		cors:         config.HTTPConfig.CORS,
+		compression:  config.HTTPConfig.Compression,
+		unixSocket:   config.HTTPConfig.UnixSocket,
+		clientAuth:   config.HTTPConfig.ClientAuth,
+		doHRoutes:    config.HTTPConfig.DoH.Routes,
+		auditLog:     config.HTTPConfig.AuditLog,
+		confHistory:  conf.confHistory,
+		syncReplica:  conf.syncReplica,
+		confReloader: conf.confReloader,
 
 		BindAddr: config.HTTPConfig.Address,
 
@@ -811,6 +965,9 @@ func run(
 	err = configureOS(ctx, baseLogger, config)
 	fatalOnError(err)
 
+	err = configureSystemInfo(ctx, baseLogger, config.OSConfig, workDir)
+	fatalOnError(err)
+
 	// Clients package uses filtering package's static data
 	// (filtering.BlockedSvcKnown()), so we have to initialize filtering static
 	// data first, but also to avoid relying on automatic Go init() function.
@@ -826,6 +983,8 @@ func run(
 	err = initContextClients(ctx, baseLogger, sigHdlr, confModifier, httpReg, workDir)
 	fatalOnError(err)
 
+	globalContext.events = newEventHub()
+
 	tlsMgr, err := initTLS(ctx, baseLogger, sigHdlr, confModifier, httpReg, acmeMgr)
 	fatalOnError(err)
 
@@ -845,6 +1004,10 @@ func run(
 
 	upd, isCustomURL := initUpdate(ctx, baseLogger, opts, tlsMgr, isFirstRun, workDir, confPath)
 
+	updLogger := baseLogger.With(slogutil.KeyPrefix, "updater")
+	cmdCons := executil.SystemCommandConstructor{}
+	verifyUpd := checkPendingUpdate(ctx, updLogger, upd, cmdCons, opts.runningAsService)
+
 	dataDirPath := filepath.Join(workDir, dataDir)
 	err = os.MkdirAll(dataDirPath, aghos.DefaultPermDir)
 	fatalOnError(errors.Annotate(err, "creating DNS data dir at %s: %w", dataDirPath))
@@ -854,6 +1017,40 @@ func run(
 
 	confModifier.setAuth(auth)
 
+	confHistory, err := newConfigHistory(
+		filepath.Join(dataDirPath, configHistoryDir),
+		config.ConfigHistory,
+		confModifier.changesMu,
+	)
+	fatalOnError(err)
+
+	confModifier.setHistory(confHistory)
+
+	syncReplica, syncPrimary := newConfigSync(
+		ctx,
+		baseLogger,
+		config.Sync,
+		tlsMgr,
+		confModifier,
+		cmdCons,
+		opts.runningAsService,
+		workDir,
+		confPath,
+	)
+
+	var confReloader *configReloader
+	if !isFirstRun {
+		confReloader = newConfigReloader(
+			ctx,
+			baseLogger,
+			tlsMgr,
+			confModifier,
+			newRestartFunc(baseLogger, cmdCons, opts.runningAsService),
+			workDir,
+			confPath,
+		)
+	}
+
 	conf := &webConfig{
 		clientBuildFS:  clientBuildFS,
 		updater:        upd,
@@ -863,6 +1060,9 @@ func run(
 		auth:           auth,
 		mux:            mux,
 		configModifier: confModifier,
+		confHistory:    confHistory,
+		syncReplica:    syncReplica,
+		confReloader:   confReloader,
 		httpReg:        httpReg,
 		workDir:        workDir,
 		confPath:       confPath,
@@ -879,16 +1079,45 @@ func run(
 
 	tlsMgr.setWebAPI(web)
 
-	initNotifications(ctx, baseLogger)
+	globalContext.localesFS = newLocalesFS(ctx, baseLogger, opts, clientBuildFS)
+	initNotifications(ctx, baseLogger, workDir)
 
 	statsDir, querylogDir, err := checkStatsAndQuerylogDirs(config, workDir)
 	fatalOnError(err)
 
+	configureSMART(ctx, baseLogger, config.OSConfig, querylogDir)
+
 	if !isFirstRun {
+		globalContext.plugins, err = initPlugins(ctx, baseLogger, cmdCons, httpReg, workDir)
+		fatalOnError(err)
+
 		runDNSServer(ctx, baseLogger, tlsMgr, confModifier, statsDir, querylogDir, httpReg)
 		injectNotificationProviders()
+
+		// The sync uses the DNS server to resolve the addresses of the peers.
+		go syncReplica.run(ctx)
+		go syncPrimary.run(ctx)
+
+		// Only reload the configuration on SIGHUP once the DNS server is
+		// running.
+		sigHdlr.addConfigReloader(confReloader)
 	}
 
+	if verifyUpd {
+		config.RLock()
+		timeout := time.Duration(config.AutoUpdate.HealthCheckTimeout)
+		config.RUnlock()
+
+		go verifyUpdate(ctx, updLogger, web, upd, cmdCons, timeout, opts.runningAsService)
+	}
+
+	go web.runAutoUpdate(ctx)
+	go web.runUpdateNotifications(ctx)
+	go globalContext.events.runStats(ctx, liveStatsInterval)
+
+	// The readiness is reported by the web server once it's listening.
+	go runWatchdog(ctx, baseLogger.With(slogutil.KeyPrefix, "sdnotify"))
+
 	if !opts.noPermCheck {
 		checkPermissions(ctx, baseLogger, workDir, confPath, dataDirPath, statsDir, querylogDir)
 	}
@@ -965,6 +1194,11 @@ func initTLS(
 
 	sigHdlr.addTLSManager(aghtlsMgr)
 
+	err = promptTLSKeyPassphrase(ctx, tlsMgrLogger, &config.TLS)
+	if err != nil {
+		tlsMgrLogger.ErrorContext(ctx, "prompting for key passphrase", slogutil.KeyError, err)
+	}
+
 	tlsMgr, err = newTLSManager(ctx, &tlsManagerConfig{
 		logger:        tlsMgrLogger,
 		confModifier:  confModifier,
@@ -979,22 +1213,80 @@ func initTLS(
 		confModifier.Apply(ctx)
 	}
 
+	tlsMgr.initOCSPStapler(ctx, httpClient(tlsMgr))
+
 	confModifier.setTLSManager(tlsMgr)
 
 	return tlsMgr, nil
 }
 
-func initNotifications(ctx context.Context, l *slog.Logger) {
+func initNotifications(ctx context.Context, l *slog.Logger, workDir string) {
 	notifLogger := l.With(slogutil.KeyPrefix, "notifications")
 
 	config.RLock()
 	telegram := config.Notifications.Telegram
 	runtimeCfg := buildRuntimeTelegramConfig(telegram)
+	chansCfg := buildRuntimeChannelsConfig(&config.Notifications)
+	tmpls := maps.Clone(config.Notifications.Templates)
+	routes := maps.Clone(config.Notifications.Routes)
+	quietHours := buildRuntimeQuietHoursConfig(&config.Notifications.QuietHours)
+	digest := buildRuntimeDigestConfig(&config.Notifications.Digest)
+	escalation := buildRuntimeEscalationConfig(&config.Notifications.Escalation)
+	dhcpEvents := buildRuntimeDHCPEventsConfig(&config.Notifications.DHCPEvents)
+	spike := buildRuntimeSpikeConfig(&config.Notifications.BlockedSpike)
+	statsReport := buildRuntimeStatsReportConfig(&config.Notifications.StatsReport)
+	heartbeat := buildRuntimeHeartbeatConfig(&config.Notifications.Heartbeat)
+	rateLimit := buildRuntimeRateLimitConfig(&config.Notifications.RateLimit)
+	bandwidth := buildRuntimeBandwidthConfig(&config.Notifications.Bandwidth)
+	selfMonitor := buildRuntimeSelfMonitorConfig(&config.Notifications.SelfMonitor)
+	dnsPerf := buildRuntimeDNSPerfConfig(&config.Notifications.DNSPerf)
+	lang := config.Language
 	config.RUnlock()
 
 	manager := notifications.NewManager(notifLogger, runtimeCfg)
+	manager.UpdateChannelsConfig(chansCfg)
+	manager.UpdateQuietHoursConfig(quietHours)
+	manager.UpdateDigestConfig(digest)
+	manager.UpdateEscalationConfig(escalation)
+	manager.UpdateDHCPEventsConfig(dhcpEvents)
+	manager.UpdateSpikeConfig(spike)
+	manager.UpdateStatsReportConfig(statsReport)
+	manager.UpdateHeartbeatConfig(heartbeat)
+	manager.UpdateRateLimitConfig(rateLimit)
+	manager.UpdateBandwidthConfig(bandwidth)
+	manager.UpdateSelfMonitorConfig(selfMonitor)
+	manager.UpdateDNSPerfConfig(dnsPerf)
+	manager.SetLocale(loadNotificationLocale(ctx, notifLogger, globalContext.localesFS, lang))
+
+	err := manager.UpdateTemplates(tmpls)
+	if err != nil {
+		notifLogger.ErrorContext(ctx, "parsing message templates", slogutil.KeyError, err)
+	}
+
+	err = manager.UpdateRoutes(routes)
+	if err != nil {
+		notifLogger.ErrorContext(ctx, "applying notification routes", slogutil.KeyError, err)
+	}
+
+	err = manager.OpenHistory(filepath.Join(workDir, dataDir, notificationHistoryFilename))
+	if err != nil {
+		notifLogger.ErrorContext(ctx, "opening notification history", slogutil.KeyError, err)
+	}
+
+	err = manager.OpenRetryQueue(filepath.Join(workDir, dataDir, notificationRetryFilename))
+	if err != nil {
+		notifLogger.ErrorContext(ctx, "opening notification retry queue", slogutil.KeyError, err)
+	}
+
+	err = manager.OpenLifecycleMarker(filepath.Join(workDir, dataDir, notificationLifecycleFilename))
+	if err != nil {
+		notifLogger.ErrorContext(ctx, "opening lifecycle marker", slogutil.KeyError, err)
+	}
+	manager.SetListener(globalContext.events.publishNotification)
 	manager.Start(ctx)
 
+	go manager.NotifyStartup(ctx, version.Version())
+
 	globalContext.notifier = manager
 }
 
@@ -1012,6 +1304,9 @@ func initUpdate(
 	execPath, err := os.Executable()
 	fatalOnError(errors.Annotate(err, "getting executable path: %w"))
 
+	signingKeys, err := parseUpdateSigningKeys(config.UpdateSigningKeys)
+	fatalOnError(err)
+
 	updLogger := baseLogger.With(slogutil.KeyPrefix, "updater")
 	upd, isCustomURL = newUpdater(
 		ctx,
@@ -1020,6 +1315,7 @@ func initUpdate(
 		workDir,
 		confPath,
 		execPath,
+		signingKeys,
 	)
 
 	// TODO(e.burkov): This could be made earlier, probably as the option's
@@ -1033,6 +1329,8 @@ func initUpdate(
 
 		if config.HTTPConfig.Pprof.Enabled {
 			startPprof(baseLogger, config.HTTPConfig.Pprof.Port)
+		} else if config.HTTPConfig.Debug.Enabled {
+			setProfileRates(true)
 		}
 	}
 
@@ -1049,6 +1347,7 @@ func newUpdater(
 	workDir string,
 	confPath string,
 	execPath string,
+	signingKeys []ed25519.PublicKey,
 ) (upd *updater.Updater, isCustomURL bool) {
 	// envName is the name of the environment variable that can be used to
 	// override the default version check URL.
@@ -1071,7 +1370,8 @@ func newUpdater(
 	if isCustomURL = err == nil; !isCustomURL {
 		l.DebugContext(ctx, "parsing custom version url", slogutil.KeyError, err)
 
-		versionURL = updater.DefaultVersionURL()
+		// Use the announcement of the configured update channel.
+		versionURL = nil
 	}
 
 	l.DebugContext(ctx, "creating updater", "config_path", confPath)
@@ -1081,7 +1381,7 @@ func newUpdater(
 		Logger:             l,
 		CommandConstructor: executil.SystemCommandConstructor{},
 		Version:            version.Version(),
-		Channel:            version.Channel(),
+		Channel:            conf.AutoUpdate.Channel,
 		GOARCH:             runtime.GOARCH,
 		GOOS:               runtime.GOOS,
 		GOARM:              version.GOARM(),
@@ -1090,9 +1390,33 @@ func newUpdater(
 		ConfName:           confPath,
 		ExecPath:           execPath,
 		VersionCheckURL:    versionURL,
+		DataFiles:          updateDataFiles(conf, workDir),
+		SigningKeys:        signingKeys,
 	}), isCustomURL
 }
 
+// parseUpdateSigningKeys parses the base64-encoded Ed25519 public keys.
+func parseUpdateSigningKeys(encoded []string) (keys []ed25519.PublicKey, err error) {
+	for i, s := range encoded {
+		var key []byte
+		key, err = base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("update signing key at index %d: %w", i, err)
+		} else if len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf(
+				"update signing key at index %d: bad length %d, want %d",
+				i,
+				len(key),
+				ed25519.PublicKeySize,
+			)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
 // checkPermissions checks and migrates permissions of the files and directories
 // used by AdGuard Home, if needed.
 func checkPermissions(
@@ -1135,22 +1459,25 @@ func initUsers(
 
 	dataDirPath := filepath.Join(workDir, dataDir)
 	auth, err = newAuth(ctx, &authConfig{
-		baseLogger:      baseLogger,
-		mux:             mux,
-		rateLimiter:     rateLimiter,
-		trustedProxies:  netutil.SliceSubnetSet(netutil.UnembedPrefixes(config.DNS.TrustedProxies)),
-		dbFilename:      filepath.Join(dataDirPath, sessionsDBName),
-		doHRoutes:       config.HTTPConfig.DoH.Routes,
-		users:           config.Users,
-		sessionTTL:      time.Duration(config.HTTPConfig.SessionTTL),
-		isGLiNet:        isGLiNet,
-		gliNetTokenRoot: glTokenRoot,
+		baseLogger:         baseLogger,
+		mux:                mux,
+		rateLimiter:        rateLimiter,
+		trustedProxies:     netutil.SliceSubnetSet(netutil.UnembedPrefixes(config.DNS.TrustedProxies)),
+		dbFilename:         filepath.Join(dataDirPath, sessionsDBName),
+		doHRoutes:          config.HTTPConfig.DoH.Routes,
+		users:              config.Users,
+		apiTokens:          config.APITokens,
+		sessionTTL:         time.Duration(config.HTTPConfig.SessionTTL),
+		sessionIdleTimeout: time.Duration(config.HTTPConfig.SessionIdleTimeout),
+		isGLiNet:           isGLiNet,
+		gliNetTokenRoot:    glTokenRoot,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("initializing auth module: %w", err)
 	}
 
 	config.Users = nil
+	config.APITokens = nil
 
 	return auth, nil
 }
@@ -1279,6 +1606,8 @@ func cleanup(ctx context.Context) {
 		log.Error("stopping dns server: %s", err)
 	}
 
+	globalContext.plugins.Shutdown(ctx)
+
 	if globalContext.dhcpServer != nil {
 		err = globalContext.dhcpServer.Stop()
 		if err != nil {
@@ -1293,6 +1622,10 @@ func cleanup(ctx context.Context) {
 	}
 
 	if globalContext.notifier != nil {
+		notifyCtx, cancel := context.WithTimeout(ctx, notificationShutdownTimeout)
+		globalContext.notifier.NotifyShutdown(notifyCtx)
+		cancel()
+
 		globalContext.notifier.Stop()
 		globalContext.notifier = nil
 	}
@@ -75,6 +75,20 @@ func httpClient(tlsMgr *tlsManager) (c *http.Client) {
 	}
 }
 
+// publicIPHTTPClient returns a new HTTP client for the public IP address
+// lookup.  Unlike [httpClient], it uses the system resolver, since the lookup
+// may happen before the DNS server is started, but it respects the configured
+// HTTP proxy.
+func publicIPHTTPClient() (c *http.Client) {
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	tr.Proxy = httpProxy
+
+	return &http.Client{
+		Timeout:   writeTimeout,
+		Transport: newCustomUserAgentTransport(tr, aghhttp.UserAgent()),
+	}
+}
+
 // httpProxy returns parses and returns an HTTP proxy URL from the config, if
 // any.
 func httpProxy(_ *http.Request) (u *url.URL, err error) {
@@ -105,5 +105,7 @@ func (web *webAPI) handleI18nChangeLanguage(w http.ResponseWriter, r *http.Reque
 
 	web.confModifier.Apply(ctx)
 
+	updateNotificationLocale(ctx, l, lang)
+
 	aghhttp.OK(ctx, l, w)
 }
@@ -0,0 +1,278 @@
+package home
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"iter"
+	"net"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/dhcpsvc"
+	"github.com/AdguardTeam/AdGuardHome/internal/filtering"
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/netutil"
+)
+
+// dnsmasqSource is the name of dnsmasq used in the imported configuration.
+const dnsmasqSource = "dnsmasq"
+
+// defaultDnsmasqPort is the port of the upstream servers of dnsmasq, which is
+// used when the port isn't specified.
+const defaultDnsmasqPort uint16 = 53
+
+// Errors of the parsing of the dnsmasq options.
+const (
+	errDnsmasqNoDomains errors.Error = "no domains"
+	errDnsmasqLocal     errors.Error = "local-only domains are not supported"
+)
+
+// parseDnsmasqConf returns the configuration imported from the dnsmasq
+// configuration data.  The options, which can't be imported, are reported in
+// the warnings of d.
+func parseDnsmasqConf(data []byte) (d *importData) {
+	d = &importData{}
+
+	d.parseDnsmasqAddresses(data)
+	d.parseDnsmasqServers(data)
+	d.parseDnsmasqCNAMEs(data)
+	d.parseDnsmasqDHCPHosts(data)
+
+	for _, name := range []string{"conf-file", "conf-dir"} {
+		for val := range dnsmasqOptions(data, name) {
+			d.warnf("%s=%s: included files are not imported", name, val)
+		}
+	}
+
+	return d
+}
+
+// parseDnsmasqAddresses adds the DNS rewrites and the blocking rules from the
+// "address" options of dnsmasq, if any.  The addresses without an IP address,
+// which dnsmasq answers with NXDOMAIN, as well as the null ones, are converted
+// into blocking rules.
+func (d *importData) parseDnsmasqAddresses(data []byte) {
+	for val := range dnsmasqOptions(data, "address") {
+		domains, target, err := splitDnsmasqDomains(val)
+		if err != nil {
+			d.warnf("address %q: %s", val, err)
+
+			continue
+		}
+
+		ip, ipErr := netip.ParseAddr(target)
+		switch {
+		case target == "", target == "#", ipErr == nil && ip.IsUnspecified():
+			for _, dom := range domains {
+				d.rules = append(d.rules, "||"+dom+"^")
+			}
+		case ipErr == nil:
+			// dnsmasq also answers the queries for the subdomains.
+			for _, dom := range domains {
+				d.rewrites = append(d.rewrites, &filtering.LegacyRewrite{
+					Domain:  dom,
+					Answer:  ip.String(),
+					Enabled: true,
+				}, &filtering.LegacyRewrite{
+					Domain:  "*." + dom,
+					Answer:  ip.String(),
+					Enabled: true,
+				})
+			}
+		default:
+			d.warnf("address %q: %s", val, ipErr)
+		}
+	}
+}
+
+// parseDnsmasqServers adds the upstream servers from the "server" options of
+// dnsmasq, if any.
+func (d *importData) parseDnsmasqServers(data []byte) {
+	for val := range dnsmasqOptions(data, "server") {
+		u, err := dnsmasqUpstream(val)
+		if err != nil {
+			d.warnf("server %q: %s", val, err)
+
+			continue
+		}
+
+		d.upstreams = append(d.upstreams, u)
+	}
+}
+
+// dnsmasqUpstream returns the upstream configuration line of AdGuard Home for
+// the value of the "server" option of dnsmasq, like "[/lan/]192.168.1.1:53"
+// for "/lan/192.168.1.1".
+func dnsmasqUpstream(val string) (upstream string, err error) {
+	var prefix string
+	target := val
+	if strings.HasPrefix(val, "/") {
+		var domains []string
+		domains, target, err = splitDnsmasqDomains(val)
+		if err != nil {
+			// Don't wrap the error since it's informative enough as is.
+			return "", err
+		}
+
+		prefix = "[/" + strings.Join(domains, "/") + "/]"
+	}
+
+	switch target {
+	case "":
+		return "", errDnsmasqLocal
+	case "#":
+		if prefix != "" {
+			// Both dnsmasq and AdGuard Home use "#" for the default upstreams.
+			return prefix + "#", nil
+		}
+	}
+
+	// Remove the source address or the interface, like in "1.2.3.4@eth0".
+	addr, _, _ := strings.Cut(target, "@")
+	host, portStr, hasPort := strings.Cut(addr, "#")
+
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return "", fmt.Errorf("server address: %w", err)
+	}
+
+	port := defaultDnsmasqPort
+	if hasPort {
+		var p uint64
+		p, err = strconv.ParseUint(portStr, 10, 16)
+		if err != nil {
+			return "", fmt.Errorf("server port: %w", err)
+		}
+
+		port = uint16(p)
+	}
+
+	return prefix + netip.AddrPortFrom(ip, port).String(), nil
+}
+
+// splitDnsmasqDomains splits the value of a dnsmasq option in the format
+// "/domain1/domain2/target" into the domains and the target, which may be
+// empty.
+func splitDnsmasqDomains(val string) (domains []string, target string, err error) {
+	rest, ok := strings.CutPrefix(val, "/")
+	i := strings.LastIndexByte(rest, '/')
+	if !ok || i <= 0 {
+		return nil, "", errDnsmasqNoDomains
+	}
+
+	for _, dom := range strings.Split(rest[:i], "/") {
+		// dnsmasq treats the leading dot as optional.
+		dom = strings.TrimPrefix(dom, ".")
+		err = netutil.ValidateHostname(dom)
+		if err != nil {
+			// Don't wrap the error since it's informative enough as is.
+			return nil, "", err
+		}
+
+		domains = append(domains, dom)
+	}
+
+	return domains, rest[i+1:], nil
+}
+
+// parseDnsmasqCNAMEs adds the DNS rewrites from the "cname" options of dnsmasq,
+// if any.
+func (d *importData) parseDnsmasqCNAMEs(data []byte) {
+	for val := range dnsmasqOptions(data, "cname") {
+		hosts := strings.Split(val, ",")
+
+		// The last value may be the TTL.
+		if _, err := strconv.ParseUint(hosts[len(hosts)-1], 10, 32); err == nil {
+			hosts = hosts[:len(hosts)-1]
+		}
+
+		if len(hosts) < 2 {
+			d.warnf("cname record %q: bad format", val)
+
+			continue
+		}
+
+		target := hosts[len(hosts)-1]
+		for _, alias := range hosts[:len(hosts)-1] {
+			d.rewrites = append(d.rewrites, &filtering.LegacyRewrite{
+				Domain:  alias,
+				Answer:  target,
+				Enabled: true,
+			})
+		}
+	}
+}
+
+// parseDnsmasqDHCPHosts adds the static leases from the "dhcp-host" options
+// of dnsmasq, if any.
+func (d *importData) parseDnsmasqDHCPHosts(data []byte) {
+	for val := range dnsmasqOptions(data, "dhcp-host") {
+		lease := parseDHCPHost(val)
+		if lease == nil {
+			d.warnf("static lease %q: mac and ip addresses are required", val)
+
+			continue
+		}
+
+		d.leases = append(d.leases, lease)
+	}
+}
+
+// parseDHCPHost returns the static lease from the value of the "dhcp-host"
+// option of dnsmasq or nil, if it doesn't contain the MAC and the IP address.
+// The other parameters, except for the hostname, are ignored.
+func parseDHCPHost(val string) (lease *dhcpsvc.Lease) {
+	lease = &dhcpsvc.Lease{
+		IsStatic: true,
+	}
+
+	for _, p := range strings.Split(val, ",") {
+		p = strings.TrimSpace(p)
+		if mac, err := net.ParseMAC(p); err == nil && lease.HWAddr == nil {
+			lease.HWAddr = mac
+		} else if ip, ipErr := netip.ParseAddr(strings.Trim(p, "[]")); ipErr == nil {
+			lease.IP = cmp.Or(lease.IP, ip)
+		} else if isDHCPHostName(p) && lease.Hostname == "" {
+			lease.Hostname = p
+		}
+	}
+
+	if lease.HWAddr == nil || !lease.IP.IsValid() {
+		return nil
+	}
+
+	return lease
+}
+
+// dhcpLeaseTimeRe matches the lease times in the "dhcp-host" options of
+// dnsmasq, like "infinite", "45m", or "3600".
+var dhcpLeaseTimeRe = regexp.MustCompile(`^(infinite|\d+[smhdw]?)$`)
+
+// isDHCPHostName returns true if p is the hostname parameter of a "dhcp-host"
+// option of dnsmasq rather than a lease time or a keyword, like "ignore".
+func isDHCPHostName(p string) (ok bool) {
+	return p != "ignore" && !dhcpLeaseTimeRe.MatchString(p) && netutil.ValidateHostname(p) == nil
+}
+
+// dnsmasqOptions returns the values of the options with name in the dnsmasq
+// configuration data, like "example.com" for "address=example.com".  The
+// options without values, like "domain-needed", yield empty strings.  The
+// comments and the empty lines are skipped.
+func dnsmasqOptions(data []byte, name string) (vals iter.Seq[string]) {
+	return func(yield func(val string) (cont bool)) {
+		for line := range bytes.Lines(data) {
+			line = bytes.TrimSpace(line)
+			if len(line) == 0 || line[0] == '#' {
+				continue
+			}
+
+			optName, val, _ := strings.Cut(string(line), "=")
+			if strings.TrimSpace(optName) == name && !yield(strings.TrimSpace(val)) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package home
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/filtering"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDnsmasqConf(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`# Router configuration
+domain-needed
+address=/nas.lan/192.168.1.10
+address=/ads.example/tracker.example/
+address=/.null.example/#
+address=/bad.example/not-an-ip
+server=1.1.1.1
+server=/lan/192.168.1.1#5353@eth0
+server=/local.example/
+cname=media.lan,nas.lan
+dhcp-host=aa:bb:cc:dd:ee:ff,192.168.1.20,printer,infinite
+conf-dir=/etc/dnsmasq.d
+`)
+
+	d := parseDnsmasqConf(data)
+
+	assert.Equal(t, []string{
+		"||ads.example^",
+		"||tracker.example^",
+		"||null.example^",
+	}, d.rules)
+	assert.Equal(t, []string{
+		"1.1.1.1:53",
+		"[/lan/]192.168.1.1:5353",
+	}, d.upstreams)
+
+	require.Len(t, d.rewrites, 3)
+	assert.Equal(t, &filtering.LegacyRewrite{
+		Domain:  "*.nas.lan",
+		Answer:  "192.168.1.10",
+		Enabled: true,
+	}, d.rewrites[1])
+	assert.Equal(t, "media.lan", d.rewrites[2].Domain)
+
+	require.Len(t, d.leases, 1)
+	assert.Equal(t, "printer", d.leases[0].Hostname)
+
+	assert.Len(t, d.warnings, 3)
+}
+
+func TestDnsmasqUpstream(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		val        string
+		want       string
+		wantErrMsg string
+	}{{
+		name:       "plain",
+		val:        "9.9.9.9",
+		want:       "9.9.9.9:53",
+		wantErrMsg: "",
+	}, {
+		name:       "ipv6_port",
+		val:        "2001:db8::1#5353",
+		want:       "[2001:db8::1]:5353",
+		wantErrMsg: "",
+	}, {
+		name:       "domains",
+		val:        "/lan/home.arpa/192.168.1.1",
+		want:       "[/lan/home.arpa/]192.168.1.1:53",
+		wantErrMsg: "",
+	}, {
+		name:       "default",
+		val:        "/example.org/#",
+		want:       "[/example.org/]#",
+		wantErrMsg: "",
+	}, {
+		name:       "local",
+		val:        "/lan/",
+		want:       "",
+		wantErrMsg: "local-only domains are not supported",
+	}, {
+		name:       "no_domains",
+		val:        "//192.168.1.1",
+		want:       "",
+		wantErrMsg: "no domains",
+	}, {
+		name: "bad_port",
+		val:  "192.168.1.1#port",
+		want: "",
+		wantErrMsg: `server port: strconv.ParseUint: parsing "port": ` +
+			`invalid syntax`,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			u, err := dnsmasqUpstream(tc.val)
+			testutil.AssertErrorMsg(t, tc.wantErrMsg, err)
+			assert.Equal(t, tc.want, u)
+		})
+	}
+}
+
+func TestDNSMasqOptions(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("# comment\naddress=/example.com/\n\n  address = /example.org/0.0.0.0\n" +
+		"server=1.1.1.1\naddress\n")
+
+	assert.Equal(t, []string{
+		"/example.com/",
+		"/example.org/0.0.0.0",
+		"",
+	}, slices.Collect(dnsmasqOptions(data, "address")))
+}
@@ -0,0 +1,240 @@
+package home
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/dhcpd"
+	"github.com/AdguardTeam/AdGuardHome/internal/dhcpsvc"
+	"github.com/AdguardTeam/AdGuardHome/internal/dnsforward"
+	"github.com/AdguardTeam/AdGuardHome/internal/filtering"
+	"github.com/AdguardTeam/golibs/container"
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// importedRulesHeader is the comment added to the user rules before the rules
+// imported from another DNS server.
+const importedRulesHeader = "! Imported from %s"
+
+// importData is the configuration imported from another DNS server.
+type importData struct {
+	// filters are the imported blocklists.
+	filters []filtering.FilterYAML
+
+	// rules are the imported user rules.
+	rules []string
+
+	// rewrites are the imported DNS rewrites.
+	rewrites []*filtering.LegacyRewrite
+
+	// upstreams are the imported upstream configuration lines.
+	upstreams []string
+
+	// leases are the imported static DHCP leases.
+	leases []*dhcpsvc.Lease
+
+	// warnings are the descriptions of the entries, which couldn't be
+	// imported.
+	warnings []string
+}
+
+// warnf adds a formatted warning to d.
+func (d *importData) warnf(format string, args ...any) {
+	d.warnings = append(d.warnings, fmt.Sprintf(format, args...))
+}
+
+// importResp is the response to the HTTP APIs importing the configuration.
+type importResp struct {
+	// Warnings are the descriptions of the entries, which weren't imported.
+	Warnings []string `json:"warnings"`
+
+	// Filters is the number of the added blocklists.
+	Filters int `json:"filters_added"`
+
+	// Rules is the number of the added user rules.
+	Rules int `json:"rules_added"`
+
+	// Rewrites is the number of the added DNS rewrites.
+	Rewrites int `json:"rewrites_added"`
+
+	// Upstreams is the number of the added upstream configuration lines.
+	Upstreams int `json:"upstreams_added"`
+
+	// Leases is the number of the added static DHCP leases.
+	Leases int `json:"leases_added"`
+}
+
+// applyImport adds the entries of d, which AdGuard Home doesn't have yet, to
+// the filtering, the upstreams, and the static leases.  source is the
+// human-readable name of the DNS server d was imported from.  d must not be
+// nil.
+func (web *webAPI) applyImport(
+	ctx context.Context,
+	source string,
+	d *importData,
+) (resp *importResp, err error) {
+	resp = &importResp{
+		Warnings: slices.Clone(d.warnings),
+	}
+
+	flt := globalContext.filters
+	c := &filtering.Config{}
+	flt.WriteDiskConfig(c)
+
+	resp.Filters, resp.Rules, resp.Rewrites = mergeImport(c, source, d)
+	if resp.Filters+resp.Rules+resp.Rewrites > 0 {
+		err = flt.ApplyConfig(ctx, c)
+		if err != nil {
+			return nil, fmt.Errorf("applying filtering: %w", err)
+		}
+
+		web.confModifier.Apply(ctx)
+	}
+
+	resp.Upstreams, err = addImportedUpstreams(ctx, globalContext.dnsServer, d.upstreams, resp)
+	if err != nil {
+		return nil, fmt.Errorf("adding upstreams: %w", err)
+	}
+
+	resp.Leases = addImportedLeases(globalContext.dhcpServer, d.leases, resp)
+
+	web.logger.InfoContext(
+		ctx,
+		"imported config",
+		"source", source,
+		"filters", resp.Filters,
+		"rules", resp.Rules,
+		"rewrites", resp.Rewrites,
+		"upstreams", resp.Upstreams,
+		"leases", resp.Leases,
+		"warnings", len(resp.Warnings),
+	)
+
+	return resp, nil
+}
+
+// mergeImport adds the filters, the rules, and the rewrites of d, which c
+// doesn't have yet, to c and returns their numbers.  c and d must not be nil.
+func mergeImport(c *filtering.Config, source string, d *importData) (filters, rules, rewrites int) {
+	urls := container.NewMapSet[string]()
+	for _, f := range slices.Concat(c.Filters, c.WhitelistFilters) {
+		urls.Add(f.URL)
+	}
+
+	for _, f := range d.filters {
+		if urls.Has(f.URL) {
+			continue
+		}
+
+		urls.Add(f.URL)
+		c.Filters = append(c.Filters, f)
+		filters++
+	}
+
+	existing := container.NewMapSet(c.UserRules...)
+	var added []string
+	for _, r := range d.rules {
+		if !existing.Has(r) {
+			existing.Add(r)
+			added = append(added, r)
+		}
+	}
+
+	if len(added) > 0 {
+		c.UserRules = append(c.UserRules, fmt.Sprintf(importedRulesHeader, source))
+		c.UserRules = append(c.UserRules, added...)
+		rules = len(added)
+	}
+
+	for _, rw := range d.rewrites {
+		if !hasRewrite(c.Rewrites, rw) {
+			c.Rewrites = append(c.Rewrites, rw)
+			rewrites++
+		}
+	}
+
+	return filters, rules, rewrites
+}
+
+// hasRewrite returns true if rws contain a rewrite with the same domain and
+// answer as rw.
+func hasRewrite(rws []*filtering.LegacyRewrite, rw *filtering.LegacyRewrite) (ok bool) {
+	return slices.ContainsFunc(rws, func(other *filtering.LegacyRewrite) (eq bool) {
+		return strings.EqualFold(other.Domain, rw.Domain) && other.Answer == rw.Answer
+	})
+}
+
+// addImportedUpstreams adds upstreams to dnsSrv, which may be nil, and returns
+// the number of the added ones.  If dnsSrv is nil or reads the upstreams from
+// a file, the upstreams are reported in the warnings of resp.
+func addImportedUpstreams(
+	ctx context.Context,
+	dnsSrv *dnsforward.Server,
+	upstreams []string,
+	resp *importResp,
+) (added int, err error) {
+	if len(upstreams) == 0 {
+		return 0, nil
+	} else if dnsSrv == nil {
+		resp.Warnings = append(resp.Warnings, fmt.Sprintf(
+			"dns server is unavailable; skipped %d upstreams",
+			len(upstreams),
+		))
+
+		return 0, nil
+	}
+
+	added, err = dnsSrv.AddUpstreams(ctx, upstreams)
+	if errors.Is(err, dnsforward.ErrUpstreamsFile) {
+		resp.Warnings = append(resp.Warnings, fmt.Sprintf(
+			"%s; skipped %d upstreams",
+			err,
+			len(upstreams),
+		))
+
+		return 0, nil
+	}
+
+	// Don't wrap the error since it's informative enough as is.
+	return added, err
+}
+
+// addImportedLeases adds leases to dhcpSrv, which may be nil, and returns the
+// number of the added ones.  The leases, which couldn't be added, are reported
+// in the warnings of resp.
+func addImportedLeases(
+	dhcpSrv dhcpd.Interface,
+	leases []*dhcpsvc.Lease,
+	resp *importResp,
+) (added int) {
+	if len(leases) == 0 {
+		return 0
+	} else if dhcpSrv == nil {
+		resp.Warnings = append(resp.Warnings, fmt.Sprintf(
+			"dhcp server is unavailable; skipped %d static leases",
+			len(leases),
+		))
+
+		return 0
+	}
+
+	for _, lease := range leases {
+		err := dhcpSrv.AddStaticLease(lease)
+		if err != nil {
+			resp.Warnings = append(resp.Warnings, fmt.Sprintf(
+				"static lease %s for %s: %s",
+				lease.IP,
+				lease.HWAddr,
+				err,
+			))
+
+			continue
+		}
+
+		added++
+	}
+
+	return added
+}
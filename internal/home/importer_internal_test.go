@@ -0,0 +1,63 @@
+package home
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/filtering"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeImport(t *testing.T) {
+	t.Parallel()
+
+	c := &filtering.Config{
+		Filters: []filtering.FilterYAML{{
+			URL: "https://example.com/block.txt",
+		}},
+		WhitelistFilters: []filtering.FilterYAML{{
+			URL: "https://example.com/allow.txt",
+		}},
+		UserRules: []string{"||existing.example^"},
+		Rewrites: []*filtering.LegacyRewrite{{
+			Domain: "NAS.lan",
+			Answer: "192.168.1.10",
+		}},
+	}
+
+	d := &importData{
+		filters: []filtering.FilterYAML{{
+			URL: "https://example.com/block.txt",
+		}, {
+			URL: "https://example.com/allow.txt",
+		}, {
+			URL: "https://example.com/new.txt",
+		}},
+		rules: []string{"||existing.example^", "||new.example^", "||new.example^"},
+		rewrites: []*filtering.LegacyRewrite{{
+			Domain: "nas.lan",
+			Answer: "192.168.1.10",
+		}, {
+			Domain: "nas.lan",
+			Answer: "192.168.1.11",
+		}},
+	}
+
+	filters, rules, rewrites := mergeImport(c, "Test", d)
+	assert.Equal(t, 1, filters)
+	assert.Equal(t, 1, rules)
+	assert.Equal(t, 1, rewrites)
+
+	assert.True(t, slices.ContainsFunc(c.Filters, func(f filtering.FilterYAML) (ok bool) {
+		return f.URL == "https://example.com/new.txt"
+	}))
+	assert.Equal(t, []string{
+		"||existing.example^",
+		"! Imported from Test",
+		"||new.example^",
+	}, c.UserRules)
+	assert.Len(t, c.Rewrites, 2)
+
+	filters, rules, rewrites = mergeImport(c, "Test", d)
+	assert.Zero(t, filters+rules+rewrites)
+}
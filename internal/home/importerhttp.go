@@ -0,0 +1,115 @@
+package home
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+)
+
+// importArchiveReq is the request to the HTTP APIs importing the configuration
+// from an archive.
+type importArchiveReq struct {
+	// Archive is the contents of the archive.  It's base64-encoded in JSON.
+	Archive []byte `json:"archive"`
+}
+
+// importConfigReq is the request to the HTTP APIs importing the configuration
+// from a text file.
+type importConfigReq struct {
+	// Config is the contents of the configuration file.
+	Config string `json:"config"`
+}
+
+// registerImportHandlers registers the HTTP handlers of the import of the
+// configuration from other DNS servers.
+func (web *webAPI) registerImportHandlers() {
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodPost,
+		"/control/import/pihole",
+		web.handleImportPihole,
+		&aghhttp.RouteInfo{
+			Summary: "Import the configuration from a Pi-hole Teleporter archive",
+			Description: "Adds the adlists, the allowed and the denied domains, " +
+				"the local DNS records, and the static DHCP leases, which " +
+				"AdGuard Home doesn't have yet.  The entries, which can't be " +
+				"imported, are reported in the warnings.",
+			Request:  importArchiveReq{},
+			Response: importResp{},
+		},
+	)
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodPost,
+		"/control/import/dnsmasq",
+		web.handleImportDnsmasq,
+		&aghhttp.RouteInfo{
+			Summary: "Import the configuration from a dnsmasq configuration file",
+			Description: "Adds the addresses as the DNS rewrites or the blocking " +
+				"rules, the servers as the upstreams, the CNAME records, and " +
+				"the static DHCP leases, which AdGuard Home doesn't have yet.  " +
+				"The options, which can't be imported, are reported in the " +
+				"warnings.",
+			Request:  importConfigReq{},
+			Response: importResp{},
+		},
+	)
+}
+
+// handleImportPihole is the handler for the POST /control/import/pihole HTTP
+// API.
+func (web *webAPI) handleImportPihole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	l := web.logger
+
+	req := &importArchiveReq{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusBadRequest, "parsing request: %s", err)
+
+		return
+	}
+
+	d, err := parsePiholeTeleporter(bytes.NewReader(req.Archive))
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusBadRequest, "archive: %s", err)
+
+		return
+	}
+
+	resp, err := web.applyImport(ctx, piholeSource, d)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusInternalServerError, "%s", err)
+
+		return
+	}
+
+	aghhttp.WriteJSONResponseOK(ctx, l, w, r, resp)
+}
+
+// handleImportDnsmasq is the handler for the POST /control/import/dnsmasq HTTP
+// API.
+func (web *webAPI) handleImportDnsmasq(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	l := web.logger
+
+	req := &importConfigReq{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusBadRequest, "parsing request: %s", err)
+
+		return
+	}
+
+	d := parseDnsmasqConf([]byte(req.Config))
+	resp, err := web.applyImport(ctx, dnsmasqSource, d)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusInternalServerError, "%s", err)
+
+		return
+	}
+
+	aghhttp.WriteJSONResponseOK(ctx, l, w, r, resp)
+}
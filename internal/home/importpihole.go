@@ -0,0 +1,298 @@
+package home
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/netip"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/filtering"
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/ioutil"
+	"github.com/AdguardTeam/golibs/netutil"
+	"github.com/AdguardTeam/golibs/netutil/urlutil"
+)
+
+// piholeSource is the name of Pi-hole used in the imported configuration.
+const piholeSource = "Pi-hole"
+
+// maxPiholeFileSize is the maximum size of a file within a Pi-hole Teleporter
+// archive.
+const maxPiholeFileSize = 16 << 20
+
+// The names of the files within a Pi-hole Teleporter archive, which are
+// imported.
+const (
+	piholeFileAdlist         = "adlist.json"
+	piholeFileBlacklist      = "blacklist.exact.json"
+	piholeFileBlacklistRegex = "blacklist.regex.json"
+	piholeFileWhitelist      = "whitelist.exact.json"
+	piholeFileWhitelistRegex = "whitelist.regex.json"
+	piholeFileCustomList     = "custom.list"
+	piholeFileCNAME          = "05-pihole-custom-cname.conf"
+	piholeFileStaticDHCP     = "04-pihole-static-dhcp.conf"
+)
+
+// errPiholeZip is returned when a Teleporter archive of Pi-hole v6 is
+// imported.
+const errPiholeZip errors.Error = "pi-hole v6 teleporter archives are not supported; " +
+	"use the archive exported by pi-hole v5"
+
+// piholeBool is a boolean value in the Teleporter archive, which is either a
+// JSON boolean or a number, as stored in the database of Pi-hole.
+type piholeBool bool
+
+// type check
+var _ json.Unmarshaler = (*piholeBool)(nil)
+
+// UnmarshalJSON implements the [json.Unmarshaler] interface for *piholeBool.
+func (b *piholeBool) UnmarshalJSON(data []byte) (err error) {
+	switch string(data) {
+	case "1", "true":
+		*b = true
+	case "0", "false", "null":
+		*b = false
+	default:
+		return fmt.Errorf("enabled: %w: %s", errors.ErrBadEnumValue, data)
+	}
+
+	return nil
+}
+
+// piholeAdlist is an entry of the adlist table of Pi-hole.
+type piholeAdlist struct {
+	Address string     `json:"address"`
+	Comment string     `json:"comment"`
+	Enabled piholeBool `json:"enabled"`
+}
+
+// piholeDomain is an entry of the domainlist table of Pi-hole.
+type piholeDomain struct {
+	Domain  string     `json:"domain"`
+	Enabled piholeBool `json:"enabled"`
+}
+
+// parsePiholeTeleporter returns the configuration imported from the Pi-hole
+// Teleporter archive read from r.  The entries, which can't be imported, are
+// reported in the warnings of d.
+func parsePiholeTeleporter(r io.Reader) (d *importData, err error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(2)
+	if err != nil {
+		return nil, fmt.Errorf("reading archive: %w", err)
+	} else if string(magic) == "PK" {
+		return nil, errPiholeZip
+	}
+
+	files, err := readPiholeFiles(br)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return nil, err
+	}
+
+	d = &importData{}
+
+	err = d.parsePiholeAdlists(files[piholeFileAdlist])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", piholeFileAdlist, err)
+	}
+
+	for _, lf := range []struct {
+		name   string
+		prefix string
+		regex  bool
+	}{{
+		name:   piholeFileBlacklist,
+		prefix: "",
+		regex:  false,
+	}, {
+		name:   piholeFileBlacklistRegex,
+		prefix: "",
+		regex:  true,
+	}, {
+		name:   piholeFileWhitelist,
+		prefix: "@@",
+		regex:  false,
+	}, {
+		name:   piholeFileWhitelistRegex,
+		prefix: "@@",
+		regex:  true,
+	}} {
+		err = d.parsePiholeDomains(files[lf.name], lf.prefix, lf.regex)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", lf.name, err)
+		}
+	}
+
+	d.parsePiholeCustomList(files[piholeFileCustomList])
+	d.parseDnsmasqCNAMEs(files[piholeFileCNAME])
+	d.parseDnsmasqDHCPHosts(files[piholeFileStaticDHCP])
+
+	return d, nil
+}
+
+// readPiholeFiles returns the contents of the imported files from the
+// gzip-compressed tar archive read from r by their base names.
+func readPiholeFiles(r io.Reader) (files map[string][]byte, err error) {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("decompressing archive: %w", err)
+	}
+	defer func() { err = errors.WithDeferred(err, gzr.Close()) }()
+
+	files = map[string][]byte{}
+	tr := tar.NewReader(gzr)
+	for {
+		var hdr *tar.Header
+		hdr, err = tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("reading archive: %w", err)
+		}
+
+		name := path.Base(hdr.Name)
+		if hdr.Typeflag != tar.TypeReg || !isPiholeFile(name) {
+			continue
+		}
+
+		var data []byte
+		data, err = io.ReadAll(ioutil.LimitReader(tr, maxPiholeFileSize))
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", name, err)
+		}
+
+		files[name] = data
+	}
+}
+
+// isPiholeFile returns true if name is the base name of an imported file.
+func isPiholeFile(name string) (ok bool) {
+	switch name {
+	case
+		piholeFileAdlist,
+		piholeFileBlacklist,
+		piholeFileBlacklistRegex,
+		piholeFileWhitelist,
+		piholeFileWhitelistRegex,
+		piholeFileCustomList,
+		piholeFileCNAME,
+		piholeFileStaticDHCP:
+		return true
+	default:
+		return false
+	}
+}
+
+// parsePiholeAdlists adds the blocklists from the adlist.json data, if any.
+func (d *importData) parsePiholeAdlists(data []byte) (err error) {
+	if len(data) == 0 {
+		return nil
+	}
+
+	var lists []*piholeAdlist
+	err = json.Unmarshal(data, &lists)
+	if err != nil {
+		return fmt.Errorf("decoding: %w", err)
+	}
+
+	for _, l := range lists {
+		u, parseErr := url.Parse(l.Address)
+		if parseErr != nil || (u.Scheme != urlutil.SchemeHTTP && u.Scheme != urlutil.SchemeHTTPS) {
+			d.warnf("adlist %q: only http and https urls are supported", l.Address)
+
+			continue
+		}
+
+		d.filters = append(d.filters, filtering.FilterYAML{
+			Enabled: bool(l.Enabled),
+			URL:     l.Address,
+			Name:    cmp.Or(strings.TrimSpace(l.Comment), u.Host),
+		})
+	}
+
+	return nil
+}
+
+// parsePiholeDomains adds the user rules from the data of a domain list, if
+// any.  prefix is added to each rule, and regex defines if the domains are
+// regular expressions.  The disabled entries are added commented out.
+func (d *importData) parsePiholeDomains(data []byte, prefix string, regex bool) (err error) {
+	if len(data) == 0 {
+		return nil
+	}
+
+	var domains []*piholeDomain
+	err = json.Unmarshal(data, &domains)
+	if err != nil {
+		return fmt.Errorf("decoding: %w", err)
+	}
+
+	for _, dom := range domains {
+		var rule string
+		if regex {
+			// Pi-hole extends the regular expressions with the options after
+			// a semicolon, such as ";querytype=A", which AdGuard Home doesn't
+			// support.
+			if strings.Contains(dom.Domain, ";") {
+				d.warnf("regex %q: pi-hole regex extensions are not supported", dom.Domain)
+
+				continue
+			}
+
+			rule = prefix + "/" + dom.Domain + "/"
+		} else {
+			err = netutil.ValidateHostname(dom.Domain)
+			if err != nil {
+				d.warnf("domain %q: %s", dom.Domain, err)
+
+				continue
+			}
+
+			// Pi-hole exact entries don't match the subdomains.
+			rule = prefix + "|" + dom.Domain + "^"
+		}
+
+		if !dom.Enabled {
+			rule = "! " + rule
+		}
+
+		d.rules = append(d.rules, rule)
+	}
+
+	return nil
+}
+
+// parsePiholeCustomList adds the DNS rewrites from the local DNS records in
+// the hosts file format, if any.
+func (d *importData) parsePiholeCustomList(data []byte) {
+	for line := range bytes.Lines(data) {
+		fields := strings.Fields(string(line))
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		ip, err := netip.ParseAddr(fields[0])
+		if err != nil || len(fields) < 2 {
+			d.warnf("local dns record %q: bad format", bytes.TrimSpace(line))
+
+			continue
+		}
+
+		for _, host := range fields[1:] {
+			d.rewrites = append(d.rewrites, &filtering.LegacyRewrite{
+				Domain:  host,
+				Answer:  ip.String(),
+				Enabled: true,
+			})
+		}
+	}
+}
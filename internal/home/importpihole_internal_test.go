@@ -0,0 +1,165 @@
+package home
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/dhcpsvc"
+	"github.com/AdguardTeam/AdGuardHome/internal/filtering"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTeleporter returns a gzip-compressed tar archive with files.
+func newTestTeleporter(tb testing.TB, files map[string]string) (archive []byte) {
+	tb.Helper()
+
+	buf := &bytes.Buffer{}
+	gzw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gzw)
+
+	for name, data := range files {
+		err := tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0o644,
+			Size:     int64(len(data)),
+			Typeflag: tar.TypeReg,
+		})
+		require.NoError(tb, err)
+
+		_, err = tw.Write([]byte(data))
+		require.NoError(tb, err)
+	}
+
+	require.NoError(tb, tw.Close())
+	require.NoError(tb, gzw.Close())
+
+	return buf.Bytes()
+}
+
+func TestParsePiholeTeleporter(t *testing.T) {
+	t.Parallel()
+
+	const (
+		customList = "# Local records\n192.168.1.10 nas.lan storage.lan\nbad\n"
+		cnameConf  = "cname=media.lan,nas.lan\ncname=a.lan,b.lan,nas.lan,300\n"
+		dhcpConf   = "dhcp-host=aa:bb:cc:dd:ee:ff,192.168.1.20,printer\n" +
+			"dhcp-host=192.168.1.21,phone\n"
+	)
+
+	archive := newTestTeleporter(t, map[string]string{
+		"adlist.json": `[
+			{"address":"https://example.com/hosts.txt","enabled":1,"comment":"Example"},
+			{"address":"https://example.org/list.txt","enabled":0,"comment":""},
+			{"address":"file:///etc/pihole/local.list","enabled":1}
+		]`,
+		"blacklist.exact.json": `[
+			{"domain":"blocked.example","enabled":1},
+			{"domain":"bad domain","enabled":1}
+		]`,
+		"blacklist.regex.json": `[
+			{"domain":"^ads[0-9]+\\.","enabled":1},
+			{"domain":"^tracker;querytype=A","enabled":1}
+		]`,
+		"whitelist.exact.json":                  `[{"domain":"allowed.example","enabled":0}]`,
+		"whitelist.regex.json":                  `[]`,
+		"custom.list":                           customList,
+		"unknown.json":                          `{}`,
+		"dnsmasq.d/05-pihole-custom-cname.conf": cnameConf,
+		"dnsmasq.d/04-pihole-static-dhcp.conf":  dhcpConf,
+	})
+
+	d, err := parsePiholeTeleporter(bytes.NewReader(archive))
+	require.NoError(t, err)
+
+	assert.Equal(t, []filtering.FilterYAML{{
+		Enabled: true,
+		URL:     "https://example.com/hosts.txt",
+		Name:    "Example",
+	}, {
+		Enabled: false,
+		URL:     "https://example.org/list.txt",
+		Name:    "example.org",
+	}}, d.filters)
+
+	assert.Equal(t, []string{
+		"|blocked.example^",
+		`/^ads[0-9]+\./`,
+		"! @@|allowed.example^",
+	}, d.rules)
+
+	assert.Equal(t, []*filtering.LegacyRewrite{{
+		Domain:  "nas.lan",
+		Answer:  "192.168.1.10",
+		Enabled: true,
+	}, {
+		Domain:  "storage.lan",
+		Answer:  "192.168.1.10",
+		Enabled: true,
+	}, {
+		Domain:  "media.lan",
+		Answer:  "nas.lan",
+		Enabled: true,
+	}, {
+		Domain:  "a.lan",
+		Answer:  "nas.lan",
+		Enabled: true,
+	}, {
+		Domain:  "b.lan",
+		Answer:  "nas.lan",
+		Enabled: true,
+	}}, d.rewrites)
+
+	assert.Equal(t, []*dhcpsvc.Lease{{
+		IP:       netip.MustParseAddr("192.168.1.20"),
+		Hostname: "printer",
+		HWAddr:   net.HardwareAddr{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff},
+		IsStatic: true,
+	}}, d.leases)
+
+	assert.Len(t, d.warnings, 5)
+}
+
+func TestParsePiholeTeleporter_errors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		archive    []byte
+		wantErrMsg string
+	}{{
+		name:       "zip",
+		archive:    []byte("PK\x03\x04"),
+		wantErrMsg: errPiholeZip.Error(),
+	}, {
+		name:       "not_gzip",
+		archive:    []byte("not an archive"),
+		wantErrMsg: "decompressing archive: gzip: invalid header",
+	}, {
+		name: "bad_json",
+		archive: newTestTeleporter(t, map[string]string{
+			"adlist.json": `{`,
+		}),
+		wantErrMsg: "adlist.json: decoding: unexpected end of JSON input",
+	}, {
+		name: "bad_enabled",
+		archive: newTestTeleporter(t, map[string]string{
+			"blacklist.exact.json": `[{"domain":"example.com","enabled":"yes"}]`,
+		}),
+		wantErrMsg: `blacklist.exact.json: decoding: enabled: bad enum value: "yes"`,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := parsePiholeTeleporter(bytes.NewReader(tc.archive))
+			testutil.AssertErrorMsg(t, tc.wantErrMsg, err)
+		})
+	}
+}
@@ -4,14 +4,16 @@ import (
 	"cmp"
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
+	"os"
 	"path/filepath"
 	"runtime"
 
 	"github.com/AdguardTeam/AdGuardHome/internal/aghos"
+	"github.com/AdguardTeam/golibs/errors"
 	"github.com/AdguardTeam/golibs/log"
 	"github.com/AdguardTeam/golibs/logutil/slogutil"
-	yaml "go.yaml.in/yaml/v4"
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
@@ -31,11 +33,14 @@ func newSlogLogger(ls *logSettings) (l *slog.Logger) {
 		lvl = slog.LevelDebug
 	}
 
-	l = slogutil.New(&slogutil.Config{
-		Format:       slogutil.FormatAdGuardLegacy,
-		Level:        lvl,
-		AddTimestamp: true,
-	})
+	l = newSystemLogger(ls, lvl)
+	if l == nil {
+		l = slogutil.New(&slogutil.Config{
+			Format:       slogutil.FormatAdGuardLegacy,
+			Level:        lvl,
+			AddTimestamp: true,
+		})
+	}
 
 	// Configure logger level.
 	if !ls.Enabled {
@@ -47,12 +52,41 @@ func newSlogLogger(ls *logSettings) (l *slog.Logger) {
 	return l
 }
 
+// newSystemLogger returns a logger writing the structured entries to the Event
+// Log, if it's configured by ls and available, or nil otherwise.  Other system
+// logs are written by the legacy logger, see [configureLogger].  ls must not be
+// nil.
+func newSystemLogger(ls *logSettings, lvl slog.Level) (l *slog.Logger) {
+	if ls.File != configSyslog {
+		return nil
+	}
+
+	h, err := aghos.NewEventLogHandler(serviceName, lvl)
+	if err != nil {
+		if !errors.Is(err, errors.ErrUnsupported) {
+			log.Error("opening event log: %s", err)
+		}
+
+		return nil
+	}
+
+	return slogutil.New(&slogutil.Config{
+		Format:  slogutil.FormatCustom,
+		Handler: h,
+		Level:   lvl,
+	})
+}
+
 // configureLogger configures logger output.  ls must not be nil.
 func configureLogger(ls *logSettings, workDir string) (err error) {
 	// Make sure that we see the microseconds in logs, as networking stuff can
 	// happen pretty quickly.
 	log.SetFlags(log.LstdFlags | log.Lmicroseconds)
 
+	// Keep the latest entries for the support bundles in addition to the
+	// configured output.
+	defer func() { log.SetOutput(io.MultiWriter(log.Writer(), recentLogs)) }()
+
 	// Write logs to stdout by default.
 	if ls.File == "" {
 		return nil
@@ -141,7 +175,16 @@ func readLogSettings(
 		return nil
 	}
 
-	err = yaml.Unmarshal(yamlFile, conf)
+	secrets, err := newSecretsCipher(os.LookupEnv)
+	if err == nil {
+		includeDir := filepath.Dir(configFilePath(ctx, l, workDir, confPath))
+		yamlFile, _, err = mergeConfigIncludes(yamlFile, includeDir, secrets)
+	}
+
+	if err == nil {
+		_, err = unmarshalConfig(yamlFile, conf, os.LookupEnv, secrets)
+	}
+
 	if err != nil {
 		log.Error("Couldn't get logging settings from the configuration: %s", err)
 	}
@@ -0,0 +1,105 @@
+package home
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/dhcpd"
+	"github.com/AdguardTeam/golibs/httphdr"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+)
+
+// metricDHCPLeases is the name of the metric of the numbers of the DHCP leases.
+const metricDHCPLeases = "adguardhome_dhcp_leases"
+
+// registerMetricsHandlers registers the HTTP handlers of the Prometheus
+// metrics.
+func (web *webAPI) registerMetricsHandlers() {
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodGet,
+		"/metrics",
+		web.handleGetMetrics,
+		&aghhttp.RouteInfo{
+			Summary: "Get the metrics in the Prometheus text exposition format",
+			Description: "Returns the metrics of the HTTP API, the DNS queries, " +
+				"and the DHCP leases.",
+		},
+	)
+}
+
+// routeMetrics returns the metrics of the HTTP routes or nil, if web.httpReg
+// doesn't collect them.
+func (web *webAPI) routeMetrics() (m *aghhttp.RouteMetrics) {
+	mc, ok := web.httpReg.(aghhttp.MetricsCollector)
+	if !ok {
+		return nil
+	}
+
+	return mc.Metrics()
+}
+
+// handleGetMetrics is the handler for the GET /metrics HTTP API.  It requires
+// the authentication as the rest of the API does.
+func (web *webAPI) handleGetMetrics(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	w.Header().Set(httphdr.ContentType, aghhttp.HdrValPrometheusText)
+
+	err := web.writeMetrics(w)
+	if err != nil {
+		web.logger.DebugContext(ctx, "writing metrics", slogutil.KeyError, err)
+	}
+}
+
+// writeMetrics writes all the collected metrics to w in the Prometheus text
+// exposition format.
+func (web *webAPI) writeMetrics(w io.Writer) (err error) {
+	if m := web.routeMetrics(); m != nil {
+		err = m.WritePrometheus(w)
+		if err != nil {
+			return fmt.Errorf("writing http metrics: %w", err)
+		}
+	}
+
+	if dnsSrv := globalContext.dnsServer; dnsSrv != nil {
+		err = dnsSrv.Metrics().WritePrometheus(w)
+		if err != nil {
+			return fmt.Errorf("writing dns metrics: %w", err)
+		}
+	}
+
+	if dhcpSrv := globalContext.dhcpServer; dhcpSrv != nil {
+		err = writeDHCPMetrics(w, dhcpSrv)
+		if err != nil {
+			return fmt.Errorf("writing dhcp metrics: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// writeDHCPMetrics writes the numbers of the leases of srv to w in the
+// Prometheus text exposition format.  srv must not be nil.
+func writeDHCPMetrics(w io.Writer, srv dhcpd.Interface) (err error) {
+	var dynamic, static uint
+	for _, l := range srv.Leases() {
+		if l.IsStatic {
+			static++
+		} else {
+			dynamic++
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+
+	_, _ = fmt.Fprintf(bw, "# HELP %s The number of the DHCP leases by type.\n", metricDHCPLeases)
+	_, _ = fmt.Fprintf(bw, "# TYPE %s gauge\n", metricDHCPLeases)
+	_, _ = fmt.Fprintf(bw, "%s{type=\"dynamic\"} %d\n", metricDHCPLeases, dynamic)
+	_, _ = fmt.Fprintf(bw, "%s{type=\"static\"} %d\n", metricDHCPLeases, static)
+
+	return bw.Flush()
+}
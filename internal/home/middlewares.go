@@ -46,7 +46,12 @@ func expectsLargerRequests(r *http.Request) (ok bool) {
 	}
 
 	switch r.URL.Path {
-	case "/control/access/set", "/control/filtering/set_rules":
+	case
+		"/control/access/set",
+		"/control/config/validate",
+		"/control/filtering/set_rules",
+		"/control/import/dnsmasq",
+		"/control/import/pihole":
 		return true
 	default:
 		return false
@@ -2,10 +2,10 @@ package home
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
-	"io"
+	"maps"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
@@ -19,8 +19,28 @@ const (
 	maxTelegramInterval = 24 * time.Hour
 	minTelegramCooldown = time.Minute
 	maxTelegramCooldown = 24 * time.Hour
+
+	// maxSustainedChecks is the maximum number of the consecutive checks
+	// required to fire an alert.
+	maxSustainedChecks = 100
+
+	// maxTemperatureThreshold is the maximum CPU temperature threshold in
+	// degrees Celsius.
+	maxTemperatureThreshold = 150
 )
 
+// The names of the files of the notifications manager in the data directory.
+const (
+	notificationHistoryFilename = "notifications_history.json"
+	notificationRetryFilename   = "notifications_retry.json"
+
+	notificationLifecycleFilename = "notifications_running.json"
+)
+
+// notificationShutdownTimeout is the maximum time of delivering the shutdown
+// notification.
+const notificationShutdownTimeout = 10 * time.Second
+
 type telegramConfigJSON struct {
 	Enabled         bool    `json:"enabled"`
 	BotToken        string  `json:"bot_token"`
@@ -31,12 +51,62 @@ type telegramConfigJSON struct {
 	CheckInterval   int64   `json:"check_interval"`
 	Cooldown        int64   `json:"cooldown"`
 	CustomMessage   string  `json:"custom_message"`
+
+	ParseMode string `json:"parse_mode"`
+
+	MessageThreadID int64 `json:"message_thread_id"`
+
+	Chats []telegramChatConfig `json:"chats"`
+
+	AlertButtons bool `json:"alert_buttons"`
+
+	Hysteresis map[string]float64 `json:"hysteresis"`
+
+	SustainedChecks int `json:"sustained_checks"`
+
+	Proxy string `json:"proxy"`
+
+	TemperatureThreshold float64 `json:"temperature_threshold"`
+	TemperatureCooldown  int64   `json:"temperature_cooldown"`
+
+	DiskPaths []diskPathConfig `json:"disk_paths"`
+
+	SwapThreshold float64 `json:"swap_threshold"`
 }
 
 func (web *webAPI) registerNotificationHandlers() {
 	web.httpReg.Register(http.MethodGet, "/control/notifications/telegram", web.handleGetTelegramConfig)
 	web.httpReg.Register(http.MethodPut, "/control/notifications/telegram/update", web.handlePutTelegramConfig)
-	web.httpReg.Register(http.MethodPost, "/control/notifications/telegram/test", web.handlePostTelegramTest)
+	web.httpReg.Register(http.MethodPost, "/control/notifications/telegram/test", web.newNotificationTestHandler("telegram"))
+
+	registerChannelHandlers(web, "matrix", func(n *notificationsConfig) *matrixConfig { return &n.Matrix })
+	registerChannelHandlers(web, "gotify", func(n *notificationsConfig) *gotifyConfig { return &n.Gotify })
+	registerChannelHandlers(web, "mqtt", func(n *notificationsConfig) *mqttConfig { return &n.MQTT })
+	registerChannelHandlers(web, "signal", func(n *notificationsConfig) *signalConfig { return &n.Signal })
+	registerChannelHandlers(web, "pagerduty", func(n *notificationsConfig) *pagerDutyConfig { return &n.PagerDuty })
+	registerChannelHandlers(web, "opsgenie", func(n *notificationsConfig) *opsgenieConfig { return &n.Opsgenie })
+	registerChannelHandlers(web, "teams", func(n *notificationsConfig) *teamsConfig { return &n.Teams })
+	registerChannelHandlers(web, "alertmanager", func(n *notificationsConfig) *alertmanagerConfig {
+		return &n.Alertmanager
+	})
+
+	web.registerTemplateHandlers()
+	web.registerRouteHandlers()
+	web.registerQuietHoursHandlers()
+	web.registerDigestHandlers()
+	web.registerEscalationHandlers()
+	web.registerConfigChangesHandlers()
+	web.registerDHCPEventsHandlers()
+	web.registerBlockedSpikeHandlers()
+	web.registerUpdateNotificationsHandlers()
+	web.registerStatsReportHandlers()
+	web.registerHeartbeatHandlers()
+	web.registerRateLimitHandlers()
+	web.registerBandwidthHandlers()
+	web.registerSelfMonitorHandlers()
+	web.registerDNSPerfHandlers()
+	web.registerHistoryHandlers()
+	web.registerAlertHandlers()
 }
 
 func (web *webAPI) handleGetTelegramConfig(w http.ResponseWriter, r *http.Request) {
@@ -106,35 +176,6 @@ func (web *webAPI) handlePutTelegramConfig(w http.ResponseWriter, r *http.Reques
 	aghhttp.OK(ctx, web.logger, w)
 }
 
-func (web *webAPI) handlePostTelegramTest(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	if globalContext.notifier == nil {
-		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusServiceUnavailable, "notifications manager unavailable")
-
-		return
-	}
-
-	var req struct {
-		Message string `json:"message"`
-	}
-
-	dec := json.NewDecoder(r.Body)
-	if err := dec.Decode(&req); err != nil && !errors.Is(err, io.EOF) {
-		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusBadRequest, "json decode: %s", err)
-
-		return
-	}
-
-	if err := globalContext.notifier.SendTelegramTest(ctx, req.Message); err != nil {
-		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusBadGateway, "telegram test failed: %s", err)
-
-		return
-	}
-
-	aghhttp.OK(ctx, web.logger, w)
-}
-
 func telegramConfigToJSON(cfg *telegramConfig) telegramConfigJSON {
 	if cfg == nil {
 		cfg = defaultTelegramConfig()
@@ -150,6 +191,20 @@ func telegramConfigToJSON(cfg *telegramConfig) telegramConfigJSON {
 		CheckInterval:   int64(time.Duration(cfg.CheckInterval) / time.Millisecond),
 		Cooldown:        int64(time.Duration(cfg.Cooldown) / time.Millisecond),
 		CustomMessage:   cfg.CustomMessage,
+		ParseMode:       cfg.ParseMode,
+		MessageThreadID: cfg.MessageThreadID,
+		Chats:           slices.Clone(cfg.Chats),
+		AlertButtons:    cfg.AlertButtons,
+		Hysteresis:      maps.Clone(cfg.Hysteresis),
+		SustainedChecks: cfg.SustainedChecks,
+		Proxy:           cfg.Proxy,
+
+		TemperatureThreshold: cfg.TemperatureThreshold,
+		TemperatureCooldown:  int64(time.Duration(cfg.TemperatureCooldown) / time.Millisecond),
+
+		DiskPaths: slices.Clone(cfg.DiskPaths),
+
+		SwapThreshold: cfg.SwapThreshold,
 	}
 }
 
@@ -172,12 +227,60 @@ func telegramConfigFromJSON(j *telegramConfigJSON) (*telegramConfig, error) {
 		"cpu":    j.CPUThreshold,
 		"memory": j.MemoryThreshold,
 		"disk":   j.DiskThreshold,
+		"swap":   j.SwapThreshold,
 	} {
 		if value < 0 || value > 100 {
 			return nil, fmt.Errorf("%s threshold must be between 0 and 100", key)
 		}
 	}
 
+	if j.TemperatureThreshold < 0 || j.TemperatureThreshold > maxTemperatureThreshold {
+		return nil, fmt.Errorf("temperature threshold must be between 0 and %d", maxTemperatureThreshold)
+	}
+
+	tempCooldown := time.Duration(j.TemperatureCooldown) * time.Millisecond
+	if tempCooldown != 0 && (tempCooldown < minTelegramCooldown || tempCooldown > maxTelegramCooldown) {
+		return nil, fmt.Errorf(
+			"temperature_cooldown must be zero or between %s and %s",
+			minTelegramCooldown,
+			maxTelegramCooldown,
+		)
+	}
+
+	err := notifications.ValidateParseMode(notifications.ParseMode(j.ParseMode))
+	if err != nil {
+		return nil, err
+	}
+
+	if j.MessageThreadID < 0 {
+		return nil, fmt.Errorf("message_thread_id must not be negative")
+	}
+
+	err = notifications.ValidateHysteresis(j.Hysteresis)
+	if err != nil {
+		return nil, err
+	}
+
+	if j.SustainedChecks < 0 || j.SustainedChecks > maxSustainedChecks {
+		return nil, fmt.Errorf("sustained_checks must be between 0 and %d", maxSustainedChecks)
+	}
+
+	err = normalizeTelegramChats(j.Chats)
+	if err != nil {
+		return nil, err
+	}
+
+	err = normalizeDiskPaths(j.DiskPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy := strings.TrimSpace(j.Proxy)
+	err = notifications.ValidateProxyURL(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("proxy: %w", err)
+	}
+
 	cfg := &telegramConfig{
 		Enabled:         j.Enabled,
 		BotToken:        strings.TrimSpace(j.BotToken),
@@ -188,10 +291,25 @@ func telegramConfigFromJSON(j *telegramConfigJSON) (*telegramConfig, error) {
 		CheckInterval:   timeutil.Duration(check),
 		Cooldown:        timeutil.Duration(cooldown),
 		CustomMessage:   strings.TrimSpace(j.CustomMessage),
+		ParseMode:       j.ParseMode,
+		MessageThreadID: j.MessageThreadID,
+		Chats:           j.Chats,
+		AlertButtons:    j.AlertButtons,
+		Hysteresis:      j.Hysteresis,
+		SustainedChecks: j.SustainedChecks,
+		Proxy:           proxy,
+
+		TemperatureThreshold: j.TemperatureThreshold,
+		TemperatureCooldown:  timeutil.Duration(tempCooldown),
+
+		DiskPaths: j.DiskPaths,
+
+		SwapThreshold: j.SwapThreshold,
 	}
 
-	if cfg.Enabled && (cfg.BotToken == "" || cfg.ChatID == "") {
-		return nil, fmt.Errorf("bot_token and chat_id are required when notifications are enabled")
+	hasChat := cfg.ChatID != "" || hasEnabledTelegramChats(cfg.Chats)
+	if cfg.Enabled && (cfg.BotToken == "" || !hasChat) {
+		return nil, fmt.Errorf("bot_token and chat_id or chats are required when notifications are enabled")
 	}
 
 	cfg.applyDefaults()
@@ -208,7 +326,18 @@ func telegramConfigEqual(a, b *telegramConfig) bool {
 		a.DiskThreshold == b.DiskThreshold &&
 		a.CheckInterval == b.CheckInterval &&
 		a.Cooldown == b.Cooldown &&
-		a.CustomMessage == b.CustomMessage
+		a.CustomMessage == b.CustomMessage &&
+		a.ParseMode == b.ParseMode &&
+		a.MessageThreadID == b.MessageThreadID &&
+		telegramChatsEqual(a.Chats, b.Chats) &&
+		a.AlertButtons == b.AlertButtons &&
+		maps.Equal(a.Hysteresis, b.Hysteresis) &&
+		a.SustainedChecks == b.SustainedChecks &&
+		a.Proxy == b.Proxy &&
+		a.TemperatureThreshold == b.TemperatureThreshold &&
+		a.TemperatureCooldown == b.TemperatureCooldown &&
+		slices.Equal(a.DiskPaths, b.DiskPaths) &&
+		a.SwapThreshold == b.SwapThreshold
 }
 
 func buildRuntimeTelegramConfig(cfg *telegramConfig) notifications.TelegramConfig {
@@ -222,5 +351,19 @@ func buildRuntimeTelegramConfig(cfg *telegramConfig) notifications.TelegramConfi
 		CheckInterval:   time.Duration(cfg.CheckInterval),
 		Cooldown:        time.Duration(cfg.Cooldown),
 		CustomMessage:   cfg.CustomMessage,
+		ParseMode:       notifications.ParseMode(cfg.ParseMode),
+		MessageThreadID: cfg.MessageThreadID,
+		Chats:           buildRuntimeTelegramChats(cfg.Chats),
+		AlertButtons:    cfg.AlertButtons,
+		Hysteresis:      maps.Clone(cfg.Hysteresis),
+		SustainedChecks: cfg.SustainedChecks,
+		Proxy:           cfg.Proxy,
+
+		TemperatureThreshold: cfg.TemperatureThreshold,
+		TemperatureCooldown:  time.Duration(cfg.TemperatureCooldown),
+
+		DiskPaths: buildRuntimeDiskPaths(cfg.DiskPaths),
+
+		SwapThreshold: cfg.SwapThreshold,
 	}
 }
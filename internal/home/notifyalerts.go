@@ -0,0 +1,130 @@
+package home
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+	"github.com/AdguardTeam/golibs/timeutil"
+)
+
+// Alert snooze duration limits.
+const (
+	minAlertSnooze = time.Minute
+	maxAlertSnooze = 7 * timeutil.Day
+)
+
+// activeAlertsResp is the response of the GET /control/notifications/alerts
+// HTTP API.
+type activeAlertsResp struct {
+	Alerts []notifications.ActiveAlert `json:"alerts"`
+}
+
+// snoozeAlertReq is the request of the POST
+// /control/notifications/alerts/{id}/snooze HTTP API.
+type snoozeAlertReq struct {
+	// Duration is the snooze duration in milliseconds.
+	Duration int64 `json:"duration"`
+}
+
+// registerAlertHandlers registers the HTTP handlers of the active alerts.
+func (web *webAPI) registerAlertHandlers() {
+	web.httpReg.Register(http.MethodGet, "/control/notifications/alerts", web.handleGetAlerts)
+	web.httpReg.Register(http.MethodPost, "/control/notifications/alerts/{id}/ack", web.handleAckAlert)
+	web.httpReg.Register(http.MethodPost, "/control/notifications/alerts/{id}/snooze", web.handleSnoozeAlert)
+}
+
+// handleGetAlerts is the handler for the GET /control/notifications/alerts
+// HTTP API.
+func (web *webAPI) handleGetAlerts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if globalContext.notifier == nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusServiceUnavailable, "notifications manager unavailable")
+
+		return
+	}
+
+	resp := &activeAlertsResp{
+		Alerts: globalContext.notifier.ActiveAlerts(),
+	}
+
+	aghhttp.WriteJSONResponseOK(ctx, web.logger, w, r, resp)
+}
+
+// handleAckAlert is the handler for the POST
+// /control/notifications/alerts/{id}/ack HTTP API.  The id path segment is the
+// metric of the alert.
+func (web *webAPI) handleAckAlert(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if globalContext.notifier == nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusServiceUnavailable, "notifications manager unavailable")
+
+		return
+	}
+
+	id := r.PathValue("id")
+	err := globalContext.notifier.AcknowledgeAlert(id)
+	web.writeAlertActionResult(w, r, id, "acknowledged", err)
+}
+
+// handleSnoozeAlert is the handler for the POST
+// /control/notifications/alerts/{id}/snooze HTTP API.  The id path segment is
+// the metric of the alert.
+func (web *webAPI) handleSnoozeAlert(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if globalContext.notifier == nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusServiceUnavailable, "notifications manager unavailable")
+
+		return
+	}
+
+	req := &snoozeAlertReq{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusBadRequest, "json decode: %s", err)
+
+		return
+	}
+
+	d := time.Duration(req.Duration) * time.Millisecond
+	if d < minAlertSnooze || d > maxAlertSnooze {
+		aghhttp.ErrorAndLog(
+			ctx,
+			web.logger,
+			r,
+			w,
+			http.StatusUnprocessableEntity,
+			"duration must be between %s and %s",
+			minAlertSnooze,
+			maxAlertSnooze,
+		)
+
+		return
+	}
+
+	id := r.PathValue("id")
+	err = globalContext.notifier.SnoozeAlert(id, d)
+	web.writeAlertActionResult(w, r, id, "snoozed", err)
+}
+
+// writeAlertActionResult writes the response of the action on the alert with
+// id finished with err.
+func (web *webAPI) writeAlertActionResult(w http.ResponseWriter, r *http.Request, id, action string, err error) {
+	ctx := r.Context()
+
+	switch {
+	case err == nil:
+		web.logger.InfoContext(ctx, "alert "+action, "id", id)
+		aghhttp.OK(ctx, web.logger, w)
+	case errors.Is(err, notifications.ErrAlertNotFound):
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusNotFound, "%s: %q", err, id)
+	default:
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusInternalServerError, "%s", err)
+	}
+}
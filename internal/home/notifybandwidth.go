@@ -0,0 +1,117 @@
+package home
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+)
+
+// bandwidthConfig is the configuration of the network bandwidth alerts.
+type bandwidthConfig struct {
+	// Interfaces are the names of the monitored network interfaces.  All the
+	// interfaces except the loopback ones are monitored if it's empty.
+	Interfaces []string `yaml:"interfaces" json:"interfaces"`
+
+	// ThresholdMbps is the receive or transmit rate of an interface in
+	// megabits per second, which triggers the alert.  It must be positive.
+	ThresholdMbps float64 `yaml:"threshold_mbps" json:"threshold_mbps"`
+
+	// SustainedChecks is the number of the consecutive checks, which the rate
+	// must be above the threshold during for the alert to fire.
+	SustainedChecks int `yaml:"sustained_checks" json:"sustained_checks"`
+
+	// Enabled defines if the bandwidth alerts are sent.
+	Enabled bool `yaml:"enabled" json:"enabled"`
+}
+
+// defaultBandwidthConfig returns the default configuration of the network
+// bandwidth alerts.
+func defaultBandwidthConfig() (c bandwidthConfig) {
+	return bandwidthConfig{
+		Interfaces:      []string{},
+		ThresholdMbps:   100,
+		SustainedChecks: 3,
+	}
+}
+
+// normalize trims and deduplicates the interface names and returns an error if
+// the bandwidth alerts configuration is invalid.
+func (c *bandwidthConfig) normalize() (err error) {
+	ifaces := make([]string, 0, len(c.Interfaces))
+	for _, name := range c.Interfaces {
+		name = strings.TrimSpace(name)
+		if name == "" || !slices.Contains(ifaces, name) {
+			ifaces = append(ifaces, name)
+		}
+	}
+
+	c.Interfaces = ifaces
+
+	return notifications.ValidateBandwidthConfig(buildRuntimeBandwidthConfig(c))
+}
+
+// buildRuntimeBandwidthConfig converts the network bandwidth alerts
+// configuration into the notifications runtime one.
+func buildRuntimeBandwidthConfig(c *bandwidthConfig) (conf notifications.BandwidthConfig) {
+	return notifications.BandwidthConfig{
+		Interfaces:      slices.Clone(c.Interfaces),
+		ThresholdMbps:   c.ThresholdMbps,
+		SustainedChecks: c.SustainedChecks,
+		Enabled:         c.Enabled,
+	}
+}
+
+// registerBandwidthHandlers registers the HTTP handlers of the network
+// bandwidth alerts.
+func (web *webAPI) registerBandwidthHandlers() {
+	web.httpReg.Register(http.MethodGet, "/control/notifications/bandwidth", web.handleGetBandwidth)
+	web.httpReg.Register(http.MethodPut, "/control/notifications/bandwidth/update", web.handlePutBandwidth)
+}
+
+// handleGetBandwidth is the handler for the GET
+// /control/notifications/bandwidth HTTP API.
+func (web *webAPI) handleGetBandwidth(w http.ResponseWriter, r *http.Request) {
+	config.RLock()
+	resp := config.Notifications.Bandwidth
+	config.RUnlock()
+
+	aghhttp.WriteJSONResponseOK(r.Context(), web.logger, w, r, resp)
+}
+
+// handlePutBandwidth is the handler for the PUT
+// /control/notifications/bandwidth/update HTTP API.
+func (web *webAPI) handlePutBandwidth(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req := bandwidthConfig{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusBadRequest, "json decode: %s", err)
+
+		return
+	}
+
+	err = req.normalize()
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusUnprocessableEntity, "%s", err)
+
+		return
+	}
+
+	config.Lock()
+	config.Notifications.Bandwidth = req
+	config.Unlock()
+
+	web.logger.InfoContext(ctx, "bandwidth alerts updated", "enabled", req.Enabled)
+	web.confModifier.Apply(ctx)
+
+	if globalContext.notifier != nil {
+		globalContext.notifier.UpdateBandwidthConfig(buildRuntimeBandwidthConfig(&req))
+	}
+
+	aghhttp.OK(ctx, web.logger, w)
+}
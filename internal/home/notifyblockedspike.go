@@ -0,0 +1,108 @@
+package home
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+)
+
+// blockedSpikeConfig is the configuration of the blocked queries spike
+// alerts.
+type blockedSpikeConfig struct {
+	// Multiplier is the multiple of the baseline the number of blocked
+	// queries must exceed to be reported.  It must be greater than 1.
+	Multiplier float64 `yaml:"multiplier" json:"multiplier"`
+
+	// MinBlocked is the minimum number of blocked queries during a check to
+	// be reported, so that the idle clients don't trigger the alerts.
+	MinBlocked uint64 `yaml:"min_blocked" json:"min_blocked"`
+
+	// PerClient defines if the spikes are also detected for each client.
+	PerClient bool `yaml:"per_client" json:"per_client"`
+
+	// Enabled defines if the spikes are reported.
+	Enabled bool `yaml:"enabled" json:"enabled"`
+}
+
+// defaultBlockedSpikeConfig returns the default configuration of the blocked
+// queries spike alerts.
+func defaultBlockedSpikeConfig() (c blockedSpikeConfig) {
+	return blockedSpikeConfig{
+		Multiplier: 5,
+		MinBlocked: 100,
+	}
+}
+
+// normalize returns an error if the blocked queries spike configuration is
+// invalid.
+func (c *blockedSpikeConfig) normalize() (err error) {
+	return notifications.ValidateSpikeConfig(buildRuntimeSpikeConfig(c))
+}
+
+// buildRuntimeSpikeConfig converts the blocked queries spike configuration
+// into the notifications runtime one.
+func buildRuntimeSpikeConfig(c *blockedSpikeConfig) (conf notifications.SpikeConfig) {
+	return notifications.SpikeConfig{
+		Multiplier: c.Multiplier,
+		MinBlocked: c.MinBlocked,
+		PerClient:  c.PerClient,
+		Enabled:    c.Enabled,
+	}
+}
+
+// registerBlockedSpikeHandlers registers the HTTP handlers of the blocked
+// queries spike alerts.
+func (web *webAPI) registerBlockedSpikeHandlers() {
+	web.httpReg.Register(http.MethodGet, "/control/notifications/blocked_spike", web.handleGetBlockedSpike)
+	web.httpReg.Register(
+		http.MethodPut,
+		"/control/notifications/blocked_spike/update",
+		web.handlePutBlockedSpike,
+	)
+}
+
+// handleGetBlockedSpike is the handler for the GET
+// /control/notifications/blocked_spike HTTP API.
+func (web *webAPI) handleGetBlockedSpike(w http.ResponseWriter, r *http.Request) {
+	config.RLock()
+	resp := config.Notifications.BlockedSpike
+	config.RUnlock()
+
+	aghhttp.WriteJSONResponseOK(r.Context(), web.logger, w, r, resp)
+}
+
+// handlePutBlockedSpike is the handler for the PUT
+// /control/notifications/blocked_spike/update HTTP API.
+func (web *webAPI) handlePutBlockedSpike(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req := blockedSpikeConfig{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusBadRequest, "json decode: %s", err)
+
+		return
+	}
+
+	err = req.normalize()
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusUnprocessableEntity, "%s", err)
+
+		return
+	}
+
+	config.Lock()
+	config.Notifications.BlockedSpike = req
+	config.Unlock()
+
+	web.logger.InfoContext(ctx, "blocked spike alerts updated", "enabled", req.Enabled)
+	web.confModifier.Apply(ctx)
+
+	if globalContext.notifier != nil {
+		globalContext.notifier.UpdateSpikeConfig(buildRuntimeSpikeConfig(&req))
+	}
+
+	aghhttp.OK(ctx, web.logger, w)
+}
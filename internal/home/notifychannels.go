@@ -0,0 +1,528 @@
+package home
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+)
+
+// channelConfig is the configuration of a notification channel other than
+// Telegram.
+type channelConfig interface {
+	// normalize trims the configuration and returns an error if it is invalid.
+	normalize() (err error)
+}
+
+// matrixConfig is the configuration of the Matrix notification channel.
+type matrixConfig struct {
+	Enabled       bool   `yaml:"enabled" json:"enabled"`
+	HomeserverURL string `yaml:"homeserver_url" json:"homeserver_url"`
+	AccessToken   string `yaml:"access_token" json:"access_token"`
+	RoomID        string `yaml:"room_id" json:"room_id"`
+	Proxy         string `yaml:"proxy" json:"proxy"`
+}
+
+// type check
+var _ channelConfig = (*matrixConfig)(nil)
+
+// normalize implements the [channelConfig] interface for *matrixConfig.
+func (c *matrixConfig) normalize() (err error) {
+	c.HomeserverURL = strings.TrimSpace(c.HomeserverURL)
+	c.AccessToken = strings.TrimSpace(c.AccessToken)
+	c.RoomID = strings.TrimSpace(c.RoomID)
+
+	err = normalizeProxy(&c.Proxy)
+	if err != nil {
+		return err
+	}
+
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.HomeserverURL == "" || c.AccessToken == "" || c.RoomID == "" {
+		return fmt.Errorf("homeserver_url, access_token and room_id are required when matrix is enabled")
+	}
+
+	return validateChannelURL("homeserver_url", c.HomeserverURL)
+}
+
+// gotifyConfig is the configuration of the Gotify notification channel.
+// Priority is used for all events except for the alerts, which use
+// AlertPriority.
+type gotifyConfig struct {
+	Enabled       bool   `yaml:"enabled" json:"enabled"`
+	ServerURL     string `yaml:"server_url" json:"server_url"`
+	AppToken      string `yaml:"app_token" json:"app_token"`
+	Priority      int    `yaml:"priority" json:"priority"`
+	AlertPriority int    `yaml:"alert_priority" json:"alert_priority"`
+	Proxy         string `yaml:"proxy" json:"proxy"`
+}
+
+// maxGotifyPriority is the maximum priority of a Gotify message.
+const maxGotifyPriority = 10
+
+// type check
+var _ channelConfig = (*gotifyConfig)(nil)
+
+// normalize implements the [channelConfig] interface for *gotifyConfig.
+func (c *gotifyConfig) normalize() (err error) {
+	c.ServerURL = strings.TrimSpace(c.ServerURL)
+	c.AppToken = strings.TrimSpace(c.AppToken)
+
+	err = normalizeProxy(&c.Proxy)
+	if err != nil {
+		return err
+	}
+
+	for name, p := range map[string]int{
+		"priority":       c.Priority,
+		"alert_priority": c.AlertPriority,
+	} {
+		if p < 0 || p > maxGotifyPriority {
+			return fmt.Errorf("%s must be between 0 and %d", name, maxGotifyPriority)
+		}
+	}
+
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.ServerURL == "" || c.AppToken == "" {
+		return fmt.Errorf("server_url and app_token are required when gotify is enabled")
+	}
+
+	return validateChannelURL("server_url", c.ServerURL)
+}
+
+// mqttConfig is the configuration of the MQTT notification channel.
+type mqttConfig struct {
+	Enabled     bool   `yaml:"enabled" json:"enabled"`
+	BrokerURL   string `yaml:"broker_url" json:"broker_url"`
+	ClientID    string `yaml:"client_id" json:"client_id"`
+	Username    string `yaml:"username" json:"username"`
+	Password    string `yaml:"password" json:"password"`
+	TopicPrefix string `yaml:"topic_prefix" json:"topic_prefix"`
+	QoS         uint8  `yaml:"qos" json:"qos"`
+	Retain      bool   `yaml:"retain" json:"retain"`
+}
+
+// type check
+var _ channelConfig = (*mqttConfig)(nil)
+
+// normalize implements the [channelConfig] interface for *mqttConfig.
+func (c *mqttConfig) normalize() (err error) {
+	c.BrokerURL = strings.TrimSpace(c.BrokerURL)
+	c.ClientID = strings.TrimSpace(c.ClientID)
+	c.Username = strings.TrimSpace(c.Username)
+	c.TopicPrefix = strings.TrimSpace(c.TopicPrefix)
+
+	if c.QoS > 1 {
+		return fmt.Errorf("qos must be 0 or 1")
+	} else if strings.ContainsAny(c.TopicPrefix, "+#") {
+		return fmt.Errorf("topic_prefix must not contain wildcards")
+	}
+
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.BrokerURL == "" {
+		return fmt.Errorf("broker_url is required when mqtt is enabled")
+	}
+
+	u, err := url.Parse(c.BrokerURL)
+	if err != nil {
+		return fmt.Errorf("broker_url: %w", err)
+	}
+
+	switch u.Scheme {
+	case "tcp", "mqtt", "ssl", "tls", "mqtts":
+		// Go on.
+	default:
+		return fmt.Errorf("broker_url scheme must be one of tcp, mqtt, ssl, tls, or mqtts")
+	}
+
+	if u.Hostname() == "" {
+		return fmt.Errorf("broker_url must contain a host")
+	}
+
+	return nil
+}
+
+// signalConfig is the configuration of the Signal notification channel, which
+// sends messages through a signal-cli-rest-api server.
+type signalConfig struct {
+	Enabled    bool     `yaml:"enabled" json:"enabled"`
+	BaseURL    string   `yaml:"base_url" json:"base_url"`
+	Number     string   `yaml:"number" json:"number"`
+	Recipients []string `yaml:"recipients" json:"recipients"`
+	Proxy      string   `yaml:"proxy" json:"proxy"`
+}
+
+// type check
+var _ channelConfig = (*signalConfig)(nil)
+
+// normalize implements the [channelConfig] interface for *signalConfig.
+func (c *signalConfig) normalize() (err error) {
+	c.BaseURL = strings.TrimSpace(c.BaseURL)
+	c.Number = strings.TrimSpace(c.Number)
+
+	err = normalizeProxy(&c.Proxy)
+	if err != nil {
+		return err
+	}
+
+	recipients := make([]string, 0, len(c.Recipients))
+	for _, r := range c.Recipients {
+		r = strings.TrimSpace(r)
+		if r != "" {
+			recipients = append(recipients, r)
+		}
+	}
+
+	c.Recipients = recipients
+
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.BaseURL == "" || c.Number == "" || len(c.Recipients) == 0 {
+		return fmt.Errorf("base_url, number and recipients are required when signal is enabled")
+	}
+
+	return validateChannelURL("base_url", c.BaseURL)
+}
+
+// pagerDutyConfig is the configuration of the PagerDuty notification channel.
+type pagerDutyConfig struct {
+	Enabled    bool   `yaml:"enabled" json:"enabled"`
+	RoutingKey string `yaml:"routing_key" json:"routing_key"`
+	Severity   string `yaml:"severity" json:"severity"`
+	Proxy      string `yaml:"proxy" json:"proxy"`
+}
+
+// type check
+var _ channelConfig = (*pagerDutyConfig)(nil)
+
+// normalize implements the [channelConfig] interface for *pagerDutyConfig.
+func (c *pagerDutyConfig) normalize() (err error) {
+	c.RoutingKey = strings.TrimSpace(c.RoutingKey)
+	c.Severity = strings.ToLower(strings.TrimSpace(c.Severity))
+
+	err = normalizeProxy(&c.Proxy)
+	if err != nil {
+		return err
+	}
+
+	switch c.Severity {
+	case "", "critical", "error", "warning", "info":
+		// Go on.
+	default:
+		return fmt.Errorf("severity must be one of critical, error, warning, or info")
+	}
+
+	if c.Enabled && c.RoutingKey == "" {
+		return fmt.Errorf("routing_key is required when pagerduty is enabled")
+	}
+
+	return nil
+}
+
+// opsgenieConfig is the configuration of the Opsgenie notification channel.
+// Priorities maps the metrics, like "cpu" or "protection", to the alert
+// priorities, and Priority is used for the metrics missing from it.
+type opsgenieConfig struct {
+	Priorities map[string]string `yaml:"priorities" json:"priorities"`
+	Enabled    bool              `yaml:"enabled" json:"enabled"`
+	APIKey     string            `yaml:"api_key" json:"api_key"`
+	Region     string            `yaml:"region" json:"region"`
+	Priority   string            `yaml:"priority" json:"priority"`
+	Tags       []string          `yaml:"tags" json:"tags"`
+	Proxy      string            `yaml:"proxy" json:"proxy"`
+}
+
+// type check
+var _ channelConfig = (*opsgenieConfig)(nil)
+
+// normalize implements the [channelConfig] interface for *opsgenieConfig.
+func (c *opsgenieConfig) normalize() (err error) {
+	c.APIKey = strings.TrimSpace(c.APIKey)
+	c.Region = strings.ToLower(strings.TrimSpace(c.Region))
+	c.Priority = strings.ToUpper(strings.TrimSpace(c.Priority))
+
+	err = normalizeProxy(&c.Proxy)
+	if err != nil {
+		return err
+	}
+
+	if c.Region != "" && c.Region != "us" && c.Region != "eu" {
+		return fmt.Errorf("region must be us or eu")
+	}
+
+	if c.Priority != "" && !isOpsgeniePriority(c.Priority) {
+		return fmt.Errorf("priority must be one of P1, P2, P3, P4, or P5")
+	}
+
+	for metric, p := range c.Priorities {
+		p = strings.ToUpper(strings.TrimSpace(p))
+		if !isOpsgeniePriority(p) {
+			return fmt.Errorf("priorities: %s: priority must be one of P1, P2, P3, P4, or P5", metric)
+		}
+
+		c.Priorities[metric] = p
+	}
+
+	tags := make([]string, 0, len(c.Tags))
+	for _, t := range c.Tags {
+		t = strings.TrimSpace(t)
+		if t != "" {
+			tags = append(tags, t)
+		}
+	}
+
+	c.Tags = tags
+
+	if c.Enabled && c.APIKey == "" {
+		return fmt.Errorf("api_key is required when opsgenie is enabled")
+	}
+
+	return nil
+}
+
+// isOpsgeniePriority returns true if p is a valid Opsgenie alert priority.
+func isOpsgeniePriority(p string) (ok bool) {
+	return len(p) == 2 && p[0] == 'P' && p[1] >= '1' && p[1] <= '5'
+}
+
+// teamsConfig is the configuration of the Microsoft Teams notification
+// channel.
+type teamsConfig struct {
+	Enabled    bool   `yaml:"enabled" json:"enabled"`
+	WebhookURL string `yaml:"webhook_url" json:"webhook_url"`
+	Proxy      string `yaml:"proxy" json:"proxy"`
+}
+
+// type check
+var _ channelConfig = (*teamsConfig)(nil)
+
+// normalize implements the [channelConfig] interface for *teamsConfig.
+func (c *teamsConfig) normalize() (err error) {
+	c.WebhookURL = strings.TrimSpace(c.WebhookURL)
+
+	err = normalizeProxy(&c.Proxy)
+	if err != nil {
+		return err
+	}
+
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.WebhookURL == "" {
+		return fmt.Errorf("webhook_url is required when teams is enabled")
+	}
+
+	return validateChannelURL("webhook_url", c.WebhookURL)
+}
+
+// alertmanagerConfig is the configuration of the Prometheus Alertmanager
+// notification channel.  Labels are added to the labels of every alert.
+type alertmanagerConfig struct {
+	Labels   map[string]string `yaml:"labels" json:"labels"`
+	Enabled  bool              `yaml:"enabled" json:"enabled"`
+	URL      string            `yaml:"url" json:"url"`
+	Username string            `yaml:"username" json:"username"`
+	Password string            `yaml:"password" json:"password"`
+	Proxy    string            `yaml:"proxy" json:"proxy"`
+}
+
+// type check
+var _ channelConfig = (*alertmanagerConfig)(nil)
+
+// normalize implements the [channelConfig] interface for *alertmanagerConfig.
+func (c *alertmanagerConfig) normalize() (err error) {
+	c.URL = strings.TrimSpace(c.URL)
+	c.Username = strings.TrimSpace(c.Username)
+
+	err = normalizeProxy(&c.Proxy)
+	if err != nil {
+		return err
+	}
+
+	for name := range c.Labels {
+		switch name {
+		case "alertname", "instance", "metric":
+			return fmt.Errorf("labels: %q is reserved", name)
+		case "":
+			return fmt.Errorf("labels: empty label name")
+		}
+	}
+
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.URL == "" {
+		return fmt.Errorf("url is required when alertmanager is enabled")
+	}
+
+	return validateChannelURL("url", c.URL)
+}
+
+// validateChannelURL returns an error if rawURL isn't an absolute HTTP(S) URL.
+func validateChannelURL(name, rawURL string) (err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s must be an http or https url", name)
+	}
+
+	return nil
+}
+
+// normalizeProxy trims the proxy URL of a notification channel and returns an
+// error if it is invalid.
+func normalizeProxy(proxy *string) (err error) {
+	*proxy = strings.TrimSpace(*proxy)
+	err = notifications.ValidateProxyURL(*proxy)
+	if err != nil {
+		return fmt.Errorf("proxy: %w", err)
+	}
+
+	return nil
+}
+
+// buildRuntimeChannelsConfig returns the runtime configuration of the
+// notification channels other than Telegram.  n must not be nil.
+func buildRuntimeChannelsConfig(n *notificationsConfig) (cfg notifications.ChannelsConfig) {
+	return notifications.ChannelsConfig{
+		Matrix: notifications.MatrixConfig{
+			Enabled:       n.Matrix.Enabled,
+			HomeserverURL: n.Matrix.HomeserverURL,
+			AccessToken:   n.Matrix.AccessToken,
+			RoomID:        n.Matrix.RoomID,
+			Proxy:         n.Matrix.Proxy,
+		},
+		Gotify: notifications.GotifyConfig{
+			Enabled:       n.Gotify.Enabled,
+			ServerURL:     n.Gotify.ServerURL,
+			AppToken:      n.Gotify.AppToken,
+			Priority:      n.Gotify.Priority,
+			AlertPriority: n.Gotify.AlertPriority,
+			Proxy:         n.Gotify.Proxy,
+		},
+		MQTT: notifications.MQTTConfig{
+			Enabled:     n.MQTT.Enabled,
+			BrokerURL:   n.MQTT.BrokerURL,
+			ClientID:    n.MQTT.ClientID,
+			Username:    n.MQTT.Username,
+			Password:    n.MQTT.Password,
+			TopicPrefix: n.MQTT.TopicPrefix,
+			QoS:         n.MQTT.QoS,
+			Retain:      n.MQTT.Retain,
+		},
+		Signal: notifications.SignalConfig{
+			Enabled:    n.Signal.Enabled,
+			BaseURL:    n.Signal.BaseURL,
+			Number:     n.Signal.Number,
+			Recipients: slices.Clone(n.Signal.Recipients),
+			Proxy:      n.Signal.Proxy,
+		},
+		PagerDuty: notifications.PagerDutyConfig{
+			Enabled:    n.PagerDuty.Enabled,
+			RoutingKey: n.PagerDuty.RoutingKey,
+			Severity:   n.PagerDuty.Severity,
+			Proxy:      n.PagerDuty.Proxy,
+		},
+		Opsgenie: notifications.OpsgenieConfig{
+			Priorities: maps.Clone(n.Opsgenie.Priorities),
+			Enabled:    n.Opsgenie.Enabled,
+			APIKey:     n.Opsgenie.APIKey,
+			Region:     n.Opsgenie.Region,
+			Priority:   n.Opsgenie.Priority,
+			Tags:       slices.Clone(n.Opsgenie.Tags),
+			Proxy:      n.Opsgenie.Proxy,
+		},
+		Teams: notifications.TeamsConfig{
+			Enabled:    n.Teams.Enabled,
+			WebhookURL: n.Teams.WebhookURL,
+			Proxy:      n.Teams.Proxy,
+		},
+		Alertmanager: notifications.AlertmanagerConfig{
+			Labels:   maps.Clone(n.Alertmanager.Labels),
+			Enabled:  n.Alertmanager.Enabled,
+			URL:      n.Alertmanager.URL,
+			Username: n.Alertmanager.Username,
+			Password: n.Alertmanager.Password,
+			Proxy:    n.Alertmanager.Proxy,
+		},
+	}
+}
+
+// registerChannelHandlers registers the HTTP handlers for getting, updating,
+// and testing the notification channel with the given name.  field returns
+// the configuration of the channel within n.
+func registerChannelHandlers[T any, PT interface {
+	*T
+	channelConfig
+}](
+	web *webAPI,
+	name string,
+	field func(n *notificationsConfig) (c PT),
+) {
+	prefix := "/control/notifications/" + name
+
+	web.httpReg.Register(http.MethodGet, prefix, func(w http.ResponseWriter, r *http.Request) {
+		config.RLock()
+		resp := *field(&config.Notifications)
+		config.RUnlock()
+
+		aghhttp.WriteJSONResponseOK(r.Context(), web.logger, w, r, resp)
+	})
+
+	web.httpReg.Register(http.MethodPut, prefix+"/update", func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var req T
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusBadRequest, "json decode: %s", err)
+
+			return
+		}
+
+		err = PT(&req).normalize()
+		if err != nil {
+			aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusUnprocessableEntity, "%s", err)
+
+			return
+		}
+
+		config.Lock()
+		*field(&config.Notifications) = req
+		runtimeCfg := buildRuntimeChannelsConfig(&config.Notifications)
+		config.Unlock()
+
+		web.logger.InfoContext(ctx, "notification channel updated", "channel", name)
+		web.confModifier.Apply(ctx)
+
+		if globalContext.notifier != nil {
+			globalContext.notifier.UpdateChannelsConfig(runtimeCfg)
+		}
+
+		aghhttp.OK(ctx, web.logger, w)
+	})
+
+	web.httpReg.Register(http.MethodPost, prefix+"/test", web.newNotificationTestHandler(name))
+}
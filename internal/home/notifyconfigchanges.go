@@ -0,0 +1,237 @@
+package home
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+	yaml "go.yaml.in/yaml/v4"
+)
+
+// configChangesConfig is the configuration of the notifications about the
+// configuration changes applied through the web API.
+type configChangesConfig struct {
+	// Ignored are the configuration keys, like "statistics" or
+	// "dns.upstream_dns", the changes of which aren't reported.
+	Ignored []string `yaml:"ignored" json:"ignored"`
+
+	// Enabled defines if the configuration changes are reported.
+	Enabled bool `yaml:"enabled" json:"enabled"`
+}
+
+// normalize returns an error if the configuration is invalid.
+func (c *configChangesConfig) normalize() (err error) {
+	for i, key := range c.Ignored {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return fmt.Errorf("ignored at index %d: empty key", i)
+		}
+
+		c.Ignored[i] = key
+	}
+
+	return nil
+}
+
+// isIgnored returns true if the changes of key aren't reported.
+func (c *configChangesConfig) isIgnored(key string) (ok bool) {
+	return slices.ContainsFunc(c.Ignored, func(ign string) (found bool) {
+		return key == ign || strings.HasPrefix(key, ign+".")
+	})
+}
+
+// configChange is a change of the value of a configuration key.
+type configChange struct {
+	// Before is the previous value of the key.  It's nil if the key has been
+	// added.
+	Before any `json:"before"`
+
+	// After is the current value of the key.  It's nil if the key has been
+	// removed.
+	After any `json:"after"`
+
+	// Key is the key of the configuration file, like "dns.upstream_dns".
+	Key string `json:"key"`
+}
+
+// configChangedKeys returns the sorted keys of the configuration file changed
+// between prev and cur.  The keys of the nested sections are joined with a
+// dot, like "dns.upstream_dns", up to the second level.
+func configChangedKeys(prev, cur []byte) (keys []string, err error) {
+	changes, err := configDiff(prev, cur)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range changes {
+		keys = append(keys, c.Key)
+	}
+
+	return keys, nil
+}
+
+// configDiff returns the changes of the configuration file between prev and
+// cur sorted by the key.  The keys are the same as in [configChangedKeys].
+func configDiff(prev, cur []byte) (changes []*configChange, err error) {
+	var prevConf, curConf map[string]any
+	err = yaml.Unmarshal(prev, &prevConf)
+	if err != nil {
+		return nil, fmt.Errorf("decoding previous config: %w", err)
+	}
+
+	err = yaml.Unmarshal(cur, &curConf)
+	if err != nil {
+		return nil, fmt.Errorf("decoding current config: %w", err)
+	}
+
+	for _, key := range unionKeys(prevConf, curConf) {
+		p, c := prevConf[key], curConf[key]
+		if reflect.DeepEqual(p, c) {
+			continue
+		}
+
+		pm, pok := p.(map[string]any)
+		cm, cok := c.(map[string]any)
+		if !pok || !cok {
+			changes = append(changes, &configChange{Before: p, After: c, Key: key})
+
+			continue
+		}
+
+		for _, sub := range unionKeys(pm, cm) {
+			if !reflect.DeepEqual(pm[sub], cm[sub]) {
+				changes = append(changes, &configChange{
+					Before: pm[sub],
+					After:  cm[sub],
+					Key:    key + "." + sub,
+				})
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+// unionKeys returns the sorted union of the keys of a and b.
+func unionKeys(a, b map[string]any) (keys []string) {
+	keys = slices.AppendSeq(slices.Collect(maps.Keys(a)), maps.Keys(b))
+	slices.Sort(keys)
+
+	return slices.Compact(keys)
+}
+
+// tracksConfigChanges returns true if the configuration change applied within
+// ctx should be reported.  Only the changes made through the web API are.
+func tracksConfigChanges(ctx context.Context) (ok bool) {
+	if globalContext.notifier == nil {
+		return false
+	} else if _, ok = remoteIPFromContext(ctx); !ok {
+		return false
+	}
+
+	config.RLock()
+	defer config.RUnlock()
+
+	return config.Notifications.ConfigChanges.Enabled
+}
+
+// notifyConfigChange reports the difference between the configuration file
+// contents prev and the current contents of the file at confPath.
+func (cm *defaultConfigModifier) notifyConfigChange(ctx context.Context, prev []byte, confPath string) {
+	cur, err := os.ReadFile(confPath)
+	if err != nil {
+		cm.logger.DebugContext(ctx, "reading config for changes", slogutil.KeyError, err)
+
+		return
+	}
+
+	keys, err := configChangedKeys(prev, cur)
+	if err != nil {
+		cm.logger.DebugContext(ctx, "comparing configs", slogutil.KeyError, err)
+
+		return
+	}
+
+	config.RLock()
+	conf := config.Notifications.ConfigChanges
+	config.RUnlock()
+
+	keys = slices.DeleteFunc(keys, conf.isIgnored)
+	if len(keys) == 0 {
+		return
+	}
+
+	change := notifications.ConfigChange{
+		Keys: keys,
+	}
+
+	if ip, ok := remoteIPFromContext(ctx); ok {
+		change.RemoteIP = ip.String()
+	}
+
+	if u, ok := webUserFromContext(ctx); ok {
+		change.User = string(u.Login)
+	}
+
+	go globalContext.notifier.NotifyConfigChange(context.WithoutCancel(ctx), change)
+}
+
+// registerConfigChangesHandlers registers the HTTP handlers of the
+// configuration change notifications.
+func (web *webAPI) registerConfigChangesHandlers() {
+	web.httpReg.Register(http.MethodGet, "/control/notifications/config_changes", web.handleGetConfigChanges)
+	web.httpReg.Register(
+		http.MethodPut,
+		"/control/notifications/config_changes/update",
+		web.handlePutConfigChanges,
+	)
+}
+
+// handleGetConfigChanges is the handler for the GET
+// /control/notifications/config_changes HTTP API.
+func (web *webAPI) handleGetConfigChanges(w http.ResponseWriter, r *http.Request) {
+	config.RLock()
+	resp := config.Notifications.ConfigChanges
+	config.RUnlock()
+
+	aghhttp.WriteJSONResponseOK(r.Context(), web.logger, w, r, resp)
+}
+
+// handlePutConfigChanges is the handler for the PUT
+// /control/notifications/config_changes/update HTTP API.
+func (web *webAPI) handlePutConfigChanges(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req := configChangesConfig{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusBadRequest, "json decode: %s", err)
+
+		return
+	}
+
+	err = req.normalize()
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusUnprocessableEntity, "%s", err)
+
+		return
+	}
+
+	config.Lock()
+	config.Notifications.ConfigChanges = req
+	config.Unlock()
+
+	web.logger.InfoContext(ctx, "config change notifications updated", "enabled", req.Enabled)
+	web.confModifier.Apply(ctx)
+
+	aghhttp.OK(ctx, web.logger, w)
+}
@@ -0,0 +1,54 @@
+package home
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigChangedKeys(t *testing.T) {
+	const prev = `
+http:
+  address: 0.0.0.0:80
+dns:
+  upstream_dns:
+    - 1.1.1.1
+  cache_size: 4096
+filtering:
+  protection_enabled: true
+schema_version: 30
+`
+
+	const cur = `
+http:
+  address: 0.0.0.0:80
+dns:
+  upstream_dns:
+    - 8.8.8.8
+  cache_size: 4096
+  cache_ttl_min: 60
+filtering:
+  protection_enabled: true
+schema_version: 31
+clients: {}
+`
+
+	keys, err := configChangedKeys([]byte(prev), []byte(cur))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"clients",
+		"dns.cache_ttl_min",
+		"dns.upstream_dns",
+		"schema_version",
+	}, keys)
+
+	conf := &configChangesConfig{Ignored: []string{" dns ", "schema_version"}}
+	require.NoError(t, conf.normalize())
+
+	assert.True(t, conf.isIgnored("dns.upstream_dns"))
+	assert.True(t, conf.isIgnored("schema_version"))
+	assert.False(t, conf.isIgnored("dnsx"))
+	assert.False(t, conf.isIgnored("clients"))
+}
@@ -0,0 +1,119 @@
+package home
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/dhcpd"
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+)
+
+// dhcpEventsConfig contains the per-event toggles of the DHCP lease
+// notifications.
+type dhcpEventsConfig struct {
+	// Granted defines if a new dynamic lease is reported.
+	Granted bool `yaml:"granted" json:"granted"`
+
+	// Renewed defines if an extension of a dynamic lease is reported.
+	Renewed bool `yaml:"renewed" json:"renewed"`
+
+	// Expired defines if a dynamic lease not renewed in time is reported.
+	Expired bool `yaml:"expired" json:"expired"`
+
+	// StaticConflict defines if a static lease conflicting with an existing
+	// one is reported.
+	StaticConflict bool `yaml:"static_conflict" json:"static_conflict"`
+}
+
+// buildRuntimeDHCPEventsConfig converts the DHCP lease notifications toggles
+// into the notifications runtime ones.
+func buildRuntimeDHCPEventsConfig(c *dhcpEventsConfig) (conf notifications.DHCPEventsConfig) {
+	return notifications.DHCPEventsConfig{
+		Granted:        c.Granted,
+		Renewed:        c.Renewed,
+		Expired:        c.Expired,
+		StaticConflict: c.StaticConflict,
+	}
+}
+
+// newDHCPLeaseEventHandler returns the DHCP lease event handler, which
+// forwards the events to the notifications manager, if there is one.
+func newDHCPLeaseEventHandler(ctx context.Context) (h dhcpd.LeaseEventHandler) {
+	ctx = context.WithoutCancel(ctx)
+
+	return func(ev dhcpd.LeaseEvent) {
+		notifier := globalContext.notifier
+		if notifier == nil {
+			return
+		}
+
+		l := ev.Lease
+		lev := notifications.DHCPLeaseEvent{
+			Type:     notifications.DHCPLeaseEventType(ev.Type),
+			Hostname: l.Hostname,
+			MAC:      l.HWAddr.String(),
+			Reason:   ev.Reason,
+		}
+
+		if l.IP.IsValid() {
+			lev.IP = l.IP.String()
+		}
+
+		if !l.IsStatic {
+			lev.Expiry = l.Expiry
+		}
+
+		// Don't block the DHCP packet handlers.
+		go notifier.NotifyDHCPLease(ctx, lev)
+	}
+}
+
+// registerDHCPEventsHandlers registers the HTTP handlers of the DHCP lease
+// notifications.
+func (web *webAPI) registerDHCPEventsHandlers() {
+	web.httpReg.Register(http.MethodGet, "/control/notifications/dhcp_events", web.handleGetDHCPEvents)
+	web.httpReg.Register(
+		http.MethodPut,
+		"/control/notifications/dhcp_events/update",
+		web.handlePutDHCPEvents,
+	)
+}
+
+// handleGetDHCPEvents is the handler for the GET
+// /control/notifications/dhcp_events HTTP API.
+func (web *webAPI) handleGetDHCPEvents(w http.ResponseWriter, r *http.Request) {
+	config.RLock()
+	resp := config.Notifications.DHCPEvents
+	config.RUnlock()
+
+	aghhttp.WriteJSONResponseOK(r.Context(), web.logger, w, r, resp)
+}
+
+// handlePutDHCPEvents is the handler for the PUT
+// /control/notifications/dhcp_events/update HTTP API.
+func (web *webAPI) handlePutDHCPEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req := dhcpEventsConfig{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusBadRequest, "json decode: %s", err)
+
+		return
+	}
+
+	config.Lock()
+	config.Notifications.DHCPEvents = req
+	config.Unlock()
+
+	web.logger.InfoContext(ctx, "dhcp lease notifications updated")
+	web.confModifier.Apply(ctx)
+
+	if globalContext.notifier != nil {
+		globalContext.notifier.UpdateDHCPEventsConfig(buildRuntimeDHCPEventsConfig(&req))
+	}
+
+	aghhttp.OK(ctx, web.logger, w)
+}
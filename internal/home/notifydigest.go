@@ -0,0 +1,114 @@
+package home
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+	"github.com/AdguardTeam/golibs/timeutil"
+)
+
+// Digest interval limits.
+const (
+	minDigestInterval = time.Minute
+	maxDigestInterval = 7 * timeutil.Day
+)
+
+// digestConfig is the configuration of the notification digest mode.
+type digestConfig struct {
+	// Events are the events batched into the digest in the format of the
+	// routing rules keys.  Only the filter updates are batched if it's nil.
+	Events []string `yaml:"events" json:"events"`
+
+	// Interval is the period of the digest.
+	Interval timeutil.Duration `yaml:"interval" json:"interval"`
+
+	// Enabled defines if the digest mode is used.
+	Enabled bool `yaml:"enabled" json:"enabled"`
+}
+
+// normalize sets the default interval and returns an error if the digest
+// configuration is invalid.
+func (c *digestConfig) normalize() (err error) {
+	if c.Interval == 0 {
+		c.Interval = timeutil.Duration(time.Hour)
+	}
+
+	if d := time.Duration(c.Interval); d < minDigestInterval || d > maxDigestInterval {
+		return fmt.Errorf("interval must be between %s and %s", minDigestInterval, maxDigestInterval)
+	}
+
+	for _, key := range c.Events {
+		err = notifications.ValidateRoutes(map[string][]string{key: nil})
+		if err != nil {
+			return fmt.Errorf("events: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// buildRuntimeDigestConfig converts the digest configuration into the
+// notifications runtime one.
+func buildRuntimeDigestConfig(c *digestConfig) (conf notifications.DigestConfig) {
+	return notifications.DigestConfig{
+		Events:   slices.Clone(c.Events),
+		Interval: time.Duration(c.Interval),
+		Enabled:  c.Enabled,
+	}
+}
+
+// registerDigestHandlers registers the HTTP handlers of the notification
+// digest mode.
+func (web *webAPI) registerDigestHandlers() {
+	web.httpReg.Register(http.MethodGet, "/control/notifications/digest", web.handleGetDigest)
+	web.httpReg.Register(http.MethodPut, "/control/notifications/digest/update", web.handlePutDigest)
+}
+
+// handleGetDigest is the handler for the GET /control/notifications/digest
+// HTTP API.
+func (web *webAPI) handleGetDigest(w http.ResponseWriter, r *http.Request) {
+	config.RLock()
+	resp := config.Notifications.Digest
+	config.RUnlock()
+
+	aghhttp.WriteJSONResponseOK(r.Context(), web.logger, w, r, resp)
+}
+
+// handlePutDigest is the handler for the PUT
+// /control/notifications/digest/update HTTP API.
+func (web *webAPI) handlePutDigest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req := digestConfig{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusBadRequest, "json decode: %s", err)
+
+		return
+	}
+
+	err = req.normalize()
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusUnprocessableEntity, "%s", err)
+
+		return
+	}
+
+	config.Lock()
+	config.Notifications.Digest = req
+	config.Unlock()
+
+	web.logger.InfoContext(ctx, "notification digest updated", "enabled", req.Enabled)
+	web.confModifier.Apply(ctx)
+
+	if globalContext.notifier != nil {
+		globalContext.notifier.UpdateDigestConfig(buildRuntimeDigestConfig(&req))
+	}
+
+	aghhttp.OK(ctx, web.logger, w)
+}
@@ -0,0 +1,44 @@
+package home
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+)
+
+// diskPathConfig is the configuration of an additional mount point, which disk
+// usage is monitored with its own threshold.
+type diskPathConfig struct {
+	// Path is the absolute path within the mount point, like "/data".
+	Path string `yaml:"path" json:"path"`
+
+	// Threshold is the disk usage in percent, which triggers the alert.
+	Threshold float64 `yaml:"threshold" json:"threshold"`
+}
+
+// normalizeDiskPaths cleans the paths of the additional mount points and
+// returns an error if any of them is invalid.
+func normalizeDiskPaths(paths []diskPathConfig) (err error) {
+	for i := range paths {
+		p := &paths[i]
+		if p.Path = strings.TrimSpace(p.Path); p.Path != "" {
+			p.Path = filepath.Clean(p.Path)
+		}
+	}
+
+	return notifications.ValidateDiskPaths(buildRuntimeDiskPaths(paths))
+}
+
+// buildRuntimeDiskPaths converts the additional mount points into the
+// notifications runtime ones.
+func buildRuntimeDiskPaths(paths []diskPathConfig) (rt []notifications.DiskPath) {
+	for _, p := range paths {
+		rt = append(rt, notifications.DiskPath{
+			Path:      p.Path,
+			Threshold: p.Threshold,
+		})
+	}
+
+	return rt
+}
@@ -0,0 +1,105 @@
+package home
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+	"github.com/AdguardTeam/golibs/timeutil"
+)
+
+// dnsPerfConfig is the configuration of the alerts about the DNS resolution
+// quality.  A zero limit means that the corresponding metric isn't monitored.
+type dnsPerfConfig struct {
+	// MaxLatency is the average duration of the upstream queries.
+	MaxLatency timeutil.Duration `yaml:"max_latency" json:"max_latency"`
+
+	// MaxServFailRate is the share of the queries answered with SERVFAIL in
+	// percent.
+	MaxServFailRate float64 `yaml:"max_servfail_rate" json:"max_servfail_rate"`
+
+	// MaxQPS is the number of the queries per second.
+	MaxQPS float64 `yaml:"max_qps" json:"max_qps"`
+
+	// Enabled defines if the DNS performance alerts are sent.
+	Enabled bool `yaml:"enabled" json:"enabled"`
+}
+
+// defaultDNSPerfConfig returns the default configuration of the DNS
+// performance alerts.
+func defaultDNSPerfConfig() (c dnsPerfConfig) {
+	return dnsPerfConfig{
+		MaxLatency:      timeutil.Duration(500 * time.Millisecond),
+		MaxServFailRate: 5,
+	}
+}
+
+// normalize returns an error if the DNS performance alerts configuration is
+// invalid.
+func (c *dnsPerfConfig) normalize() (err error) {
+	return notifications.ValidateDNSPerfConfig(buildRuntimeDNSPerfConfig(c))
+}
+
+// buildRuntimeDNSPerfConfig converts the DNS performance alerts configuration
+// into the notifications runtime one.
+func buildRuntimeDNSPerfConfig(c *dnsPerfConfig) (conf notifications.DNSPerfConfig) {
+	return notifications.DNSPerfConfig{
+		MaxLatency:      time.Duration(c.MaxLatency),
+		MaxServFailRate: c.MaxServFailRate,
+		MaxQPS:          c.MaxQPS,
+		Enabled:         c.Enabled,
+	}
+}
+
+// registerDNSPerfHandlers registers the HTTP handlers of the DNS performance
+// alerts.
+func (web *webAPI) registerDNSPerfHandlers() {
+	web.httpReg.Register(http.MethodGet, "/control/notifications/dns_perf", web.handleGetDNSPerf)
+	web.httpReg.Register(http.MethodPut, "/control/notifications/dns_perf/update", web.handlePutDNSPerf)
+}
+
+// handleGetDNSPerf is the handler for the GET /control/notifications/dns_perf
+// HTTP API.
+func (web *webAPI) handleGetDNSPerf(w http.ResponseWriter, r *http.Request) {
+	config.RLock()
+	resp := config.Notifications.DNSPerf
+	config.RUnlock()
+
+	aghhttp.WriteJSONResponseOK(r.Context(), web.logger, w, r, resp)
+}
+
+// handlePutDNSPerf is the handler for the PUT
+// /control/notifications/dns_perf/update HTTP API.
+func (web *webAPI) handlePutDNSPerf(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req := dnsPerfConfig{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusBadRequest, "json decode: %s", err)
+
+		return
+	}
+
+	err = req.normalize()
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusUnprocessableEntity, "%s", err)
+
+		return
+	}
+
+	config.Lock()
+	config.Notifications.DNSPerf = req
+	config.Unlock()
+
+	web.logger.InfoContext(ctx, "dns performance alerts updated", "enabled", req.Enabled)
+	web.confModifier.Apply(ctx)
+
+	if globalContext.notifier != nil {
+		globalContext.notifier.UpdateDNSPerfConfig(buildRuntimeDNSPerfConfig(&req))
+	}
+
+	aghhttp.OK(ctx, web.logger, w)
+}
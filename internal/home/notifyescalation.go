@@ -0,0 +1,111 @@
+package home
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+	"github.com/AdguardTeam/golibs/timeutil"
+)
+
+// minEscalationInterval is the minimum non-zero interval of the
+// re-notification and escalation of the unresolved alerts.
+const minEscalationInterval = time.Minute
+
+// escalationConfig is the configuration of the re-notification and escalation
+// of the unresolved alerts.
+type escalationConfig struct {
+	// Targets are the names of the targets receiving the escalated alerts.
+	Targets []string `yaml:"targets" json:"targets"`
+
+	// Repeat is the interval of the re-notification about the unresolved
+	// alerts.  Zero disables it.
+	Repeat timeutil.Duration `yaml:"repeat" json:"repeat"`
+
+	// After is the time after which the unresolved alerts are escalated to
+	// Targets.  Zero disables it.
+	After timeutil.Duration `yaml:"after" json:"after"`
+
+	// Enabled defines if the unresolved alerts are re-notified and escalated.
+	Enabled bool `yaml:"enabled" json:"enabled"`
+}
+
+// normalize returns an error if the escalation configuration is invalid.
+func (c *escalationConfig) normalize() (err error) {
+	for name, d := range map[string]timeutil.Duration{
+		"repeat": c.Repeat,
+		"after":  c.After,
+	} {
+		if d != 0 && time.Duration(d) < minEscalationInterval {
+			return fmt.Errorf("%s must be zero or at least %s", name, minEscalationInterval)
+		}
+	}
+
+	return notifications.ValidateEscalationConfig(buildRuntimeEscalationConfig(c))
+}
+
+// buildRuntimeEscalationConfig converts the escalation configuration into the
+// notifications runtime one.
+func buildRuntimeEscalationConfig(c *escalationConfig) (conf notifications.EscalationConfig) {
+	return notifications.EscalationConfig{
+		Targets: slices.Clone(c.Targets),
+		Repeat:  time.Duration(c.Repeat),
+		After:   time.Duration(c.After),
+		Enabled: c.Enabled,
+	}
+}
+
+// registerEscalationHandlers registers the HTTP handlers of the alert
+// escalation.
+func (web *webAPI) registerEscalationHandlers() {
+	web.httpReg.Register(http.MethodGet, "/control/notifications/escalation", web.handleGetEscalation)
+	web.httpReg.Register(http.MethodPut, "/control/notifications/escalation/update", web.handlePutEscalation)
+}
+
+// handleGetEscalation is the handler for the GET
+// /control/notifications/escalation HTTP API.
+func (web *webAPI) handleGetEscalation(w http.ResponseWriter, r *http.Request) {
+	config.RLock()
+	resp := config.Notifications.Escalation
+	config.RUnlock()
+
+	aghhttp.WriteJSONResponseOK(r.Context(), web.logger, w, r, resp)
+}
+
+// handlePutEscalation is the handler for the PUT
+// /control/notifications/escalation/update HTTP API.
+func (web *webAPI) handlePutEscalation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req := escalationConfig{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusBadRequest, "json decode: %s", err)
+
+		return
+	}
+
+	err = req.normalize()
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusUnprocessableEntity, "%s", err)
+
+		return
+	}
+
+	config.Lock()
+	config.Notifications.Escalation = req
+	config.Unlock()
+
+	web.logger.InfoContext(ctx, "alert escalation updated", "enabled", req.Enabled)
+	web.confModifier.Apply(ctx)
+
+	if globalContext.notifier != nil {
+		globalContext.notifier.UpdateEscalationConfig(buildRuntimeEscalationConfig(&req))
+	}
+
+	aghhttp.OK(ctx, web.logger, w)
+}
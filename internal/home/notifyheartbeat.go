@@ -0,0 +1,105 @@
+package home
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+	"github.com/AdguardTeam/golibs/timeutil"
+)
+
+// heartbeatConfig is the configuration of the periodic heartbeats.
+type heartbeatConfig struct {
+	// PingURL is the URL of a dead man's switch service, like healthchecks.io,
+	// requested on every heartbeat.  It's not requested if empty.
+	PingURL string `yaml:"ping_url" json:"ping_url"`
+
+	// Interval is the interval between the heartbeats.
+	Interval timeutil.Duration `yaml:"interval" json:"interval"`
+
+	// SendMessage defines if the heartbeat message is delivered to the
+	// notification targets.
+	SendMessage bool `yaml:"send_message" json:"send_message"`
+
+	// Enabled defines if the heartbeats are sent.
+	Enabled bool `yaml:"enabled" json:"enabled"`
+}
+
+// defaultHeartbeatConfig returns the default configuration of the heartbeats.
+func defaultHeartbeatConfig() (c heartbeatConfig) {
+	return heartbeatConfig{
+		Interval: timeutil.Duration(time.Hour),
+	}
+}
+
+// normalize trims the heartbeat configuration and returns an error if it is
+// invalid.
+func (c *heartbeatConfig) normalize() (err error) {
+	c.PingURL = strings.TrimSpace(c.PingURL)
+
+	return notifications.ValidateHeartbeatConfig(buildRuntimeHeartbeatConfig(c))
+}
+
+// buildRuntimeHeartbeatConfig converts the heartbeat configuration into the
+// notifications runtime one.
+func buildRuntimeHeartbeatConfig(c *heartbeatConfig) (conf notifications.HeartbeatConfig) {
+	return notifications.HeartbeatConfig{
+		PingURL:     c.PingURL,
+		Interval:    time.Duration(c.Interval),
+		SendMessage: c.SendMessage,
+		Enabled:     c.Enabled,
+	}
+}
+
+// registerHeartbeatHandlers registers the HTTP handlers of the heartbeats.
+func (web *webAPI) registerHeartbeatHandlers() {
+	web.httpReg.Register(http.MethodGet, "/control/notifications/heartbeat", web.handleGetHeartbeat)
+	web.httpReg.Register(http.MethodPut, "/control/notifications/heartbeat/update", web.handlePutHeartbeat)
+}
+
+// handleGetHeartbeat is the handler for the GET
+// /control/notifications/heartbeat HTTP API.
+func (web *webAPI) handleGetHeartbeat(w http.ResponseWriter, r *http.Request) {
+	config.RLock()
+	resp := config.Notifications.Heartbeat
+	config.RUnlock()
+
+	aghhttp.WriteJSONResponseOK(r.Context(), web.logger, w, r, resp)
+}
+
+// handlePutHeartbeat is the handler for the PUT
+// /control/notifications/heartbeat/update HTTP API.
+func (web *webAPI) handlePutHeartbeat(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req := heartbeatConfig{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusBadRequest, "json decode: %s", err)
+
+		return
+	}
+
+	err = req.normalize()
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusUnprocessableEntity, "%s", err)
+
+		return
+	}
+
+	config.Lock()
+	config.Notifications.Heartbeat = req
+	config.Unlock()
+
+	web.logger.InfoContext(ctx, "notification heartbeat updated", "enabled", req.Enabled)
+	web.confModifier.Apply(ctx)
+
+	if globalContext.notifier != nil {
+		globalContext.notifier.UpdateHeartbeatConfig(buildRuntimeHeartbeatConfig(&req))
+	}
+
+	aghhttp.OK(ctx, web.logger, w)
+}
@@ -0,0 +1,92 @@
+package home
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+)
+
+// Notification history page limits.
+const (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 500
+)
+
+// notificationHistoryResp is the response of the GET
+// /control/notifications/history HTTP API.
+type notificationHistoryResp struct {
+	Entries []notifications.HistoryEntry `json:"entries"`
+	Total   int                          `json:"total"`
+}
+
+// registerHistoryHandlers registers the HTTP handlers of the notification
+// history.
+func (web *webAPI) registerHistoryHandlers() {
+	web.httpReg.Register(http.MethodGet, "/control/notifications/history", web.handleGetHistory)
+}
+
+// handleGetHistory is the handler for the GET /control/notifications/history
+// HTTP API.  It supports the "limit", "offset", "type", "channel", "status",
+// and "since" query parameters.
+func (web *webAPI) handleGetHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if globalContext.notifier == nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusServiceUnavailable, "notifications manager unavailable")
+
+		return
+	}
+
+	q, err := parseHistoryQuery(r.URL.Query())
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusBadRequest, "%s", err)
+
+		return
+	}
+
+	resp := &notificationHistoryResp{}
+	resp.Entries, resp.Total = globalContext.notifier.History(q)
+
+	aghhttp.WriteJSONResponseOK(ctx, web.logger, w, r, resp)
+}
+
+// parseHistoryQuery parses the notification history query from the URL
+// parameters.
+func parseHistoryQuery(params url.Values) (q *notifications.HistoryQuery, err error) {
+	q = &notifications.HistoryQuery{
+		Type:    params.Get("type"),
+		Channel: params.Get("channel"),
+		Status:  notifications.HistoryStatus(params.Get("status")),
+		Limit:   defaultHistoryLimit,
+	}
+
+	for name, dst := range map[string]*int{"limit": &q.Limit, "offset": &q.Offset} {
+		v := params.Get(name)
+		if v == "" {
+			continue
+		}
+
+		*dst, err = strconv.Atoi(v)
+		if err != nil || *dst < 0 {
+			return nil, fmt.Errorf("%s: bad value %q", name, v)
+		}
+	}
+
+	if q.Limit == 0 || q.Limit > maxHistoryLimit {
+		q.Limit = maxHistoryLimit
+	}
+
+	if since := params.Get("since"); since != "" {
+		q.Since, err = time.Parse(time.RFC3339Nano, since)
+		if err != nil {
+			return nil, fmt.Errorf("since: %w", err)
+		}
+	}
+
+	return q, nil
+}
@@ -0,0 +1,74 @@
+package home
+
+import (
+	"context"
+	"io/fs"
+	"log/slog"
+	"os"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+)
+
+// localesDir is the directory of the web UI translation files within the
+// embedded client build as well as within the source tree, which the local
+// frontend files are used from.
+const localesDir = "client/src/__locales"
+
+// newLocalesFS returns the file system with the web UI translation files, which
+// is nil if there are none.  clientBuildFS must not be nil if
+// opts.localFrontend is false.
+func newLocalesFS(ctx context.Context, l *slog.Logger, opts options, clientBuildFS fs.FS) (fsys fs.FS) {
+	if opts.localFrontend {
+		return os.DirFS(localesDir)
+	}
+
+	fsys, err := fs.Sub(clientBuildFS, localesDir)
+	if err != nil {
+		l.WarnContext(ctx, "getting embedded translations", slogutil.KeyError, err)
+
+		return nil
+	}
+
+	return fsys
+}
+
+// loadNotificationLocale returns the translations of the notification messages
+// into lang.  It returns nil, meaning English, if lang is empty or the
+// translations are unavailable.
+func loadNotificationLocale(
+	ctx context.Context,
+	l *slog.Logger,
+	fsys fs.FS,
+	lang string,
+) (loc notifications.Locale) {
+	if fsys == nil || lang == "" {
+		return nil
+	}
+
+	b, err := fs.ReadFile(fsys, lang+".json")
+	if err != nil {
+		l.DebugContext(ctx, "reading notification translations", "lang", lang, slogutil.KeyError, err)
+
+		return nil
+	}
+
+	loc, err = notifications.ParseLocale(b)
+	if err != nil {
+		l.WarnContext(ctx, "loading notification translations", "lang", lang, slogutil.KeyError, err)
+
+		return nil
+	}
+
+	return loc
+}
+
+// updateNotificationLocale applies the translations into lang to the
+// notifications manager, if there is one.
+func updateNotificationLocale(ctx context.Context, l *slog.Logger, lang string) {
+	if globalContext.notifier == nil {
+		return
+	}
+
+	globalContext.notifier.SetLocale(loadNotificationLocale(ctx, l, globalContext.localesFS, lang))
+}
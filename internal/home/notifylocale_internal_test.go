@@ -0,0 +1,40 @@
+package home
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadNotificationLocale(t *testing.T) {
+	fsys := fstest.MapFS{
+		"de.json":  {Data: []byte(`{"notification_label_metric": "Metrik"}`)},
+		"bad.json": {Data: []byte(`{`)},
+	}
+
+	ctx := testutil.ContextWithTimeout(t, testTimeout)
+	l := testLogger
+
+	loc := loadNotificationLocale(ctx, l, fsys, "de")
+	assert.Equal(t, "Metrik", loc["notification_label_metric"])
+
+	assert.Nil(t, loadNotificationLocale(ctx, l, fsys, ""))
+	assert.Nil(t, loadNotificationLocale(ctx, l, fsys, "fr"))
+	assert.Nil(t, loadNotificationLocale(ctx, l, fsys, "bad"))
+	assert.Nil(t, loadNotificationLocale(ctx, l, nil, "de"))
+}
+
+func TestLoadNotificationLocale_sources(t *testing.T) {
+	dir := filepath.Join("..", "..", filepath.FromSlash(localesDir))
+	_, err := os.Stat(dir)
+	require.NoError(t, err)
+
+	ctx := testutil.ContextWithTimeout(t, testTimeout)
+	loc := loadNotificationLocale(ctx, testLogger, os.DirFS(dir), "en")
+	assert.Equal(t, "Metrics", loc["notification_section_metrics"])
+}
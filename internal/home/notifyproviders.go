@@ -2,6 +2,7 @@ package home
 
 import (
 	"context"
+	"time"
 
 	"github.com/AdguardTeam/AdGuardHome/internal/dnsforward"
 	"github.com/AdguardTeam/AdGuardHome/internal/filtering"
@@ -34,6 +35,23 @@ func (a *protectionAdapter) SetProtectionEnabled(enabled bool) error {
 	return nil
 }
 
+// type check
+var _ notifications.ProtectionPauser = (*protectionAdapter)(nil)
+
+// PauseProtection implements the [notifications.ProtectionPauser] interface for
+// *protectionAdapter.  The protection is resumed by the DNS server once the
+// time is up.
+func (a *protectionAdapter) PauseProtection(d time.Duration) (err error) {
+	if a.filters == nil {
+		return errors.Error("filtering is not initialized")
+	}
+
+	until := time.Now().Add(d)
+	a.filters.SetProtectionStatus(false, &until)
+
+	return nil
+}
+
 // youtubeAdapter implements [notifications.YouTubeProvider] on top of the
 // package-level YouTube ad-blocking config and manager.
 type youtubeAdapter struct{}
@@ -135,6 +153,16 @@ func (certAdapter) IssueCertificateNow() error {
 	return err
 }
 
+// clientWakerAdapter implements [notifications.ClientWaker] on top of the
+// clients container.
+type clientWakerAdapter struct {
+	clients *clientsContainer
+}
+
+func (a clientWakerAdapter) WakeClient(ctx context.Context, name string) error {
+	return a.clients.wake(ctx, name)
+}
+
 func injectNotificationProviders() {
 	n := globalContext.notifier
 	if n == nil {
@@ -144,6 +172,9 @@ func injectNotificationProviders() {
 	var sp notifications.StatsProvider
 	if globalContext.stats != nil {
 		sp = globalContext.stats
+		n.SetBlockedCountsProvider(globalContext.stats)
+		n.SetDNSPerfProvider(globalContext.stats)
+		n.SetStatsSummaryProvider(globalContext.stats)
 	}
 
 	var fp notifications.FilterProvider
@@ -170,4 +201,5 @@ func injectNotificationProviders() {
 
 	n.SetYouTubeProvider(youtubeAdapter{})
 	n.SetCertProvider(certAdapter{})
+	n.SetClientWaker(clientWakerAdapter{clients: &globalContext.clients})
 }
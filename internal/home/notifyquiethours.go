@@ -0,0 +1,182 @@
+package home
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+)
+
+// quietHoursConfig is the configuration of the notification quiet hours.
+type quietHoursConfig struct {
+	// Timezone is the IANA name of the time zone of Schedule.  The local time
+	// zone is used if it's empty.
+	Timezone string `yaml:"timezone" json:"timezone"`
+
+	// Schedule are the quiet hours windows.
+	Schedule []quietWindowConfig `yaml:"schedule" json:"schedule"`
+
+	// Critical are the events delivered during the quiet hours in the format
+	// of the routing rules keys.  Only the alerts are critical if it's nil.
+	Critical []string `yaml:"critical" json:"critical"`
+
+	// Enabled defines if the quiet hours are applied.
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// Queue defines if the non-critical events are delivered as a summary
+	// after the quiet hours instead of being dropped.
+	Queue bool `yaml:"queue" json:"queue"`
+}
+
+// quietWindowConfig is a quiet hours window repeated on Days.  The window
+// continues into the next day if End isn't after Start.
+type quietWindowConfig struct {
+	// Days are the lowercase three-letter names of the weekdays, like "mon".
+	Days []string `yaml:"days" json:"days"`
+
+	// Start is the start of the window in the "15:04" format.
+	Start string `yaml:"start" json:"start"`
+
+	// End is the end of the window in the "15:04" format.
+	End string `yaml:"end" json:"end"`
+}
+
+// weekdayNames are the names of the weekdays in [quietWindowConfig.Days].
+var weekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// normalize trims the quiet hours configuration and returns an error if it is
+// invalid.
+func (c *quietHoursConfig) normalize() (err error) {
+	c.Timezone = strings.TrimSpace(c.Timezone)
+	if c.Timezone != "" {
+		_, err = time.LoadLocation(c.Timezone)
+		if err != nil {
+			return fmt.Errorf("timezone: %w", err)
+		}
+	}
+
+	for i := range c.Schedule {
+		w := &c.Schedule[i]
+		for j, d := range w.Days {
+			w.Days[j] = strings.ToLower(strings.TrimSpace(d))
+			if !slices.Contains(weekdayNames, w.Days[j]) {
+				return fmt.Errorf("schedule at index %d: unknown day %q", i, d)
+			}
+		}
+
+		if _, err = parseClock(w.Start); err != nil {
+			return fmt.Errorf("schedule at index %d: start: %w", i, err)
+		} else if _, err = parseClock(w.End); err != nil {
+			return fmt.Errorf("schedule at index %d: end: %w", i, err)
+		}
+	}
+
+	for _, key := range c.Critical {
+		err = notifications.ValidateRoutes(map[string][]string{key: nil})
+		if err != nil {
+			return fmt.Errorf("critical: %w", err)
+		}
+	}
+
+	if c.Enabled && len(c.Schedule) == 0 {
+		return fmt.Errorf("schedule is required when quiet hours are enabled")
+	}
+
+	return nil
+}
+
+// parseClock parses the time of day in the "15:04" format into the offset
+// from midnight.
+func parseClock(s string) (d time.Duration, err error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("bad time of day %q, want hh:mm", s)
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// buildRuntimeQuietHoursConfig converts the quiet hours configuration into the
+// notifications runtime one.  c must be normalized.
+func buildRuntimeQuietHoursConfig(c *quietHoursConfig) (conf notifications.QuietHoursConfig) {
+	conf = notifications.QuietHoursConfig{
+		Critical: slices.Clone(c.Critical),
+		Enabled:  c.Enabled,
+		Queue:    c.Queue,
+	}
+
+	if c.Timezone != "" {
+		// The time zone is validated in normalize.
+		conf.Location, _ = time.LoadLocation(c.Timezone)
+	}
+
+	for _, w := range c.Schedule {
+		start, _ := parseClock(w.Start)
+		end, _ := parseClock(w.End)
+		for _, d := range w.Days {
+			conf.Windows = append(conf.Windows, notifications.QuietWindow{
+				Weekday: time.Weekday(slices.Index(weekdayNames, d)),
+				Start:   start,
+				End:     end,
+			})
+		}
+	}
+
+	return conf
+}
+
+// registerQuietHoursHandlers registers the HTTP handlers of the notification
+// quiet hours.
+func (web *webAPI) registerQuietHoursHandlers() {
+	web.httpReg.Register(http.MethodGet, "/control/notifications/quiet_hours", web.handleGetQuietHours)
+	web.httpReg.Register(http.MethodPut, "/control/notifications/quiet_hours/update", web.handlePutQuietHours)
+}
+
+// handleGetQuietHours is the handler for the GET
+// /control/notifications/quiet_hours HTTP API.
+func (web *webAPI) handleGetQuietHours(w http.ResponseWriter, r *http.Request) {
+	config.RLock()
+	resp := config.Notifications.QuietHours
+	config.RUnlock()
+
+	aghhttp.WriteJSONResponseOK(r.Context(), web.logger, w, r, resp)
+}
+
+// handlePutQuietHours is the handler for the PUT
+// /control/notifications/quiet_hours/update HTTP API.
+func (web *webAPI) handlePutQuietHours(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req := quietHoursConfig{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusBadRequest, "json decode: %s", err)
+
+		return
+	}
+
+	err = req.normalize()
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusUnprocessableEntity, "%s", err)
+
+		return
+	}
+
+	config.Lock()
+	config.Notifications.QuietHours = req
+	config.Unlock()
+
+	web.logger.InfoContext(ctx, "notification quiet hours updated", "enabled", req.Enabled)
+	web.confModifier.Apply(ctx)
+
+	if globalContext.notifier != nil {
+		globalContext.notifier.UpdateQuietHoursConfig(buildRuntimeQuietHoursConfig(&req))
+	}
+
+	aghhttp.OK(ctx, web.logger, w)
+}
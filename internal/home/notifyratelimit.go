@@ -0,0 +1,105 @@
+package home
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+	"github.com/AdguardTeam/golibs/timeutil"
+)
+
+// rateLimitConfig is the configuration of the global limit of the
+// notifications.
+type rateLimitConfig struct {
+	// Period is the period the notifications are counted over.
+	Period timeutil.Duration `yaml:"period" json:"period"`
+
+	// Limit is the maximum number of the notifications delivered within
+	// Period.  The notice about the suppressed notifications isn't counted.
+	Limit int `yaml:"limit" json:"limit"`
+
+	// Enabled defines if the notifications are limited.
+	Enabled bool `yaml:"enabled" json:"enabled"`
+}
+
+// defaultRateLimitConfig returns the default configuration of the global limit
+// of the notifications.
+func defaultRateLimitConfig() (c rateLimitConfig) {
+	return rateLimitConfig{
+		Period: timeutil.Duration(time.Hour),
+		Limit:  30,
+	}
+}
+
+// normalize returns an error if the rate limit configuration is invalid.
+func (c *rateLimitConfig) normalize() (err error) {
+	return notifications.ValidateRateLimitConfig(buildRuntimeRateLimitConfig(c))
+}
+
+// buildRuntimeRateLimitConfig converts the rate limit configuration into the
+// notifications runtime one.
+func buildRuntimeRateLimitConfig(c *rateLimitConfig) (conf notifications.RateLimitConfig) {
+	return notifications.RateLimitConfig{
+		Period:  time.Duration(c.Period),
+		Limit:   c.Limit,
+		Enabled: c.Enabled,
+	}
+}
+
+// registerRateLimitHandlers registers the HTTP handlers of the global limit of
+// the notifications.
+func (web *webAPI) registerRateLimitHandlers() {
+	web.httpReg.Register(http.MethodGet, "/control/notifications/rate_limit", web.handleGetRateLimit)
+	web.httpReg.Register(http.MethodPut, "/control/notifications/rate_limit/update", web.handlePutRateLimit)
+}
+
+// handleGetRateLimit is the handler for the GET
+// /control/notifications/rate_limit HTTP API.
+func (web *webAPI) handleGetRateLimit(w http.ResponseWriter, r *http.Request) {
+	config.RLock()
+	resp := config.Notifications.RateLimit
+	config.RUnlock()
+
+	aghhttp.WriteJSONResponseOK(r.Context(), web.logger, w, r, resp)
+}
+
+// handlePutRateLimit is the handler for the PUT
+// /control/notifications/rate_limit/update HTTP API.
+func (web *webAPI) handlePutRateLimit(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req := defaultRateLimitConfig()
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusBadRequest, "json decode: %s", err)
+
+		return
+	}
+
+	err = req.normalize()
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusUnprocessableEntity, "%s", err)
+
+		return
+	}
+
+	config.Lock()
+	config.Notifications.RateLimit = req
+	config.Unlock()
+
+	web.logger.InfoContext(
+		ctx,
+		"notification rate limit updated",
+		"enabled", req.Enabled,
+		"limit", req.Limit,
+	)
+	web.confModifier.Apply(ctx)
+
+	if globalContext.notifier != nil {
+		globalContext.notifier.UpdateRateLimitConfig(buildRuntimeRateLimitConfig(&req))
+	}
+
+	aghhttp.OK(ctx, web.logger, w)
+}
@@ -0,0 +1,88 @@
+package home
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+)
+
+// notificationRoutesJSON is the JSON representation of the notification
+// routing rules.
+type notificationRoutesJSON struct {
+	// Routes map the event types, optionally with metrics like "alert:disk",
+	// to the names of the targets.
+	Routes map[string][]string `json:"routes"`
+
+	// EventTypes are the supported event types.  It is ignored on update.
+	EventTypes []string `json:"event_types,omitempty"`
+
+	// Targets are the supported target names.  It is ignored on update.
+	Targets []string `json:"targets,omitempty"`
+}
+
+// registerRouteHandlers registers the HTTP handlers of the notification
+// routing rules.
+func (web *webAPI) registerRouteHandlers() {
+	web.httpReg.Register(http.MethodGet, "/control/notifications/routes", web.handleGetRoutes)
+	web.httpReg.Register(http.MethodPut, "/control/notifications/routes/update", web.handlePutRoutes)
+}
+
+// handleGetRoutes is the handler for the GET /control/notifications/routes
+// HTTP API.
+func (web *webAPI) handleGetRoutes(w http.ResponseWriter, r *http.Request) {
+	config.RLock()
+	routes := maps.Clone(config.Notifications.Routes)
+	config.RUnlock()
+
+	if routes == nil {
+		routes = map[string][]string{}
+	}
+
+	aghhttp.WriteJSONResponseOK(r.Context(), web.logger, w, r, &notificationRoutesJSON{
+		Routes:     routes,
+		EventTypes: notifications.RouteEventTypes,
+		Targets:    notifications.TargetNames,
+	})
+}
+
+// handlePutRoutes is the handler for the PUT
+// /control/notifications/routes/update HTTP API.
+func (web *webAPI) handlePutRoutes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req := &notificationRoutesJSON{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusBadRequest, "json decode: %s", err)
+
+		return
+	}
+
+	err = notifications.ValidateRoutes(req.Routes)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusUnprocessableEntity, "%s", err)
+
+		return
+	}
+
+	config.Lock()
+	config.Notifications.Routes = req.Routes
+	config.Unlock()
+
+	web.logger.InfoContext(ctx, "notification routes updated", "count", len(req.Routes))
+	web.confModifier.Apply(ctx)
+
+	if globalContext.notifier != nil {
+		err = globalContext.notifier.UpdateRoutes(req.Routes)
+		if err != nil {
+			aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusInternalServerError, "%s", err)
+
+			return
+		}
+	}
+
+	aghhttp.OK(ctx, web.logger, w)
+}
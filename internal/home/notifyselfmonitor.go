@@ -0,0 +1,114 @@
+package home
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+	"github.com/AdguardTeam/golibs/timeutil"
+)
+
+// selfMonitorConfig is the configuration of the alerts about the resource
+// usage of the AdGuard Home process itself.  A zero limit means that the
+// corresponding metric isn't monitored.
+type selfMonitorConfig struct {
+	// MaxMemoryMB is the resident set size of the process in mebibytes.
+	MaxMemoryMB uint64 `yaml:"max_memory_mb" json:"max_memory_mb"`
+
+	// MaxGoroutines is the number of the goroutines.
+	MaxGoroutines int `yaml:"max_goroutines" json:"max_goroutines"`
+
+	// MaxOpenFiles is the number of the open file descriptors.
+	MaxOpenFiles int `yaml:"max_open_files" json:"max_open_files"`
+
+	// MaxGCPause is the longest garbage collection pause between the checks.
+	MaxGCPause timeutil.Duration `yaml:"max_gc_pause" json:"max_gc_pause"`
+
+	// Enabled defines if the self-monitoring alerts are sent.
+	Enabled bool `yaml:"enabled" json:"enabled"`
+}
+
+// defaultSelfMonitorConfig returns the default configuration of the
+// self-monitoring alerts.
+func defaultSelfMonitorConfig() (c selfMonitorConfig) {
+	return selfMonitorConfig{
+		MaxMemoryMB:   1024,
+		MaxGoroutines: 10_000,
+		MaxOpenFiles:  4096,
+		MaxGCPause:    timeutil.Duration(100 * time.Millisecond),
+	}
+}
+
+// normalize returns an error if the self-monitoring configuration is invalid.
+func (c *selfMonitorConfig) normalize() (err error) {
+	return notifications.ValidateSelfMonitorConfig(buildRuntimeSelfMonitorConfig(c))
+}
+
+// buildRuntimeSelfMonitorConfig converts the self-monitoring configuration
+// into the notifications runtime one.
+func buildRuntimeSelfMonitorConfig(c *selfMonitorConfig) (conf notifications.SelfMonitorConfig) {
+	return notifications.SelfMonitorConfig{
+		MaxRSS:        c.MaxMemoryMB << 20,
+		MaxGoroutines: c.MaxGoroutines,
+		MaxOpenFiles:  c.MaxOpenFiles,
+		MaxGCPause:    time.Duration(c.MaxGCPause),
+		Enabled:       c.Enabled,
+	}
+}
+
+// registerSelfMonitorHandlers registers the HTTP handlers of the
+// self-monitoring alerts.
+func (web *webAPI) registerSelfMonitorHandlers() {
+	web.httpReg.Register(http.MethodGet, "/control/notifications/self_monitor", web.handleGetSelfMonitor)
+	web.httpReg.Register(
+		http.MethodPut,
+		"/control/notifications/self_monitor/update",
+		web.handlePutSelfMonitor,
+	)
+}
+
+// handleGetSelfMonitor is the handler for the GET
+// /control/notifications/self_monitor HTTP API.
+func (web *webAPI) handleGetSelfMonitor(w http.ResponseWriter, r *http.Request) {
+	config.RLock()
+	resp := config.Notifications.SelfMonitor
+	config.RUnlock()
+
+	aghhttp.WriteJSONResponseOK(r.Context(), web.logger, w, r, resp)
+}
+
+// handlePutSelfMonitor is the handler for the PUT
+// /control/notifications/self_monitor/update HTTP API.
+func (web *webAPI) handlePutSelfMonitor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req := selfMonitorConfig{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusBadRequest, "json decode: %s", err)
+
+		return
+	}
+
+	err = req.normalize()
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusUnprocessableEntity, "%s", err)
+
+		return
+	}
+
+	config.Lock()
+	config.Notifications.SelfMonitor = req
+	config.Unlock()
+
+	web.logger.InfoContext(ctx, "self-monitoring alerts updated", "enabled", req.Enabled)
+	web.confModifier.Apply(ctx)
+
+	if globalContext.notifier != nil {
+		globalContext.notifier.UpdateSelfMonitorConfig(buildRuntimeSelfMonitorConfig(&req))
+	}
+
+	aghhttp.OK(ctx, web.logger, w)
+}
@@ -0,0 +1,152 @@
+package home
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+)
+
+// statsReportConfig is the configuration of the scheduled statistics summary.
+type statsReportConfig struct {
+	// Timezone is the IANA name of the time zone of the schedule.  The local
+	// time zone is used if it's empty.
+	Timezone string `yaml:"timezone" json:"timezone"`
+
+	// Period is either "daily" or "weekly".
+	Period notifications.StatsReportPeriod `yaml:"period" json:"period"`
+
+	// Time is the time of day the summary is sent at in the "15:04" format.
+	Time string `yaml:"time" json:"time"`
+
+	// Day is the lowercase three-letter name of the weekday the weekly summary
+	// is sent on, like "mon".
+	Day string `yaml:"day" json:"day"`
+
+	// Enabled defines if the summary is sent.
+	Enabled bool `yaml:"enabled" json:"enabled"`
+}
+
+// defaultStatsReportConfig returns the default configuration of the scheduled
+// statistics summary.
+func defaultStatsReportConfig() (c statsReportConfig) {
+	return statsReportConfig{
+		Period: notifications.StatsReportDaily,
+		Time:   "09:00",
+		Day:    "mon",
+	}
+}
+
+// normalize trims the statistics summary configuration and returns an error if
+// it is invalid.
+func (c *statsReportConfig) normalize() (err error) {
+	c.Timezone = strings.TrimSpace(c.Timezone)
+	if c.Timezone != "" {
+		_, err = time.LoadLocation(c.Timezone)
+		if err != nil {
+			return fmt.Errorf("timezone: %w", err)
+		}
+	}
+
+	switch c.Period {
+	case notifications.StatsReportDaily, notifications.StatsReportWeekly:
+		// Go on.
+	default:
+		return fmt.Errorf("period: unknown value %q, want daily or weekly", c.Period)
+	}
+
+	if _, err = parseClock(c.Time); err != nil {
+		return fmt.Errorf("time: %w", err)
+	}
+
+	c.Day = strings.ToLower(strings.TrimSpace(c.Day))
+	if c.Period == notifications.StatsReportWeekly && !slices.Contains(weekdayNames, c.Day) {
+		return fmt.Errorf("day: unknown day %q", c.Day)
+	}
+
+	return nil
+}
+
+// buildRuntimeStatsReportConfig converts the statistics summary configuration
+// into the notifications runtime one.  c must be normalized.
+func buildRuntimeStatsReportConfig(c *statsReportConfig) (conf notifications.StatsReportConfig) {
+	at, _ := parseClock(c.Time)
+	conf = notifications.StatsReportConfig{
+		Period:  c.Period,
+		At:      at,
+		Weekday: time.Weekday(max(slices.Index(weekdayNames, c.Day), 0)),
+		Enabled: c.Enabled,
+	}
+
+	if c.Timezone != "" {
+		// The time zone is validated in normalize.
+		conf.Location, _ = time.LoadLocation(c.Timezone)
+	}
+
+	return conf
+}
+
+// registerStatsReportHandlers registers the HTTP handlers of the scheduled
+// statistics summary.
+func (web *webAPI) registerStatsReportHandlers() {
+	web.httpReg.Register(http.MethodGet, "/control/notifications/stats_report", web.handleGetStatsReport)
+	web.httpReg.Register(
+		http.MethodPut,
+		"/control/notifications/stats_report/update",
+		web.handlePutStatsReport,
+	)
+}
+
+// handleGetStatsReport is the handler for the GET
+// /control/notifications/stats_report HTTP API.
+func (web *webAPI) handleGetStatsReport(w http.ResponseWriter, r *http.Request) {
+	config.RLock()
+	resp := config.Notifications.StatsReport
+	config.RUnlock()
+
+	aghhttp.WriteJSONResponseOK(r.Context(), web.logger, w, r, resp)
+}
+
+// handlePutStatsReport is the handler for the PUT
+// /control/notifications/stats_report/update HTTP API.
+func (web *webAPI) handlePutStatsReport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req := defaultStatsReportConfig()
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusBadRequest, "json decode: %s", err)
+
+		return
+	}
+
+	err = req.normalize()
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusUnprocessableEntity, "%s", err)
+
+		return
+	}
+
+	config.Lock()
+	config.Notifications.StatsReport = req
+	config.Unlock()
+
+	web.logger.InfoContext(
+		ctx,
+		"statistics report updated",
+		"enabled", req.Enabled,
+		"period", req.Period,
+	)
+	web.confModifier.Apply(ctx)
+
+	if globalContext.notifier != nil {
+		globalContext.notifier.UpdateStatsReportConfig(buildRuntimeStatsReportConfig(&req))
+	}
+
+	aghhttp.OK(ctx, web.logger, w)
+}
@@ -0,0 +1,50 @@
+package home
+
+import (
+	"context"
+	"io/fs"
+	"syscall"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+	"github.com/AdguardTeam/AdGuardHome/internal/stats"
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// newStorageErrorHandler returns the storage error handler of the component
+// keeping its data at path, which forwards the errors to the notifications
+// manager, if there is one.
+func newStorageErrorHandler(
+	component notifications.StorageComponent,
+	path string,
+) (h func(ctx context.Context, err error)) {
+	return func(ctx context.Context, err error) {
+		notifier := globalContext.notifier
+		if notifier == nil {
+			return
+		}
+
+		failure := notifications.StorageFailure{
+			Component: component,
+			Reason:    storageFailureReason(err),
+			Path:      path,
+			Error:     err.Error(),
+		}
+
+		// Don't block the writes of the component.
+		go notifier.NotifyStorageError(context.WithoutCancel(ctx), failure)
+	}
+}
+
+// storageFailureReason returns the kind of the storage failure err.
+func storageFailureReason(err error) (reason notifications.StorageFailureReason) {
+	switch {
+	case errors.Is(err, stats.ErrCorrupted):
+		return notifications.StorageFailureReasonCorruption
+	case errors.Is(err, syscall.ENOSPC), errors.Is(err, syscall.EDQUOT):
+		return notifications.StorageFailureReasonDiskFull
+	case errors.Is(err, fs.ErrPermission), errors.Is(err, syscall.EROFS):
+		return notifications.StorageFailureReasonPermission
+	default:
+		return notifications.StorageFailureReasonOther
+	}
+}
@@ -0,0 +1,43 @@
+package home
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+	"github.com/AdguardTeam/AdGuardHome/internal/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorageFailureReason(t *testing.T) {
+	testCases := []struct {
+		err  error
+		name string
+		want notifications.StorageFailureReason
+	}{{
+		err:  &fs.PathError{Op: "write", Path: "querylog.json", Err: syscall.ENOSPC},
+		name: "disk_full",
+		want: notifications.StorageFailureReasonDiskFull,
+	}, {
+		err:  fmt.Errorf("creating file: %w", os.ErrPermission),
+		name: "permission",
+		want: notifications.StorageFailureReasonPermission,
+	}, {
+		err:  fmt.Errorf("%w: decoding unit 1: unexpected EOF", stats.ErrCorrupted),
+		name: "corruption",
+		want: notifications.StorageFailureReasonCorruption,
+	}, {
+		err:  assert.AnError,
+		name: "other",
+		want: notifications.StorageFailureReasonOther,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, storageFailureReason(tc.err))
+		})
+	}
+}
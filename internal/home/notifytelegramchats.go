@@ -0,0 +1,80 @@
+package home
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+)
+
+// telegramChatConfig is the configuration of an additional chat receiving
+// the Telegram notifications.
+type telegramChatConfig struct {
+	// ID is the ID of the chat.
+	ID string `yaml:"id" json:"id"`
+
+	// Events are the events sent to the chat in the format of the routing
+	// rules keys.  All events are sent if it's empty.
+	Events []string `yaml:"events" json:"events"`
+
+	// MessageThreadID is the ID of the forum topic in the chat.
+	MessageThreadID int64 `yaml:"message_thread_id,omitempty" json:"message_thread_id,omitempty"`
+
+	// Enabled defines if the notifications are sent to the chat.
+	Enabled bool `yaml:"enabled" json:"enabled"`
+}
+
+// normalizeTelegramChats trims the additional chats and returns an error if
+// any of them is invalid.
+func normalizeTelegramChats(chats []telegramChatConfig) (err error) {
+	for i := range chats {
+		c := &chats[i]
+		c.ID = strings.TrimSpace(c.ID)
+		if c.ID == "" {
+			return fmt.Errorf("chats at index %d: id is required", i)
+		} else if c.MessageThreadID < 0 {
+			return fmt.Errorf("chats at index %d: message_thread_id must not be negative", i)
+		}
+
+		for _, key := range c.Events {
+			err = notifications.ValidateRoutes(map[string][]string{key: nil})
+			if err != nil {
+				return fmt.Errorf("chats at index %d: events: %w", i, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// hasEnabledTelegramChats returns true if any of the additional chats is
+// enabled.
+func hasEnabledTelegramChats(chats []telegramChatConfig) (ok bool) {
+	return slices.ContainsFunc(chats, func(c telegramChatConfig) (enabled bool) { return c.Enabled })
+}
+
+// telegramChatsEqual returns true if a and b are equal.
+func telegramChatsEqual(a, b []telegramChatConfig) (ok bool) {
+	return slices.EqualFunc(a, b, func(x, y telegramChatConfig) (eq bool) {
+		return x.ID == y.ID &&
+			x.MessageThreadID == y.MessageThreadID &&
+			x.Enabled == y.Enabled &&
+			slices.Equal(x.Events, y.Events)
+	})
+}
+
+// buildRuntimeTelegramChats converts the additional chats into the
+// notifications runtime ones.
+func buildRuntimeTelegramChats(chats []telegramChatConfig) (rt []notifications.TelegramChat) {
+	for _, c := range chats {
+		rt = append(rt, notifications.TelegramChat{
+			ID:              c.ID,
+			Events:          slices.Clone(c.Events),
+			MessageThreadID: c.MessageThreadID,
+			Enabled:         c.Enabled,
+		})
+	}
+
+	return rt
+}
@@ -0,0 +1,150 @@
+package home
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+)
+
+// notificationTemplatesJSON is the JSON representation of the message
+// templates.
+type notificationTemplatesJSON struct {
+	// Templates are the Go text/template message templates by event type.
+	Templates map[string]string `json:"templates"`
+
+	// EventTypes are the supported event types.  It is ignored on update.
+	EventTypes []string `json:"event_types,omitempty"`
+}
+
+// validateNotificationTemplates trims the message templates, removes the
+// empty ones, and returns an error if any of the rest is invalid.
+func validateNotificationTemplates(ctx context.Context, tmpls map[string]string) (err error) {
+	for _, typ := range slices.Sorted(maps.Keys(tmpls)) {
+		text := strings.TrimSpace(tmpls[typ])
+		if text == "" {
+			delete(tmpls, typ)
+
+			continue
+		}
+
+		_, err = notifications.ValidateTemplate(ctx, typ, text)
+		if err != nil {
+			return fmt.Errorf("templates: %w", err)
+		}
+
+		tmpls[typ] = text
+	}
+
+	return nil
+}
+
+// registerTemplateHandlers registers the HTTP handlers of the notification
+// message templates.
+func (web *webAPI) registerTemplateHandlers() {
+	web.httpReg.Register(http.MethodGet, "/control/notifications/templates", web.handleGetTemplates)
+	web.httpReg.Register(http.MethodPut, "/control/notifications/templates/update", web.handlePutTemplates)
+	web.httpReg.Register(http.MethodPost, "/control/notifications/templates/validate", web.handlePostTemplateValidate)
+}
+
+// handleGetTemplates is the handler for the GET
+// /control/notifications/templates HTTP API.
+func (web *webAPI) handleGetTemplates(w http.ResponseWriter, r *http.Request) {
+	config.RLock()
+	tmpls := maps.Clone(config.Notifications.Templates)
+	config.RUnlock()
+
+	if tmpls == nil {
+		tmpls = map[string]string{}
+	}
+
+	aghhttp.WriteJSONResponseOK(r.Context(), web.logger, w, r, &notificationTemplatesJSON{
+		Templates:  tmpls,
+		EventTypes: notifications.TemplateEventTypes,
+	})
+}
+
+// handlePutTemplates is the handler for the PUT
+// /control/notifications/templates/update HTTP API.
+func (web *webAPI) handlePutTemplates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req := &notificationTemplatesJSON{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusBadRequest, "json decode: %s", err)
+
+		return
+	}
+
+	err = validateNotificationTemplates(ctx, req.Templates)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusUnprocessableEntity, "%s", err)
+
+		return
+	}
+
+	config.Lock()
+	config.Notifications.Templates = req.Templates
+	config.Unlock()
+
+	web.logger.InfoContext(ctx, "notification templates updated", "count", len(req.Templates))
+	web.confModifier.Apply(ctx)
+
+	if globalContext.notifier != nil {
+		err = globalContext.notifier.UpdateTemplates(req.Templates)
+		if err != nil {
+			aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusInternalServerError, "%s", err)
+
+			return
+		}
+	}
+
+	aghhttp.OK(ctx, web.logger, w)
+}
+
+// templateValidateReq is the request of the POST
+// /control/notifications/templates/validate HTTP API.
+type templateValidateReq struct {
+	Type     string `json:"type"`
+	Template string `json:"template"`
+}
+
+// templateValidateResp is the response of the POST
+// /control/notifications/templates/validate HTTP API.
+type templateValidateResp struct {
+	Preview string `json:"preview,omitempty"`
+	Error   string `json:"error,omitempty"`
+	Valid   bool   `json:"valid"`
+}
+
+// handlePostTemplateValidate is the handler for the POST
+// /control/notifications/templates/validate HTTP API.  It renders the
+// template with sample data.
+func (web *webAPI) handlePostTemplateValidate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req := &templateValidateReq{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusBadRequest, "json decode: %s", err)
+
+		return
+	}
+
+	resp := &templateValidateResp{}
+	resp.Preview, err = notifications.ValidateTemplate(ctx, req.Type, req.Template)
+	if err != nil {
+		resp.Error = err.Error()
+	} else {
+		resp.Valid = true
+	}
+
+	aghhttp.WriteJSONResponseOK(ctx, web.logger, w, r, resp)
+}
@@ -0,0 +1,98 @@
+package home
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+)
+
+// notificationTestReq is the request of the POST
+// /control/notifications/{channel}/test HTTP API.
+type notificationTestReq struct {
+	Message string `json:"message"`
+}
+
+// notificationTestResp is the response of the POST
+// /control/notifications/{channel}/test HTTP API.
+type notificationTestResp struct {
+	Channel string                   `json:"channel"`
+	Results []notificationTestResult `json:"results"`
+	OK      bool                     `json:"ok"`
+}
+
+// notificationTestResult is the diagnostics of the test notification sent to a
+// single target.
+type notificationTestResult struct {
+	Target        string               `json:"target"`
+	Error         string               `json:"error,omitempty"`
+	ProviderError string               `json:"provider_error,omitempty"`
+	Latency       aghhttp.JSONDuration `json:"latency"`
+	Status        int                  `json:"status,omitempty"`
+}
+
+// newNotificationTestResp converts the test results of the channel into the
+// response.
+func newNotificationTestResp(channel string, results []notifications.TestResult) (resp *notificationTestResp) {
+	resp = &notificationTestResp{
+		Channel: channel,
+		Results: make([]notificationTestResult, 0, len(results)),
+		OK:      true,
+	}
+
+	for _, res := range results {
+		resp.Results = append(resp.Results, notificationTestResult{
+			Target:        res.Target,
+			Error:         res.Error,
+			ProviderError: res.ProviderError,
+			Latency:       aghhttp.JSONDuration(res.Latency),
+			Status:        res.Status,
+		})
+
+		resp.OK = resp.OK && res.Error == ""
+	}
+
+	return resp
+}
+
+// newNotificationTestHandler returns the handler for the POST
+// /control/notifications/{channel}/test HTTP API.  The response status is 502
+// Bad Gateway if the delivery to any of the targets has failed.
+func (web *webAPI) newNotificationTestHandler(channel string) (h http.HandlerFunc) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		if globalContext.notifier == nil {
+			aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusServiceUnavailable, "notifications manager unavailable")
+
+			return
+		}
+
+		req := &notificationTestReq{}
+		err := json.NewDecoder(r.Body).Decode(req)
+		if err != nil && !errors.Is(err, io.EOF) {
+			aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusBadRequest, "json decode: %s", err)
+
+			return
+		}
+
+		results, err := globalContext.notifier.SendTest(ctx, channel, req.Message)
+		if err != nil {
+			aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusUnprocessableEntity, "%s test: %s", channel, err)
+
+			return
+		}
+
+		resp := newNotificationTestResp(channel, results)
+		code := http.StatusOK
+		if !resp.OK {
+			web.logger.WarnContext(ctx, "notification test failed", "channel", channel)
+			code = http.StatusBadGateway
+		}
+
+		aghhttp.WriteJSONResponse(ctx, web.logger, w, r, code, resp)
+	}
+}
@@ -0,0 +1,217 @@
+package home
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/aghos"
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+	"github.com/AdguardTeam/AdGuardHome/internal/updater"
+	"github.com/AdguardTeam/AdGuardHome/internal/version"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+	"github.com/google/renameio/v2/maybe"
+)
+
+// updateNotifiedFilename is the name of the file in the data directory
+// containing the last version the update notification has been sent for.
+const updateNotifiedFilename = "notifications_update_version"
+
+const (
+	// updateNotifyCheckInterval is the interval of checking for a new version
+	// to notify about.  The version information is cached by the updater, so
+	// the update server is requested less often.
+	updateNotifyCheckInterval = time.Hour
+
+	// updateRemindInterval is the interval of the repeated notifications
+	// about the same version, unless those are sent once per version.
+	updateRemindInterval = 24 * time.Hour
+)
+
+// updateNotificationsConfig is the configuration of the notifications about
+// the new releases.
+type updateNotificationsConfig struct {
+	// OncePerVersion defines if each new version is only notified about once.
+	// Otherwise, the notification is repeated daily until updated.
+	OncePerVersion bool `yaml:"once_per_version" json:"once_per_version"`
+
+	// Enabled defines if the new releases are notified about.
+	Enabled bool `yaml:"enabled" json:"enabled"`
+}
+
+// updateNotifier decides if the notification about a new version is due.
+type updateNotifier struct {
+	// mu protects lastVersion and lastTime.
+	mu *sync.Mutex
+
+	// lastTime is the time of the last notification.
+	lastTime time.Time
+
+	// path is the path to the file storing lastVersion.
+	path string
+
+	// lastVersion is the last version notified about.
+	lastVersion string
+}
+
+// newUpdateNotifier returns a new *updateNotifier storing its state in the file
+// at path.
+func newUpdateNotifier(ctx context.Context, web *webAPI, path string) (n *updateNotifier) {
+	n = &updateNotifier{
+		mu:   &sync.Mutex{},
+		path: path,
+	}
+
+	data, err := os.ReadFile(path)
+	if err == nil {
+		n.lastVersion = strings.TrimSpace(string(data))
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		web.logger.WarnContext(ctx, "reading notified update version", slogutil.KeyError, err)
+	}
+
+	return n
+}
+
+// isDue returns true if the notification about newVersion should be sent now
+// according to conf, and records it as sent.
+func (n *updateNotifier) isDue(conf updateNotificationsConfig, newVersion string, now time.Time) (ok bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if newVersion == n.lastVersion {
+		if conf.OncePerVersion || now.Sub(n.lastTime) < updateRemindInterval {
+			return false
+		}
+	}
+
+	n.lastVersion, n.lastTime = newVersion, now
+
+	return true
+}
+
+// store writes the last version notified about into the file.
+func (n *updateNotifier) store() (err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return maybe.WriteFile(n.path, []byte(n.lastVersion+"\n"), aghos.DefaultPermFile)
+}
+
+// runUpdateNotifications periodically checks for a new version and notifies
+// about it.  It is intended to be used as a goroutine.
+func (web *webAPI) runUpdateNotifications(ctx context.Context) {
+	defer slogutil.RecoverAndLog(ctx, web.logger)
+
+	if web.conf.disableUpdate {
+		return
+	}
+
+	n := newUpdateNotifier(ctx, web, filepath.Join(web.conf.workDir, dataDir, updateNotifiedFilename))
+
+	ticker := time.NewTicker(updateNotifyCheckInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		config.RLock()
+		conf := config.Notifications.UpdateAvailable
+		config.RUnlock()
+
+		notifier := globalContext.notifier
+		if !conf.Enabled || notifier == nil {
+			continue
+		}
+
+		vi, err := web.conf.updater.VersionInfo(ctx, false)
+		if err != nil {
+			web.logger.DebugContext(ctx, "checking for updates to notify", slogutil.KeyError, err)
+
+			continue
+		}
+
+		web.notifyUpdate(ctx, notifier, n, conf, vi)
+	}
+}
+
+// notifyUpdate sends the notification about the new version in vi, if it's
+// due.
+func (web *webAPI) notifyUpdate(
+	ctx context.Context,
+	notifier *notifications.Manager,
+	n *updateNotifier,
+	conf updateNotificationsConfig,
+	vi updater.VersionInfo,
+) {
+	cur := version.Version()
+	if vi.NewVersion == "" || vi.NewVersion == cur || !n.isDue(conf, vi.NewVersion, time.Now()) {
+		return
+	}
+
+	notifier.NotifyUpdateAvailable(ctx, notifications.UpdateInfo{
+		NewVersion:      vi.NewVersion,
+		CurrentVersion:  cur,
+		Channel:         version.Channel(),
+		Announcement:    vi.Announcement,
+		AnnouncementURL: vi.AnnouncementURL,
+	})
+
+	err := n.store()
+	if err != nil {
+		web.logger.WarnContext(ctx, "storing notified update version", slogutil.KeyError, err)
+	}
+}
+
+// registerUpdateNotificationsHandlers registers the HTTP handlers of the new
+// release notifications.
+func (web *webAPI) registerUpdateNotificationsHandlers() {
+	web.httpReg.Register(
+		http.MethodGet,
+		"/control/notifications/update_available",
+		web.handleGetUpdateNotifications,
+	)
+	web.httpReg.Register(
+		http.MethodPut,
+		"/control/notifications/update_available/update",
+		web.handlePutUpdateNotifications,
+	)
+}
+
+// handleGetUpdateNotifications is the handler for the GET
+// /control/notifications/update_available HTTP API.
+func (web *webAPI) handleGetUpdateNotifications(w http.ResponseWriter, r *http.Request) {
+	config.RLock()
+	resp := config.Notifications.UpdateAvailable
+	config.RUnlock()
+
+	aghhttp.WriteJSONResponseOK(r.Context(), web.logger, w, r, resp)
+}
+
+// handlePutUpdateNotifications is the handler for the PUT
+// /control/notifications/update_available/update HTTP API.
+func (web *webAPI) handlePutUpdateNotifications(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req := updateNotificationsConfig{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusBadRequest, "json decode: %s", err)
+
+		return
+	}
+
+	config.Lock()
+	config.Notifications.UpdateAvailable = req
+	config.Unlock()
+
+	web.logger.InfoContext(ctx, "update notifications updated", "enabled", req.Enabled)
+	web.confModifier.Apply(ctx)
+
+	aghhttp.OK(ctx, web.logger, w)
+}
@@ -0,0 +1,31 @@
+package home
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateNotifier_isDue(t *testing.T) {
+	now := time.Now()
+
+	t.Run("once_per_version", func(t *testing.T) {
+		n := &updateNotifier{mu: &sync.Mutex{}, lastVersion: "v0.107.60"}
+		conf := updateNotificationsConfig{OncePerVersion: true, Enabled: true}
+
+		assert.False(t, n.isDue(conf, "v0.107.60", now))
+		assert.True(t, n.isDue(conf, "v0.107.61", now))
+		assert.False(t, n.isDue(conf, "v0.107.61", now.Add(2*updateRemindInterval)))
+	})
+
+	t.Run("reminders", func(t *testing.T) {
+		n := &updateNotifier{mu: &sync.Mutex{}}
+		conf := updateNotificationsConfig{Enabled: true}
+
+		assert.True(t, n.isDue(conf, "v0.107.60", now))
+		assert.False(t, n.isDue(conf, "v0.107.60", now.Add(time.Hour)))
+		assert.True(t, n.isDue(conf, "v0.107.60", now.Add(updateRemindInterval)))
+	})
+}
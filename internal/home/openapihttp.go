@@ -0,0 +1,51 @@
+package home
+
+import (
+	"net/http"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/version"
+)
+
+// openAPITitle is the title of the generated OpenAPI document.
+const openAPITitle = "AdGuard Home"
+
+// newV1Versions returns the versions of the routes available both under the
+// unversioned and the [aghhttp.APIVersion1] paths.  info may be nil.
+func newV1Versions(info *aghhttp.RouteInfo) (vers *aghhttp.Versions) {
+	return &aghhttp.Versions{
+		Info:    info,
+		Mounted: []aghhttp.APIVersion{aghhttp.APIVersionLegacy, aghhttp.APIVersion1},
+	}
+}
+
+// registerOpenAPIHandlers registers the HTTP handlers of the generated OpenAPI
+// document.
+func (web *webAPI) registerOpenAPIHandlers() {
+	aghhttp.RegisterVersions(
+		web.httpReg,
+		http.MethodGet,
+		"/control/openapi.json",
+		web.handleGetOpenAPI,
+		newV1Versions(&aghhttp.RouteInfo{
+			Summary: "Get the OpenAPI document generated from the registered routes",
+		}),
+	)
+}
+
+// handleGetOpenAPI is the handler for the GET /control/openapi.json HTTP API.
+// The document describes the routes registered with web.httpReg, so it only
+// contains the routes of the enabled modules.
+func (web *webAPI) handleGetOpenAPI(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	lister, ok := web.httpReg.(aghhttp.RouteLister)
+	if !ok {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusNotImplemented, "routes are not listed")
+
+		return
+	}
+
+	doc := aghhttp.NewOpenAPIDocument(openAPITitle, version.Version(), lister.Routes())
+	aghhttp.WriteJSONResponseOK(ctx, web.logger, w, r, doc)
+}
@@ -87,6 +87,10 @@ type options struct {
 	// noPermCheck disables checking and migration of permissions for the
 	// security-sensitive files.
 	noPermCheck bool
+
+	// virtualAccount, if set, makes the installed service run under its
+	// virtual account instead of LocalSystem on Windows.
+	virtualAccount bool
 }
 
 // initCmdLineOpts completes initialization of the global command-line option
@@ -323,6 +327,15 @@ var cmdLineOpts = []cmdLineOpt{{
 		"of security-sensitive files.",
 	longName:  "no-permcheck",
 	shortName: "",
+}, {
+	updateWithValue: nil,
+	updateNoValue:   func(o options) (options, error) { o.virtualAccount = true; return o, nil },
+	effect:          nil,
+	serialize:       func(o options) (val string, ok bool) { return "", o.virtualAccount },
+	description: "Run the service installed with \"-s install\" under its virtual " +
+		"account instead of LocalSystem.  Windows only.",
+	longName:  "virtual-account",
+	shortName: "",
 }, {
 	updateWithValue: nil,
 	updateNoValue:   nil,
@@ -353,7 +366,8 @@ func printHelp(exec string) {
 	stringutil.WriteToBuilder(
 		b,
 		"Usage:\n\n",
-		fmt.Sprintf("%s [options]\n\n", exec),
+		fmt.Sprintf("%s [options]\n", exec),
+		fmt.Sprintf("%s %s [flags] COMMAND [ARGS]\n\n", exec, ctlArg),
 		"Options:\n",
 	)
 
@@ -215,6 +215,10 @@ func TestOptsToArgs(t *testing.T) {
 		name: "glinet_mode",
 		args: []string{"--glinet"},
 		opts: options{glinetMode: true},
+	}, {
+		name: "virtual_account",
+		args: []string{"--virtual-account"},
+		opts: options{virtualAccount: true},
 	}, {
 		name: "multiple",
 		args: []string{
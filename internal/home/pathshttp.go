@@ -0,0 +1,93 @@
+package home
+
+import (
+	"net/http"
+	"path/filepath"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+)
+
+// registerPathsHandlers registers the HTTP handlers of the paths used by
+// AdGuard Home.
+func (web *webAPI) registerPathsHandlers() {
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodGet,
+		"/control/paths",
+		web.handleGetPaths,
+		&aghhttp.RouteInfo{
+			Summary: "Get the resolved paths of the files and directories in use",
+			Description: "The paths are absolute, with the symbolic links to the " +
+				"configuration file resolved.",
+			Response: pathsResp{},
+		},
+	)
+}
+
+// pathsResp is the response to the GET /control/paths HTTP API.
+type pathsResp struct {
+	// WorkDir is the working directory.
+	WorkDir string `json:"work_dir"`
+
+	// ConfigFile is the path to the configuration file.
+	ConfigFile string `json:"config_file"`
+
+	// DataDir is the directory with the filter lists, the sessions, and the
+	// other data.
+	DataDir string `json:"data_dir"`
+
+	// QueryLogDir is the directory with the query log files.
+	QueryLogDir string `json:"querylog_dir"`
+
+	// StatsDir is the directory with the statistics database.
+	StatsDir string `json:"stats_dir"`
+
+	// FallbackWorkDirUsed is true if the default working directory isn't
+	// writable and a per-user one is used instead.  It's only ever true on
+	// Windows.
+	FallbackWorkDirUsed bool `json:"fallback_work_dir_used"`
+}
+
+// handleGetPaths is the handler for the GET /control/paths HTTP API.
+func (web *webAPI) handleGetPaths(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	l := web.logger
+
+	workDir := absPath(web.conf.workDir)
+	dataDirPath := filepath.Join(workDir, dataDir)
+
+	config.RLock()
+	statsDir, querylogDir := config.Stats.DirPath, config.QueryLog.DirPath
+	config.RUnlock()
+
+	aghhttp.WriteJSONResponseOK(ctx, l, w, r, &pathsResp{
+		WorkDir:             workDir,
+		ConfigFile:          absPath(configFilePath(ctx, l, workDir, web.conf.confPath)),
+		DataDir:             dataDirPath,
+		QueryLogDir:         dirOrDefault(querylogDir, dataDirPath),
+		StatsDir:            dirOrDefault(statsDir, dataDirPath),
+		FallbackWorkDirUsed: isFallbackWorkDirUsed(),
+	})
+}
+
+// dirOrDefault returns the absolute path to the custom directory dir or def if
+// dir is empty, the same way as [checkStatsAndQuerylogDirs] does.
+func dirOrDefault(dir, def string) (res string) {
+	if dir == "" {
+		return def
+	}
+
+	return absPath(dir)
+}
+
+// absPath returns the absolute form of path or path itself if it can't be
+// determined.
+func absPath(path string) (res string) {
+	res, err := filepath.Abs(path)
+	if err != nil {
+		// Don't fail the introspection; the relative path is still helpful.
+		return path
+	}
+
+	return res
+}
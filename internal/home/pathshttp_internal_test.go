@@ -0,0 +1,46 @@
+package home
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebAPI_handleGetPaths(t *testing.T) {
+	storeGlobals(t)
+
+	workDir := t.TempDir()
+	statsDir := t.TempDir()
+
+	config = newDefaultConfig()
+	config.Stats.DirPath = statsDir
+
+	web := newTestWeb(t, &webConfig{
+		workDir:  workDir,
+		confPath: "AdGuardHome.yaml",
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/control/paths", nil)
+	web.handleGetPaths(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	resp := &pathsResp{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(resp))
+
+	dataDirPath := filepath.Join(workDir, dataDir)
+	assert.Equal(t, &pathsResp{
+		WorkDir:             workDir,
+		ConfigFile:          filepath.Join(workDir, "AdGuardHome.yaml"),
+		DataDir:             dataDirPath,
+		QueryLogDir:         dataDirPath,
+		StatsDir:            statsDir,
+		FallbackWorkDirUsed: false,
+	}, resp)
+}
@@ -0,0 +1,132 @@
+package home
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/plugin"
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+	"github.com/AdguardTeam/golibs/osutil/executil"
+	"github.com/AdguardTeam/golibs/timeutil"
+)
+
+const (
+	// defaultPluginsDir is the default directory with the executables of the
+	// plugins relative to the working directory.
+	defaultPluginsDir = "plugins"
+
+	// defaultPluginHookTimeout is the default timeout of a single call of a
+	// DNS hook of a plugin.
+	defaultPluginHookTimeout = 1 * time.Second
+)
+
+// pluginsConfig is the configuration of the plugins.  See [plugin.Manager].
+type pluginsConfig struct {
+	// Settings are the configurations of the plugins by their names.  Each of
+	// them is passed to the plugin encoded as JSON.
+	Settings map[string]any `yaml:"settings"`
+
+	// Dir is the directory with the executables of the plugins.  If it's
+	// relative, it's relative to the working directory.
+	Dir string `yaml:"dir"`
+
+	// HookTimeout is the timeout of a single call of a DNS hook of a plugin.
+	HookTimeout timeutil.Duration `yaml:"hook_timeout"`
+
+	// Enabled defines if the plugins are started.
+	Enabled bool `yaml:"enabled"`
+}
+
+// defaultPluginsConfig returns the default configuration of the plugins.
+func defaultPluginsConfig() (c *pluginsConfig) {
+	return &pluginsConfig{
+		Settings:    map[string]any{},
+		Dir:         defaultPluginsDir,
+		HookTimeout: timeutil.Duration(defaultPluginHookTimeout),
+		Enabled:     false,
+	}
+}
+
+// validate returns an error if c is invalid.  c may be nil.
+func (c *pluginsConfig) validate() (err error) {
+	if c == nil {
+		return nil
+	}
+
+	if c.Dir == "" {
+		return fmt.Errorf("dir: %w", errors.ErrEmptyValue)
+	}
+
+	if c.HookTimeout <= 0 {
+		return fmt.Errorf("hook_timeout: %w: %s", errors.ErrNotPositive, c.HookTimeout)
+	}
+
+	_, err = c.jsonSettings()
+
+	return err
+}
+
+// jsonSettings returns the settings of the plugins encoded as JSON.
+func (c *pluginsConfig) jsonSettings() (settings map[string]json.RawMessage, err error) {
+	settings = make(map[string]json.RawMessage, len(c.Settings))
+	for name, s := range c.Settings {
+		settings[name], err = json.Marshal(s)
+		if err != nil {
+			return nil, fmt.Errorf("settings: plugin %q: %w", name, err)
+		}
+	}
+
+	return settings, nil
+}
+
+// initPlugins starts the plugins, if they're enabled, and registers the HTTP
+// handlers of the plugins with httpReg.  baseLogger, cmdCons, and httpReg must
+// not be nil.  It also must not be called unless [config] is initialized.
+func initPlugins(
+	ctx context.Context,
+	baseLogger *slog.Logger,
+	cmdCons executil.CommandConstructor,
+	httpReg aghhttp.Registrar,
+	workDir string,
+) (m *plugin.Manager, err error) {
+	config.RLock()
+	c := config.Plugins
+	config.RUnlock()
+
+	settings, err := c.jsonSettings()
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return nil, err
+	}
+
+	dir := c.Dir
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(workDir, dir)
+	}
+
+	m = plugin.New(&plugin.Config{
+		Logger:             baseLogger.With(slogutil.KeyPrefix, "plugins"),
+		CommandConstructor: cmdCons,
+		Settings:           settings,
+		Dir:                dir,
+		DataDir:            filepath.Join(workDir, dataDir, defaultPluginsDir),
+		HookTimeout:        time.Duration(c.HookTimeout),
+	})
+
+	if c.Enabled {
+		err = m.Start(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("starting plugins: %w", err)
+		}
+	}
+
+	m.RegisterHandlers(httpReg)
+
+	return m, nil
+}
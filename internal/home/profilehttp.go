@@ -140,6 +140,7 @@ func (web *webAPI) handlePutProfile(w http.ResponseWriter, r *http.Request) {
 
 	if changed {
 		web.confModifier.Apply(ctx)
+		updateNotificationLocale(ctx, l, lang)
 	}
 
 	aghhttp.OK(ctx, l, w)
@@ -262,6 +263,8 @@ func (web *webAPI) handleChangeUsername(w http.ResponseWriter, r *http.Request)
 		}
 	}()
 
+	web.auth.apiTokens.renameUser(oldLogin, u.Login)
+	web.auth.totp.renameUser(oldLogin, u.Login)
 	web.confModifier.Apply(ctx)
 
 	l.InfoContext(ctx, "username changed", "old", oldLogin, "new", req.NewUsername)
@@ -0,0 +1,80 @@
+package home
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghos"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+)
+
+// notifyServiceManager sends state to the service manager, if it expects
+// notifications.  l must not be nil.
+func notifyServiceManager(ctx context.Context, l *slog.Logger, state string) {
+	sent, err := aghos.SDNotify(state)
+	if err != nil {
+		l.WarnContext(ctx, "notifying service manager", "state", state, slogutil.KeyError, err)
+	} else if sent {
+		l.DebugContext(ctx, "notified service manager", "state", state)
+	}
+}
+
+// runWatchdog pings the watchdog of the service manager for as long as
+// AdGuard Home stays responsive, so that the service manager restarts it if
+// it hangs.  It does nothing if the watchdog isn't enabled.  It is intended to
+// be used as a goroutine.  l must not be nil.
+func runWatchdog(ctx context.Context, l *slog.Logger) {
+	defer slogutil.RecoverAndLog(ctx, l)
+
+	timeout, ok, err := aghos.SDWatchdogInterval()
+	if err != nil {
+		l.WarnContext(ctx, "getting watchdog interval", slogutil.KeyError, err)
+
+		return
+	} else if !ok {
+		return
+	}
+
+	// Ping twice per timeout, as recommended by sd_watchdog_enabled(3).
+	ivl := timeout / 2
+	l.InfoContext(ctx, "watchdog enabled", "timeout", timeout)
+
+	ticker := time.NewTicker(ivl)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if isResponsive(ivl) {
+				notifyServiceManager(ctx, l, aghos.SDNotifyWatchdog)
+			} else {
+				l.WarnContext(ctx, "not responsive; skipping watchdog ping")
+			}
+		}
+	}
+}
+
+// isResponsive returns true if the global configuration lock, which most of
+// the modules depend on, can be acquired within timeout.
+func isResponsive(timeout time.Duration) (ok bool) {
+	acquired := make(chan struct{})
+	go func() {
+		config.RLock()
+		defer config.RUnlock()
+
+		close(acquired)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-acquired:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
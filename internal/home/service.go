@@ -6,6 +6,8 @@ import (
 	"io/fs"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"slices"
 
 	"github.com/AdguardTeam/AdGuardHome/internal/aghos"
 	"github.com/AdguardTeam/AdGuardHome/internal/ossvc"
@@ -287,6 +289,26 @@ func handleServiceReloadCmd(ctx context.Context, l *slog.Logger, mgr ossvc.Manag
 	return nil
 }
 
+// serviceAccessPaths returns the directories, to which the service needs the
+// write access: the current directory, the working one, and the one with the
+// configuration file.  l must not be nil.
+func serviceAccessPaths(
+	ctx context.Context,
+	l *slog.Logger,
+	pwd string,
+	workDir string,
+	confPath string,
+) (paths []string) {
+	confDir := filepath.Dir(configFilePath(ctx, l, workDir, confPath))
+	for _, p := range []string{pwd, workDir, confDir} {
+		if p != "" && !slices.Contains(paths, p) {
+			paths = append(paths, p)
+		}
+	}
+
+	return paths
+}
+
 // handleServiceInstallCmd handles the service "install" command.  l must
 // not be nil.
 func handleServiceInstallCmd(
@@ -304,22 +326,32 @@ func handleServiceInstallCmd(
 
 	runOpts := opts
 	runOpts.serviceControlAction = "run"
+	runOpts.virtualAccount = false
 
 	args := optsToArgs(runOpts)
 	l.DebugContext(ctx, "using", "args", args)
 
 	err = mgr.Perform(ctx, &ossvc.ActionInstall{
-		ServiceName:      serviceName,
-		DisplayName:      serviceDisplayName,
-		Description:      serviceDescription,
-		WorkingDirectory: pwd,
-		Version:          version.Full(),
-		Arguments:        args,
+		ServiceName:       serviceName,
+		DisplayName:       serviceDisplayName,
+		Description:       serviceDescription,
+		WorkingDirectory:  pwd,
+		Version:           version.Full(),
+		Arguments:         args,
+		AccessPaths:       serviceAccessPaths(ctx, l, pwd, workDir, confPath),
+		UseVirtualAccount: opts.virtualAccount,
 	})
 	if err != nil {
 		return fmt.Errorf("installing service: %w", err)
 	}
 
+	// Register the source of the system log entries while AdGuard Home has the
+	// administrative permissions, since the service may not have them.
+	err = aghos.InstallSystemLog(serviceName)
+	if err != nil {
+		l.WarnContext(ctx, "registering system log source", slogutil.KeyError, err)
+	}
+
 	err = mgr.Perform(ctx, &ossvc.ActionStart{
 		ServiceName: serviceName,
 	})
@@ -0,0 +1,222 @@
+package home
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/aghuser"
+)
+
+// registerSessionHandlers registers the HTTP handlers of the web sessions.
+func (web *webAPI) registerSessionHandlers() {
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodGet,
+		"/control/sessions",
+		web.handleGetSessions,
+		&aghhttp.RouteInfo{
+			Summary:  "List the active web sessions",
+			Response: sessionsResp{},
+		},
+	)
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodPost,
+		"/control/sessions/revoke",
+		web.handleRevokeSession,
+		&aghhttp.RouteInfo{
+			Summary: "Revoke a web session",
+			Request: revokeSessionReq{},
+		},
+	)
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodPost,
+		"/control/sessions/revoke_others",
+		web.handleRevokeOtherSessions,
+		&aghhttp.RouteInfo{
+			Summary:     "Revoke all web sessions except the current one",
+			Description: "If the request isn't authenticated with a session, all sessions are revoked.",
+			Response:    revokeOtherSessionsResp{},
+		},
+	)
+}
+
+// sessionIDLen is the length of the identifiers of the web sessions in bytes.
+const sessionIDLen = 8
+
+// sessionID returns the identifier of the web session with the token t.  The
+// identifier doesn't allow to restore the token, so it may be shown to the
+// users.
+func sessionID(t aghuser.SessionToken) (id string) {
+	sum := sha256.Sum256(t[:])
+
+	return hex.EncodeToString(sum[:sessionIDLen])
+}
+
+// sessionJSON is a web session in the response to the GET /control/sessions
+// HTTP API.
+type sessionJSON struct {
+	LastActivity time.Time `json:"last_activity"`
+	Expires      time.Time `json:"expires"`
+	ID           string    `json:"id"`
+	User         string    `json:"user"`
+	RemoteIP     string    `json:"remote_ip,omitempty"`
+	UserAgent    string    `json:"user_agent"`
+
+	// Current is true if the request has been made within the session.
+	Current bool `json:"current"`
+}
+
+// sessionsResp is the response to the GET /control/sessions HTTP API.
+type sessionsResp struct {
+	Sessions []*sessionJSON `json:"sessions"`
+}
+
+// revokeSessionReq is the request to the POST /control/sessions/revoke HTTP
+// API.
+type revokeSessionReq struct {
+	ID string `json:"id"`
+}
+
+// revokeOtherSessionsResp is the response to the POST
+// /control/sessions/revoke_others HTTP API.
+type revokeOtherSessionsResp struct {
+	Revoked int `json:"revoked"`
+}
+
+// currentSessionToken returns the token of the session of r, if any.
+func currentSessionToken(r *http.Request) (t aghuser.SessionToken, ok bool) {
+	c, err := r.Cookie(sessionCookieName)
+	if err != nil {
+		return t, false
+	}
+
+	t, err = sessionTokenFromHex(c.Value)
+
+	return t, err == nil
+}
+
+// handleGetSessions is the handler for the GET /control/sessions HTTP API.
+func (web *webAPI) handleGetSessions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sessions, err := web.auth.sessions.All(ctx)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusInternalServerError, "%s", err)
+
+		return
+	}
+
+	cur, hasCur := currentSessionToken(r)
+	resp := &sessionsResp{
+		Sessions: make([]*sessionJSON, 0, len(sessions)),
+	}
+
+	for _, s := range sessions {
+		sj := &sessionJSON{
+			LastActivity: s.LastActivity,
+			Expires:      s.Expire,
+			ID:           sessionID(s.Token),
+			User:         string(s.UserLogin),
+			UserAgent:    s.UserAgent,
+			Current:      hasCur && s.Token == cur,
+		}
+
+		if s.RemoteIP.IsValid() {
+			sj.RemoteIP = s.RemoteIP.String()
+		}
+
+		resp.Sessions = append(resp.Sessions, sj)
+	}
+
+	slices.SortFunc(resp.Sessions, func(a, b *sessionJSON) (res int) {
+		return cmp.Or(b.LastActivity.Compare(a.LastActivity), cmp.Compare(a.ID, b.ID))
+	})
+
+	aghhttp.WriteJSONResponseOK(ctx, web.logger, w, r, resp)
+}
+
+// handleRevokeSession is the handler for the POST /control/sessions/revoke
+// HTTP API.
+func (web *webAPI) handleRevokeSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	l := web.logger
+
+	req := &revokeSessionReq{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusBadRequest, "reading req: %s", err)
+
+		return
+	}
+
+	sessions, err := web.auth.sessions.All(ctx)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusInternalServerError, "%s", err)
+
+		return
+	}
+
+	i := slices.IndexFunc(sessions, func(s *aghuser.Session) (ok bool) {
+		return sessionID(s.Token) == req.ID
+	})
+	if i < 0 {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusNotFound, "session %q not found", req.ID)
+
+		return
+	}
+
+	s := sessions[i]
+	err = web.auth.sessions.DeleteByToken(ctx, s.Token)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusInternalServerError, "%s", err)
+
+		return
+	}
+
+	l.InfoContext(ctx, "session revoked", "id", req.ID, "user", s.UserLogin)
+
+	aghhttp.OK(ctx, l, w)
+}
+
+// handleRevokeOtherSessions is the handler for the POST
+// /control/sessions/revoke_others HTTP API.
+func (web *webAPI) handleRevokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	l := web.logger
+
+	sessions, err := web.auth.sessions.All(ctx)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusInternalServerError, "%s", err)
+
+		return
+	}
+
+	cur, hasCur := currentSessionToken(r)
+	resp := &revokeOtherSessionsResp{}
+	for _, s := range sessions {
+		if hasCur && s.Token == cur {
+			continue
+		}
+
+		err = web.auth.sessions.DeleteByToken(ctx, s.Token)
+		if err != nil {
+			aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusInternalServerError, "%s", err)
+
+			return
+		}
+
+		resp.Revoked++
+	}
+
+	l.InfoContext(ctx, "sessions revoked", "count", resp.Revoked)
+
+	aghhttp.WriteJSONResponseOK(ctx, l, w, r, resp)
+}
@@ -0,0 +1,119 @@
+package home
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghuser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebAPI_sessions(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cur := aghuser.NewSessionToken()
+	other := aghuser.NewSessionToken()
+	third := aghuser.NewSessionToken()
+
+	sessions := map[aghuser.SessionToken]*aghuser.Session{
+		cur: {
+			LastActivity: now,
+			RemoteIP:     netip.MustParseAddr("192.0.2.1"),
+			UserAgent:    "Firefox",
+			UserLogin:    testUsername,
+			Token:        cur,
+		},
+		other: {
+			LastActivity: now.Add(-time.Hour),
+			UserLogin:    testUsername,
+			Token:        other,
+		},
+		third: {
+			LastActivity: now.Add(-2 * time.Hour),
+			UserLogin:    "other",
+			Token:        third,
+		},
+	}
+
+	ts := newTestSessionStorage()
+	ts.onAll = func(_ context.Context) (ss []*aghuser.Session, err error) {
+		return slices.Collect(maps.Values(sessions)), nil
+	}
+	ts.onDeleteByToken = func(_ context.Context, t aghuser.SessionToken) (err error) {
+		delete(sessions, t)
+
+		return nil
+	}
+
+	web := &webAPI{
+		logger: testLogger,
+		auth: &auth{
+			sessions: ts,
+		},
+	}
+
+	cookie := &http.Cookie{Name: sessionCookieName, Value: hex.EncodeToString(cur[:])}
+
+	r := httptest.NewRequest(http.MethodGet, "/control/sessions", nil)
+	r.AddCookie(cookie)
+	w := httptest.NewRecorder()
+	web.handleGetSessions(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	resp := &sessionsResp{}
+	err := json.NewDecoder(w.Body).Decode(resp)
+	require.NoError(t, err)
+	require.Len(t, resp.Sessions, 3)
+
+	got := resp.Sessions[0]
+	assert.Equal(t, sessionID(cur), got.ID)
+	assert.Equal(t, "192.0.2.1", got.RemoteIP)
+	assert.Equal(t, "Firefox", got.UserAgent)
+	assert.True(t, got.Current)
+	assert.False(t, resp.Sessions[1].Current)
+	assert.Equal(t, "other", resp.Sessions[2].User)
+
+	t.Run("revoke_unknown", func(t *testing.T) {
+		body := strings.NewReader(`{"id":"unknown"}`)
+		w = httptest.NewRecorder()
+		web.handleRevokeSession(w, httptest.NewRequest(http.MethodPost, "/", body))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Len(t, sessions, 3)
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		body := strings.NewReader(`{"id":"` + sessionID(third) + `"}`)
+		w = httptest.NewRecorder()
+		web.handleRevokeSession(w, httptest.NewRequest(http.MethodPost, "/", body))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, sessions, third)
+	})
+
+	t.Run("revoke_others", func(t *testing.T) {
+		r = httptest.NewRequest(http.MethodPost, "/control/sessions/revoke_others", nil)
+		r.AddCookie(cookie)
+		w = httptest.NewRecorder()
+		web.handleRevokeOtherSessions(w, r)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		revoked := &revokeOtherSessionsResp{}
+		err = json.NewDecoder(w.Body).Decode(revoked)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, revoked.Revoked)
+		assert.Equal(t, []aghuser.SessionToken{cur}, slices.Collect(maps.Keys(sessions)))
+	})
+}
@@ -21,7 +21,7 @@ type signalHandler struct {
 	// logger is used to log the operation of the signal handler.
 	logger *slog.Logger
 
-	// mu protects clientStorage and tlsManager.
+	// mu protects clientStorage, tlsManager, and confReloader.
 	mu *sync.Mutex
 
 	// clientStorage is used to reload information about runtime clients with an
@@ -31,6 +31,9 @@ type signalHandler struct {
 	// tlsManager is used to reload the TLS configuration.
 	tlsManager aghtls.Manager
 
+	// confReloader is used to reload the configuration file.
+	confReloader *configReloader
+
 	// signals receives incoming signals.
 	signals <-chan os.Signal
 
@@ -69,6 +72,14 @@ func (h *signalHandler) addTLSManager(m aghtls.Manager) {
 	h.tlsManager = m
 }
 
+// addConfigReloader stores the reloader of the configuration file.
+func (h *signalHandler) addConfigReloader(r *configReloader) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.confReloader = r
+}
+
 // handle processes incoming signals.  It blocks until a signal is received.  It
 // reloads configurations of stored entities on SIGHUP, or performs cleanup on
 // all other signals.  It is intended to be used as a goroutine.
@@ -128,4 +139,8 @@ func (h *signalHandler) reloadConfig(ctx context.Context) {
 			h.logger.ErrorContext(ctx, "refreshing tls manager", slogutil.KeyError, err)
 		}
 	}
+
+	if h.confReloader != nil {
+		h.confReloader.reloadOnSignal(ctx)
+	}
 }
@@ -0,0 +1,269 @@
+package home
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/configmigrate"
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+	"github.com/AdguardTeam/AdGuardHome/internal/version"
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/httphdr"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+	yaml "go.yaml.in/yaml/v4"
+)
+
+// supportBundlePath is the path of the HTTP API generating the support bundle,
+// which only the administrators may use.
+const supportBundlePath = "/control/support_bundle"
+
+// maxRecentLogsSize is the maximum size of the latest log output kept for the
+// support bundles.
+const maxRecentLogsSize = 1 << 20
+
+// recentLogs keeps the latest log output for the support bundles.
+var recentLogs = newLogTail(maxRecentLogsSize)
+
+// The names of the files within a support bundle.
+const (
+	supportFileConfig     = "config.yaml"
+	supportFileErrors     = "errors.txt"
+	supportFileLogs       = "logs.txt"
+	supportFileSystemInfo = "systeminfo.json"
+	supportFileUpstreams  = "upstreams.json"
+	supportFileVersion    = "version.txt"
+)
+
+// logTail is an [io.Writer] keeping the latest whole lines written to it.  It's
+// safe for concurrent use.
+type logTail struct {
+	// mu protects buf.
+	mu *sync.Mutex
+
+	// buf contains the written data.  It grows up to twice the size limit
+	// before being trimmed, so that the data isn't moved on each write.
+	buf []byte
+
+	// limit is the maximum size of the kept data.
+	limit int
+}
+
+// newLogTail returns a new properly initialized *logTail keeping up to limit
+// bytes.
+func newLogTail(limit int) (t *logTail) {
+	return &logTail{
+		mu:    &sync.Mutex{},
+		limit: limit,
+	}
+}
+
+// type check
+var _ io.Writer = (*logTail)(nil)
+
+// Write implements the [io.Writer] interface for *logTail.  It never returns an
+// error.
+func (t *logTail) Write(p []byte) (n int, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.buf = append(t.buf, p...)
+	if len(t.buf) > 2*t.limit {
+		t.buf = append(t.buf[:0], tailLines(t.buf, t.limit)...)
+	}
+
+	return len(p), nil
+}
+
+// contents returns a copy of the latest data written to t.
+func (t *logTail) contents() (data []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return slices.Clone(tailLines(t.buf, t.limit))
+}
+
+// tailLines returns the latest whole lines of data, which take up to limit
+// bytes.
+func tailLines(data []byte, limit int) (tail []byte) {
+	if len(data) <= limit {
+		return data
+	}
+
+	tail = data[len(data)-limit:]
+	if i := bytes.IndexByte(tail, '\n'); i >= 0 {
+		tail = tail[i+1:]
+	}
+
+	return tail
+}
+
+// supportBundleFile is a file within a support bundle.
+type supportBundleFile struct {
+	name string
+	data []byte
+}
+
+// registerSupportBundleHandlers registers the HTTP handlers of the support
+// bundles.
+func (web *webAPI) registerSupportBundleHandlers() {
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodGet,
+		supportBundlePath,
+		web.handleSupportBundle,
+		&aghhttp.RouteInfo{
+			Summary: "Generate the diagnostic support bundle",
+			Description: "Returns the gzip-compressed tar archive with the " +
+				"configuration with the secrets redacted, the latest log output, " +
+				"the version and the build information, the snapshot of the " +
+				"system information, and the results of the check of the " +
+				"upstreams.",
+		},
+	)
+}
+
+// handleSupportBundle is the handler for the GET /control/support_bundle HTTP
+// API.
+func (web *webAPI) handleSupportBundle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	l := web.logger
+
+	now := time.Now()
+	files := supportBundleFiles(ctx)
+
+	const nameFmt = `attachment; filename="adguardhome-support-%s.tar.gz"`
+	h := w.Header()
+	h.Set(httphdr.ContentType, "application/gzip")
+	h.Set(httphdr.ContentDisposition, fmt.Sprintf(nameFmt, now.UTC().Format("20060102-150405")))
+
+	err := writeSupportBundle(w, files, now)
+	if err != nil {
+		l.DebugContext(ctx, "writing support bundle", slogutil.KeyError, err)
+
+		return
+	}
+
+	l.InfoContext(ctx, "generated support bundle", "files", len(files))
+}
+
+// supportBundleFiles collects the files of a support bundle.  The errors of
+// the collection are reported in a separate file.
+func supportBundleFiles(ctx context.Context) (files []*supportBundleFile) {
+	var errs []string
+	add := func(name string, data []byte, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", name, err))
+
+			return
+		}
+
+		files = append(files, &supportBundleFile{
+			name: name,
+			data: data,
+		})
+	}
+
+	add(supportFileVersion, []byte(version.Verbose(configmigrate.LastSchemaVersion)), nil)
+
+	data, err := sanitizedConfig()
+	add(supportFileConfig, data, err)
+
+	add(supportFileLogs, recentLogs.contents(), nil)
+
+	data, err = json.MarshalIndent(systeminfo.Collect(ctx), "", "  ")
+	add(supportFileSystemInfo, data, err)
+
+	data, err = checkUpstreamsJSON(ctx)
+	add(supportFileUpstreams, data, err)
+
+	if len(errs) > 0 {
+		add(supportFileErrors, []byte(strings.Join(errs, "\n")+"\n"), nil)
+	}
+
+	return files
+}
+
+// sanitizedConfig returns the current configuration in the YAML format with
+// the values of the secrets, like the password hashes, the tokens, the webhook
+// URLs, and the URLs with credentials, redacted.
+func sanitizedConfig() (data []byte, err error) {
+	config.RLock()
+	data, err = yaml.Marshal(config)
+	config.RUnlock()
+	if err != nil {
+		return nil, fmt.Errorf("encoding config: %w", err)
+	}
+
+	var conf map[string]any
+	err = yaml.Unmarshal(data, &conf)
+	if err != nil {
+		return nil, fmt.Errorf("decoding config: %w", err)
+	}
+
+	return yaml.Marshal(redactSecrets("", conf))
+}
+
+// checkUpstreamsJSON returns the results of the check of the configured
+// upstreams encoded in JSON.
+func checkUpstreamsJSON(ctx context.Context) (data []byte, err error) {
+	srv := globalContext.dnsServer
+	if srv == nil {
+		return nil, errors.Error("dns server is unavailable")
+	}
+
+	results, err := srv.CheckUpstreams(ctx)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return nil, err
+	}
+
+	return json.MarshalIndent(results, "", "  ")
+}
+
+// writeSupportBundle writes files as a gzip-compressed tar archive to w.
+// modTime is used as the modification time of the files.
+func writeSupportBundle(w io.Writer, files []*supportBundleFile, modTime time.Time) (err error) {
+	gzw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gzw)
+
+	for _, f := range files {
+		err = tw.WriteHeader(&tar.Header{
+			Name:     f.name,
+			Mode:     0o644,
+			Size:     int64(len(f.data)),
+			ModTime:  modTime,
+			Typeflag: tar.TypeReg,
+		})
+		if err != nil {
+			return fmt.Errorf("writing header of %s: %w", f.name, err)
+		}
+
+		_, err = tw.Write(f.data)
+		if err != nil {
+			return fmt.Errorf("writing %s: %w", f.name, err)
+		}
+	}
+
+	err = tw.Close()
+	if err != nil {
+		return fmt.Errorf("closing archive: %w", err)
+	}
+
+	err = gzw.Close()
+	if err != nil {
+		return fmt.Errorf("closing compression: %w", err)
+	}
+
+	return nil
+}
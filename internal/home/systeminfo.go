@@ -0,0 +1,263 @@
+package home
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+	"github.com/AdguardTeam/golibs/osutil/executil"
+)
+
+const (
+	// defaultSysInfoHistoryInterval is the default interval between the
+	// samples of the system metrics history.
+	defaultSysInfoHistoryInterval = time.Minute
+
+	// defaultSysInfoHistoryRetention is the default duration, during which
+	// the samples of the system metrics history are kept.
+	defaultSysInfoHistoryRetention = 24 * time.Hour
+
+	// maxSysInfoHistorySize is the maximum number of the samples in the
+	// system metrics history, which limits the memory used by it.
+	maxSysInfoHistorySize = 100_000
+
+	// defaultSysInfoStreamInterval is the default interval between the
+	// samples pushed by the system metrics stream.
+	defaultSysInfoStreamInterval = 5 * time.Second
+
+	// maxSysInfoStreamInterval is the maximum interval between the samples
+	// pushed by the system metrics stream.
+	maxSysInfoStreamInterval = time.Minute
+
+	// maxTopProcesses is the maximum number of the top processes reported in
+	// the system information.
+	maxTopProcesses = 50
+)
+
+// registerSystemInfoHandlers registers the HTTP handlers of the host system
+// information.
+func (web *webAPI) registerSystemInfoHandlers() {
+	aghhttp.RegisterVersions(
+		web.httpReg,
+		http.MethodGet,
+		"/control/systeminfo",
+		web.handleGetSystemInfo,
+		newV1Versions(&aghhttp.RouteInfo{
+			Summary:  "Get a snapshot of the host system metrics",
+			Response: systeminfo.Info{},
+		}),
+	)
+	aghhttp.RegisterVersions(
+		web.httpReg,
+		http.MethodGet,
+		"/control/systeminfo/history",
+		web.handleGetSystemInfoHistory,
+		newV1Versions(&aghhttp.RouteInfo{
+			Summary:  "Get the history of the host system metrics",
+			Response: sysInfoHistoryResp{},
+		}),
+	)
+	aghhttp.RegisterVersions(
+		web.httpReg,
+		http.MethodGet,
+		"/control/systeminfo/stream",
+		web.handleSystemInfoStream,
+		newV1Versions(&aghhttp.RouteInfo{
+			Summary: "Stream the host system metrics as Server-Sent Events",
+		}),
+	)
+}
+
+// handleGetSystemInfo is the handler for the GET /control/systeminfo HTTP API.
+// It doesn't depend on the notifications, so the host metrics are available
+// even when no notification channel is configured.
+func (web *webAPI) handleGetSystemInfo(w http.ResponseWriter, r *http.Request) {
+	aghhttp.WriteJSONResponseOK(r.Context(), web.logger, w, r, systeminfo.Collect(r.Context()))
+}
+
+// startSystemInfoHistory validates the history settings of osConf, which may
+// be nil, and starts collecting the samples of the system metrics.  l must not
+// be nil.
+func startSystemInfoHistory(ctx context.Context, l *slog.Logger, osConf *osConfig) (err error) {
+	interval, retention := defaultSysInfoHistoryInterval, defaultSysInfoHistoryRetention
+	if osConf != nil {
+		if osConf.HistoryInterval != 0 {
+			interval = time.Duration(osConf.HistoryInterval)
+		}
+
+		if osConf.HistoryRetention != 0 {
+			retention = time.Duration(osConf.HistoryRetention)
+		}
+	}
+
+	if interval < time.Second {
+		return fmt.Errorf("history_interval: must be at least 1s, got %s", interval)
+	} else if retention < interval {
+		return fmt.Errorf(
+			"history_retention: must not be less than history_interval, got %s",
+			retention,
+		)
+	} else if size := retention / interval; size > maxSysInfoHistorySize {
+		return fmt.Errorf(
+			"history_retention: must not exceed %d samples, got %d",
+			maxSysInfoHistorySize,
+			size,
+		)
+	}
+
+	h := systeminfo.NewHistory(int(retention / interval))
+	globalContext.sysInfoHistory = h
+
+	go h.Run(ctx, interval)
+
+	l.DebugContext(ctx, "system info history started", "interval", interval, "retention", retention)
+
+	return nil
+}
+
+// configurePower validates the power probe configuration of osConf, which may
+// be nil, and applies it.
+func configurePower(osConf *osConfig) (err error) {
+	conf := &systeminfo.PowerConfig{}
+	if osConf != nil && osConf.Power != nil {
+		p := osConf.Power
+		if p.NUTAddress != "" && p.NUTUPS == "" {
+			return errors.Error("nut_ups: must not be empty when nut_address is set")
+		}
+
+		conf.NUTAddress, conf.NUTUPS, conf.Battery = p.NUTAddress, p.NUTUPS, p.Battery
+	}
+
+	systeminfo.SetPowerConfig(conf)
+
+	return nil
+}
+
+// configureSMART enables the SMART health probe of the drives, if it's enabled
+// in osConf, which may be nil.  The drive holding querylogDir is watched for
+// degradation.  l must not be nil.
+func configureSMART(ctx context.Context, l *slog.Logger, osConf *osConfig, querylogDir string) {
+	if osConf == nil || !osConf.SMART {
+		return
+	}
+
+	dir, err := filepath.Abs(querylogDir)
+	if err != nil {
+		l.WarnContext(ctx, "resolving query log directory", slogutil.KeyError, err)
+
+		dir = querylogDir
+	}
+
+	systeminfo.SetSMARTConfig(&systeminfo.SMARTConfig{
+		CmdCons:    executil.SystemCommandConstructor{},
+		WatchPaths: []string{dir},
+	})
+
+	l.DebugContext(ctx, "smart health probe enabled", "watch_path", dir)
+}
+
+// sysInfoHistoryResp is the response to the GET /control/systeminfo/history
+// HTTP API.
+type sysInfoHistoryResp struct {
+	// Samples are the samples of the system metrics, oldest first.
+	Samples []systeminfo.Sample `json:"samples"`
+}
+
+// handleGetSystemInfoHistory is the handler for the GET
+// /control/systeminfo/history HTTP API.  The optional since query parameter
+// is an RFC 3339 time, after which the samples are returned.
+func (web *webAPI) handleGetSystemInfoHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var since time.Time
+	if s := r.URL.Query().Get("since"); s != "" {
+		var err error
+		since, err = time.Parse(time.RFC3339, s)
+		if err != nil {
+			aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusBadRequest, "parsing since: %s", err)
+
+			return
+		}
+	}
+
+	resp := &sysInfoHistoryResp{
+		Samples: []systeminfo.Sample{},
+	}
+
+	if h := globalContext.sysInfoHistory; h != nil {
+		resp.Samples = h.Samples(since)
+	}
+
+	aghhttp.WriteJSONResponseOK(ctx, web.logger, w, r, resp)
+}
+
+// handleSystemInfoStream is the handler for the GET /control/systeminfo/stream
+// HTTP API.  It pushes the snapshots of the system metrics as Server-Sent
+// Events until the client disconnects.  The optional interval query parameter
+// is the number of seconds between the snapshots.
+func (web *webAPI) handleSystemInfoStream(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	interval := defaultSysInfoStreamInterval
+	if s := r.URL.Query().Get("interval"); s != "" {
+		sec, err := strconv.Atoi(s)
+		if err != nil || sec < 1 || time.Duration(sec)*time.Second > maxSysInfoStreamInterval {
+			aghhttp.ErrorAndLog(
+				ctx,
+				web.logger,
+				r,
+				w,
+				http.StatusBadRequest,
+				"interval: must be between 1 and %d seconds, got %q",
+				int(maxSysInfoStreamInterval.Seconds()),
+				s,
+			)
+
+			return
+		}
+
+		interval = time.Duration(sec) * time.Second
+	}
+
+	rc := http.NewResponseController(w)
+
+	// The stream outlives the write timeout of the server.  If the deadline
+	// can't be removed, the server closes the stream after the timeout and
+	// the client reconnects.
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		web.logger.DebugContext(ctx, "systeminfo stream: removing deadline", slogutil.KeyError, err)
+	}
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	h.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		writeSSEEvent(w, "systeminfo", systeminfo.Collect(ctx))
+		if err := rc.Flush(); err != nil {
+			web.logger.DebugContext(ctx, "systeminfo stream: flushing", slogutil.KeyError, err)
+
+			return
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
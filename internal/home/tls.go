@@ -37,7 +37,8 @@ type tlsManager struct {
 	// logger is used for logging the operation of the TLS Manager.
 	logger *slog.Logger
 
-	// mu protects status, certLastMod, extTLSConf, and servePlainDNS.
+	// mu protects status, certLastMod, keyLastMod, extTLSConf, and
+	// servePlainDNS.
 	mu *sync.Mutex
 
 	// status is the current status of the configuration.  It is never nil.
@@ -46,6 +47,9 @@ type tlsManager struct {
 	// certLastMod is the last modification time of the certificate file.
 	certLastMod time.Time
 
+	// keyLastMod is the last modification time of the private key file.
+	keyLastMod time.Time
+
 	// rootCerts is a pool of root CAs for TLSv1.2.
 	rootCerts *x509.CertPool
 
@@ -83,6 +87,10 @@ type tlsManager struct {
 	// case the "SSL/TLS issue" feature is unavailable.
 	acme *acme.Manager
 
+	// ocsp staples the OCSP responses for the current certificate.  It may be
+	// nil, in which case nothing is stapled.
+	ocsp *aghtls.OCSPStapler
+
 	// acmeJobMu protects acmeJob.
 	acmeJobMu sync.Mutex
 
@@ -179,6 +187,32 @@ func newTLSManager(ctx context.Context, conf *tlsManagerConfig) (m *tlsManager,
 	return m, nil
 }
 
+// initOCSPStapler initializes the OCSP stapler for the current certificate
+// using c to send the requests.  It must be called before [tlsManager.start].
+// c must not be nil.
+func (m *tlsManager) initOCSPStapler(ctx context.Context, c *http.Client) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.ocsp = aghtls.NewOCSPStapler(&aghtls.OCSPStaplerConfig{
+		Logger:     m.logger.With(slogutil.KeyPrefix, "ocsp_stapler"),
+		HTTPClient: c,
+	})
+
+	m.updateOCSPStapler(ctx)
+}
+
+// updateOCSPStapler sets the current certificate chain to the OCSP stapler.
+// m.mu is expected to be locked.
+func (m *tlsManager) updateOCSPStapler(ctx context.Context) {
+	var chain []byte
+	if m.extTLSConf.Enabled {
+		chain = m.extTLSConf.CertificateChainData
+	}
+
+	m.ocsp.Set(ctx, chain)
+}
+
 // setWebAPI stores the provided web API.  It must be called before
 // [tlsManager.start], [tlsManager.reload], [tlsManager.handleTLSConfigure], or
 // [tlsManager.validateTLSSettings].
@@ -196,21 +230,49 @@ func (m *tlsManager) extendedTLSConfig() (extTLSConf *tlsConfigSettings) {
 	return m.extTLSConf.clone()
 }
 
-// setCertFileTime sets [tlsManager.certLastMod] from the certificate.  If there
-// are errors, setCertFileTime logs them.  m.mu is expected to be locked.
+// setCertFileTime sets [tlsManager.certLastMod] and [tlsManager.keyLastMod]
+// from the certificate and the private key files.  If there are errors,
+// setCertFileTime logs them.  m.mu is expected to be locked.
 func (m *tlsManager) setCertFileTime(ctx context.Context) {
-	if len(m.extTLSConf.CertificatePath) == 0 {
+	certMod, keyMod, err := tlsFilesModTime(m.extTLSConf)
+	if err != nil {
+		m.logger.ErrorContext(ctx, "looking up tls files", slogutil.KeyError, err)
+
 		return
 	}
 
-	fi, err := os.Stat(m.extTLSConf.CertificatePath)
+	m.certLastMod, m.keyLastMod = certMod, keyMod
+}
+
+// tlsFilesModTime returns the modification times of the certificate and the
+// private key files from conf.  The time is zero for an empty path.
+func tlsFilesModTime(conf *tlsConfigSettings) (certMod, keyMod time.Time, err error) {
+	certMod, err = fileModTime(conf.CertificatePath)
 	if err != nil {
-		m.logger.ErrorContext(ctx, "looking up certificate path", slogutil.KeyError, err)
+		return time.Time{}, time.Time{}, fmt.Errorf("certificate: %w", err)
+	}
 
-		return
+	keyMod, err = fileModTime(conf.PrivateKeyPath)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("private key: %w", err)
+	}
+
+	return certMod, keyMod, nil
+}
+
+// fileModTime returns the modification time of the file at path in UTC, or a
+// zero time if path is empty.
+func fileModTime(path string) (mod time.Time, err error) {
+	if path == "" {
+		return time.Time{}, nil
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
 	}
 
-	m.certLastMod = fi.ModTime().UTC()
+	return fi.ModTime().UTC(), nil
 }
 
 // start updates the configuration of t and starts it.
@@ -230,11 +292,24 @@ func (m *tlsManager) start(ctx context.Context) {
 
 	go m.handleCertFileChange(ctx)
 
+	if m.ocsp != nil {
+		err := m.ocsp.Start(ctx)
+		if err != nil {
+			m.logger.ErrorContext(ctx, "starting ocsp stapler", slogutil.KeyError, err)
+		}
+	}
+
 	if m.acme != nil {
 		go m.certExpiryLoop(ctx)
 	}
 }
 
+// certReloadDelay is the time to wait after the last change of the TLS files
+// before reloading them.  Renewal tools, such as certbot or acme.sh, usually
+// replace the certificate and the key one after another, so reloading on the
+// first event would either fail or restart the servers twice.
+const certReloadDelay = 1 * time.Second
+
 // handleCertFileChange handles changes in the certificate file.  It's intended
 // to be run as a goroutine.
 func (m *tlsManager) handleCertFileChange(ctx context.Context) {
@@ -248,13 +323,44 @@ func (m *tlsManager) handleCertFileChange(ctx context.Context) {
 	}
 
 	for range updates {
+		if !waitUpdatesSettled(ctx, updates, certReloadDelay) {
+			return
+		}
+
 		m.logger.DebugContext(ctx, "reloading")
 
 		m.reload(ctx)
 	}
 }
 
-// reload updates the configuration and restarts the TLS manager.  It logs any
+// waitUpdatesSettled waits until no signals are received from updates for the
+// duration of delay.  It returns false if ctx is done or updates is closed.
+func waitUpdatesSettled(
+	ctx context.Context,
+	updates <-chan aghtls.UpdateSignal,
+	delay time.Duration,
+) (ok bool) {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case _, ok = <-updates:
+			if !ok {
+				return false
+			}
+
+			timer.Reset(delay)
+		case <-timer.C:
+			return true
+		}
+	}
+}
+
+// reload updates the configuration and restarts the TLS manager, if either
+// the certificate or the private key file has been modified.  It logs any
 // encountered errors.
 //
 // TODO(s.chzhen):  Consider returning an error.
@@ -268,21 +374,20 @@ func (m *tlsManager) reload(ctx context.Context) {
 		return
 	}
 
-	certPath := tlsConfPtr.CertificatePath
-	fi, err := os.Stat(certPath)
+	certMod, keyMod, err := tlsFilesModTime(tlsConfPtr)
 	if err != nil {
-		m.logger.ErrorContext(ctx, "checking certificate file", slogutil.KeyError, err)
+		m.logger.ErrorContext(ctx, "checking tls files", slogutil.KeyError, err)
 
 		return
 	}
 
-	if fi.ModTime().UTC().Equal(m.certLastMod) {
-		m.logger.InfoContext(ctx, "certificate file is not modified")
+	if certMod.Equal(m.certLastMod) && keyMod.Equal(m.keyLastMod) {
+		m.logger.DebugContext(ctx, "tls files are not modified")
 
 		return
 	}
 
-	m.logger.InfoContext(ctx, "certificate file is modified")
+	m.logger.InfoContext(ctx, "tls files are modified")
 
 	tlsConf := *tlsConfPtr
 	status := &tlsConfigStatus{}
@@ -296,8 +401,9 @@ func (m *tlsManager) reload(ctx context.Context) {
 
 	m.extTLSConf = &tlsConf
 	m.status = status
+	m.updateOCSPStapler(ctx)
 
-	m.certLastMod = fi.ModTime().UTC()
+	m.certLastMod, m.keyLastMod = certMod, keyMod
 
 	err = m.reconfigureDNSServer(ctx)
 	if err != nil {
@@ -414,7 +520,7 @@ func loadPrivateKeyData(extTLSConf *tlsConfigSettings) (err error) {
 		}
 	}
 
-	return nil
+	return decryptPrivateKeyData(extTLSConf)
 }
 
 // tlsConfigStatus contains the status of a certificate chain and key pair.
@@ -561,6 +667,7 @@ func (m *tlsManager) setConfig(
 	}
 
 	m.extTLSConf = &newConf
+	m.updateOCSPStapler(ctx)
 
 	m.status = status
 
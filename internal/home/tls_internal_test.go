@@ -261,8 +261,9 @@ func TestTLSManager_Reload(t *testing.T) {
 	require.NoError(t, err)
 
 	const (
-		snBefore int64 = 1
-		snAfter  int64 = 2
+		snBefore  int64 = 1
+		snAfter   int64 = 2
+		snKeyOnly int64 = 3
 	)
 
 	tmpDir := t.TempDir()
@@ -304,6 +305,46 @@ func TestTLSManager_Reload(t *testing.T) {
 
 	extTLSConf = m.extendedTLSConfig()
 	assertCertSerialNumber(t, extTLSConf, snAfter)
+
+	// Make sure that the change of the private key file alone is enough.
+	certFI, err := os.Stat(certPath)
+	require.NoError(t, err)
+
+	certDER, key = newCertAndKey(t, snKeyOnly)
+	writeCertAndKey(t, certDER, certPath, key, keyPath)
+
+	err = os.Chtimes(certPath, certFI.ModTime(), certFI.ModTime())
+	require.NoError(t, err)
+
+	keyMod := certFI.ModTime().Add(time.Second)
+	err = os.Chtimes(keyPath, keyMod, keyMod)
+	require.NoError(t, err)
+
+	m.reload(ctx)
+
+	extTLSConf = m.extendedTLSConfig()
+	assertCertSerialNumber(t, extTLSConf, snKeyOnly)
+}
+
+func TestWaitUpdatesSettled(t *testing.T) {
+	const delay = 10 * time.Millisecond
+
+	t.Run("settled", func(t *testing.T) {
+		updates := make(chan aghtls.UpdateSignal, 1)
+		updates <- aghtls.UpdateSignal{}
+
+		ctx := testutil.ContextWithTimeout(t, testTimeout)
+		assert.True(t, waitUpdatesSettled(ctx, updates, delay))
+		assert.Empty(t, updates)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		updates := make(chan aghtls.UpdateSignal)
+		close(updates)
+
+		ctx := testutil.ContextWithTimeout(t, testTimeout)
+		assert.False(t, waitUpdatesSettled(ctx, updates, delay))
+	})
 }
 
 func TestTLSManager_HandleTLSStatus(t *testing.T) {
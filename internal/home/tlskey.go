@@ -0,0 +1,112 @@
+package home
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghtls"
+	"github.com/AdguardTeam/golibs/errors"
+	"golang.org/x/term"
+)
+
+// envTLSKeyPassphrase is the name of the environment variable containing the
+// passphrase of the encrypted TLS private key.
+const envTLSKeyPassphrase = "ADGUARD_HOME_TLS_KEY_PASSPHRASE"
+
+// errNoKeyPassphrase is returned when the TLS private key is encrypted, but no
+// passphrase is provided.
+const errNoKeyPassphrase errors.Error = "private key is encrypted, but no passphrase is provided; " +
+	"set " + envTLSKeyPassphrase + " or tls.private_key_passphrase_file"
+
+// keyPassphrase returns the passphrase of the encrypted private key from conf.
+// The environment variable takes precedence over the passphrase file, which in
+// turn takes precedence over the passphrase entered at startup.
+func keyPassphrase(conf *tlsConfigSettings) (pass []byte, err error) {
+	if env, ok := os.LookupEnv(envTLSKeyPassphrase); ok {
+		return []byte(env), nil
+	}
+
+	if conf.PrivateKeyPassphraseFile != "" {
+		pass, err = os.ReadFile(conf.PrivateKeyPassphraseFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading passphrase file: %w", err)
+		}
+
+		return bytes.TrimRight(pass, "\r\n"), nil
+	}
+
+	if globalContext.tlsKeyPassphrase != nil {
+		return globalContext.tlsKeyPassphrase, nil
+	}
+
+	return nil, errNoKeyPassphrase
+}
+
+// decryptPrivateKeyData decrypts [tlsConfigSettings.PrivateKeyData] in place,
+// if it's encrypted.
+func decryptPrivateKeyData(conf *tlsConfigSettings) (err error) {
+	if !aghtls.IsEncryptedPrivateKeyPEM(conf.PrivateKeyData) {
+		return nil
+	}
+
+	pass, err := keyPassphrase(conf)
+	if err != nil {
+		// Don't wrap the error, because it's informative enough as is.
+		return err
+	}
+
+	conf.PrivateKeyData, err = aghtls.DecryptPrivateKeyPEM(conf.PrivateKeyData, pass)
+	if err != nil {
+		return fmt.Errorf("decrypting private key: %w", err)
+	}
+
+	return nil
+}
+
+// promptTLSKeyPassphrase asks for the passphrase of the encrypted private key
+// from conf on the terminal, if the key is encrypted and the passphrase isn't
+// provided otherwise.  It does nothing if standard input isn't a terminal, for
+// example when running as a service.
+func promptTLSKeyPassphrase(ctx context.Context, l *slog.Logger, conf *tlsConfigSettings) (err error) {
+	if !conf.Enabled {
+		return nil
+	}
+
+	data := []byte(conf.PrivateKey)
+	if conf.PrivateKeyPath != "" {
+		data, err = os.ReadFile(conf.PrivateKeyPath)
+		if err != nil {
+			// The error is reported when the key is loaded.
+			return nil
+		}
+	}
+
+	if !aghtls.IsEncryptedPrivateKeyPEM(data) {
+		return nil
+	}
+
+	if _, err = keyPassphrase(conf); !errors.Is(err, errNoKeyPassphrase) {
+		return nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		l.DebugContext(ctx, "not prompting for key passphrase: not a terminal")
+
+		return nil
+	}
+
+	_, _ = fmt.Fprint(os.Stderr, "Enter the passphrase of the TLS private key: ")
+	pass, err := term.ReadPassword(fd)
+	_, _ = fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return fmt.Errorf("reading passphrase: %w", err)
+	}
+
+	globalContext.tlsKeyPassphrase = pass
+
+	return nil
+}
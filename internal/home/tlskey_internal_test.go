@@ -0,0 +1,77 @@
+package home
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testEncryptedPrivateKeyPath is the path to [testPrivateKeyPath] encrypted
+// with [testKeyPassphrase].
+const testEncryptedPrivateKeyPath = "./testdata/key_encrypted.pem"
+
+// testKeyPassphrase is the passphrase of the encrypted test private key.
+const testKeyPassphrase = "test-passphrase"
+
+func TestLoadPrivateKeyData_encrypted(t *testing.T) {
+	wantKey := requireReadFile(t, testPrivateKeyPath)
+
+	passFile := filepath.Join(t.TempDir(), "passphrase")
+	err := os.WriteFile(passFile, []byte(testKeyPassphrase+"\n"), 0o600)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		env        string
+		passFile   string
+		wantErrMsg string
+	}{{
+		name:       "env",
+		env:        testKeyPassphrase,
+		passFile:   "",
+		wantErrMsg: "",
+	}, {
+		name:       "file",
+		env:        "",
+		passFile:   passFile,
+		wantErrMsg: "",
+	}, {
+		name:       "bad_passphrase",
+		env:        "wrong",
+		passFile:   "",
+		wantErrMsg: "decrypting private key: incorrect private key passphrase",
+	}, {
+		name:       "no_passphrase",
+		env:        "",
+		passFile:   "",
+		wantErrMsg: errNoKeyPassphrase.Error(),
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.env != "" {
+				t.Setenv(envTLSKeyPassphrase, tc.env)
+			}
+
+			conf := &tlsConfigSettings{
+				PrivateKeyPath:           testEncryptedPrivateKeyPath,
+				PrivateKeyPassphraseFile: tc.passFile,
+			}
+
+			err = loadPrivateKeyData(conf)
+			testutil.AssertErrorMsg(t, tc.wantErrMsg, err)
+			if tc.wantErrMsg != "" {
+				return
+			}
+
+			_, err = validatePKey(conf.PrivateKeyData)
+			require.NoError(t, err)
+
+			assert.Equal(t, string(wantKey), string(conf.PrivateKeyData))
+		})
+	}
+}
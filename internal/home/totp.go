@@ -0,0 +1,430 @@
+package home
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghuser"
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+const (
+	// totpIssuer is the issuer of the TOTP secrets shown by the authenticator
+	// apps.
+	totpIssuer = "AdGuard Home"
+
+	// totpSecretLen is the length of the TOTP secrets in bytes, as recommended
+	// by RFC 4226 for HMAC-SHA1.
+	totpSecretLen = 20
+
+	// totpDigits is the number of digits in a TOTP code.
+	totpDigits = 6
+
+	// totpPeriod is the time step of the TOTP codes.
+	totpPeriod = 30 * time.Second
+
+	// totpSkew is the number of time steps before and after the current one,
+	// which codes are also accepted to tolerate the clock drift.
+	totpSkew = 1
+
+	// totpBackupCodesNum is the number of the backup codes generated on setup.
+	totpBackupCodesNum = 10
+
+	// totpBackupCodeLen is the length of a backup code without the separator.
+	totpBackupCodeLen = 10
+)
+
+const (
+	// errTOTPRequired is returned when a web user with the enabled two-factor
+	// authentication logs in without a code.  The web UI relies on this
+	// message to ask for the code.
+	errTOTPRequired errors.Error = "totp code required"
+
+	// errTOTPInvalid is returned when the two-factor authentication code is
+	// wrong.
+	errTOTPInvalid errors.Error = "invalid totp code"
+
+	// errTOTPBasicAuth is returned when a web user with the enabled two-factor
+	// authentication tries to use the basic authentication, which can't carry
+	// the code.
+	errTOTPBasicAuth errors.Error = "basic authentication is disabled for users with totp"
+)
+
+// totpEncoding is the encoding of the TOTP secrets expected by the
+// authenticator apps.
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// totpConfig is the two-factor authentication configuration of a web user.
+type totpConfig struct {
+	// Secret is the base32-encoded TOTP secret without padding.
+	Secret string `yaml:"secret"`
+
+	// BackupCodes are the hexadecimal SHA-256 hashes of the unused backup
+	// codes.
+	BackupCodes []string `yaml:"backup_codes"`
+}
+
+// clone returns a deep copy of c.  c may be nil.
+func (c *totpConfig) clone() (cloned *totpConfig) {
+	if c == nil {
+		return nil
+	}
+
+	return &totpConfig{
+		Secret:      c.Secret,
+		BackupCodes: slices.Clone(c.BackupCodes),
+	}
+}
+
+// validate returns an error if c is invalid.
+func (c *totpConfig) validate() (err error) {
+	var errs []error
+	if key, decErr := totpEncoding.DecodeString(c.Secret); decErr != nil || len(key) == 0 {
+		errs = append(errs, errors.Error("secret: bad base32 value"))
+	}
+
+	for i, h := range c.BackupCodes {
+		if _, decErr := hex.DecodeString(h); decErr != nil || len(h) != sha256.Size*2 {
+			errs = append(errs, fmt.Errorf("backup_codes: at index %d: bad value %q", i, h))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// totpCode returns the TOTP code of key for the time step counter, as defined
+// by RFC 6238 with HMAC-SHA1.
+func totpCode(key []byte, counter uint64) (code string) {
+	mac := hmac.New(sha1.New, key)
+	_ = binary.Write(mac, binary.BigEndian, counter)
+	sum := mac.Sum(nil)
+
+	off := sum[len(sum)-1] & 0x0F
+	bin := binary.BigEndian.Uint32(sum[off:]) & 0x7FFF_FFFF
+
+	return fmt.Sprintf("%0*d", totpDigits, bin%1_000_000)
+}
+
+// totpStep returns the TOTP time step of t.
+func totpStep(t time.Time) (step int64) {
+	return t.Unix() / int64(totpPeriod/time.Second)
+}
+
+// checkTOTP returns the time step, which code matches for key at now, and true
+// if there is one.
+func checkTOTP(key []byte, code string, now time.Time) (step int64, ok bool) {
+	if len(code) != totpDigits {
+		return 0, false
+	}
+
+	cur := totpStep(now)
+	for step = cur - totpSkew; step <= cur+totpSkew; step++ {
+		want := totpCode(key, uint64(step))
+		if subtle.ConstantTimeCompare([]byte(code), []byte(want)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// totpURI returns the otpauth URI, which the authenticator apps use to add the
+// secret of the web user with login.
+func totpURI(login aghuser.Login, secret string) (uri string) {
+	q := url.Values{}
+	q.Set("secret", secret)
+	q.Set("issuer", totpIssuer)
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", fmt.Sprint(totpDigits))
+	q.Set("period", fmt.Sprint(int(totpPeriod.Seconds())))
+
+	u := &url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + totpIssuer + ":" + string(login),
+		RawQuery: q.Encode(),
+	}
+
+	return u.String()
+}
+
+// normalizeBackupCode removes the separators and the case from code.
+func normalizeBackupCode(code string) (norm string) {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+// hashBackupCode returns the hexadecimal SHA-256 hash of the normalized code.
+// The codes are random, so a slow password hash isn't necessary.
+func hashBackupCode(code string) (hash string) {
+	sum := sha256.Sum256([]byte(normalizeBackupCode(code)))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// newTOTPConfig returns a new TOTP configuration with a random secret and the
+// backup codes, which are only returned once.  If an error occurs during random
+// generation, it will cause the program to crash.
+func newTOTPConfig() (c *totpConfig, backupCodes []string) {
+	key := make([]byte, totpSecretLen)
+	_, _ = rand.Read(key)
+
+	c = &totpConfig{
+		Secret:      totpEncoding.EncodeToString(key),
+		BackupCodes: make([]string, 0, totpBackupCodesNum),
+	}
+
+	backupCodes = make([]string, 0, totpBackupCodesNum)
+	for range totpBackupCodesNum {
+		b := make([]byte, totpBackupCodeLen*5/8)
+		_, _ = rand.Read(b)
+
+		code := strings.ToLower(totpEncoding.EncodeToString(b))
+		code = code[:totpBackupCodeLen/2] + "-" + code[totpBackupCodeLen/2:]
+
+		backupCodes = append(backupCodes, code)
+		c.BackupCodes = append(c.BackupCodes, hashBackupCode(code))
+	}
+
+	return c, backupCodes
+}
+
+// totpStore is the in-memory storage of the two-factor authentication
+// configurations of the web users.  It's safe for concurrent use.  A nil
+// *totpStore has no users with the two-factor authentication.
+type totpStore struct {
+	// mu protects all fields below.
+	mu *sync.Mutex
+
+	// enabled are the configurations of the web users with the enabled
+	// two-factor authentication.
+	enabled map[aghuser.Login]*totpConfig
+
+	// pending are the configurations, which have been set up but not yet
+	// confirmed with a code.  They aren't persisted.
+	pending map[aghuser.Login]*totpConfig
+
+	// lastSteps are the time steps of the last accepted codes, so that a code
+	// can't be used twice.
+	lastSteps map[aghuser.Login]int64
+}
+
+// newTOTPStore returns a new properly initialized *totpStore with the
+// configurations of users from the configuration file.
+func newTOTPStore(users []webUser) (s *totpStore, err error) {
+	s = &totpStore{
+		mu:        &sync.Mutex{},
+		enabled:   map[aghuser.Login]*totpConfig{},
+		pending:   map[aghuser.Login]*totpConfig{},
+		lastSteps: map[aghuser.Login]int64{},
+	}
+
+	for _, u := range users {
+		if u.TOTP == nil {
+			continue
+		}
+
+		if err = u.TOTP.validate(); err != nil {
+			return nil, fmt.Errorf("user %q: %w", u.Name, err)
+		}
+
+		s.enabled[aghuser.Login(u.Name)] = u.TOTP.clone()
+	}
+
+	return s, nil
+}
+
+// isEnabled returns true if the web user with login has the enabled two-factor
+// authentication.
+func (s *totpStore) isEnabled(login aghuser.Login) (ok bool) {
+	if s == nil {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok = s.enabled[login]
+
+	return ok
+}
+
+// config returns the copy of the configuration of the web user with login or
+// nil, if the two-factor authentication isn't enabled.
+func (s *totpStore) config(login aghuser.Login) (c *totpConfig) {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.enabled[login].clone()
+}
+
+// setup generates a new pending configuration for the web user with login and
+// returns its secret and backup codes.
+func (s *totpStore) setup(login aghuser.Login) (secret string, backupCodes []string) {
+	c, backupCodes := newTOTPConfig()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pending[login] = c
+
+	return c.Secret, backupCodes
+}
+
+// enable enables the pending configuration of the web user with login, if code
+// is valid for it at now.
+func (s *totpStore) enable(login aghuser.Login, code string, now time.Time) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.pending[login]
+	if !ok {
+		return errors.Error("totp is not set up")
+	}
+
+	if !s.checkCode(login, c, code, now, false) {
+		return errTOTPInvalid
+	}
+
+	delete(s.pending, login)
+	s.enabled[login] = c
+
+	return nil
+}
+
+// disable disables the two-factor authentication of the web user with login, if
+// code is a valid TOTP or backup code.
+func (s *totpStore) disable(login aghuser.Login, code string, now time.Time) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.enabled[login]
+	if !ok {
+		return errors.Error("totp is not enabled")
+	}
+
+	if !s.checkCode(login, c, code, now, true) {
+		return errTOTPInvalid
+	}
+
+	delete(s.enabled, login)
+	delete(s.lastSteps, login)
+
+	return nil
+}
+
+// verify returns true if code is a valid TOTP or backup code of the web user
+// with login at now.  usedBackup is true if a backup code has been consumed, so
+// the configuration needs to be saved.
+func (s *totpStore) verify(
+	login aghuser.Login,
+	code string,
+	now time.Time,
+) (ok, usedBackup bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.enabled[login]
+	if !ok {
+		return false, false
+	}
+
+	n := len(c.BackupCodes)
+	ok = s.checkCode(login, c, code, now, true)
+
+	return ok, len(c.BackupCodes) < n
+}
+
+// checkCode returns true if code is a valid TOTP code of c at now, which hasn't
+// been used yet, or, if allowBackup is true, one of its backup codes.  The used
+// backup code is removed from c.  s.mu must be locked.
+func (s *totpStore) checkCode(
+	login aghuser.Login,
+	c *totpConfig,
+	code string,
+	now time.Time,
+	allowBackup bool,
+) (ok bool) {
+	key, err := totpEncoding.DecodeString(c.Secret)
+	if err != nil {
+		// Should not happen, since the secrets are validated.
+		return false
+	}
+
+	if step, stepOK := checkTOTP(key, strings.TrimSpace(code), now); stepOK {
+		if last, has := s.lastSteps[login]; has && step <= last {
+			return false
+		}
+
+		s.lastSteps[login] = step
+
+		return true
+	}
+
+	if !allowBackup {
+		return false
+	}
+
+	hash := hashBackupCode(code)
+	i := slices.IndexFunc(c.BackupCodes, func(h string) (found bool) {
+		return subtle.ConstantTimeCompare([]byte(h), []byte(hash)) == 1
+	})
+	if i < 0 {
+		return false
+	}
+
+	c.BackupCodes = slices.Delete(c.BackupCodes, i, i+1)
+
+	return true
+}
+
+// backupCodesLeft returns the number of the unused backup codes of the web user
+// with login.
+func (s *totpStore) backupCodesLeft(login aghuser.Login) (n int) {
+	if s == nil {
+		return 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.enabled[login]
+	if !ok {
+		return 0
+	}
+
+	return len(c.BackupCodes)
+}
+
+// renameUser moves the configuration of the web user, which login has changed.
+func (s *totpStore) renameUser(oldLogin, newLogin aghuser.Login) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, m := range []map[aghuser.Login]*totpConfig{s.enabled, s.pending} {
+		if c, ok := m[oldLogin]; ok {
+			delete(m, oldLogin)
+			m[newLogin] = c
+		}
+	}
+
+	if step, ok := s.lastSteps[oldLogin]; ok {
+		delete(s.lastSteps, oldLogin)
+		s.lastSteps[newLogin] = step
+	}
+}
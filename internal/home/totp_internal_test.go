@@ -0,0 +1,274 @@
+package home
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/aghuser"
+	"github.com/AdguardTeam/golibs/httphdr"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestTOTPCode(t *testing.T) {
+	t.Parallel()
+
+	// The test vectors for SHA-1 from the appendix B of RFC 6238, truncated to
+	// six digits.
+	key := []byte("12345678901234567890")
+
+	testCases := []struct {
+		want string
+		unix int64
+	}{{
+		want: "287082",
+		unix: 59,
+	}, {
+		want: "081804",
+		unix: 1_111_111_109,
+	}, {
+		want: "050471",
+		unix: 1_111_111_111,
+	}, {
+		want: "005924",
+		unix: 1_234_567_890,
+	}, {
+		want: "279037",
+		unix: 2_000_000_000,
+	}, {
+		want: "353130",
+		unix: 20_000_000_000,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.want, func(t *testing.T) {
+			t.Parallel()
+
+			step := totpStep(time.Unix(tc.unix, 0))
+			assert.Equal(t, tc.want, totpCode(key, uint64(step)))
+		})
+	}
+}
+
+func TestCheckTOTP(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("12345678901234567890")
+	now := time.Unix(1_111_111_111, 0)
+	cur := totpStep(now)
+
+	testCases := []struct {
+		name   string
+		code   string
+		wantOK bool
+	}{{
+		name:   "current",
+		code:   totpCode(key, uint64(cur)),
+		wantOK: true,
+	}, {
+		name:   "previous",
+		code:   totpCode(key, uint64(cur-1)),
+		wantOK: true,
+	}, {
+		name:   "next",
+		code:   totpCode(key, uint64(cur+1)),
+		wantOK: true,
+	}, {
+		name:   "too_old",
+		code:   totpCode(key, uint64(cur-2)),
+		wantOK: false,
+	}, {
+		name:   "bad_length",
+		code:   "12345",
+		wantOK: false,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, ok := checkTOTP(key, tc.code, now)
+			assert.Equal(t, tc.wantOK, ok)
+		})
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	t.Parallel()
+
+	got := totpURI("admin", "JBSWY3DPEHPK3PXP")
+	assert.Equal(
+		t,
+		"otpauth://totp/AdGuard%20Home:admin?"+
+			"algorithm=SHA1&digits=6&issuer=AdGuard+Home&period=30&secret=JBSWY3DPEHPK3PXP",
+		got,
+	)
+}
+
+// currentTOTPCode returns the current TOTP code of base32-encoded secret.
+func currentTOTPCode(tb testing.TB, secret string, now time.Time) (code string) {
+	tb.Helper()
+
+	key, err := totpEncoding.DecodeString(secret)
+	require.NoError(tb, err)
+
+	return totpCode(key, uint64(totpStep(now)))
+}
+
+func TestTOTPStore(t *testing.T) {
+	t.Parallel()
+
+	const login aghuser.Login = "admin"
+
+	s, err := newTOTPStore(nil)
+	require.NoError(t, err)
+
+	now := time.Now()
+	secret, backupCodes := s.setup(login)
+	require.Len(t, backupCodes, totpBackupCodesNum)
+
+	assert.False(t, s.isEnabled(login))
+	assert.ErrorIs(t, s.enable(login, "000000", now), errTOTPInvalid)
+
+	// The backup codes can't confirm the setup.
+	assert.ErrorIs(t, s.enable(login, backupCodes[0], now), errTOTPInvalid)
+
+	code := currentTOTPCode(t, secret, now)
+	require.NoError(t, s.enable(login, code, now))
+	assert.True(t, s.isEnabled(login))
+
+	ok, _ := s.verify(login, code, now)
+	assert.False(t, ok, "replayed code")
+
+	ok, usedBackup := s.verify(login, strings.ToUpper(backupCodes[0]), now)
+	assert.True(t, ok)
+	assert.True(t, usedBackup)
+	assert.Equal(t, totpBackupCodesNum-1, s.backupCodesLeft(login))
+
+	ok, _ = s.verify(login, backupCodes[0], now)
+	assert.False(t, ok, "reused backup code")
+
+	c := s.config(login)
+	require.NotNil(t, c)
+	require.NoError(t, c.validate())
+
+	assert.Equal(t, secret, c.Secret)
+
+	s.renameUser(login, "root")
+	assert.False(t, s.isEnabled(login))
+
+	require.NoError(t, s.disable("root", backupCodes[1], now))
+	assert.False(t, s.isEnabled("root"))
+
+	var nilStore *totpStore
+	assert.False(t, nilStore.isEnabled(login))
+	assert.Nil(t, nilStore.config(login))
+}
+
+func TestAuth_ServeHTTP_totp(t *testing.T) {
+	storeGlobals(t)
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.DefaultCost)
+	require.NoError(t, err)
+
+	const secret = "JBSWY3DPEHPK3PXP"
+	users := []webUser{{
+		Name:         testUsername,
+		PasswordHash: string(passwordHash),
+		TOTP: &totpConfig{
+			Secret: secret,
+		},
+	}}
+
+	mw := &webMw{}
+	baseMux := http.NewServeMux()
+	httpReg := aghhttp.NewDefaultRegistrar(baseMux, mw.wrap)
+
+	auth, err := newAuth(testutil.ContextWithTimeout(t, testTimeout), &authConfig{
+		baseLogger:     testLogger,
+		mux:            baseMux,
+		rateLimiter:    emptyRateLimiter{},
+		trustedProxies: testTrustedProxies,
+		dbFilename:     filepath.Join(t.TempDir(), "sessions.db"),
+		users:          users,
+		sessionTTL:     testTTL * time.Second,
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { auth.close(testutil.ContextWithTimeout(t, testTimeout)) })
+
+	web := newTestWeb(t, &webConfig{
+		auth:    auth,
+		mux:     baseMux,
+		httpReg: httpReg,
+	})
+
+	globalContext.web = web
+	mw.set(web)
+
+	mux := auth.middleware().Wrap(baseMux)
+
+	login := func(code string) (w *httptest.ResponseRecorder) {
+		creds, mErr := json.Marshal(&loginJSON{Name: testUsername, Password: testPassword, TOTP: code})
+		require.NoError(t, mErr)
+
+		r := httptest.NewRequest(http.MethodPost, "/control/login", bytes.NewReader(creds))
+		r.Header.Set(httphdr.ContentType, aghhttp.HdrValApplicationJSON)
+
+		w = httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+
+		return w
+	}
+
+	w := login("")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), string(errTOTPRequired))
+
+	w = login("000000")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), string(errTOTPInvalid))
+
+	w = login(currentTOTPCode(t, secret, time.Now()))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	r := httptest.NewRequest(http.MethodGet, "/control/totp", nil)
+	r.SetBasicAuth(testUsername, testPassword)
+	assertHandlerStatusCode(t, mux, r, http.StatusUnauthorized)
+
+	var cookie *http.Cookie
+	for _, c := range w.Result().Cookies() {
+		if c.Name == sessionCookieName {
+			cookie = c
+		}
+	}
+
+	require.NotNil(t, cookie)
+
+	r = httptest.NewRequest(http.MethodGet, "/control/totp", nil)
+	r.AddCookie(cookie)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	status := &totpStatusResp{}
+	err = json.NewDecoder(w.Body).Decode(status)
+	require.NoError(t, err)
+
+	assert.Equal(t, &totpStatusResp{Available: true, Enabled: true}, status)
+
+	wu := auth.usersList(testutil.ContextWithTimeout(t, testTimeout))
+	require.Len(t, wu, 1)
+	require.NotNil(t, wu[0].TOTP)
+
+	assert.Equal(t, secret, wu[0].TOTP.Secret)
+}
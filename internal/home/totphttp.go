@@ -0,0 +1,247 @@
+package home
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/aghuser"
+	"github.com/AdguardTeam/AdGuardHome/internal/qrcode"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+)
+
+// registerTOTPHandlers registers the HTTP handlers of the two-factor
+// authentication.
+func (web *webAPI) registerTOTPHandlers() {
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodGet,
+		"/control/totp",
+		web.handleGetTOTP,
+		&aghhttp.RouteInfo{
+			Summary:  "Get the two-factor authentication status of the current user",
+			Response: totpStatusResp{},
+		},
+	)
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodPost,
+		"/control/totp/setup",
+		web.handleSetupTOTP,
+		&aghhttp.RouteInfo{
+			Summary: "Generate a new two-factor authentication secret",
+			Description: "The secret isn't used until it's confirmed with POST /control/totp/enable.  " +
+				"The backup codes aren't shown again.",
+			Request:  totpSetupReq{},
+			Response: totpSetupResp{},
+		},
+	)
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodPost,
+		"/control/totp/enable",
+		web.handleEnableTOTP,
+		&aghhttp.RouteInfo{
+			Summary: "Enable the two-factor authentication with a code from the new secret",
+			Request: totpCodeReq{},
+		},
+	)
+	aghhttp.RegisterWithInfo(
+		web.httpReg,
+		http.MethodPost,
+		"/control/totp/disable",
+		web.handleDisableTOTP,
+		&aghhttp.RouteInfo{
+			Summary: "Disable the two-factor authentication with a code or a backup code",
+			Request: totpCodeReq{},
+		},
+	)
+}
+
+// totpStatusResp is the response to the GET /control/totp HTTP API.
+type totpStatusResp struct {
+	BackupCodesLeft int `json:"backup_codes_left"`
+
+	// Available is false if there are no web users, so the two-factor
+	// authentication can't be set up.
+	Available bool `json:"available"`
+
+	Enabled bool `json:"enabled"`
+}
+
+// totpSetupReq is the request to the POST /control/totp/setup HTTP API.
+type totpSetupReq struct {
+	Password string `json:"password"`
+}
+
+// totpSetupResp is the response to the POST /control/totp/setup HTTP API.
+type totpSetupResp struct {
+	// Secret is the base32-encoded secret for entering it manually.
+	Secret string `json:"secret"`
+
+	// URI is the otpauth URI of the secret.
+	URI string `json:"uri"`
+
+	// QRCode is the SVG image of the QR code with URI.
+	QRCode string `json:"qr_code"`
+
+	// BackupCodes are the single-use codes, which replace the TOTP codes if
+	// the authenticator app is lost.
+	BackupCodes []string `json:"backup_codes"`
+}
+
+// totpCodeReq is the request to the POST /control/totp/enable and POST
+// /control/totp/disable HTTP APIs.
+type totpCodeReq struct {
+	Code string `json:"code"`
+}
+
+// totpUser returns the current web user of the request for the two-factor
+// authentication APIs.  If it returns false, the error has already been
+// written to w.
+func (web *webAPI) totpUser(
+	ctx context.Context,
+	w http.ResponseWriter,
+	r *http.Request,
+) (u *aghuser.User, ok bool) {
+	if web.auth.isUserless || web.auth.isGLiNet {
+		aghhttp.ErrorAndLog(ctx, web.logger, r, w, http.StatusForbidden, "no users configured")
+
+		return nil, false
+	}
+
+	u, ok = webUserFromContext(ctx)
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+
+	return u, ok
+}
+
+// handleGetTOTP is the handler for the GET /control/totp HTTP API.
+func (web *webAPI) handleGetTOTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if web.auth.isUserless || web.auth.isGLiNet {
+		aghhttp.WriteJSONResponseOK(ctx, web.logger, w, r, &totpStatusResp{})
+
+		return
+	}
+
+	u, ok := web.totpUser(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	aghhttp.WriteJSONResponseOK(ctx, web.logger, w, r, &totpStatusResp{
+		BackupCodesLeft: web.auth.totp.backupCodesLeft(u.Login),
+		Available:       true,
+		Enabled:         web.auth.totp.isEnabled(u.Login),
+	})
+}
+
+// handleSetupTOTP is the handler for the POST /control/totp/setup HTTP API.
+func (web *webAPI) handleSetupTOTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	l := web.logger
+
+	u, ok := web.totpUser(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	req := &totpSetupReq{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusBadRequest, "reading req: %s", err)
+
+		return
+	}
+
+	if !u.Password.Authenticate(ctx, req.Password) {
+		// Don't use [http.StatusForbidden], since the web UI treats it as an
+		// expired session.
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusBadRequest, "current password is incorrect")
+
+		return
+	}
+
+	if web.auth.totp.isEnabled(u.Login) {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusConflict, "totp is already enabled")
+
+		return
+	}
+
+	secret, backupCodes := web.auth.totp.setup(u.Login)
+	uri := totpURI(u.Login, secret)
+
+	resp := &totpSetupResp{
+		Secret:      secret,
+		URI:         uri,
+		BackupCodes: backupCodes,
+	}
+
+	qr, err := qrcode.Encode([]byte(uri))
+	if err != nil {
+		// Only possible with very long logins; the secret can still be
+		// entered manually.
+		l.WarnContext(ctx, "encoding totp qr code", slogutil.KeyError, err)
+	} else {
+		resp.QRCode = string(qr.SVG())
+	}
+
+	l.InfoContext(ctx, "totp set up", "user", u.Login)
+
+	aghhttp.WriteJSONResponseOK(ctx, l, w, r, resp)
+}
+
+// handleEnableTOTP is the handler for the POST /control/totp/enable HTTP API.
+func (web *webAPI) handleEnableTOTP(w http.ResponseWriter, r *http.Request) {
+	web.handleTOTPCode(w, r, web.auth.totp.enable, "totp enabled")
+}
+
+// handleDisableTOTP is the handler for the POST /control/totp/disable HTTP
+// API.
+func (web *webAPI) handleDisableTOTP(w http.ResponseWriter, r *http.Request) {
+	web.handleTOTPCode(w, r, web.auth.totp.disable, "totp disabled")
+}
+
+// handleTOTPCode decodes the code from the request, applies change with it to
+// the current web user, and saves the configuration.
+func (web *webAPI) handleTOTPCode(
+	w http.ResponseWriter,
+	r *http.Request,
+	change func(login aghuser.Login, code string, now time.Time) (err error),
+	msg string,
+) {
+	ctx := r.Context()
+	l := web.logger
+
+	u, ok := web.totpUser(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	req := &totpCodeReq{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusBadRequest, "reading req: %s", err)
+
+		return
+	}
+
+	err = change(u.Login, req.Code, time.Now())
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, l, r, w, http.StatusBadRequest, "%s", err)
+
+		return
+	}
+
+	web.confModifier.Apply(ctx)
+
+	l.InfoContext(ctx, msg, "user", u.Login)
+
+	aghhttp.OK(ctx, l, w)
+}
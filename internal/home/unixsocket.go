@@ -0,0 +1,166 @@
+package home
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+)
+
+// unixSocketConfig is the configuration of the Unix domain socket, on which
+// the web UI and the control API are served in addition to or instead of TCP.
+type unixSocketConfig struct {
+	// Path is the path of the socket.  The relative paths are relative to the
+	// working directory.  If it's empty, the socket isn't used.
+	Path string `yaml:"path"`
+
+	// Mode is the octal permissions of the socket file, like "0660".  It's
+	// ignored on Windows.
+	Mode string `yaml:"mode"`
+
+	// DisableTCP, if true, disables the HTTP and HTTPS servers, so that the web
+	// UI and the control API are only available on the socket.  It's ignored on
+	// the first run, since the install wizard must be reachable.
+	DisableTCP bool `yaml:"disable_tcp"`
+}
+
+// defaultUnixSocketMode is the default permissions of the socket file.
+const defaultUnixSocketMode = "0660"
+
+// validate returns an error if c is invalid.  c may be nil.
+func (c *unixSocketConfig) validate() (err error) {
+	if c == nil {
+		return nil
+	}
+
+	if c.Path == "" {
+		if c.DisableTCP {
+			return fmt.Errorf("disable_tcp: %w: path", errors.ErrEmptyValue)
+		}
+
+		return nil
+	}
+
+	_, err = c.mode()
+
+	return err
+}
+
+// mode returns the permissions of the socket file from c.Mode.  An empty
+// c.Mode means [defaultUnixSocketMode].
+func (c *unixSocketConfig) mode() (m fs.FileMode, err error) {
+	s := cmp.Or(c.Mode, defaultUnixSocketMode)
+	perm, err := strconv.ParseUint(s, 8, 32)
+	if err != nil {
+		return 0, fmt.Errorf("mode: %w", err)
+	} else if perm > uint64(fs.ModePerm) {
+		return 0, fmt.Errorf("mode: %w: %s", errors.ErrOutOfRange, s)
+	}
+
+	return fs.FileMode(perm), nil
+}
+
+// socketPath returns the path of the socket file with the relative c.Path
+// resolved against workDir.
+func (c *unixSocketConfig) socketPath(workDir string) (p string) {
+	if filepath.IsAbs(c.Path) {
+		return c.Path
+	}
+
+	return filepath.Join(workDir, c.Path)
+}
+
+// tcpDisabled returns true if the TCP servers of the web UI shouldn't be
+// started.
+func (web *webAPI) tcpDisabled() (ok bool) {
+	c := web.conf.unixSocket
+
+	return c != nil && c.Path != "" && c.DisableTCP && !web.conf.firstRun
+}
+
+// startUnixSocket starts serving the web UI and the control API on the Unix
+// socket, if configured.
+func (web *webAPI) startUnixSocket(ctx context.Context) (err error) {
+	c := web.conf.unixSocket
+	if c == nil || c.Path == "" {
+		return nil
+	}
+
+	mode, err := c.mode()
+	if err != nil {
+		// Don't wrap the error, since it's informative enough as is.
+		return err
+	}
+
+	sockPath := c.socketPath(web.conf.workDir)
+	l, err := listenUnixSocket(sockPath, mode)
+	if err != nil {
+		return fmt.Errorf("listening on unix socket: %w", err)
+	}
+
+	logger := web.baseLogger.With(loggerKeyServer, "unix")
+	web.unixServer = &http.Server{
+		Handler:           web.rootHandler(logger),
+		ReadTimeout:       web.conf.ReadTimeout,
+		ReadHeaderTimeout: web.conf.ReadHeaderTimeout,
+		WriteTimeout:      web.conf.WriteTimeout,
+		ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelError),
+		ConnContext: func(ctx context.Context, _ net.Conn) (connCtx context.Context) {
+			return withUnixSocket(ctx)
+		},
+	}
+
+	go func() {
+		defer slogutil.RecoverAndLog(ctx, logger)
+
+		logger.InfoContext(ctx, "starting unix socket server", "path", sockPath)
+
+		srvErr := web.unixServer.Serve(l)
+		if !errors.Is(srvErr, http.ErrServerClosed) {
+			logger.ErrorContext(ctx, "serving on unix socket", slogutil.KeyError, srvErr)
+		}
+	}()
+
+	return nil
+}
+
+// listenUnixSocket listens on the Unix socket at sockPath and sets its
+// permissions to mode.  Until then, only the owner can connect to it.  The
+// stale socket file left by a previous run is removed.
+func listenUnixSocket(sockPath string, mode fs.FileMode) (l net.Listener, err error) {
+	fi, err := os.Lstat(sockPath)
+	if err == nil && fi.Mode()&fs.ModeSocket != 0 {
+		err = os.Remove(sockPath)
+		if err != nil {
+			return nil, fmt.Errorf("removing stale socket: %w", err)
+		}
+	}
+
+	l, err = listenUnix(sockPath)
+	if err != nil {
+		// Don't wrap the error, since it's informative enough as is.
+		return nil, err
+	}
+
+	// The permissions of the socket files aren't supported on Windows.
+	if runtime.GOOS == "windows" {
+		return l, nil
+	}
+
+	err = os.Chmod(sockPath, mode)
+	if err != nil {
+		return nil, errors.WithDeferred(fmt.Errorf("setting permissions: %w", err), l.Close())
+	}
+
+	return l, nil
+}
@@ -0,0 +1,130 @@
+package home
+
+import (
+	"context"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnixSocketConfig_validate(t *testing.T) {
+	testCases := []struct {
+		conf       *unixSocketConfig
+		name       string
+		wantErrMsg string
+	}{{
+		conf:       nil,
+		name:       "nil",
+		wantErrMsg: "",
+	}, {
+		conf:       &unixSocketConfig{Mode: defaultUnixSocketMode},
+		name:       "disabled",
+		wantErrMsg: "",
+	}, {
+		conf: &unixSocketConfig{
+			Path:       "AdGuardHome.sock",
+			Mode:       "0600",
+			DisableTCP: true,
+		},
+		name:       "valid",
+		wantErrMsg: "",
+	}, {
+		conf:       &unixSocketConfig{DisableTCP: true},
+		name:       "disable_tcp_no_path",
+		wantErrMsg: "disable_tcp: empty value: path",
+	}, {
+		conf: &unixSocketConfig{
+			Path: "AdGuardHome.sock",
+			Mode: "0669",
+		},
+		name:       "bad_mode",
+		wantErrMsg: `mode: strconv.ParseUint: parsing "0669": invalid syntax`,
+	}, {
+		conf: &unixSocketConfig{
+			Path: "AdGuardHome.sock",
+			Mode: "01777",
+		},
+		name:       "mode_out_of_range",
+		wantErrMsg: "mode: out of range: 01777",
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			testutil.AssertErrorMsg(t, tc.wantErrMsg, tc.conf.validate())
+		})
+	}
+}
+
+func TestListenUnix(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the permissions of the socket files aren't supported on windows")
+	}
+
+	sockPath := filepath.Join(t.TempDir(), "agh.sock")
+	l, err := listenUnix(sockPath)
+	require.NoError(t, err)
+	testutil.CleanupAndRequireSuccess(t, l.Close)
+
+	fi, err := os.Stat(sockPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, fs.FileMode(0o600), fi.Mode().Perm())
+}
+
+func TestListenUnixSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the permissions of the socket files aren't supported on windows")
+	}
+
+	sockPath := filepath.Join(t.TempDir(), "agh.sock")
+
+	// Leave a stale socket, like after a crash.
+	stale, err := net.Listen("unix", sockPath)
+	require.NoError(t, err)
+
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, stale.Close())
+
+	l, err := listenUnixSocket(sockPath, 0o600)
+	require.NoError(t, err)
+
+	fi, err := os.Stat(sockPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, fs.FileMode(0o600), fi.Mode().Perm())
+
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isUnixSocket(r.Context()) {
+				w.WriteHeader(http.StatusTeapot)
+			}
+		}),
+		ConnContext: func(ctx context.Context, _ net.Conn) (connCtx context.Context) {
+			return withUnixSocket(ctx)
+		},
+	}
+	go func() { _ = srv.Serve(l) }()
+	testutil.CleanupAndRequireSuccess(t, srv.Close)
+
+	cli := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (conn net.Conn, err error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", sockPath)
+			},
+		},
+	}
+
+	resp, err := cli.Get("http://localhost/control/status")
+	require.NoError(t, err)
+	testutil.CleanupAndRequireSuccess(t, resp.Body.Close)
+
+	assert.Equal(t, http.StatusTeapot, resp.StatusCode)
+}
@@ -0,0 +1,19 @@
+//go:build unix
+
+package home
+
+import (
+	"net"
+	"syscall"
+)
+
+// listenUnix listens on the Unix socket at sockPath, which is created with the
+// 0600 permissions, so that no other user can connect to it before its
+// permissions are set.  The umask is process-wide, so the files created
+// concurrently get the restrictive permissions as well.
+func listenUnix(sockPath string) (l net.Listener, err error) {
+	prev := syscall.Umask(0o177)
+	defer syscall.Umask(prev)
+
+	return net.Listen("unix", sockPath)
+}
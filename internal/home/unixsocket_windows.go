@@ -0,0 +1,11 @@
+//go:build windows
+
+package home
+
+import "net"
+
+// listenUnix listens on the Unix socket at sockPath.  The permissions of the
+// socket files aren't supported on Windows.
+func listenUnix(sockPath string) (l net.Listener, err error) {
+	return net.Listen("unix", sockPath)
+}
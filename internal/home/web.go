@@ -6,14 +6,16 @@ import (
 	"fmt"
 	"io/fs"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/netip"
-	"runtime"
+	"path/filepath"
 	"sync"
 	"time"
 
 	"github.com/AdguardTeam/AdGuardHome/internal/agh"
 	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/aghos"
 	"github.com/AdguardTeam/AdGuardHome/internal/updater"
 	"github.com/AdguardTeam/golibs/errors"
 	"github.com/AdguardTeam/golibs/logutil/slogutil"
@@ -22,7 +24,6 @@ import (
 	"github.com/AdguardTeam/golibs/netutil/urlutil"
 	"github.com/AdguardTeam/golibs/osutil"
 	"github.com/AdguardTeam/golibs/osutil/executil"
-	"github.com/NYTimes/gziphandler"
 	"github.com/quic-go/quic-go/http3"
 )
 
@@ -75,6 +76,40 @@ type webAPIConfig struct {
 	// clientFS is used to initialize file server.  It must not be nil.
 	clientFS fs.FS
 
+	// cors is the CORS policy of the control API.  It must be valid, it may be
+	// nil.
+	cors *corsConfig
+
+	// compression is the configuration of the compression of the responses.
+	// It must be valid, it may be nil.
+	compression *compressionConfig
+
+	// unixSocket is the configuration of the Unix socket.  It must be valid,
+	// it may be nil.
+	unixSocket *unixSocketConfig
+
+	// clientAuth is the configuration of the authentication with the TLS
+	// client certificates.  It must be valid, it may be nil.
+	clientAuth *clientAuthConfig
+
+	// doHRoutes are the patterns of the DoH routes.
+	doHRoutes []string
+
+	// auditLog is the configuration of the audit log.  It must be valid, it
+	// may be nil.
+	auditLog *auditLogConfig
+
+	// confHistory is the history of the configuration file.  It's nil if the
+	// history is disabled.
+	confHistory *configHistory
+
+	// syncReplica pulls the configuration from the primary instance.  It's nil
+	// unless the instance is a replica.
+	syncReplica *syncReplica
+
+	// confReloader reloads the configuration file.  It's nil on the first run.
+	confReloader *configReloader
+
 	// BindAddr is the binding address with port for plain HTTP web interface.
 	BindAddr netip.AddrPort
 
@@ -145,6 +180,10 @@ type webAPI struct {
 	// TODO(a.garipov): Refactor all these servers.
 	httpServer *http.Server
 
+	// unixServer is the server on the Unix socket.  It's nil if the socket
+	// isn't configured.
+	unixServer *http.Server
+
 	// logger is a slog logger used in webAPI. It must not be nil.
 	logger *slog.Logger
 
@@ -159,6 +198,20 @@ type webAPI struct {
 	// auth stores web user information and handles authentication.
 	auth *auth
 
+	// cors applies the CORS policy to the requests to the control API.
+	cors *corsMiddleware
+
+	// clientCert requires the TLS client certificates, if enabled.
+	clientCert *clientCertMiddleware
+
+	// compress compresses the responses of the control API and of the
+	// frontend.
+	compress middleware
+
+	// auditLog records the state-changing requests to the control API.  It's
+	// nil if the audit log is disabled.
+	auditLog *auditLog
+
 	// httpsServer is the server that handles HTTPS traffic.  If it is not nil,
 	// [Web.http3Server] must also not be nil.
 	httpsServer httpsServer
@@ -174,6 +227,27 @@ type webAPI struct {
 func newWebAPI(ctx context.Context, conf *webAPIConfig) (w *webAPI) {
 	conf.logger.InfoContext(ctx, "initializing")
 
+	compress, err := newCompressionMiddleware(conf.compression)
+	if err != nil {
+		conf.logger.ErrorContext(ctx, "initializing compression", slogutil.KeyError, err)
+
+		return nil
+	}
+
+	clientCert, err := newClientCertMiddleware(conf.logger, conf.mux, conf.clientAuth, conf.doHRoutes)
+	if err != nil {
+		conf.logger.ErrorContext(ctx, "initializing client auth", slogutil.KeyError, err)
+
+		return nil
+	}
+
+	auditLog, err := newAuditLog(filepath.Join(conf.workDir, dataDir, auditLogFilename), conf.auditLog)
+	if err != nil {
+		conf.logger.ErrorContext(ctx, "initializing audit log", slogutil.KeyError, err)
+
+		return nil
+	}
+
 	w = &webAPI{
 		conf:         conf,
 		confModifier: conf.confModifier,
@@ -183,6 +257,10 @@ func newWebAPI(ctx context.Context, conf *webAPIConfig) (w *webAPI) {
 		baseLogger:   conf.baseLogger,
 		tlsManager:   conf.tlsManager,
 		auth:         conf.auth,
+		cors:         newCORSMiddleware(conf.cors),
+		compress:     compress,
+		clientCert:   clientCert,
+		auditLog:     auditLog,
 		startTime:    time.Now(),
 	}
 
@@ -190,7 +268,7 @@ func newWebAPI(ctx context.Context, conf *webAPIConfig) (w *webAPI) {
 
 	mux := conf.mux
 	// if not configured, redirect / to /install.html, otherwise redirect /install.html to /
-	mux.Handle("/", withMiddlewares(clientFS, gziphandler.GzipHandler, w.postInstallHandler))
+	mux.Handle("/", withMiddlewares(clientFS, compress, w.postInstallHandler))
 
 	// add handlers for /install paths, we only need them when we're not configured yet
 	if conf.firstRun {
@@ -253,25 +331,35 @@ const loggerKeyServer = "server"
 func (web *webAPI) start(ctx context.Context) {
 	defer slogutil.RecoverAndExit(ctx, web.logger, osutil.ExitCodeFailure)
 
+	err := web.startUnixSocket(ctx)
+	if err != nil {
+		cleanupAlways()
+		panic(err)
+	}
+
+	if web.tcpDisabled() {
+		web.logger.InfoContext(ctx, "tcp servers are disabled, only serving on unix socket")
+		notifyServiceManager(ctx, web.logger, aghos.SDNotifyReady)
+
+		return
+	}
+
 	web.logger.InfoContext(ctx, "AdGuard Home is available at the following addresses:")
 
 	// for https, we have a separate goroutine loop
 	go web.tlsServerLoop(ctx)
 
+	// notified is true if the service manager has been notified of the
+	// readiness, which is only done once the plain server is first bound.
+	notified := false
+
 	// this loop is used as an ability to change listening host and/or port
 	for !web.httpsServer.inShutdown {
 		printHTTPAddresses(ctx, web.logger, urlutil.SchemeHTTP, web.tlsManager)
 		errs := make(chan error, 2)
 
-		hdlr := withMiddlewares(web.conf.mux, limitRequestBody)
-
 		logger := web.baseLogger.With(loggerKeyServer, "plain")
-
-		// TODO(a.garipov):  Remove other logs like this in other code.
-		logMw := httputil.NewLogMiddleware(logger, slog.LevelDebug)
-		hdlr = logMw.Wrap(hdlr)
-
-		hdlr = web.auth.middleware().Wrap(hdlr)
+		hdlr := web.rootHandler(logger)
 
 		// Enable unencrypted HTTP/2, e.g. for proxies, using prior-knowledge
 		// negotiation rather than the HTTP/1.1 upgrade mechanism, which is
@@ -290,15 +378,29 @@ func (web *webAPI) start(ctx context.Context) {
 			ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelError),
 			Protocols:         protocols,
 		}
+
+		// Bind synchronously, so that the readiness is reported only when the
+		// web interface can actually be reached.
+		ln, err := net.Listen("tcp", web.httpServer.Addr)
+		if err != nil {
+			cleanupAlways()
+			panic(err)
+		}
+
+		if !notified {
+			notifyServiceManager(ctx, web.logger, aghos.SDNotifyReady)
+			notified = true
+		}
+
 		go func() {
 			defer slogutil.RecoverAndLog(ctx, logger)
 
 			logger.InfoContext(ctx, "starting plain server", "addr", web.httpServer.Addr)
 
-			errs <- web.httpServer.ListenAndServe()
+			errs <- web.httpServer.Serve(ln)
 		}()
 
-		err := <-errs
+		err = <-errs
 		if !errors.Is(err, http.ErrServerClosed) {
 			cleanupAlways()
 			panic(err)
@@ -309,6 +411,19 @@ func (web *webAPI) start(ctx context.Context) {
 	}
 }
 
+// rootHandler returns the handler of the plain HTTP servers, which applies the
+// CORS policy, the authentication, the logging, and the limits of the request
+// bodies.  logger must not be nil.
+func (web *webAPI) rootHandler(logger *slog.Logger) (h http.Handler) {
+	h = withMiddlewares(web.conf.mux, limitRequestBody)
+
+	// TODO(a.garipov):  Remove other logs like this in other code.
+	logMw := httputil.NewLogMiddleware(logger, slog.LevelDebug)
+	h = logMw.Wrap(h)
+
+	return web.cors.Wrap(web.clientCert.Wrap(web.auth.middleware().Wrap(h)))
+}
+
 // close gracefully shuts down the HTTP servers.
 func (web *webAPI) close(ctx context.Context) {
 	web.logger.InfoContext(ctx, "stopping http server")
@@ -324,6 +439,7 @@ func (web *webAPI) close(ctx context.Context) {
 	shutdownSrv(ctx, web.logger, web.httpsServer.server)
 	shutdownSrv3(ctx, web.logger, web.httpsServer.server3)
 	shutdownSrv(ctx, web.logger, web.httpServer)
+	shutdownSrv(ctx, web.logger, web.unixServer)
 
 	if web.auth != nil {
 		web.auth.close(ctx)
@@ -367,14 +483,9 @@ func (web *webAPI) serveTLS(ctx context.Context) (next bool) {
 	hdlr := logMw.Wrap(withMiddlewares(web.conf.mux, limitRequestBody))
 
 	web.httpsServer.server = &http.Server{
-		Addr:    addr,
-		Handler: web.auth.middleware().Wrap(hdlr),
-		TLSConfig: &tls.Config{
-			Certificates: []tls.Certificate{web.httpsServer.cert},
-			RootCAs:      web.tlsManager.rootCerts,
-			CipherSuites: web.tlsManager.customCipherIDs,
-			MinVersion:   tls.VersionTLS12,
-		},
+		Addr:              addr,
+		Handler:           web.cors.Wrap(web.clientCert.Wrap(web.auth.middleware().Wrap(hdlr))),
+		TLSConfig:         web.newServerTLSConfig(),
 		ReadTimeout:       web.conf.ReadTimeout,
 		ReadHeaderTimeout: web.conf.ReadHeaderTimeout,
 		WriteTimeout:      web.conf.WriteTimeout,
@@ -418,6 +529,26 @@ func (web *webAPI) waitForTLSReady() (ok bool) {
 	return true
 }
 
+// newServerTLSConfig returns the TLS configuration for the HTTPS servers with
+// the current certificate.  web.httpsServer.cond.L is expected to be locked.
+func (web *webAPI) newServerTLSConfig() (conf *tls.Config) {
+	cert := web.httpsServer.cert
+	stapler := web.tlsManager.ocsp
+
+	conf = &tls.Config{
+		GetCertificate: func(_ *tls.ClientHelloInfo) (c *tls.Certificate, err error) {
+			return stapler.Staple(&cert), nil
+		},
+		RootCAs:      web.tlsManager.rootCerts,
+		CipherSuites: web.tlsManager.customCipherIDs,
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	web.clientCert.setTLSConfig(conf)
+
+	return conf
+}
+
 // mustStartHTTP3 initializes and starts HTTP3 server.
 func (web *webAPI) mustStartHTTP3(ctx context.Context, address string) {
 	defer slogutil.RecoverAndExit(ctx, web.logger, osutil.ExitCodeFailure)
@@ -425,14 +556,11 @@ func (web *webAPI) mustStartHTTP3(ctx context.Context, address string) {
 	web.httpsServer.server3 = &http3.Server{
 		// TODO(a.garipov): See if there is a way to use the error log as
 		// well as timeouts here.
-		Addr: address,
-		TLSConfig: &tls.Config{
-			Certificates: []tls.Certificate{web.httpsServer.cert},
-			RootCAs:      web.tlsManager.rootCerts,
-			CipherSuites: web.tlsManager.customCipherIDs,
-			MinVersion:   tls.VersionTLS12,
-		},
-		Handler: web.auth.middleware().Wrap(withMiddlewares(web.conf.mux, limitRequestBody)),
+		Addr:      address,
+		TLSConfig: web.newServerTLSConfig(),
+		Handler: web.cors.Wrap(web.clientCert.Wrap(
+			web.auth.middleware().Wrap(withMiddlewares(web.conf.mux, limitRequestBody)),
+		)),
 	}
 
 	web.logger.DebugContext(ctx, "starting http/3 server")
@@ -447,8 +575,7 @@ func (web *webAPI) mustStartHTTP3(ctx context.Context, address string) {
 func startPprof(baseLogger *slog.Logger, port uint16) {
 	addr := netip.AddrPortFrom(netutil.IPv4Localhost(), port)
 
-	runtime.SetBlockProfileRate(1)
-	runtime.SetMutexProfileFraction(1)
+	setProfileRates(true)
 
 	mux := http.NewServeMux()
 	httputil.RoutePprof(mux)
@@ -7,3 +7,9 @@ package home
 func ensureWritableWorkDir(workDir string) (string, error) {
 	return workDir, nil
 }
+
+// isFallbackWorkDirUsed returns false, since there is no fallback working
+// directory on these platforms.
+func isFallbackWorkDirUsed() (ok bool) {
+	return false
+}
@@ -18,6 +18,12 @@ import (
 // selected instead of the default working directory.
 var fallbackWorkDirUsed atomic.Bool
 
+// isFallbackWorkDirUsed returns true if the fallback writable directory is used
+// as the working directory.
+func isFallbackWorkDirUsed() (ok bool) {
+	return fallbackWorkDirUsed.Load()
+}
+
 // ensureWritableWorkDir verifies that workDir can be used for mutable data and
 // falls back to a per-user writable directory when it is not.
 func ensureWritableWorkDir(workDir string) (string, error) {
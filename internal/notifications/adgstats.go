@@ -1,6 +1,9 @@
 package notifications
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // StatsProvider exposes DNS query statistics for the bot menu.
 type StatsProvider interface {
@@ -184,3 +187,18 @@ func (m *Manager) SetLogsProvider(lp LogsProvider) {
 
 	m.logs = lp
 }
+
+// ClientWaker sends Wake-on-LAN magic packets to the known clients on behalf of
+// the bot.
+type ClientWaker interface {
+	// WakeClient wakes the persistent client with the given name.
+	WakeClient(ctx context.Context, name string) error
+}
+
+// SetClientWaker injects the Wake-on-LAN provider.
+func (m *Manager) SetClientWaker(cw ClientWaker) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.waker = cw
+}
@@ -0,0 +1,177 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// alertPauseDuration is the time, which the protection is paused for by the
+// pause button of the alert messages.
+const alertPauseDuration = 30 * time.Minute
+
+// alertActionPrefix is the prefix of the callback data of the alert buttons.
+const alertActionPrefix = "alert:"
+
+// Actions of the alert buttons.
+const (
+	alertActionPause = "pause"
+	alertActionAck   = "ack"
+	alertActionStats = "stats"
+)
+
+// tgMaxCallbackDataLen is the maximum length of the callback data of the inline
+// keyboard buttons in bytes.
+const tgMaxCallbackDataLen = 64
+
+// ProtectionPauser is implemented by the [ProtectionProvider] which can disable
+// the protection for a limited time.
+type ProtectionPauser interface {
+	// PauseProtection disables the protection for d.
+	PauseProtection(d time.Duration) (err error)
+}
+
+// alertKeyboard returns the inline keyboard attached to the Telegram message of
+// ev.  kb is nil if ev isn't an alert.
+func alertKeyboard(ev *event) (kb *tgInlineKeyboardMarkup) {
+	if ev.typ != eventTypeAlert {
+		return nil
+	}
+
+	// The metrics of the additional mount points may be too long for the
+	// callback data, so the button only removes the buttons of such alerts.
+	ack := alertActionPrefix + alertActionAck + ":" + ev.metric
+	if len(ack) > tgMaxCallbackDataLen {
+		ack = alertActionPrefix + alertActionAck
+	}
+
+	return &tgInlineKeyboardMarkup{
+		InlineKeyboard: [][]tgInlineKeyboardButton{
+			{
+				{Text: "⏸️ Pause protection 30m", CallbackData: alertActionPrefix + alertActionPause},
+				{Text: "📊 Show stats", CallbackData: alertActionPrefix + alertActionStats},
+			},
+			{
+				{Text: "✅ Acknowledge", CallbackData: ack},
+			},
+		},
+	}
+}
+
+// encodeReplyMarkup returns kb encoded for the reply_markup parameter of the
+// form-encoded Bot API requests.
+func encodeReplyMarkup(kb *tgInlineKeyboardMarkup) (s string, err error) {
+	b, err := json.Marshal(kb)
+	if err != nil {
+		return "", fmt.Errorf("marshal reply markup: %w", err)
+	}
+
+	return string(b), nil
+}
+
+// parseAlertAction splits the callback data of an alert button into the
+// action and the metric, if any.
+func parseAlertAction(data string) (action, metric string, ok bool) {
+	rest, ok := strings.CutPrefix(data, alertActionPrefix)
+	if !ok {
+		return "", "", false
+	}
+
+	action, metric, _ = strings.Cut(rest, ":")
+
+	return action, metric, action != ""
+}
+
+// handleAlertAction executes the action of the alert button pressed in the
+// message with messageID.
+func (m *Manager) handleAlertAction(
+	ctx context.Context,
+	cfg TelegramConfig,
+	chatID int64,
+	messageID int64,
+	data string,
+) {
+	action, metric, _ := parseAlertAction(data)
+	switch action {
+	case alertActionPause:
+		m.pauseProtection(ctx, cfg, chatID)
+	case alertActionStats:
+		m.sendDNSStats(ctx, cfg, chatID, 0)
+	case alertActionAck:
+		m.acknowledgeAlert(ctx, cfg, chatID, messageID, metric)
+	default:
+		m.logger.Debug("unknown alert action", "data", data)
+	}
+}
+
+// pauseProtection disables the protection for [alertPauseDuration] and reports
+// the result to the chat.
+func (m *Manager) pauseProtection(ctx context.Context, cfg TelegramConfig, chatID int64) {
+	m.mu.RLock()
+	pp := m.protection
+	m.mu.RUnlock()
+
+	var text string
+	pauser, ok := pp.(ProtectionPauser)
+	if !ok {
+		text = "⚠️ Pausing the protection is not available"
+	} else if err := pauser.PauseProtection(alertPauseDuration); err != nil {
+		text = fmt.Sprintf("❌ Pausing the protection failed: <code>%s</code>", html.EscapeString(err.Error()))
+	} else {
+		resumeAt := time.Now().Add(alertPauseDuration)
+		text = fmt.Sprintf(
+			"⏸️ <b>Protection paused</b>\n%s\n\nProtection is resumed at <code>%s</code>.",
+			divider(),
+			resumeAt.Format("15:04:05"),
+		)
+	}
+
+	kb := backToMenuKeyboard()
+	if pp != nil {
+		kb = protectionKeyboard(pp.IsProtectionEnabled())
+	}
+
+	err := m.sendMessageWithKeyboard(ctx, cfg, chatID, text, kb)
+	if err != nil {
+		m.logger.Debug("sending pause result failed", slog.String("error", err.Error()))
+	}
+}
+
+// acknowledgeAlert acknowledges the alert for metric, removes the buttons from
+// the alert message with messageID, and confirms the acknowledgement in the
+// chat.
+func (m *Manager) acknowledgeAlert(
+	ctx context.Context,
+	cfg TelegramConfig,
+	chatID int64,
+	messageID int64,
+	metric string,
+) {
+	if metric != "" {
+		err := m.AcknowledgeAlert(metric)
+		if err != nil {
+			m.logger.Debug("acknowledging alert", "metric", metric, slog.String("error", err.Error()))
+		}
+	}
+
+	if messageID > 0 {
+		err := m.editMessageReplyMarkup(ctx, cfg, chatID, messageID, nil)
+		if err != nil {
+			m.logger.Debug("removing alert buttons failed", slog.String("error", err.Error()))
+		}
+	}
+
+	text := "✅ Alert acknowledged"
+	if metric != "" {
+		text += ": " + html.EscapeString(metricDisplayName(metric))
+	}
+
+	err := m.sendMessageWithKeyboard(ctx, cfg, chatID, text, nil)
+	if err != nil {
+		m.logger.Debug("sending acknowledgement failed", slog.String("error", err.Error()))
+	}
+}
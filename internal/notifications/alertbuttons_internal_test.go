@@ -0,0 +1,133 @@
+package notifications
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// roundTripFunc is an [http.RoundTripper] implemented by a function.
+type roundTripFunc func(req *http.Request) (resp *http.Response, err error)
+
+// RoundTrip implements the [http.RoundTripper] interface for roundTripFunc.
+func (f roundTripFunc) RoundTrip(req *http.Request) (resp *http.Response, err error) {
+	return f(req)
+}
+
+// fakePauser is a [ProtectionProvider] and [ProtectionPauser] for tests.
+type fakePauser struct {
+	paused  time.Duration
+	enabled bool
+}
+
+// IsProtectionEnabled implements the [ProtectionProvider] interface for
+// *fakePauser.
+func (p *fakePauser) IsProtectionEnabled() (ok bool) { return p.enabled }
+
+// SetProtectionEnabled implements the [ProtectionProvider] interface for
+// *fakePauser.
+func (p *fakePauser) SetProtectionEnabled(enabled bool) (err error) {
+	p.enabled = enabled
+
+	return nil
+}
+
+// PauseProtection implements the [ProtectionPauser] interface for *fakePauser.
+func (p *fakePauser) PauseProtection(d time.Duration) (err error) {
+	p.enabled, p.paused = false, d
+
+	return nil
+}
+
+func TestAlertKeyboard(t *testing.T) {
+	if kb := alertKeyboard(&event{typ: eventTypeRecovery, metric: "cpu"}); kb != nil {
+		t.Errorf("unexpected keyboard for recovery: %+v", kb)
+	}
+
+	kb := alertKeyboard(&event{typ: eventTypeAlert, metric: "cpu"})
+	if kb == nil {
+		t.Fatal("no keyboard for alert")
+	}
+
+	var data []string
+	for _, row := range kb.InlineKeyboard {
+		for _, btn := range row {
+			data = append(data, btn.CallbackData)
+		}
+	}
+
+	want := []string{"alert:pause", "alert:stats", "alert:ack:cpu"}
+	if strings.Join(data, " ") != strings.Join(want, " ") {
+		t.Errorf("got callback data %q, want %q", data, want)
+	}
+}
+
+func TestParseAlertAction(t *testing.T) {
+	testCases := []struct {
+		data       string
+		wantAction string
+		wantMetric string
+		wantOK     bool
+	}{{
+		data:       "alert:ack:cpu",
+		wantAction: "ack",
+		wantMetric: "cpu",
+		wantOK:     true,
+	}, {
+		data:       "alert:pause",
+		wantAction: "pause",
+		wantOK:     true,
+	}, {
+		data: "alert:",
+	}, {
+		data: "cmd:stats",
+	}}
+
+	for _, tc := range testCases {
+		action, metric, ok := parseAlertAction(tc.data)
+		if action != tc.wantAction || metric != tc.wantMetric || ok != tc.wantOK {
+			t.Errorf(
+				"parseAlertAction(%q) = %q, %q, %t; want %q, %q, %t",
+				tc.data,
+				action,
+				metric,
+				ok,
+				tc.wantAction,
+				tc.wantMetric,
+				tc.wantOK,
+			)
+		}
+	}
+}
+
+func TestManager_handleAlertAction_pause(t *testing.T) {
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+
+	var methods []string
+	m.client = &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (resp *http.Response, err error) {
+			methods = append(methods, req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:])
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"ok":true}`)),
+			}, nil
+		}),
+	}
+
+	pp := &fakePauser{enabled: true}
+	m.protection = pp
+
+	m.handleAlertAction(context.Background(), TelegramConfig{BotToken: "token"}, 1, 2, "alert:pause")
+	if pp.enabled || pp.paused != alertPauseDuration {
+		t.Errorf("protection not paused: %+v", pp)
+	}
+
+	if len(methods) != 1 || methods[0] != "sendMessage" {
+		t.Errorf("unexpected requests %q", methods)
+	}
+}
@@ -0,0 +1,193 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+)
+
+// AlertmanagerConfig contains runtime configuration for sending alerts to a
+// Prometheus Alertmanager.  Labels are added to the labels of every alert.
+type AlertmanagerConfig struct {
+	Labels   map[string]string
+	Enabled  bool
+	URL      string
+	Username string
+	Password string
+	Proxy    string
+}
+
+// alertmanagerChannel posts the metric alerts to the Alertmanager API v2.
+// Since Alertmanager resolves the alerts, which haven't been updated within
+// its resolve timeout, the firing alerts are re-posted on every check.  Other
+// events are ignored.
+type alertmanagerChannel struct {
+	client *http.Client
+	conf   AlertmanagerConfig
+
+	// mu protects firing.
+	mu *sync.Mutex
+
+	// firing are the currently firing alerts by metric.
+	firing map[string]*alertmanagerAlert
+
+	// instance is the name of the host reported in the instance label.
+	instance string
+}
+
+// newAlertmanagerChannel returns a new Alertmanager channel.
+func newAlertmanagerChannel(client *http.Client, conf AlertmanagerConfig) (c *alertmanagerChannel) {
+	instance, _ := os.Hostname()
+	if instance == "" {
+		instance = "adguardhome"
+	}
+
+	return &alertmanagerChannel{
+		client:   client,
+		conf:     conf,
+		mu:       &sync.Mutex{},
+		firing:   map[string]*alertmanagerAlert{},
+		instance: instance,
+	}
+}
+
+// type check
+var (
+	_ incidentChannel = (*alertmanagerChannel)(nil)
+	_ snapshotChannel = (*alertmanagerChannel)(nil)
+)
+
+// name implements the [channel] interface for *alertmanagerChannel.
+func (c *alertmanagerChannel) name() (n string) { return "alertmanager" }
+
+// alertmanagerAlert is an alert of the Alertmanager API v2.
+type alertmanagerAlert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      *time.Time        `json:"endsAt,omitempty"`
+}
+
+// send implements the [channel] interface for *alertmanagerChannel.
+func (c *alertmanagerChannel) send(ctx context.Context, ev *event) (err error) {
+	switch ev.typ {
+	case eventTypeAlert, eventTypeTest:
+		// Go on.
+	case eventTypeRecovery:
+		return c.resolve(ctx, ev.metric)
+	default:
+		return nil
+	}
+
+	metric := ev.metric
+	if ev.typ == eventTypeTest {
+		metric = "test"
+	}
+
+	labels := maps.Clone(c.conf.Labels)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	labels["alertname"] = "AdGuardHome" + strings.ReplaceAll(metricDisplayName(metric), " ", "")
+	labels["instance"] = c.instance
+	labels["metric"] = metric
+
+	alert := &alertmanagerAlert{
+		Labels: labels,
+		Annotations: map[string]string{
+			"summary":     ev.title(),
+			"description": htmlToText(ev.text),
+		},
+		StartsAt: ev.time,
+	}
+
+	if ev.typ == eventTypeTest {
+		// Resolve the test alert right away so that it doesn't linger.
+		endsAt := ev.time.Add(time.Minute)
+		alert.EndsAt = &endsAt
+
+		return c.post(ctx, []*alertmanagerAlert{alert})
+	}
+
+	c.mu.Lock()
+	c.firing[metric] = alert
+	c.mu.Unlock()
+
+	return c.post(ctx, []*alertmanagerAlert{alert})
+}
+
+// resolve implements the [incidentChannel] interface for *alertmanagerChannel.
+func (c *alertmanagerChannel) resolve(ctx context.Context, metric string) (err error) {
+	c.mu.Lock()
+	alert, ok := c.firing[metric]
+	delete(c.firing, metric)
+	c.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	endsAt := time.Now()
+	alert.EndsAt = &endsAt
+
+	return c.post(ctx, []*alertmanagerAlert{alert})
+}
+
+// sendSnapshot implements the [snapshotChannel] interface for
+// *alertmanagerChannel.  It re-posts the firing alerts.
+func (c *alertmanagerChannel) sendSnapshot(ctx context.Context, _ *systeminfo.Info) (err error) {
+	c.mu.Lock()
+	alerts := slices.Collect(maps.Values(c.firing))
+	c.mu.Unlock()
+
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	return c.post(ctx, alerts)
+}
+
+// post sends alerts to the Alertmanager.
+func (c *alertmanagerChannel) post(ctx context.Context, alerts []*alertmanagerAlert) (err error) {
+	body, err := json.Marshal(alerts)
+	if err != nil {
+		return fmt.Errorf("marshal alertmanager alerts: %w", err)
+	}
+
+	endpoint := strings.TrimSuffix(c.conf.URL, "/") + "/api/v2/alerts"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if c.conf.Username != "" {
+		req.SetBasicAuth(c.conf.Username, c.conf.Password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	traceResponse(ctx, resp.StatusCode, respBody)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("alertmanager api status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAlertmanagerChannel(t *testing.T) {
+	var got [][]alertmanagerAlert
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/alerts" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+
+		var alerts []alertmanagerAlert
+		if err := json.NewDecoder(r.Body).Decode(&alerts); err != nil {
+			t.Errorf("decoding request: %s", err)
+		}
+
+		got = append(got, alerts)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := newAlertmanagerChannel(srv.Client(), AlertmanagerConfig{
+		Enabled: true,
+		URL:     srv.URL,
+		Labels:  map[string]string{"site": "home"},
+	})
+
+	ctx := context.Background()
+	startsAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if err := c.send(ctx, &event{time: startsAt, typ: eventTypeAlert, metric: "cpu"}); err != nil {
+		t.Fatalf("sending alert: %s", err)
+	}
+
+	if err := c.sendSnapshot(ctx, nil); err != nil {
+		t.Fatalf("re-posting alerts: %s", err)
+	}
+
+	if err := c.resolve(ctx, "cpu"); err != nil {
+		t.Fatalf("resolving alert: %s", err)
+	}
+
+	if err := c.sendSnapshot(ctx, nil); err != nil {
+		t.Fatalf("re-posting alerts: %s", err)
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("got %d requests, want 3", len(got))
+	}
+
+	firing, resolved := got[1][0], got[2][0]
+	if firing.EndsAt != nil || !firing.StartsAt.Equal(startsAt) {
+		t.Errorf("unexpected firing alert %+v", firing)
+	}
+
+	if resolved.EndsAt == nil || !resolved.StartsAt.Equal(startsAt) {
+		t.Errorf("unexpected resolved alert %+v", resolved)
+	}
+
+	labels := resolved.Labels
+	if labels["alertname"] != "AdGuardHomeCPUUsage" || labels["metric"] != "cpu" || labels["site"] != "home" {
+		t.Errorf("unexpected labels %v", labels)
+	}
+}
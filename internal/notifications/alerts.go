@@ -0,0 +1,103 @@
+package notifications
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrAlertNotFound is returned when there is no active alert with the given
+// ID.
+var ErrAlertNotFound = errors.New("alert not found")
+
+// ActiveAlert is the state of an unresolved alert.  ID is the metric of the
+// alert, like "cpu" or "protection".  SnoozedUntil is nil unless the alert is
+// snoozed.
+type ActiveAlert struct {
+	Since        time.Time  `json:"since"`
+	LastNotified time.Time  `json:"last_notified"`
+	SnoozedUntil *time.Time `json:"snoozed_until,omitempty"`
+	ID           string     `json:"id"`
+	Title        string     `json:"title"`
+	Value        float64    `json:"value,omitempty"`
+	Threshold    float64    `json:"threshold,omitempty"`
+	Escalated    bool       `json:"escalated"`
+	Acknowledged bool       `json:"acknowledged"`
+}
+
+// ActiveAlerts returns the unresolved alerts sorted by the time they have
+// fired at.
+func (m *Manager) ActiveAlerts() (alerts []ActiveAlert) {
+	now := time.Now()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	alerts = make([]ActiveAlert, 0, len(m.activeAlerts))
+	for metric, a := range m.activeAlerts {
+		alert := ActiveAlert{
+			Since:        a.ev.time,
+			LastNotified: a.lastNotified,
+			ID:           metric,
+			Title:        a.ev.title(),
+			Value:        a.ev.value,
+			Threshold:    a.ev.threshold,
+			Escalated:    a.escalated,
+			Acknowledged: a.acknowledged,
+		}
+
+		if a.snoozedUntil.After(now) {
+			until := a.snoozedUntil
+			alert.SnoozedUntil = &until
+		}
+
+		alerts = append(alerts, alert)
+	}
+
+	slices.SortFunc(alerts, func(a, b ActiveAlert) (res int) {
+		if c := a.Since.Compare(b.Since); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return alerts
+}
+
+// AcknowledgeAlert stops the re-notification and escalation of the active
+// alert with id until it's resolved.  It returns [ErrAlertNotFound] if there
+// is no such alert.
+func (m *Manager) AcknowledgeAlert(id string) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	a, ok := m.activeAlerts[id]
+	if !ok {
+		return ErrAlertNotFound
+	}
+
+	a.acknowledged = true
+	a.snoozedUntil = time.Time{}
+
+	return nil
+}
+
+// SnoozeAlert stops the re-notification and escalation of the active alert
+// with id for d.  The alert is delivered again once d is over.  It returns
+// [ErrAlertNotFound] if there is no such alert.
+func (m *Manager) SnoozeAlert(id string, d time.Duration) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	a, ok := m.activeAlerts[id]
+	if !ok {
+		return ErrAlertNotFound
+	}
+
+	a.acknowledged = false
+	a.snoozedUntil = time.Now().Add(d)
+
+	return nil
+}
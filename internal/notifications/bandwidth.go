@@ -0,0 +1,367 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+)
+
+// bandwidthMetricPrefix is the prefix of the metrics of the bandwidth alerts,
+// which is followed by the name of the interface, like "bandwidth:eth0".
+const bandwidthMetricPrefix = "bandwidth:"
+
+// BandwidthConfig contains runtime configuration for the network bandwidth
+// alerts.  An alert is fired when the receive or transmit rate of an interface
+// is at least ThresholdMbps during SustainedChecks consecutive checks.
+type BandwidthConfig struct {
+	// Interfaces are the names of the monitored interfaces.  All the
+	// interfaces except the loopback ones are monitored if it's empty.
+	Interfaces []string
+
+	// ThresholdMbps is the rate in megabits per second.
+	ThresholdMbps float64
+
+	// SustainedChecks is the number of the consecutive checks, which the rate
+	// must be above the threshold during.  The alert fires on the first check
+	// if it's zero or one.
+	SustainedChecks int
+
+	Enabled bool
+}
+
+// ValidateBandwidthConfig returns an error if the enabled bandwidth alerts
+// configuration is invalid.
+func ValidateBandwidthConfig(conf BandwidthConfig) (err error) {
+	if !conf.Enabled {
+		return nil
+	}
+
+	if conf.ThresholdMbps <= 0 || math.IsInf(conf.ThresholdMbps, 0) {
+		return fmt.Errorf("threshold_mbps: must be positive, got %v", conf.ThresholdMbps)
+	}
+
+	if conf.SustainedChecks < 0 {
+		return fmt.Errorf("sustained_checks: must not be negative, got %d", conf.SustainedChecks)
+	}
+
+	for i, name := range conf.Interfaces {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("interfaces: at index %d: empty name", i)
+		}
+	}
+
+	return nil
+}
+
+// UpdateBandwidthConfig applies the new bandwidth alerts configuration.  The
+// collected counters are dropped.
+func (m *Manager) UpdateBandwidthConfig(conf BandwidthConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.bandwidth = conf
+	m.bandwidthIfaces = map[string]*ifaceBandwidth{}
+}
+
+// ifaceBandwidth is the state of the bandwidth monitoring of an interface.
+type ifaceBandwidth struct {
+	// at is the time of the last counters.
+	at time.Time
+
+	// sent and recv are the last cumulative counters.
+	sent uint64
+	recv uint64
+
+	// breaches is the number of the consecutive checks, which the rate has
+	// been above the threshold during.
+	breaches int
+}
+
+// bandwidthRate is the throughput of an interface.
+type bandwidthRate struct {
+	iface string
+
+	// rxMbps and txMbps are the receive and transmit rates in megabits per
+	// second.
+	rxMbps float64
+	txMbps float64
+
+	// sustained is true if the rate has been above the threshold for long
+	// enough.
+	sustained bool
+}
+
+// peak returns the highest of the receive and transmit rates.
+func (r bandwidthRate) peak() (mbps float64) {
+	return max(r.rxMbps, r.txMbps)
+}
+
+// checkBandwidth sends the alerts about the interfaces, which throughput has
+// been above the threshold, and the recoveries of the ones it has dropped
+// below the threshold for.
+func (m *Manager) checkBandwidth(ctx context.Context, cfg TelegramConfig, info systeminfo.Info) {
+	conf, rates := m.updateBandwidth(info.Interfaces, time.Now())
+	for _, metric := range m.staleBandwidthMetrics() {
+		m.clearAlert(ctx, metric)
+	}
+
+	if !conf.Enabled {
+		return
+	}
+
+	for _, r := range rates {
+		metric := bandwidthMetricPrefix + r.iface
+		active, last := m.metricState(metric)
+		switch {
+		case r.sustained && !active && time.Since(last) >= cfg.cooldown(metric):
+			m.sendBandwidthAlert(ctx, cfg, conf, r, info)
+		case active && r.peak() < conf.ThresholdMbps*resetFactor:
+			m.sendBandwidthRecovery(ctx, cfg, conf, r, info)
+		}
+	}
+}
+
+// updateBandwidth accounts the interface counters collected at now and returns
+// the current configuration along with the rates of the monitored interfaces.
+// The rates are computed starting from the second check.
+func (m *Manager) updateBandwidth(
+	ifaces []systeminfo.InterfaceIO,
+	now time.Time,
+) (conf BandwidthConfig, rates []bandwidthRate) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	conf = m.bandwidth
+	if !conf.Enabled {
+		return conf, nil
+	} else if m.bandwidthIfaces == nil {
+		m.bandwidthIfaces = map[string]*ifaceBandwidth{}
+	}
+
+	seen := make(map[string]bool, len(ifaces))
+	for _, c := range ifaces {
+		if !conf.monitors(c) {
+			continue
+		}
+
+		seen[c.Name] = true
+		st := m.bandwidthIfaces[c.Name]
+		if st == nil {
+			m.bandwidthIfaces[c.Name] = &ifaceBandwidth{at: now, sent: c.BytesSent, recv: c.BytesRecv}
+
+			continue
+		}
+
+		elapsed := now.Sub(st.at).Seconds()
+		r := bandwidthRate{
+			iface:  c.Name,
+			rxMbps: mbps(rateDelta(c.BytesRecv, st.recv, elapsed)),
+			txMbps: mbps(rateDelta(c.BytesSent, st.sent, elapsed)),
+		}
+
+		if r.peak() >= conf.ThresholdMbps {
+			st.breaches++
+		} else {
+			st.breaches = 0
+		}
+
+		r.sustained = st.breaches > 0 && st.breaches >= conf.SustainedChecks
+		st.at, st.sent, st.recv = now, c.BytesSent, c.BytesRecv
+
+		rates = append(rates, r)
+	}
+
+	// Forget the interfaces, which have disappeared.
+	for name := range m.bandwidthIfaces {
+		if !seen[name] {
+			delete(m.bandwidthIfaces, name)
+		}
+	}
+
+	return conf, rates
+}
+
+// monitors returns true if the interface is monitored according to conf.
+func (conf *BandwidthConfig) monitors(c systeminfo.InterfaceIO) (ok bool) {
+	if len(conf.Interfaces) == 0 {
+		return !c.Loopback
+	}
+
+	return slices.Contains(conf.Interfaces, c.Name)
+}
+
+// mbps converts the rate in bytes per second into megabits per second.
+func mbps(bytesPerSec uint64) (v float64) {
+	return float64(bytesPerSec) * 8 / 1e6
+}
+
+// staleBandwidthMetrics returns the metrics of the active bandwidth alerts
+// about the interfaces, which aren't monitored anymore.
+func (m *Manager) staleBandwidthMetrics() (metrics []string) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for metric, active := range m.alertActive {
+		iface, ok := strings.CutPrefix(metric, bandwidthMetricPrefix)
+		if active && ok && (!m.bandwidth.Enabled || m.bandwidthIfaces[iface] == nil) {
+			metrics = append(metrics, metric)
+		}
+	}
+
+	return metrics
+}
+
+// sendBandwidthAlert sends the alert about the throughput of an interface.
+func (m *Manager) sendBandwidthAlert(
+	ctx context.Context,
+	cfg TelegramConfig,
+	conf BandwidthConfig,
+	r bandwidthRate,
+	info systeminfo.Info,
+) {
+	ev := &event{
+		time:      time.Now(),
+		typ:       eventTypeAlert,
+		metric:    bandwidthMetricPrefix + r.iface,
+		text:      composeBandwidthAlertMessage(cfg, m.getLocale(), conf, r),
+		value:     r.peak(),
+		threshold: conf.ThresholdMbps,
+	}
+	m.applyTemplate(ev, newTemplateData(cfg, ev, info))
+
+	if err := m.deliver(ctx, cfg, ev); err != nil {
+		m.logger.Error("bandwidth alert failed", "interface", r.iface, slog.String("error", err.Error()))
+
+		return
+	}
+
+	m.updateMetricState(ev.metric, true, ev.time)
+
+	m.mu.Lock()
+	m.alertStartTime[ev.metric] = ev.time
+	m.mu.Unlock()
+
+	m.trackAlert(ev)
+}
+
+// sendBandwidthRecovery sends the recovery of the throughput of an interface
+// and clears its alert.
+func (m *Manager) sendBandwidthRecovery(
+	ctx context.Context,
+	cfg TelegramConfig,
+	conf BandwidthConfig,
+	r bandwidthRate,
+	info systeminfo.Info,
+) {
+	metric := bandwidthMetricPrefix + r.iface
+
+	m.mu.RLock()
+	startTime := m.alertStartTime[metric]
+	m.mu.RUnlock()
+
+	escalatedTo := m.untrackAlert(metric)
+	duration := time.Since(startTime).Truncate(time.Second)
+	ev := &event{
+		time:      time.Now(),
+		typ:       eventTypeRecovery,
+		metric:    metric,
+		text:      composeBandwidthRecoveryMessage(cfg, m.getLocale(), conf, r, duration),
+		value:     r.peak(),
+		threshold: conf.ThresholdMbps,
+	}
+
+	data := newTemplateData(cfg, ev, info)
+	data.Duration = duration
+	m.applyTemplate(ev, data)
+	ev.targets = m.withEscalationTargets(ev, escalatedTo)
+	if err := m.deliver(ctx, cfg, ev); err != nil {
+		m.logger.Debug("bandwidth recovery message failed", slog.String("error", err.Error()))
+	}
+
+	m.mu.Lock()
+	delete(m.alertActive, metric)
+	delete(m.alertStartTime, metric)
+	m.mu.Unlock()
+}
+
+// bandwidthLines returns the lines with the rates of the interface.
+func bandwidthLines(loc Locale, conf BandwidthConfig, r bandwidthRate) (lines []string) {
+	return []string{
+		fmt.Sprintf(
+			"  ▸ <b>%s:</b> <code>%s</code>",
+			loc.text("notification_label_interface", "Interface"),
+			html.EscapeString(r.iface),
+		),
+		fmt.Sprintf(
+			"  ▸ <b>%s:</b> <code>%.1f Mbps</code>",
+			loc.text("notification_label_received", "Received"),
+			r.rxMbps,
+		),
+		fmt.Sprintf(
+			"  ▸ <b>%s:</b> <code>%.1f Mbps</code>",
+			loc.text("notification_label_sent", "Sent"),
+			r.txMbps,
+		),
+		fmt.Sprintf(
+			"  ▸ <b>%s:</b> <code>%.1f Mbps</code>",
+			loc.text("notification_label_threshold", "Threshold"),
+			conf.ThresholdMbps,
+		),
+	}
+}
+
+// composeBandwidthAlertMessage formats the alert about the throughput of an
+// interface.
+func composeBandwidthAlertMessage(cfg TelegramConfig, loc Locale, conf BandwidthConfig, r bandwidthRate) (msg string) {
+	lines := make([]string, 0, 12)
+	if prefix := strings.TrimSpace(cfg.CustomMessage); prefix != "" {
+		lines = append(lines, prefix, "")
+	}
+
+	metric := loc.metricName(bandwidthMetricPrefix + r.iface)
+	title := loc.text("notification_alert_title", "ALERT: {{metric}} exceeded threshold", "metric", metric)
+	lines = append(lines, fmt.Sprintf("🚨 <b>%s</b>", title), divider(), "")
+	lines = append(lines, bandwidthLines(loc, conf, r)...)
+	lines = append(lines, "", divider(), loc.timestampLine())
+
+	return strings.Join(lines, "\n")
+}
+
+// composeBandwidthRecoveryMessage formats the recovery of the throughput of an
+// interface, which has been above the threshold for duration.
+func composeBandwidthRecoveryMessage(
+	cfg TelegramConfig,
+	loc Locale,
+	conf BandwidthConfig,
+	r bandwidthRate,
+	duration time.Duration,
+) (msg string) {
+	lines := make([]string, 0, 14)
+	if prefix := strings.TrimSpace(cfg.CustomMessage); prefix != "" {
+		lines = append(lines, prefix, "")
+	}
+
+	metric := loc.metricName(bandwidthMetricPrefix + r.iface)
+	title := loc.text("notification_recovery_title", "RECOVERY: {{metric}} back to normal", "metric", metric)
+	lines = append(lines, fmt.Sprintf("✅ <b>%s</b>", title), divider(), "")
+	lines = append(lines, bandwidthLines(loc, conf, r)...)
+	lines = append(lines,
+		fmt.Sprintf(
+			"  ▸ <b>%s:</b> <code>%s</code>",
+			loc.text("notification_label_alert_duration", "Alert Duration"),
+			duration.String(),
+		),
+		"",
+		divider(),
+		loc.timestampLine(),
+	)
+
+	return strings.Join(lines, "\n")
+}
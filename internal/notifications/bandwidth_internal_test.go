@@ -0,0 +1,103 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+)
+
+func TestManager_updateBandwidth(t *testing.T) {
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	m.UpdateBandwidthConfig(BandwidthConfig{
+		ThresholdMbps:   80,
+		SustainedChecks: 2,
+		Enabled:         true,
+	})
+
+	start := time.Now()
+	ifaces := func(recv uint64) (c []systeminfo.InterfaceIO) {
+		return []systeminfo.InterfaceIO{{
+			Name:      "eth0",
+			BytesRecv: recv,
+		}, {
+			Name:      "lo",
+			BytesRecv: recv,
+			Loopback:  true,
+		}}
+	}
+
+	if _, rates := m.updateBandwidth(ifaces(0), start); len(rates) != 0 {
+		t.Fatalf("got rates %+v on the first check, want none", rates)
+	}
+
+	// 100 Mbps during 10 seconds.
+	_, rates := m.updateBandwidth(ifaces(125_000_000), start.Add(10*time.Second))
+	if len(rates) != 1 || rates[0].iface != "eth0" {
+		t.Fatalf("got rates %+v, want eth0 only", rates)
+	} else if rates[0].rxMbps != 100 || rates[0].sustained {
+		t.Errorf("got rate %+v, want 100 Mbps not sustained", rates[0])
+	}
+
+	_, rates = m.updateBandwidth(ifaces(250_000_000), start.Add(20*time.Second))
+	if len(rates) != 1 || !rates[0].sustained {
+		t.Errorf("got rates %+v, want sustained", rates)
+	}
+
+	_, rates = m.updateBandwidth(ifaces(250_000_000), start.Add(30*time.Second))
+	if len(rates) != 1 || rates[0].peak() != 0 || rates[0].sustained {
+		t.Errorf("got rates %+v, want idle", rates)
+	}
+}
+
+func TestManager_checkBandwidth(t *testing.T) {
+	rec := &recordingChannel{chanName: "matrix"}
+
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	m.channels = []channel{rec}
+	m.UpdateBandwidthConfig(BandwidthConfig{
+		Interfaces:    []string{"eth0"},
+		ThresholdMbps: 10,
+		Enabled:       true,
+	})
+
+	ctx := context.Background()
+	prev := time.Now().Add(-time.Second)
+	m.bandwidthIfaces["eth0"] = &ifaceBandwidth{at: prev}
+
+	info := systeminfo.Info{Interfaces: []systeminfo.InterfaceIO{{Name: "eth0", BytesSent: 10_000_000}}}
+	m.checkBandwidth(ctx, TelegramConfig{}, info)
+
+	metric := bandwidthMetricPrefix + "eth0"
+	if active, _ := m.metricState(metric); !active {
+		t.Fatal("bandwidth alert is not active")
+	}
+
+	// The counters haven't changed, so the rate is zero.
+	m.checkBandwidth(ctx, TelegramConfig{}, info)
+	if active, _ := m.metricState(metric); active {
+		t.Error("bandwidth alert is still active")
+	}
+
+	want := []eventType{eventTypeAlert, eventTypeRecovery}
+	if !slices.Equal(rec.got, want) {
+		t.Errorf("got events %v, want %v", rec.got, want)
+	}
+}
+
+func TestValidateBandwidthConfig(t *testing.T) {
+	if err := ValidateBandwidthConfig(BandwidthConfig{Enabled: true}); err == nil {
+		t.Error("no error for zero threshold")
+	}
+
+	if err := ValidateBandwidthConfig(BandwidthConfig{Enabled: true, ThresholdMbps: 50, Interfaces: []string{" "}}); err == nil {
+		t.Error("no error for empty interface name")
+	}
+
+	if err := ValidateBandwidthConfig(BandwidthConfig{Enabled: true, ThresholdMbps: 50}); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,230 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+)
+
+// eventTypeBlockedSpike is the type of the blocked requests spike events.
+const eventTypeBlockedSpike eventType = "blocked_spike"
+
+const (
+	// spikeBaselineWeight is the weight of the latest check in the moving
+	// average baseline of the blocked requests.
+	spikeBaselineWeight = 0.1
+
+	// spikeWarmupChecks is the number of checks the baseline is collected
+	// during before the spikes are detected.
+	spikeWarmupChecks = 10
+
+	// spikeMinBaseline is the baseline below which an idle client baseline is
+	// forgotten.
+	spikeMinBaseline = 0.01
+
+	// spikeOverallKey is the baseline key of all the blocked requests.
+	spikeOverallKey = ""
+)
+
+// BlockedCountsProvider returns the numbers of blocked requests since the
+// previous call.
+type BlockedCountsProvider interface {
+	// TakeBlockedCounts returns the total number of blocked requests and the
+	// numbers of blocked requests by client since the previous call.
+	TakeBlockedCounts() (total uint64, byClient map[string]uint64)
+}
+
+// SpikeConfig contains runtime configuration for the blocked requests spike
+// detection.  A spike is reported when the number of blocked requests during a
+// check exceeds Multiplier times its moving average and is at least MinBlocked.
+type SpikeConfig struct {
+	Multiplier float64
+	MinBlocked uint64
+	PerClient  bool
+	Enabled    bool
+}
+
+// ValidateSpikeConfig returns an error if conf is invalid.
+func ValidateSpikeConfig(conf SpikeConfig) (err error) {
+	if conf.Multiplier <= 1 || math.IsInf(conf.Multiplier, 0) {
+		return fmt.Errorf("multiplier must be greater than 1, got %v", conf.Multiplier)
+	}
+
+	return nil
+}
+
+// spikeBaseline is the moving average of the blocked requests of a client or
+// of all of them.
+type spikeBaseline struct {
+	// avg is the moving average number of blocked requests per check.
+	avg float64
+
+	// checks is the number of checks the baseline has been collected during.
+	checks int
+
+	// spiking is true while the spike is being reported.
+	spiking bool
+}
+
+// update accounts n and returns true if n is a new spike according to conf.
+// The spikes aren't included into the baseline.
+func (b *spikeBaseline) update(conf SpikeConfig, n uint64) (isSpike bool) {
+	warm := b.checks >= spikeWarmupChecks
+	over := warm && n >= conf.MinBlocked && float64(n) > conf.Multiplier*b.avg
+	if over {
+		isSpike, b.spiking = !b.spiking, true
+
+		return isSpike
+	}
+
+	b.spiking = false
+	b.checks++
+	if b.checks == 1 {
+		b.avg = float64(n)
+	} else {
+		b.avg += spikeBaselineWeight * (float64(n) - b.avg)
+	}
+
+	return false
+}
+
+// SetBlockedCountsProvider injects the provider of the blocked requests
+// counts.
+func (m *Manager) SetBlockedCountsProvider(bp BlockedCountsProvider) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.blockedCounts = bp
+}
+
+// UpdateSpikeConfig applies the new blocked requests spike detection
+// configuration.  The collected baselines are dropped.
+func (m *Manager) UpdateSpikeConfig(conf SpikeConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.spike = conf
+	m.spikeBaselines = map[string]*spikeBaseline{}
+}
+
+// blockedSpike is a detected spike of the blocked requests.
+type blockedSpike struct {
+	// client is the client ID, it's empty for the overall spike.
+	client string
+
+	// baseline is the moving average of the blocked requests per check.
+	baseline float64
+
+	// blocked is the number of blocked requests during the check.
+	blocked uint64
+}
+
+// checkBlockedSpikes takes the blocked requests counts and sends the alerts
+// about the spikes detected.
+func (m *Manager) checkBlockedSpikes(ctx context.Context, cfg TelegramConfig) {
+	m.mu.RLock()
+	bp := m.blockedCounts
+	m.mu.RUnlock()
+
+	if bp == nil {
+		return
+	}
+
+	total, byClient := bp.TakeBlockedCounts()
+	for _, s := range m.detectSpikes(total, byClient) {
+		ev := &event{
+			time:      time.Now(),
+			typ:       eventTypeBlockedSpike,
+			metric:    s.client,
+			value:     float64(s.blocked),
+			threshold: s.baseline,
+			text:      composeBlockedSpikeMessage(cfg, s),
+		}
+
+		if err := m.deliver(ctx, cfg, ev); err != nil {
+			m.logger.Error("blocked spike alert failed", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// detectSpikes updates the baselines with the counts and returns the new
+// spikes.
+func (m *Manager) detectSpikes(total uint64, byClient map[string]uint64) (spikes []blockedSpike) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	conf := m.spike
+	if !conf.Enabled {
+		return nil
+	} else if m.spikeBaselines == nil {
+		m.spikeBaselines = map[string]*spikeBaseline{}
+	}
+
+	bump := func(client string, n uint64) {
+		b := m.spikeBaselines[client]
+		if b == nil {
+			b = &spikeBaseline{}
+			m.spikeBaselines[client] = b
+		}
+
+		avg := b.avg
+		if b.update(conf, n) {
+			spikes = append(spikes, blockedSpike{client: client, baseline: avg, blocked: n})
+		}
+	}
+
+	bump(spikeOverallKey, total)
+	if !conf.PerClient {
+		return spikes
+	}
+
+	for client, n := range byClient {
+		bump(client, n)
+	}
+
+	// Decay the baselines of the idle clients and forget the ones idle for
+	// long enough.
+	for client, b := range m.spikeBaselines {
+		if _, seen := byClient[client]; seen || client == spikeOverallKey {
+			continue
+		}
+
+		_ = b.update(conf, 0)
+		if b.avg < spikeMinBaseline {
+			delete(m.spikeBaselines, client)
+		}
+	}
+
+	return spikes
+}
+
+// composeBlockedSpikeMessage formats the blocked requests spike alert.
+func composeBlockedSpikeMessage(cfg TelegramConfig, s blockedSpike) (msg string) {
+	lines := make([]string, 0, 12)
+	if prefix := strings.TrimSpace(cfg.CustomMessage); prefix != "" {
+		lines = append(lines, prefix, "")
+	}
+
+	lines = append(lines, "📈 <b>Blocked queries spike</b>", divider(), "")
+	if s.client != "" {
+		lines = append(lines, fmt.Sprintf("  ▸ <b>Client:</b> <code>%s</code>", html.EscapeString(s.client)))
+	} else {
+		lines = append(lines, "  ▸ <b>Client:</b> all clients")
+	}
+
+	lines = append(lines,
+		fmt.Sprintf("  ▸ <b>Blocked:</b> <code>%d</code>", s.blocked),
+		fmt.Sprintf("  ▸ <b>Baseline:</b> <code>%.1f</code>", s.baseline),
+		"",
+		"<i>A sudden rise of blocked queries may mean malware beaconing or a misbehaving device.</i>",
+		"",
+		divider(),
+		timestampLine(),
+	)
+
+	return strings.Join(lines, "\n")
+}
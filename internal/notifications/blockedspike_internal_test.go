@@ -0,0 +1,50 @@
+package notifications
+
+import (
+	"log/slog"
+	"testing"
+)
+
+func TestManager_detectSpikes(t *testing.T) {
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	m.UpdateSpikeConfig(SpikeConfig{
+		Multiplier: 3,
+		MinBlocked: 50,
+		PerClient:  true,
+		Enabled:    true,
+	})
+
+	normal := map[string]uint64{"1.2.3.4": 10, "5.6.7.8": 10}
+	for range spikeWarmupChecks {
+		if spikes := m.detectSpikes(20, normal); len(spikes) != 0 {
+			t.Fatalf("unexpected spikes during warmup: %+v", spikes)
+		}
+	}
+
+	// The client spike is also the overall one.
+	spikes := m.detectSpikes(110, map[string]uint64{"1.2.3.4": 100, "5.6.7.8": 10})
+	if len(spikes) != 2 || spikes[0].client != spikeOverallKey || spikes[1].client != "1.2.3.4" {
+		t.Fatalf("got spikes %+v, want overall and 1.2.3.4", spikes)
+	}
+
+	// The ongoing spike isn't reported again.
+	if spikes = m.detectSpikes(110, map[string]uint64{"1.2.3.4": 100, "5.6.7.8": 10}); len(spikes) != 0 {
+		t.Errorf("ongoing spike reported again: %+v", spikes)
+	}
+
+	// Below MinBlocked.
+	m.detectSpikes(20, normal)
+	if spikes = m.detectSpikes(45, map[string]uint64{"1.2.3.4": 40, "5.6.7.8": 5}); len(spikes) != 0 {
+		t.Errorf("unexpected spikes below the minimum: %+v", spikes)
+	}
+}
+
+func TestValidateSpikeConfig(t *testing.T) {
+	if err := ValidateSpikeConfig(SpikeConfig{Multiplier: 1}); err == nil {
+		t.Error("no error for multiplier 1")
+	}
+
+	if err := ValidateSpikeConfig(SpikeConfig{Multiplier: 2.5}); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
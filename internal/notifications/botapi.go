@@ -68,6 +68,12 @@ type tgEditMessageTextRequest struct {
 	ReplyMarkup *tgInlineKeyboardMarkup `json:"reply_markup,omitempty"`
 }
 
+type tgEditMessageReplyMarkupRequest struct {
+	ChatID      int64                   `json:"chat_id"`
+	MessageID   int64                   `json:"message_id"`
+	ReplyMarkup *tgInlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
 type tgSetMyCommandsRequest struct {
 	Commands []tgBotCommand `json:"commands"`
 }
@@ -89,7 +95,8 @@ func (m *Manager) getUpdates(ctx context.Context, cfg TelegramConfig, offset int
 		return nil, fmt.Errorf("create getUpdates request: %w", err)
 	}
 
-	resp, err := m.pollClient.Do(req)
+	_, pollClient := m.telegramClients()
+	resp, err := pollClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("getUpdates request: %w", err)
 	}
@@ -138,13 +145,15 @@ func (m *Manager) sendMessageWithKeyboard(ctx context.Context, cfg TelegramConfi
 
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := m.client.Do(req)
+	client, _ := m.telegramClients()
+	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("sendMessage request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, telegramMaxMessageLen))
+	traceResponse(ctx, resp.StatusCode, respBody)
 
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("sendMessage status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
@@ -177,7 +186,8 @@ func (m *Manager) editMessageWithKeyboard(ctx context.Context, cfg TelegramConfi
 
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := m.client.Do(req)
+	client, _ := m.telegramClients()
+	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("editMessageText request: %w", err)
 	}
@@ -197,6 +207,45 @@ func (m *Manager) editMessageText(ctx context.Context, cfg TelegramConfig, chatI
 	return m.editMessageWithKeyboard(ctx, cfg, chatID, messageID, text, nil)
 }
 
+// editMessageReplyMarkup replaces the inline keyboard of an existing message
+// with kb.  A nil kb removes the keyboard.
+func (m *Manager) editMessageReplyMarkup(ctx context.Context, cfg TelegramConfig, chatID int64, messageID int64, kb *tgInlineKeyboardMarkup) error {
+	endpoint := fmt.Sprintf("https://api.telegram.org/bot%s/editMessageReplyMarkup", cfg.BotToken)
+
+	payload := tgEditMessageReplyMarkupRequest{
+		ChatID:      chatID,
+		MessageID:   messageID,
+		ReplyMarkup: kb,
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal editMessageReplyMarkup payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create editMessageReplyMarkup request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	client, _ := m.telegramClients()
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("editMessageReplyMarkup request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, telegramMaxMessageLen))
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("editMessageReplyMarkup status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return nil
+}
+
 func (m *Manager) answerCallbackQuery(ctx context.Context, cfg TelegramConfig, callbackID string) error {
 	endpoint := fmt.Sprintf("https://api.telegram.org/bot%s/answerCallbackQuery", cfg.BotToken)
 
@@ -214,7 +263,8 @@ func (m *Manager) answerCallbackQuery(ctx context.Context, cfg TelegramConfig, c
 
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := m.client.Do(req)
+	client, _ := m.telegramClients()
+	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("answerCallbackQuery request: %w", err)
 	}
@@ -233,7 +283,8 @@ func (m *Manager) deleteWebhook(ctx context.Context, cfg TelegramConfig) error {
 		return fmt.Errorf("create deleteWebhook request: %w", err)
 	}
 
-	resp, err := m.client.Do(req)
+	client, _ := m.telegramClients()
+	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("deleteWebhook request: %w", err)
 	}
@@ -270,6 +321,7 @@ func (m *Manager) registerBotCommands(ctx context.Context, cfg TelegramConfig) {
 			{Command: "removeallow", Description: "Remove allowlist: /removeallow <url>"},
 			{Command: "enablelist", Description: "Enable list: /enablelist <url>"},
 			{Command: "disablelist", Description: "Disable list: /disablelist <url>"},
+			{Command: "wake", Description: "Wake client: /wake <client name>"},
 		},
 	}
 
@@ -289,7 +341,8 @@ func (m *Manager) registerBotCommands(ctx context.Context, cfg TelegramConfig) {
 
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := m.client.Do(req)
+	client, _ := m.telegramClients()
+	resp, err := client.Do(req)
 	if err != nil {
 		m.logger.Debug("setMyCommands request failed", "error", err.Error())
 
@@ -0,0 +1,405 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+)
+
+// eventType is the type of a notification event.
+type eventType string
+
+// Available event types.
+const (
+	eventTypeAlert        eventType = "alert"
+	eventTypeRecovery     eventType = "recovery"
+	eventTypeFilterUpdate eventType = "filter_update"
+	eventTypeCertExpiry   eventType = "cert_expiry"
+	eventTypeCertRenewal  eventType = "cert_renewal"
+	eventTypeTest         eventType = "test"
+)
+
+// event is a notification delivered to the channels.
+type event struct {
+	// time is the time of the event.
+	time time.Time
+
+	// typ is the type of the event.
+	typ eventType
+
+	// filter is the refreshed filter list of the filter update events.
+	filter *FilterUpdate
+
+	// metric is the alert key, like "cpu" or "protection", of the alert and
+	// recovery events.
+	metric string
+
+	// text is the composed message in the HTML subset supported by Telegram.
+	text string
+
+	// value is the current value of the metric of the alert and recovery
+	// events.
+	value float64
+
+	// threshold is the threshold of the metric of the alert and recovery
+	// events.
+	threshold float64
+
+	// targets, if not nil, are the names of the targets ev is delivered to
+	// instead of the ones selected by the routing rules.
+	targets []string
+}
+
+// title returns the short summary of ev for the channels that show it
+// separately from the message.
+func (ev *event) title() (t string) {
+	switch ev.typ {
+	case eventTypeAlert:
+		return "Alert: " + metricDisplayName(ev.metric)
+	case eventTypeRecovery:
+		return "Recovery: " + recoveryHeadline(ev.metric)
+	case eventTypeFilterUpdate:
+		return "Filter list updated"
+	case eventTypeFilterUpdateFailure:
+		return "Filter list update failed"
+	case eventTypeCertExpiry:
+		return "Certificate expiring"
+	case eventTypeCertRenewal:
+		return "Certificate renewal"
+	case eventTypeQuietSummary:
+		return "Quiet hours summary"
+	case eventTypeDigest:
+		return "Notification digest"
+	case eventTypeStartup:
+		return "AdGuard Home started"
+	case eventTypeShutdown:
+		return "AdGuard Home shutting down"
+	case eventTypeUncleanRestart:
+		return "AdGuard Home restarted after an unclean shutdown"
+	case eventTypeConfigChange:
+		return "Configuration changed"
+	case eventTypeDHCPLease:
+		return dhcpLeaseHeadline(DHCPLeaseEventType(ev.metric))
+	case eventTypeBlockedSpike:
+		return "Blocked queries spike"
+	case eventTypeUpdateAvailable:
+		return "AdGuard Home update available"
+	case eventTypePublicIPChange:
+		return "Public IP address changed"
+	case eventTypeInterfaceChange:
+		return "Network interface " + ev.metric + " changed"
+	case eventTypeHeartbeat:
+		return "AdGuard Home is alive"
+	case eventTypeStatsReport:
+		return capitalizeFirst(ev.metric) + " statistics report"
+	case eventTypeStorageError:
+		return "Storage error: " + storageComponentLabel(StorageComponent(ev.metric))
+	case eventTypeRateLimit:
+		return "Notifications " + ev.metric
+	default:
+		return "AdGuard Home notification"
+	}
+}
+
+// summary returns the one-line description of ev for the summaries of the
+// held back events.
+func (ev *event) summary() (s string) {
+	if f := ev.filter; f != nil && f.Name != "" {
+		return ev.title() + ": " + f.Name
+	}
+
+	return ev.title()
+}
+
+// channel is a notification delivery backend other than the Telegram bot.
+type channel interface {
+	// name returns the name of the channel used in logs and the HTTP API.
+	name() (n string)
+
+	// send delivers ev to the channel.
+	send(ctx context.Context, ev *event) (err error)
+}
+
+// snapshotChannel is a channel which also accepts the periodic snapshots of
+// the system information.
+type snapshotChannel interface {
+	channel
+
+	// sendSnapshot delivers the system information.
+	sendSnapshot(ctx context.Context, info *systeminfo.Info) (err error)
+}
+
+// incidentChannel is a channel which tracks the state of the alerts and needs
+// to know when an alert is cleared without a recovery message.
+type incidentChannel interface {
+	channel
+
+	// resolve closes the alert for metric.
+	resolve(ctx context.Context, metric string) (err error)
+}
+
+// ChannelsConfig contains runtime configuration for the notification channels
+// other than Telegram.  The Proxy fields of the channels are the URLs of the
+// proxies the requests of the channels are sent through, see
+// [ValidateProxyURL].
+type ChannelsConfig struct {
+	Matrix    MatrixConfig
+	Gotify    GotifyConfig
+	MQTT      MQTTConfig
+	Signal    SignalConfig
+	PagerDuty PagerDutyConfig
+	Opsgenie  OpsgenieConfig
+	Teams     TeamsConfig
+
+	Alertmanager AlertmanagerConfig
+}
+
+// newChannels returns the enabled channels from cfg.
+func newChannels(client *http.Client, cfg ChannelsConfig) (chans []channel) {
+	if cfg.Matrix.Enabled {
+		chans = append(chans, newMatrixChannel(withProxy(client, cfg.Matrix.Proxy), cfg.Matrix))
+	}
+
+	if cfg.Gotify.Enabled {
+		chans = append(chans, newGotifyChannel(withProxy(client, cfg.Gotify.Proxy), cfg.Gotify))
+	}
+
+	if cfg.MQTT.Enabled {
+		chans = append(chans, newMQTTChannel(cfg.MQTT))
+	}
+
+	if cfg.Signal.Enabled {
+		chans = append(chans, newSignalChannel(withProxy(client, cfg.Signal.Proxy), cfg.Signal))
+	}
+
+	if cfg.PagerDuty.Enabled {
+		chans = append(chans, newPagerDutyChannel(withProxy(client, cfg.PagerDuty.Proxy), cfg.PagerDuty))
+	}
+
+	if cfg.Opsgenie.Enabled {
+		chans = append(chans, newOpsgenieChannel(withProxy(client, cfg.Opsgenie.Proxy), cfg.Opsgenie))
+	}
+
+	if cfg.Teams.Enabled {
+		chans = append(chans, newTeamsChannel(withProxy(client, cfg.Teams.Proxy), cfg.Teams))
+	}
+
+	if cfg.Alertmanager.Enabled {
+		chans = append(chans, newAlertmanagerChannel(withProxy(client, cfg.Alertmanager.Proxy), cfg.Alertmanager))
+	}
+
+	return chans
+}
+
+// UpdateChannelsConfig applies a new configuration of the notification
+// channels other than Telegram at runtime.
+func (m *Manager) UpdateChannelsConfig(cfg ChannelsConfig) {
+	chans := newChannels(m.direct, cfg)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.channels = chans
+}
+
+// getChannels returns the enabled channels.
+func (m *Manager) getChannels() (chans []channel) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.channels
+}
+
+// isReady returns true if there is at least one target to deliver the
+// notifications to.
+func (m *Manager) isReady(cfg TelegramConfig) (ok bool) {
+	return isTelegramReady(cfg) || len(m.getChannels()) > 0
+}
+
+// isTelegramReady returns true if the notifications should be delivered to
+// Telegram.
+func isTelegramReady(cfg TelegramConfig) (ok bool) {
+	if !cfg.Enabled || cfg.BotToken == "" {
+		return false
+	}
+
+	return cfg.ChatID != "" || slices.ContainsFunc(cfg.Chats, func(c TelegramChat) (ok bool) {
+		return c.Enabled && c.ID != ""
+	})
+}
+
+// deliver sends ev to Telegram, if configured, and to the enabled channels
+// selected by the routing rules.  The non-critical events are held back during
+// the quiet hours, and the ones selected for the digest are batched.  The
+// failures of the individual targets are logged, and err is only returned
+// if ev hasn't been delivered anywhere.  The failed deliveries are queued for
+// retrying, if the retry queue is enabled, and the queued ones are considered
+// delivered.
+func (m *Manager) deliver(ctx context.Context, cfg TelegramConfig, ev *event) (err error) {
+	var (
+		delivered bool
+		queued    bool
+		errs      []error
+	)
+
+	m.notifyListener(ev)
+
+	if status := m.holdForQuietHours(ev); status != "" {
+		m.record(newHistoryEntry(ev, "", status, nil))
+
+		return nil
+	} else if m.holdForDigest(ev) {
+		m.record(newHistoryEntry(ev, "", HistoryStatusBatched, nil))
+
+		return nil
+	} else if held, notice := m.holdForRateLimit(ev); held {
+		m.record(newHistoryEntry(ev, "", HistoryStatusSuppressed, nil))
+		if notice {
+			m.notifyRateLimited(ctx, cfg)
+		}
+
+		return nil
+	}
+
+	var entries []*HistoryEntry
+	defer func() { m.record(entries...) }()
+
+	targets, routed := m.routeTargets(ev.typ, ev.metric)
+	if ev.targets != nil {
+		targets, routed = ev.targets, true
+	}
+
+	if isTelegramReady(cfg) && isRouted(targets, routed, telegramTarget) {
+		for _, rcpt := range telegramRecipients(cfg, ev) {
+			var kb *tgInlineKeyboardMarkup
+			if cfg.AlertButtons && rcpt.target == telegramTarget {
+				kb = alertKeyboard(ev)
+			}
+
+			err = withRetry(ctx, func() error { return m.sendTelegramWithKeyboard(ctx, rcpt.cfg, ev.text, kb) })
+			entries = append(entries, newHistoryEntry(ev, rcpt.target, deliveryStatus(err), err))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", rcpt.target, err))
+				queued = m.enqueueRetry(ev, rcpt.target, err) || queued
+			} else {
+				delivered = true
+			}
+		}
+	}
+
+	for _, ch := range m.getChannels() {
+		if !isRouted(targets, routed, ch.name()) {
+			continue
+		}
+
+		err = withRetry(ctx, func() error { return ch.send(ctx, ev) })
+		entries = append(entries, newHistoryEntry(ev, ch.name(), deliveryStatus(err), err))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ch.name(), err))
+			queued = m.enqueueRetry(ev, ch.name(), err) || queued
+		} else {
+			delivered = true
+		}
+	}
+
+	err = errors.Join(errs...)
+	if (delivered || queued) && err != nil {
+		m.logger.Warn("notification partially delivered", "type", string(ev.typ), slog.String("error", err.Error()))
+
+		return nil
+	}
+
+	return err
+}
+
+// deliveryStatus returns the history status of a delivery attempt finished
+// with err.
+func deliveryStatus(err error) (status HistoryStatus) {
+	if err != nil {
+		return HistoryStatusFailed
+	}
+
+	return HistoryStatusSent
+}
+
+// sendSnapshots delivers info to all enabled channels accepting the system
+// snapshots.
+func (m *Manager) sendSnapshots(ctx context.Context, info *systeminfo.Info) {
+	for _, ch := range m.getChannels() {
+		sc, ok := ch.(snapshotChannel)
+		if !ok {
+			continue
+		}
+
+		err := sc.sendSnapshot(ctx, info)
+		if err != nil {
+			m.logger.Debug("sending system snapshot failed", "channel", sc.name(), slog.String("error", err.Error()))
+		}
+	}
+}
+
+// resolveIncidents closes the alert for metric in the enabled channels tracking
+// the state of the alerts, which the recovery of metric is routed to.
+func (m *Manager) resolveIncidents(ctx context.Context, metric string) {
+	targets, routed := m.routeTargets(eventTypeRecovery, metric)
+	for _, ch := range m.getChannels() {
+		ic, ok := ch.(incidentChannel)
+		if !ok || !isRouted(targets, routed, ch.name()) {
+			continue
+		}
+
+		err := withRetry(ctx, func() error { return ic.resolve(ctx, metric) })
+		if err != nil {
+			m.logger.Error("resolving alert failed", "channel", ic.name(), "metric", metric, slog.String("error", err.Error()))
+		}
+	}
+}
+
+// withRetry calls send until it succeeds with exponential backoff.  It waits
+// for the short delays requested by the target and gives up on the long ones.
+func withRetry(ctx context.Context, send func() error) (err error) {
+	delays := []time.Duration{1 * time.Second, 3 * time.Second, 10 * time.Second}
+
+	// First attempt without delay.
+	if err = send(); err == nil {
+		return nil
+	}
+
+	for _, delay := range delays {
+		if raErr, ok := errors.AsType[*retryAfterError](err); ok {
+			if raErr.after > maxInlineRetryAfter {
+				return err
+			}
+
+			delay = raErr.after
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+
+		if err = send(); err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+// tagRe matches the HTML tags.
+var tagRe = regexp.MustCompile(`<[^>]*>`)
+
+// htmlToText converts the message in the Telegram HTML subset into plain text.
+func htmlToText(s string) (text string) {
+	return html.UnescapeString(tagRe.ReplaceAllString(s, ""))
+}
@@ -2,66 +2,105 @@ package notifications
 
 import (
 	"fmt"
+	"html"
 	"strings"
 	"time"
 
 	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
 )
 
-func composeAlertMessage(cfg TelegramConfig, metric string, value, threshold float64, info systeminfo.Info) string {
+// composeAlertMessage formats a threshold alert notification.
+func composeAlertMessage(
+	cfg TelegramConfig,
+	loc Locale,
+	metric string,
+	value float64,
+	threshold float64,
+	info systeminfo.Info,
+) string {
 	lines := make([]string, 0, 20)
 	if prefix := strings.TrimSpace(cfg.CustomMessage); prefix != "" {
 		lines = append(lines, prefix)
 		lines = append(lines, "")
 	}
 
-	lines = append(lines, fmt.Sprintf("🚨 <b>ALERT: %s</b>", alertHeadline(metric)))
+	title := loc.text("notification_alert_title", "ALERT: {{metric}} exceeded threshold", "metric", loc.metricName(metric))
+	lines = append(lines, fmt.Sprintf("🚨 <b>%s</b>", title))
 	lines = append(lines, divider())
 	lines = append(lines, "")
-	lines = append(lines, sectionHeader("📈", "Metrics"))
-	lines = append(lines, fmt.Sprintf("  ▸ <b>Metric:</b>    %s", metricDisplayName(metric)))
-	lines = append(lines, fmt.Sprintf("  ▸ <b>Current:</b>   %s", usageBar(value)))
-	lines = append(lines, fmt.Sprintf("  ▸ <b>Threshold:</b> <code>%s</code>", formatPercentage(threshold)))
+	lines = append(lines, sectionHeader("📈", loc.text("notification_section_metrics", "Metrics")))
+	lines = append(lines, fmt.Sprintf("  ▸ <b>%s:</b>    %s", loc.text("notification_label_metric", "Metric"), loc.metricName(metric)))
+	lines = append(lines, fmt.Sprintf("  ▸ <b>%s:</b>   %s", loc.text("notification_label_current", "Current"), metricBar(metric, value)))
+	lines = append(lines, fmt.Sprintf(
+		"  ▸ <b>%s:</b> <code>%s</code>",
+		loc.text("notification_label_threshold", "Threshold"),
+		formatMetricValue(metric, threshold),
+	))
 	lines = append(lines, "")
-	lines = append(lines, systemOverviewLines(info)...)
+	if procLines := topProcessLines(loc, metric, info); len(procLines) > 0 {
+		lines = append(lines, procLines...)
+		lines = append(lines, "")
+	}
+	lines = append(lines, systemOverviewLines(loc, info)...)
 	lines = append(lines, "")
 	lines = append(lines, divider())
-	lines = append(lines, timestampLine())
+	lines = append(lines, loc.timestampLine())
 
 	return strings.Join(lines, "\n")
 }
 
 // composeRecoveryMessage formats a recovery notification.
-func composeRecoveryMessage(cfg TelegramConfig, metric string, currentValue, threshold float64, duration time.Duration, info systeminfo.Info) string {
+func composeRecoveryMessage(
+	cfg TelegramConfig,
+	loc Locale,
+	metric string,
+	currentValue float64,
+	threshold float64,
+	duration time.Duration,
+	info systeminfo.Info,
+) string {
 	lines := make([]string, 0, 24)
 	if prefix := strings.TrimSpace(cfg.CustomMessage); prefix != "" {
 		lines = append(lines, prefix)
 		lines = append(lines, "")
 	}
 
-	lines = append(lines, fmt.Sprintf("✅ <b>RECOVERY: %s</b>", recoveryHeadline(metric)))
+	title := loc.text("notification_recovery_title", "RECOVERY: {{metric}} back to normal", "metric", loc.metricName(metric))
+	lines = append(lines, fmt.Sprintf("✅ <b>%s</b>", title))
 	lines = append(lines, divider())
 	lines = append(lines, "")
 
-	lines = append(lines, sectionHeader("📈", "Metrics"))
-	lines = append(lines, fmt.Sprintf("  ▸ <b>Metric:</b>         %s", metricDisplayName(metric)))
-	if metric != "protection" {
-		lines = append(lines, fmt.Sprintf("  ▸ <b>Current:</b>        %s", usageBar(currentValue)))
+	lines = append(lines, sectionHeader("📈", loc.text("notification_section_metrics", "Metrics")))
+	lines = append(lines, fmt.Sprintf("  ▸ <b>%s:</b>         %s", loc.text("notification_label_metric", "Metric"), loc.metricName(metric)))
+	if metric != "protection" && metric != metricPower && !strings.HasPrefix(metric, diskHealthMetricPrefix) {
+		lines = append(lines, fmt.Sprintf(
+			"  ▸ <b>%s:</b>        %s",
+			loc.text("notification_label_current", "Current"),
+			metricBar(metric, currentValue),
+		))
 		if threshold > 0 {
-			lines = append(lines, fmt.Sprintf("  ▸ <b>Threshold:</b>      <code>%s</code>", formatPercentage(threshold)))
+			lines = append(lines, fmt.Sprintf(
+				"  ▸ <b>%s:</b>      <code>%s</code>",
+				loc.text("notification_label_threshold", "Threshold"),
+				formatMetricValue(metric, threshold),
+			))
 		}
 	}
 
-	lines = append(lines, fmt.Sprintf("  ▸ <b>Alert Duration:</b> <code>%s</code>", duration.String()))
+	lines = append(lines, fmt.Sprintf(
+		"  ▸ <b>%s:</b> <code>%s</code>",
+		loc.text("notification_label_alert_duration", "Alert Duration"),
+		duration.String(),
+	))
 	lines = append(lines, "")
 
 	if info.Hostname != "" {
-		lines = append(lines, systemOverviewLines(info)...)
+		lines = append(lines, systemOverviewLines(loc, info)...)
 		lines = append(lines, "")
 	}
 
 	lines = append(lines, divider())
-	lines = append(lines, timestampLine())
+	lines = append(lines, loc.timestampLine())
 
 	return strings.Join(lines, "\n")
 }
@@ -70,27 +109,31 @@ func recoveryHeadline(metric string) string {
 	return fmt.Sprintf("%s back to normal", metricDisplayName(metric))
 }
 
-func composeProtectionAlertMessage(cfg TelegramConfig, info systeminfo.Info) string {
+func composeProtectionAlertMessage(cfg TelegramConfig, loc Locale, info systeminfo.Info) string {
 	lines := make([]string, 0, 24)
 	if prefix := strings.TrimSpace(cfg.CustomMessage); prefix != "" {
 		lines = append(lines, prefix)
 		lines = append(lines, "")
 	}
 
-	lines = append(lines, "🚨 <b>ALERT: DNS Protection is DISABLED!</b>")
+	title := loc.text("notification_protection_disabled_title", "ALERT: DNS Protection is DISABLED!")
+	lines = append(lines, fmt.Sprintf("🚨 <b>%s</b>", title))
 	lines = append(lines, divider())
 	lines = append(lines, "")
-	lines = append(lines, "🔴 DNS filtering is currently <b>turned off</b>.")
-	lines = append(lines, "<i>All queries pass through unfiltered.</i>")
+	lines = append(lines, "🔴 "+loc.text("notification_protection_disabled_desc", "DNS filtering is currently turned off."))
+	lines = append(lines, fmt.Sprintf(
+		"<i>%s</i>",
+		loc.text("notification_protection_disabled_hint", "All queries pass through unfiltered."),
+	))
 	lines = append(lines, "")
 
 	if info.Hostname != "" {
-		lines = append(lines, systemOverviewLines(info)...)
+		lines = append(lines, systemOverviewLines(loc, info)...)
 		lines = append(lines, "")
 	}
 
 	lines = append(lines, divider())
-	lines = append(lines, timestampLine())
+	lines = append(lines, loc.timestampLine())
 
 	return strings.Join(lines, "\n")
 }
@@ -108,13 +151,13 @@ func composeYouTubeAlertMessage(cfg TelegramConfig, status YouTubeStatus, info s
 	lines = append(lines, divider())
 	lines = append(lines, "")
 	lines = append(lines, fmt.Sprintf("  ▸ <b>Healthy IPs:</b> <code>%d</code> / <code>%d</code>", status.HealthyIPs, status.TotalIPs))
-	lines = append(lines, fmt.Sprintf("  ▸ <b>Last sync:</b>   %s", fallbackString(status.LastSyncStatus)))
+	lines = append(lines, fmt.Sprintf("  ▸ <b>Last sync:</b>   %s", fallbackString(html.EscapeString(status.LastSyncStatus))))
 	lines = append(lines, "")
 	lines = append(lines, "<i>DNS rewrites routing YouTube traffic may stop working until the route server recovers.</i>")
 	lines = append(lines, "")
 
 	if info.Hostname != "" {
-		lines = append(lines, systemOverviewLines(info)...)
+		lines = append(lines, systemOverviewLines(nil, info)...)
 		lines = append(lines, "")
 	}
 
@@ -142,7 +185,7 @@ func composeYouTubeStatusMessage(status YouTubeStatus) string {
 		fmt.Sprintf("  ▸ <b>Healthy IPs:</b>    <code>%d</code> / <code>%d</code>", status.HealthyIPs, status.TotalIPs),
 		fmt.Sprintf("  ▸ <b>Blocked rules:</b>  <code>%d</code>", status.BlockedRules),
 		fmt.Sprintf("  ▸ <b>Active rewrites:</b> <code>%d</code>", status.ActiveRewrites),
-		fmt.Sprintf("  ▸ <b>Last sync:</b>      %s", fallbackString(status.LastSyncStatus)),
+		fmt.Sprintf("  ▸ <b>Last sync:</b>      %s", fallbackString(html.EscapeString(status.LastSyncStatus))),
 	}
 
 	if !status.LastSyncTime.IsZero() {
@@ -174,8 +217,8 @@ func composeCertStatusMessage(status CertStatus) string {
 		divider(),
 		"",
 		fmt.Sprintf("  %s <b>Status:</b>      %s", statusIcon, statusText),
-		fmt.Sprintf("  ▸ <b>Domains:</b>     %s", fallbackString(strings.Join(status.Domains, ", "))),
-		fmt.Sprintf("  ▸ <b>Challenge:</b>   %s", fallbackString(status.Challenge)),
+		fmt.Sprintf("  ▸ <b>Domains:</b>     %s", fallbackString(html.EscapeString(strings.Join(status.Domains, ", ")))),
+		fmt.Sprintf("  ▸ <b>Challenge:</b>   %s", fallbackString(html.EscapeString(status.Challenge))),
 		fmt.Sprintf("  %s <b>Auto-renew:</b>  %s", autoRenewIcon, autoRenewText),
 	}
 
@@ -202,48 +245,60 @@ func composeCertStatusMessage(status CertStatus) string {
 	return strings.Join(lines, "\n")
 }
 
-func composeFilterUpdateMessage(cfg TelegramConfig, update FilterUpdate, info systeminfo.Info) string {
+func composeFilterUpdateMessage(cfg TelegramConfig, loc Locale, update FilterUpdate, info systeminfo.Info) string {
 	lines := make([]string, 0, 24)
 	if prefix := strings.TrimSpace(cfg.CustomMessage); prefix != "" {
 		lines = append(lines, prefix)
 		lines = append(lines, "")
 	}
 
-	head := filterUpdateHeader(update.ListType)
-	lines = append(lines, fmt.Sprintf("🔄 <b>%s</b>", head))
+	typ := loc.filterTypeLabel(update.ListType)
+	lines = append(lines, fmt.Sprintf("🔄 <b>%s</b>", loc.text("notification_filter_updated_title", "{{type}} Updated", "type", typ)))
 	lines = append(lines, divider())
 	lines = append(lines, "")
-	lines = append(lines, sectionHeader("📋", "List Details"))
-	lines = append(lines, fmt.Sprintf("  ▸ <b>Name:</b>   %s", fallbackString(update.Name)))
+	lines = append(lines, sectionHeader("📋", loc.text("notification_section_list_details", "List Details")))
+	lines = append(lines, fmt.Sprintf(
+		"  ▸ <b>%s:</b>   %s",
+		loc.text("notification_label_name", "Name"),
+		fallbackString(html.EscapeString(update.Name)),
+	))
 	if update.ID != 0 {
-		lines = append(lines, fmt.Sprintf("  ▸ <b>ID:</b>     <code>#%s</code>", formatUint64(update.ID)))
+		lines = append(lines, fmt.Sprintf("  ▸ <b>%s:</b>     <code>#%s</code>", loc.text("notification_label_id", "ID"), formatUint64(update.ID)))
 	}
-	lines = append(lines, fmt.Sprintf("  ▸ <b>Type:</b>   %s", filterTypeLabel(update.ListType)))
+	lines = append(lines, fmt.Sprintf("  ▸ <b>%s:</b>   %s", loc.text("notification_label_type", "Type"), typ))
 	if update.URL != "" {
-		lines = append(lines, fmt.Sprintf("  ▸ <b>Source:</b> <code>%s</code>", update.URL))
+		lines = append(lines, fmt.Sprintf("  ▸ <b>%s:</b> %s", loc.text("notification_label_source", "Source"), linkOrCode(update.URL)))
 	}
 
 	rules := update.RulesCount
 	if rules < 0 {
 		rules = 0
 	}
-	lines = append(lines, fmt.Sprintf("  ▸ <b>Rules:</b>  <code>%s</code> entries", formatInt64(int64(rules))))
+	lines = append(lines, fmt.Sprintf(
+		"  ▸ <b>%s:</b>  %s",
+		loc.text("notification_label_rules", "Rules"),
+		loc.text("notification_rules_entries", "{{count}} entries", "count", "<code>"+formatInt64(int64(rules))+"</code>"),
+	))
 	if update.BytesWritten > 0 {
-		lines = append(lines, fmt.Sprintf("  ▸ <b>Size:</b>   <code>%s</code>", formatBytesUint(uint64(update.BytesWritten))))
+		lines = append(lines, fmt.Sprintf(
+			"  ▸ <b>%s:</b>   <code>%s</code>",
+			loc.text("notification_label_size", "Size"),
+			formatBytesUint(uint64(update.BytesWritten)),
+		))
 	}
 
 	statusIcon := "✅"
-	statusLabel := "Enabled"
+	statusLabel := loc.text("notification_status_enabled", "Enabled")
 	if !update.Enabled {
 		statusIcon = "🚫"
-		statusLabel = "Disabled"
+		statusLabel = loc.text("notification_status_disabled", "Disabled")
 	}
-	lines = append(lines, fmt.Sprintf("  ▸ <b>Status:</b> %s %s", statusIcon, statusLabel))
+	lines = append(lines, fmt.Sprintf("  ▸ <b>%s:</b> %s %s", loc.text("notification_label_status", "Status"), statusIcon, statusLabel))
 	lines = append(lines, "")
-	lines = append(lines, systemOverviewLines(info)...)
+	lines = append(lines, systemOverviewLines(loc, info)...)
 	lines = append(lines, "")
 	lines = append(lines, divider())
-	lines = append(lines, timestampLine())
+	lines = append(lines, loc.timestampLine())
 
 	return strings.Join(lines, "\n")
 }
@@ -266,13 +321,13 @@ func composeCertExpiryMessage(cfg TelegramConfig, ev CertExpiryReminder, info sy
 	lines = append(lines, divider())
 	lines = append(lines, "")
 	lines = append(lines, sectionHeader("🔐", "Certificate"))
-	lines = append(lines, fmt.Sprintf("  ▸ <b>Domains:</b>    %s", fallbackString(strings.Join(ev.Domains, ", "))))
+	lines = append(lines, fmt.Sprintf("  ▸ <b>Domains:</b>    %s", fallbackString(html.EscapeString(strings.Join(ev.Domains, ", ")))))
 	lines = append(lines, fmt.Sprintf("  ▸ <b>Expires:</b>    <code>%s</code>", ev.NotAfter.Format(time.RFC1123)))
 	lines = append(lines, fmt.Sprintf("  ▸ <b>Days left:</b>  <code>%d</code>", ev.DaysLeft))
 	lines = append(lines, "")
 	lines = append(lines, "Renew it manually in AdGuard Home's encryption settings.")
 	lines = append(lines, "")
-	lines = append(lines, systemOverviewLines(info)...)
+	lines = append(lines, systemOverviewLines(nil, info)...)
 	lines = append(lines, "")
 	lines = append(lines, divider())
 	lines = append(lines, timestampLine())
@@ -294,8 +349,8 @@ func composeCertRenewalMessage(cfg TelegramConfig, ev CertRenewalResult, info sy
 		lines = append(lines, divider())
 		lines = append(lines, "")
 		lines = append(lines, sectionHeader("🔐", "Certificate"))
-		lines = append(lines, fmt.Sprintf("  ▸ <b>Domains:</b> %s", fallbackString(strings.Join(ev.Domains, ", "))))
-		lines = append(lines, fmt.Sprintf("  ▸ <b>Error:</b>   <code>%s</code>", ev.Err.Error()))
+		lines = append(lines, fmt.Sprintf("  ▸ <b>Domains:</b> %s", fallbackString(html.EscapeString(strings.Join(ev.Domains, ", ")))))
+		lines = append(lines, fmt.Sprintf("  ▸ <b>Error:</b>   <code>%s</code>", html.EscapeString(ev.Err.Error())))
 		lines = append(lines, "")
 		lines = append(lines, "Renew it manually in AdGuard Home's encryption settings.")
 	} else {
@@ -303,12 +358,12 @@ func composeCertRenewalMessage(cfg TelegramConfig, ev CertRenewalResult, info sy
 		lines = append(lines, divider())
 		lines = append(lines, "")
 		lines = append(lines, sectionHeader("🔐", "Certificate"))
-		lines = append(lines, fmt.Sprintf("  ▸ <b>Domains:</b>     %s", fallbackString(strings.Join(ev.Domains, ", "))))
+		lines = append(lines, fmt.Sprintf("  ▸ <b>Domains:</b>     %s", fallbackString(html.EscapeString(strings.Join(ev.Domains, ", ")))))
 		lines = append(lines, fmt.Sprintf("  ▸ <b>New expiry:</b>  <code>%s</code>", ev.NotAfter.Format(time.RFC1123)))
 	}
 
 	lines = append(lines, "")
-	lines = append(lines, systemOverviewLines(info)...)
+	lines = append(lines, systemOverviewLines(nil, info)...)
 	lines = append(lines, "")
 	lines = append(lines, divider())
 	lines = append(lines, timestampLine())
@@ -316,11 +371,15 @@ func composeCertRenewalMessage(cfg TelegramConfig, ev CertRenewalResult, info sy
 	return strings.Join(lines, "\n")
 }
 
-func alertHeadline(metric string) string {
-	return fmt.Sprintf("%s exceeded threshold", metricDisplayName(metric))
-}
-
 func metricDisplayName(metric string) string {
+	if iface, ok := strings.CutPrefix(metric, bandwidthMetricPrefix); ok {
+		return fmt.Sprintf("Bandwidth (%s)", iface)
+	} else if path, ok := strings.CutPrefix(metric, diskPathMetricPrefix); ok {
+		return fmt.Sprintf("Disk Usage (%s)", path)
+	} else if dev, ok := strings.CutPrefix(metric, diskHealthMetricPrefix); ok {
+		return fmt.Sprintf("Disk Health (%s)", dev)
+	}
+
 	switch strings.ToLower(metric) {
 	case "cpu":
 		return "CPU Usage"
@@ -328,10 +387,30 @@ func metricDisplayName(metric string) string {
 		return "Memory Usage"
 	case "disk":
 		return "Disk Usage"
+	case "swap":
+		return "Swap Usage"
 	case "protection":
 		return "DNS Protection"
 	case "youtube_health":
 		return "YouTube Blocking"
+	case metricPower:
+		return "Power Supply"
+	case metricTemperature:
+		return "CPU Temperature"
+	case metricSelfRSS:
+		return "AdGuard Home Memory"
+	case metricSelfGoroutines:
+		return "AdGuard Home Goroutines"
+	case metricSelfOpenFiles:
+		return "AdGuard Home Open Files"
+	case metricSelfGCPause:
+		return "AdGuard Home GC Pause"
+	case metricDNSLatency:
+		return "DNS Upstream Latency"
+	case metricDNSServFail:
+		return "DNS SERVFAIL Rate"
+	case metricDNSQPS:
+		return "DNS Queries per Second"
 	default:
 		if metric == "" {
 			return "Metric"
@@ -340,17 +419,6 @@ func metricDisplayName(metric string) string {
 	}
 }
 
-func filterUpdateHeader(listType FilterListType) string {
-	switch listType {
-	case FilterListTypeAllow:
-		return "Allowlist Updated"
-	case FilterListTypeBlock:
-		return "Blocklist Updated"
-	default:
-		return "Filter Updated"
-	}
-}
-
 func filterTypeLabel(listType FilterListType) string {
 	switch listType {
 	case FilterListTypeAllow:
@@ -2,6 +2,7 @@ package notifications
 
 import (
 	"errors"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -116,6 +117,7 @@ func TestMetricDisplayName(t *testing.T) {
 		want   string
 	}{
 		{metric: "cpu", want: "CPU Usage"},
+		{metric: "swap", want: "Swap Usage"},
 		{metric: "protection", want: "DNS Protection"},
 		{metric: "youtube_health", want: "YouTube Blocking"},
 		{metric: "", want: "Metric"},
@@ -205,3 +207,81 @@ func keyboardHasCallback(kb *tgInlineKeyboardMarkup, callback string) bool {
 
 	return false
 }
+
+func TestComposeConfigChangeMessage(t *testing.T) {
+	keys := make([]string, maxConfigChangeKeys+2)
+	for i := range keys {
+		keys[i] = "dns.key" + strconv.Itoa(i)
+	}
+
+	keys[0] = "dns.<upstream_dns>"
+
+	msg := composeConfigChangeMessage(TelegramConfig{}, ConfigChange{
+		User:     "admin",
+		RemoteIP: "192.0.2.1",
+		Keys:     keys,
+	})
+
+	for _, want := range []string{"admin", "192.0.2.1", "dns.&lt;upstream_dns&gt;", "and 2 more"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected message to contain %q, got: %s", want, msg)
+		}
+	}
+}
+
+func TestComposeUpdateAvailableMessage(t *testing.T) {
+	msg := composeUpdateAvailableMessage(TelegramConfig{}, UpdateInfo{
+		NewVersion:      "v0.107.60",
+		CurrentVersion:  "v0.107.59",
+		Channel:         "release",
+		AnnouncementURL: "https://github.com/AdguardTeam/AdGuardHome/releases/tag/v0.107.60",
+	})
+
+	for _, want := range []string{"v0.107.60", "v0.107.59", "release", `href="https://github.com/`} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected message to contain %q, got: %s", want, msg)
+		}
+	}
+}
+
+func TestComposeFilterUpdateFailureMessage(t *testing.T) {
+	now := time.Now()
+	msg := composeFilterUpdateFailureMessage(TelegramConfig{}, nil, FilterUpdateFailure{
+		LastSuccess: now.Add(-72 * time.Hour),
+		Name:        "AdGuard DNS filter",
+		URL:         "https://example.org/filter.txt",
+		Reason:      "http",
+		Error:       "got status code 404, want 200",
+		ListType:    FilterListTypeBlock,
+		ID:          1,
+	}, now)
+
+	for _, want := range []string{"Blocklist Update Failed", "Download failed", "status code 404", "72h0m0s ago"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected message to contain %q, got: %s", want, msg)
+		}
+	}
+}
+
+func TestComposeAlertMessage_topProcesses(t *testing.T) {
+	info := systeminfo.Info{
+		TopCPUProcesses: []systeminfo.ProcessUsage{{Name: "<ffmpeg>", PID: 42, CPUPercent: 180}},
+		TopMemProcesses: []systeminfo.ProcessUsage{{Name: "java", PID: 7, MemBytes: 1 << 30}},
+	}
+
+	msg := composeAlertMessage(TelegramConfig{}, nil, "cpu", 95, 90, info)
+	for _, want := range []string{"Top Processes", "&lt;ffmpeg&gt;", "(42)"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message %q does not contain %q", msg, want)
+		}
+	}
+
+	if strings.Contains(msg, "java") {
+		t.Errorf("cpu alert %q contains the top memory processes", msg)
+	}
+
+	msg = composeAlertMessage(TelegramConfig{}, nil, "disk", 95, 90, info)
+	if strings.Contains(msg, "Top Processes") {
+		t.Errorf("disk alert %q contains the top processes", msg)
+	}
+}
@@ -0,0 +1,81 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// eventTypeConfigChange is the type of the configuration change events.
+const eventTypeConfigChange eventType = "config_change"
+
+// maxConfigChangeKeys is the maximum number of the changed keys listed in the
+// message.
+const maxConfigChangeKeys = 20
+
+// ConfigChange describes a change of the configuration applied through the
+// web API.
+type ConfigChange struct {
+	// User is the login of the web user, who has made the change.  It's empty
+	// if the authentication is disabled.
+	User string
+
+	// RemoteIP is the address of the client, which the change has come from.
+	RemoteIP string
+
+	// Keys are the changed configuration keys, like "dns.upstream_dns".
+	Keys []string
+}
+
+// NotifyConfigChange sends the notification about the configuration change.
+func (m *Manager) NotifyConfigChange(ctx context.Context, change ConfigChange) {
+	cfg := m.getTelegramConfig()
+	if !m.isReady(cfg) || len(change.Keys) == 0 {
+		return
+	}
+
+	ev := &event{
+		time: time.Now(),
+		typ:  eventTypeConfigChange,
+		text: composeConfigChangeMessage(cfg, change),
+	}
+
+	if err := m.deliver(ctx, cfg, ev); err != nil {
+		m.logger.Error("config change notification failed", slog.String("error", err.Error()))
+	}
+}
+
+// composeConfigChangeMessage formats the configuration change notification.
+func composeConfigChangeMessage(cfg TelegramConfig, change ConfigChange) (msg string) {
+	lines := make([]string, 0, 16+min(len(change.Keys), maxConfigChangeKeys))
+	if prefix := strings.TrimSpace(cfg.CustomMessage); prefix != "" {
+		lines = append(lines, prefix, "")
+	}
+
+	lines = append(lines, "⚙️ <b>Configuration changed</b>", divider(), "")
+	if change.User != "" {
+		lines = append(lines, fmt.Sprintf("  ▸ <b>User:</b> <code>%s</code>", html.EscapeString(change.User)))
+	}
+
+	if change.RemoteIP != "" {
+		lines = append(lines, fmt.Sprintf("  ▸ <b>From:</b> <code>%s</code>", html.EscapeString(change.RemoteIP)))
+	}
+
+	lines = append(lines, "  ▸ <b>Changed:</b>")
+	for i, key := range change.Keys {
+		if i == maxConfigChangeKeys {
+			lines = append(lines, fmt.Sprintf("    • <i>and %d more</i>", len(change.Keys)-i))
+
+			break
+		}
+
+		lines = append(lines, fmt.Sprintf("    • <code>%s</code>", html.EscapeString(key)))
+	}
+
+	lines = append(lines, "", divider(), timestampLine())
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,164 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// eventTypeDHCPLease is the type of the DHCP lease events.  The metric of the
+// event is its [DHCPLeaseEventType], so that the routing rules like
+// "dhcp_lease:expired" can be used.
+const eventTypeDHCPLease eventType = "dhcp_lease"
+
+// DHCPLeaseEventType is the type of a DHCP lease event.
+type DHCPLeaseEventType string
+
+// DHCPLeaseEventType values.
+const (
+	DHCPLeaseGranted        DHCPLeaseEventType = "granted"
+	DHCPLeaseRenewed        DHCPLeaseEventType = "renewed"
+	DHCPLeaseExpired        DHCPLeaseEventType = "expired"
+	DHCPLeaseStaticConflict DHCPLeaseEventType = "static_conflict"
+)
+
+// DHCPEventsConfig contains the per-event toggles of the DHCP lease
+// notifications.
+type DHCPEventsConfig struct {
+	Granted        bool
+	Renewed        bool
+	Expired        bool
+	StaticConflict bool
+}
+
+// enabled returns true if the notifications about typ are enabled.
+func (c DHCPEventsConfig) enabled(typ DHCPLeaseEventType) (ok bool) {
+	switch typ {
+	case DHCPLeaseGranted:
+		return c.Granted
+	case DHCPLeaseRenewed:
+		return c.Renewed
+	case DHCPLeaseExpired:
+		return c.Expired
+	case DHCPLeaseStaticConflict:
+		return c.StaticConflict
+	default:
+		return false
+	}
+}
+
+// DHCPLeaseEvent is an event happened to a DHCP lease.
+type DHCPLeaseEvent struct {
+	// Expiry is the expiration time of the lease.  It's zero for static
+	// leases.
+	Expiry time.Time
+
+	// Type is the type of the event.
+	Type DHCPLeaseEventType
+
+	// Hostname is the hostname of the client, if any.
+	Hostname string
+
+	// IP is the leased address.
+	IP string
+
+	// MAC is the hardware address of the client.
+	MAC string
+
+	// Reason describes the static lease conflict.
+	Reason string
+}
+
+// UpdateDHCPEventsConfig applies the new DHCP lease notifications toggles.
+func (m *Manager) UpdateDHCPEventsConfig(conf DHCPEventsConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.dhcpEvents = conf
+}
+
+// NotifyDHCPLease sends the notification about the DHCP lease event, if the
+// notifications about its type are enabled.
+func (m *Manager) NotifyDHCPLease(ctx context.Context, lev DHCPLeaseEvent) {
+	m.mu.RLock()
+	conf := m.dhcpEvents
+	m.mu.RUnlock()
+
+	cfg := m.getTelegramConfig()
+	if !conf.enabled(lev.Type) || !m.isReady(cfg) {
+		return
+	}
+
+	ev := &event{
+		time:   time.Now(),
+		typ:    eventTypeDHCPLease,
+		metric: string(lev.Type),
+		text:   composeDHCPLeaseMessage(cfg, lev),
+	}
+
+	if err := m.deliver(ctx, cfg, ev); err != nil {
+		m.logger.Error(
+			"dhcp lease notification failed",
+			"type", lev.Type,
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// dhcpLeaseHeadline returns the headline of the DHCP lease notification of
+// typ.
+func dhcpLeaseHeadline(typ DHCPLeaseEventType) (h string) {
+	switch typ {
+	case DHCPLeaseGranted:
+		return "DHCP lease granted"
+	case DHCPLeaseRenewed:
+		return "DHCP lease renewed"
+	case DHCPLeaseExpired:
+		return "DHCP lease expired"
+	case DHCPLeaseStaticConflict:
+		return "Static DHCP lease conflict"
+	default:
+		return "DHCP lease event"
+	}
+}
+
+// composeDHCPLeaseMessage formats the DHCP lease notification.
+func composeDHCPLeaseMessage(cfg TelegramConfig, lev DHCPLeaseEvent) (msg string) {
+	lines := make([]string, 0, 16)
+	if prefix := strings.TrimSpace(cfg.CustomMessage); prefix != "" {
+		lines = append(lines, prefix, "")
+	}
+
+	icon := "📶"
+	if lev.Type == DHCPLeaseStaticConflict {
+		icon = "⚠️"
+	}
+
+	lines = append(lines, fmt.Sprintf("%s <b>%s</b>", icon, dhcpLeaseHeadline(lev.Type)), divider(), "")
+	if lev.Hostname != "" {
+		lines = append(lines, fmt.Sprintf("  ▸ <b>Hostname:</b> <code>%s</code>", html.EscapeString(lev.Hostname)))
+	}
+
+	lines = append(lines,
+		fmt.Sprintf("  ▸ <b>IP:</b> <code>%s</code>", html.EscapeString(fallbackString(lev.IP))),
+		fmt.Sprintf("  ▸ <b>MAC:</b> <code>%s</code>", html.EscapeString(fallbackString(lev.MAC))),
+	)
+
+	if !lev.Expiry.IsZero() && lev.Type != DHCPLeaseStaticConflict {
+		lines = append(lines, fmt.Sprintf(
+			"  ▸ <b>Expires:</b> <code>%s</code>",
+			toLocal(lev.Expiry).Format(time.DateTime),
+		))
+	}
+
+	if lev.Reason != "" {
+		lines = append(lines, fmt.Sprintf("  ▸ <b>Reason:</b> %s", html.EscapeString(lev.Reason)))
+	}
+
+	lines = append(lines, "", divider(), timestampLine())
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,56 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestManager_NotifyDHCPLease(t *testing.T) {
+	rec := &recordingChannel{chanName: "matrix"}
+
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	m.channels = []channel{rec}
+	m.UpdateDHCPEventsConfig(DHCPEventsConfig{
+		Granted:        true,
+		StaticConflict: true,
+	})
+
+	ctx := context.Background()
+	for _, typ := range []DHCPLeaseEventType{
+		DHCPLeaseGranted,
+		DHCPLeaseRenewed,
+		DHCPLeaseExpired,
+		DHCPLeaseStaticConflict,
+	} {
+		m.NotifyDHCPLease(ctx, DHCPLeaseEvent{Type: typ, IP: "192.168.1.10"})
+	}
+
+	want := []eventType{eventTypeDHCPLease, eventTypeDHCPLease}
+	if !slices.Equal(rec.got, want) {
+		t.Errorf("got %q, want %q", rec.got, want)
+	}
+}
+
+func TestComposeDHCPLeaseMessage(t *testing.T) {
+	msg := composeDHCPLeaseMessage(TelegramConfig{}, DHCPLeaseEvent{
+		Type:     DHCPLeaseStaticConflict,
+		Hostname: "<laptop>",
+		IP:       "192.168.1.10",
+		MAC:      "aa:bb:cc:dd:ee:ff",
+		Reason:   "ip address is not unique",
+	})
+
+	for _, want := range []string{
+		"Static DHCP lease conflict",
+		"&lt;laptop&gt;",
+		"aa:bb:cc:dd:ee:ff",
+		"ip address is not unique",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message %q doesn't contain %q", msg, want)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+)
+
+// maxProviderErrorLen is the maximum length of the provider error in the test
+// results.
+const maxProviderErrorLen = 512
+
+// TestResult contains the diagnostics of a test notification sent to a single
+// target.
+type TestResult struct {
+	// Target is the name of the target, like "matrix" or "telegram:-100123".
+	Target string
+
+	// Error is the delivery error, if any.
+	Error string
+
+	// ProviderError is the error description returned by the API of the
+	// target, if any.
+	ProviderError string
+
+	// Latency is the duration of the delivery.
+	Latency time.Duration
+
+	// Status is the HTTP status code of the last response of the API of the
+	// target.  It's zero if there has been no HTTP response.
+	Status int
+}
+
+// deliveryTrace records the last response of the API of a target.
+type deliveryTrace struct {
+	body   []byte
+	status int
+}
+
+// deliveryTraceKey is the context key of the [deliveryTrace].
+type deliveryTraceKey struct{}
+
+// traceResponse records the status code and the body of the response in the
+// trace within ctx, if any.
+func traceResponse(ctx context.Context, status int, body []byte) {
+	if tr, ok := ctx.Value(deliveryTraceKey{}).(*deliveryTrace); ok {
+		tr.status, tr.body = status, body
+	}
+}
+
+// SendTest sends the test message to the target with the given name, which is
+// either "telegram" or the name of a channel, and returns the diagnostics for
+// each of its recipients.  err is not nil if the target isn't enabled.
+func (m *Manager) SendTest(ctx context.Context, target, message string) (results []TestResult, err error) {
+	msg := strings.TrimSpace(message)
+	if msg == "" {
+		msg = "AdGuard Home test notification"
+	}
+
+	if target == telegramTarget {
+		return m.sendTelegramTest(ctx, msg)
+	}
+
+	for _, ch := range m.getChannels() {
+		if ch.name() != target {
+			continue
+		}
+
+		ev := &event{
+			time: time.Now(),
+			typ:  eventTypeTest,
+			text: fmt.Sprintf(
+				"🔔 <b>Test Notification</b>\n%s\n\n💬 <code>%s</code>\n\n%s",
+				divider(),
+				html.EscapeString(msg),
+				timestampLine(),
+			),
+		}
+
+		res := runTest(ctx, target, func(ctx context.Context) (err error) { return ch.send(ctx, ev) })
+
+		return []TestResult{res}, nil
+	}
+
+	return nil, fmt.Errorf("%s channel is not enabled", target)
+}
+
+// sendTelegramTest sends the test message msg to every Telegram chat.
+func (m *Manager) sendTelegramTest(ctx context.Context, msg string) (results []TestResult, err error) {
+	cfg := m.getTelegramConfig()
+	rcpts := telegramRecipients(cfg, &event{typ: eventTypeTest})
+	if cfg.BotToken == "" || len(rcpts) == 0 {
+		return nil, errors.New("telegram configuration incomplete")
+	}
+
+	text := fmt.Sprintf(
+		"🔔 <b>Telegram Test Notification</b>\n%s\n\n💬 <code>%s</code>\n\n%s",
+		divider(),
+		msg,
+		timestampLine(),
+	)
+
+	for _, rcpt := range rcpts {
+		results = append(results, runTest(ctx, rcpt.target, func(ctx context.Context) (err error) {
+			return m.sendTelegram(ctx, rcpt.cfg, text)
+		}))
+	}
+
+	return results, nil
+}
+
+// runTest calls send with the traced context and returns its diagnostics.
+func runTest(ctx context.Context, target string, send func(ctx context.Context) (err error)) (res TestResult) {
+	tr := &deliveryTrace{}
+	ctx = context.WithValue(ctx, deliveryTraceKey{}, tr)
+
+	start := time.Now()
+	err := send(ctx)
+
+	res = TestResult{
+		Target:  target,
+		Latency: time.Since(start),
+		Status:  tr.status,
+	}
+
+	if err != nil {
+		res.Error = err.Error()
+		res.ProviderError = providerError(tr.body)
+	}
+
+	return res
+}
+
+// providerError extracts the error description from the response body of the
+// API of a target.  The known JSON fields are preferred over the whole body.
+func providerError(body []byte) (msg string) {
+	var fields map[string]any
+	if json.Unmarshal(body, &fields) == nil {
+		// The fields used by Telegram, Gotify, Matrix and Signal, and PagerDuty
+		// and Opsgenie correspondingly.
+		for _, key := range []string{"description", "errorDescription", "error", "message"} {
+			if s, ok := fields[key].(string); ok && s != "" {
+				return s
+			}
+		}
+	}
+
+	msg = strings.TrimSpace(string(body))
+	if len(msg) > maxProviderErrorLen {
+		msg = strings.ToValidUTF8(msg[:maxProviderErrorLen], "") + "…"
+	}
+
+	return msg
+}
@@ -0,0 +1,94 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestProviderError(t *testing.T) {
+	testCases := []struct {
+		name string
+		body string
+		want string
+	}{{
+		name: "empty",
+		body: "",
+		want: "",
+	}, {
+		name: "telegram",
+		body: `{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked"}`,
+		want: "Forbidden: bot was blocked",
+	}, {
+		name: "gotify",
+		body: `{"error":"Unauthorized","errorCode":401,"errorDescription":"invalid token"}`,
+		want: "invalid token",
+	}, {
+		name: "opsgenie",
+		body: `{"message":"Key format is not valid!","took":0.001}`,
+		want: "Key format is not valid!",
+	}, {
+		name: "plain",
+		body: "  Bad Request\n",
+		want: "Bad Request",
+	}, {
+		name: "long",
+		body: strings.Repeat("x", maxProviderErrorLen+10),
+		want: strings.Repeat("x", maxProviderErrorLen) + "…",
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := providerError([]byte(tc.body)); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestManager_SendTest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"error":{"code":"Forbidden"},"message":"webhook disabled"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	m.channels = []channel{newTeamsChannel(srv.Client(), TeamsConfig{
+		Enabled:    true,
+		WebhookURL: srv.URL,
+	})}
+
+	ctx := context.Background()
+
+	results, err := m.SendTest(ctx, "teams", "")
+	if err != nil {
+		t.Fatalf("sending test: %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+
+	res := results[0]
+	if res.Target != "teams" || res.Status != http.StatusForbidden || res.Error == "" {
+		t.Errorf("got result %+v, want failed teams delivery with status 403", res)
+	}
+
+	if res.ProviderError != "webhook disabled" {
+		t.Errorf("got provider error %q, want %q", res.ProviderError, "webhook disabled")
+	}
+
+	_, err = m.SendTest(ctx, "matrix", "")
+	if err == nil {
+		t.Error("got no error for disabled channel")
+	}
+
+	_, err = m.SendTest(ctx, telegramTarget, "")
+	if err == nil {
+		t.Error("got no error for incomplete telegram configuration")
+	}
+}
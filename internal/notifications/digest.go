@@ -0,0 +1,120 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// eventTypeDigest is the type of the periodic digest of the batched events.
+const eventTypeDigest eventType = "digest"
+
+// Default digest settings.
+const (
+	defaultDigestInterval = time.Hour
+
+	// maxDigestLen is the maximum number of events in a digest.  The oldest
+	// events are dropped once it's reached.
+	maxDigestLen = 100
+)
+
+// DigestConfig contains runtime configuration for the digest mode.  The
+// events matching Events, which uses the keys of the routing rules, are
+// batched and delivered as a single message every Interval.
+type DigestConfig struct {
+	Events   []string
+	Interval time.Duration
+	Enabled  bool
+}
+
+// defaultDigestEvents are the batched events used if [DigestConfig.Events] is
+// nil.
+var defaultDigestEvents = []string{string(eventTypeFilterUpdate)}
+
+// isBatched returns true if ev should be delivered in the digest.
+func (conf *DigestConfig) isBatched(ev *event) (ok bool) {
+	if !conf.Enabled {
+		return false
+	}
+
+	events := conf.Events
+	if events == nil {
+		events = defaultDigestEvents
+	}
+
+	for _, key := range routeKeys(ev.typ, ev.metric) {
+		if slices.Contains(events, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// UpdateDigestConfig applies the new digest configuration.  The batched
+// events are kept until the next digest.
+func (m *Manager) UpdateDigestConfig(conf DigestConfig) {
+	if conf.Interval <= 0 {
+		conf.Interval = defaultDigestInterval
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.digest = conf
+}
+
+// holdForDigest returns true if ev is batched into the digest.
+func (m *Manager) holdForDigest(ev *event) (held bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.digest.isBatched(ev) {
+		return false
+	}
+
+	if len(m.digestQueue) == 0 {
+		m.digestStart = time.Now()
+	}
+
+	if len(m.digestQueue) >= maxDigestLen {
+		m.digestQueue = m.digestQueue[1:]
+		m.digestDropped++
+	}
+
+	m.digestQueue = append(m.digestQueue, ev)
+
+	return true
+}
+
+// flushDigest delivers the digest of the batched events once the interval
+// since the first of them has passed.  The digest is postponed during the
+// quiet hours.
+func (m *Manager) flushDigest(ctx context.Context, cfg TelegramConfig) {
+	now := time.Now()
+
+	m.mu.Lock()
+	if len(m.digestQueue) == 0 ||
+		now.Sub(m.digestStart) < m.digest.Interval ||
+		m.quietHours.isQuiet(now) {
+		m.mu.Unlock()
+
+		return
+	}
+
+	batched, dropped := m.digestQueue, m.digestDropped
+	m.digestQueue, m.digestDropped = nil, 0
+	m.mu.Unlock()
+
+	ev := &event{
+		time: now,
+		typ:  eventTypeDigest,
+		text: composeEventSummaryMessage("📬", "Notification Digest", batched, dropped),
+	}
+
+	err := m.deliver(ctx, cfg, ev)
+	if err != nil {
+		m.logger.Error("notification digest failed", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,55 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestManager_flushDigest(t *testing.T) {
+	ch := &recordingChannel{chanName: "matrix"}
+
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	m.channels = []channel{ch}
+	m.UpdateDigestConfig(DigestConfig{Enabled: true, Interval: time.Hour})
+
+	ctx := context.Background()
+	for _, ev := range []*event{
+		{time: time.Now(), typ: eventTypeFilterUpdate, filter: &FilterUpdate{Name: "List 1"}},
+		{time: time.Now(), typ: eventTypeFilterUpdate, filter: &FilterUpdate{Name: "List 2"}},
+		{time: time.Now(), typ: eventTypeAlert, metric: "cpu"},
+	} {
+		if err := m.deliver(ctx, TelegramConfig{}, ev); err != nil {
+			t.Fatalf("delivering %s: %s", ev.typ, err)
+		}
+	}
+
+	m.flushDigest(ctx, TelegramConfig{})
+	if want := []eventType{eventTypeAlert}; !slices.Equal(ch.got, want) {
+		t.Fatalf("got %v before the interval, want %v", ch.got, want)
+	}
+
+	m.mu.Lock()
+	m.digestStart = time.Now().Add(-time.Hour)
+	m.mu.Unlock()
+
+	m.flushDigest(ctx, TelegramConfig{})
+	if want := []eventType{eventTypeAlert, eventTypeDigest}; !slices.Equal(ch.got, want) {
+		t.Fatalf("got %v after the interval, want %v", ch.got, want)
+	}
+}
+
+func TestComposeEventSummaryMessage(t *testing.T) {
+	msg := composeEventSummaryMessage("📬", "Notification Digest", []*event{
+		{typ: eventTypeFilterUpdate, filter: &FilterUpdate{Name: "<List>"}},
+	}, 2)
+
+	for _, want := range []string{"Events (3)", "Filter list updated: &lt;List&gt;", "2 older events omitted"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message %q doesn't contain %q", msg, want)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+)
+
+// diskHealthMetricPrefix is the prefix of the metrics of the SMART health
+// alerts, which is followed by the device, like "disk_health:/dev/sda".
+const diskHealthMetricPrefix = "disk_health:"
+
+// checkDiskHealth sends an alert about each degraded drive, which holds any of
+// the watched paths, like the query log, and a recovery message once it's
+// healthy again.  The alerts about the drives, which are no longer reported,
+// are cleared.
+func (m *Manager) checkDiskHealth(ctx context.Context, cfg TelegramConfig, info systeminfo.Info) {
+	for _, metric := range m.removedDiskHealthMetrics(info.DiskHealth) {
+		m.clearAlert(ctx, metric)
+	}
+
+	for _, h := range info.DiskHealth {
+		if len(h.Paths) == 0 {
+			continue
+		}
+
+		metric := diskHealthMetricPrefix + h.Device
+		active, _ := m.metricState(metric)
+		if !h.Degraded() {
+			if active {
+				m.clearAlertWithRecovery(ctx, cfg, metric, 0, 0, info)
+			}
+
+			continue
+		} else if active {
+			continue
+		}
+
+		msg := composeDiskHealthAlertMessage(cfg, m.getLocale(), h, info)
+		ev := &event{time: time.Now(), typ: eventTypeAlert, metric: metric, text: msg}
+		m.applyTemplate(ev, newTemplateData(cfg, ev, info))
+		if err := m.deliver(ctx, cfg, ev); err != nil {
+			m.logger.Error("disk health alert failed", "device", h.Device, slog.String("error", err.Error()))
+
+			continue
+		}
+
+		now := time.Now()
+		m.updateMetricState(metric, true, now)
+
+		m.mu.Lock()
+		m.alertStartTime[metric] = now
+		m.mu.Unlock()
+
+		m.trackAlert(ev)
+	}
+}
+
+// removedDiskHealthMetrics returns the metrics of the active alerts about the
+// drives, which aren't in health or don't hold any watched paths anymore.
+func (m *Manager) removedDiskHealthMetrics(health []systeminfo.DiskHealth) (metrics []string) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for metric, active := range m.alertActive {
+		dev, ok := strings.CutPrefix(metric, diskHealthMetricPrefix)
+		if !active || !ok {
+			continue
+		}
+
+		if !slices.ContainsFunc(health, func(h systeminfo.DiskHealth) (found bool) {
+			return h.Device == dev && len(h.Paths) > 0
+		}) {
+			metrics = append(metrics, metric)
+		}
+	}
+
+	return metrics
+}
+
+// composeDiskHealthAlertMessage formats an alert that the drive holding the
+// watched paths is degraded.
+func composeDiskHealthAlertMessage(
+	cfg TelegramConfig,
+	loc Locale,
+	h systeminfo.DiskHealth,
+	info systeminfo.Info,
+) (msg string) {
+	lines := make([]string, 0, 20)
+	if prefix := strings.TrimSpace(cfg.CustomMessage); prefix != "" {
+		lines = append(lines, prefix)
+		lines = append(lines, "")
+	}
+
+	title := loc.text("notification_disk_health_title", "ALERT: Drive {{device}} is degraded!", "device", h.Device)
+	lines = append(lines, fmt.Sprintf("🚨 <b>%s</b>", html.EscapeString(title)))
+	lines = append(lines, divider())
+	lines = append(lines, "")
+	lines = append(lines, fmt.Sprintf(
+		"  ▸ <b>%s:</b> <code>%s</code>",
+		loc.text("notification_label_model", "Model"),
+		html.EscapeString(fallbackString(h.Model)),
+	))
+
+	status := loc.text("notification_disk_health_passed", "Passed")
+	if !h.Passed {
+		status = "🔴 " + loc.text("notification_disk_health_failed", "Failed")
+	}
+	lines = append(lines, fmt.Sprintf(
+		"  ▸ <b>%s:</b> %s",
+		loc.text("notification_label_smart_status", "SMART Status"),
+		status,
+	))
+
+	if h.ReallocatedSectors >= 0 {
+		lines = append(lines, fmt.Sprintf(
+			"  ▸ <b>%s:</b> <code>%d</code>",
+			loc.text("notification_label_reallocated_sectors", "Reallocated Sectors"),
+			h.ReallocatedSectors,
+		))
+	}
+
+	if h.Temperature > 0 {
+		lines = append(lines, fmt.Sprintf(
+			"  ▸ <b>%s:</b> <code>%s</code>",
+			loc.text("notification_label_temperature", "Temperature"),
+			formatMetricValue(metricTemperature, h.Temperature),
+		))
+	}
+
+	paths := make([]string, 0, len(h.Paths))
+	for _, p := range h.Paths {
+		paths = append(paths, fmt.Sprintf("<code>%s</code>", html.EscapeString(p)))
+	}
+	lines = append(lines, fmt.Sprintf(
+		"  ▸ <b>%s:</b> %s",
+		loc.text("notification_label_holds", "Holds"),
+		strings.Join(paths, ", "),
+	))
+	lines = append(lines, "")
+	lines = append(lines, fmt.Sprintf(
+		"<i>%s</i>",
+		loc.text("notification_disk_health_hint", "Back up the data and replace the drive before it fails."),
+	))
+	lines = append(lines, "")
+
+	if info.Hostname != "" {
+		lines = append(lines, systemOverviewLines(loc, info)...)
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, divider())
+	lines = append(lines, loc.timestampLine())
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,66 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+)
+
+func TestManager_checkDiskHealth(t *testing.T) {
+	rec := &recordingChannel{chanName: "matrix"}
+
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	m.channels = []channel{rec}
+
+	degraded := systeminfo.DiskHealth{
+		Device:             "/dev/sda",
+		Paths:              []string{"/opt/AdGuardHome/data"},
+		ReallocatedSectors: 8,
+		Passed:             true,
+	}
+	unwatched := systeminfo.DiskHealth{Device: "/dev/sdb", ReallocatedSectors: -1}
+
+	ctx := context.Background()
+	info := systeminfo.Info{DiskHealth: []systeminfo.DiskHealth{degraded, unwatched}}
+	m.checkDiskHealth(ctx, TelegramConfig{}, info)
+	m.checkDiskHealth(ctx, TelegramConfig{}, info)
+
+	if active, _ := m.metricState(diskHealthMetricPrefix + "/dev/sdb"); active {
+		t.Error("alert about the drive without watched paths is active")
+	}
+
+	healthy := degraded
+	healthy.ReallocatedSectors = 0
+	m.checkDiskHealth(ctx, TelegramConfig{}, systeminfo.Info{DiskHealth: []systeminfo.DiskHealth{healthy}})
+
+	want := []eventType{eventTypeAlert, eventTypeRecovery}
+	if !slices.Equal(rec.got, want) {
+		t.Errorf("got events %q, want %q", rec.got, want)
+	}
+}
+
+func TestComposeDiskHealthAlertMessage(t *testing.T) {
+	h := systeminfo.DiskHealth{
+		Device:             "/dev/sda",
+		Model:              "WDC WD40EFRX",
+		Paths:              []string{"/opt/AdGuardHome/data"},
+		Temperature:        41,
+		ReallocatedSectors: -1,
+		Passed:             false,
+	}
+
+	msg := composeDiskHealthAlertMessage(TelegramConfig{}, nil, h, systeminfo.Info{})
+	for _, want := range []string{"/dev/sda", "WDC WD40EFRX", "Failed", "41 °C", "/opt/AdGuardHome/data"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message %q does not contain %q", msg, want)
+		}
+	}
+
+	if strings.Contains(msg, "Reallocated") {
+		t.Errorf("message %q contains the unknown reallocated sectors", msg)
+	}
+}
@@ -0,0 +1,85 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+)
+
+// diskPathMetricPrefix is the prefix of the metrics of the disk usage alerts
+// about the additional mount points, which is followed by the path, like
+// "disk:/data".
+const diskPathMetricPrefix = "disk:"
+
+// DiskPath is an additional mount point, which disk usage is monitored with
+// its own threshold.
+type DiskPath struct {
+	// Path is the absolute path within the mount point.
+	Path string
+
+	// Threshold is the disk usage in percent, which triggers the alert.
+	Threshold float64
+}
+
+// ValidateDiskPaths returns an error if the paths aren't absolute or unique or
+// the thresholds aren't greater than zero and not greater than 100.
+func ValidateDiskPaths(paths []DiskPath) (err error) {
+	for i, p := range paths {
+		if !filepath.IsAbs(p.Path) {
+			return fmt.Errorf("disk_paths: at index %d: path %q is not absolute", i, p.Path)
+		} else if p.Threshold <= 0 || p.Threshold > 100 {
+			return fmt.Errorf("disk_paths: at index %d: threshold must be between 0 and 100", i)
+		}
+
+		dup := slices.IndexFunc(paths[:i], func(o DiskPath) (ok bool) { return o.Path == p.Path })
+		if dup >= 0 {
+			return fmt.Errorf("disk_paths: at index %d: duplicate path %q", i, p.Path)
+		}
+	}
+
+	return nil
+}
+
+// checkDiskPaths handles the disk usage of each additional mount point from
+// cfg and clears the alerts about the ones removed from it.
+func (m *Manager) checkDiskPaths(ctx context.Context, cfg TelegramConfig, info systeminfo.Info) {
+	for _, metric := range m.removedDiskPathMetrics(cfg.DiskPaths) {
+		m.clearAlert(ctx, metric)
+	}
+
+	for _, p := range cfg.DiskPaths {
+		metric := diskPathMetricPrefix + p.Path
+		du, err := systeminfo.DiskUsage(ctx, p.Path)
+		if err != nil {
+			m.logger.Debug("getting disk usage", "path", p.Path, "error", err.Error())
+
+			continue
+		}
+
+		m.handleMetric(ctx, cfg, metric, du.UsagePercent, p.Threshold, info)
+	}
+}
+
+// removedDiskPathMetrics returns the metrics of the active alerts about the
+// mount points, which aren't in paths.
+func (m *Manager) removedDiskPathMetrics(paths []DiskPath) (metrics []string) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for metric, active := range m.alertActive {
+		path, ok := strings.CutPrefix(metric, diskPathMetricPrefix)
+		if !active || !ok {
+			continue
+		}
+
+		if !slices.ContainsFunc(paths, func(p DiskPath) (found bool) { return p.Path == path }) {
+			metrics = append(metrics, metric)
+		}
+	}
+
+	return metrics
+}
@@ -0,0 +1,81 @@
+package notifications
+
+import (
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidateDiskPaths(t *testing.T) {
+	root := t.TempDir()
+	data := filepath.Join(root, "data")
+
+	testCases := []struct {
+		name    string
+		in      []DiskPath
+		wantErr bool
+	}{{
+		name:    "empty",
+		in:      nil,
+		wantErr: false,
+	}, {
+		name:    "valid",
+		in:      []DiskPath{{Path: root, Threshold: 90}, {Path: data, Threshold: 80}},
+		wantErr: false,
+	}, {
+		name:    "relative",
+		in:      []DiskPath{{Path: "data", Threshold: 80}},
+		wantErr: true,
+	}, {
+		name:    "bad_threshold",
+		in:      []DiskPath{{Path: data, Threshold: 120}},
+		wantErr: true,
+	}, {
+		name:    "duplicate",
+		in:      []DiskPath{{Path: data, Threshold: 80}, {Path: data, Threshold: 90}},
+		wantErr: true,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateDiskPaths(tc.in)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("got error %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestManager_removedDiskPathMetrics(t *testing.T) {
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	m.updateMetricState("disk", true, m.startTime)
+	m.updateMetricState("disk:/data", true, m.startTime)
+	m.updateMetricState("disk:/backup", true, m.startTime)
+
+	got := m.removedDiskPathMetrics([]DiskPath{{Path: "/data", Threshold: 80}})
+	if want := []string{"disk:/backup"}; !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestTelegramConfig_resetThreshold_diskPath(t *testing.T) {
+	cfg := TelegramConfig{Hysteresis: map[string]float64{"disk": 0.5}}
+
+	if got := cfg.resetThreshold("disk:/data", 80); got != 40 {
+		t.Errorf("got reset threshold %v, want 40", got)
+	}
+}
+
+func TestAlertKeyboard_longMetric(t *testing.T) {
+	ev := &event{typ: eventTypeAlert, metric: diskPathMetricPrefix + "/" + strings.Repeat("a", 64)}
+
+	for _, row := range alertKeyboard(ev).InlineKeyboard {
+		for _, b := range row {
+			if len(b.CallbackData) > tgMaxCallbackDataLen {
+				t.Errorf("button %q: callback data is %d bytes long", b.Text, len(b.CallbackData))
+			}
+		}
+	}
+}
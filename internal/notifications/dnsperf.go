@@ -0,0 +1,163 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+)
+
+// The metrics of the DNS performance alerts.
+const (
+	metricDNSLatency  = "dns_latency"
+	metricDNSServFail = "dns_servfail"
+	metricDNSQPS      = "dns_qps"
+)
+
+// dnsPerfMinQueries is the minimum number of the queries during a check for the
+// SERVFAIL rate to be computed, so that a few failures of an idle server don't
+// trigger the alert.
+const dnsPerfMinQueries = 20
+
+// DNSPerfCounts are the DNS query counters since the previous call of
+// [DNSPerfProvider.TakeDNSPerfCounts].
+type DNSPerfCounts struct {
+	// Queries is the number of the processed queries.
+	Queries uint64
+
+	// ServFail is the number of the queries answered with SERVFAIL.
+	ServFail uint64
+
+	// UpstreamQueries is the number of the queries resolved by the upstream
+	// servers, excluding the cached ones and the errors.
+	UpstreamQueries uint64
+
+	// UpstreamTime is the total duration of the upstream queries.
+	UpstreamTime time.Duration
+}
+
+// DNSPerfProvider returns the DNS query counters for the performance alerts.
+type DNSPerfProvider interface {
+	// TakeDNSPerfCounts returns the counters since the previous call.
+	TakeDNSPerfCounts() (c DNSPerfCounts)
+}
+
+// DNSPerfConfig contains runtime configuration for the DNS performance alerts.
+// A zero limit means that the corresponding metric isn't monitored.
+type DNSPerfConfig struct {
+	// MaxLatency is the average duration of the upstream queries.
+	MaxLatency time.Duration
+
+	// MaxServFailRate is the share of the queries answered with SERVFAIL in
+	// percent.
+	MaxServFailRate float64
+
+	// MaxQPS is the number of the queries per second.
+	MaxQPS float64
+
+	Enabled bool
+}
+
+// ValidateDNSPerfConfig returns an error if the enabled DNS performance alerts
+// configuration is invalid.
+func ValidateDNSPerfConfig(conf DNSPerfConfig) (err error) {
+	if !conf.Enabled {
+		return nil
+	}
+
+	if conf.MaxLatency < 0 {
+		return fmt.Errorf("max_latency: must not be negative, got %s", conf.MaxLatency)
+	} else if conf.MaxServFailRate < 0 || conf.MaxServFailRate > 100 {
+		return fmt.Errorf("max_servfail_rate: must be between 0 and 100, got %v", conf.MaxServFailRate)
+	} else if conf.MaxQPS < 0 || math.IsInf(conf.MaxQPS, 0) {
+		return fmt.Errorf("max_qps: must not be negative, got %v", conf.MaxQPS)
+	}
+
+	if conf == (DNSPerfConfig{Enabled: true}) {
+		return fmt.Errorf("at least one limit is required")
+	}
+
+	return nil
+}
+
+// SetDNSPerfProvider injects the provider of the DNS query counters.
+func (m *Manager) SetDNSPerfProvider(dp DNSPerfProvider) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.dnsPerfCounts = dp
+}
+
+// UpdateDNSPerfConfig applies the new DNS performance alerts configuration.
+func (m *Manager) UpdateDNSPerfConfig(conf DNSPerfConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.dnsPerf = conf
+}
+
+// dnsPerf are the DNS performance metrics computed from [DNSPerfCounts].
+type dnsPerf struct {
+	// latencyMs is the average upstream latency in milliseconds.
+	latencyMs float64
+
+	// servFailRate is the share of the SERVFAIL responses in percent.
+	servFailRate float64
+
+	// qps is the number of the queries per second.
+	qps float64
+}
+
+// newDNSPerf computes the metrics from the counters collected during elapsed.
+func newDNSPerf(c DNSPerfCounts, elapsed time.Duration) (p dnsPerf) {
+	if c.UpstreamQueries > 0 {
+		p.latencyMs = durationMillis(c.UpstreamTime / time.Duration(c.UpstreamQueries))
+	}
+
+	if c.Queries >= dnsPerfMinQueries {
+		p.servFailRate = float64(c.ServFail) / float64(c.Queries) * 100
+	}
+
+	if elapsed > 0 {
+		p.qps = float64(c.Queries) / elapsed.Seconds()
+	}
+
+	return p
+}
+
+// checkDNSPerf takes the DNS query counters and handles the DNS performance
+// metrics.  The counters taken on the first check are only used as the
+// starting point.
+func (m *Manager) checkDNSPerf(ctx context.Context, cfg TelegramConfig, info systeminfo.Info) {
+	m.mu.Lock()
+	dp := m.dnsPerfCounts
+	conf := m.dnsPerf
+	m.mu.Unlock()
+
+	if dp == nil {
+		return
+	}
+
+	now := time.Now()
+	counts := dp.TakeDNSPerfCounts()
+
+	m.mu.Lock()
+	last := m.dnsPerfLast
+	m.dnsPerfLast = now
+	m.mu.Unlock()
+
+	if last.IsZero() {
+		return
+	}
+
+	if !conf.Enabled {
+		conf = DNSPerfConfig{}
+	}
+
+	p := newDNSPerf(counts, now.Sub(last))
+	m.handleMetric(ctx, cfg, metricDNSLatency, p.latencyMs, durationMillis(conf.MaxLatency), info)
+	m.handleMetric(ctx, cfg, metricDNSServFail, p.servFailRate, conf.MaxServFailRate, info)
+	m.handleMetric(ctx, cfg, metricDNSQPS, p.qps, conf.MaxQPS, info)
+}
@@ -0,0 +1,147 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+)
+
+// testDNSPerfProvider is a [DNSPerfProvider] for tests.
+type testDNSPerfProvider struct {
+	counts DNSPerfCounts
+}
+
+// TakeDNSPerfCounts implements the [DNSPerfProvider] interface for
+// *testDNSPerfProvider.
+func (p *testDNSPerfProvider) TakeDNSPerfCounts() (c DNSPerfCounts) {
+	return p.counts
+}
+
+func TestManager_checkDNSPerf(t *testing.T) {
+	rec := &recordingChannel{chanName: "matrix"}
+
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	m.channels = []channel{rec}
+	m.SetDNSPerfProvider(&testDNSPerfProvider{
+		counts: DNSPerfCounts{
+			Queries:         100,
+			ServFail:        10,
+			UpstreamQueries: 50,
+			UpstreamTime:    50 * time.Second,
+		},
+	})
+	m.UpdateDNSPerfConfig(DNSPerfConfig{
+		MaxLatency:      500 * time.Millisecond,
+		MaxServFailRate: 5,
+		Enabled:         true,
+	})
+
+	ctx := context.Background()
+
+	// The first check only starts the measurement.
+	m.checkDNSPerf(ctx, TelegramConfig{}, systeminfo.Info{})
+	if len(rec.got) != 0 {
+		t.Fatalf("got events %q on the first check", rec.got)
+	}
+
+	m.checkDNSPerf(ctx, TelegramConfig{}, systeminfo.Info{})
+	for _, metric := range []string{metricDNSLatency, metricDNSServFail} {
+		if active, _ := m.metricState(metric); !active {
+			t.Errorf("%s alert is not active", metric)
+		}
+	}
+
+	if active, _ := m.metricState(metricDNSQPS); active {
+		t.Errorf("%s alert is active with no limit", metricDNSQPS)
+	}
+
+	// Disabling resolves the alerts.
+	m.UpdateDNSPerfConfig(DNSPerfConfig{})
+	m.checkDNSPerf(ctx, TelegramConfig{}, systeminfo.Info{})
+
+	want := []eventType{eventTypeAlert, eventTypeAlert, eventTypeRecovery, eventTypeRecovery}
+	if !slices.Equal(rec.got, want) {
+		t.Errorf("got events %q, want %q", rec.got, want)
+	}
+}
+
+func TestNewDNSPerf(t *testing.T) {
+	testCases := []struct {
+		name    string
+		in      DNSPerfCounts
+		elapsed time.Duration
+		want    dnsPerf
+	}{{
+		name:    "empty",
+		in:      DNSPerfCounts{},
+		elapsed: time.Minute,
+		want:    dnsPerf{},
+	}, {
+		name: "few_queries",
+		in: DNSPerfCounts{
+			Queries:         10,
+			ServFail:        10,
+			UpstreamQueries: 2,
+			UpstreamTime:    100 * time.Millisecond,
+		},
+		elapsed: 10 * time.Second,
+		want:    dnsPerf{latencyMs: 50, qps: 1},
+	}, {
+		name: "many_queries",
+		in: DNSPerfCounts{
+			Queries:  600,
+			ServFail: 30,
+		},
+		elapsed: time.Minute,
+		want:    dnsPerf{servFailRate: 5, qps: 10},
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := newDNSPerf(tc.in, tc.elapsed); got != tc.want {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidateDNSPerfConfig(t *testing.T) {
+	testCases := []struct {
+		name    string
+		in      DNSPerfConfig
+		wantErr bool
+	}{{
+		name:    "disabled",
+		in:      DNSPerfConfig{},
+		wantErr: false,
+	}, {
+		name:    "valid",
+		in:      DNSPerfConfig{MaxQPS: 1000, Enabled: true},
+		wantErr: false,
+	}, {
+		name:    "no_limits",
+		in:      DNSPerfConfig{Enabled: true},
+		wantErr: true,
+	}, {
+		name:    "bad_rate",
+		in:      DNSPerfConfig{MaxServFailRate: 120, Enabled: true},
+		wantErr: true,
+	}, {
+		name:    "negative",
+		in:      DNSPerfConfig{MaxLatency: -time.Second, Enabled: true},
+		wantErr: true,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateDNSPerfConfig(tc.in)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("got error %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
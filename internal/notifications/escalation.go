@@ -0,0 +1,193 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// EscalationConfig contains runtime configuration for the re-notification and
+// escalation of the unresolved alerts.  An alert still active after Repeat is
+// delivered again, once per Repeat, to its usual targets.  An alert still
+// active after After is additionally delivered once to Targets.  Zero Repeat
+// or After disables the corresponding step.
+type EscalationConfig struct {
+	Targets []string
+	Repeat  time.Duration
+	After   time.Duration
+	Enabled bool
+}
+
+// ValidateEscalationConfig returns an error if conf is invalid.
+func ValidateEscalationConfig(conf EscalationConfig) (err error) {
+	if conf.Repeat < 0 {
+		return fmt.Errorf("repeat must not be negative")
+	} else if conf.After < 0 {
+		return fmt.Errorf("after must not be negative")
+	}
+
+	for _, t := range conf.Targets {
+		if !slices.Contains(TargetNames, t) {
+			return fmt.Errorf("targets: unknown target %q", t)
+		}
+	}
+
+	if conf.After > 0 && len(conf.Targets) == 0 {
+		return fmt.Errorf("targets are required to escalate alerts")
+	}
+
+	return nil
+}
+
+// activeAlert is the state of an unresolved alert.
+type activeAlert struct {
+	// ev is the originally delivered alert.
+	ev *event
+
+	// lastNotified is the time of the last delivery of the alert.
+	lastNotified time.Time
+
+	// snoozedUntil is the time until which the alert is snoozed.
+	snoozedUntil time.Time
+
+	// escalated is true if the alert has been escalated.
+	escalated bool
+
+	// acknowledged is true if the alert has been acknowledged.
+	acknowledged bool
+}
+
+// UpdateEscalationConfig applies the new escalation configuration.
+func (m *Manager) UpdateEscalationConfig(conf EscalationConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.escalation = conf
+}
+
+// trackAlert remembers the delivered alert ev for re-notification and
+// escalation.
+func (m *Manager) trackAlert(ev *event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.activeAlerts[ev.metric] = &activeAlert{
+		ev:           ev,
+		lastNotified: ev.time,
+	}
+}
+
+// untrackAlert forgets the alert for metric and returns the escalation
+// targets it has been delivered to, if any.
+func (m *Manager) untrackAlert(metric string) (escalatedTo []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	a, ok := m.activeAlerts[metric]
+	delete(m.activeAlerts, metric)
+	if ok && a.escalated {
+		return slices.Clone(m.escalation.Targets)
+	}
+
+	return nil
+}
+
+// checkEscalations re-notifies about and escalates the unresolved alerts
+// according to the escalation configuration.  The acknowledged alerts are
+// skipped, and the snoozed ones are delivered again once the snooze is over.
+func (m *Manager) checkEscalations(ctx context.Context, cfg TelegramConfig) {
+	now := time.Now()
+
+	var evs []*event
+	func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		conf := m.escalation
+		for _, a := range m.activeAlerts {
+			unresolved := now.Sub(a.ev.time)
+			if a.acknowledged || now.Before(a.snoozedUntil) {
+				continue
+			} else if !a.snoozedUntil.IsZero() {
+				a.snoozedUntil = time.Time{}
+				a.lastNotified = now
+				evs = append(evs, repeatedAlert(a.ev, unresolved))
+
+				continue
+			} else if !conf.Enabled {
+				continue
+			}
+
+			if conf.After > 0 && !a.escalated && unresolved >= conf.After {
+				a.escalated = true
+				evs = append(evs, escalatedAlert(a.ev, unresolved, conf.Targets))
+			} else if conf.Repeat > 0 && now.Sub(a.lastNotified) >= conf.Repeat {
+				a.lastNotified = now
+				evs = append(evs, repeatedAlert(a.ev, unresolved))
+			}
+		}
+	}()
+
+	for _, ev := range evs {
+		err := m.deliver(ctx, cfg, ev)
+		if err != nil {
+			m.logger.Error("repeating alert failed", "metric", ev.metric, slog.String("error", err.Error()))
+		}
+	}
+}
+
+// repeatedAlert returns the copy of the alert ev for the re-notification of
+// the alert unresolved for d.
+func repeatedAlert(ev *event, d time.Duration) (r *event) {
+	r = &event{}
+	*r = *ev
+	r.time = time.Now()
+	r.text = fmt.Sprintf(
+		"🔁 <b>Still unresolved</b> for <code>%s</code>\n\n%s",
+		d.Truncate(time.Second),
+		ev.text,
+	)
+
+	return r
+}
+
+// escalatedAlert returns the copy of the alert ev for the escalation of the
+// alert unresolved for d to targets.
+func escalatedAlert(ev *event, d time.Duration, targets []string) (r *event) {
+	r = &event{}
+	*r = *ev
+	r.time = time.Now()
+	r.targets = slices.Clone(targets)
+	r.text = fmt.Sprintf(
+		"🚨 <b>Escalated</b>: unresolved for <code>%s</code>\n\n%s",
+		d.Truncate(time.Second),
+		ev.text,
+	)
+
+	return r
+}
+
+// withEscalationTargets returns the targets of the recovery ev extended with
+// escalatedTo, so that the targets of the escalation learn about the
+// recovery.  It returns nil if the recovery goes to all targets anyway.
+func (m *Manager) withEscalationTargets(ev *event, escalatedTo []string) (targets []string) {
+	if len(escalatedTo) == 0 {
+		return nil
+	}
+
+	routed, ok := m.routeTargets(ev.typ, ev.metric)
+	if !ok {
+		return nil
+	}
+
+	targets = slices.Clone(routed)
+	for _, t := range escalatedTo {
+		if !slices.Contains(targets, t) {
+			targets = append(targets, t)
+		}
+	}
+
+	return targets
+}
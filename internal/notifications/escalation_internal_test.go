@@ -0,0 +1,146 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestManager_checkEscalations(t *testing.T) {
+	matrix := &recordingChannel{chanName: "matrix"}
+	pagerDuty := &recordingChannel{chanName: "pagerduty"}
+
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	m.channels = []channel{matrix, pagerDuty}
+
+	err := m.UpdateRoutes(map[string][]string{"alert": {"matrix"}})
+	if err != nil {
+		t.Fatalf("updating routes: %s", err)
+	}
+
+	m.UpdateEscalationConfig(EscalationConfig{
+		Targets: []string{"pagerduty"},
+		Repeat:  10 * time.Minute,
+		After:   30 * time.Minute,
+		Enabled: true,
+	})
+
+	ctx := context.Background()
+	cfg := TelegramConfig{}
+	started := time.Now()
+	m.trackAlert(&event{time: started, typ: eventTypeAlert, metric: "disk"})
+
+	// Not due yet.
+	m.checkEscalations(ctx, cfg)
+
+	// Due for the re-notification.
+	m.activeAlerts["disk"].lastNotified = started.Add(-15 * time.Minute)
+	m.checkEscalations(ctx, cfg)
+
+	// Due for the escalation.
+	m.activeAlerts["disk"].ev.time = started.Add(-time.Hour)
+	m.checkEscalations(ctx, cfg)
+
+	wantMatrix := []eventType{eventTypeAlert}
+	if !slices.Equal(matrix.got, wantMatrix) {
+		t.Errorf("matrix got %q, want %q", matrix.got, wantMatrix)
+	}
+
+	wantPagerDuty := []eventType{eventTypeAlert}
+	if !slices.Equal(pagerDuty.got, wantPagerDuty) {
+		t.Errorf("pagerduty got %q, want %q", pagerDuty.got, wantPagerDuty)
+	}
+
+	escalatedTo := m.untrackAlert("disk")
+	if !slices.Equal(escalatedTo, []string{"pagerduty"}) {
+		t.Errorf("escalated to %q, want pagerduty", escalatedTo)
+	}
+
+	targets := m.withEscalationTargets(&event{typ: eventTypeRecovery, metric: "disk"}, escalatedTo)
+	if want := []string{"matrix", "pagerduty"}; !slices.Equal(targets, want) {
+		t.Errorf("recovery targets %q, want %q", targets, want)
+	}
+}
+
+func TestValidateEscalationConfig(t *testing.T) {
+	testCases := []struct {
+		name    string
+		conf    EscalationConfig
+		wantErr bool
+	}{{
+		name:    "valid",
+		conf:    EscalationConfig{Targets: []string{"telegram"}, After: time.Hour},
+		wantErr: false,
+	}, {
+		name:    "repeat_only",
+		conf:    EscalationConfig{Repeat: time.Hour},
+		wantErr: false,
+	}, {
+		name:    "no_targets",
+		conf:    EscalationConfig{After: time.Hour},
+		wantErr: true,
+	}, {
+		name:    "unknown_target",
+		conf:    EscalationConfig{Targets: []string{"email"}, After: time.Hour},
+		wantErr: true,
+	}, {
+		name:    "negative",
+		conf:    EscalationConfig{Repeat: -time.Second},
+		wantErr: true,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateEscalationConfig(tc.conf)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("ValidateEscalationConfig() error = %v, want error %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestManager_checkEscalations_ackSnooze(t *testing.T) {
+	rec := &recordingChannel{chanName: "matrix"}
+
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	m.channels = []channel{rec}
+	m.UpdateEscalationConfig(EscalationConfig{
+		Repeat:  10 * time.Minute,
+		Enabled: true,
+	})
+
+	ctx := context.Background()
+	cfg := TelegramConfig{}
+	past := time.Now().Add(-time.Hour)
+	m.trackAlert(&event{time: past, typ: eventTypeAlert, metric: "cpu"})
+	m.trackAlert(&event{time: past, typ: eventTypeAlert, metric: "disk"})
+
+	if err := m.AcknowledgeAlert("cpu"); err != nil {
+		t.Fatalf("acknowledging: %s", err)
+	} else if err = m.SnoozeAlert("disk", time.Hour); err != nil {
+		t.Fatalf("snoozing: %s", err)
+	} else if err = m.SnoozeAlert("memory", time.Hour); !errors.Is(err, ErrAlertNotFound) {
+		t.Errorf("snoozing unknown alert: got %v, want %v", err, ErrAlertNotFound)
+	}
+
+	m.checkEscalations(ctx, cfg)
+	if len(rec.got) != 0 {
+		t.Fatalf("unexpected events %q", rec.got)
+	}
+
+	alerts := m.ActiveAlerts()
+	if len(alerts) != 2 || !alerts[0].Acknowledged || alerts[1].SnoozedUntil == nil {
+		t.Fatalf("unexpected alerts %+v", alerts)
+	}
+
+	// The snooze is over.
+	m.activeAlerts["disk"].snoozedUntil = time.Now().Add(-time.Second)
+	m.checkEscalations(ctx, cfg)
+
+	if want := []eventType{eventTypeAlert}; !slices.Equal(rec.got, want) {
+		t.Errorf("got %q, want %q", rec.got, want)
+	}
+}
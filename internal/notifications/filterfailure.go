@@ -0,0 +1,170 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// eventTypeFilterUpdateFailure is the type of the failed filter list refresh
+// events.
+const eventTypeFilterUpdateFailure eventType = "filter_update_failure"
+
+// FilterUpdateFailure describes a failed refresh of a filter list.
+type FilterUpdateFailure struct {
+	// LastSuccess is the time of the last successful update of the list.  It's
+	// zero if the list has never been updated successfully.
+	LastSuccess time.Time
+
+	// Name is the name of the list.
+	Name string
+
+	// URL is the source of the list.
+	URL string
+
+	// Reason is the kind of the failure, like "http", "parse", or "empty".
+	Reason string
+
+	// Error is the text of the error.
+	Error string
+
+	// ListType is the type of the list.
+	ListType FilterListType
+
+	// ID is the ID of the list.
+	ID uint64
+}
+
+// NotifyFilterUpdateFailure sends the notification about the failed refresh of
+// a filter list.
+func (m *Manager) NotifyFilterUpdateFailure(ctx context.Context, failure FilterUpdateFailure) {
+	cfg := m.getTelegramConfig()
+	if !m.isReady(cfg) {
+		return
+	}
+
+	ev := &event{
+		time:   time.Now(),
+		typ:    eventTypeFilterUpdateFailure,
+		metric: failure.Reason,
+		text:   composeFilterUpdateFailureMessage(cfg, m.getLocale(), failure, time.Now()),
+	}
+
+	if err := m.deliver(ctx, cfg, ev); err != nil {
+		m.logger.Error("filter update failure notification failed",
+			"list_type", string(failure.ListType),
+			"name", failure.Name,
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// filterFailureLabel returns the human-readable description of the filter list
+// update failure reason.
+func filterFailureLabel(reason string) (label string) {
+	switch reason {
+	case "http":
+		return "Download failed"
+	case "read":
+		return "Reading failed"
+	case "parse":
+		return "Parsing failed"
+	case "empty":
+		return "List is empty"
+	default:
+		return "Update failed"
+	}
+}
+
+// filterFailureText returns the translated description of the filter list
+// update failure reason.
+func (loc Locale) filterFailureText(reason string) (label string) {
+	switch reason {
+	case "http", "read", "parse", "empty":
+		return loc.text("notification_filter_failure_"+reason, filterFailureLabel(reason))
+	default:
+		return loc.text("notification_filter_failure_other", filterFailureLabel(reason))
+	}
+}
+
+// composeFilterUpdateFailureMessage formats the failed filter list refresh
+// notification.  now is used to show the time since the last success.
+func composeFilterUpdateFailureMessage(
+	cfg TelegramConfig,
+	loc Locale,
+	failure FilterUpdateFailure,
+	now time.Time,
+) (msg string) {
+	lines := make([]string, 0, 16)
+	if prefix := strings.TrimSpace(cfg.CustomMessage); prefix != "" {
+		lines = append(lines, prefix, "")
+	}
+
+	title := loc.text(
+		"notification_filter_update_failed_title",
+		"{{type}} Update Failed",
+		"type", loc.filterTypeLabel(failure.ListType),
+	)
+
+	lines = append(lines,
+		fmt.Sprintf("❌ <b>%s</b>", title),
+		divider(),
+		"",
+		fmt.Sprintf(
+			"  ▸ <b>%s:</b>   %s",
+			loc.text("notification_label_name", "Name"),
+			fallbackString(html.EscapeString(failure.Name)),
+		),
+	)
+
+	if failure.ID != 0 {
+		lines = append(lines, fmt.Sprintf(
+			"  ▸ <b>%s:</b>     <code>#%s</code>",
+			loc.text("notification_label_id", "ID"),
+			formatUint64(failure.ID),
+		))
+	}
+
+	if failure.URL != "" {
+		lines = append(lines, fmt.Sprintf(
+			"  ▸ <b>%s:</b> %s",
+			loc.text("notification_label_source", "Source"),
+			linkOrCode(failure.URL),
+		))
+	}
+
+	lines = append(lines, fmt.Sprintf(
+		"  ▸ <b>%s:</b> %s",
+		loc.text("notification_label_reason", "Reason"),
+		loc.filterFailureText(failure.Reason),
+	))
+	if failure.Error != "" {
+		lines = append(lines, fmt.Sprintf(
+			"  ▸ <b>%s:</b>  <code>%s</code>",
+			loc.text("notification_label_error", "Error"),
+			html.EscapeString(failure.Error),
+		))
+	}
+
+	lastSuccess := loc.text("notification_last_success_never", "never")
+	if !failure.LastSuccess.IsZero() {
+		lastSuccess = loc.text(
+			"notification_last_success_ago",
+			"{{time}} ({{duration}} ago)",
+			"time", "<code>"+toLocal(failure.LastSuccess).Format(time.DateTime)+"</code>",
+			"duration", now.Sub(failure.LastSuccess).Truncate(time.Minute).String(),
+		)
+	}
+
+	lines = append(lines,
+		fmt.Sprintf("  ▸ <b>%s:</b> %s", loc.text("notification_label_last_success", "Last Success"), lastSuccess),
+		"",
+		divider(),
+		loc.timestampLine(),
+	)
+
+	return strings.Join(lines, "\n")
+}
@@ -2,6 +2,7 @@ package notifications
 
 import (
 	"fmt"
+	"html"
 	"math"
 	"os"
 	"strconv"
@@ -43,30 +44,90 @@ func usageBar(pct float64) string {
 	return fmt.Sprintf("%s <code>%s</code>", bar, formatPercentage(pct))
 }
 
-func systemOverviewLines(info systeminfo.Info) []string {
-	lines := []string{sectionHeader("🖥️", "System Overview")}
-	lines = append(lines, fmt.Sprintf("  🏷️ <b>Host:</b> <code>%s</code>", fallbackString(info.Hostname)))
-	lines = append(lines, fmt.Sprintf("  🐧 <b>OS:</b> %s", formatOS(info)))
+// systemOverviewLines returns the system overview section translated
+// according to loc.
+func systemOverviewLines(loc Locale, info systeminfo.Info) []string {
+	lines := []string{sectionHeader("🖥️", loc.text("notification_section_system_overview", "System Overview"))}
+	lines = append(lines, fmt.Sprintf("  🏷️ <b>%s:</b> <code>%s</code>", loc.text("notification_label_host", "Host"), fallbackString(info.Hostname)))
+	lines = append(lines, fmt.Sprintf("  🐧 <b>%s:</b> %s", loc.text("notification_label_os", "OS"), formatOS(info)))
 	if info.KernelVersion != "" {
-		lines = append(lines, fmt.Sprintf("  🔧 <b>Kernel:</b> <code>%s</code>", info.KernelVersion))
-	}
-	lines = append(lines, fmt.Sprintf("  ⚙️ <b>CPU:</b> %s", formatCPU(info)))
-	lines = append(lines, fmt.Sprintf("  📊 <b>CPU Usage:</b> %s", usageBar(info.CPUUsage)))
-	lines = append(lines, fmt.Sprintf("  💾 <b>Memory:</b> %s", formatUsageWithBar(info.MemoryUsed, info.MemoryTotal, info.MemoryUsage)))
-	lines = append(lines, fmt.Sprintf("  💿 <b>Disk:</b> %s", formatUsageWithBar(info.DiskUsed, info.DiskTotal, info.DiskUsage)))
-	lines = append(lines, fmt.Sprintf("  📁 <b>Disk Path:</b> <code>%s</code>", fallbackString(info.DiskPath)))
-	lines = append(lines, fmt.Sprintf("  🌐 <b>Local IPs:</b> %s", formatLocalIPs(info.LocalIPs)))
-	lines = append(lines, fmt.Sprintf("  🌍 <b>Public IP:</b> <code>%s</code>", fallbackString(info.PublicIP)))
+		lines = append(lines, fmt.Sprintf("  🔧 <b>%s:</b> <code>%s</code>", loc.text("notification_label_kernel", "Kernel"), info.KernelVersion))
+	}
+	lines = append(lines, fmt.Sprintf("  ⚙️ <b>%s:</b> %s", loc.text("notification_label_cpu", "CPU"), formatCPU(info)))
+	lines = append(lines, fmt.Sprintf("  📊 <b>%s:</b> %s", loc.metricName("cpu"), usageBar(info.CPUUsage)))
+	if info.CPUTemp > 0 {
+		lines = append(lines, fmt.Sprintf(
+			"  🌡️ <b>%s:</b> %s",
+			loc.metricName(metricTemperature),
+			metricBar(metricTemperature, info.CPUTemp),
+		))
+	}
+	lines = append(lines, fmt.Sprintf(
+		"  💾 <b>%s:</b> %s",
+		loc.text("notification_label_memory", "Memory"),
+		formatUsageWithBar(info.MemoryUsed, info.MemoryTotal, info.MemoryUsage),
+	))
+	if info.SwapTotal > 0 {
+		lines = append(lines, fmt.Sprintf(
+			"  🔄 <b>%s:</b> %s",
+			loc.text("notification_label_swap", "Swap"),
+			formatUsageWithBar(info.SwapUsed, info.SwapTotal, info.SwapUsage),
+		))
+	}
+	lines = append(lines, fmt.Sprintf(
+		"  💿 <b>%s:</b> %s",
+		loc.text("notification_label_disk", "Disk"),
+		formatUsageWithBar(info.DiskUsed, info.DiskTotal, info.DiskUsage),
+	))
+	lines = append(lines, fmt.Sprintf("  📁 <b>%s:</b> <code>%s</code>", loc.text("notification_label_disk_path", "Disk Path"), fallbackString(info.DiskPath)))
+	lines = append(lines, fmt.Sprintf("  🌐 <b>%s:</b> %s", loc.text("notification_label_local_ips", "Local IPs"), formatLocalIPs(info.LocalIPs)))
+	lines = append(lines, fmt.Sprintf("  🌍 <b>%s:</b> <code>%s</code>", loc.text("notification_label_public_ip", "Public IP"), fallbackString(info.PublicIP)))
 	if info.SystemTime != "" {
 		if t, err := time.Parse(time.RFC3339, info.SystemTime); err == nil {
-			lines = append(lines, fmt.Sprintf("  🕐 <b>Time:</b> <code>%s</code>", toLocal(t).Format("15:04:05 02/01/2006")))
+			lines = append(lines, fmt.Sprintf(
+				"  🕐 <b>%s:</b> <code>%s</code>",
+				loc.text("notification_label_time", "Time"),
+				toLocal(t).Format("15:04:05 02/01/2006"),
+			))
 		}
 	}
 	uptime := formatUptime(info.UptimeSeconds)
 	if uptime == "" {
 		uptime = "-"
 	}
-	lines = append(lines, fmt.Sprintf("  ⏱️ <b>Uptime:</b> %s", uptime))
+	lines = append(lines, fmt.Sprintf("  ⏱️ <b>%s:</b> %s", loc.text("notification_label_uptime", "Uptime"), uptime))
+
+	return lines
+}
+
+// topProcessLines returns the section listing the processes with the highest
+// usage of the resource of metric, if any.  It's empty for the other metrics
+// and when the top processes aren't collected.
+func topProcessLines(loc Locale, metric string, info systeminfo.Info) (lines []string) {
+	var procs []systeminfo.ProcessUsage
+	var usage func(p systeminfo.ProcessUsage) (s string)
+	switch metric {
+	case "cpu":
+		procs = info.TopCPUProcesses
+		usage = func(p systeminfo.ProcessUsage) (s string) { return formatPercentage(p.CPUPercent) }
+	case "memory":
+		procs = info.TopMemProcesses
+		usage = func(p systeminfo.ProcessUsage) (s string) { return formatBytesUint(p.MemBytes) }
+	}
+
+	if len(procs) == 0 {
+		return nil
+	}
+
+	lines = []string{sectionHeader("🔝", loc.text("notification_section_top_processes", "Top Processes"))}
+	for _, p := range procs {
+		lines = append(lines, fmt.Sprintf(
+			"  ▸ <code>%s</code> (%d): <b>%s</b>",
+			html.EscapeString(fallbackString(p.Name)),
+			p.PID,
+			usage(p),
+		))
+	}
 
 	return lines
 }
@@ -182,6 +243,54 @@ func formatFloat(v float64) string {
 	return formatted
 }
 
+// formatMetricValue returns the value of metric with its unit.
+func formatMetricValue(metric string, v float64) (s string) {
+	switch {
+	case metric == metricTemperature:
+		return formatFloat(v) + " °C"
+	case strings.HasPrefix(metric, bandwidthMetricPrefix):
+		return formatFloat(v) + " Mbps"
+	case metric == metricSelfRSS:
+		return formatBytesUint(uint64(v))
+	case metric == metricSelfGoroutines, metric == metricSelfOpenFiles:
+		return formatInt64(int64(v))
+	case metric == metricSelfGCPause, metric == metricDNSLatency:
+		return formatFloat(v) + " ms"
+	case metric == metricDNSQPS:
+		return formatFloat(v) + " qps"
+	default:
+		return formatPercentage(v)
+	}
+}
+
+// isPercentMetric returns true if the values of metric are percentages.
+func isPercentMetric(metric string) (ok bool) {
+	switch {
+	case
+		metric == metricTemperature,
+		strings.HasPrefix(metric, bandwidthMetricPrefix),
+		metric == metricSelfRSS,
+		metric == metricSelfGoroutines,
+		metric == metricSelfOpenFiles,
+		metric == metricSelfGCPause,
+		metric == metricDNSLatency,
+		metric == metricDNSQPS:
+		return false
+	default:
+		return true
+	}
+}
+
+// metricBar returns the usage bar for the percentage metrics and the value
+// with its unit for the other ones.
+func metricBar(metric string, v float64) (s string) {
+	if !isPercentMetric(metric) {
+		return fmt.Sprintf("<code>%s</code>", formatMetricValue(metric, v))
+	}
+
+	return usageBar(v)
+}
+
 func formatPercentage(value float64) string {
 	if math.IsNaN(value) || math.IsInf(value, 0) {
 		return "-"
@@ -236,6 +345,17 @@ func formatIntegerString(s string, negative bool) string {
 	return result
 }
 
+// linkOrCode returns the HTML link to rawURL if it's an HTTP(S) URL and the
+// escaped code element with it otherwise.
+func linkOrCode(rawURL string) string {
+	escaped := html.EscapeString(rawURL)
+	if strings.HasPrefix(rawURL, "http://") || strings.HasPrefix(rawURL, "https://") {
+		return fmt.Sprintf("<a href=\"%s\">%s</a>", escaped, escaped)
+	}
+
+	return fmt.Sprintf("<code>%s</code>", escaped)
+}
+
 func fallbackString(val string) string {
 	val = strings.TrimSpace(val)
 	if val == "" {
@@ -303,8 +423,15 @@ func toLocal(t time.Time) time.Time {
 
 // timestampLine returns a formatted timestamp line for message footers.
 func timestampLine() string {
-	now := localNow()
-	return fmt.Sprintf("🕐 <i>Updated: %s</i>", now.Format("15:04:05 02/01/2006"))
+	return Locale(nil).timestampLine()
+}
+
+// timestampLine returns the footer line with the current time translated
+// according to loc.
+func (loc Locale) timestampLine() string {
+	now := localNow().Format("15:04:05 02/01/2006")
+
+	return fmt.Sprintf("🕐 <i>%s</i>", loc.text("notification_updated_at", "Updated: {{time}}", "time", now))
 }
 
 // capitalizeFirst returns s with its first letter uppercased.
@@ -0,0 +1,104 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Default Gotify message priorities.
+const (
+	defaultGotifyPriority      = 5
+	defaultGotifyAlertPriority = 8
+)
+
+// GotifyConfig contains runtime configuration for Gotify notifications.
+// Priority is used for all events except for the alerts, which use
+// AlertPriority.
+type GotifyConfig struct {
+	Enabled       bool
+	ServerURL     string
+	AppToken      string
+	Priority      int
+	AlertPriority int
+	Proxy         string
+}
+
+// gotifyChannel delivers notifications to a Gotify server.
+type gotifyChannel struct {
+	client *http.Client
+	conf   GotifyConfig
+}
+
+// newGotifyChannel returns a new Gotify channel.
+func newGotifyChannel(client *http.Client, conf GotifyConfig) (c *gotifyChannel) {
+	if conf.Priority <= 0 {
+		conf.Priority = defaultGotifyPriority
+	}
+
+	if conf.AlertPriority <= 0 {
+		conf.AlertPriority = defaultGotifyAlertPriority
+	}
+
+	return &gotifyChannel{
+		client: client,
+		conf:   conf,
+	}
+}
+
+// type check
+var _ channel = (*gotifyChannel)(nil)
+
+// name implements the [channel] interface for *gotifyChannel.
+func (c *gotifyChannel) name() (n string) { return "gotify" }
+
+// gotifyMessage is the message of the Gotify API.
+type gotifyMessage struct {
+	Title    string `json:"title"`
+	Message  string `json:"message"`
+	Priority int    `json:"priority"`
+}
+
+// send implements the [channel] interface for *gotifyChannel.
+func (c *gotifyChannel) send(ctx context.Context, ev *event) (err error) {
+	priority := c.conf.Priority
+	if ev.typ == eventTypeAlert {
+		priority = c.conf.AlertPriority
+	}
+
+	body, err := json.Marshal(gotifyMessage{
+		Title:    ev.title(),
+		Message:  htmlToText(ev.text),
+		Priority: priority,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal gotify message: %w", err)
+	}
+
+	endpoint := strings.TrimSuffix(c.conf.ServerURL, "/") + "/message"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", c.conf.AppToken)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	traceResponse(ctx, resp.StatusCode, respBody)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("gotify api status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return nil
+}
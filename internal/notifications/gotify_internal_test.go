@@ -0,0 +1,67 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGotifyChannel_Send(t *testing.T) {
+	var (
+		gotKey string
+		gotMsg gotifyMessage
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/message" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+
+		gotKey = r.Header.Get("X-Gotify-Key")
+		if err := json.NewDecoder(r.Body).Decode(&gotMsg); err != nil {
+			t.Errorf("decoding request: %s", err)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	c := newGotifyChannel(srv.Client(), GotifyConfig{
+		Enabled:   true,
+		ServerURL: srv.URL,
+		AppToken:  "token",
+	})
+
+	testCases := []struct {
+		ev           *event
+		name         string
+		wantTitle    string
+		wantPriority int
+	}{{
+		ev:           &event{typ: eventTypeAlert, metric: "cpu", text: "<b>cpu</b>"},
+		name:         "alert",
+		wantTitle:    "Alert: CPU Usage",
+		wantPriority: defaultGotifyAlertPriority,
+	}, {
+		ev:           &event{typ: eventTypeRecovery, metric: "cpu", text: "<b>cpu</b>"},
+		name:         "recovery",
+		wantTitle:    "Recovery: CPU Usage back to normal",
+		wantPriority: defaultGotifyPriority,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := c.send(context.Background(), tc.ev); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if gotKey != "token" {
+				t.Errorf("unexpected key %q", gotKey)
+			}
+
+			if gotMsg.Title != tc.wantTitle || gotMsg.Priority != tc.wantPriority || gotMsg.Message != "cpu" {
+				t.Errorf("unexpected message %+v", gotMsg)
+			}
+		})
+	}
+}
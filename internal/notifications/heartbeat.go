@@ -0,0 +1,159 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// eventTypeHeartbeat is the type of the periodic "I'm alive" events.
+const eventTypeHeartbeat eventType = "heartbeat"
+
+// minHeartbeatInterval is the minimum interval between the heartbeats.
+const minHeartbeatInterval = time.Minute
+
+// HeartbeatConfig contains runtime configuration for the heartbeats.  Every
+// Interval, the heartbeat message is delivered to the notification targets if
+// SendMessage is true, and PingURL, if any, is requested, so that a
+// dead man's switch service, like healthchecks.io, reports the missing pings.
+type HeartbeatConfig struct {
+	PingURL     string
+	Interval    time.Duration
+	SendMessage bool
+	Enabled     bool
+}
+
+// ValidateHeartbeatConfig returns an error if the enabled heartbeat
+// configuration is invalid.
+func ValidateHeartbeatConfig(conf HeartbeatConfig) (err error) {
+	if !conf.Enabled {
+		return nil
+	}
+
+	if conf.Interval < minHeartbeatInterval {
+		return fmt.Errorf("interval: must be at least %s, got %s", minHeartbeatInterval, conf.Interval)
+	}
+
+	if conf.PingURL == "" {
+		if !conf.SendMessage {
+			return errors.New("either ping url or sending messages is required")
+		}
+
+		return nil
+	}
+
+	u, err := url.Parse(conf.PingURL)
+	if err != nil {
+		return fmt.Errorf("ping url: %w", err)
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("ping url: scheme %q is not http or https", u.Scheme)
+	} else if u.Host == "" {
+		return errors.New("ping url: host is required")
+	}
+
+	return nil
+}
+
+// UpdateHeartbeatConfig applies the new heartbeat configuration.  The next
+// heartbeat is due in conf.Interval after the previous one.
+func (m *Manager) UpdateHeartbeatConfig(conf HeartbeatConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.heartbeat = conf
+}
+
+// checkHeartbeat pings the configured URL and sends the heartbeat message, if
+// the heartbeat is due.
+func (m *Manager) checkHeartbeat(ctx context.Context) {
+	now := time.Now()
+
+	m.mu.Lock()
+	conf := m.heartbeat
+	last := m.heartbeatLast
+	if last.IsZero() {
+		last = m.startTime
+	}
+
+	if !conf.Enabled || conf.Interval <= 0 || now.Sub(last) < conf.Interval {
+		m.mu.Unlock()
+
+		return
+	}
+
+	m.heartbeatLast = now
+	version := m.version
+	m.mu.Unlock()
+
+	if conf.PingURL != "" {
+		err := m.pingHeartbeat(ctx, conf.PingURL)
+		if err != nil {
+			m.logger.Error("heartbeat ping failed", slog.String("error", err.Error()))
+		}
+	}
+
+	cfg := m.getTelegramConfig()
+	if !conf.SendMessage || !m.isReady(cfg) {
+		return
+	}
+
+	uptime := now.Sub(m.startTime).Truncate(time.Second)
+	ev := &event{
+		time: now,
+		typ:  eventTypeHeartbeat,
+		text: composeHeartbeatMessage(cfg, version, uptime),
+	}
+
+	if err := m.deliver(ctx, cfg, ev); err != nil {
+		m.logger.Error("heartbeat notification failed", slog.String("error", err.Error()))
+	}
+}
+
+// pingHeartbeat requests the dead man's switch URL.
+func (m *Manager) pingHeartbeat(ctx context.Context, pingURL string) (err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pingURL, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := m.direct.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("ping status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return nil
+}
+
+// composeHeartbeatMessage formats the heartbeat notification.
+func composeHeartbeatMessage(cfg TelegramConfig, version string, uptime time.Duration) (msg string) {
+	lines := make([]string, 0, 8)
+	if prefix := strings.TrimSpace(cfg.CustomMessage); prefix != "" {
+		lines = append(lines, prefix, "")
+	}
+
+	lines = append(lines,
+		"💓 <b>AdGuard Home is alive</b>",
+		divider(),
+		"",
+		fmt.Sprintf("  ▸ <b>Version:</b> <code>%s</code>", html.EscapeString(fallbackString(version))),
+		fmt.Sprintf("  ▸ <b>Uptime:</b>  <code>%s</code>", uptime),
+		"",
+		divider(),
+		timestampLine(),
+	)
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,93 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestValidateHeartbeatConfig(t *testing.T) {
+	testCases := []struct {
+		name    string
+		conf    HeartbeatConfig
+		wantErr bool
+	}{{
+		name:    "disabled",
+		conf:    HeartbeatConfig{},
+		wantErr: false,
+	}, {
+		name:    "message",
+		conf:    HeartbeatConfig{Interval: time.Hour, SendMessage: true, Enabled: true},
+		wantErr: false,
+	}, {
+		name: "ping",
+		conf: HeartbeatConfig{
+			PingURL:  "https://hc-ping.com/uuid",
+			Interval: time.Hour,
+			Enabled:  true,
+		},
+		wantErr: false,
+	}, {
+		name:    "nothing_to_send",
+		conf:    HeartbeatConfig{Interval: time.Hour, Enabled: true},
+		wantErr: true,
+	}, {
+		name:    "short_interval",
+		conf:    HeartbeatConfig{Interval: time.Second, SendMessage: true, Enabled: true},
+		wantErr: true,
+	}, {
+		name: "bad_scheme",
+		conf: HeartbeatConfig{
+			PingURL:  "ftp://example.org/ping",
+			Interval: time.Hour,
+			Enabled:  true,
+		},
+		wantErr: true,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateHeartbeatConfig(tc.conf)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("got error %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestManager_checkHeartbeat(t *testing.T) {
+	var pings atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pings.Add(1)
+	}))
+	t.Cleanup(srv.Close)
+
+	rec := &recordingChannel{chanName: "matrix"}
+
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	m.direct = srv.Client()
+	m.channels = []channel{rec}
+	m.startTime = time.Now().Add(-2 * time.Hour)
+	m.UpdateHeartbeatConfig(HeartbeatConfig{
+		PingURL:     srv.URL,
+		Interval:    time.Hour,
+		SendMessage: true,
+		Enabled:     true,
+	})
+
+	ctx := context.Background()
+	m.checkHeartbeat(ctx)
+	m.checkHeartbeat(ctx)
+
+	if got := pings.Load(); got != 1 {
+		t.Errorf("got %d pings, want 1", got)
+	}
+
+	if len(rec.got) != 1 || rec.got[0] != eventTypeHeartbeat {
+		t.Errorf("got %q, want a single %q", rec.got, eventTypeHeartbeat)
+	}
+}
@@ -0,0 +1,238 @@
+package notifications
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghos"
+	"github.com/google/renameio/v2/maybe"
+)
+
+// maxHistoryLen is the maximum number of the history entries kept.
+const maxHistoryLen = 1000
+
+// HistoryStatus is the delivery status of a notification.
+type HistoryStatus string
+
+// Available delivery statuses.
+const (
+	// HistoryStatusSent means that the notification has been delivered.
+	HistoryStatusSent HistoryStatus = "sent"
+
+	// HistoryStatusFailed means that all attempts to deliver the notification
+	// have failed.
+	HistoryStatusFailed HistoryStatus = "failed"
+
+	// HistoryStatusQueued means that the notification has been queued until
+	// the end of the quiet hours.
+	HistoryStatusQueued HistoryStatus = "queued"
+
+	// HistoryStatusSuppressed means that the notification has been dropped
+	// because of the quiet hours.
+	HistoryStatusSuppressed HistoryStatus = "suppressed"
+
+	// HistoryStatusBatched means that the notification has been batched into
+	// the digest.
+	HistoryStatusBatched HistoryStatus = "batched"
+)
+
+// HistoryEntry is a record about a notification delivered to a single target.
+// Channel is empty for the notifications held back before delivery.
+type HistoryEntry struct {
+	Time    time.Time     `json:"time"`
+	Channel string        `json:"channel,omitempty"`
+	Type    string        `json:"type"`
+	Metric  string        `json:"metric,omitempty"`
+	Title   string        `json:"title"`
+	Message string        `json:"message"`
+	Status  HistoryStatus `json:"status"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// HistoryQuery is the filter and the page of the history entries.  Empty
+// fields match all entries.
+type HistoryQuery struct {
+	Since   time.Time
+	Type    string
+	Channel string
+	Status  HistoryStatus
+	Offset  int
+	Limit   int
+}
+
+// matches returns true if e matches q.
+func (q *HistoryQuery) matches(e *HistoryEntry) (ok bool) {
+	return (q.Since.IsZero() || !e.Time.Before(q.Since)) &&
+		(q.Type == "" || e.Type == q.Type) &&
+		(q.Channel == "" || e.Channel == q.Channel) &&
+		(q.Status == "" || e.Status == q.Status)
+}
+
+// history is the persistent history of the notifications.
+type history struct {
+	// mu protects entries and the file.
+	mu *sync.Mutex
+
+	// path is the path to the file with the entries.  The history is only
+	// kept in memory if it's empty.
+	path string
+
+	// entries are the entries from the oldest to the newest.
+	entries []*HistoryEntry
+}
+
+// newHistory returns a new history stored in the file at path and loads the
+// existing entries.
+func newHistory(path string) (h *history, err error) {
+	h = &history{
+		mu:   &sync.Mutex{},
+		path: path,
+	}
+
+	if path == "" {
+		return h, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return h, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading history: %w", err)
+	}
+
+	err = json.Unmarshal(data, &h.entries)
+	if err != nil {
+		return nil, fmt.Errorf("decoding history: %w", err)
+	}
+
+	return h, nil
+}
+
+// add appends the entries and stores the history.
+func (h *history) add(entries ...*HistoryEntry) (err error) {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.entries = append(h.entries, entries...)
+	if n := len(h.entries) - maxHistoryLen; n > 0 {
+		h.entries = slices.Delete(h.entries, 0, n)
+	}
+
+	if h.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(h.entries)
+	if err != nil {
+		return fmt.Errorf("encoding history: %w", err)
+	}
+
+	err = maybe.WriteFile(h.path, data, aghos.DefaultPermFile)
+	if err != nil {
+		return fmt.Errorf("writing history: %w", err)
+	}
+
+	return nil
+}
+
+// query returns the page of the entries matching q from the newest to the
+// oldest and the total number of the matching entries.
+func (h *history) query(q *HistoryQuery) (page []HistoryEntry, total int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	page = []HistoryEntry{}
+	for _, e := range slices.Backward(h.entries) {
+		if !q.matches(e) {
+			continue
+		}
+
+		if total >= q.Offset && (q.Limit <= 0 || len(page) < q.Limit) {
+			page = append(page, *e)
+		}
+
+		total++
+	}
+
+	return page, total
+}
+
+// newHistoryEntry returns a history entry about ev.
+func newHistoryEntry(ev *event, channel string, status HistoryStatus, err error) (e *HistoryEntry) {
+	e = &HistoryEntry{
+		Time:    ev.time,
+		Channel: channel,
+		Type:    string(ev.typ),
+		Metric:  ev.metric,
+		Title:   ev.title(),
+		Message: htmlToText(ev.text),
+		Status:  status,
+	}
+
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	if err != nil {
+		e.Error = err.Error()
+	}
+
+	return e
+}
+
+// OpenHistory enables the notification history stored in the file at path.
+// The history is only kept in memory if path is empty.
+func (m *Manager) OpenHistory(path string) (err error) {
+	h, err := newHistory(path)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.history = h
+
+	return nil
+}
+
+// History returns the page of the history entries matching q from the newest
+// to the oldest and the total number of the matching entries.
+func (m *Manager) History(q *HistoryQuery) (page []HistoryEntry, total int) {
+	m.mu.RLock()
+	h := m.history
+	m.mu.RUnlock()
+
+	if h == nil {
+		return []HistoryEntry{}, 0
+	}
+
+	return h.query(q)
+}
+
+// record adds the entries to the history, if it's enabled.
+func (m *Manager) record(entries ...*HistoryEntry) {
+	m.mu.RLock()
+	h := m.history
+	m.mu.RUnlock()
+
+	if h == nil {
+		return
+	}
+
+	err := h.add(entries...)
+	if err != nil {
+		m.logger.Error("recording notification history", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,60 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestManager_History(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	m.channels = []channel{&recordingChannel{chanName: "matrix"}}
+	if err := m.OpenHistory(path); err != nil {
+		t.Fatalf("opening history: %s", err)
+	}
+
+	ctx := context.Background()
+	start := time.Now()
+	for i, metric := range []string{"cpu", "memory", "disk"} {
+		ev := &event{time: start.Add(time.Duration(i) * time.Second), typ: eventTypeAlert, metric: metric}
+		if err := m.deliver(ctx, TelegramConfig{}, ev); err != nil {
+			t.Fatalf("delivering: %s", err)
+		}
+	}
+
+	page, total := m.History(&HistoryQuery{Limit: 2})
+	if total != 3 || len(page) != 2 || page[0].Metric != "disk" || page[1].Metric != "memory" {
+		t.Fatalf("got page %+v of %d entries", page, total)
+	}
+
+	page, total = m.History(&HistoryQuery{Offset: 2, Limit: 2})
+	if total != 3 || len(page) != 1 || page[0].Metric != "cpu" || page[0].Status != HistoryStatusSent {
+		t.Fatalf("got page %+v of %d entries", page, total)
+	}
+
+	// Reopen the history to check that it's persisted.
+	m = NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	if err := m.OpenHistory(path); err != nil {
+		t.Fatalf("reopening history: %s", err)
+	}
+
+	ev := &event{typ: eventTypeFilterUpdate}
+	if err := m.history.add(newHistoryEntry(ev, "gotify", HistoryStatusFailed, errors.New("test error"))); err != nil {
+		t.Fatalf("adding entry: %s", err)
+	}
+
+	page, total = m.History(&HistoryQuery{Status: HistoryStatusFailed, Channel: "gotify"})
+	if total != 1 || page[0].Error != "test error" || page[0].Type != "filter_update" {
+		t.Fatalf("got page %+v of %d entries", page, total)
+	}
+
+	_, total = m.History(&HistoryQuery{})
+	if total != 4 {
+		t.Errorf("got %d entries after reopening, want 4", total)
+	}
+}
@@ -0,0 +1,42 @@
+package notifications
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// HysteresisMetrics are the metrics, which the hysteresis factor can be set
+// for.
+var HysteresisMetrics = []string{"cpu", "memory", "disk", "swap", metricTemperature}
+
+// ValidateHysteresis returns an error if the hysteresis factors are invalid.
+// The keys must be the names from [HysteresisMetrics], and the factors must be
+// greater than zero and not greater than one.
+func ValidateHysteresis(factors map[string]float64) (err error) {
+	for metric, f := range factors {
+		if !slices.Contains(HysteresisMetrics, metric) {
+			return fmt.Errorf("hysteresis: unknown metric %q", metric)
+		} else if f <= 0 || f > 1 {
+			return fmt.Errorf("hysteresis: %s factor must be greater than 0 and not greater than 1", metric)
+		}
+	}
+
+	return nil
+}
+
+// resetThreshold returns the value, which metric must fall below for its alert
+// with threshold to be considered resolved.  The additional mount points use
+// the factor of the "disk" metric.
+func (cfg *TelegramConfig) resetThreshold(metric string, threshold float64) (v float64) {
+	if strings.HasPrefix(metric, diskPathMetricPrefix) {
+		metric = "disk"
+	}
+
+	f, ok := cfg.Hysteresis[metric]
+	if !ok || f <= 0 || f > 1 {
+		f = resetFactor
+	}
+
+	return threshold * f
+}
@@ -0,0 +1,64 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+)
+
+func TestManager_handleMetric_hysteresis(t *testing.T) {
+	rec := &recordingChannel{chanName: "matrix"}
+
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	m.channels = []channel{rec}
+
+	cfg := TelegramConfig{
+		Hysteresis: map[string]float64{"cpu": 0.5},
+	}
+
+	ctx := context.Background()
+	for _, v := range []float64{95, 85, 60, 40} {
+		m.handleMetric(ctx, cfg, "cpu", v, 90, systeminfo.Info{})
+	}
+
+	want := []eventType{eventTypeAlert, eventTypeRecovery}
+	if !slices.Equal(rec.got, want) {
+		t.Errorf("got %q, want %q", rec.got, want)
+	}
+}
+
+func TestValidateHysteresis(t *testing.T) {
+	testCases := []struct {
+		factors map[string]float64
+		name    string
+		wantErr bool
+	}{{
+		factors: map[string]float64{"cpu": 0.8, "disk": 1, "swap": 0.5},
+		name:    "valid",
+		wantErr: false,
+	}, {
+		factors: map[string]float64{"load": 0.8},
+		name:    "unknown_metric",
+		wantErr: true,
+	}, {
+		factors: map[string]float64{"memory": 0},
+		name:    "zero",
+		wantErr: true,
+	}, {
+		factors: map[string]float64{"memory": 1.5},
+		name:    "too_big",
+		wantErr: true,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateHysteresis(tc.factors)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("ValidateHysteresis() error = %v, want error %t", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+)
+
+// eventTypeInterfaceChange is the type of the events about the changes of the
+// link state and of the addresses of the network interfaces.  The metric of
+// the event is the name of the interface, so that the routing rules like
+// "interface_change:eth0" can be used.
+const eventTypeInterfaceChange eventType = "interface_change"
+
+// checkInterfaceChanges sends a notification for each network interface
+// changed since the previous check.
+func (m *Manager) checkInterfaceChanges(ctx context.Context, cfg TelegramConfig, info systeminfo.Info) {
+	m.mu.Lock()
+	seen := m.ifaceChangesSeen
+	m.mu.Unlock()
+
+	// The changes detected at once have the same time.
+	last := seen
+
+	var names []string
+	byName := map[string][]systeminfo.InterfaceChange{}
+	for _, c := range info.InterfaceChanges {
+		if !c.Time.After(seen) {
+			continue
+		}
+
+		last = c.Time
+		if _, ok := byName[c.Interface]; !ok {
+			names = append(names, c.Interface)
+		}
+
+		byName[c.Interface] = append(byName[c.Interface], c)
+	}
+
+	if len(names) == 0 {
+		return
+	}
+
+	m.mu.Lock()
+	m.ifaceChangesSeen = last
+	m.mu.Unlock()
+
+	slices.Sort(names)
+
+	loc := m.getLocale()
+	for _, name := range names {
+		ev := &event{
+			time:   time.Now(),
+			typ:    eventTypeInterfaceChange,
+			metric: name,
+			text:   composeInterfaceChangeMessage(cfg, loc, name, byName[name], info),
+		}
+
+		if err := m.deliver(ctx, cfg, ev); err != nil {
+			m.logger.Error(
+				"interface change notification failed",
+				"interface", name,
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// composeInterfaceChangeMessage formats the notification about the changes of
+// the network interface with the name.
+func composeInterfaceChangeMessage(
+	cfg TelegramConfig,
+	loc Locale,
+	name string,
+	changes []systeminfo.InterfaceChange,
+	info systeminfo.Info,
+) (msg string) {
+	lines := make([]string, 0, 12+len(changes))
+	if prefix := strings.TrimSpace(cfg.CustomMessage); prefix != "" {
+		lines = append(lines, prefix)
+		lines = append(lines, "")
+	}
+
+	title := loc.text("notification_interface_changed_title", "Network interface {{interface}} has changed", "interface", name)
+	lines = append(lines, fmt.Sprintf("🔌 <b>%s</b>", html.EscapeString(title)))
+	lines = append(lines, divider())
+	lines = append(lines, "")
+
+	if info.Hostname != "" {
+		lines = append(lines, fmt.Sprintf(
+			"  ▸ <b>%s:</b> <code>%s</code>",
+			loc.text("notification_label_host", "Host"),
+			html.EscapeString(info.Hostname),
+		))
+		lines = append(lines, "")
+	}
+
+	for _, c := range changes {
+		lines = append(lines, "  ▸ "+html.EscapeString(interfaceChangeText(loc, c)))
+	}
+
+	if lostAllAddrs(name, changes, info) {
+		lines = append(lines, "")
+		lines = append(lines, "🔴 "+loc.text("notification_interface_no_addresses", "The interface has no addresses left."))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, divider())
+	lines = append(lines, loc.timestampLine())
+
+	return strings.Join(lines, "\n")
+}
+
+// interfaceChangeText returns the description of the change c.
+func interfaceChangeText(loc Locale, c systeminfo.InterfaceChange) (s string) {
+	switch c.Type {
+	case systeminfo.InterfaceLinkUp:
+		return loc.text("notification_interface_link_up", "Link is up")
+	case systeminfo.InterfaceLinkDown:
+		return loc.text("notification_interface_link_down", "Link is down")
+	case systeminfo.InterfaceAddrAdded:
+		return loc.text("notification_interface_address_added", "Address added: {{addr}}", "addr", c.Addr)
+	case systeminfo.InterfaceAddrRemoved:
+		return loc.text("notification_interface_address_removed", "Address removed: {{addr}}", "addr", c.Addr)
+	default:
+		return string(c.Type)
+	}
+}
+
+// lostAllAddrs returns true if the network interface with the name has lost
+// an address with changes and has no addresses left.
+func lostAllAddrs(name string, changes []systeminfo.InterfaceChange, info systeminfo.Info) (ok bool) {
+	removed := slices.ContainsFunc(changes, func(c systeminfo.InterfaceChange) (ok bool) {
+		return c.Type == systeminfo.InterfaceAddrRemoved
+	})
+	if !removed {
+		return false
+	}
+
+	i := slices.IndexFunc(info.Interfaces, func(iface systeminfo.InterfaceIO) (ok bool) {
+		return iface.Name == name
+	})
+
+	return i < 0 || len(info.Interfaces[i].Addrs) == 0
+}
@@ -0,0 +1,64 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+)
+
+func TestManager_checkInterfaceChanges(t *testing.T) {
+	rec := &recordingChannel{chanName: "matrix"}
+
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	m.channels = []channel{rec}
+
+	now := time.Now()
+	info := systeminfo.Info{
+		InterfaceChanges: []systeminfo.InterfaceChange{{
+			Time:      now,
+			Type:      systeminfo.InterfaceLinkDown,
+			Interface: "eth0",
+		}, {
+			Time:      now,
+			Type:      systeminfo.InterfaceAddrRemoved,
+			Interface: "eth0",
+			Addr:      "192.168.1.2",
+		}, {
+			Time:      now,
+			Type:      systeminfo.InterfaceLinkUp,
+			Interface: "wg0",
+		}},
+	}
+
+	ctx := context.Background()
+	m.checkInterfaceChanges(ctx, TelegramConfig{}, info)
+	m.checkInterfaceChanges(ctx, TelegramConfig{}, info)
+
+	want := []eventType{eventTypeInterfaceChange, eventTypeInterfaceChange}
+	if !slices.Equal(rec.got, want) {
+		t.Errorf("got events %q, want %q", rec.got, want)
+	}
+}
+
+func TestComposeInterfaceChangeMessage(t *testing.T) {
+	changes := []systeminfo.InterfaceChange{{
+		Type:      systeminfo.InterfaceAddrRemoved,
+		Interface: "eth0",
+		Addr:      "192.168.1.2",
+	}}
+
+	msg := composeInterfaceChangeMessage(TelegramConfig{}, nil, "eth0", changes, systeminfo.Info{
+		Interfaces: []systeminfo.InterfaceIO{{Name: "eth0", Up: true}},
+	})
+
+	for _, want := range []string{"eth0 has changed", "Address removed: 192.168.1.2", "no addresses left"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message %q does not contain %q", msg, want)
+		}
+	}
+}
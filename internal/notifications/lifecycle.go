@@ -0,0 +1,261 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io/fs"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghos"
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+	"github.com/google/renameio/v2/maybe"
+)
+
+// Service lifecycle event types.
+const (
+	eventTypeStartup        eventType = "startup"
+	eventTypeShutdown       eventType = "shutdown"
+	eventTypeUncleanRestart eventType = "unclean_restart"
+)
+
+// lifecycleTouchInterval is the interval of updating the last seen time in
+// the lifecycle marker.
+const lifecycleTouchInterval = 5 * time.Minute
+
+// lifecycleMarker is the contents of the file existing while AdGuard Home is
+// running.  The file left from the previous run means that it hasn't been shut
+// down cleanly.
+type lifecycleMarker struct {
+	Started  time.Time `json:"started"`
+	LastSeen time.Time `json:"last_seen"`
+	Version  string    `json:"version"`
+}
+
+// lifecycle is the state of the lifecycle notifications.
+type lifecycle struct {
+	// prev is the marker left from the previous run, if any.
+	prev *lifecycleMarker
+
+	// path is the path to the marker file.
+	path string
+
+	// cur is the marker of the current run.
+	cur lifecycleMarker
+}
+
+// OpenLifecycleMarker loads the lifecycle marker left in the file at path by
+// the previous run, if any, to detect an unclean restart.  It must be called
+// before [Manager.NotifyStartup].
+func (m *Manager) OpenLifecycleMarker(path string) (err error) {
+	lc := &lifecycle{
+		path: path,
+	}
+
+	data, err := os.ReadFile(path)
+	if err == nil {
+		lc.prev = &lifecycleMarker{}
+		err = json.Unmarshal(data, lc.prev)
+		if err != nil {
+			m.logger.Warn("decoding lifecycle marker", slog.String("error", err.Error()))
+			lc.prev = &lifecycleMarker{}
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("reading lifecycle marker: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lifecycle = lc
+
+	return nil
+}
+
+// NotifyStartup stores the lifecycle marker of the current run and sends the
+// startup notification, or the unclean restart one if the previous run hasn't
+// been shut down cleanly.
+func (m *Manager) NotifyStartup(ctx context.Context, version string) {
+	now := time.Now()
+
+	var prev *lifecycleMarker
+	cur := lifecycleMarker{
+		Started:  now,
+		LastSeen: now,
+		Version:  version,
+	}
+
+	m.mu.Lock()
+	lc := m.lifecycle
+	m.version = version
+	if lc != nil {
+		prev, lc.prev = lc.prev, nil
+		lc.cur = cur
+	}
+	m.mu.Unlock()
+
+	if lc != nil {
+		err := writeLifecycleMarker(lc.path, cur)
+		if err != nil {
+			m.logger.Error("writing lifecycle marker", slog.String("error", err.Error()))
+		}
+	}
+
+	cfg := m.getTelegramConfig()
+	if !m.isReady(cfg) {
+		return
+	}
+
+	info := systeminfo.Collect(ctx)
+	ev := &event{time: now, typ: eventTypeStartup, text: composeStartupMessage(cfg, version, prev, info)}
+	if prev != nil {
+		ev.typ = eventTypeUncleanRestart
+	}
+
+	if err := m.deliver(ctx, cfg, ev); err != nil {
+		m.logger.Error("startup notification failed", slog.String("error", err.Error()))
+	}
+}
+
+// NotifyShutdown sends the clean shutdown notification and removes the
+// lifecycle marker.  It should be called before [Manager.Stop].
+func (m *Manager) NotifyShutdown(ctx context.Context) {
+	m.mu.Lock()
+	lc := m.lifecycle
+	version := m.version
+	m.lifecycle = nil
+	m.mu.Unlock()
+
+	if lc != nil {
+		err := os.Remove(lc.path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			m.logger.Error("removing lifecycle marker", slog.String("error", err.Error()))
+		}
+	}
+
+	cfg := m.getTelegramConfig()
+	if !m.isReady(cfg) {
+		return
+	}
+
+	uptime := time.Since(m.startTime).Truncate(time.Second)
+	ev := &event{time: time.Now(), typ: eventTypeShutdown, text: composeShutdownMessage(cfg, version, uptime)}
+	if err := m.deliver(ctx, cfg, ev); err != nil {
+		m.logger.Error("shutdown notification failed", slog.String("error", err.Error()))
+	}
+}
+
+// touchLifecycle updates the last seen time in the lifecycle marker, if it's
+// due.
+func (m *Manager) touchLifecycle() {
+	now := time.Now()
+
+	m.mu.Lock()
+	lc := m.lifecycle
+	if lc == nil || lc.cur.Started.IsZero() || now.Sub(lc.cur.LastSeen) < lifecycleTouchInterval {
+		m.mu.Unlock()
+
+		return
+	}
+
+	lc.cur.LastSeen = now
+	cur := lc.cur
+	m.mu.Unlock()
+
+	err := writeLifecycleMarker(lc.path, cur)
+	if err != nil {
+		m.logger.Debug("updating lifecycle marker", slog.String("error", err.Error()))
+	}
+}
+
+// writeLifecycleMarker stores marker in the file at path.
+func writeLifecycleMarker(path string, marker lifecycleMarker) (err error) {
+	data, err := json.Marshal(marker)
+	if err != nil {
+		return fmt.Errorf("encoding lifecycle marker: %w", err)
+	}
+
+	return maybe.WriteFile(path, data, aghos.DefaultPermFile)
+}
+
+// composeStartupMessage formats the startup notification.  prev is the marker
+// of the previous run, if it hasn't been shut down cleanly.
+func composeStartupMessage(
+	cfg TelegramConfig,
+	version string,
+	prev *lifecycleMarker,
+	info systeminfo.Info,
+) (msg string) {
+	lines := make([]string, 0, 16)
+	if prefix := strings.TrimSpace(cfg.CustomMessage); prefix != "" {
+		lines = append(lines, prefix, "")
+	}
+
+	if prev == nil {
+		lines = append(lines, "🟢 <b>AdGuard Home started</b>")
+	} else {
+		lines = append(lines, "⚠️ <b>AdGuard Home restarted after an unclean shutdown</b>")
+	}
+
+	lines = append(lines, divider(), "")
+	lines = append(lines, fmt.Sprintf("  ▸ <b>Version:</b> <code>%s</code>", html.EscapeString(fallbackString(version))))
+
+	if prev != nil {
+		if !prev.LastSeen.IsZero() {
+			lines = append(lines, fmt.Sprintf(
+				"  ▸ <b>Last Seen:</b> <code>%s</code>",
+				toLocal(prev.LastSeen).Format(time.DateTime),
+			))
+		}
+
+		if !prev.Started.IsZero() && prev.LastSeen.After(prev.Started) {
+			lines = append(lines, fmt.Sprintf(
+				"  ▸ <b>Previous Uptime:</b> <code>%s</code>",
+				prev.LastSeen.Sub(prev.Started).Truncate(time.Second),
+			))
+		}
+
+		if prev.Version != "" && prev.Version != version {
+			lines = append(lines, fmt.Sprintf(
+				"  ▸ <b>Previous Version:</b> <code>%s</code>",
+				html.EscapeString(prev.Version),
+			))
+		}
+	}
+
+	lines = append(lines, "")
+	if info.Hostname != "" {
+		lines = append(lines, systemOverviewLines(nil, info)...)
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, divider(), timestampLine())
+
+	return strings.Join(lines, "\n")
+}
+
+// composeShutdownMessage formats the clean shutdown notification.
+func composeShutdownMessage(cfg TelegramConfig, version string, uptime time.Duration) (msg string) {
+	lines := make([]string, 0, 8)
+	if prefix := strings.TrimSpace(cfg.CustomMessage); prefix != "" {
+		lines = append(lines, prefix, "")
+	}
+
+	lines = append(lines,
+		"🔴 <b>AdGuard Home is shutting down</b>",
+		divider(),
+		"",
+		fmt.Sprintf("  ▸ <b>Version:</b> <code>%s</code>", html.EscapeString(fallbackString(version))),
+		fmt.Sprintf("  ▸ <b>Uptime:</b> <code>%s</code>", uptime),
+		"",
+		divider(),
+		timestampLine(),
+	)
+
+	return strings.Join(lines, "\n")
+}
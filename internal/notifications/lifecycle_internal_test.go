@@ -0,0 +1,57 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestManager_lifecycle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lifecycle.json")
+	ctx := context.Background()
+
+	newManager := func(t *testing.T) (m *Manager, rec *recordingChannel) {
+		t.Helper()
+
+		rec = &recordingChannel{chanName: "matrix"}
+		m = NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+		m.channels = []channel{rec}
+
+		err := m.OpenLifecycleMarker(path)
+		if err != nil {
+			t.Fatalf("opening lifecycle marker: %s", err)
+		}
+
+		return m, rec
+	}
+
+	m, rec := newManager(t)
+	m.NotifyStartup(ctx, "v1.0.0")
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("lifecycle marker not written: %s", err)
+	}
+
+	m.NotifyShutdown(ctx)
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("lifecycle marker not removed: %v", err)
+	}
+
+	if want := []eventType{eventTypeStartup, eventTypeShutdown}; !slices.Equal(rec.got, want) {
+		t.Errorf("got %q, want %q", rec.got, want)
+	}
+
+	// Simulate a crash by not shutting down.
+	m, _ = newManager(t)
+	m.NotifyStartup(ctx, "v1.0.0")
+
+	m, rec = newManager(t)
+	m.NotifyStartup(ctx, "v1.0.1")
+	if want := []eventType{eventTypeUncleanRestart}; !slices.Equal(rec.got, want) {
+		t.Errorf("got %q, want %q", rec.got, want)
+	}
+}
@@ -0,0 +1,57 @@
+package notifications
+
+import (
+	"time"
+)
+
+// LiveEventTypeAlert is the [LiveEvent.Type] of the alerts.
+const LiveEventTypeAlert = string(eventTypeAlert)
+
+// LiveEvent is a notification event reported to the listener set with
+// [Manager.SetListener], like the one updating the web UI.
+type LiveEvent struct {
+	Time time.Time `json:"time"`
+
+	// Type is the type of the event, like "alert" or "filter_update".
+	Type string `json:"type"`
+
+	// Metric is the alert key, like "cpu", of the alert and recovery events.
+	Metric string `json:"metric,omitempty"`
+
+	Title   string `json:"title"`
+	Message string `json:"message"`
+}
+
+// SetListener sets the function, which is called with each event before its
+// delivery, even if it's held back by the quiet hours or the rate limit.  l
+// must not block.  If l is nil, the events aren't reported.
+func (m *Manager) SetListener(l func(ev LiveEvent)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.listener = l
+}
+
+// notifyListener reports ev to the listener, if any.
+func (m *Manager) notifyListener(ev *event) {
+	m.mu.RLock()
+	l := m.listener
+	m.mu.RUnlock()
+
+	if l == nil {
+		return
+	}
+
+	t := ev.time
+	if t.IsZero() {
+		t = time.Now()
+	}
+
+	l(LiveEvent{
+		Time:    t,
+		Type:    string(ev.typ),
+		Metric:  ev.metric,
+		Title:   ev.title(),
+		Message: htmlToText(ev.text),
+	})
+}
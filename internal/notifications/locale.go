@@ -0,0 +1,106 @@
+package notifications
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Locale contains the translations of the notification messages by the keys of
+// the web UI translation files, like "notification_section_metrics".  The
+// placeholders use the i18next syntax, like "{{metric}}".  A nil Locale is
+// valid and means English.
+type Locale map[string]string
+
+// ParseLocale parses the contents of a web UI translation file.
+func ParseLocale(b []byte) (loc Locale, err error) {
+	err = json.Unmarshal(b, &loc)
+	if err != nil {
+		return nil, fmt.Errorf("parsing translations: %w", err)
+	}
+
+	return loc, nil
+}
+
+// SetLocale sets the translations of the notification messages.  loc must not
+// be modified after calling SetLocale.
+func (m *Manager) SetLocale(loc Locale) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.locale = loc
+}
+
+// getLocale returns the current translations of the notification messages.
+func (m *Manager) getLocale() (loc Locale) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.locale
+}
+
+// text returns the translation of key or def, if there is none, with the
+// placeholders replaced.  args are the pairs of the placeholder names and
+// values.
+func (loc Locale) text(key, def string, args ...string) (s string) {
+	s = def
+	if tr := loc[key]; tr != "" {
+		s = tr
+	}
+
+	if len(args) == 0 {
+		return s
+	}
+
+	oldnew := make([]string, 0, len(args))
+	for i := 0; i+1 < len(args); i += 2 {
+		oldnew = append(oldnew, "{{"+args[i]+"}}", args[i+1])
+	}
+
+	return strings.NewReplacer(oldnew...).Replace(s)
+}
+
+// metricName returns the translated display name of metric.
+func (loc Locale) metricName(metric string) (name string) {
+	if iface, ok := strings.CutPrefix(metric, bandwidthMetricPrefix); ok {
+		return loc.text("notification_metric_bandwidth", metricDisplayName(metric), "interface", iface)
+	} else if path, ok := strings.CutPrefix(metric, diskPathMetricPrefix); ok {
+		return loc.text("notification_metric_disk_path", metricDisplayName(metric), "path", path)
+	} else if dev, ok := strings.CutPrefix(metric, diskHealthMetricPrefix); ok {
+		return loc.text("notification_metric_disk_health", metricDisplayName(metric), "device", dev)
+	}
+
+	switch m := strings.ToLower(metric); m {
+	case
+		"cpu",
+		"memory",
+		"disk",
+		"swap",
+		"protection",
+		"youtube_health",
+		metricPower,
+		metricTemperature,
+		metricSelfRSS,
+		metricSelfGoroutines,
+		metricSelfOpenFiles,
+		metricSelfGCPause,
+		metricDNSLatency,
+		metricDNSServFail,
+		metricDNSQPS:
+		return loc.text("notification_metric_"+m, metricDisplayName(metric))
+	default:
+		return metricDisplayName(metric)
+	}
+}
+
+// filterTypeLabel returns the translated label of the filter list type.
+func (loc Locale) filterTypeLabel(listType FilterListType) (label string) {
+	switch listType {
+	case FilterListTypeAllow:
+		return loc.text("notification_filter_type_allowlist", filterTypeLabel(listType))
+	case FilterListTypeBlock:
+		return loc.text("notification_filter_type_blocklist", filterTypeLabel(listType))
+	default:
+		return loc.text("notification_filter_type_filter", filterTypeLabel(listType))
+	}
+}
@@ -0,0 +1,79 @@
+package notifications
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+)
+
+func TestLocale_text(t *testing.T) {
+	loc := Locale{"notification_alert_title": "ALARM: {{metric}} über dem Schwellenwert"}
+
+	got := loc.text("notification_alert_title", "ALERT: {{metric}} exceeded threshold", "metric", "CPU")
+	if want := "ALARM: CPU über dem Schwellenwert"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	got = Locale(nil).text("notification_alert_title", "ALERT: {{metric}} exceeded threshold", "metric", "CPU")
+	if want := "ALERT: CPU exceeded threshold"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestParseLocale(t *testing.T) {
+	loc, err := ParseLocale([]byte(`{"notification_label_metric": "Metrik", "enabled": "Aktiviert"}`))
+	if err != nil {
+		t.Fatalf("parsing: %v", err)
+	}
+
+	if got := loc["notification_label_metric"]; got != "Metrik" {
+		t.Errorf("got %q, want %q", got, "Metrik")
+	}
+
+	_, err = ParseLocale([]byte(`[]`))
+	if err == nil {
+		t.Error("got no error for invalid translations")
+	}
+}
+
+func TestComposeAlertMessage_locale(t *testing.T) {
+	loc := Locale{
+		"notification_metric_memory":           "Speicherauslastung",
+		"notification_label_threshold":         "Schwellenwert",
+		"notification_section_system_overview": "Systemübersicht",
+	}
+
+	msg := composeAlertMessage(TelegramConfig{}, loc, "memory", 95, 90, systeminfo.Info{})
+	for _, want := range []string{
+		"ALERT: Speicherauslastung exceeded threshold",
+		"<b>Schwellenwert:</b>",
+		"Systemübersicht",
+		// Untranslated labels fall back to English.
+		"<b>Metric:</b>",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message %q does not contain %q", msg, want)
+		}
+	}
+}
+
+func TestComposeFilterUpdateMessage_locale(t *testing.T) {
+	loc := Locale{
+		"notification_filter_type_blocklist": "Sperrliste",
+		"notification_filter_updated_title":  "{{type}} aktualisiert",
+		"notification_rules_entries":         "{{count}} Einträge",
+	}
+
+	msg := composeFilterUpdateMessage(TelegramConfig{}, loc, FilterUpdate{
+		Name:       "AdGuard DNS filter",
+		ListType:   FilterListTypeBlock,
+		RulesCount: 42,
+		Enabled:    true,
+	}, systeminfo.Info{})
+	for _, want := range []string{"Sperrliste aktualisiert", "<code>42</code> Einträge"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message %q does not contain %q", msg, want)
+		}
+	}
+}
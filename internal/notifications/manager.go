@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"html"
 	"io"
 	"log/slog"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"text/template"
 	"time"
 
 	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
@@ -54,6 +56,52 @@ type TelegramConfig struct {
 	CheckInterval   time.Duration
 	Cooldown        time.Duration
 	CustomMessage   string
+
+	// ParseMode is the formatting mode of the notification messages.  The
+	// messages of the bot menu always use HTML.
+	ParseMode ParseMode
+
+	// MessageThreadID is the ID of the forum topic, which the notifications
+	// are sent to.  The general topic is used if it's zero.
+	MessageThreadID int64
+
+	// Chats are the additional chats receiving the notifications.
+	Chats []TelegramChat
+
+	// AlertButtons, if true, attaches the inline action buttons to the alert
+	// messages sent to the primary chat.
+	AlertButtons bool
+
+	// Hysteresis are the factors of the thresholds by metric, which the
+	// metric must fall below for its alert to be resolved.  The default
+	// factor is 0.9.
+	Hysteresis map[string]float64
+
+	// SustainedChecks is the number of the consecutive checks, which a metric
+	// must be above its threshold during for the alert to fire.  The alert
+	// fires on the first check if it's zero or one.
+	SustainedChecks int
+
+	// Proxy is the URL of the proxy the requests to the Telegram Bot API are
+	// sent through, see [ValidateProxyURL].  The requests are sent directly if
+	// it's empty.
+	Proxy string
+
+	// TemperatureThreshold is the CPU temperature in degrees Celsius, which
+	// triggers the alert.  The temperature isn't monitored if it's zero.
+	TemperatureThreshold float64
+
+	// TemperatureCooldown is the minimum time between the CPU temperature
+	// alerts.  Cooldown is used if it's zero.
+	TemperatureCooldown time.Duration
+
+	// DiskPaths are the additional mount points, which disk usage is
+	// monitored along with the root one, see [ValidateDiskPaths].
+	DiskPaths []DiskPath
+
+	// SwapThreshold is the swap usage in percent, which triggers the alert.
+	// The swap usage isn't monitored if it's zero.
+	SwapThreshold float64
 }
 
 // ioSnapshot holds cumulative I/O counters for delta computation.
@@ -83,11 +131,13 @@ type removeResult struct {
 	err  error
 }
 
-// Manager orchestrates background checks and delivers alerts via Telegram.
+// Manager orchestrates background checks and delivers alerts via Telegram and
+// the other configured channels.
 type Manager struct {
 	logger      *slog.Logger
 	mu          sync.RWMutex
 	telegram    TelegramConfig
+	direct      *http.Client
 	client      *http.Client
 	pollClient  *http.Client
 	stopCh      chan struct{}
@@ -101,12 +151,156 @@ type Manager struct {
 	protection  ProtectionProvider
 	youtube     YouTubeProvider
 	cert        CertProvider
+	waker       ClientWaker
 
 	logs LogsProvider
 
+	// channels are the enabled notification channels other than Telegram.
+	channels []channel
+
+	// templates are the user-defined message templates by event type.
+	templates map[eventType]*template.Template
+
+	// routes are the routing rules mapping the events to the target names.
+	routes map[string][]string
+
+	// quietHours is the quiet hours configuration.
+	quietHours QuietHoursConfig
+
+	// quietQueue are the events queued during the quiet hours.
+	quietQueue []*event
+
+	// quietDropped is the number of the events dropped from the full
+	// quietQueue.
+	quietDropped int
+
+	// digest is the digest mode configuration.
+	digest DigestConfig
+
+	// digestStart is the time of the first event in digestQueue.
+	digestStart time.Time
+
+	// digestQueue are the events batched into the next digest.
+	digestQueue []*event
+
+	// digestDropped is the number of the events dropped from the full
+	// digestQueue.
+	digestDropped int
+
+	// history is the notification history.  It's nil if the history is
+	// disabled.
+	history *history
+
+	// retries is the queue of the notifications to retry.  It's nil if the
+	// queue is disabled.
+	retries *retryQueue
+
+	// listener, if not nil, is called with each event before its delivery.
+	listener func(ev LiveEvent)
+
 	// Recovery alert support.
 	alertStartTime map[string]time.Time
 
+	// escalation is the configuration of the re-notification and escalation
+	// of the unresolved alerts.
+	escalation EscalationConfig
+
+	// activeAlerts are the unresolved alerts by metric.
+	activeAlerts map[string]*activeAlert
+
+	// dhcpEvents are the toggles of the DHCP lease notifications.
+	dhcpEvents DHCPEventsConfig
+
+	// blockedCounts provides the blocked requests counts for the spike
+	// detection.
+	blockedCounts BlockedCountsProvider
+
+	// spike is the configuration of the blocked requests spike detection.
+	spike SpikeConfig
+
+	// spikeBaselines are the baselines of the blocked requests by client ID,
+	// see [spikeOverallKey].
+	spikeBaselines map[string]*spikeBaseline
+
+	// summaries provides the statistics summaries for the scheduled reports.
+	summaries StatsSummaryProvider
+
+	// statsReport is the configuration of the scheduled statistics summary.
+	statsReport StatsReportConfig
+
+	// statsReportLast is the scheduled time of the last sent statistics
+	// summary.
+	statsReportLast time.Time
+
+	// heartbeat is the heartbeat configuration.
+	heartbeat HeartbeatConfig
+
+	// heartbeatLast is the time of the last heartbeat.
+	heartbeatLast time.Time
+
+	// storageErrors are the times of the last storage error notifications by
+	// component and reason.
+	storageErrors map[string]time.Time
+
+	// rateLimit is the global limit of the notifications.
+	rateLimit RateLimitConfig
+
+	// rateSent are the times of the notifications delivered within the
+	// current rate limit period in chronological order.
+	rateSent []time.Time
+
+	// rateSuppressed is the number of the notifications suppressed by the
+	// rate limit since the last summary.
+	rateSuppressed int
+
+	// bandwidth is the configuration of the network bandwidth alerts.
+	bandwidth BandwidthConfig
+
+	// bandwidthIfaces are the states of the bandwidth monitoring by interface
+	// name.
+	bandwidthIfaces map[string]*ifaceBandwidth
+
+	// selfMonitor is the configuration of the alerts about the resource usage
+	// of the process itself.
+	selfMonitor SelfMonitorConfig
+
+	// selfGCCheck is the time of the last check of the garbage collection
+	// pauses.
+	selfGCCheck time.Time
+
+	// dnsPerfCounts provides the DNS query counters for the DNS performance
+	// alerts.
+	dnsPerfCounts DNSPerfProvider
+
+	// dnsPerf is the configuration of the DNS performance alerts.
+	dnsPerf DNSPerfConfig
+
+	// dnsPerfLast is the time of the last take of the DNS query counters.
+	dnsPerfLast time.Time
+
+	// publicIP is the last reported public IP address of the host.
+	publicIP string
+
+	// ifaceChangesSeen is the time of the last network interface change,
+	// which has been notified about.
+	ifaceChangesSeen time.Time
+
+	// locale contains the translations of the alert and filter update
+	// messages.  It's nil for English.
+	locale Locale
+
+	// breaches are the numbers of the consecutive checks, which the metrics
+	// have been above their thresholds during.
+	breaches map[string]int
+
+	// lifecycle is the state of the lifecycle notifications.  It's nil if the
+	// lifecycle marker isn't used.
+	lifecycle *lifecycle
+
+	// version is the version of AdGuard Home reported in the lifecycle
+	// notifications.
+	version string
+
 	// I/O snapshot for delta computation.
 	lastIOSnapshot   *ioSnapshot
 	lastIOSnapshotAt time.Time
@@ -139,15 +333,19 @@ func NewManager(l *slog.Logger, cfg TelegramConfig) *Manager {
 	}
 
 	cfg = normalizeTelegramConfig(cfg)
+	direct := &http.Client{Timeout: 10 * time.Second}
 
 	return &Manager{
 		logger:         l,
 		telegram:       cfg,
-		client:         &http.Client{Timeout: 10 * time.Second},
-		pollClient:     &http.Client{Timeout: 35 * time.Second},
+		direct:         direct,
+		client:         withProxy(direct, cfg.Proxy),
+		pollClient:     withProxy(&http.Client{Timeout: 35 * time.Second}, cfg.Proxy),
 		lastSent:       map[string]time.Time{},
 		alertActive:    map[string]bool{},
 		alertStartTime: map[string]time.Time{},
+		activeAlerts:   map[string]*activeAlert{},
+		breaches:       map[string]int{},
 		startTime:      time.Now(),
 		pendingRemove:  map[int64]*removeSession{},
 	}
@@ -167,8 +365,9 @@ func (m *Manager) Start(ctx context.Context) {
 	m.pollCtx = ctx
 	m.pollStop = stopCh
 
-	m.wg.Add(1)
+	m.wg.Add(2)
 	go m.loop(ctx, stopCh)
+	go m.retryLoop(ctx, stopCh)
 
 	if m.telegram.BotToken != "" {
 		m.startPollLoopLocked(ctx, stopCh)
@@ -208,10 +407,16 @@ func (m *Manager) UpdateTelegramConfig(cfg TelegramConfig) {
 
 	m.mu.Lock()
 	oldToken := m.telegram.BotToken
+	if cfg.Proxy != m.telegram.Proxy {
+		m.client = withProxy(m.direct, cfg.Proxy)
+		m.pollClient = withProxy(&http.Client{Timeout: 35 * time.Second}, cfg.Proxy)
+	}
 	m.telegram = cfg
-	if !cfg.Enabled {
+	if !cfg.Enabled && len(m.channels) == 0 {
 		m.alertActive = map[string]bool{}
 		m.alertStartTime = map[string]time.Time{}
+		m.activeAlerts = map[string]*activeAlert{}
+		m.breaches = map[string]int{}
 	}
 
 	needStartPoll := cfg.Enabled && cfg.BotToken != "" && !m.pollRunning && m.pollCtx != nil
@@ -225,38 +430,24 @@ func (m *Manager) UpdateTelegramConfig(cfg TelegramConfig) {
 	}
 }
 
-// SendTelegramTest delivers a test message using the current configuration.
-func (m *Manager) SendTelegramTest(ctx context.Context, message string) error {
-	cfg := m.getTelegramConfig()
-	if cfg.BotToken == "" || cfg.ChatID == "" {
-		return fmt.Errorf("telegram configuration incomplete")
-	}
-
-	msg := strings.TrimSpace(message)
-	if msg == "" {
-		msg = "AdGuard Home test notification"
-	}
-
-	formattedMsg := fmt.Sprintf("🔔 <b>Telegram Test Notification</b>\n%s\n\n💬 <code>%s</code>\n\n%s", divider(), msg, timestampLine())
-	return m.sendTelegram(ctx, cfg, formattedMsg)
-}
-
 // NotifyFilterUpdate sends a formatted Telegram message describing a filter
 // refresh event.
 func (m *Manager) NotifyFilterUpdate(ctx context.Context, update FilterUpdate) {
 	cfg := m.getTelegramConfig()
-	if !cfg.Enabled || cfg.BotToken == "" || cfg.ChatID == "" {
+	if !m.isReady(cfg) {
 		return
 	}
 
-	info := systeminfo.Collect()
-	msg := composeFilterUpdateMessage(cfg, update, info)
+	info := systeminfo.Collect(ctx)
+	msg := composeFilterUpdateMessage(cfg, m.getLocale(), update, info)
 	if msg == "" {
 		return
 	}
 
-	if err := m.sendTelegramWithRetry(ctx, cfg, msg); err != nil {
-		m.logger.Error("telegram filter update failed",
+	ev := &event{time: time.Now(), typ: eventTypeFilterUpdate, filter: &update, text: msg}
+	m.applyTemplate(ev, newTemplateData(cfg, ev, info))
+	if err := m.deliver(ctx, cfg, ev); err != nil {
+		m.logger.Error("filter update notification failed",
 			"list_type", string(update.ListType),
 			"name", update.Name,
 			slog.String("error", err.Error()),
@@ -276,18 +467,22 @@ type CertExpiryReminder struct {
 // nearing expiration and should be renewed.
 func (m *Manager) NotifyCertExpiry(ctx context.Context, ev CertExpiryReminder) {
 	cfg := m.getTelegramConfig()
-	if !cfg.Enabled || cfg.BotToken == "" || cfg.ChatID == "" {
+	if !m.isReady(cfg) {
 		return
 	}
 
-	info := systeminfo.Collect()
+	info := systeminfo.Collect(ctx)
 	msg := composeCertExpiryMessage(cfg, ev, info)
 	if msg == "" {
 		return
 	}
 
-	if err := m.sendTelegramWithRetry(ctx, cfg, msg); err != nil {
-		m.logger.Error("telegram cert expiry reminder failed", slog.String("error", err.Error()))
+	certEv := &event{time: time.Now(), typ: eventTypeCertExpiry, text: msg}
+	data := newTemplateData(cfg, certEv, info)
+	data.CertExpiry = &ev
+	m.applyTemplate(certEv, data)
+	if err := m.deliver(ctx, cfg, certEv); err != nil {
+		m.logger.Error("cert expiry reminder failed", slog.String("error", err.Error()))
 	}
 }
 
@@ -303,18 +498,22 @@ type CertRenewalResult struct {
 // automatic ACME certificate renewal.
 func (m *Manager) NotifyCertRenewal(ctx context.Context, ev CertRenewalResult) {
 	cfg := m.getTelegramConfig()
-	if !cfg.Enabled || cfg.BotToken == "" || cfg.ChatID == "" {
+	if !m.isReady(cfg) {
 		return
 	}
 
-	info := systeminfo.Collect()
+	info := systeminfo.Collect(ctx)
 	msg := composeCertRenewalMessage(cfg, ev, info)
 	if msg == "" {
 		return
 	}
 
-	if err := m.sendTelegramWithRetry(ctx, cfg, msg); err != nil {
-		m.logger.Error("telegram cert renewal notification failed", slog.String("error", err.Error()))
+	certEv := &event{time: time.Now(), typ: eventTypeCertRenewal, text: msg}
+	data := newTemplateData(cfg, certEv, info)
+	data.CertRenewal = &ev
+	m.applyTemplate(certEv, data)
+	if err := m.deliver(ctx, cfg, certEv); err != nil {
+		m.logger.Error("cert renewal notification failed", slog.String("error", err.Error()))
 	}
 }
 
@@ -327,7 +526,8 @@ func (m *Manager) ValidateBotToken(ctx context.Context, token string) (string, e
 		return "", fmt.Errorf("create getMe request: %w", err)
 	}
 
-	resp, err := m.client.Do(req)
+	client, _ := m.telegramClients()
+	resp, err := client.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("getMe request: %w", err)
 	}
@@ -389,12 +589,15 @@ func (m *Manager) getCheckInterval() time.Duration {
 }
 
 func (m *Manager) runCheck(ctx context.Context) {
+	m.touchLifecycle()
+	m.checkHeartbeat(ctx)
+
 	cfg := m.getTelegramConfig()
-	if !cfg.Enabled || cfg.BotToken == "" || cfg.ChatID == "" {
+	if !m.isReady(cfg) {
 		return
 	}
 
-	info := systeminfo.Collect()
+	info := systeminfo.Collect(ctx)
 
 	// Update I/O rates from delta.
 	m.updateIOSnapshot(info)
@@ -402,12 +605,32 @@ func (m *Manager) runCheck(ctx context.Context) {
 	m.handleMetric(ctx, cfg, "cpu", info.CPUUsage, cfg.CPUThreshold, info)
 	m.handleMetric(ctx, cfg, "memory", info.MemoryUsage, cfg.MemoryThreshold, info)
 	m.handleMetric(ctx, cfg, "disk", info.DiskUsage, cfg.DiskThreshold, info)
+	m.handleMetric(ctx, cfg, "swap", info.SwapUsage, cfg.SwapThreshold, info)
+	m.checkDiskPaths(ctx, cfg, info)
+	m.checkDiskHealth(ctx, cfg, info)
+	m.checkPower(ctx, cfg, info)
+	m.checkSelfMonitor(ctx, cfg, info)
+	m.checkPublicIP(ctx, cfg, info)
+	m.checkInterfaceChanges(ctx, cfg, info)
+	m.handleMetric(ctx, cfg, metricTemperature, info.CPUTemp, cfg.TemperatureThreshold, info)
+	m.checkBandwidth(ctx, cfg, info)
+	m.checkDNSPerf(ctx, cfg, info)
 
 	// Check protection status.
 	m.checkProtectionAlert(ctx, cfg, info)
 
 	// Check YouTube ad-blocking route server health.
 	m.checkYouTubeAlert(ctx, cfg, info)
+
+	m.checkBlockedSpikes(ctx, cfg)
+	m.checkStatsReport(ctx, cfg)
+
+	m.checkEscalations(ctx, cfg)
+
+	m.sendSnapshots(ctx, &info)
+	m.flushQuietQueue(ctx, cfg)
+	m.flushDigest(ctx, cfg)
+	m.flushRateLimit(ctx, cfg)
 }
 
 // checkProtectionAlert sends an alert if DNS protection is disabled.
@@ -426,14 +649,18 @@ func (m *Manager) checkProtectionAlert(ctx context.Context, cfg TelegramConfig,
 		m.mu.RUnlock()
 
 		if !alreadyAlerted {
-			msg := composeProtectionAlertMessage(cfg, info)
-			if err := m.sendTelegramWithRetry(ctx, cfg, msg); err != nil {
-				m.logger.Error("telegram protection alert failed", slog.String("error", err.Error()))
+			msg := composeProtectionAlertMessage(cfg, m.getLocale(), info)
+			ev := &event{time: time.Now(), typ: eventTypeAlert, metric: "protection", text: msg}
+			m.applyTemplate(ev, newTemplateData(cfg, ev, info))
+			if err := m.deliver(ctx, cfg, ev); err != nil {
+				m.logger.Error("protection alert failed", slog.String("error", err.Error()))
 			} else {
 				m.mu.Lock()
 				m.alertActive["protection"] = true
 				m.alertStartTime["protection"] = time.Now()
 				m.mu.Unlock()
+
+				m.trackAlert(ev)
 			}
 		}
 	} else {
@@ -465,7 +692,7 @@ func (m *Manager) checkYouTubeAlert(ctx context.Context, cfg TelegramConfig, inf
 
 	status := yp.GetYouTubeStatus()
 	if !status.Enabled || !status.Active || status.TotalIPs == 0 {
-		m.clearAlert(youtubeAlertMetric)
+		m.clearAlert(ctx, youtubeAlertMetric)
 
 		return
 	}
@@ -477,13 +704,17 @@ func (m *Manager) checkYouTubeAlert(ctx context.Context, cfg TelegramConfig, inf
 
 		if !alreadyAlerted {
 			msg := composeYouTubeAlertMessage(cfg, status, info)
-			if err := m.sendTelegramWithRetry(ctx, cfg, msg); err != nil {
-				m.logger.Error("telegram youtube alert failed", slog.String("error", err.Error()))
+			ev := &event{time: time.Now(), typ: eventTypeAlert, metric: youtubeAlertMetric, text: msg}
+			m.applyTemplate(ev, newTemplateData(cfg, ev, info))
+			if err := m.deliver(ctx, cfg, ev); err != nil {
+				m.logger.Error("youtube alert failed", slog.String("error", err.Error()))
 			} else {
 				m.mu.Lock()
 				m.alertActive[youtubeAlertMetric] = true
 				m.alertStartTime[youtubeAlertMetric] = time.Now()
 				m.mu.Unlock()
+
+				m.trackAlert(ev)
 			}
 		}
 
@@ -582,20 +813,22 @@ func (m *Manager) GetIOStats() IOStats {
 
 func (m *Manager) handleMetric(ctx context.Context, cfg TelegramConfig, metric string, value, threshold float64, info systeminfo.Info) {
 	if threshold <= 0 || value <= 0 {
+		m.resetBreaches(metric)
 		m.clearAlertWithRecovery(ctx, cfg, metric, value, threshold, info)
 		return
 	}
 
 	active, last := m.metricState(metric)
-	cooldown := cfg.Cooldown
-	if cooldown <= 0 {
-		cooldown = defaultCooldown
-	}
+	cooldown := cfg.cooldown(metric)
 
 	if value >= threshold {
+		if !m.isSustained(cfg, metric) {
+			return
+		}
+
 		if !active && time.Since(last) >= cooldown {
 			if err := m.sendAlert(ctx, cfg, metric, value, threshold, info); err != nil {
-				m.logger.Error("telegram alert failed",
+				m.logger.Error("alert failed",
 					"metric", metric,
 					slog.String("error", err.Error()),
 				)
@@ -612,47 +845,77 @@ func (m *Manager) handleMetric(ctx context.Context, cfg TelegramConfig, metric s
 		return
 	}
 
-	if active && value < threshold*resetFactor {
+	m.resetBreaches(metric)
+	if active && value < cfg.resetThreshold(metric, threshold) {
 		m.clearAlertWithRecovery(ctx, cfg, metric, value, threshold, info)
 	}
 }
 
-func (m *Manager) sendAlert(ctx context.Context, cfg TelegramConfig, metric string, value, threshold float64, info systeminfo.Info) error {
-	message := composeAlertMessage(cfg, metric, value, threshold, info)
-	return m.sendTelegramWithRetry(ctx, cfg, message)
+// isSustained counts the check, which metric is above its threshold during,
+// and returns true if the metric has been above it for at least the number of
+// the consecutive checks required by cfg.
+func (m *Manager) isSustained(cfg TelegramConfig, metric string) (ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.breaches[metric]++
+
+	return m.breaches[metric] >= cfg.SustainedChecks
 }
 
-// sendTelegramWithRetry attempts to send a message with exponential backoff.
-func (m *Manager) sendTelegramWithRetry(ctx context.Context, cfg TelegramConfig, msg string) error {
-	delays := []time.Duration{1 * time.Second, 3 * time.Second, 10 * time.Second}
-	var lastErr error
+// resetBreaches resets the number of the consecutive checks, which metric has
+// been above its threshold during.
+func (m *Manager) resetBreaches(metric string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	// First attempt without delay.
-	if lastErr = m.sendTelegram(ctx, cfg, msg); lastErr == nil {
-		return nil
-	}
+	delete(m.breaches, metric)
+}
 
-	for _, delay := range delays {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(delay):
-		}
+func (m *Manager) sendAlert(ctx context.Context, cfg TelegramConfig, metric string, value, threshold float64, info systeminfo.Info) (err error) {
+	message := composeAlertMessage(cfg, m.getLocale(), metric, value, threshold, info)
 
-		if lastErr = m.sendTelegram(ctx, cfg, msg); lastErr == nil {
-			return nil
-		}
+	ev := &event{
+		time:      time.Now(),
+		typ:       eventTypeAlert,
+		metric:    metric,
+		text:      message,
+		value:     value,
+		threshold: threshold,
+	}
+	m.applyTemplate(ev, newTemplateData(cfg, ev, info))
+
+	err = m.deliver(ctx, cfg, ev)
+	if err == nil {
+		m.trackAlert(ev)
 	}
 
-	return lastErr
+	return err
+}
+
+// sendTelegramWithRetry attempts to send a message with exponential backoff.
+func (m *Manager) sendTelegramWithRetry(ctx context.Context, cfg TelegramConfig, msg string) error {
+	return withRetry(ctx, func() error { return m.sendTelegram(ctx, cfg, msg) })
 }
 
 func (m *Manager) sendTelegram(ctx context.Context, cfg TelegramConfig, message string) error {
+	return m.sendTelegramWithKeyboard(ctx, cfg, message, nil)
+}
+
+// sendTelegramWithKeyboard sends the notification message with the inline
+// keyboard kb attached, if it's not nil.
+func (m *Manager) sendTelegramWithKeyboard(
+	ctx context.Context,
+	cfg TelegramConfig,
+	message string,
+	kb *tgInlineKeyboardMarkup,
+) error {
 	trimmed := strings.TrimSpace(message)
 	if trimmed == "" {
 		return nil
 	}
 
+	trimmed, parseMode := formatTelegramMessage(trimmed, cfg.ParseMode)
 	if len(trimmed) > telegramMaxMessageLen {
 		trimmed = trimmed[:telegramMaxMessageLen]
 	}
@@ -661,8 +924,22 @@ func (m *Manager) sendTelegram(ctx context.Context, cfg TelegramConfig, message
 
 	data := url.Values{}
 	data.Set("chat_id", cfg.ChatID)
+	if cfg.MessageThreadID > 0 {
+		data.Set("message_thread_id", strconv.FormatInt(cfg.MessageThreadID, 10))
+	}
 	data.Set("text", trimmed)
-	data.Set("parse_mode", "HTML")
+	if parseMode != "" {
+		data.Set("parse_mode", parseMode)
+	}
+
+	if kb != nil {
+		markup, err := encodeReplyMarkup(kb)
+		if err != nil {
+			return err
+		}
+
+		data.Set("reply_markup", markup)
+	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(data.Encode()))
 	if err != nil {
@@ -671,15 +948,19 @@ func (m *Manager) sendTelegram(ctx context.Context, cfg TelegramConfig, message
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := m.client.Do(req)
+	client, _ := m.telegramClients()
+	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, _ := io.ReadAll(io.LimitReader(resp.Body, telegramMaxMessageLen))
+	traceResponse(ctx, resp.StatusCode, body)
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return newTelegramRateLimitError(body)
+	} else if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("telegram api status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
 	}
 
@@ -738,11 +1019,25 @@ func (m *Manager) clearAlertWithRecovery(ctx context.Context, cfg TelegramConfig
 	startTime := m.alertStartTime[metric]
 	m.mu.RUnlock()
 
+	escalatedTo := m.untrackAlert(metric)
 	if wasActive {
 		duration := time.Since(startTime).Truncate(time.Second)
-		msg := composeRecoveryMessage(cfg, metric, currentValue, threshold, duration, info)
-		if err := m.sendTelegramWithRetry(ctx, cfg, msg); err != nil {
-			m.logger.Debug("telegram recovery message failed", slog.String("error", err.Error()))
+		msg := composeRecoveryMessage(cfg, m.getLocale(), metric, currentValue, threshold, duration, info)
+		ev := &event{
+			time:      time.Now(),
+			typ:       eventTypeRecovery,
+			metric:    metric,
+			text:      msg,
+			value:     currentValue,
+			threshold: threshold,
+		}
+
+		data := newTemplateData(cfg, ev, info)
+		data.Duration = duration
+		m.applyTemplate(ev, data)
+		ev.targets = m.withEscalationTargets(ev, escalatedTo)
+		if err := m.deliver(ctx, cfg, ev); err != nil {
+			m.logger.Debug("recovery message failed", slog.String("error", err.Error()))
 		}
 	}
 
@@ -752,7 +1047,16 @@ func (m *Manager) clearAlertWithRecovery(ctx context.Context, cfg TelegramConfig
 	m.mu.Unlock()
 }
 
-func (m *Manager) clearAlert(metric string) {
+// clearAlert clears the alert without a recovery notification.  The channels
+// tracking the state of the alerts are notified if the alert was active.
+func (m *Manager) clearAlert(ctx context.Context, metric string) {
+	active, _ := m.metricState(metric)
+	if active {
+		m.resolveIncidents(ctx, metric)
+	}
+
+	_ = m.untrackAlert(metric)
+
 	m.updateMetricState(metric, false, time.Time{})
 }
 
@@ -763,6 +1067,15 @@ func (m *Manager) getTelegramConfig() TelegramConfig {
 	return m.telegram
 }
 
+// telegramClients returns the HTTP clients for the Telegram Bot API requests
+// and for the long polling of the updates.
+func (m *Manager) telegramClients() (client, pollClient *http.Client) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.client, m.pollClient
+}
+
 func (m *Manager) pollLoop(ctx context.Context, stop <-chan struct{}) {
 	defer func() {
 		m.mu.Lock()
@@ -856,6 +1169,12 @@ func (m *Manager) handleUpdate(ctx context.Context, cfg TelegramConfig, u tgUpda
 		return
 	}
 
+	if strings.HasPrefix(command, alertActionPrefix) {
+		m.handleAlertAction(ctx, cfg, chatID, messageID, command)
+
+		return
+	}
+
 	switch command {
 	case "/start", "/menu", "cmd:menu":
 		m.sendMainMenu(ctx, cfg, chatID, messageID)
@@ -936,7 +1255,7 @@ func extractCommand(text string) string {
 }
 
 func (m *Manager) sendMainMenu(ctx context.Context, cfg TelegramConfig, chatID int64, messageID int64) {
-	info := systeminfo.Collect()
+	info := systeminfo.Collect(ctx)
 
 	protOn := true
 	m.mu.RLock()
@@ -963,7 +1282,7 @@ func (m *Manager) sendMainMenu(ctx context.Context, cfg TelegramConfig, chatID i
 }
 
 func (m *Manager) sendSystemStatus(ctx context.Context, cfg TelegramConfig, chatID int64, messageID int64) {
-	info := systeminfo.Collect()
+	info := systeminfo.Collect(ctx)
 	ioStats := m.GetIOStats()
 	text := composeSystemStatusMessage(info, ioStats)
 	kb := backToMenuKeyboard()
@@ -1216,7 +1535,7 @@ func (m *Manager) issueCertNow(ctx context.Context, cfg TelegramConfig, chatID i
 }
 
 func (m *Manager) sendProcessInfo(ctx context.Context, cfg TelegramConfig, chatID int64, messageID int64) {
-	info := systeminfo.Collect()
+	info := systeminfo.Collect(ctx)
 	text := composeProcessInfoMessage(m.startTime, info)
 	kb := backToMenuKeyboard()
 
@@ -1704,7 +2023,35 @@ func (m *Manager) handleTextCommand(ctx context.Context, cfg TelegramConfig, cha
 		}
 		m.handleEnableList(ctx, cfg, chatID, parts[1], false, false)
 
+	case "/wake":
+		if len(parts) < 2 {
+			_ = m.sendTelegram(ctx, cfg, "ℹ️ <b>Usage: Wake Client</b>\n"+divider()+"\n\n<code>/wake &lt;client name&gt;</code>")
+			return
+		}
+		m.handleWakeClient(ctx, cfg, strings.Join(parts[1:], " "))
+
+	}
+}
+
+// handleWakeClient sends a Wake-on-LAN magic packet to the persistent client
+// with the given name.
+func (m *Manager) handleWakeClient(ctx context.Context, cfg TelegramConfig, name string) {
+	m.mu.RLock()
+	cw := m.waker
+	m.mu.RUnlock()
+
+	if cw == nil {
+		_ = m.sendTelegram(ctx, cfg, "⚠️ <b>Wake-on-LAN not available.</b>")
+		return
+	}
+
+	var msg string
+	if err := cw.WakeClient(ctx, name); err != nil {
+		msg = fmt.Sprintf("❌ <b>Failed to Wake Client</b>\n"+divider()+"\n\n<b>Client:</b> %s\n<b>Error:</b> <code>%s</code>\n\n%s", html.EscapeString(name), html.EscapeString(err.Error()), timestampLine())
+	} else {
+		msg = fmt.Sprintf("⏰ <b>Wake-on-LAN Packet Sent</b>\n"+divider()+"\n\n<b>Client:</b> %s\n\n%s", html.EscapeString(name), timestampLine())
 	}
+	_ = m.sendTelegram(ctx, cfg, msg)
 }
 
 // handleAddLists adds one or more filter lists. Supports formats:
@@ -0,0 +1,41 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+)
+
+func TestManager_handleMetric_sustained(t *testing.T) {
+	rec := &recordingChannel{chanName: "matrix"}
+
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	m.channels = []channel{rec}
+
+	cfg := TelegramConfig{
+		SustainedChecks: 3,
+	}
+
+	ctx := context.Background()
+
+	// A single spike and a short one don't fire the alert.
+	for _, v := range []float64{95, 50, 95, 95, 50} {
+		m.handleMetric(ctx, cfg, "cpu", v, 90, systeminfo.Info{})
+	}
+
+	if len(rec.got) != 0 {
+		t.Fatalf("unexpected events %q", rec.got)
+	}
+
+	for _, v := range []float64{95, 95, 95, 95} {
+		m.handleMetric(ctx, cfg, "cpu", v, 90, systeminfo.Info{})
+	}
+
+	want := []eventType{eventTypeAlert}
+	if !slices.Equal(rec.got, want) {
+		t.Errorf("got %q, want %q", rec.got, want)
+	}
+}
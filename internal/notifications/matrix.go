@@ -0,0 +1,98 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// MatrixConfig contains runtime configuration for Matrix notifications.
+type MatrixConfig struct {
+	Enabled       bool
+	HomeserverURL string
+	AccessToken   string
+	RoomID        string
+	Proxy         string
+}
+
+// matrixChannel delivers notifications to a Matrix room using the
+// client-server API.
+type matrixChannel struct {
+	client *http.Client
+	conf   MatrixConfig
+
+	// txnPrefix and txnCounter make up the unique transaction IDs required by
+	// the homeserver to deduplicate retried requests.
+	txnPrefix  string
+	txnCounter atomic.Uint64
+}
+
+// newMatrixChannel returns a new Matrix channel.
+func newMatrixChannel(client *http.Client, conf MatrixConfig) (c *matrixChannel) {
+	return &matrixChannel{
+		client:    client,
+		conf:      conf,
+		txnPrefix: "agh" + strconv.FormatInt(time.Now().UnixNano(), 36),
+	}
+}
+
+// type check
+var _ channel = (*matrixChannel)(nil)
+
+// name implements the [channel] interface for *matrixChannel.
+func (c *matrixChannel) name() (n string) { return "matrix" }
+
+// matrixMessage is the content of the m.room.message event.
+type matrixMessage struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format"`
+	FormattedBody string `json:"formatted_body"`
+}
+
+// send implements the [channel] interface for *matrixChannel.
+func (c *matrixChannel) send(ctx context.Context, ev *event) (err error) {
+	txnID := c.txnPrefix + "." + strconv.FormatUint(c.txnCounter.Add(1), 10)
+	endpoint := strings.TrimSuffix(c.conf.HomeserverURL, "/") + "/_matrix/client/v3/rooms/" +
+		url.PathEscape(c.conf.RoomID) + "/send/m.room.message/" + txnID
+
+	body, err := json.Marshal(matrixMessage{
+		MsgType:       "m.text",
+		Body:          htmlToText(ev.text),
+		Format:        "org.matrix.custom.html",
+		FormattedBody: strings.ReplaceAll(ev.text, "\n", "<br>"),
+	})
+	if err != nil {
+		return fmt.Errorf("marshal matrix message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.conf.AccessToken)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	traceResponse(ctx, resp.StatusCode, respBody)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("matrix api status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMatrixChannel_Send(t *testing.T) {
+	var (
+		gotPath string
+		gotAuth string
+		gotMsg  matrixMessage
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		gotAuth = r.Header.Get("Authorization")
+
+		if err := json.NewDecoder(r.Body).Decode(&gotMsg); err != nil {
+			t.Errorf("decoding request: %s", err)
+		}
+
+		_, _ = w.Write([]byte(`{"event_id":"$1"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	c := newMatrixChannel(srv.Client(), MatrixConfig{
+		Enabled:       true,
+		HomeserverURL: srv.URL + "/",
+		AccessToken:   "token",
+		RoomID:        "!room:example.org",
+	})
+
+	err := c.send(context.Background(), &event{text: "<b>ALERT</b>\n&lt;cpu&gt;"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	wantPrefix := "/_matrix/client/v3/rooms/%21room:example.org/send/m.room.message/"
+	if !strings.HasPrefix(gotPath, wantPrefix) || gotPath == wantPrefix {
+		t.Errorf("unexpected path %q", gotPath)
+	}
+
+	if gotAuth != "Bearer token" {
+		t.Errorf("unexpected authorization %q", gotAuth)
+	}
+
+	if gotMsg.Body != "ALERT\n<cpu>" {
+		t.Errorf("unexpected body %q", gotMsg.Body)
+	}
+
+	if gotMsg.FormattedBody != "<b>ALERT</b><br>&lt;cpu&gt;" {
+		t.Errorf("unexpected formatted body %q", gotMsg.FormattedBody)
+	}
+}
+
+func TestMatrixChannel_Send_error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"errcode":"M_FORBIDDEN"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	c := newMatrixChannel(srv.Client(), MatrixConfig{HomeserverURL: srv.URL, RoomID: "!room"})
+
+	err := c.send(context.Background(), &event{text: "test"})
+	if err == nil || !strings.Contains(err.Error(), "M_FORBIDDEN") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,334 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+)
+
+// Default MQTT settings.
+const (
+	defaultMQTTTopicPrefix = "adguardhome"
+	defaultMQTTPort        = "1883"
+	defaultMQTTTLSPort     = "8883"
+
+	// mqttTimeout is the timeout for the whole exchange with the broker.
+	mqttTimeout = 10 * time.Second
+
+	// mqttKeepAlive is the keep alive interval sent in the CONNECT packet, in
+	// seconds.
+	mqttKeepAlive = 60
+
+	// mqttSnapshotTopic is the subtopic of the system snapshots.
+	mqttSnapshotTopic = "system"
+)
+
+// MQTT control packet types and flags, see the MQTT 3.1.1 specification.
+const (
+	mqttPacketConnect    byte = 0x10
+	mqttPacketConnAck    byte = 0x20
+	mqttPacketPublish    byte = 0x30
+	mqttPacketPubAck     byte = 0x40
+	mqttPacketDisconnect byte = 0xE0
+
+	mqttFlagRetain       byte = 0x01
+	mqttFlagCleanSession byte = 0x02
+	mqttFlagPassword     byte = 0x40
+	mqttFlagUsername     byte = 0x80
+
+	mqttProtocolLevel byte = 4
+)
+
+// MQTTConfig contains runtime configuration for MQTT publishing.  The events
+// are published to the subtopics of TopicPrefix named after the event types,
+// for example "adguardhome/alert", and the system snapshots are published to
+// the "system" subtopic on each check.  QoS must be 0 or 1.
+type MQTTConfig struct {
+	Enabled     bool
+	BrokerURL   string
+	ClientID    string
+	Username    string
+	Password    string
+	TopicPrefix string
+	QoS         byte
+	Retain      bool
+}
+
+// mqttChannel publishes the notifications as JSON to an MQTT broker.  It
+// connects to the broker for each message, since the notifications are rare.
+type mqttChannel struct {
+	conf MQTTConfig
+}
+
+// newMQTTChannel returns a new MQTT channel.
+func newMQTTChannel(conf MQTTConfig) (c *mqttChannel) {
+	conf.TopicPrefix = strings.TrimSuffix(conf.TopicPrefix, "/")
+	if conf.TopicPrefix == "" {
+		conf.TopicPrefix = defaultMQTTTopicPrefix
+	}
+
+	if conf.ClientID == "" {
+		host, _ := os.Hostname()
+		conf.ClientID = "adguardhome-" + host
+	}
+
+	return &mqttChannel{
+		conf: conf,
+	}
+}
+
+// type check
+var _ snapshotChannel = (*mqttChannel)(nil)
+
+// name implements the [channel] interface for *mqttChannel.
+func (c *mqttChannel) name() (n string) { return "mqtt" }
+
+// mqttFilter is the JSON representation of [FilterUpdate].
+type mqttFilter struct {
+	Name       string `json:"name"`
+	URL        string `json:"url"`
+	ListType   string `json:"list_type"`
+	ID         uint64 `json:"id"`
+	RulesCount int    `json:"rules_count"`
+	Enabled    bool   `json:"enabled"`
+}
+
+// mqttEvent is the JSON representation of [event].
+type mqttEvent struct {
+	Time      time.Time   `json:"time"`
+	Filter    *mqttFilter `json:"filter,omitempty"`
+	Type      string      `json:"type"`
+	Title     string      `json:"title"`
+	Message   string      `json:"message"`
+	Metric    string      `json:"metric,omitempty"`
+	Value     float64     `json:"value,omitempty"`
+	Threshold float64     `json:"threshold,omitempty"`
+}
+
+// send implements the [channel] interface for *mqttChannel.
+func (c *mqttChannel) send(ctx context.Context, ev *event) (err error) {
+	msg := &mqttEvent{
+		Time:      ev.time,
+		Type:      string(ev.typ),
+		Title:     ev.title(),
+		Message:   htmlToText(ev.text),
+		Metric:    ev.metric,
+		Value:     ev.value,
+		Threshold: ev.threshold,
+	}
+
+	if f := ev.filter; f != nil {
+		msg.Filter = &mqttFilter{
+			Name:       f.Name,
+			URL:        f.URL,
+			ListType:   string(f.ListType),
+			ID:         f.ID,
+			RulesCount: f.RulesCount,
+			Enabled:    f.Enabled,
+		}
+	}
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal mqtt event: %w", err)
+	}
+
+	return c.publish(ctx, c.conf.TopicPrefix+"/"+string(ev.typ), payload)
+}
+
+// sendSnapshot implements the [snapshotChannel] interface for *mqttChannel.
+func (c *mqttChannel) sendSnapshot(ctx context.Context, info *systeminfo.Info) (err error) {
+	payload, err := json.Marshal(info)
+	if err != nil {
+		return fmt.Errorf("marshal system info: %w", err)
+	}
+
+	return c.publish(ctx, c.conf.TopicPrefix+"/"+mqttSnapshotTopic, payload)
+}
+
+// publish connects to the broker, publishes payload to topic, and
+// disconnects.
+func (c *mqttChannel) publish(ctx context.Context, topic string, payload []byte) (err error) {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("connecting to broker: %w", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(mqttTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	err = conn.SetDeadline(deadline)
+	if err != nil {
+		return fmt.Errorf("setting deadline: %w", err)
+	}
+
+	_, err = conn.Write(c.connectPacket())
+	if err != nil {
+		return fmt.Errorf("writing connect: %w", err)
+	}
+
+	ack := make([]byte, 4)
+	_, err = io.ReadFull(conn, ack)
+	if err != nil {
+		return fmt.Errorf("reading connack: %w", err)
+	} else if ack[0] != mqttPacketConnAck {
+		return fmt.Errorf("unexpected packet type %#x, want connack", ack[0])
+	} else if ack[3] != 0 {
+		return fmt.Errorf("connection refused by broker with code %d", ack[3])
+	}
+
+	const packetID = 1
+	_, err = conn.Write(publishPacket(topic, payload, c.conf.QoS, c.conf.Retain, packetID))
+	if err != nil {
+		return fmt.Errorf("writing publish: %w", err)
+	}
+
+	if c.conf.QoS > 0 {
+		_, err = io.ReadFull(conn, ack)
+		if err != nil {
+			return fmt.Errorf("reading puback: %w", err)
+		} else if ack[0] != mqttPacketPubAck || binary.BigEndian.Uint16(ack[2:]) != packetID {
+			return fmt.Errorf("unexpected packet %x, want puback", ack)
+		}
+	}
+
+	_, err = conn.Write([]byte{mqttPacketDisconnect, 0})
+	if err != nil {
+		return fmt.Errorf("writing disconnect: %w", err)
+	}
+
+	return nil
+}
+
+// dial connects to the broker.  The supported URL schemes are "tcp" and
+// "mqtt" for plain connections, and "ssl", "tls", and "mqtts" for TLS ones.
+func (c *mqttChannel) dial(ctx context.Context) (conn net.Conn, err error) {
+	u, err := url.Parse(c.conf.BrokerURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing broker url: %w", err)
+	}
+
+	isTLS := false
+	port := defaultMQTTPort
+	switch u.Scheme {
+	case "tcp", "mqtt":
+		// Go on.
+	case "ssl", "tls", "mqtts":
+		isTLS = true
+		port = defaultMQTTTLSPort
+	default:
+		return nil, fmt.Errorf("unsupported broker url scheme %q", u.Scheme)
+	}
+
+	if p := u.Port(); p != "" {
+		port = p
+	}
+
+	addr := net.JoinHostPort(u.Hostname(), port)
+	dialer := &net.Dialer{Timeout: mqttTimeout}
+	if !isTLS {
+		return dialer.DialContext(ctx, "tcp", addr)
+	}
+
+	tlsDialer := &tls.Dialer{
+		NetDialer: dialer,
+		Config: &tls.Config{
+			ServerName: u.Hostname(),
+			MinVersion: tls.VersionTLS12,
+		},
+	}
+
+	return tlsDialer.DialContext(ctx, "tcp", addr)
+}
+
+// connectPacket returns the CONNECT packet with the credentials from the
+// configuration.
+func (c *mqttChannel) connectPacket() (pkt []byte) {
+	flags := mqttFlagCleanSession
+	body := &bytes.Buffer{}
+	writeMQTTString(body, "MQTT")
+	body.WriteByte(mqttProtocolLevel)
+
+	if c.conf.Username != "" {
+		flags |= mqttFlagUsername
+		if c.conf.Password != "" {
+			flags |= mqttFlagPassword
+		}
+	}
+
+	body.WriteByte(flags)
+	_ = binary.Write(body, binary.BigEndian, uint16(mqttKeepAlive))
+
+	writeMQTTString(body, c.conf.ClientID)
+	if flags&mqttFlagUsername != 0 {
+		writeMQTTString(body, c.conf.Username)
+	}
+
+	if flags&mqttFlagPassword != 0 {
+		writeMQTTString(body, c.conf.Password)
+	}
+
+	return mqttPacket(mqttPacketConnect, body.Bytes())
+}
+
+// publishPacket returns the PUBLISH packet.  packetID is only used if qos is
+// greater than zero.
+func publishPacket(topic string, payload []byte, qos byte, retain bool, packetID uint16) (pkt []byte) {
+	hdr := mqttPacketPublish | qos<<1
+	if retain {
+		hdr |= mqttFlagRetain
+	}
+
+	body := &bytes.Buffer{}
+	writeMQTTString(body, topic)
+	if qos > 0 {
+		_ = binary.Write(body, binary.BigEndian, packetID)
+	}
+
+	body.Write(payload)
+
+	return mqttPacket(hdr, body.Bytes())
+}
+
+// mqttPacket returns the packet with the fixed header hdr and the remaining
+// data body.
+func mqttPacket(hdr byte, body []byte) (pkt []byte) {
+	pkt = append(make([]byte, 0, len(body)+5), hdr)
+
+	// Encode the remaining length, seven bits per byte.
+	n := len(body)
+	for {
+		b := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			b |= 0x80
+		}
+
+		pkt = append(pkt, b)
+		if n == 0 {
+			break
+		}
+	}
+
+	return append(pkt, body...)
+}
+
+// writeMQTTString writes the length-prefixed UTF-8 string s into buf.
+func writeMQTTString(buf *bytes.Buffer, s string) {
+	_ = binary.Write(buf, binary.BigEndian, uint16(len(s)))
+	buf.WriteString(s)
+}
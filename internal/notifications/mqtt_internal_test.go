@@ -0,0 +1,133 @@
+package notifications
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"testing"
+)
+
+// readMQTTPacket reads a single MQTT packet from r.
+func readMQTTPacket(r *bufio.Reader) (hdr byte, body []byte, err error) {
+	hdr, err = r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	n, mul := 0, 1
+	for {
+		var b byte
+		b, err = r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		n += int(b&0x7f) * mul
+		mul *= 128
+		if b&0x80 == 0 {
+			break
+		}
+	}
+
+	body = make([]byte, n)
+	_, err = io.ReadFull(r, body)
+
+	return hdr, body, err
+}
+
+func TestMQTTChannel_Send(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %s", err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+
+	type published struct {
+		topic   string
+		payload []byte
+		hdr     byte
+	}
+
+	pubCh := make(chan published, 1)
+	go func() {
+		conn, aErr := l.Accept()
+		if aErr != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		hdr, _, rErr := readMQTTPacket(r)
+		if rErr != nil || hdr != mqttPacketConnect {
+			t.Errorf("unexpected packet %#x, want connect: %v", hdr, rErr)
+
+			return
+		}
+
+		_, _ = conn.Write([]byte{mqttPacketConnAck, 2, 0, 0})
+
+		hdr, body, rErr := readMQTTPacket(r)
+		if rErr != nil {
+			t.Errorf("reading publish: %s", rErr)
+
+			return
+		}
+
+		topicLen := int(binary.BigEndian.Uint16(body))
+		topic := string(body[2 : 2+topicLen])
+		id := body[2+topicLen : 4+topicLen]
+
+		_, _ = conn.Write([]byte{mqttPacketPubAck, 2, id[0], id[1]})
+
+		pubCh <- published{topic: topic, payload: body[4+topicLen:], hdr: hdr}
+	}()
+
+	c := newMQTTChannel(MQTTConfig{
+		Enabled:     true,
+		BrokerURL:   "tcp://" + l.Addr().String(),
+		Username:    "user",
+		Password:    "pass",
+		TopicPrefix: "home/agh/",
+		QoS:         1,
+		Retain:      true,
+	})
+
+	err = c.send(context.Background(), &event{
+		typ:       eventTypeAlert,
+		metric:    "cpu",
+		text:      "<b>cpu</b>",
+		value:     95,
+		threshold: 90,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	pub := <-pubCh
+	if pub.topic != "home/agh/alert" {
+		t.Errorf("unexpected topic %q", pub.topic)
+	}
+
+	if want := mqttPacketPublish | 1<<1 | mqttFlagRetain; pub.hdr != want {
+		t.Errorf("unexpected publish header %#x, want %#x", pub.hdr, want)
+	}
+
+	var got mqttEvent
+	if err = json.Unmarshal(pub.payload, &got); err != nil {
+		t.Fatalf("decoding payload: %s", err)
+	}
+
+	if got.Type != "alert" || got.Metric != "cpu" || got.Value != 95 || got.Threshold != 90 {
+		t.Errorf("unexpected event %+v", got)
+	}
+}
+
+func TestMQTTPacket_remainingLength(t *testing.T) {
+	pkt := mqttPacket(mqttPacketPublish, make([]byte, 321))
+	if pkt[1] != 0xC1 || pkt[2] != 0x02 || len(pkt) != 3+321 {
+		t.Errorf("unexpected header %x", pkt[:3])
+	}
+}
@@ -0,0 +1,190 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Opsgenie API endpoints by region.
+const (
+	opsgenieURLUS = "https://api.opsgenie.com"
+	opsgenieURLEU = "https://api.eu.opsgenie.com"
+)
+
+// defaultOpsgeniePriority is the default priority of the created alerts.
+const defaultOpsgeniePriority = "P3"
+
+// opsgenieMaxMessageLen is the maximum length of the alert message accepted
+// by Opsgenie.
+const opsgenieMaxMessageLen = 130
+
+// OpsgenieConfig contains runtime configuration for Opsgenie notifications.
+// Region is either "us" or "eu".  Priorities maps the metrics, like "cpu" or
+// "protection", to the priorities from "P1" to "P5", and Priority is used for
+// the metrics missing from it.
+type OpsgenieConfig struct {
+	Priorities map[string]string
+	Enabled    bool
+	APIKey     string
+	Region     string
+	Priority   string
+	Tags       []string
+	Proxy      string
+}
+
+// opsgenieChannel creates and closes Opsgenie alerts for the metric alerts
+// using the Alert API.  Other events are ignored.
+type opsgenieChannel struct {
+	client *http.Client
+	conf   OpsgenieConfig
+
+	// baseURL is the regional API endpoint.
+	baseURL string
+
+	// source is the name of the host reported as the source of the alerts.
+	source string
+}
+
+// newOpsgenieChannel returns a new Opsgenie channel.
+func newOpsgenieChannel(client *http.Client, conf OpsgenieConfig) (c *opsgenieChannel) {
+	if conf.Priority == "" {
+		conf.Priority = defaultOpsgeniePriority
+	}
+
+	baseURL := opsgenieURLUS
+	if strings.EqualFold(conf.Region, "eu") {
+		baseURL = opsgenieURLEU
+	}
+
+	source, _ := os.Hostname()
+	if source == "" {
+		source = "adguardhome"
+	}
+
+	return &opsgenieChannel{
+		client:  client,
+		conf:    conf,
+		baseURL: baseURL,
+		source:  source,
+	}
+}
+
+// type check
+var _ incidentChannel = (*opsgenieChannel)(nil)
+
+// name implements the [channel] interface for *opsgenieChannel.
+func (c *opsgenieChannel) name() (n string) { return "opsgenie" }
+
+// opsgenieAlert is the request for creating an Opsgenie alert.
+type opsgenieAlert struct {
+	Details     map[string]string `json:"details,omitempty"`
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description"`
+	Source      string            `json:"source"`
+	Priority    string            `json:"priority"`
+	Tags        []string          `json:"tags,omitempty"`
+}
+
+// opsgenieClose is the request for closing an Opsgenie alert.
+type opsgenieClose struct {
+	Source string `json:"source"`
+	Note   string `json:"note"`
+}
+
+// send implements the [channel] interface for *opsgenieChannel.
+func (c *opsgenieChannel) send(ctx context.Context, ev *event) (err error) {
+	switch ev.typ {
+	case eventTypeAlert, eventTypeTest:
+		// Go on.
+	case eventTypeRecovery:
+		return c.resolve(ctx, ev.metric)
+	default:
+		return nil
+	}
+
+	msg := fmt.Sprintf("%s on %s", ev.title(), c.source)
+	if len(msg) > opsgenieMaxMessageLen {
+		msg = msg[:opsgenieMaxMessageLen]
+	}
+
+	priority := c.conf.Priority
+	if p, ok := c.conf.Priorities[ev.metric]; ok {
+		priority = p
+	}
+
+	alert := &opsgenieAlert{
+		Message:     msg,
+		Alias:       c.alias(ev.metric),
+		Description: htmlToText(ev.text),
+		Source:      c.source,
+		Priority:    priority,
+		Tags:        slices.Clone(c.conf.Tags),
+	}
+
+	if ev.typ == eventTypeAlert {
+		alert.Details = map[string]string{
+			"metric":    ev.metric,
+			"value":     formatFloat(ev.value),
+			"threshold": formatFloat(ev.threshold),
+		}
+	} else {
+		alert.Alias = c.alias("test")
+	}
+
+	return c.post(ctx, "/v2/alerts", alert)
+}
+
+// resolve implements the [incidentChannel] interface for *opsgenieChannel.
+func (c *opsgenieChannel) resolve(ctx context.Context, metric string) (err error) {
+	path := "/v2/alerts/" + url.PathEscape(c.alias(metric)) + "/close?identifierType=alias"
+
+	return c.post(ctx, path, &opsgenieClose{
+		Source: c.source,
+		Note:   metricDisplayName(metric) + " is back to normal",
+	})
+}
+
+// alias returns the alias of the alert for metric, which is used to
+// deduplicate and close it.
+func (c *opsgenieChannel) alias(metric string) (a string) {
+	return "adguardhome/" + c.source + "/" + metric
+}
+
+// post sends v as JSON to path of the API.
+func (c *opsgenieChannel) post(ctx context.Context, path string, v any) (err error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal opsgenie request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "GenieKey "+c.conf.APIKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	traceResponse(ctx, resp.StatusCode, respBody)
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("opsgenie api status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOpsgenieChannel_Send(t *testing.T) {
+	var (
+		gotAlert opsgenieAlert
+		gotPaths []string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "GenieKey key" {
+			t.Errorf("unexpected authorization %q", auth)
+		}
+
+		gotPaths = append(gotPaths, r.URL.RequestURI())
+		if r.URL.Path == "/v2/alerts" {
+			if err := json.NewDecoder(r.Body).Decode(&gotAlert); err != nil {
+				t.Errorf("decoding request: %s", err)
+			}
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := newOpsgenieChannel(srv.Client(), OpsgenieConfig{
+		Enabled:    true,
+		APIKey:     "key",
+		Priorities: map[string]string{"protection": "P1"},
+		Tags:       []string{"dns"},
+	})
+	c.baseURL = srv.URL
+	c.source = "host"
+
+	ctx := context.Background()
+	if err := c.send(ctx, &event{typ: eventTypeAlert, metric: "protection"}); err != nil {
+		t.Fatalf("sending alert: %s", err)
+	}
+
+	if err := c.send(ctx, &event{typ: eventTypeRecovery, metric: "protection"}); err != nil {
+		t.Fatalf("sending recovery: %s", err)
+	}
+
+	if gotAlert.Priority != "P1" || gotAlert.Alias != "adguardhome/host/protection" || len(gotAlert.Tags) != 1 {
+		t.Errorf("unexpected alert %+v", gotAlert)
+	}
+
+	wantClose := "/v2/alerts/adguardhome%2Fhost%2Fprotection/close?identifierType=alias"
+	if len(gotPaths) != 2 || gotPaths[1] != wantClose {
+		t.Errorf("unexpected requests %q", gotPaths)
+	}
+}
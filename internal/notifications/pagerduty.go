@@ -0,0 +1,159 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// pagerDutyEventsURL is the endpoint of the PagerDuty Events API v2.
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// defaultPagerDutySeverity is the default severity of the triggered alerts.
+const defaultPagerDutySeverity = "error"
+
+// PagerDutyConfig contains runtime configuration for PagerDuty notifications.
+// Severity is one of "critical", "error", "warning", and "info".
+type PagerDutyConfig struct {
+	Enabled    bool
+	RoutingKey string
+	Severity   string
+	Proxy      string
+}
+
+// pagerDutyChannel triggers and resolves PagerDuty alerts for the metric
+// alerts using the Events API v2.  Other events are ignored.
+type pagerDutyChannel struct {
+	client *http.Client
+	conf   PagerDutyConfig
+
+	// url is the endpoint of the Events API.
+	url string
+
+	// source is the name of the host reported as the source of the alerts.
+	source string
+}
+
+// newPagerDutyChannel returns a new PagerDuty channel.
+func newPagerDutyChannel(client *http.Client, conf PagerDutyConfig) (c *pagerDutyChannel) {
+	if conf.Severity == "" {
+		conf.Severity = defaultPagerDutySeverity
+	}
+
+	source, _ := os.Hostname()
+	if source == "" {
+		source = "adguardhome"
+	}
+
+	return &pagerDutyChannel{
+		client: client,
+		conf:   conf,
+		url:    pagerDutyEventsURL,
+		source: source,
+	}
+}
+
+// type check
+var _ incidentChannel = (*pagerDutyChannel)(nil)
+
+// name implements the [channel] interface for *pagerDutyChannel.
+func (c *pagerDutyChannel) name() (n string) { return "pagerduty" }
+
+// pagerDutyEvent is the event of the PagerDuty Events API v2.
+type pagerDutyEvent struct {
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+}
+
+// pagerDutyPayload is the payload of the triggered PagerDuty event.
+type pagerDutyPayload struct {
+	CustomDetails map[string]any `json:"custom_details,omitempty"`
+	Summary       string         `json:"summary"`
+	Source        string         `json:"source"`
+	Severity      string         `json:"severity"`
+	Timestamp     string         `json:"timestamp"`
+}
+
+// send implements the [channel] interface for *pagerDutyChannel.
+func (c *pagerDutyChannel) send(ctx context.Context, ev *event) (err error) {
+	switch ev.typ {
+	case eventTypeAlert, eventTypeTest:
+		return c.enqueue(ctx, &pagerDutyEvent{
+			RoutingKey:  c.conf.RoutingKey,
+			EventAction: "trigger",
+			DedupKey:    c.dedupKey(ev),
+			Payload: &pagerDutyPayload{
+				Summary:   fmt.Sprintf("%s on %s", ev.title(), c.source),
+				Source:    c.source,
+				Severity:  c.conf.Severity,
+				Timestamp: ev.time.UTC().Format(time.RFC3339),
+				CustomDetails: map[string]any{
+					"metric":    ev.metric,
+					"value":     ev.value,
+					"threshold": ev.threshold,
+					"message":   htmlToText(ev.text),
+				},
+			},
+		})
+	case eventTypeRecovery:
+		return c.resolve(ctx, ev.metric)
+	default:
+		return nil
+	}
+}
+
+// resolve implements the [incidentChannel] interface for *pagerDutyChannel.
+func (c *pagerDutyChannel) resolve(ctx context.Context, metric string) (err error) {
+	return c.enqueue(ctx, &pagerDutyEvent{
+		RoutingKey:  c.conf.RoutingKey,
+		EventAction: "resolve",
+		DedupKey:    c.dedupKey(&event{typ: eventTypeAlert, metric: metric}),
+	})
+}
+
+// dedupKey returns the deduplication key of the alert, which is shared by the
+// trigger and the resolve events of the same metric.
+func (c *pagerDutyChannel) dedupKey(ev *event) (key string) {
+	if ev.typ == eventTypeTest {
+		return "adguardhome/" + c.source + "/test"
+	}
+
+	return "adguardhome/" + c.source + "/" + ev.metric
+}
+
+// enqueue sends pdEv to the Events API.
+func (c *pagerDutyChannel) enqueue(ctx context.Context, pdEv *pagerDutyEvent) (err error) {
+	body, err := json.Marshal(pdEv)
+	if err != nil {
+		return fmt.Errorf("marshal pagerduty event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	traceResponse(ctx, resp.StatusCode, respBody)
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("pagerduty api status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return nil
+}
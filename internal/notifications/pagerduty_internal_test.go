@@ -0,0 +1,59 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPagerDutyChannel_Send(t *testing.T) {
+	var got []pagerDutyEvent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ev pagerDutyEvent
+		if err := json.NewDecoder(r.Body).Decode(&ev); err != nil {
+			t.Errorf("decoding request: %s", err)
+		}
+
+		got = append(got, ev)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := newPagerDutyChannel(srv.Client(), PagerDutyConfig{Enabled: true, RoutingKey: "key"})
+	c.url = srv.URL
+	c.source = "host"
+
+	ctx := context.Background()
+	alert := &event{time: time.Now(), typ: eventTypeAlert, metric: "disk", value: 97, threshold: 90}
+	if err := c.send(ctx, alert); err != nil {
+		t.Fatalf("sending alert: %s", err)
+	}
+
+	if err := c.send(ctx, &event{typ: eventTypeFilterUpdate}); err != nil {
+		t.Fatalf("sending filter update: %s", err)
+	}
+
+	if err := c.send(ctx, &event{typ: eventTypeRecovery, metric: "disk"}); err != nil {
+		t.Fatalf("sending recovery: %s", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("got %d events, want 2", len(got))
+	}
+
+	trigger, resolve := got[0], got[1]
+	if trigger.EventAction != "trigger" || trigger.Payload == nil || trigger.Payload.Severity != defaultPagerDutySeverity {
+		t.Errorf("unexpected trigger %+v", trigger)
+	}
+
+	if resolve.EventAction != "resolve" || resolve.Payload != nil {
+		t.Errorf("unexpected resolve %+v", resolve)
+	}
+
+	if trigger.DedupKey != "adguardhome/host/disk" || resolve.DedupKey != trigger.DedupKey {
+		t.Errorf("dedup keys %q and %q, want %q", trigger.DedupKey, resolve.DedupKey, "adguardhome/host/disk")
+	}
+}
@@ -0,0 +1,158 @@
+package notifications
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// ParseMode is the formatting mode of the Telegram messages.
+type ParseMode string
+
+// Supported parse modes.
+const (
+	// ParseModeHTML is the default mode, in which the messages are composed.
+	ParseModeHTML ParseMode = "HTML"
+
+	// ParseModeMarkdownV2 converts the messages into the MarkdownV2 syntax.
+	ParseModeMarkdownV2 ParseMode = "MarkdownV2"
+
+	// ParseModeNone sends the messages as plain text.
+	ParseModeNone ParseMode = "none"
+)
+
+// ValidateParseMode returns an error if mode isn't supported.  An empty mode
+// means [ParseModeHTML].
+func ValidateParseMode(mode ParseMode) (err error) {
+	switch mode {
+	case "", ParseModeHTML, ParseModeMarkdownV2, ParseModeNone:
+		return nil
+	default:
+		return fmt.Errorf(
+			"unsupported parse mode %q: must be one of %q, %q, %q",
+			mode,
+			ParseModeHTML,
+			ParseModeMarkdownV2,
+			ParseModeNone,
+		)
+	}
+}
+
+// formatTelegramMessage converts the message composed in the Telegram HTML
+// subset for mode.  apiMode is the value of the parse_mode parameter, which is
+// empty for the plain text.
+func formatTelegramMessage(msg string, mode ParseMode) (text, apiMode string) {
+	switch mode {
+	case ParseModeMarkdownV2:
+		return htmlToMarkdownV2(msg), string(ParseModeMarkdownV2)
+	case ParseModeNone:
+		return htmlToText(msg), ""
+	default:
+		return msg, string(ParseModeHTML)
+	}
+}
+
+// tagPartsRe matches the HTML tags and captures the closing slash, the name,
+// and the href attribute of a link.
+var tagPartsRe = regexp.MustCompile(`(?i)^<\s*(/?)\s*([a-z-]+)(?:[^>]*?\bhref\s*=\s*"([^"]*)")?[^>]*>$`)
+
+// markdownV2Escaper escapes the characters reserved in the MarkdownV2 text.
+var markdownV2Escaper = newMarkdownV2Escaper("_*[]()~`>#+-=|{}.!\\")
+
+// markdownV2CodeEscaper escapes the characters reserved in the MarkdownV2 code
+// entities.
+var markdownV2CodeEscaper = newMarkdownV2Escaper("`\\")
+
+// markdownV2URLEscaper escapes the characters reserved in the MarkdownV2 link
+// URLs.
+var markdownV2URLEscaper = newMarkdownV2Escaper(")\\")
+
+// newMarkdownV2Escaper returns a replacer escaping chars with a backslash.
+func newMarkdownV2Escaper(chars string) (r *strings.Replacer) {
+	pairs := make([]string, 0, len(chars)*2)
+	for _, c := range chars {
+		pairs = append(pairs, string(c), `\`+string(c))
+	}
+
+	return strings.NewReplacer(pairs...)
+}
+
+// markdownV2Markers are the MarkdownV2 markers of the HTML elements.
+var markdownV2Markers = map[string]string{
+	"b":          "*",
+	"strong":     "*",
+	"i":          "_",
+	"em":         "_",
+	"u":          "__",
+	"ins":        "__",
+	"s":          "~",
+	"strike":     "~",
+	"del":        "~",
+	"tg-spoiler": "||",
+}
+
+// htmlToMarkdownV2 converts the message in the Telegram HTML subset into the
+// MarkdownV2 syntax escaping the text.  The unsupported tags are dropped.
+func htmlToMarkdownV2(s string) (md string) {
+	b := &strings.Builder{}
+
+	var (
+		inCode bool
+		inPre  bool
+		hrefs  []string
+	)
+
+	escapeText := func(text string) {
+		text = html.UnescapeString(text)
+		if inCode || inPre {
+			b.WriteString(markdownV2CodeEscaper.Replace(text))
+		} else {
+			b.WriteString(markdownV2Escaper.Replace(text))
+		}
+	}
+
+	last := 0
+	for _, loc := range tagRe.FindAllStringIndex(s, -1) {
+		escapeText(s[last:loc[0]])
+		last = loc[1]
+
+		parts := tagPartsRe.FindStringSubmatch(s[loc[0]:loc[1]])
+		if parts == nil {
+			continue
+		}
+
+		closing, name := parts[1] == "/", strings.ToLower(parts[2])
+		switch name {
+		case "code":
+			if !inPre {
+				inCode = !closing
+				b.WriteString("`")
+			}
+		case "pre":
+			inPre = !closing
+			if closing {
+				b.WriteString("\n```")
+			} else {
+				b.WriteString("```\n")
+			}
+		case "a":
+			if !closing {
+				hrefs = append(hrefs, html.UnescapeString(parts[3]))
+				b.WriteString("[")
+			} else if len(hrefs) > 0 {
+				href := hrefs[len(hrefs)-1]
+				hrefs = hrefs[:len(hrefs)-1]
+				fmt.Fprintf(b, "](%s)", markdownV2URLEscaper.Replace(href))
+			}
+		default:
+			if marker, ok := markdownV2Markers[name]; ok && !inCode && !inPre {
+				b.WriteString(marker)
+			}
+		}
+	}
+
+	escapeText(s[last:])
+
+	return b.String()
+}
@@ -0,0 +1,64 @@
+package notifications
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+)
+
+func TestHTMLToMarkdownV2(t *testing.T) {
+	testCases := []struct {
+		name string
+		in   string
+		want string
+	}{{
+		name: "bold",
+		in:   "🚨 <b>ALERT: CPU Usage</b>",
+		want: `🚨 *ALERT: CPU Usage*`,
+	}, {
+		name: "escaping",
+		in:   "List (1.2) &lt;name&gt; - #1!",
+		want: `List \(1\.2\) <name\> \- \#1\!`,
+	}, {
+		name: "code",
+		in:   "<code>95.0%_a`b</code>",
+		want: "`95.0%_a\\`b`",
+	}, {
+		name: "link",
+		in:   `<a href="https://example.org/a_(b)">example.org</a>`,
+		want: `[example\.org](https://example.org/a_(b\))`,
+	}, {
+		name: "pre",
+		in:   "<pre><b>x</b>*</pre>",
+		want: "```\nx*\n```",
+	}, {
+		name: "unsupported_tag",
+		in:   "<span>text</span>",
+		want: "text",
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := htmlToMarkdownV2(tc.in); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestComposeFilterUpdateMessage_escaping(t *testing.T) {
+	msg := composeFilterUpdateMessage(TelegramConfig{}, nil, FilterUpdate{
+		Name: "<b>List</b> & more",
+		URL:  "https://example.org/list.txt?a=1&b=2",
+	}, systeminfo.Info{})
+
+	for _, want := range []string{
+		"&lt;b&gt;List&lt;/b&gt; &amp; more",
+		`<a href="https://example.org/list.txt?a=1&amp;b=2">`,
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message %q doesn't contain %q", msg, want)
+		}
+	}
+}
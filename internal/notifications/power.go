@@ -0,0 +1,109 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+)
+
+// metricPower is the metric of the alerts about the host running on battery.
+const metricPower = "power"
+
+// checkPower sends an alert when the host switches to the battery power and a
+// recovery message when it's back on the mains power.  The alert is cleared
+// if the power state is no longer reported.
+func (m *Manager) checkPower(ctx context.Context, cfg TelegramConfig, info systeminfo.Info) {
+	active, _ := m.metricState(metricPower)
+
+	p := info.Power
+	if p == nil {
+		m.clearAlert(ctx, metricPower)
+
+		return
+	} else if !p.OnBattery {
+		if active {
+			m.clearAlertWithRecovery(ctx, cfg, metricPower, p.Charge, 0, info)
+		}
+
+		return
+	} else if active {
+		return
+	}
+
+	msg := composePowerAlertMessage(cfg, m.getLocale(), *p, info)
+	ev := &event{time: time.Now(), typ: eventTypeAlert, metric: metricPower, text: msg, value: p.Charge}
+	m.applyTemplate(ev, newTemplateData(cfg, ev, info))
+	if err := m.deliver(ctx, cfg, ev); err != nil {
+		m.logger.Error("power alert failed", slog.String("error", err.Error()))
+
+		return
+	}
+
+	now := time.Now()
+	m.updateMetricState(metricPower, true, now)
+
+	m.mu.Lock()
+	m.alertStartTime[metricPower] = now
+	m.mu.Unlock()
+
+	m.trackAlert(ev)
+}
+
+// composePowerAlertMessage formats an alert that the host runs on battery.
+func composePowerAlertMessage(
+	cfg TelegramConfig,
+	loc Locale,
+	p systeminfo.PowerInfo,
+	info systeminfo.Info,
+) (msg string) {
+	lines := make([]string, 0, 20)
+	if prefix := strings.TrimSpace(cfg.CustomMessage); prefix != "" {
+		lines = append(lines, prefix)
+		lines = append(lines, "")
+	}
+
+	title := loc.text("notification_power_on_battery_title", "ALERT: Host is running on battery power!")
+	lines = append(lines, fmt.Sprintf("🪫 <b>%s</b>", title))
+	lines = append(lines, divider())
+	lines = append(lines, "")
+	lines = append(lines, fmt.Sprintf(
+		"  ▸ <b>%s:</b> <code>%s</code>",
+		loc.text("notification_label_name", "Name"),
+		html.EscapeString(fallbackString(p.Name)),
+	))
+	lines = append(lines, fmt.Sprintf(
+		"  ▸ <b>%s:</b> %s",
+		loc.text("notification_label_charge", "Charge"),
+		usageBar(p.Charge),
+	))
+
+	if p.RuntimeSeconds > 0 {
+		lines = append(lines, fmt.Sprintf(
+			"  ▸ <b>%s:</b> <code>%s</code>",
+			loc.text("notification_label_runtime", "Runtime"),
+			(time.Duration(p.RuntimeSeconds) * time.Second).String(),
+		))
+	}
+
+	if p.LowBattery {
+		lines = append(lines, "")
+		lines = append(lines, "🔴 "+loc.text("notification_power_low_battery", "The battery is low, the host may shut down soon."))
+	}
+
+	lines = append(lines, "")
+
+	if info.Hostname != "" {
+		lines = append(lines, systemOverviewLines(loc, info)...)
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, divider())
+	lines = append(lines, loc.timestampLine())
+
+	return strings.Join(lines, "\n")
+}
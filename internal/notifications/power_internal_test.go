@@ -0,0 +1,46 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+)
+
+func TestManager_checkPower(t *testing.T) {
+	rec := &recordingChannel{chanName: "matrix"}
+
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	m.channels = []channel{rec}
+
+	ctx := context.Background()
+	onBattery := systeminfo.Info{Power: &systeminfo.PowerInfo{Name: "ups", Charge: 80, OnBattery: true}}
+	m.checkPower(ctx, TelegramConfig{}, onBattery)
+	m.checkPower(ctx, TelegramConfig{}, onBattery)
+	m.checkPower(ctx, TelegramConfig{}, systeminfo.Info{Power: &systeminfo.PowerInfo{Name: "ups", Charge: 81}})
+
+	want := []eventType{eventTypeAlert, eventTypeRecovery}
+	if !slices.Equal(rec.got, want) {
+		t.Errorf("got events %q, want %q", rec.got, want)
+	}
+}
+
+func TestComposePowerAlertMessage(t *testing.T) {
+	p := systeminfo.PowerInfo{
+		Name:           "ups",
+		Charge:         9,
+		RuntimeSeconds: 300,
+		OnBattery:      true,
+		LowBattery:     true,
+	}
+
+	msg := composePowerAlertMessage(TelegramConfig{}, nil, p, systeminfo.Info{})
+	for _, want := range []string{"battery power", "ups", "9%", "5m0s", "battery is low"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message %q does not contain %q", msg, want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+)
+
+// eventTypePublicIPChange is the type of the events about the changes of the
+// public IP address of the host.
+const eventTypePublicIPChange eventType = "public_ip_change"
+
+// checkPublicIP sends a notification when the public IP address of the host
+// changes.  The first reported address and the failed lookups are only
+// recorded.
+func (m *Manager) checkPublicIP(ctx context.Context, cfg TelegramConfig, info systeminfo.Info) {
+	if info.PublicIP == "" {
+		return
+	}
+
+	m.mu.Lock()
+	prev := m.publicIP
+	m.publicIP = info.PublicIP
+	m.mu.Unlock()
+
+	if prev == "" || prev == info.PublicIP {
+		return
+	}
+
+	ev := &event{
+		time: time.Now(),
+		typ:  eventTypePublicIPChange,
+		text: composePublicIPChangeMessage(cfg, m.getLocale(), prev, info),
+	}
+
+	if err := m.deliver(ctx, cfg, ev); err != nil {
+		m.logger.Error("public ip change notification failed", slog.String("error", err.Error()))
+	}
+}
+
+// composePublicIPChangeMessage formats the notification about the change of
+// the public IP address from prev.
+func composePublicIPChangeMessage(
+	cfg TelegramConfig,
+	loc Locale,
+	prev string,
+	info systeminfo.Info,
+) (msg string) {
+	lines := make([]string, 0, 12)
+	if prefix := strings.TrimSpace(cfg.CustomMessage); prefix != "" {
+		lines = append(lines, prefix)
+		lines = append(lines, "")
+	}
+
+	title := loc.text("notification_public_ip_changed_title", "Public IP address has changed")
+	lines = append(lines, fmt.Sprintf("🌍 <b>%s</b>", title))
+	lines = append(lines, divider())
+	lines = append(lines, "")
+
+	if info.Hostname != "" {
+		lines = append(lines, fmt.Sprintf(
+			"  ▸ <b>%s:</b> <code>%s</code>",
+			loc.text("notification_label_host", "Host"),
+			html.EscapeString(info.Hostname),
+		))
+	}
+
+	lines = append(lines, fmt.Sprintf(
+		"  ▸ <b>%s:</b> <code>%s</code>",
+		loc.text("notification_label_previous_ip", "Previous IP"),
+		html.EscapeString(prev),
+	))
+	lines = append(lines, fmt.Sprintf(
+		"  ▸ <b>%s:</b> <code>%s</code>",
+		loc.text("notification_label_public_ip", "Public IP"),
+		html.EscapeString(info.PublicIP),
+	))
+	lines = append(lines, "")
+	lines = append(lines, "💡 "+loc.text(
+		"notification_public_ip_changed_hint",
+		"Update the DNS records and the client settings pointing to the old address.",
+	))
+	lines = append(lines, "")
+	lines = append(lines, divider())
+	lines = append(lines, loc.timestampLine())
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,41 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+)
+
+func TestManager_checkPublicIP(t *testing.T) {
+	rec := &recordingChannel{chanName: "matrix"}
+
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	m.channels = []channel{rec}
+
+	ctx := context.Background()
+	for _, ip := range []string{"203.0.113.1", "203.0.113.1", "", "203.0.113.2"} {
+		m.checkPublicIP(ctx, TelegramConfig{}, systeminfo.Info{PublicIP: ip})
+	}
+
+	want := []eventType{eventTypePublicIPChange}
+	if !slices.Equal(rec.got, want) {
+		t.Errorf("got events %q, want %q", rec.got, want)
+	}
+}
+
+func TestComposePublicIPChangeMessage(t *testing.T) {
+	msg := composePublicIPChangeMessage(TelegramConfig{}, nil, "203.0.113.1", systeminfo.Info{
+		Hostname: "<router>",
+		PublicIP: "203.0.113.2",
+	})
+
+	for _, want := range []string{"has changed", "&lt;router&gt;", "203.0.113.1", "203.0.113.2"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message %q does not contain %q", msg, want)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+// eventTypeQuietSummary is the type of the summary of the events queued
+// during the quiet hours.
+const eventTypeQuietSummary eventType = "quiet_summary"
+
+// maxQuietQueueLen is the maximum number of events queued during the quiet
+// hours.  The oldest events are dropped once it's reached.
+const maxQuietQueueLen = 100
+
+// QuietWindow is a quiet hours window starting on Weekday.  Start and End are
+// the offsets from midnight, and the window continues into the next day if End
+// isn't after Start.
+type QuietWindow struct {
+	Weekday time.Weekday
+	Start   time.Duration
+	End     time.Duration
+}
+
+// QuietHoursConfig contains runtime configuration for the quiet hours.  The
+// events not matching Critical, which uses the keys of the routing rules, are
+// queued during the windows if Queue is true and dropped otherwise.  The
+// queued events are delivered as a summary once the window ends.  Location is
+// the time zone of the windows, [time.Local] is used if it's nil.
+type QuietHoursConfig struct {
+	Location *time.Location
+	Windows  []QuietWindow
+	Critical []string
+	Enabled  bool
+	Queue    bool
+}
+
+// defaultQuietCritical are the critical events used if
+// [QuietHoursConfig.Critical] is nil.
+var defaultQuietCritical = []string{string(eventTypeAlert)}
+
+// isQuiet returns true if t is within one of the windows of conf.
+func (conf *QuietHoursConfig) isQuiet(t time.Time) (ok bool) {
+	if !conf.Enabled {
+		return false
+	}
+
+	loc := conf.Location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	t = t.In(loc)
+	y, mon, d := t.Date()
+	offset := t.Sub(time.Date(y, mon, d, 0, 0, 0, 0, loc))
+	day := t.Weekday()
+	prevDay := (day + 6) % 7
+
+	for _, w := range conf.Windows {
+		overnight := w.End <= w.Start
+		if w.Weekday == day && offset >= w.Start && (overnight || offset < w.End) {
+			return true
+		} else if overnight && w.Weekday == prevDay && offset < w.End {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isCritical returns true if ev must be delivered during the quiet hours.
+func (conf *QuietHoursConfig) isCritical(ev *event) (ok bool) {
+	switch ev.typ {
+	case eventTypeTest, eventTypeQuietSummary, eventTypeDigest, eventTypeRateLimit:
+		return true
+	}
+
+	critical := conf.Critical
+	if critical == nil {
+		critical = defaultQuietCritical
+	}
+
+	for _, key := range routeKeys(ev.typ, ev.metric) {
+		if slices.Contains(critical, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// UpdateQuietHoursConfig applies the new quiet hours configuration.  The
+// queued events are kept and delivered once the quiet hours end.
+func (m *Manager) UpdateQuietHoursConfig(conf QuietHoursConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.quietHours = conf
+}
+
+// holdForQuietHours returns the status of ev if it shouldn't be delivered now
+// because of the quiet hours, and an empty string otherwise.  It queues ev, if
+// configured.
+func (m *Manager) holdForQuietHours(ev *event) (status HistoryStatus) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	conf := &m.quietHours
+	if !conf.isQuiet(time.Now()) || conf.isCritical(ev) {
+		return ""
+	}
+
+	if !conf.Queue {
+		m.logger.Debug("notification suppressed by quiet hours", "type", string(ev.typ))
+
+		return HistoryStatusSuppressed
+	}
+
+	if len(m.quietQueue) >= maxQuietQueueLen {
+		m.quietQueue = m.quietQueue[1:]
+		m.quietDropped++
+	}
+
+	m.quietQueue = append(m.quietQueue, ev)
+
+	return HistoryStatusQueued
+}
+
+// flushQuietQueue delivers the summary of the events queued during the quiet
+// hours if they have ended.
+func (m *Manager) flushQuietQueue(ctx context.Context, cfg TelegramConfig) {
+	m.mu.Lock()
+	if len(m.quietQueue) == 0 || m.quietHours.isQuiet(time.Now()) {
+		m.mu.Unlock()
+
+		return
+	}
+
+	queued, dropped := m.quietQueue, m.quietDropped
+	m.quietQueue, m.quietDropped = nil, 0
+	m.mu.Unlock()
+
+	ev := &event{
+		time: time.Now(),
+		typ:  eventTypeQuietSummary,
+		text: composeEventSummaryMessage("🌙", "Quiet Hours Summary", queued, dropped),
+	}
+
+	err := m.deliver(ctx, cfg, ev)
+	if err != nil {
+		m.logger.Error("quiet hours summary failed", slog.String("error", err.Error()))
+	}
+}
+
+// composeEventSummaryMessage formats the summary of the held back events with
+// the given header.  dropped is the number of the events omitted from queued.
+func composeEventSummaryMessage(icon, header string, queued []*event, dropped int) (msg string) {
+	lines := make([]string, 0, len(queued)+8)
+	lines = append(lines, fmt.Sprintf("%s <b>%s</b>", icon, header))
+	lines = append(lines, divider())
+	lines = append(lines, "")
+	lines = append(lines, sectionHeader("📋", fmt.Sprintf("Events (%d)", len(queued)+dropped)))
+	for _, ev := range queued {
+		lines = append(lines, fmt.Sprintf(
+			"  ▸ <code>%s</code> %s",
+			ev.time.Format("Mon 15:04"),
+			html.EscapeString(ev.summary()),
+		))
+	}
+
+	if dropped > 0 {
+		lines = append(lines, fmt.Sprintf("  ▸ <i>%d older events omitted</i>", dropped))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, divider())
+	lines = append(lines, timestampLine())
+
+	return strings.Join(lines, "\n")
+}
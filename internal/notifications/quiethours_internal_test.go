@@ -0,0 +1,94 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestQuietHoursConfig_isQuiet(t *testing.T) {
+	conf := &QuietHoursConfig{
+		Location: time.UTC,
+		Enabled:  true,
+		Windows: []QuietWindow{{
+			Weekday: time.Monday,
+			Start:   22 * time.Hour,
+			End:     7 * time.Hour,
+		}, {
+			Weekday: time.Saturday,
+			Start:   13 * time.Hour,
+			End:     15 * time.Hour,
+		}},
+	}
+
+	// 2026-01-05 is a Monday.
+	testCases := []struct {
+		time time.Time
+		name string
+		want bool
+	}{{
+		time: time.Date(2026, 1, 5, 21, 59, 0, 0, time.UTC),
+		name: "before_overnight",
+		want: false,
+	}, {
+		time: time.Date(2026, 1, 5, 23, 0, 0, 0, time.UTC),
+		name: "overnight_same_day",
+		want: true,
+	}, {
+		time: time.Date(2026, 1, 6, 6, 59, 0, 0, time.UTC),
+		name: "overnight_next_day",
+		want: true,
+	}, {
+		time: time.Date(2026, 1, 6, 7, 0, 0, 0, time.UTC),
+		name: "after_overnight",
+		want: false,
+	}, {
+		time: time.Date(2026, 1, 10, 14, 0, 0, 0, time.UTC),
+		name: "daytime",
+		want: true,
+	}, {
+		time: time.Date(2026, 1, 11, 14, 0, 0, 0, time.UTC),
+		name: "other_day",
+		want: false,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := conf.isQuiet(tc.time); got != tc.want {
+				t.Errorf("isQuiet(%s) = %t, want %t", tc.time, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestManager_deliver_quietHours(t *testing.T) {
+	ch := &recordingChannel{chanName: "matrix"}
+
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	m.channels = []channel{ch}
+
+	always := []QuietWindow{{Weekday: time.Now().Weekday(), Start: 0, End: 0}}
+	m.UpdateQuietHoursConfig(QuietHoursConfig{Windows: always, Enabled: true, Queue: true})
+
+	ctx := context.Background()
+	for _, ev := range []*event{
+		{time: time.Now(), typ: eventTypeAlert, metric: "cpu"},
+		{time: time.Now(), typ: eventTypeFilterUpdate},
+	} {
+		if err := m.deliver(ctx, TelegramConfig{}, ev); err != nil {
+			t.Fatalf("delivering %s: %s", ev.typ, err)
+		}
+	}
+
+	m.flushQuietQueue(ctx, TelegramConfig{})
+	if len(ch.got) != 1 || ch.got[0] != eventTypeAlert {
+		t.Fatalf("got %v during quiet hours, want only the alert", ch.got)
+	}
+
+	m.UpdateQuietHoursConfig(QuietHoursConfig{})
+	m.flushQuietQueue(ctx, TelegramConfig{})
+	if len(ch.got) != 2 || ch.got[1] != eventTypeQuietSummary {
+		t.Fatalf("got %v after quiet hours, want the summary", ch.got)
+	}
+}
@@ -0,0 +1,199 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+// eventTypeRateLimit is the type of the events about the notifications
+// suppressed by the global rate limit.  The metric is either
+// [rateLimitSuppressed] or [rateLimitResumed].
+const eventTypeRateLimit eventType = "rate_limit"
+
+// The metrics of the rate limit events.
+const (
+	rateLimitSuppressed = "suppressed"
+	rateLimitResumed    = "resumed"
+)
+
+// minRateLimitPeriod is the minimum period of the rate limit.
+const minRateLimitPeriod = time.Minute
+
+// RateLimitConfig contains runtime configuration for the global limit of the
+// notifications across all targets.  At most Limit notifications are delivered
+// within any Period, the rest are suppressed until the rate drops below the
+// limit.
+type RateLimitConfig struct {
+	Period  time.Duration
+	Limit   int
+	Enabled bool
+}
+
+// ValidateRateLimitConfig returns an error if the enabled rate limit
+// configuration is invalid.
+func ValidateRateLimitConfig(conf RateLimitConfig) (err error) {
+	if !conf.Enabled {
+		return nil
+	}
+
+	if conf.Limit <= 0 {
+		return fmt.Errorf("limit: must be positive, got %d", conf.Limit)
+	}
+
+	if conf.Period < minRateLimitPeriod {
+		return fmt.Errorf("period: must be at least %s, got %s", minRateLimitPeriod, conf.Period)
+	}
+
+	return nil
+}
+
+// UpdateRateLimitConfig applies the new rate limit configuration.  The
+// notifications suppressed so far are reported once the rate drops below the
+// new limit.
+func (m *Manager) UpdateRateLimitConfig(conf RateLimitConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rateLimit = conf
+}
+
+// holdForRateLimit returns true if ev exceeds the rate limit and must be
+// suppressed.  notice is true if ev is the first suppressed one, so that the
+// suppression notice should be sent.  Once the limit is reached, all
+// notifications are suppressed until [Manager.flushRateLimit] reports them.
+func (m *Manager) holdForRateLimit(ev *event) (held, notice bool) {
+	if ev.typ == eventTypeTest || ev.typ == eventTypeRateLimit {
+		return false, false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	conf := m.rateLimit
+	if !conf.Enabled {
+		return false, false
+	}
+
+	now := time.Now()
+	m.pruneRateSentLocked(now.Add(-conf.Period))
+	if m.rateSuppressed == 0 && len(m.rateSent) < conf.Limit {
+		m.rateSent = append(m.rateSent, now)
+
+		return false, false
+	}
+
+	m.rateSuppressed++
+	m.logger.Debug("notification suppressed by rate limit", "type", string(ev.typ))
+
+	return true, m.rateSuppressed == 1
+}
+
+// pruneRateSentLocked removes the delivery times not after since.  m.mu must
+// be locked.
+func (m *Manager) pruneRateSentLocked(since time.Time) {
+	i := slices.IndexFunc(m.rateSent, func(t time.Time) (ok bool) { return t.After(since) })
+	if i < 0 {
+		i = len(m.rateSent)
+	}
+
+	m.rateSent = slices.Delete(m.rateSent, 0, i)
+}
+
+// notifyRateLimited sends the notice that the further notifications are
+// suppressed.
+func (m *Manager) notifyRateLimited(ctx context.Context, cfg TelegramConfig) {
+	m.mu.RLock()
+	conf := m.rateLimit
+	m.mu.RUnlock()
+
+	ev := &event{
+		time:   time.Now(),
+		typ:    eventTypeRateLimit,
+		metric: rateLimitSuppressed,
+		text:   composeRateLimitMessage(cfg, conf),
+	}
+
+	if err := m.deliver(ctx, cfg, ev); err != nil {
+		m.logger.Error("rate limit notification failed", slog.String("error", err.Error()))
+	}
+}
+
+// flushRateLimit sends the number of the suppressed notifications once the
+// rate has dropped below the limit or the limit has been disabled.
+func (m *Manager) flushRateLimit(ctx context.Context, cfg TelegramConfig) {
+	m.mu.Lock()
+	conf := m.rateLimit
+	m.pruneRateSentLocked(time.Now().Add(-conf.Period))
+	if m.rateSuppressed == 0 || (conf.Enabled && len(m.rateSent) >= conf.Limit) {
+		m.mu.Unlock()
+
+		return
+	}
+
+	suppressed := m.rateSuppressed
+	m.rateSuppressed = 0
+	m.mu.Unlock()
+
+	ev := &event{
+		time:   time.Now(),
+		typ:    eventTypeRateLimit,
+		metric: rateLimitResumed,
+		text:   composeRateLimitResumedMessage(cfg, suppressed),
+	}
+
+	if err := m.deliver(ctx, cfg, ev); err != nil {
+		m.logger.Error("rate limit summary failed", slog.String("error", err.Error()))
+	}
+}
+
+// composeRateLimitMessage formats the notice that the further notifications
+// are suppressed.
+func composeRateLimitMessage(cfg TelegramConfig, conf RateLimitConfig) (msg string) {
+	lines := make([]string, 0, 10)
+	if prefix := strings.TrimSpace(cfg.CustomMessage); prefix != "" {
+		lines = append(lines, prefix, "")
+	}
+
+	lines = append(lines,
+		"🚦 <b>Notification Rate Limit Reached</b>",
+		divider(),
+		"",
+		fmt.Sprintf(
+			"  ▸ <b>Limit:</b> <code>%d</code> per <code>%s</code>",
+			conf.Limit,
+			formatUptime(uint64(conf.Period.Seconds())),
+		),
+		"",
+		"<i>Further notifications are suppressed until the rate drops.</i>",
+		"",
+		divider(),
+		timestampLine(),
+	)
+
+	return strings.Join(lines, "\n")
+}
+
+// composeRateLimitResumedMessage formats the notice that the notifications are
+// delivered again after suppressed of them have been dropped.
+func composeRateLimitResumedMessage(cfg TelegramConfig, suppressed int) (msg string) {
+	lines := make([]string, 0, 8)
+	if prefix := strings.TrimSpace(cfg.CustomMessage); prefix != "" {
+		lines = append(lines, prefix, "")
+	}
+
+	lines = append(lines,
+		"🚦 <b>Notifications Resumed</b>",
+		divider(),
+		"",
+		fmt.Sprintf("  ▸ <b>Suppressed:</b> <code>%d</code> notifications", suppressed),
+		"",
+		divider(),
+		timestampLine(),
+	)
+
+	return strings.Join(lines, "\n")
+}
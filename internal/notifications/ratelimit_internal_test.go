@@ -0,0 +1,93 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestValidateRateLimitConfig(t *testing.T) {
+	testCases := []struct {
+		name    string
+		conf    RateLimitConfig
+		wantErr bool
+	}{{
+		name:    "disabled",
+		conf:    RateLimitConfig{},
+		wantErr: false,
+	}, {
+		name:    "valid",
+		conf:    RateLimitConfig{Period: time.Hour, Limit: 30, Enabled: true},
+		wantErr: false,
+	}, {
+		name:    "zero_limit",
+		conf:    RateLimitConfig{Period: time.Hour, Enabled: true},
+		wantErr: true,
+	}, {
+		name:    "short_period",
+		conf:    RateLimitConfig{Period: time.Second, Limit: 30, Enabled: true},
+		wantErr: true,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateRateLimitConfig(tc.conf)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("got error %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestManager_deliver_rateLimit(t *testing.T) {
+	rec := &recordingChannel{chanName: "matrix"}
+
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	m.channels = []channel{rec}
+	m.UpdateRateLimitConfig(RateLimitConfig{Period: time.Hour, Limit: 2, Enabled: true})
+
+	ctx := context.Background()
+	for range 4 {
+		if err := m.deliver(ctx, TelegramConfig{}, &event{typ: eventTypeFilterUpdate}); err != nil {
+			t.Fatalf("delivering: %s", err)
+		}
+	}
+
+	// The test messages aren't limited.
+	if err := m.deliver(ctx, TelegramConfig{}, &event{typ: eventTypeTest}); err != nil {
+		t.Fatalf("delivering: %s", err)
+	}
+
+	want := []eventType{eventTypeFilterUpdate, eventTypeFilterUpdate, eventTypeRateLimit, eventTypeTest}
+	if !slices.Equal(rec.got, want) {
+		t.Fatalf("got %v, want %v", rec.got, want)
+	}
+
+	// The summary isn't sent while the rate is still at the limit.
+	m.flushRateLimit(ctx, TelegramConfig{})
+	if len(rec.got) != len(want) {
+		t.Fatalf("got %v after flush at the limit", rec.got)
+	}
+
+	// Let the period pass.
+	m.mu.Lock()
+	for i := range m.rateSent {
+		m.rateSent[i] = m.rateSent[i].Add(-time.Hour)
+	}
+	m.mu.Unlock()
+
+	m.flushRateLimit(ctx, TelegramConfig{})
+	if got := rec.got[len(rec.got)-1]; got != eventTypeRateLimit || m.rateSuppressed != 0 {
+		t.Fatalf("got last event %q and %d suppressed, want summary", got, m.rateSuppressed)
+	}
+
+	if err := m.deliver(ctx, TelegramConfig{}, &event{typ: eventTypeFilterUpdate}); err != nil {
+		t.Fatalf("delivering: %s", err)
+	}
+
+	if got := rec.got[len(rec.got)-1]; got != eventTypeFilterUpdate {
+		t.Errorf("got last event %q after resuming, want %q", got, eventTypeFilterUpdate)
+	}
+}
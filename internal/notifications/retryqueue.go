@@ -0,0 +1,395 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghos"
+	"github.com/google/renameio/v2/maybe"
+)
+
+// Retry queue settings.
+const (
+	// retryQueueTick is the interval between the checks of the retry queue.
+	retryQueueTick = 15 * time.Second
+
+	// retryBaseDelay is the delay before the first retry from the queue.
+	retryBaseDelay = 30 * time.Second
+
+	// retryMaxDelay is the maximum delay between the retries.
+	retryMaxDelay = time.Hour
+
+	// retryMaxAge is the time after which the undelivered notifications are
+	// dropped.
+	retryMaxAge = 24 * time.Hour
+
+	// maxRetryQueueLen is the maximum number of the queued notifications.
+	// The oldest ones are dropped once it's reached.
+	maxRetryQueueLen = 500
+
+	// maxInlineRetryAfter is the maximum retry_after, which [withRetry] waits
+	// for.  The longer ones are left to the retry queue.
+	maxInlineRetryAfter = 30 * time.Second
+)
+
+// retryAfterError is returned when the target asks to wait before the next
+// attempt.
+type retryAfterError struct {
+	err   error
+	after time.Duration
+}
+
+// type check
+var _ error = (*retryAfterError)(nil)
+
+// Error implements the error interface for *retryAfterError.
+func (e *retryAfterError) Error() (msg string) {
+	return fmt.Sprintf("%s, retry after %s", e.err, e.after)
+}
+
+// Unwrap returns the underlying error.
+func (e *retryAfterError) Unwrap() (err error) { return e.err }
+
+// newTelegramRateLimitError returns the error for the Telegram response with
+// the 429 status and the body, which includes retry_after in seconds.
+func newTelegramRateLimitError(body []byte) (err error) {
+	err = fmt.Errorf("telegram api status %d: %s", http.StatusTooManyRequests, strings.TrimSpace(string(body)))
+
+	var apiResp struct {
+		Parameters struct {
+			RetryAfter int `json:"retry_after"`
+		} `json:"parameters"`
+	}
+
+	if json.Unmarshal(body, &apiResp) != nil || apiResp.Parameters.RetryAfter <= 0 {
+		return err
+	}
+
+	return &retryAfterError{
+		err:   err,
+		after: time.Duration(apiResp.Parameters.RetryAfter) * time.Second,
+	}
+}
+
+// retryEvent is the JSON representation of [event] in the retry queue.
+type retryEvent struct {
+	Time      time.Time     `json:"time"`
+	Filter    *FilterUpdate `json:"filter,omitempty"`
+	Type      eventType     `json:"type"`
+	Metric    string        `json:"metric,omitempty"`
+	Text      string        `json:"text"`
+	Value     float64       `json:"value,omitempty"`
+	Threshold float64       `json:"threshold,omitempty"`
+}
+
+// retryEntry is a notification waiting for another attempt to deliver it to
+// Target.
+type retryEntry struct {
+	Created     time.Time  `json:"created"`
+	NextAttempt time.Time  `json:"next_attempt"`
+	Event       retryEvent `json:"event"`
+	Target      string     `json:"target"`
+	LastError   string     `json:"last_error,omitempty"`
+	Attempts    int        `json:"attempts"`
+}
+
+// toEvent returns the event of e.
+func (e *retryEntry) toEvent() (ev *event) {
+	return &event{
+		time:      e.Event.Time,
+		typ:       e.Event.Type,
+		filter:    e.Event.Filter,
+		metric:    e.Event.Metric,
+		text:      e.Event.Text,
+		value:     e.Event.Value,
+		threshold: e.Event.Threshold,
+	}
+}
+
+// reschedule updates e after the failed attempt.  It returns false if e
+// shouldn't be retried anymore.
+func (e *retryEntry) reschedule(now time.Time, err error) (ok bool) {
+	e.Attempts++
+	e.LastError = err.Error()
+	e.NextAttempt = now.Add(retryDelay(e.Attempts, err))
+
+	return e.NextAttempt.Sub(e.Created) < retryMaxAge
+}
+
+// retryDelay returns the delay before the next attempt after the given number
+// of the failed ones.  It honors the delay requested by the target and adds
+// jitter to the exponential backoff otherwise.
+func retryDelay(attempts int, err error) (d time.Duration) {
+	if raErr, ok := errors.AsType[*retryAfterError](err); ok {
+		return raErr.after
+	}
+
+	d = retryMaxDelay
+	if attempts < 8 {
+		d = min(retryBaseDelay<<(attempts-1), retryMaxDelay)
+	}
+
+	// Use the "equal jitter" to spread the retries of the concurrent failures.
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryQueue is the persistent queue of the notifications to retry.
+type retryQueue struct {
+	// mu protects entries and the file.
+	mu *sync.Mutex
+
+	// path is the path to the file with the entries.  The queue is only kept
+	// in memory if it's empty.
+	path string
+
+	// entries are the queued notifications.
+	entries []*retryEntry
+}
+
+// newRetryQueue returns a new retry queue stored in the file at path and loads
+// the existing entries.
+func newRetryQueue(path string) (q *retryQueue, err error) {
+	q = &retryQueue{
+		mu:   &sync.Mutex{},
+		path: path,
+	}
+
+	if path == "" {
+		return q, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return q, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading retry queue: %w", err)
+	}
+
+	err = json.Unmarshal(data, &q.entries)
+	if err != nil {
+		return nil, fmt.Errorf("decoding retry queue: %w", err)
+	}
+
+	return q, nil
+}
+
+// push adds the entries to the queue and stores it.
+func (q *retryQueue) push(entries ...*retryEntry) (err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.entries = append(q.entries, entries...)
+	if n := len(q.entries) - maxRetryQueueLen; n > 0 {
+		q.entries = slices.Delete(q.entries, 0, n)
+	}
+
+	return q.storeLocked()
+}
+
+// popDue removes the entries due at now from the queue and returns them.
+func (q *retryQueue) popDue(now time.Time) (due []*retryEntry, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.entries = slices.DeleteFunc(q.entries, func(e *retryEntry) (ok bool) {
+		if e.NextAttempt.After(now) {
+			return false
+		}
+
+		due = append(due, e)
+
+		return true
+	})
+
+	if len(due) == 0 {
+		return nil, nil
+	}
+
+	return due, q.storeLocked()
+}
+
+// len returns the number of the queued entries.
+func (q *retryQueue) len() (n int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.entries)
+}
+
+// storeLocked writes the entries into the file.  q.mu must be locked.
+func (q *retryQueue) storeLocked() (err error) {
+	if q.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(q.entries)
+	if err != nil {
+		return fmt.Errorf("encoding retry queue: %w", err)
+	}
+
+	err = maybe.WriteFile(q.path, data, aghos.DefaultPermFile)
+	if err != nil {
+		return fmt.Errorf("writing retry queue: %w", err)
+	}
+
+	return nil
+}
+
+// OpenRetryQueue enables the retry queue of the undelivered notifications
+// stored in the file at path.  The queue is only kept in memory if path is
+// empty.
+func (m *Manager) OpenRetryQueue(path string) (err error) {
+	q, err := newRetryQueue(path)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.retries = q
+
+	return nil
+}
+
+// getRetryQueue returns the retry queue, if it's enabled.
+func (m *Manager) getRetryQueue() (q *retryQueue) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.retries
+}
+
+// enqueueRetry queues ev for another attempt to deliver it to target.  It
+// returns false if the retry queue is disabled.
+func (m *Manager) enqueueRetry(ev *event, target string, sendErr error) (ok bool) {
+	q := m.getRetryQueue()
+	if q == nil {
+		return false
+	}
+
+	now := time.Now()
+	e := &retryEntry{
+		Created: now,
+		Event: retryEvent{
+			Time:      ev.time,
+			Filter:    ev.filter,
+			Type:      ev.typ,
+			Metric:    ev.metric,
+			Text:      ev.text,
+			Value:     ev.value,
+			Threshold: ev.threshold,
+		},
+		Target: target,
+	}
+	e.reschedule(now, sendErr)
+
+	err := q.push(e)
+	if err != nil {
+		m.logger.Error("queueing notification retry", slog.String("error", err.Error()))
+	}
+
+	return true
+}
+
+// retryLoop periodically retries the queued notifications.
+func (m *Manager) retryLoop(ctx context.Context, stop <-chan struct{}) {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(retryQueueTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.processRetryQueue(ctx)
+		}
+	}
+}
+
+// processRetryQueue makes another attempt to deliver the due notifications.
+func (m *Manager) processRetryQueue(ctx context.Context) {
+	q := m.getRetryQueue()
+	if q == nil {
+		return
+	}
+
+	now := time.Now()
+	due, err := q.popDue(now)
+	if err != nil {
+		m.logger.Error("reading notification retries", slog.String("error", err.Error()))
+	}
+
+	var failed []*retryEntry
+	for _, e := range due {
+		ev := e.toEvent()
+		err = m.retrySend(ctx, e.Target, ev)
+		if err == nil {
+			m.record(newHistoryEntry(ev, e.Target, HistoryStatusSent, nil))
+
+			continue
+		}
+
+		if errors.Is(err, errTargetDisabled) || !e.reschedule(now, err) {
+			m.logger.Warn("dropping undelivered notification",
+				"target", e.Target,
+				"type", string(ev.typ),
+				"attempts", e.Attempts,
+				slog.String("error", err.Error()),
+			)
+			m.record(newHistoryEntry(ev, e.Target, HistoryStatusFailed, err))
+
+			continue
+		}
+
+		failed = append(failed, e)
+	}
+
+	if len(failed) == 0 {
+		return
+	}
+
+	err = q.push(failed...)
+	if err != nil {
+		m.logger.Error("requeueing notification retries", slog.String("error", err.Error()))
+	}
+}
+
+// errTargetDisabled is returned when the target of a queued notification has
+// been disabled.
+var errTargetDisabled = errors.New("target is disabled")
+
+// retrySend makes a single attempt to deliver ev to target.
+func (m *Manager) retrySend(ctx context.Context, target string, ev *event) (err error) {
+	if target == telegramTarget || strings.HasPrefix(target, telegramChatTargetPrefix) {
+		cfg, ok := telegramRecipientConfig(m.getTelegramConfig(), target)
+		if !ok {
+			return errTargetDisabled
+		}
+
+		return m.sendTelegram(ctx, cfg, ev.text)
+	}
+
+	for _, ch := range m.getChannels() {
+		if ch.name() == target {
+			return ch.send(ctx, ev)
+		}
+	}
+
+	return errTargetDisabled
+}
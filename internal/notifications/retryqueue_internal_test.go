@@ -0,0 +1,93 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// flakyChannel is a [channel], which fails until it's fixed.
+type flakyChannel struct {
+	fixed bool
+	sent  int
+}
+
+// name implements the [channel] interface for *flakyChannel.
+func (c *flakyChannel) name() (n string) { return "matrix" }
+
+// send implements the [channel] interface for *flakyChannel.
+func (c *flakyChannel) send(_ context.Context, _ *event) (err error) {
+	if !c.fixed {
+		return &retryAfterError{err: errors.New("test error"), after: time.Hour}
+	}
+
+	c.sent++
+
+	return nil
+}
+
+func TestManager_processRetryQueue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "retries.json")
+	ch := &flakyChannel{}
+
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	m.channels = []channel{ch}
+	if err := m.OpenRetryQueue(path); err != nil {
+		t.Fatalf("opening retry queue: %s", err)
+	}
+
+	ev := &event{time: time.Now(), typ: eventTypeAlert, metric: "cpu", text: "cpu"}
+	if err := m.deliver(context.Background(), TelegramConfig{}, ev); err != nil {
+		t.Fatalf("queued notification must be considered delivered, got %s", err)
+	}
+
+	// Reopen the queue to check that it's persisted.
+	m = NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	m.channels = []channel{ch}
+	if err := m.OpenRetryQueue(path); err != nil {
+		t.Fatalf("reopening retry queue: %s", err)
+	}
+
+	q := m.getRetryQueue()
+	if n := q.len(); n != 1 {
+		t.Fatalf("got %d queued entries, want 1", n)
+	}
+
+	if next := q.entries[0].NextAttempt; time.Until(next) < 59*time.Minute {
+		t.Errorf("retry_after isn't honored, next attempt at %s", next)
+	}
+
+	q.entries[0].NextAttempt = time.Now()
+	ch.fixed = true
+	m.processRetryQueue(context.Background())
+
+	if ch.sent != 1 || q.len() != 0 {
+		t.Errorf("got %d sent and %d queued, want 1 and 0", ch.sent, q.len())
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	for attempts := 1; attempts < 20; attempts++ {
+		d := retryDelay(attempts, errors.New("test error"))
+		if d < retryBaseDelay/2 || d > retryMaxDelay {
+			t.Errorf("retryDelay(%d) = %s, out of bounds", attempts, d)
+		}
+	}
+}
+
+func TestNewTelegramRateLimitError(t *testing.T) {
+	err := newTelegramRateLimitError([]byte(`{"ok":false,"error_code":429,"parameters":{"retry_after":42}}`))
+
+	raErr, ok := errors.AsType[*retryAfterError](err)
+	if !ok || raErr.after != 42*time.Second {
+		t.Errorf("got %v, want retry after 42s", err)
+	}
+
+	err = newTelegramRateLimitError([]byte(`Too Many Requests`))
+	if _, ok = errors.AsType[*retryAfterError](err); ok || err == nil {
+		t.Errorf("got %v, want a plain error", err)
+	}
+}
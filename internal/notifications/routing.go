@@ -0,0 +1,127 @@
+package notifications
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// telegramTarget is the name of the Telegram bot in the routing rules.
+const telegramTarget = "telegram"
+
+// TargetNames are the names of all notification targets, which can be used in
+// the routing rules.
+var TargetNames = []string{
+	telegramTarget,
+	"matrix",
+	"gotify",
+	"mqtt",
+	"signal",
+	"pagerduty",
+	"opsgenie",
+	"teams",
+	"alertmanager",
+}
+
+// RouteEventTypes are the event types, which can be used in the routing rules.
+var RouteEventTypes = []string{
+	string(eventTypeAlert),
+	string(eventTypeRecovery),
+	string(eventTypeFilterUpdate),
+	string(eventTypeFilterUpdateFailure),
+	string(eventTypeCertExpiry),
+	string(eventTypeCertRenewal),
+	string(eventTypeStartup),
+	string(eventTypeShutdown),
+	string(eventTypeUncleanRestart),
+	string(eventTypeConfigChange),
+	string(eventTypeDHCPLease),
+	string(eventTypeBlockedSpike),
+	string(eventTypeUpdateAvailable),
+	string(eventTypePublicIPChange),
+	string(eventTypeInterfaceChange),
+	string(eventTypeStorageError),
+	string(eventTypeStatsReport),
+	string(eventTypeHeartbeat),
+}
+
+// ValidateRoutes returns an error if the routing rules are invalid.  The keys
+// of routes are either event types, like "filter_update", or event types with
+// metrics, like "alert:disk".  The values are the target names, an empty list
+// mutes the matching events.
+func ValidateRoutes(routes map[string][]string) (err error) {
+	for _, key := range slices.Sorted(maps.Keys(routes)) {
+		typ, metric, hasMetric := strings.Cut(key, ":")
+		if !slices.Contains(RouteEventTypes, typ) {
+			return fmt.Errorf("route %q: unsupported event type %q", key, typ)
+		} else if hasMetric && metric == "" {
+			return fmt.Errorf("route %q: empty metric", key)
+		}
+
+		for _, target := range routes[key] {
+			if !slices.Contains(TargetNames, target) {
+				return fmt.Errorf("route %q: unknown target %q", key, target)
+			}
+		}
+	}
+
+	return nil
+}
+
+// UpdateRoutes applies the new routing rules, see [ValidateRoutes].  The
+// events not matching any rule are delivered to all targets.
+func (m *Manager) UpdateRoutes(routes map[string][]string) (err error) {
+	err = ValidateRoutes(routes)
+	if err != nil {
+		return err
+	}
+
+	routes = maps.Clone(routes)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.routes = routes
+
+	return nil
+}
+
+// routeTargets returns the names of the targets for the event of type typ
+// about metric.  ok is false if no rule matches, so the event goes to all
+// targets.  The recoveries without their own rule follow the alert rules.
+func (m *Manager) routeTargets(typ eventType, metric string) (targets []string, ok bool) {
+	keys := routeKeys(typ, metric)
+	if typ == eventTypeRecovery {
+		keys = append(keys, routeKeys(eventTypeAlert, metric)...)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, key := range keys {
+		targets, ok = m.routes[key]
+		if ok {
+			return targets, true
+		}
+	}
+
+	return nil, false
+}
+
+// routeKeys returns the keys of the routing rules matching the event of type
+// typ about metric from the most specific to the least.
+func routeKeys(typ eventType, metric string) (keys []string) {
+	if metric != "" {
+		keys = append(keys, string(typ)+":"+metric)
+	}
+
+	return append(keys, string(typ))
+}
+
+// isRouted returns true if the event should be delivered to the target with
+// the given name according to targets and ok returned by
+// [Manager.routeTargets].
+func isRouted(targets []string, ok bool, name string) (routed bool) {
+	return !ok || slices.Contains(targets, name)
+}
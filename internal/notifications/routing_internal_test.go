@@ -0,0 +1,100 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"testing"
+)
+
+// recordingChannel is a [channel] recording the types of the sent events.
+type recordingChannel struct {
+	chanName string
+	got      []eventType
+}
+
+// name implements the [channel] interface for *recordingChannel.
+func (c *recordingChannel) name() (n string) { return c.chanName }
+
+// send implements the [channel] interface for *recordingChannel.
+func (c *recordingChannel) send(_ context.Context, ev *event) (err error) {
+	c.got = append(c.got, ev.typ)
+
+	return nil
+}
+
+func TestManager_deliver_routes(t *testing.T) {
+	matrix := &recordingChannel{chanName: "matrix"}
+	gotify := &recordingChannel{chanName: "gotify"}
+
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	m.channels = []channel{matrix, gotify}
+
+	err := m.UpdateRoutes(map[string][]string{
+		"alert:disk":    {"matrix"},
+		"filter_update": {"gotify"},
+		"cert_expiry":   {},
+	})
+	if err != nil {
+		t.Fatalf("updating routes: %s", err)
+	}
+
+	ctx := context.Background()
+	for _, ev := range []*event{
+		{typ: eventTypeAlert, metric: "disk"},
+		{typ: eventTypeRecovery, metric: "disk"},
+		{typ: eventTypeAlert, metric: "cpu"},
+		{typ: eventTypeFilterUpdate},
+		{typ: eventTypeCertExpiry},
+	} {
+		if err = m.deliver(ctx, TelegramConfig{}, ev); err != nil {
+			t.Fatalf("delivering %s: %s", ev.typ, err)
+		}
+	}
+
+	wantMatrix := []eventType{eventTypeAlert, eventTypeRecovery, eventTypeAlert}
+	if !slices.Equal(matrix.got, wantMatrix) {
+		t.Errorf("matrix got %v, want %v", matrix.got, wantMatrix)
+	}
+
+	wantGotify := []eventType{eventTypeAlert, eventTypeFilterUpdate}
+	if !slices.Equal(gotify.got, wantGotify) {
+		t.Errorf("gotify got %v, want %v", gotify.got, wantGotify)
+	}
+}
+
+func TestValidateRoutes(t *testing.T) {
+	testCases := []struct {
+		routes  map[string][]string
+		name    string
+		wantErr string
+	}{{
+		routes: map[string][]string{"alert:cpu": {"telegram", "matrix"}, "recovery": nil},
+		name:   "valid",
+	}, {
+		routes:  map[string][]string{"unknown": {"telegram"}},
+		name:    "bad_type",
+		wantErr: `route "unknown": unsupported event type "unknown"`,
+	}, {
+		routes:  map[string][]string{"alert:": {"telegram"}},
+		name:    "empty_metric",
+		wantErr: `route "alert:": empty metric`,
+	}, {
+		routes:  map[string][]string{"alert": {"email"}},
+		name:    "bad_target",
+		wantErr: `route "alert": unknown target "email"`,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateRoutes(tc.routes)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %s", err)
+				}
+			} else if err == nil || err.Error() != tc.wantErr {
+				t.Errorf("got error %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
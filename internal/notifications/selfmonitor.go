@@ -0,0 +1,120 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+)
+
+// The metrics of the resource usage of the AdGuard Home process itself.
+const (
+	metricSelfRSS        = "self_rss"
+	metricSelfGoroutines = "self_goroutines"
+	metricSelfOpenFiles  = "self_open_files"
+	metricSelfGCPause    = "self_gc_pause"
+)
+
+// SelfMonitorConfig contains runtime configuration for the alerts about the
+// resource usage of the AdGuard Home process itself.  A zero limit means that
+// the corresponding metric isn't monitored.
+type SelfMonitorConfig struct {
+	// MaxRSS is the resident set size of the process in bytes.
+	MaxRSS uint64
+
+	// MaxGoroutines is the number of the goroutines.
+	MaxGoroutines int
+
+	// MaxOpenFiles is the number of the open file descriptors.  It's not
+	// available on Windows.
+	MaxOpenFiles int
+
+	// MaxGCPause is the longest garbage collection pause since the previous
+	// check.
+	MaxGCPause time.Duration
+
+	Enabled bool
+}
+
+// ValidateSelfMonitorConfig returns an error if the enabled self-monitoring
+// configuration is invalid.
+func ValidateSelfMonitorConfig(conf SelfMonitorConfig) (err error) {
+	if !conf.Enabled {
+		return nil
+	}
+
+	if conf.MaxGoroutines < 0 {
+		return fmt.Errorf("max_goroutines: must not be negative, got %d", conf.MaxGoroutines)
+	} else if conf.MaxOpenFiles < 0 {
+		return fmt.Errorf("max_open_files: must not be negative, got %d", conf.MaxOpenFiles)
+	} else if conf.MaxGCPause < 0 {
+		return fmt.Errorf("max_gc_pause: must not be negative, got %s", conf.MaxGCPause)
+	}
+
+	if conf == (SelfMonitorConfig{Enabled: true}) {
+		return fmt.Errorf("at least one limit is required")
+	}
+
+	return nil
+}
+
+// UpdateSelfMonitorConfig applies the new self-monitoring configuration.
+func (m *Manager) UpdateSelfMonitorConfig(conf SelfMonitorConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.selfMonitor = conf
+}
+
+// checkSelfMonitor handles the resource usage metrics of the process.  The
+// metrics with no limits are resolved.
+func (m *Manager) checkSelfMonitor(ctx context.Context, cfg TelegramConfig, info systeminfo.Info) {
+	m.mu.Lock()
+	conf := m.selfMonitor
+	since := m.selfGCCheck
+	m.selfGCCheck = time.Now()
+	m.mu.Unlock()
+
+	if !conf.Enabled {
+		conf = SelfMonitorConfig{}
+	}
+
+	pause := maxGCPauseSince(since)
+
+	m.handleMetric(ctx, cfg, metricSelfRSS, float64(info.SelfMemBytes), float64(conf.MaxRSS), info)
+	m.handleMetric(
+		ctx,
+		cfg,
+		metricSelfGoroutines,
+		float64(runtime.NumGoroutine()),
+		float64(conf.MaxGoroutines),
+		info,
+	)
+	m.handleMetric(ctx, cfg, metricSelfOpenFiles, float64(info.SelfOpenFiles), float64(conf.MaxOpenFiles), info)
+	m.handleMetric(ctx, cfg, metricSelfGCPause, durationMillis(pause), durationMillis(conf.MaxGCPause), info)
+}
+
+// maxGCPauseSince returns the longest garbage collection pause, which has
+// ended after since.  Only the recent pauses are kept by the runtime.
+func maxGCPauseSince(since time.Time) (pause time.Duration) {
+	stats := &debug.GCStats{}
+	debug.ReadGCStats(stats)
+
+	for i, end := range stats.PauseEnd {
+		if !end.After(since) || i >= len(stats.Pause) {
+			break
+		}
+
+		pause = max(pause, stats.Pause[i])
+	}
+
+	return pause
+}
+
+// durationMillis returns d in milliseconds.
+func durationMillis(d time.Duration) (ms float64) {
+	return float64(d) / float64(time.Millisecond)
+}
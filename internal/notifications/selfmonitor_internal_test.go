@@ -0,0 +1,110 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+)
+
+func TestManager_checkSelfMonitor(t *testing.T) {
+	rec := &recordingChannel{chanName: "matrix"}
+
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	m.channels = []channel{rec}
+	m.UpdateSelfMonitorConfig(SelfMonitorConfig{
+		MaxRSS:        100 << 20,
+		MaxGoroutines: 1,
+		Enabled:       true,
+	})
+
+	ctx := context.Background()
+	m.checkSelfMonitor(ctx, TelegramConfig{}, systeminfo.Info{SelfMemBytes: 200 << 20})
+
+	for _, metric := range []string{metricSelfRSS, metricSelfGoroutines} {
+		if active, _ := m.metricState(metric); !active {
+			t.Errorf("%s alert is not active", metric)
+		}
+	}
+
+	// Disabling resolves the alerts.
+	m.UpdateSelfMonitorConfig(SelfMonitorConfig{})
+	m.checkSelfMonitor(ctx, TelegramConfig{}, systeminfo.Info{SelfMemBytes: 200 << 20})
+
+	want := []eventType{eventTypeAlert, eventTypeAlert, eventTypeRecovery, eventTypeRecovery}
+	if !slices.Equal(rec.got, want) {
+		t.Errorf("got events %q, want %q", rec.got, want)
+	}
+}
+
+func TestValidateSelfMonitorConfig(t *testing.T) {
+	testCases := []struct {
+		name    string
+		in      SelfMonitorConfig
+		wantErr bool
+	}{{
+		name:    "disabled",
+		in:      SelfMonitorConfig{},
+		wantErr: false,
+	}, {
+		name:    "valid",
+		in:      SelfMonitorConfig{MaxGCPause: 100 * time.Millisecond, Enabled: true},
+		wantErr: false,
+	}, {
+		name:    "no_limits",
+		in:      SelfMonitorConfig{Enabled: true},
+		wantErr: true,
+	}, {
+		name:    "negative",
+		in:      SelfMonitorConfig{MaxGoroutines: -1, Enabled: true},
+		wantErr: true,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateSelfMonitorConfig(tc.in)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("got error %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestFormatMetricValue(t *testing.T) {
+	testCases := []struct {
+		metric string
+		want   string
+		v      float64
+	}{{
+		metric: "cpu",
+		want:   "91.5%",
+		v:      91.5,
+	}, {
+		metric: metricTemperature,
+		want:   "72 °C",
+		v:      72,
+	}, {
+		metric: bandwidthMetricPrefix + "eth0",
+		want:   "120.5 Mbps",
+		v:      120.5,
+	}, {
+		metric: metricSelfGoroutines,
+		want:   "12,000",
+		v:      12_000,
+	}, {
+		metric: metricSelfGCPause,
+		want:   "150 ms",
+		v:      150,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.metric, func(t *testing.T) {
+			if got := formatMetricValue(tc.metric, tc.v); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
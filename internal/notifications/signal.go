@@ -0,0 +1,84 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// SignalConfig contains runtime configuration for Signal notifications sent
+// through a signal-cli-rest-api server.
+type SignalConfig struct {
+	Enabled    bool
+	BaseURL    string
+	Number     string
+	Recipients []string
+	Proxy      string
+}
+
+// signalChannel delivers notifications to Signal recipients using the
+// signal-cli-rest-api server.
+type signalChannel struct {
+	client *http.Client
+	conf   SignalConfig
+}
+
+// newSignalChannel returns a new Signal channel.
+func newSignalChannel(client *http.Client, conf SignalConfig) (c *signalChannel) {
+	return &signalChannel{
+		client: client,
+		conf:   conf,
+	}
+}
+
+// type check
+var _ channel = (*signalChannel)(nil)
+
+// name implements the [channel] interface for *signalChannel.
+func (c *signalChannel) name() (n string) { return "signal" }
+
+// signalSendRequest is the request of the send endpoint of the
+// signal-cli-rest-api.
+type signalSendRequest struct {
+	Message    string   `json:"message"`
+	Number     string   `json:"number"`
+	Recipients []string `json:"recipients"`
+}
+
+// send implements the [channel] interface for *signalChannel.
+func (c *signalChannel) send(ctx context.Context, ev *event) (err error) {
+	body, err := json.Marshal(signalSendRequest{
+		Message:    htmlToText(ev.text),
+		Number:     c.conf.Number,
+		Recipients: c.conf.Recipients,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal signal message: %w", err)
+	}
+
+	endpoint := strings.TrimSuffix(c.conf.BaseURL, "/") + "/v2/send"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	traceResponse(ctx, resp.StatusCode, respBody)
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("signal api status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return nil
+}
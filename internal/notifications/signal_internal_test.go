@@ -0,0 +1,46 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestSignalChannel_Send(t *testing.T) {
+	var got signalSendRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/send" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding request: %s", err)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := newSignalChannel(srv.Client(), SignalConfig{
+		Enabled:    true,
+		BaseURL:    srv.URL + "/",
+		Number:     "+10000000000",
+		Recipients: []string{"+10000000001", "+10000000002"},
+	})
+
+	err := c.send(context.Background(), &event{text: "<b>disk</b> &amp; memory"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got.Message != "disk & memory" || got.Number != "+10000000000" {
+		t.Errorf("unexpected request %+v", got)
+	}
+
+	if !slices.Equal(got.Recipients, []string{"+10000000001", "+10000000002"}) {
+		t.Errorf("unexpected recipients %q", got.Recipients)
+	}
+}
@@ -0,0 +1,259 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// eventTypeStatsReport is the type of the scheduled statistics summary events.
+const eventTypeStatsReport eventType = "stats_report"
+
+// statsReportTopCount is the maximum number of the entries in each top list of
+// the statistics summary.
+const statsReportTopCount = 5
+
+// StatsReportPeriod is the period covered by the statistics summary.
+type StatsReportPeriod string
+
+// Available statistics summary periods.
+const (
+	StatsReportDaily  StatsReportPeriod = "daily"
+	StatsReportWeekly StatsReportPeriod = "weekly"
+)
+
+// duration returns the length of p.
+func (p StatsReportPeriod) duration() (d time.Duration) {
+	if p == StatsReportWeekly {
+		return 7 * 24 * time.Hour
+	}
+
+	return 24 * time.Hour
+}
+
+// StatsReportConfig contains runtime configuration for the scheduled
+// statistics summary.  The summary is sent at At, the offset from midnight,
+// every day, or on Weekday only if Period is [StatsReportWeekly].  Location is
+// the time zone of the schedule, [time.Local] is used if it's nil.
+type StatsReportConfig struct {
+	Location *time.Location
+	Period   StatsReportPeriod
+	At       time.Duration
+	Weekday  time.Weekday
+	Enabled  bool
+}
+
+// lastDue returns the latest scheduled time of the summary not after now.
+func (conf *StatsReportConfig) lastDue(now time.Time) (due time.Time) {
+	loc := conf.Location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	now = now.In(loc)
+	y, mon, d := now.Date()
+
+	step, back := 1, 0
+	if conf.Period == StatsReportWeekly {
+		step, back = 7, int(now.Weekday()-conf.Weekday+7)%7
+	}
+
+	due = time.Date(y, mon, d-back, 0, 0, 0, 0, loc).Add(conf.At)
+	if due.After(now) {
+		due = time.Date(y, mon, d-back-step, 0, 0, 0, 0, loc).Add(conf.At)
+	}
+
+	return due
+}
+
+// StatsTopEntry is an entry of a top list of the statistics summary.
+type StatsTopEntry struct {
+	// Name is the client, the domain, or the upstream.
+	Name string
+
+	// Count is the number of requests.
+	Count uint64
+}
+
+// UpstreamLatency is the average response time of an upstream.
+type UpstreamLatency struct {
+	// Address is the address of the upstream.
+	Address string
+
+	// AvgTime is the average response time of the upstream.
+	AvgTime time.Duration
+}
+
+// StatsSummary contains the DNS statistics for a period of time.
+type StatsSummary struct {
+	// TopClients are the clients with the most requests.
+	TopClients []StatsTopEntry
+
+	// TopBlocked are the most blocked domains.
+	TopBlocked []StatsTopEntry
+
+	// Upstreams are the upstreams with the highest average response time.
+	Upstreams []UpstreamLatency
+
+	// Period is the period the statistics are collected over.  It may be
+	// shorter than the requested one if the statistics are retained for less
+	// time.
+	Period time.Duration
+
+	// AvgProcessingTime is the average processing time of the requests.
+	AvgProcessingTime time.Duration
+
+	// NumQueries is the total number of requests.
+	NumQueries uint64
+
+	// NumBlocked is the number of requests blocked by the filters, safe
+	// browsing, and parental control.
+	NumBlocked uint64
+}
+
+// StatsSummaryProvider provides the statistics summaries.
+type StatsSummaryProvider interface {
+	// StatsSummary returns the statistics for the last period with at most
+	// limit entries in each top list.  ok is false if the statistics are
+	// unavailable.
+	StatsSummary(period time.Duration, limit int) (sum StatsSummary, ok bool)
+}
+
+// SetStatsSummaryProvider injects the provider of the statistics summaries.
+func (m *Manager) SetStatsSummaryProvider(sp StatsSummaryProvider) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.summaries = sp
+}
+
+// UpdateStatsReportConfig applies the new configuration of the scheduled
+// statistics summary.
+func (m *Manager) UpdateStatsReportConfig(conf StatsReportConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.statsReport = conf
+}
+
+// checkStatsReport sends the statistics summary if it's due.  The summaries
+// scheduled before the start of the manager aren't sent.
+func (m *Manager) checkStatsReport(ctx context.Context, cfg TelegramConfig) {
+	now := time.Now()
+
+	m.mu.Lock()
+	conf := m.statsReport
+	sp := m.summaries
+	due := conf.lastDue(now)
+	if !conf.Enabled || sp == nil || due.Before(m.startTime) || !due.After(m.statsReportLast) {
+		m.mu.Unlock()
+
+		return
+	}
+
+	m.statsReportLast = due
+	m.mu.Unlock()
+
+	sum, ok := sp.StatsSummary(conf.Period.duration(), statsReportTopCount)
+	if !ok {
+		m.logger.Debug("statistics summary is unavailable")
+
+		return
+	}
+
+	ev := &event{
+		time:   now,
+		typ:    eventTypeStatsReport,
+		metric: string(conf.Period),
+		text:   composeStatsReportMessage(cfg, conf.Period, sum),
+	}
+
+	if err := m.deliver(ctx, cfg, ev); err != nil {
+		m.logger.Error("statistics summary notification failed", slog.String("error", err.Error()))
+	}
+}
+
+// composeStatsReportMessage formats the statistics summary notification.
+func composeStatsReportMessage(cfg TelegramConfig, period StatsReportPeriod, sum StatsSummary) (msg string) {
+	lines := make([]string, 0, 32)
+	if prefix := strings.TrimSpace(cfg.CustomMessage); prefix != "" {
+		lines = append(lines, prefix, "")
+	}
+
+	blockPct := "-"
+	if sum.NumQueries > 0 {
+		blockPct = formatPercentage(float64(sum.NumBlocked) / float64(sum.NumQueries) * 100)
+	}
+
+	lines = append(lines,
+		fmt.Sprintf("📊 <b>%s Statistics Report</b>", capitalizeFirst(string(period))),
+		divider(),
+		"",
+		sectionHeader("🔍", "Query Summary"),
+		fmt.Sprintf("  ▸ <b>Period:</b>        <code>%s</code>", formatReportPeriod(sum.Period)),
+		fmt.Sprintf("  ▸ <b>Total Queries:</b> <code>%s</code>", formatUint64(sum.NumQueries)),
+		fmt.Sprintf("  ▸ <b>Blocked:</b>       <code>%s</code> (%s)", formatUint64(sum.NumBlocked), blockPct),
+		fmt.Sprintf("  ▸ <b>Avg Response:</b>  <code>%s ms</code>", formatFloat(msFloat(sum.AvgProcessingTime))),
+	)
+
+	lines = appendTopEntries(lines, "👥", "Top Clients", sum.TopClients)
+	lines = appendTopEntries(lines, "🚫", "Top Blocked Domains", sum.TopBlocked)
+
+	if len(sum.Upstreams) > 0 {
+		lines = append(lines, "", sectionHeader("⚡", "Upstream Latency"))
+		for _, u := range sum.Upstreams {
+			lines = append(lines, fmt.Sprintf(
+				"  ▸ <code>%s</code>: %s ms",
+				html.EscapeString(u.Address),
+				formatFloat(msFloat(u.AvgTime)),
+			))
+		}
+	}
+
+	lines = append(lines, "", divider(), timestampLine())
+
+	return strings.Join(lines, "\n")
+}
+
+// appendTopEntries appends the section with the top list entries to lines, if
+// there are any.
+func appendTopEntries(lines []string, icon, title string, entries []StatsTopEntry) (res []string) {
+	if len(entries) == 0 {
+		return lines
+	}
+
+	lines = append(lines, "", sectionHeader(icon, title))
+	for i, e := range entries {
+		lines = append(lines, fmt.Sprintf(
+			"  %d. <code>%s</code>: %s",
+			i+1,
+			html.EscapeString(e.Name),
+			formatUint64(e.Count),
+		))
+	}
+
+	return lines
+}
+
+// formatReportPeriod formats the period of the statistics summary, which is
+// usually a whole number of days.
+func formatReportPeriod(d time.Duration) (s string) {
+	const day = 24 * time.Hour
+
+	switch {
+	case d == day:
+		return "1 day"
+	case d > day && d%day == 0:
+		return fmt.Sprintf("%d days", d/day)
+	default:
+		return d.String()
+	}
+}
+
+// msFloat returns d in milliseconds.
+func msFloat(d time.Duration) (ms float64) {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,119 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSummaryProvider is a [StatsSummaryProvider] returning sum.
+type fakeSummaryProvider struct {
+	sum StatsSummary
+}
+
+// StatsSummary implements the [StatsSummaryProvider] interface for
+// *fakeSummaryProvider.
+func (p *fakeSummaryProvider) StatsSummary(period time.Duration, _ int) (sum StatsSummary, ok bool) {
+	sum = p.sum
+	sum.Period = period
+
+	return sum, true
+}
+
+func TestStatsReportConfig_lastDue(t *testing.T) {
+	// 2024-05-15 is Wednesday.
+	now := time.Date(2024, 5, 15, 10, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		want time.Time
+		conf StatsReportConfig
+		name string
+	}{{
+		want: time.Date(2024, 5, 15, 9, 0, 0, 0, time.UTC),
+		conf: StatsReportConfig{Location: time.UTC, Period: StatsReportDaily, At: 9 * time.Hour},
+		name: "daily_today",
+	}, {
+		want: time.Date(2024, 5, 14, 11, 0, 0, 0, time.UTC),
+		conf: StatsReportConfig{Location: time.UTC, Period: StatsReportDaily, At: 11 * time.Hour},
+		name: "daily_yesterday",
+	}, {
+		want: time.Date(2024, 5, 13, 9, 0, 0, 0, time.UTC),
+		conf: StatsReportConfig{
+			Location: time.UTC,
+			Period:   StatsReportWeekly,
+			At:       9 * time.Hour,
+			Weekday:  time.Monday,
+		},
+		name: "weekly_this_week",
+	}, {
+		want: time.Date(2024, 5, 8, 11, 0, 0, 0, time.UTC),
+		conf: StatsReportConfig{
+			Location: time.UTC,
+			Period:   StatsReportWeekly,
+			At:       11 * time.Hour,
+			Weekday:  time.Wednesday,
+		},
+		name: "weekly_last_week",
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.conf.lastDue(now); !got.Equal(tc.want) {
+				t.Errorf("got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestManager_checkStatsReport(t *testing.T) {
+	rec := &recordingChannel{chanName: "matrix"}
+
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	m.channels = []channel{rec}
+	m.startTime = time.Now().Add(-48 * time.Hour)
+	m.SetStatsSummaryProvider(&fakeSummaryProvider{})
+
+	ctx := context.Background()
+	m.checkStatsReport(ctx, TelegramConfig{})
+	if len(rec.got) != 0 {
+		t.Fatalf("got %d reports while disabled, want 0", len(rec.got))
+	}
+
+	m.UpdateStatsReportConfig(StatsReportConfig{Period: StatsReportDaily, Enabled: true})
+	m.checkStatsReport(ctx, TelegramConfig{})
+	m.checkStatsReport(ctx, TelegramConfig{})
+	if len(rec.got) != 1 || rec.got[0] != eventTypeStatsReport {
+		t.Errorf("got %q, want a single %q", rec.got, eventTypeStatsReport)
+	}
+}
+
+func TestComposeStatsReportMessage(t *testing.T) {
+	msg := composeStatsReportMessage(TelegramConfig{}, StatsReportWeekly, StatsSummary{
+		TopClients: []StatsTopEntry{{Name: "192.168.1.2", Count: 1500}},
+		TopBlocked: []StatsTopEntry{{Name: "ads.example", Count: 42}},
+		Upstreams: []UpstreamLatency{{
+			Address: "https://dns.example/dns-query",
+			AvgTime: 25 * time.Millisecond,
+		}},
+		Period:            7 * 24 * time.Hour,
+		AvgProcessingTime: 3 * time.Millisecond,
+		NumQueries:        2000,
+		NumBlocked:        500,
+	})
+
+	for _, want := range []string{
+		"Weekly Statistics Report",
+		"7 days",
+		"2,000",
+		"25%",
+		"192.168.1.2",
+		"ads.example",
+		"https://dns.example/dns-query</code>: 25 ms",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message %q doesn't contain %q", msg, want)
+		}
+	}
+}
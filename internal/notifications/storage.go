@@ -0,0 +1,158 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// eventTypeStorageError is the type of the failed query log and statistics
+// database write events.
+const eventTypeStorageError eventType = "storage_error"
+
+// storageErrorInterval is the minimum interval between the notifications about
+// the same kind of failure of the same component.
+const storageErrorInterval = time.Hour
+
+// StorageComponent is the name of the component storing the data on disk.
+type StorageComponent string
+
+// Available storage components.
+const (
+	StorageComponentQueryLog StorageComponent = "querylog"
+	StorageComponentStats    StorageComponent = "stats"
+)
+
+// StorageFailureReason is the kind of a storage failure.
+type StorageFailureReason string
+
+// Available storage failure reasons.
+const (
+	StorageFailureReasonDiskFull   StorageFailureReason = "disk_full"
+	StorageFailureReasonPermission StorageFailureReason = "permission"
+	StorageFailureReasonCorruption StorageFailureReason = "corruption"
+	StorageFailureReasonOther      StorageFailureReason = "other"
+)
+
+// StorageFailure describes a failure to write the data of a component to disk.
+type StorageFailure struct {
+	// Component is the component, which has failed to write its data.
+	Component StorageComponent
+
+	// Reason is the kind of the failure.
+	Reason StorageFailureReason
+
+	// Path is the path to the file or database, if known.
+	Path string
+
+	// Error is the text of the error.
+	Error string
+}
+
+// NotifyStorageError sends the notification about the failed write of the
+// component's data.  The repeated failures of the same kind are only reported
+// once per [storageErrorInterval].
+func (m *Manager) NotifyStorageError(ctx context.Context, failure StorageFailure) {
+	cfg := m.getTelegramConfig()
+	if !m.isReady(cfg) {
+		return
+	}
+
+	now := time.Now()
+	if !m.storageErrorDue(failure, now) {
+		return
+	}
+
+	ev := &event{
+		time:   now,
+		typ:    eventTypeStorageError,
+		metric: string(failure.Component),
+		text:   composeStorageErrorMessage(cfg, failure),
+	}
+
+	if err := m.deliver(ctx, cfg, ev); err != nil {
+		m.logger.Error("storage error notification failed",
+			"component", string(failure.Component),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// storageErrorDue returns true if the notification about failure should be
+// sent at now and records it as sent.
+func (m *Manager) storageErrorDue(failure StorageFailure, now time.Time) (ok bool) {
+	key := string(failure.Component) + ":" + string(failure.Reason)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if last, sent := m.storageErrors[key]; sent && now.Sub(last) < storageErrorInterval {
+		return false
+	}
+
+	if m.storageErrors == nil {
+		m.storageErrors = map[string]time.Time{}
+	}
+
+	m.storageErrors[key] = now
+
+	return true
+}
+
+// storageComponentLabel returns the human-readable name of the storage
+// component.
+func storageComponentLabel(c StorageComponent) (label string) {
+	switch c {
+	case StorageComponentQueryLog:
+		return "Query log"
+	case StorageComponentStats:
+		return "Statistics"
+	default:
+		return fallbackString(string(c))
+	}
+}
+
+// storageFailureLabel returns the human-readable description of the storage
+// failure reason.
+func storageFailureLabel(reason StorageFailureReason) (label string) {
+	switch reason {
+	case StorageFailureReasonDiskFull:
+		return "Disk is full"
+	case StorageFailureReasonPermission:
+		return "Permission denied"
+	case StorageFailureReasonCorruption:
+		return "Data is corrupted"
+	default:
+		return "Write failed"
+	}
+}
+
+// composeStorageErrorMessage formats the failed write notification.
+func composeStorageErrorMessage(cfg TelegramConfig, failure StorageFailure) (msg string) {
+	lines := make([]string, 0, 12)
+	if prefix := strings.TrimSpace(cfg.CustomMessage); prefix != "" {
+		lines = append(lines, prefix, "")
+	}
+
+	lines = append(lines,
+		fmt.Sprintf("💾 <b>%s Storage Error</b>", storageComponentLabel(failure.Component)),
+		divider(),
+		"",
+		fmt.Sprintf("  ▸ <b>Reason:</b> %s", storageFailureLabel(failure.Reason)),
+	)
+
+	if failure.Path != "" {
+		lines = append(lines, fmt.Sprintf("  ▸ <b>Path:</b>   <code>%s</code>", html.EscapeString(failure.Path)))
+	}
+
+	if failure.Error != "" {
+		lines = append(lines, fmt.Sprintf("  ▸ <b>Error:</b>  <code>%s</code>", html.EscapeString(failure.Error)))
+	}
+
+	lines = append(lines, "", divider(), timestampLine())
+
+	return strings.Join(lines, "\n")
+}
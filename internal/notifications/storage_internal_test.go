@@ -0,0 +1,59 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestManager_NotifyStorageError(t *testing.T) {
+	rec := &recordingChannel{chanName: "matrix"}
+
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	m.channels = []channel{rec}
+
+	ctx := context.Background()
+	diskFull := StorageFailure{
+		Component: StorageComponentQueryLog,
+		Reason:    StorageFailureReasonDiskFull,
+	}
+
+	m.NotifyStorageError(ctx, diskFull)
+	m.NotifyStorageError(ctx, diskFull)
+	if len(rec.got) != 1 {
+		t.Fatalf("got %d notifications about the same failure, want 1", len(rec.got))
+	}
+
+	m.NotifyStorageError(ctx, StorageFailure{
+		Component: StorageComponentStats,
+		Reason:    StorageFailureReasonDiskFull,
+	})
+	m.NotifyStorageError(ctx, StorageFailure{
+		Component: StorageComponentQueryLog,
+		Reason:    StorageFailureReasonPermission,
+	})
+	if len(rec.got) != 3 {
+		t.Errorf("got %d notifications, want 3", len(rec.got))
+	}
+}
+
+func TestComposeStorageErrorMessage(t *testing.T) {
+	msg := composeStorageErrorMessage(TelegramConfig{}, StorageFailure{
+		Component: StorageComponentStats,
+		Reason:    StorageFailureReasonCorruption,
+		Path:      "/opt/AdGuardHome/data/stats.db",
+		Error:     "decoding unit: <eof>",
+	})
+
+	for _, want := range []string{
+		"Statistics Storage Error",
+		"Data is corrupted",
+		"/opt/AdGuardHome/data/stats.db",
+		"&lt;eof&gt;",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message %q doesn't contain %q", msg, want)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// TeamsConfig contains runtime configuration for Microsoft Teams
+// notifications sent through an incoming webhook.
+type TeamsConfig struct {
+	Enabled    bool
+	WebhookURL string
+	Proxy      string
+}
+
+// teamsChannel delivers notifications as Adaptive Cards to a Microsoft Teams
+// incoming webhook.
+type teamsChannel struct {
+	client *http.Client
+	conf   TeamsConfig
+}
+
+// newTeamsChannel returns a new Microsoft Teams channel.
+func newTeamsChannel(client *http.Client, conf TeamsConfig) (c *teamsChannel) {
+	return &teamsChannel{
+		client: client,
+		conf:   conf,
+	}
+}
+
+// type check
+var _ channel = (*teamsChannel)(nil)
+
+// name implements the [channel] interface for *teamsChannel.
+func (c *teamsChannel) name() (n string) { return "teams" }
+
+// teamsMessage is the message with the Adaptive Card attachment accepted by
+// the incoming webhooks.
+type teamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+// teamsAttachment is the attachment of [teamsMessage].
+type teamsAttachment struct {
+	Content     *teamsCard `json:"content"`
+	ContentType string     `json:"contentType"`
+}
+
+// teamsCard is an Adaptive Card.
+type teamsCard struct {
+	Schema  string           `json:"$schema"`
+	Type    string           `json:"type"`
+	Version string           `json:"version"`
+	Body    []map[string]any `json:"body"`
+}
+
+// teamsFact is a single fact of the Adaptive Card FactSet element.
+type teamsFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// send implements the [channel] interface for *teamsChannel.
+func (c *teamsChannel) send(ctx context.Context, ev *event) (err error) {
+	body, err := json.Marshal(newTeamsMessage(ev))
+	if err != nil {
+		return fmt.Errorf("marshal teams message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.conf.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	traceResponse(ctx, resp.StatusCode, respBody)
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("teams webhook status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return nil
+}
+
+// newTeamsMessage returns the message with the Adaptive Card for ev.
+func newTeamsMessage(ev *event) (msg *teamsMessage) {
+	color := "Default"
+	switch ev.typ {
+	case eventTypeAlert:
+		color = "Attention"
+	case eventTypeRecovery:
+		color = "Good"
+	case eventTypeCertExpiry:
+		color = "Warning"
+	}
+
+	body := []map[string]any{{
+		"type":   "TextBlock",
+		"text":   ev.title(),
+		"weight": "Bolder",
+		"size":   "Medium",
+		"color":  color,
+		"wrap":   true,
+	}}
+
+	if facts := teamsFacts(ev); len(facts) > 0 {
+		body = append(body, map[string]any{
+			"type":  "FactSet",
+			"facts": facts,
+		})
+	}
+
+	body = append(body, map[string]any{
+		"type":     "TextBlock",
+		"text":     htmlToText(ev.text),
+		"wrap":     true,
+		"fontType": "Monospace",
+	})
+
+	return &teamsMessage{
+		Type: "message",
+		Attachments: []teamsAttachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content: &teamsCard{
+				Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+				Type:    "AdaptiveCard",
+				Version: "1.4",
+				Body:    body,
+			},
+		}},
+	}
+}
+
+// teamsFacts returns the key facts of the alerts, the recoveries, and the
+// filter updates.
+func teamsFacts(ev *event) (facts []teamsFact) {
+	switch ev.typ {
+	case eventTypeAlert, eventTypeRecovery:
+		facts = append(facts, teamsFact{Title: "Metric", Value: metricDisplayName(ev.metric)})
+		if ev.threshold > 0 {
+			facts = append(facts,
+				teamsFact{Title: "Current", Value: formatMetricValue(ev.metric, ev.value)},
+				teamsFact{Title: "Threshold", Value: formatMetricValue(ev.metric, ev.threshold)},
+			)
+		}
+	case eventTypeFilterUpdate:
+		if f := ev.filter; f != nil {
+			facts = append(facts,
+				teamsFact{Title: filterTypeLabel(f.ListType), Value: fallbackString(f.Name)},
+				teamsFact{Title: "Rules", Value: strconv.Itoa(f.RulesCount)},
+				teamsFact{Title: "URL", Value: fallbackString(f.URL)},
+			)
+		}
+	}
+
+	return facts
+}
@@ -0,0 +1,49 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTeamsChannel_Send(t *testing.T) {
+	var got teamsMessage
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding request: %s", err)
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := newTeamsChannel(srv.Client(), TeamsConfig{Enabled: true, WebhookURL: srv.URL})
+
+	err := c.send(context.Background(), &event{
+		typ:  eventTypeFilterUpdate,
+		text: "<b>Blocklist Updated</b>",
+		filter: &FilterUpdate{
+			Name:       "AdGuard DNS filter",
+			RulesCount: 42,
+			ListType:   FilterListTypeBlock,
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(got.Attachments) != 1 || got.Attachments[0].Content == nil {
+		t.Fatalf("unexpected message %+v", got)
+	}
+
+	card := got.Attachments[0].Content
+	if card.Type != "AdaptiveCard" || len(card.Body) != 3 {
+		t.Fatalf("unexpected card %+v", card)
+	}
+
+	if card.Body[0]["text"] != "Filter list updated" || card.Body[1]["type"] != "FactSet" {
+		t.Errorf("unexpected card body %+v", card.Body)
+	}
+}
@@ -0,0 +1,94 @@
+package notifications
+
+import (
+	"slices"
+	"strings"
+)
+
+// telegramChatTargetPrefix is the prefix of the history and retry queue
+// targets of the additional Telegram chats.
+const telegramChatTargetPrefix = telegramTarget + ":"
+
+// TelegramChat is an additional chat receiving the notifications from the bot.
+// Events use the keys of the routing rules, and all events are sent to the
+// chat if it's empty.  Unlike the main chat, the bot doesn't accept commands
+// from the additional ones.
+type TelegramChat struct {
+	ID              string
+	Events          []string
+	MessageThreadID int64
+	Enabled         bool
+}
+
+// accepts returns true if ev should be sent to c.
+func (c *TelegramChat) accepts(ev *event) (ok bool) {
+	if !c.Enabled {
+		return false
+	} else if len(c.Events) == 0 || ev.typ == eventTypeTest {
+		return true
+	}
+
+	for _, key := range routeKeys(ev.typ, ev.metric) {
+		if slices.Contains(c.Events, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// telegramRecipient is a chat to send a notification to.
+type telegramRecipient struct {
+	// target is the name of the recipient in the history and the retry
+	// queue.
+	target string
+
+	// cfg is the configuration with the chat of the recipient.
+	cfg TelegramConfig
+}
+
+// telegramRecipients returns the chats ev should be sent to.
+func telegramRecipients(cfg TelegramConfig, ev *event) (rcpts []telegramRecipient) {
+	if cfg.ChatID != "" {
+		rcpts = append(rcpts, telegramRecipient{target: telegramTarget, cfg: cfg})
+	}
+
+	for _, c := range cfg.Chats {
+		if !c.accepts(ev) || c.ID == cfg.ChatID {
+			continue
+		}
+
+		chatCfg := cfg
+		chatCfg.ChatID = c.ID
+		chatCfg.MessageThreadID = c.MessageThreadID
+		rcpts = append(rcpts, telegramRecipient{target: telegramChatTargetPrefix + c.ID, cfg: chatCfg})
+	}
+
+	return rcpts
+}
+
+// telegramRecipientConfig returns the configuration for sending to the
+// recipient with the given target name.  ok is false if there is no such
+// enabled recipient.
+func telegramRecipientConfig(cfg TelegramConfig, target string) (rcptCfg TelegramConfig, ok bool) {
+	if !isTelegramReady(cfg) {
+		return cfg, false
+	}
+
+	if target == telegramTarget {
+		return cfg, cfg.ChatID != ""
+	}
+
+	id, ok := strings.CutPrefix(target, telegramChatTargetPrefix)
+	if !ok {
+		return cfg, false
+	}
+
+	for _, rcpt := range telegramRecipients(cfg, &event{typ: eventTypeTest}) {
+		if rcpt.target == telegramChatTargetPrefix+id {
+			return rcpt.cfg, true
+		}
+	}
+
+	return cfg, false
+}
@@ -0,0 +1,66 @@
+package notifications
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTelegramRecipients(t *testing.T) {
+	cfg := TelegramConfig{
+		Enabled:  true,
+		BotToken: "token",
+		ChatID:   "1",
+		Chats: []TelegramChat{{
+			ID:      "2",
+			Enabled: true,
+		}, {
+			ID:              "3",
+			Events:          []string{"alert:disk", "filter_update"},
+			MessageThreadID: 7,
+			Enabled:         true,
+		}, {
+			ID:      "4",
+			Enabled: false,
+		}},
+	}
+
+	testCases := []struct {
+		ev   *event
+		name string
+		want []string
+	}{{
+		ev:   &event{typ: eventTypeAlert, metric: "disk"},
+		name: "disk_alert",
+		want: []string{"telegram", "telegram:2", "telegram:3"},
+	}, {
+		ev:   &event{typ: eventTypeAlert, metric: "cpu"},
+		name: "cpu_alert",
+		want: []string{"telegram", "telegram:2"},
+	}, {
+		ev:   &event{typ: eventTypeTest},
+		name: "test",
+		want: []string{"telegram", "telegram:2", "telegram:3"},
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, rcpt := range telegramRecipients(cfg, tc.ev) {
+				got = append(got, rcpt.target)
+			}
+
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	rcptCfg, ok := telegramRecipientConfig(cfg, "telegram:3")
+	if !ok || rcptCfg.ChatID != "3" || rcptCfg.MessageThreadID != 7 {
+		t.Errorf("got %+v, %t for chat 3", rcptCfg, ok)
+	}
+
+	if _, ok = telegramRecipientConfig(cfg, "telegram:4"); ok {
+		t.Error("got disabled chat 4")
+	}
+}
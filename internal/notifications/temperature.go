@@ -0,0 +1,17 @@
+package notifications
+
+import "time"
+
+// metricTemperature is the metric of the CPU temperature alerts.
+const metricTemperature = "temperature"
+
+// cooldown returns the minimum time between the alerts about metric.
+func (cfg *TelegramConfig) cooldown(metric string) (d time.Duration) {
+	if metric == metricTemperature && cfg.TemperatureCooldown > 0 {
+		return cfg.TemperatureCooldown
+	} else if cfg.Cooldown > 0 {
+		return cfg.Cooldown
+	}
+
+	return defaultCooldown
+}
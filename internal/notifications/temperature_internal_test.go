@@ -0,0 +1,66 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+)
+
+func TestTelegramConfig_cooldown(t *testing.T) {
+	cfg := TelegramConfig{
+		Cooldown:            5 * time.Minute,
+		TemperatureCooldown: 30 * time.Minute,
+	}
+
+	if got := cfg.cooldown("cpu"); got != cfg.Cooldown {
+		t.Errorf("got cpu cooldown %s, want %s", got, cfg.Cooldown)
+	}
+
+	if got := cfg.cooldown(metricTemperature); got != cfg.TemperatureCooldown {
+		t.Errorf("got temperature cooldown %s, want %s", got, cfg.TemperatureCooldown)
+	}
+
+	cfg.TemperatureCooldown = 0
+	if got := cfg.cooldown(metricTemperature); got != cfg.Cooldown {
+		t.Errorf("got default temperature cooldown %s, want %s", got, cfg.Cooldown)
+	}
+}
+
+func TestManager_handleMetric_temperature(t *testing.T) {
+	rec := &recordingChannel{chanName: "matrix"}
+
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+	m.channels = []channel{rec}
+
+	cfg := TelegramConfig{TemperatureCooldown: time.Hour}
+
+	ctx := context.Background()
+	for _, v := range []float64{82, 60, 85} {
+		m.handleMetric(ctx, cfg, metricTemperature, v, 80, systeminfo.Info{})
+	}
+
+	// The second alert is within the cooldown.
+	want := []eventType{eventTypeAlert, eventTypeRecovery}
+	if !slices.Equal(rec.got, want) {
+		t.Errorf("got %q, want %q", rec.got, want)
+	}
+}
+
+func TestComposeAlertMessage_temperature(t *testing.T) {
+	msg := composeAlertMessage(TelegramConfig{}, nil, metricTemperature, 82.5, 80, systeminfo.Info{})
+
+	for _, want := range []string{"CPU Temperature", "82.5 °C", "80 °C"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message %q does not contain %q", msg, want)
+		}
+	}
+
+	if strings.Contains(msg, "80%") {
+		t.Errorf("message %q contains the threshold as the percentage", msg)
+	}
+}
@@ -0,0 +1,212 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+)
+
+// TemplateEventTypes are the types of the events, which messages can be
+// customized with templates.
+var TemplateEventTypes = []string{
+	string(eventTypeAlert),
+	string(eventTypeRecovery),
+	string(eventTypeFilterUpdate),
+	string(eventTypeCertExpiry),
+	string(eventTypeCertRenewal),
+}
+
+// templateData is the data passed to the message templates.  Filter is only
+// set for the filter updates, CertExpiry and CertRenewal only for the
+// corresponding certificate events, and Duration only for the recoveries.
+type templateData struct {
+	Time          time.Time
+	Info          systeminfo.Info
+	Filter        *FilterUpdate
+	CertExpiry    *CertExpiryReminder
+	CertRenewal   *CertRenewalResult
+	Type          string
+	Metric        string
+	MetricName    string
+	CustomMessage string
+	Value         float64
+	Threshold     float64
+	Duration      time.Duration
+}
+
+// newTemplateData returns the template data for ev.
+func newTemplateData(cfg TelegramConfig, ev *event, info systeminfo.Info) (d *templateData) {
+	d = &templateData{
+		Time:          ev.time,
+		Info:          info,
+		Filter:        ev.filter,
+		Type:          string(ev.typ),
+		Metric:        ev.metric,
+		CustomMessage: strings.TrimSpace(cfg.CustomMessage),
+		Value:         ev.value,
+		Threshold:     ev.threshold,
+	}
+
+	if ev.metric != "" {
+		d.MetricName = metricDisplayName(ev.metric)
+	}
+
+	return d
+}
+
+// templateFuncs are the functions available in the message templates in
+// addition to the text/template builtins.
+var templateFuncs = template.FuncMap{
+	"percent": formatPercentage,
+	"bar":     usageBar,
+	"bytes":   formatBytesUint,
+	"escape":  html.EscapeString,
+	"upper":   strings.ToUpper,
+	"lower":   strings.ToLower,
+	"timefmt": func(layout string, t time.Time) string { return t.Format(layout) },
+}
+
+// parseTemplates parses the message templates by event type.  Empty templates
+// are skipped.
+func parseTemplates(texts map[string]string) (tmpls map[eventType]*template.Template, err error) {
+	tmpls = make(map[eventType]*template.Template, len(texts))
+	for _, typ := range slices.Sorted(maps.Keys(texts)) {
+		text := texts[typ]
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		var tmpl *template.Template
+		tmpl, err = parseTemplate(typ, text)
+		if err != nil {
+			return nil, err
+		}
+
+		tmpls[eventType(typ)] = tmpl
+	}
+
+	return tmpls, nil
+}
+
+// parseTemplate parses the message template for the event type typ.
+func parseTemplate(typ, text string) (tmpl *template.Template, err error) {
+	if !slices.Contains(TemplateEventTypes, typ) {
+		return nil, fmt.Errorf("unsupported event type %q", typ)
+	}
+
+	tmpl, err = template.New(typ).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s template: %w", typ, err)
+	}
+
+	return tmpl, nil
+}
+
+// executeTemplate renders tmpl with data.
+func executeTemplate(tmpl *template.Template, data *templateData) (msg string, err error) {
+	b := &strings.Builder{}
+	err = tmpl.Execute(b, data)
+	if err != nil {
+		return "", fmt.Errorf("executing %s template: %w", tmpl.Name(), err)
+	}
+
+	return b.String(), nil
+}
+
+// UpdateTemplates applies the new message templates by event type.  The
+// templates produce Telegram HTML, which is converted for the other channels.
+// The events without a template use the built-in layouts.
+func (m *Manager) UpdateTemplates(texts map[string]string) (err error) {
+	tmpls, err := parseTemplates(texts)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.templates = tmpls
+
+	return nil
+}
+
+// applyTemplate replaces the text of ev with the rendered template for its
+// type, if there is one.  The built-in text is kept if the template fails.
+func (m *Manager) applyTemplate(ev *event, data *templateData) {
+	m.mu.RLock()
+	tmpl := m.templates[ev.typ]
+	m.mu.RUnlock()
+
+	if tmpl == nil {
+		return
+	}
+
+	msg, err := executeTemplate(tmpl, data)
+	if err != nil {
+		m.logger.Error("rendering message template", slog.String("error", err.Error()))
+
+		return
+	}
+
+	ev.text = msg
+}
+
+// ValidateTemplate parses the message template for the event type typ and
+// renders it with sample data and the current system information.
+func ValidateTemplate(ctx context.Context, typ, text string) (preview string, err error) {
+	tmpl, err := parseTemplate(typ, text)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	data := &templateData{
+		Time:       now,
+		Info:       systeminfo.Collect(ctx),
+		Type:       typ,
+		Metric:     "cpu",
+		MetricName: metricDisplayName("cpu"),
+		Value:      95,
+		Threshold:  90,
+	}
+
+	switch eventType(typ) {
+	case eventTypeRecovery:
+		data.Value = 42
+		data.Duration = 5 * time.Minute
+	case eventTypeFilterUpdate:
+		data.Filter = &FilterUpdate{
+			ID:         1,
+			Name:       "AdGuard DNS filter",
+			URL:        "https://adguardteam.github.io/HostlistsRegistry/assets/filter_1.txt",
+			RulesCount: 50000,
+			Enabled:    true,
+			ListType:   FilterListTypeBlock,
+		}
+	case eventTypeCertExpiry:
+		data.CertExpiry = &CertExpiryReminder{
+			Domains:  []string{"example.org"},
+			NotAfter: now.AddDate(0, 0, 7),
+			DaysLeft: 7,
+		}
+	case eventTypeCertRenewal:
+		data.CertRenewal = &CertRenewalResult{
+			Domains:  []string{"example.org"},
+			NotAfter: now.AddDate(0, 3, 0),
+		}
+	}
+
+	if eventType(typ) != eventTypeAlert && eventType(typ) != eventTypeRecovery {
+		data.Metric, data.MetricName, data.Value, data.Threshold = "", "", 0, 0
+	}
+
+	return executeTemplate(tmpl, data)
+}
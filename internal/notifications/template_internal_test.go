@@ -0,0 +1,89 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/systeminfo"
+)
+
+func TestManager_applyTemplate(t *testing.T) {
+	m := NewManager(slog.New(slog.DiscardHandler), TelegramConfig{})
+
+	err := m.UpdateTemplates(map[string]string{
+		"alert": `{{ .MetricName }} is at {{ percent .Value }} on {{ .Info.Hostname }}`,
+	})
+	if err != nil {
+		t.Fatalf("updating templates: %s", err)
+	}
+
+	cfg := TelegramConfig{}
+	ev := &event{typ: eventTypeAlert, metric: "cpu", text: "default", value: 95, threshold: 90}
+	data := newTemplateData(cfg, ev, systeminfo.Info{Hostname: "test-host"})
+	m.applyTemplate(ev, data)
+
+	if !strings.HasPrefix(ev.text, "CPU Usage is at 95") || !strings.HasSuffix(ev.text, "on test-host") {
+		t.Errorf("unexpected text %q", ev.text)
+	}
+
+	ev = &event{typ: eventTypeRecovery, metric: "cpu", text: "default"}
+	m.applyTemplate(ev, newTemplateData(cfg, ev, systeminfo.Info{Hostname: "test-host"}))
+	if ev.text != "default" {
+		t.Errorf("unexpected text %q, want the built-in one", ev.text)
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		typ     string
+		text    string
+		wantErr string
+	}{{
+		name: "filter_update",
+		typ:  "filter_update",
+		text: `{{ .Filter.Name }}: {{ .Filter.RulesCount }}`,
+	}, {
+		name: "cert_expiry",
+		typ:  "cert_expiry",
+		text: `{{ index .CertExpiry.Domains 0 }} in {{ .CertExpiry.DaysLeft }} days`,
+	}, {
+		name:    "bad_type",
+		typ:     "unknown",
+		text:    `text`,
+		wantErr: `unsupported event type "unknown"`,
+	}, {
+		name:    "bad_syntax",
+		typ:     "alert",
+		text:    `{{ .Metric `,
+		wantErr: "parsing alert template",
+	}, {
+		name:    "bad_field",
+		typ:     "alert",
+		text:    `{{ .Unknown }}`,
+		wantErr: "executing alert template",
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			preview, err := ValidateTemplate(context.Background(), tc.typ, tc.text)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tc.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if preview == "" {
+				t.Error("empty preview")
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// eventTypeUpdateAvailable is the type of the new release events.
+const eventTypeUpdateAvailable eventType = "update_available"
+
+// UpdateInfo describes a newer release of AdGuard Home.
+type UpdateInfo struct {
+	// NewVersion is the version of the new release.
+	NewVersion string
+
+	// CurrentVersion is the version currently running.
+	CurrentVersion string
+
+	// Channel is the release channel, like "release" or "beta".
+	Channel string
+
+	// Announcement is the short description of the release, if any.
+	Announcement string
+
+	// AnnouncementURL is the link to the changelog of the release, if any.
+	AnnouncementURL string
+}
+
+// NotifyUpdateAvailable sends the notification about the new release.
+func (m *Manager) NotifyUpdateAvailable(ctx context.Context, info UpdateInfo) {
+	cfg := m.getTelegramConfig()
+	if !m.isReady(cfg) {
+		return
+	}
+
+	ev := &event{
+		time: time.Now(),
+		typ:  eventTypeUpdateAvailable,
+		text: composeUpdateAvailableMessage(cfg, info),
+	}
+
+	if err := m.deliver(ctx, cfg, ev); err != nil {
+		m.logger.Error(
+			"update notification failed",
+			"new_version", info.NewVersion,
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// composeUpdateAvailableMessage formats the new release notification.
+func composeUpdateAvailableMessage(cfg TelegramConfig, info UpdateInfo) (msg string) {
+	lines := make([]string, 0, 16)
+	if prefix := strings.TrimSpace(cfg.CustomMessage); prefix != "" {
+		lines = append(lines, prefix, "")
+	}
+
+	lines = append(lines,
+		"🆕 <b>AdGuard Home update available</b>",
+		divider(),
+		"",
+		fmt.Sprintf("  ▸ <b>New Version:</b> <code>%s</code>", html.EscapeString(fallbackString(info.NewVersion))),
+		fmt.Sprintf(
+			"  ▸ <b>Current Version:</b> <code>%s</code>",
+			html.EscapeString(fallbackString(info.CurrentVersion)),
+		),
+		fmt.Sprintf("  ▸ <b>Channel:</b> <code>%s</code>", html.EscapeString(fallbackString(info.Channel))),
+	)
+
+	if info.Announcement != "" {
+		lines = append(lines, "", html.EscapeString(info.Announcement))
+	}
+
+	if info.AnnouncementURL != "" {
+		lines = append(lines, "", fmt.Sprintf(
+			`📝 <a href="%s">Changelog</a>`,
+			html.EscapeString(info.AnnouncementURL),
+		))
+	}
+
+	lines = append(lines, "", divider(), timestampLine())
+
+	return strings.Join(lines, "\n")
+}
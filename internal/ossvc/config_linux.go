@@ -37,6 +37,10 @@ func configureOSOptions(conf *service.Config) {
 //  2. The StandardOutput and StandardError settings are set to redirect the
 //     output to the systemd journal, see
 //     https://man7.org/linux/man-pages/man5/systemd.exec.5.html#LOGGING_AND_STANDARD_INPUT/OUTPUT.
+//
+//  3. The Type setting is set to notify, since AdGuard Home reports its
+//     readiness, and the WatchdogSec setting makes systemd restart AdGuard
+//     Home if it stops pinging the watchdog, see sd_notify(3).
 const systemdScript = `[Unit]
 Description={{.Description}}
 ConditionFileIsExecutable={{.Path|cmdEscape}}
@@ -44,6 +48,9 @@ ConditionFileIsExecutable={{.Path|cmdEscape}}
 {{$dep}} {{end}}
 
 [Service]
+Type=notify
+NotifyAccess=main
+WatchdogSec=60
 StartLimitInterval=5
 StartLimitBurst=10
 ExecStart={{.Path|cmdEscape}}{{range .Arguments}} {{.|cmd}}{{end}}
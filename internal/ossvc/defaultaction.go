@@ -10,6 +10,15 @@ type ActionInstall struct {
 	WorkingDirectory string
 	Version          string
 	Arguments        []string
+
+	// AccessPaths are the paths to the directories, to which the account of
+	// the service is granted the modify access, if it's not the default one.
+	AccessPaths []string
+
+	// UseVirtualAccount, if true, makes the service run under its own virtual
+	// account with limited privileges instead of the default one.  It's only
+	// supported on Windows, where the default account is LocalSystem.
+	UseVirtualAccount bool
 }
 
 // Name implements the [Action] interface for *ActionInstall.
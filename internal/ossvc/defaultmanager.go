@@ -141,6 +141,13 @@ func (m *manager) install(ctx context.Context, action *ActionInstall) (err error
 	}
 	ConfigureServiceOptions(conf, action.Version)
 
+	if action.UseVirtualAccount {
+		conf.UserName, err = virtualAccount(action.ServiceName)
+		if err != nil {
+			return fmt.Errorf("using virtual account: %w", err)
+		}
+	}
+
 	s, err := service.New(emptyInterface{}, conf)
 	if err != nil {
 		return fmt.Errorf("creating service: %w", err)
@@ -151,6 +158,20 @@ func (m *manager) install(ctx context.Context, action *ActionInstall) (err error
 		return fmt.Errorf("installing service: %w", err)
 	}
 
+	if conf.UserName != "" {
+		err = m.grantAccess(ctx, conf.UserName, action.AccessPaths)
+		if err != nil {
+			return fmt.Errorf("granting access to %q: %w", conf.UserName, err)
+		}
+	}
+
+	err = m.configureRecovery(ctx, action.ServiceName)
+	if err != nil {
+		// Don't fail the installation, since the service is usable without the
+		// automatic restarts.
+		m.logger.WarnContext(ctx, "configuring recovery", slogutil.KeyError, err)
+	}
+
 	if m.isOpenWrt {
 		// On OpenWrt it is important to run enable after the service
 		// installation.  Otherwise, the service won't start on the system
@@ -59,3 +59,23 @@ func (m *manager) reload(ctx context.Context, name ServiceName) (err error) {
 
 	return nil
 }
+
+// configureRecovery is a UNIX platform implementation for configuring the
+// restarts of the installed service on failures.  The restarts are configured
+// by the service scripts, see [configureOSOptions].
+func (*manager) configureRecovery(context.Context, ServiceName) (err error) {
+	return nil
+}
+
+// virtualAccount returns an error, since the virtual accounts are only
+// available on Windows.
+func virtualAccount(_ ServiceName) (user string, err error) {
+	return "", errors.ErrUnsupported
+}
+
+// grantAccess is a UNIX platform implementation for granting the account of
+// the service the access to the paths.  The accounts of the services aren't
+// configured on UNIX, so it does nothing.
+func (*manager) grantAccess(context.Context, string, []string) (err error) {
+	return nil
+}
@@ -4,8 +4,13 @@ package ossvc
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/AdguardTeam/AdGuardHome/internal/aghos"
 	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/osutil/executil"
+	"golang.org/x/sys/windows/svc/mgr"
 )
 
 // reload is a Windows platform implementation of the Reload method of the
@@ -13,3 +18,84 @@ import (
 func (*manager) reload(context.Context, ServiceName) error {
 	return errors.ErrUnsupported
 }
+
+// recoveryResetPeriod is the period of time without failures after which the
+// Service Control Manager resets the failure count, so that the next restart
+// uses the shortest delay again.
+const recoveryResetPeriod = 24 * time.Hour
+
+// recoveryActions are the actions the Service Control Manager performs on the
+// first, second, and subsequent failures of the service.  The delays grow to
+// avoid restarting the service in a tight loop.
+var recoveryActions = []mgr.RecoveryAction{{
+	Type:  mgr.ServiceRestart,
+	Delay: 10 * time.Second,
+}, {
+	Type:  mgr.ServiceRestart,
+	Delay: 30 * time.Second,
+}, {
+	Type:  mgr.ServiceRestart,
+	Delay: 2 * time.Minute,
+}}
+
+// configureRecovery sets up the automatic restarts of the installed service on
+// failures, including exits with a non-zero code.
+func (m *manager) configureRecovery(ctx context.Context, name ServiceName) (err error) {
+	scm, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("connecting to service manager: %w", err)
+	}
+	defer func() { err = errors.WithDeferred(err, scm.Disconnect()) }()
+
+	s, err := scm.OpenService(string(name))
+	if err != nil {
+		return fmt.Errorf("opening service: %w", err)
+	}
+	defer func() { err = errors.WithDeferred(err, s.Close()) }()
+
+	err = s.SetRecoveryActions(recoveryActions, uint32(recoveryResetPeriod/time.Second))
+	if err != nil {
+		return fmt.Errorf("setting recovery actions: %w", err)
+	}
+
+	err = s.SetRecoveryActionsOnNonCrashFailures(true)
+	if err != nil {
+		return fmt.Errorf("enabling recovery on non-crash failures: %w", err)
+	}
+
+	m.logger.DebugContext(ctx, "configured service recovery", "actions", len(recoveryActions))
+
+	return nil
+}
+
+// virtualAccount returns the name of the virtual account of the service, which
+// is created by the Service Control Manager along with the service.
+func virtualAccount(name ServiceName) (user string, err error) {
+	return `NT SERVICE\` + string(name), nil
+}
+
+// grantAccess grants user the modify access to the directories at paths and
+// everything within them, since the virtual accounts have no access to the
+// files created by the administrators.
+func (m *manager) grantAccess(ctx context.Context, user string, paths []string) (err error) {
+	for _, p := range paths {
+		err = executil.RunWithPeek(
+			ctx,
+			m.cmdCons,
+			aghos.MaxCmdOutputSize,
+			"icacls",
+			p,
+			"/grant",
+			user+":(OI)(CI)M",
+			"/T",
+			"/Q",
+		)
+		if err != nil {
+			return fmt.Errorf("path %q: %w", p, err)
+		}
+
+		m.logger.DebugContext(ctx, "granted access", "user", user, "path", p)
+	}
+
+	return nil
+}
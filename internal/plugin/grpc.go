@@ -0,0 +1,225 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
+)
+
+// ServiceName is the full name of the gRPC service, which plugins serve.
+const ServiceName = "adguardhome.plugin.v1.Plugin"
+
+// Names of the methods of the gRPC service.
+const (
+	methodConfigure         = "Configure"
+	methodDescribe          = "Describe"
+	methodHandleDNSRequest  = "HandleDNSRequest"
+	methodHandleDNSResponse = "HandleDNSResponse"
+	methodHandleHTTP        = "HandleHTTP"
+)
+
+// Codec is the gRPC codec, which encodes the messages of the plugin service as
+// JSON.  Its content subtype is "json", so the content type of the requests is
+// "application/grpc+json".
+type Codec struct{}
+
+// type check
+var _ encoding.Codec = Codec{}
+
+// Marshal implements the [encoding.Codec] interface for Codec.
+func (Codec) Marshal(v any) (data []byte, err error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal implements the [encoding.Codec] interface for Codec.
+func (Codec) Unmarshal(data []byte, v any) (err error) {
+	return json.Unmarshal(data, v)
+}
+
+// Name implements the [encoding.Codec] interface for Codec.
+func (Codec) Name() (name string) {
+	return "json"
+}
+
+// empty is the message of the methods, which have no request or response.
+type empty struct{}
+
+// serviceDesc is the description of the gRPC service of the plugins.
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*Plugin)(nil),
+	Methods: []grpc.MethodDesc{
+		newMethodDesc(methodDescribe, func(p Plugin, ctx context.Context, _ *empty) (
+			resp *Description,
+			err error,
+		) {
+			return p.Describe(ctx)
+		}),
+		newMethodDesc(methodConfigure, func(p Plugin, ctx context.Context, req *ConfigureRequest) (
+			resp *empty,
+			err error,
+		) {
+			return &empty{}, p.Configure(ctx, req)
+		}),
+		newMethodDesc(methodHandleDNSRequest, Plugin.HandleDNSRequest),
+		newMethodDesc(methodHandleDNSResponse, Plugin.HandleDNSResponse),
+		newMethodDesc(methodHandleHTTP, Plugin.HandleHTTP),
+	},
+	Streams: []grpc.StreamDesc{},
+}
+
+// newMethodDesc returns the description of the unary gRPC method, which calls
+// f.  The signature of f is the one of the method expressions of [Plugin].
+func newMethodDesc[Req, Resp any](
+	name string,
+	f func(p Plugin, ctx context.Context, req *Req) (resp *Resp, err error),
+) (desc grpc.MethodDesc) {
+	info := &grpc.UnaryServerInfo{
+		FullMethod: fullMethod(name),
+	}
+
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(
+			srv any,
+			ctx context.Context,
+			dec func(v any) (err error),
+			interceptor grpc.UnaryServerInterceptor,
+		) (resp any, err error) {
+			req := new(Req)
+			err = dec(req)
+			if err != nil {
+				return nil, err
+			}
+
+			p := srv.(Plugin)
+			if interceptor == nil {
+				return f(p, ctx, req)
+			}
+
+			return interceptor(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+				return f(p, ctx, req.(*Req))
+			})
+		},
+	}
+}
+
+// fullMethod returns the full name of the method of the plugin service.
+func fullMethod(name string) (full string) {
+	return "/" + ServiceName + "/" + name
+}
+
+// Serve serves p on the Unix socket at sockPath until ctx is canceled.  It's
+// intended to be called from the main function of the plugin with the path
+// from its only command-line argument.
+func Serve(ctx context.Context, sockPath string, p Plugin) (err error) {
+	l, err := net.Listen("unix", sockPath)
+	if err != nil {
+		return fmt.Errorf("listening: %w", err)
+	}
+
+	return ServeListener(ctx, l, p)
+}
+
+// ServeListener is like [Serve] but uses l.  l is closed on return.
+func ServeListener(ctx context.Context, l net.Listener, p Plugin) (err error) {
+	srv := grpc.NewServer(grpc.ForceServerCodec(Codec{}))
+	srv.RegisterService(&serviceDesc, p)
+
+	stop := context.AfterFunc(ctx, srv.GracefulStop)
+	defer stop()
+
+	err = srv.Serve(l)
+	if err != nil {
+		return fmt.Errorf("serving: %w", err)
+	}
+
+	return nil
+}
+
+// client is a [Plugin], which calls an external plugin over gRPC.
+type client struct {
+	conn *grpc.ClientConn
+}
+
+// type check
+var _ Plugin = (*client)(nil)
+
+// newClient returns a client of the plugin serving on the Unix socket at
+// sockPath.  The connection is established lazily.
+func newClient(sockPath string) (c *client, err error) {
+	conn, err := grpc.NewClient(
+		"unix://"+sockPath,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(Codec{})),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating grpc client: %w", err)
+	}
+
+	return &client{
+		conn: conn,
+	}, nil
+}
+
+// Describe implements the [Plugin] interface for *client.
+func (c *client) Describe(ctx context.Context) (d *Description, err error) {
+	d = &Description{}
+	err = c.conn.Invoke(ctx, fullMethod(methodDescribe), &empty{}, d)
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// Configure implements the [Plugin] interface for *client.
+func (c *client) Configure(ctx context.Context, req *ConfigureRequest) (err error) {
+	return c.conn.Invoke(ctx, fullMethod(methodConfigure), req, &empty{})
+}
+
+// HandleDNSRequest implements the [Plugin] interface for *client.
+func (c *client) HandleDNSRequest(ctx context.Context, q *DNSQuery) (res *DNSResult, err error) {
+	res = &DNSResult{}
+	err = c.conn.Invoke(ctx, fullMethod(methodHandleDNSRequest), q, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// HandleDNSResponse implements the [Plugin] interface for *client.
+func (c *client) HandleDNSResponse(ctx context.Context, q *DNSQuery) (res *DNSResult, err error) {
+	res = &DNSResult{}
+	err = c.conn.Invoke(ctx, fullMethod(methodHandleDNSResponse), q, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// HandleHTTP implements the [Plugin] interface for *client.
+func (c *client) HandleHTTP(
+	ctx context.Context,
+	req *HTTPRequest,
+) (resp *HTTPResponse, err error) {
+	resp = &HTTPResponse{}
+	err = c.conn.Invoke(ctx, fullMethod(methodHandleHTTP), req, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// close closes the connection to the plugin.
+func (c *client) close() (err error) {
+	return c.conn.Close()
+}
@@ -0,0 +1,123 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/golibs/httphdr"
+	"github.com/AdguardTeam/golibs/ioutil"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+)
+
+const (
+	// basePath is the path, under which the routes of the plugins are
+	// registered.
+	basePath = "/control/plugins"
+
+	// httpTimeout is the timeout of the handling of an HTTP request by a
+	// plugin.
+	httpTimeout = 30 * time.Second
+
+	// maxBodySize is the maximum size of the body of an HTTP request
+	// forwarded to a plugin.
+	maxBodySize = 1 << 20
+)
+
+// listResp is the response to the GET /control/plugins HTTP API.
+type listResp struct {
+	Plugins []*Description `json:"plugins"`
+}
+
+// RegisterHandlers registers the list of the plugins and the routes of the
+// running plugins with reg.  reg must not be nil.  It must be called after
+// [Manager.Start].
+func (m *Manager) RegisterHandlers(reg aghhttp.Registrar) {
+	aghhttp.RegisterWithInfo(reg, http.MethodGet, basePath, m.handleList, &aghhttp.RouteInfo{
+		Summary:     "List the running plugins",
+		Description: "Returns the descriptions of the running plugins sorted by names.",
+		Response:    &listResp{},
+	})
+
+	for _, p := range m.plugins {
+		prefix := p.pathPrefix()
+		for _, r := range p.desc.Routes {
+			path := prefix
+			if r.Path != "/" {
+				path += r.Path
+			}
+
+			aghhttp.RegisterWithInfo(reg, r.Method, path, p.handleHTTP, &aghhttp.RouteInfo{
+				Summary: fmt.Sprintf("Route of the plugin %q", p.desc.Name),
+			})
+		}
+	}
+}
+
+// handleList is the handler for the GET /control/plugins HTTP API.
+func (m *Manager) handleList(w http.ResponseWriter, r *http.Request) {
+	aghhttp.WriteJSONResponseOK(r.Context(), m.logger, w, r, &listResp{
+		Plugins: m.Descriptions(),
+	})
+}
+
+// pathPrefix returns the path, under which the routes of p are registered.
+func (p *process) pathPrefix() (prefix string) {
+	return basePath + "/" + p.desc.Name
+}
+
+// handleHTTP forwards the HTTP request to the plugin.
+func (p *process) handleHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	body, err := io.ReadAll(ioutil.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, p.logger, r, w, http.StatusBadRequest, "reading body: %s", err)
+
+		return
+	}
+
+	// Don't pass the credentials of the user to the plugin.
+	hdr := r.Header.Clone()
+	hdr.Del(httphdr.Authorization)
+	hdr.Del(httphdr.Cookie)
+
+	path := strings.TrimPrefix(r.URL.Path, p.pathPrefix())
+	if path == "" {
+		path = "/"
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, httpTimeout)
+	defer cancel()
+
+	resp, err := p.client.HandleHTTP(ctx, &HTTPRequest{
+		Header: hdr,
+		Method: r.Method,
+		Path:   path,
+		Query:  r.URL.RawQuery,
+		Body:   body,
+	})
+	if err != nil {
+		aghhttp.ErrorAndLog(ctx, p.logger, r, w, http.StatusBadGateway, "plugin: %s", err)
+
+		return
+	}
+
+	respHdr := w.Header()
+	for k, v := range resp.Header {
+		respHdr[http.CanonicalHeaderKey(k)] = v
+	}
+
+	if resp.Status != 0 {
+		w.WriteHeader(resp.Status)
+	}
+
+	_, err = w.Write(resp.Body)
+	if err != nil {
+		p.logger.DebugContext(ctx, "writing response", slogutil.KeyError, err)
+	}
+}
@@ -0,0 +1,478 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/dnsforward"
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+	"github.com/AdguardTeam/golibs/osutil/executil"
+	"github.com/miekg/dns"
+)
+
+const (
+	// startTimeout is the time, in which a started plugin must begin serving
+	// and respond to [Plugin.Describe] and [Plugin.Configure].
+	startTimeout = 10 * time.Second
+
+	// socketPollIvl is the interval between the checks of the socket of a
+	// started plugin.
+	socketPollIvl = 50 * time.Millisecond
+
+	// socketName is the name of the Unix socket of a plugin inside its
+	// temporary directory.
+	socketName = "plugin.sock"
+)
+
+// Config is the configuration of a [Manager].
+type Config struct {
+	// Logger is used for logging the operation of the plugins.  It must not be
+	// nil.
+	Logger *slog.Logger
+
+	// CommandConstructor is used to start the plugins.  It must not be nil.
+	CommandConstructor executil.CommandConstructor
+
+	// Settings are the JSON-encoded configurations of the plugins by their
+	// names.
+	Settings map[string]json.RawMessage
+
+	// Dir is the directory with the executables of the plugins.
+	Dir string
+
+	// DataDir is the directory, inside which every plugin gets its own data
+	// directory.
+	DataDir string
+
+	// HookTimeout is the timeout of a single call of a DNS hook of a plugin.
+	// It must be positive.
+	HookTimeout time.Duration
+}
+
+// Manager discovers, starts, and stops the plugins.
+type Manager struct {
+	logger      *slog.Logger
+	cmdCons     executil.CommandConstructor
+	settings    map[string]json.RawMessage
+	dir         string
+	dataDir     string
+	hookTimeout time.Duration
+
+	// plugins are the running plugins sorted by names.  It's only modified in
+	// [Manager.Start].
+	plugins []*process
+}
+
+// New returns a new manager of the plugins.  c must not be nil and must be
+// valid.  The plugins aren't started until [Manager.Start] is called.
+func New(c *Config) (m *Manager) {
+	return &Manager{
+		logger:      c.Logger,
+		cmdCons:     c.CommandConstructor,
+		settings:    c.Settings,
+		dir:         c.Dir,
+		dataDir:     c.DataDir,
+		hookTimeout: c.HookTimeout,
+	}
+}
+
+// process is a running plugin.
+type process struct {
+	// logger is used for logging the operation of the plugin.
+	logger *slog.Logger
+
+	// client is used to call the plugin.
+	client *client
+
+	// cmd is the process of the plugin.
+	cmd executil.Command
+
+	// desc is the description the plugin has returned.
+	desc *Description
+
+	// exited is closed when the process of the plugin exits.
+	exited chan struct{}
+
+	// sockDir is the temporary directory with the Unix socket of the plugin.
+	sockDir string
+}
+
+// Start starts every executable file in the plugins directory.  The plugins,
+// which fail to start, are logged and skipped.  It must only be called once,
+// before any other method.
+func (m *Manager) Start(ctx context.Context) (err error) {
+	entries, err := os.ReadDir(m.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		m.logger.InfoContext(ctx, "no plugins directory", "dir", m.dir)
+
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("reading plugins directory: %w", err)
+	}
+
+	for _, e := range entries {
+		if !isExecutable(e) {
+			continue
+		}
+
+		path := filepath.Join(m.dir, e.Name())
+		p, startErr := m.startPlugin(ctx, path)
+		if startErr != nil {
+			m.logger.ErrorContext(ctx, "starting plugin", "path", path, slogutil.KeyError, startErr)
+
+			continue
+		}
+
+		if m.plugin(p.desc.Name) != nil {
+			m.logger.ErrorContext(ctx, "duplicate plugin; stopping", "name", p.desc.Name)
+			p.stop(ctx)
+
+			continue
+		}
+
+		p.logger.InfoContext(ctx, "started", "version", p.desc.Version, "path", path)
+
+		m.plugins = append(m.plugins, p)
+	}
+
+	slices.SortFunc(m.plugins, func(a, b *process) (res int) {
+		return strings.Compare(a.desc.Name, b.desc.Name)
+	})
+
+	return nil
+}
+
+// isExecutable returns true if e is an executable file.
+func isExecutable(e fs.DirEntry) (ok bool) {
+	if !e.Type().IsRegular() || strings.HasPrefix(e.Name(), ".") {
+		return false
+	}
+
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(filepath.Ext(e.Name()), ".exe")
+	}
+
+	fi, err := e.Info()
+
+	return err == nil && fi.Mode().Perm()&0o111 != 0
+}
+
+// startPlugin starts the plugin at path, describes, and configures it.
+func (m *Manager) startPlugin(ctx context.Context, path string) (p *process, err error) {
+	sockDir, err := os.MkdirTemp("", "adguardhome-plugin-")
+	if err != nil {
+		return nil, fmt.Errorf("creating socket dir: %w", err)
+	}
+
+	sockPath := filepath.Join(sockDir, socketName)
+
+	// Don't use ctx, since the process must exist until [Manager.Shutdown].
+	cmd, err := m.cmdCons.New(context.Background(), &executil.CommandConfig{
+		Path:   path,
+		Args:   []string{sockPath},
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	})
+	if err == nil {
+		err = cmd.Start(ctx)
+	}
+	if err != nil {
+		return nil, errors.WithDeferred(
+			fmt.Errorf("starting: %w", err),
+			os.RemoveAll(sockDir),
+		)
+	}
+
+	p = &process{
+		logger:  m.logger.With("path", path),
+		cmd:     cmd,
+		exited:  make(chan struct{}),
+		sockDir: sockDir,
+	}
+
+	go p.wait(ctx)
+
+	err = m.initPlugin(ctx, p, sockPath)
+	if err != nil {
+		p.stop(ctx)
+
+		// Don't wrap the error since it's informative enough as is.
+		return nil, err
+	}
+
+	p.logger = m.logger.With("plugin", p.desc.Name)
+
+	return p, nil
+}
+
+// initPlugin connects to the started plugin p, describes, and configures it.
+func (m *Manager) initPlugin(ctx context.Context, p *process, sockPath string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, startTimeout)
+	defer cancel()
+
+	err = waitSocket(ctx, sockPath, p.exited)
+	if err != nil {
+		return fmt.Errorf("waiting for socket: %w", err)
+	}
+
+	p.client, err = newClient(sockPath)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return err
+	}
+
+	p.desc, err = p.client.Describe(ctx)
+	if err != nil {
+		return fmt.Errorf("describing: %w", err)
+	}
+
+	err = p.desc.validate()
+	if err != nil {
+		return fmt.Errorf("description: %w", err)
+	}
+
+	err = p.client.Configure(ctx, &ConfigureRequest{
+		Config:  m.settings[p.desc.Name],
+		DataDir: filepath.Join(m.dataDir, p.desc.Name),
+	})
+	if err != nil {
+		return fmt.Errorf("configuring %q: %w", p.desc.Name, err)
+	}
+
+	return nil
+}
+
+// waitSocket waits until the socket at sockPath appears.  It returns an error
+// if the process exits, which is signaled by closing exited, or ctx is
+// canceled.
+func waitSocket(ctx context.Context, sockPath string, exited <-chan struct{}) (err error) {
+	ticker := time.NewTicker(socketPollIvl)
+	defer ticker.Stop()
+
+	for {
+		_, err = os.Stat(sockPath)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-exited:
+			return errors.Error("plugin exited")
+		case <-ticker.C:
+			// Go on.
+		}
+	}
+}
+
+// wait waits for the process of p to exit.
+func (p *process) wait(ctx context.Context) {
+	defer close(p.exited)
+
+	err := p.cmd.Wait(ctx)
+	if err != nil {
+		p.logger.DebugContext(ctx, "exited", slogutil.KeyError, err)
+	} else {
+		p.logger.DebugContext(ctx, "exited")
+	}
+}
+
+// stop closes the connection to p, stops its process, and removes its socket.
+func (p *process) stop(ctx context.Context) {
+	if p.client != nil {
+		err := p.client.close()
+		if err != nil {
+			p.logger.DebugContext(ctx, "closing connection", slogutil.KeyError, err)
+		}
+	}
+
+	select {
+	case <-p.exited:
+		// Already exited.
+	default:
+		err := p.cmd.Cancel(ctx)
+		if err != nil {
+			p.logger.DebugContext(ctx, "stopping", slogutil.KeyError, err)
+		}
+
+		<-p.exited
+	}
+
+	err := os.RemoveAll(p.sockDir)
+	if err != nil {
+		p.logger.DebugContext(ctx, "removing socket dir", slogutil.KeyError, err)
+	}
+}
+
+// Shutdown stops all the plugins.  m may be nil.
+func (m *Manager) Shutdown(ctx context.Context) {
+	if m == nil {
+		return
+	}
+
+	for _, p := range m.plugins {
+		p.stop(ctx)
+	}
+}
+
+// Descriptions returns the descriptions of the running plugins sorted by
+// names.  m may be nil.
+func (m *Manager) Descriptions() (descs []*Description) {
+	if m == nil {
+		return []*Description{}
+	}
+
+	descs = make([]*Description, 0, len(m.plugins))
+	for _, p := range m.plugins {
+		descs = append(descs, p.desc)
+	}
+
+	return descs
+}
+
+// plugin returns the running plugin with the name or nil if there is none.
+func (m *Manager) plugin(name string) (p *process) {
+	i := slices.IndexFunc(m.plugins, func(p *process) (ok bool) {
+		return p.desc.Name == name
+	})
+	if i < 0 {
+		return nil
+	}
+
+	return m.plugins[i]
+}
+
+// type check
+var _ dnsforward.Hook = (*Manager)(nil)
+
+// Hook returns m as a DNS hook if any of the plugins handles DNS requests or
+// responses, and nil otherwise.  m may be nil.
+func (m *Manager) Hook() (h dnsforward.Hook) {
+	if m == nil {
+		return nil
+	}
+
+	for _, p := range m.plugins {
+		if p.desc.DNSRequestHook || p.desc.DNSResponseHook {
+			return m
+		}
+	}
+
+	return nil
+}
+
+// HandleRequest implements the [dnsforward.Hook] interface for *Manager.  The
+// plugins are called in the order of their names, and the first response is
+// used.
+func (m *Manager) HandleRequest(
+	ctx context.Context,
+	q *dnsforward.HookQuery,
+) (resp *dns.Msg, err error) {
+	req, err := q.Request.Pack()
+	if err != nil {
+		return nil, fmt.Errorf("packing request: %w", err)
+	}
+
+	var errs []error
+	for _, p := range m.plugins {
+		if !p.desc.DNSRequestHook {
+			continue
+		}
+
+		resp, err = m.callHook(ctx, p, p.client.HandleDNSRequest, &DNSQuery{
+			ClientIP: q.ClientIP,
+			ClientID: q.ClientID,
+			Request:  req,
+		}, q.Request)
+		if err != nil {
+			errs = append(errs, err)
+		} else if resp != nil {
+			break
+		}
+	}
+
+	return resp, errors.Join(errs...)
+}
+
+// HandleResponse implements the [dnsforward.Hook] interface for *Manager.  The
+// plugins are called in the order of their names, and each of them gets the
+// response returned by the previous one.
+func (m *Manager) HandleResponse(
+	ctx context.Context,
+	q *dnsforward.HookQuery,
+) (resp *dns.Msg, err error) {
+	req, err := q.Request.Pack()
+	if err != nil {
+		return nil, fmt.Errorf("packing request: %w", err)
+	}
+
+	var errs []error
+	cur := q.Response
+	for _, p := range m.plugins {
+		if !p.desc.DNSResponseHook {
+			continue
+		}
+
+		var respData []byte
+		respData, err = cur.Pack()
+		if err != nil {
+			return resp, errors.Join(append(errs, fmt.Errorf("packing response: %w", err))...)
+		}
+
+		var modified *dns.Msg
+		modified, err = m.callHook(ctx, p, p.client.HandleDNSResponse, &DNSQuery{
+			ClientIP: q.ClientIP,
+			ClientID: q.ClientID,
+			Request:  req,
+			Response: respData,
+		}, q.Request)
+		if err != nil {
+			errs = append(errs, err)
+		} else if modified != nil {
+			resp, cur = modified, modified
+		}
+	}
+
+	return resp, errors.Join(errs...)
+}
+
+// callHook calls the hook of p with the timeout and decodes the message it
+// returns, if any.  The ID of the message is set to the one of req.
+func (m *Manager) callHook(
+	ctx context.Context,
+	p *process,
+	hook func(ctx context.Context, q *DNSQuery) (res *DNSResult, err error),
+	q *DNSQuery,
+	req *dns.Msg,
+) (msg *dns.Msg, err error) {
+	ctx, cancel := context.WithTimeout(ctx, m.hookTimeout)
+	defer cancel()
+
+	res, err := hook(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %q: %w", p.desc.Name, err)
+	} else if res == nil || len(res.Msg) == 0 {
+		return nil, nil
+	}
+
+	msg = &dns.Msg{}
+	err = msg.Unpack(res.Msg)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %q: unpacking message: %w", p.desc.Name, err)
+	}
+
+	msg.Id = req.Id
+
+	return msg, nil
+}
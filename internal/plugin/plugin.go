@@ -0,0 +1,251 @@
+// Package plugin contains the plugin subsystem of AdGuard Home.  Plugins are
+// external executables, which AdGuard Home starts on launch and communicates
+// with over gRPC.
+//
+// AdGuard Home starts each executable file from the plugins directory with a
+// single argument, which is the path of a Unix socket.  The plugin must listen
+// on that socket and serve the gRPC service [ServiceName] with the methods of
+// [Plugin].  The messages are encoded as JSON, see [Codec], so that plugins
+// could be written in any language without generating the protobuf code.  See
+// [Serve] for a Go implementation.
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"path"
+	"regexp"
+
+	"github.com/AdguardTeam/golibs/container"
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// Plugin is the interface of a plugin.  All methods must be safe for
+// concurrent use.
+type Plugin interface {
+	// Describe returns the description of the plugin.  It's called once, right
+	// after the plugin has been started.
+	Describe(ctx context.Context) (d *Description, err error)
+
+	// Configure applies the configuration of the plugin.  It's called once,
+	// after [Plugin.Describe] and before any other method.
+	Configure(ctx context.Context, req *ConfigureRequest) (err error)
+
+	// HandleDNSRequest is called for every DNS request before it's filtered,
+	// if [Description.DNSRequestHook] is true.  If res.Msg isn't empty, it's
+	// used as the response, so that neither filtering nor upstreams are used.
+	HandleDNSRequest(ctx context.Context, q *DNSQuery) (res *DNSResult, err error)
+
+	// HandleDNSResponse is called for every DNS response after it's filtered,
+	// if [Description.DNSResponseHook] is true.  If res.Msg isn't empty, it
+	// replaces the response.
+	HandleDNSResponse(ctx context.Context, q *DNSQuery) (res *DNSResult, err error)
+
+	// HandleHTTP handles an HTTP request to one of [Description.Routes].
+	HandleHTTP(ctx context.Context, req *HTTPRequest) (resp *HTTPResponse, err error)
+}
+
+// UnimplementedPlugin is a [Plugin] that does nothing.  It's intended to be
+// embedded into the implementations, which only need some of the methods.
+type UnimplementedPlugin struct{}
+
+// type check
+var _ Plugin = UnimplementedPlugin{}
+
+// Describe implements the [Plugin] interface for UnimplementedPlugin.  It
+// always returns an error.
+func (UnimplementedPlugin) Describe(_ context.Context) (d *Description, err error) {
+	return nil, errors.ErrUnsupported
+}
+
+// Configure implements the [Plugin] interface for UnimplementedPlugin.
+func (UnimplementedPlugin) Configure(_ context.Context, _ *ConfigureRequest) (err error) {
+	return nil
+}
+
+// HandleDNSRequest implements the [Plugin] interface for UnimplementedPlugin.
+// It always returns an empty result.
+func (UnimplementedPlugin) HandleDNSRequest(
+	_ context.Context,
+	_ *DNSQuery,
+) (res *DNSResult, err error) {
+	return &DNSResult{}, nil
+}
+
+// HandleDNSResponse implements the [Plugin] interface for UnimplementedPlugin.
+// It always returns an empty result.
+func (UnimplementedPlugin) HandleDNSResponse(
+	_ context.Context,
+	_ *DNSQuery,
+) (res *DNSResult, err error) {
+	return &DNSResult{}, nil
+}
+
+// HandleHTTP implements the [Plugin] interface for UnimplementedPlugin.  It
+// always responds with the status 404 Not Found.
+func (UnimplementedPlugin) HandleHTTP(
+	_ context.Context,
+	_ *HTTPRequest,
+) (resp *HTTPResponse, err error) {
+	return &HTTPResponse{Status: http.StatusNotFound}, nil
+}
+
+// Description is the description of a plugin.
+type Description struct {
+	// Name is the unique name of the plugin.  It must consist of lowercase
+	// Latin letters, digits, hyphens, and underscores.  It's used as the key of
+	// the configuration of the plugin and in the paths of its HTTP routes.
+	Name string `json:"name"`
+
+	// Version is the version of the plugin.  It's only used for information.
+	Version string `json:"version"`
+
+	// Routes are the HTTP routes, which AdGuard Home must forward to the
+	// plugin.
+	Routes []*Route `json:"routes"`
+
+	// DNSRequestHook is true if [Plugin.HandleDNSRequest] must be called.
+	DNSRequestHook bool `json:"dns_request_hook"`
+
+	// DNSResponseHook is true if [Plugin.HandleDNSResponse] must be called.
+	DNSResponseHook bool `json:"dns_response_hook"`
+}
+
+// nameRe is the regular expression for the valid plugin names.
+var nameRe = regexp.MustCompile(`^[a-z0-9_-]{1,64}$`)
+
+// routePathRe is the regular expression for the valid paths of the routes.
+// The patterns of [http.ServeMux] aren't allowed.
+var routePathRe = regexp.MustCompile(`^/[A-Za-z0-9._/-]*$`)
+
+// validate returns an error if d isn't valid.
+func (d *Description) validate() (err error) {
+	if d == nil {
+		return errors.ErrNoValue
+	}
+
+	if !nameRe.MatchString(d.Name) {
+		return fmt.Errorf("name: %w: %q", errors.ErrBadEnumValue, d.Name)
+	}
+
+	var errs []error
+	routes := container.NewMapSet[Route]()
+	for i, r := range d.Routes {
+		err = r.validate()
+		if err == nil && routes.Has(*r) {
+			err = errors.ErrDuplicated
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("routes: at index %d: %w", i, err))
+
+			continue
+		}
+
+		routes.Add(*r)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Route is an HTTP route of a plugin.
+type Route struct {
+	// Method is the HTTP method of the route, like "GET".
+	Method string `json:"method"`
+
+	// Path is the path of the route relative to the base path of the plugin,
+	// which is "/control/plugins/<name>".  It must start with a slash and
+	// only contain Latin letters, digits, and the characters ".", "_", "/",
+	// and "-".  It must be clean, see [path.Clean].
+	Path string `json:"path"`
+}
+
+// validate returns an error if r isn't valid.
+func (r *Route) validate() (err error) {
+	if r == nil {
+		return errors.ErrNoValue
+	}
+
+	switch r.Method {
+	case http.MethodDelete, http.MethodGet, http.MethodPost, http.MethodPut:
+		// Go on.
+	default:
+		return fmt.Errorf("method: %w: %q", errors.ErrBadEnumValue, r.Method)
+	}
+
+	if !routePathRe.MatchString(r.Path) || path.Clean(r.Path) != r.Path {
+		return fmt.Errorf("path: %w: %q", errors.ErrBadEnumValue, r.Path)
+	}
+
+	return nil
+}
+
+// ConfigureRequest is the request of [Plugin.Configure].
+type ConfigureRequest struct {
+	// Config is the JSON-encoded configuration of the plugin from the
+	// configuration file.  It's null if there is none.
+	Config json.RawMessage `json:"config"`
+
+	// DataDir is the directory, which the plugin may use to store its data.
+	// It may not exist yet.
+	DataDir string `json:"data_dir"`
+}
+
+// DNSQuery is the request of [Plugin.HandleDNSRequest] and
+// [Plugin.HandleDNSResponse].
+type DNSQuery struct {
+	// ClientIP is the IP address of the client.
+	ClientIP netip.Addr `json:"client_ip"`
+
+	// ClientID is the ClientID from DoH, DoQ, or DoT, if any.
+	ClientID string `json:"client_id,omitempty"`
+
+	// Request is the DNS request in the wire format.
+	Request []byte `json:"request"`
+
+	// Response is the DNS response in the wire format.  It's only set for
+	// [Plugin.HandleDNSResponse].
+	Response []byte `json:"response,omitempty"`
+}
+
+// DNSResult is the response of [Plugin.HandleDNSRequest] and
+// [Plugin.HandleDNSResponse].
+type DNSResult struct {
+	// Msg, if not empty, is the DNS response in the wire format, which
+	// AdGuard Home must use.
+	Msg []byte `json:"msg,omitempty"`
+}
+
+// HTTPRequest is the request of [Plugin.HandleHTTP].
+type HTTPRequest struct {
+	// Header are the headers of the request.
+	Header http.Header `json:"header"`
+
+	// Method is the HTTP method of the request.
+	Method string `json:"method"`
+
+	// Path is the path of the request relative to the base path of the plugin.
+	Path string `json:"path"`
+
+	// Query is the encoded query of the request without the question mark.
+	Query string `json:"query"`
+
+	// Body is the body of the request.
+	Body []byte `json:"body,omitempty"`
+}
+
+// HTTPResponse is the response of [Plugin.HandleHTTP].
+type HTTPResponse struct {
+	// Header are the headers of the response.
+	Header http.Header `json:"header,omitempty"`
+
+	// Body is the body of the response.
+	Body []byte `json:"body,omitempty"`
+
+	// Status is the HTTP status code of the response.  If it's zero, 200 OK is
+	// used.
+	Status int `json:"status"`
+}
@@ -0,0 +1,337 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
+	"github.com/AdguardTeam/AdGuardHome/internal/dnsforward"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testTimeout is the common timeout for tests.
+const testTimeout = 1 * time.Second
+
+// testLogger is the common logger for tests.
+var testLogger = slogutil.NewDiscardLogger()
+
+// testName is the name of the plugin used in tests.
+const testName = "test"
+
+// testHookedFQDN is the domain name, for which [testPlugin] responds.
+const testHookedFQDN = "hooked.example."
+
+// testPlugin is a [Plugin] for tests.
+type testPlugin struct {
+	UnimplementedPlugin
+
+	// conf is the configuration received by Configure.
+	conf chan *ConfigureRequest
+}
+
+// Describe implements the [Plugin] interface for *testPlugin.
+func (p *testPlugin) Describe(_ context.Context) (d *Description, err error) {
+	return &Description{
+		Name:    testName,
+		Version: "v1.0.0",
+		Routes: []*Route{{
+			Method: http.MethodPost,
+			Path:   "/echo",
+		}},
+		DNSRequestHook: true,
+	}, nil
+}
+
+// Configure implements the [Plugin] interface for *testPlugin.
+func (p *testPlugin) Configure(_ context.Context, req *ConfigureRequest) (err error) {
+	p.conf <- req
+
+	return nil
+}
+
+// HandleDNSRequest implements the [Plugin] interface for *testPlugin.
+func (p *testPlugin) HandleDNSRequest(_ context.Context, q *DNSQuery) (res *DNSResult, err error) {
+	req := &dns.Msg{}
+	err = req.Unpack(q.Request)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Question[0].Name != testHookedFQDN {
+		return &DNSResult{}, nil
+	}
+
+	resp := (&dns.Msg{}).SetRcode(req, dns.RcodeRefused)
+	resp.Id = 0
+
+	data, err := resp.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	return &DNSResult{Msg: data}, nil
+}
+
+// HandleHTTP implements the [Plugin] interface for *testPlugin.
+func (p *testPlugin) HandleHTTP(
+	_ context.Context,
+	req *HTTPRequest,
+) (resp *HTTPResponse, err error) {
+	body := strings.Join([]string{
+		req.Method,
+		req.Path,
+		req.Query,
+		req.Header.Get("Cookie"),
+		string(req.Body),
+	}, " ")
+
+	return &HTTPResponse{
+		Header: http.Header{"X-Test": []string{"1"}},
+		Body:   []byte(body),
+		Status: http.StatusAccepted,
+	}, nil
+}
+
+// newTestProcess serves p on a Unix socket and returns a running plugin
+// connected to it.
+func newTestProcess(t *testing.T, p Plugin) (proc *process) {
+	t.Helper()
+
+	// Use a short path, since the length of the paths of Unix sockets is
+	// limited.
+	dir, err := os.MkdirTemp("", "agh")
+	require.NoError(t, err)
+
+	testutil.CleanupAndRequireSuccess(t, func() (err error) { return os.RemoveAll(dir) })
+
+	sockPath := filepath.Join(dir, socketName)
+	l, err := net.Listen("unix", sockPath)
+	require.NoError(t, err)
+
+	srvCtx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- ServeListener(srvCtx, l, p) }()
+
+	c, err := newClient(sockPath)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		assert.NoError(t, c.close())
+
+		cancel()
+		assert.NoError(t, <-errCh)
+	})
+
+	ctx := testutil.ContextWithTimeout(t, testTimeout)
+	desc, err := c.Describe(ctx)
+	require.NoError(t, err)
+	require.NoError(t, desc.validate())
+
+	return &process{
+		logger: testLogger,
+		client: c,
+		desc:   desc,
+	}
+}
+
+func TestManager(t *testing.T) {
+	t.Parallel()
+
+	p := &testPlugin{
+		conf: make(chan *ConfigureRequest, 1),
+	}
+	proc := newTestProcess(t, p)
+
+	m := New(&Config{
+		Logger:      testLogger,
+		HookTimeout: testTimeout,
+	})
+	m.plugins = []*process{proc}
+
+	t.Run("configure", func(t *testing.T) {
+		ctx := testutil.ContextWithTimeout(t, testTimeout)
+		err := proc.client.Configure(ctx, &ConfigureRequest{
+			Config:  json.RawMessage(`{"key":"value"}`),
+			DataDir: "/data",
+		})
+		require.NoError(t, err)
+
+		got, ok := testutil.RequireReceive(t, p.conf, testTimeout)
+		require.True(t, ok)
+
+		assert.JSONEq(t, `{"key":"value"}`, string(got.Config))
+		assert.Equal(t, "/data", got.DataDir)
+	})
+
+	t.Run("descriptions", func(t *testing.T) {
+		descs := m.Descriptions()
+		require.Len(t, descs, 1)
+
+		assert.Equal(t, testName, descs[0].Name)
+		assert.Equal(t, "v1.0.0", descs[0].Version)
+		assert.Same(t, m, m.Hook())
+	})
+
+	t.Run("dns_request", func(t *testing.T) {
+		req := (&dns.Msg{}).SetQuestion(testHookedFQDN, dns.TypeA)
+		q := &dnsforward.HookQuery{
+			Request:  req,
+			ClientIP: netip.MustParseAddr("192.0.2.1"),
+		}
+
+		ctx := testutil.ContextWithTimeout(t, testTimeout)
+		resp, err := m.HandleRequest(ctx, q)
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+
+		assert.Equal(t, dns.RcodeRefused, resp.Rcode)
+		assert.Equal(t, req.Id, resp.Id)
+
+		q.Request = (&dns.Msg{}).SetQuestion("other.example.", dns.TypeA)
+		resp, err = m.HandleRequest(ctx, q)
+		require.NoError(t, err)
+
+		assert.Nil(t, resp)
+	})
+
+	t.Run("dns_response", func(t *testing.T) {
+		req := (&dns.Msg{}).SetQuestion(testHookedFQDN, dns.TypeA)
+
+		ctx := testutil.ContextWithTimeout(t, testTimeout)
+		resp, err := m.HandleResponse(ctx, &dnsforward.HookQuery{
+			Request:  req,
+			Response: (&dns.Msg{}).SetReply(req),
+		})
+		require.NoError(t, err)
+
+		// The plugin doesn't handle responses.
+		assert.Nil(t, resp)
+	})
+
+	t.Run("http", func(t *testing.T) {
+		mux := http.NewServeMux()
+		m.RegisterHandlers(aghhttp.NewDefaultRegistrar(mux, func(
+			_ string,
+			h http.HandlerFunc,
+		) (wrapped http.Handler) {
+			return h
+		}))
+
+		r := httptest.NewRequest(
+			http.MethodPost,
+			"/control/plugins/test/echo?a=1",
+			strings.NewReader("body"),
+		)
+		r.Header.Set("Cookie", "session=secret")
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+
+		res := w.Result()
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusAccepted, res.StatusCode)
+		assert.Equal(t, "1", res.Header.Get("X-Test"))
+		assert.Equal(t, "POST /echo a=1  body", string(body))
+
+		r = httptest.NewRequest(http.MethodGet, "/control/plugins", nil)
+		w = httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+
+		require.Equal(t, http.StatusOK, w.Code)
+
+		list := &listResp{}
+		err = json.NewDecoder(w.Body).Decode(list)
+		require.NoError(t, err)
+		require.Len(t, list.Plugins, 1)
+
+		assert.Equal(t, testName, list.Plugins[0].Name)
+	})
+}
+
+func TestManager_Start_noDir(t *testing.T) {
+	t.Parallel()
+
+	m := New(&Config{
+		Logger: testLogger,
+		Dir:    filepath.Join(t.TempDir(), "plugins"),
+	})
+
+	err := m.Start(testutil.ContextWithTimeout(t, testTimeout))
+	require.NoError(t, err)
+
+	assert.Empty(t, m.Descriptions())
+	assert.Nil(t, m.Hook())
+}
+
+func TestDescription_validate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc       *Description
+		name       string
+		wantErrMsg string
+	}{{
+		desc: &Description{
+			Name:   "good_name-1",
+			Routes: []*Route{{Method: http.MethodGet, Path: "/"}},
+		},
+		name:       "valid",
+		wantErrMsg: "",
+	}, {
+		desc:       nil,
+		name:       "nil",
+		wantErrMsg: "no value",
+	}, {
+		desc:       &Description{Name: "Bad Name"},
+		name:       "bad_name",
+		wantErrMsg: `name: bad enum value: "Bad Name"`,
+	}, {
+		desc: &Description{
+			Name:   "test",
+			Routes: []*Route{{Method: "PATCH", Path: "/a"}},
+		},
+		name:       "bad_method",
+		wantErrMsg: `routes: at index 0: method: bad enum value: "PATCH"`,
+	}, {
+		desc: &Description{
+			Name:   "test",
+			Routes: []*Route{{Method: http.MethodGet, Path: "/../login"}},
+		},
+		name:       "bad_path",
+		wantErrMsg: `routes: at index 0: path: bad enum value: "/../login"`,
+	}, {
+		desc: &Description{
+			Name: "test",
+			Routes: []*Route{
+				{Method: http.MethodGet, Path: "/a"},
+				{Method: http.MethodGet, Path: "/a"},
+			},
+		},
+		name:       "duplicate_route",
+		wantErrMsg: `routes: at index 1: duplicated value`,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			testutil.AssertErrorMsg(t, tc.wantErrMsg, tc.desc.validate())
+		})
+	}
+}
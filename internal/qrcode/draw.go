@@ -0,0 +1,201 @@
+package qrcode
+
+// setFunction sets the module at column x and row y and marks it as a part of
+// a function pattern.
+func (c *Code) setFunction(x, y int, dark bool) {
+	c.modules[y][x] = dark
+	c.isFunction[y][x] = true
+}
+
+// drawFunctionPatterns draws the timing, finder, and alignment patterns as well
+// as reserves the areas of the format and the version information.
+func (c *Code) drawFunctionPatterns() {
+	for i := range c.size {
+		c.setFunction(6, i, i%2 == 0)
+		c.setFunction(i, 6, i%2 == 0)
+	}
+
+	c.drawFinder(3, 3)
+	c.drawFinder(c.size-4, 3)
+	c.drawFinder(3, c.size-4)
+
+	pos := alignmentPositions[c.version]
+	last := len(pos) - 1
+	for i, x := range pos {
+		for j, y := range pos {
+			// Skip the corners occupied by the finder patterns.
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+
+			c.drawAlignment(x, y)
+		}
+	}
+
+	// Reserve the area; the actual bits are drawn after masking.
+	c.drawFormatBits(0)
+	c.drawVersion()
+}
+
+// drawFinder draws the finder pattern with the separator around the center at
+// column x and row y.
+func (c *Code) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || yy < 0 || xx >= c.size || yy >= c.size {
+				continue
+			}
+
+			dist := max(abs(dx), abs(dy))
+			c.setFunction(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+// drawAlignment draws the alignment pattern around the center at column x and
+// row y.
+func (c *Code) drawAlignment(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			c.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// abs returns the absolute value of n.
+func abs(n int) (res int) {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+// bit returns true if the i-th bit of v is set.
+func bit(v uint, i int) (ok bool) {
+	return (v>>i)&1 == 1
+}
+
+// formatBits returns the BCH-encoded format information for the error
+// correction level M and mask.
+func formatBits(mask int) (bits uint) {
+	// The error correction level M is encoded as 0b00.
+	data := uint(mask)
+	rem := data
+	for range 10 {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+
+	return (data<<10 | rem) ^ 0x5412
+}
+
+// drawFormatBits draws both copies of the format information for mask.
+func (c *Code) drawFormatBits(mask int) {
+	bits := formatBits(mask)
+
+	for i := range 6 {
+		c.setFunction(8, i, bit(bits, i))
+	}
+
+	c.setFunction(8, 7, bit(bits, 6))
+	c.setFunction(8, 8, bit(bits, 7))
+	c.setFunction(7, 8, bit(bits, 8))
+	for i := 9; i < 15; i++ {
+		c.setFunction(14-i, 8, bit(bits, i))
+	}
+
+	for i := range 8 {
+		c.setFunction(c.size-1-i, 8, bit(bits, i))
+	}
+
+	for i := 8; i < 15; i++ {
+		c.setFunction(8, c.size-15+i, bit(bits, i))
+	}
+
+	// The dark module.
+	c.setFunction(8, c.size-8, true)
+}
+
+// drawVersion draws both copies of the version information, if the version
+// requires it.
+func (c *Code) drawVersion() {
+	if c.version < 7 {
+		return
+	}
+
+	rem := uint(c.version)
+	for range 12 {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+
+	bits := uint(c.version)<<12 | rem
+	for i := range 18 {
+		a, b := c.size-11+i%3, i/3
+		c.setFunction(a, b, bit(bits, i))
+		c.setFunction(b, a, bit(bits, i))
+	}
+}
+
+// drawCodewords draws data in the zigzag order over the modules, which aren't
+// a part of the function patterns.
+func (c *Code) drawCodewords(data []byte) {
+	i := 0
+	for right := c.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			// Skip the vertical timing pattern.
+			right = 5
+		}
+
+		upward := (right+1)&2 == 0
+		for vert := range c.size {
+			y := vert
+			if upward {
+				y = c.size - 1 - vert
+			}
+
+			for j := range 2 {
+				x := right - j
+				if c.isFunction[y][x] || i >= len(data)*8 {
+					continue
+				}
+
+				c.modules[y][x] = bit(uint(data[i/8]), 7-i%8)
+				i++
+			}
+		}
+	}
+}
+
+// applyMask inverts the data modules selected by mask.
+func (c *Code) applyMask(mask int) {
+	for y := range c.size {
+		for x := range c.size {
+			if !c.isFunction[y][x] && masked(mask, x, y) {
+				c.modules[y][x] = !c.modules[y][x]
+			}
+		}
+	}
+}
+
+// masked returns true if mask inverts the module at column x and row y.
+func masked(mask, x, y int) (ok bool) {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
@@ -0,0 +1,116 @@
+package qrcode
+
+// The weights of the penalty rules.
+const (
+	penaltyRun     = 3
+	penaltyBlock   = 3
+	penaltyFinder  = 40
+	penaltyBalance = 10
+)
+
+// penalty returns the penalty score of c, which is used to choose the best
+// mask.
+func (c *Code) penalty() (res int) {
+	for y := range c.size {
+		res += c.linePenalty(func(i int) (dark bool) { return c.modules[y][i] })
+	}
+
+	for x := range c.size {
+		res += c.linePenalty(func(i int) (dark bool) { return c.modules[i][x] })
+	}
+
+	dark := 0
+	for y := range c.size {
+		for x := range c.size {
+			color := c.modules[y][x]
+			if color {
+				dark++
+			}
+
+			if x < c.size-1 && y < c.size-1 &&
+				color == c.modules[y][x+1] &&
+				color == c.modules[y+1][x] &&
+				color == c.modules[y+1][x+1] {
+				res += penaltyBlock
+			}
+		}
+	}
+
+	total := c.size * c.size
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+
+	return res + k*penaltyBalance
+}
+
+// linePenalty returns the penalty score for the runs of the same color and the
+// finder-like patterns in a row or a column, which modules are returned by
+// module.
+func (c *Code) linePenalty(module func(i int) (dark bool)) (res int) {
+	runColor := false
+	runLen := 0
+	h := &runHistory{size: c.size}
+	for i := range c.size {
+		if module(i) == runColor {
+			runLen++
+			if runLen == 5 {
+				res += penaltyRun
+			} else if runLen > 5 {
+				res++
+			}
+
+			continue
+		}
+
+		h.add(runLen)
+		if !runColor {
+			res += h.countFinders() * penaltyFinder
+		}
+
+		runColor = !runColor
+		runLen = 1
+	}
+
+	if runColor {
+		h.add(runLen)
+		runLen = 0
+	}
+
+	// Account for the light border.
+	h.add(runLen + c.size)
+
+	return res + h.countFinders()*penaltyFinder
+}
+
+// runHistory stores the lengths of the last seven runs of modules in a line.
+type runHistory struct {
+	runs [7]int
+	size int
+}
+
+// add records a run of n modules.
+func (h *runHistory) add(n int) {
+	if h.runs[0] == 0 {
+		// Account for the light border before the first run.
+		n += h.size
+	}
+
+	copy(h.runs[1:], h.runs[:len(h.runs)-1])
+	h.runs[0] = n
+}
+
+// countFinders returns the number of the finder-like patterns, which end with
+// the last light run.
+func (h *runHistory) countFinders() (n int) {
+	r := h.runs
+	m := r[1]
+	core := m > 0 && r[2] == m && r[3] == m*3 && r[4] == m && r[5] == m
+	if core && r[0] >= m*4 && r[6] >= m {
+		n++
+	}
+
+	if core && r[6] >= m*4 && r[0] >= m {
+		n++
+	}
+
+	return n
+}
@@ -0,0 +1,288 @@
+// Package qrcode contains a minimal encoder of QR codes, which is enough to
+// render short strings, like the otpauth URIs of the two-factor authentication.
+//
+// The encoder only supports the byte mode, the error correction level M, and
+// the versions from 1 to 10.
+package qrcode
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// ErrTooLong is returned when the data don't fit into the largest supported
+// QR code.
+const ErrTooLong errors.Error = "data too long"
+
+// maxVersion is the largest supported version of the QR codes.
+const maxVersion = 10
+
+// eccPerBlock is the number of the error correction codewords per block for the
+// error correction level M, indexed by the version.
+var eccPerBlock = [maxVersion + 1]int{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26}
+
+// numBlocks is the number of the error correction blocks for the error
+// correction level M, indexed by the version.
+var numBlocks = [maxVersion + 1]int{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5}
+
+// alignmentPositions are the coordinates of the centers of the alignment
+// patterns, indexed by the version.
+var alignmentPositions = [maxVersion + 1][]int{
+	nil,
+	nil,
+	{6, 18},
+	{6, 22},
+	{6, 26},
+	{6, 30},
+	{6, 34},
+	{6, 22, 38},
+	{6, 24, 42},
+	{6, 26, 46},
+	{6, 28, 50},
+}
+
+// Code is an encoded QR code.
+type Code struct {
+	// modules are the modules of the code, indexed by the row and then by the
+	// column.  True means dark.
+	modules [][]bool
+
+	// isFunction marks the modules of the function patterns, which aren't
+	// masked.  It's only used during encoding.
+	isFunction [][]bool
+
+	// size is the number of modules on a side.
+	size int
+
+	// version is the version of the code.
+	version int
+}
+
+// Encode returns the QR code with data encoded in the byte mode.  It returns
+// [ErrTooLong] if data don't fit into the supported versions.
+func Encode(data []byte) (c *Code, err error) {
+	ver := 1
+	for ; ver <= maxVersion; ver++ {
+		if dataBits(len(data), ver) <= numDataCodewords(ver)*8 {
+			break
+		}
+	}
+
+	if ver > maxVersion {
+		return nil, fmt.Errorf("%d bytes: %w", len(data), ErrTooLong)
+	}
+
+	size := ver*4 + 17
+	c = &Code{
+		modules:    newGrid(size),
+		isFunction: newGrid(size),
+		size:       size,
+		version:    ver,
+	}
+
+	c.drawFunctionPatterns()
+	c.drawCodewords(addECCAndInterleave(encodeSegment(data, ver), ver))
+
+	bestMask, minPenalty := 0, math.MaxInt
+	for mask := range 8 {
+		c.applyMask(mask)
+		c.drawFormatBits(mask)
+		if p := c.penalty(); p < minPenalty {
+			bestMask, minPenalty = mask, p
+		}
+
+		// Masking is an XOR, so applying it again reverts it.
+		c.applyMask(mask)
+	}
+
+	c.applyMask(bestMask)
+	c.drawFormatBits(bestMask)
+	c.isFunction = nil
+
+	return c, nil
+}
+
+// Size returns the number of modules on a side of c.
+func (c *Code) Size() (n int) {
+	return c.size
+}
+
+// Dark returns true if the module at column x and row y is dark.  The modules
+// outside of the code are light.
+func (c *Code) Dark(x, y int) (ok bool) {
+	return x >= 0 && y >= 0 && x < c.size && y < c.size && c.modules[y][x]
+}
+
+// newGrid returns a square grid of size.
+func newGrid(size int) (g [][]bool) {
+	g = make([][]bool, size)
+	for i := range g {
+		g[i] = make([]bool, size)
+	}
+
+	return g
+}
+
+// countBits returns the length of the character count indicator of the byte
+// mode in ver.
+func countBits(ver int) (n int) {
+	if ver <= 9 {
+		return 8
+	}
+
+	return 16
+}
+
+// dataBits returns the number of bits of the segment with n bytes in ver.
+func dataBits(n, ver int) (bits int) {
+	return 4 + countBits(ver) + n*8
+}
+
+// numRawDataModules returns the number of the modules available for the data
+// and the error correction codewords in ver.
+func numRawDataModules(ver int) (n int) {
+	n = (16*ver+128)*ver + 64
+	if ver >= 2 {
+		numAlign := ver/7 + 2
+		n -= (25*numAlign-10)*numAlign - 55
+		if ver >= 7 {
+			n -= 36
+		}
+	}
+
+	return n
+}
+
+// numDataCodewords returns the number of the data codewords in ver.
+func numDataCodewords(ver int) (n int) {
+	return numRawDataModules(ver)/8 - eccPerBlock[ver]*numBlocks[ver]
+}
+
+// bitBuffer is a sequence of bits.
+type bitBuffer []bool
+
+// appendBits appends the n low bits of val in the big-endian order.
+func (b *bitBuffer) appendBits(val uint, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*b = append(*b, (val>>i)&1 == 1)
+	}
+}
+
+// encodeSegment returns the data codewords of a single byte mode segment with
+// data, padded to the capacity of ver.
+func encodeSegment(data []byte, ver int) (codewords []byte) {
+	capBits := numDataCodewords(ver) * 8
+
+	bb := make(bitBuffer, 0, capBits)
+	bb.appendBits(0b0100, 4)
+	bb.appendBits(uint(len(data)), countBits(ver))
+	for _, b := range data {
+		bb.appendBits(uint(b), 8)
+	}
+
+	bb.appendBits(0, min(4, capBits-len(bb)))
+	bb.appendBits(0, (8-len(bb)%8)%8)
+	for pad := uint(0xEC); len(bb) < capBits; pad ^= 0xEC ^ 0x11 {
+		bb.appendBits(pad, 8)
+	}
+
+	codewords = make([]byte, len(bb)/8)
+	for i, bit := range bb {
+		if bit {
+			codewords[i/8] |= 1 << (7 - i%8)
+		}
+	}
+
+	return codewords
+}
+
+// addECCAndInterleave splits data into the blocks of ver, appends the error
+// correction codewords to each of them, and interleaves the result.
+func addECCAndInterleave(data []byte, ver int) (res []byte) {
+	blocksNum := numBlocks[ver]
+	eccLen := eccPerBlock[ver]
+	rawCodewords := numRawDataModules(ver) / 8
+	numShortBlocks := blocksNum - rawCodewords%blocksNum
+	shortBlockLen := rawCodewords / blocksNum
+
+	divisor := rsDivisor(eccLen)
+	blocks := make([][]byte, 0, blocksNum)
+	for i, k := 0, 0; i < blocksNum; i++ {
+		datLen := shortBlockLen - eccLen
+		if i >= numShortBlocks {
+			datLen++
+		}
+
+		block := make([]byte, 0, shortBlockLen+1)
+		block = append(block, data[k:k+datLen]...)
+		k += datLen
+
+		ecc := rsRemainder(block, divisor)
+		if i < numShortBlocks {
+			// Keep the columns aligned; the placeholder is skipped below.
+			block = append(block, 0)
+		}
+
+		blocks = append(blocks, append(block, ecc...))
+	}
+
+	res = make([]byte, 0, rawCodewords)
+	for i := range shortBlockLen + 1 {
+		for j, block := range blocks {
+			if i != shortBlockLen-eccLen || j >= numShortBlocks {
+				res = append(res, block[i])
+			}
+		}
+	}
+
+	return res
+}
+
+// gfMul returns the product of x and y in GF(2^8) with the polynomial 0x11D.
+func gfMul(x, y byte) (z byte) {
+	var r uint
+	for i := 7; i >= 0; i-- {
+		r = (r << 1) ^ ((r >> 7) * 0x11D)
+		r ^= uint((y>>i)&1) * uint(x)
+	}
+
+	return byte(r)
+}
+
+// rsDivisor returns the coefficients of the Reed–Solomon generator polynomial
+// of degree, from the highest to the lowest, without the leading one.
+func rsDivisor(degree int) (res []byte) {
+	res = make([]byte, degree)
+	res[degree-1] = 1
+
+	root := byte(1)
+	for range degree {
+		for j := range res {
+			res[j] = gfMul(res[j], root)
+			if j+1 < len(res) {
+				res[j] ^= res[j+1]
+			}
+		}
+
+		root = gfMul(root, 0x02)
+	}
+
+	return res
+}
+
+// rsRemainder returns the Reed–Solomon error correction codewords of data.
+func rsRemainder(data, divisor []byte) (res []byte) {
+	res = make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ res[0]
+		copy(res, res[1:])
+		res[len(res)-1] = 0
+		for i, d := range divisor {
+			res[i] ^= gfMul(d, factor)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,132 @@
+package qrcode_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/qrcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// render returns the modules of c as lines of ones and zeroes.
+func render(c *qrcode.Code) (s string) {
+	b := &strings.Builder{}
+	for y := range c.Size() {
+		for x := range c.Size() {
+			if c.Dark(x, y) {
+				b.WriteByte('1')
+			} else {
+				b.WriteByte('0')
+			}
+		}
+
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+
+	// The code with the mask pattern 4.
+	const want = `111111101100101111111
+100000100001001000001
+101110100101001011101
+101110101001001011101
+101110101110101011101
+100000101001001000001
+111111101010101111111
+000000001001100000000
+100010111111011111001
+000100001011100001111
+001111110011011010010
+111110001100010000000
+111110101010101100110
+000000001010111101011
+111111101110101011010
+100000100101110110011
+101110101101011000110
+101110100100100011011
+101110100111000111000
+100000100001010000000
+111111101111111110101
+`
+
+	c, err := qrcode.Encode([]byte("HELLO WORLD"))
+	require.NoError(t, err)
+
+	assert.Equal(t, 21, c.Size())
+	assert.Equal(t, want, render(c))
+	assert.False(t, c.Dark(-1, 0))
+	assert.False(t, c.Dark(0, c.Size()))
+}
+
+func TestEncode_versions(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		wantErr  error
+		dataLen  int
+		wantSize int
+	}{{
+		name:     "empty",
+		wantErr:  nil,
+		dataLen:  0,
+		wantSize: 21,
+	}, {
+		name:     "version_2",
+		wantErr:  nil,
+		dataLen:  15,
+		wantSize: 25,
+	}, {
+		name:     "version_7",
+		wantErr:  nil,
+		dataLen:  120,
+		wantSize: 45,
+	}, {
+		name:     "max",
+		wantErr:  nil,
+		dataLen:  213,
+		wantSize: 57,
+	}, {
+		name:     "too_long",
+		wantErr:  qrcode.ErrTooLong,
+		dataLen:  214,
+		wantSize: 0,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := qrcode.Encode(bytes.Repeat([]byte{'a'}, tc.dataLen))
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.wantSize, c.Size())
+		})
+	}
+}
+
+func TestCode_SVG(t *testing.T) {
+	t.Parallel()
+
+	c, err := qrcode.Encode([]byte("test"))
+	require.NoError(t, err)
+
+	img := string(c.SVG())
+	assert.True(t, strings.HasPrefix(img, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 29 29"`))
+	assert.True(t, strings.HasSuffix(img, `"/></svg>`))
+
+	// The top left module of the finder pattern.
+	assert.Contains(t, img, `d="M4 4h1v1h-1z`)
+}
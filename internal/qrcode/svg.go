@@ -0,0 +1,35 @@
+package qrcode
+
+import (
+	"fmt"
+	"strings"
+)
+
+// quietZone is the width of the light border around the code in modules.
+const quietZone = 4
+
+// SVG returns the SVG image of c with the mandatory light border.  A module is
+// one user unit, so the image should be scaled by the viewer.
+func (c *Code) SVG() (img []byte) {
+	side := c.size + quietZone*2
+
+	b := &strings.Builder{}
+	_, _ = fmt.Fprintf(
+		b,
+		`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %[1]d %[1]d" shape-rendering="crispEdges">`,
+		side,
+	)
+	_, _ = fmt.Fprintf(b, `<rect width="%[1]d" height="%[1]d" fill="#fff"/><path fill="#000" d="`, side)
+
+	for y := range c.size {
+		for x := range c.size {
+			if c.modules[y][x] {
+				_, _ = fmt.Fprintf(b, "M%d %dh1v1h-1z", x+quietZone, y+quietZone)
+			}
+		}
+	}
+
+	b.WriteString(`"/></svg>`)
+
+	return []byte(b.String())
+}
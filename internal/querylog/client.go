@@ -7,7 +7,9 @@ import "github.com/AdguardTeam/AdGuardHome/internal/whois"
 type Client struct {
 	WHOIS          *whois.Info `json:"whois,omitempty"`
 	Name           string      `json:"name"`
+	Notes          string      `json:"notes,omitempty"`
 	DisallowedRule string      `json:"disallowed_rule"`
+	Tags           []string    `json:"tags,omitempty"`
 	Disallowed     bool        `json:"disallowed"`
 	IgnoreQueryLog bool        `json:"-"`
 }
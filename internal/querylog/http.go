@@ -386,11 +386,13 @@ func (l *queryLog) parseSearchCriterion(
 			// Don't wrap the error, because it's informative enough as is.
 			return false, sc, err
 		}
+	case ctClientTag:
+		// Go on, any tag value is valid.
 	default:
 		return false, sc, fmt.Errorf(
 			"invalid criterion type %v: should be one of %v",
 			ct,
-			[]criterionType{ctTerm, ctFilteringStatus, ctReason},
+			[]criterionType{ctTerm, ctFilteringStatus, ctReason, ctClientTag},
 		)
 	}
 
@@ -490,6 +492,9 @@ func (l *queryLog) parseSearchCriterions(
 	}, {
 		urlField: "reason",
 		ct:       ctReason,
+	}, {
+		urlField: "client_tag",
+		ct:       ctClientTag,
 	}} {
 		var ok bool
 		var c searchCriterion
@@ -11,6 +11,7 @@ import (
 
 	"github.com/AdguardTeam/AdGuardHome/internal/aghnet"
 	"github.com/AdguardTeam/AdGuardHome/internal/filtering"
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
 	"github.com/AdguardTeam/golibs/container"
 	"github.com/AdguardTeam/golibs/errors"
 	"github.com/AdguardTeam/golibs/logutil/slogutil"
@@ -36,6 +37,13 @@ type queryLog struct {
 
 	findClient func(ids []string) (c *Client, err error)
 
+	// onStorageError, if not nil, is called when writing or rotating the log
+	// files fails.
+	onStorageError func(ctx context.Context, err error)
+
+	// onAdd, if not nil, is called with the summary of each added entry.
+	onAdd func(e notifications.QueryLogEntry)
+
 	// buffer contains recent log entries.  The entries in this buffer must not
 	// be modified.
 	buffer *container.RingBuffer[*logEntry]
@@ -246,6 +254,9 @@ func (l *queryLog) Add(params *AddParams) {
 	}
 
 	entry := newLogEntry(ctx, l.logger, params)
+	if l.onAdd != nil {
+		l.onAdd(entry.summary(l.anonymizer.Load()))
+	}
 
 	l.bufferLock.Lock()
 	defer l.bufferLock.Unlock()
@@ -1,6 +1,7 @@
 package querylog
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"net"
@@ -62,6 +63,14 @@ type Config struct {
 	// FindClient returns client information by their IDs.
 	FindClient func(ids []string) (c *Client, err error)
 
+	// OnStorageError, if not nil, is called when writing or rotating the log
+	// files fails.
+	OnStorageError func(ctx context.Context, err error)
+
+	// OnAdd, if not nil, is called with the summary of each added entry.  It
+	// must not block.
+	OnAdd func(e notifications.QueryLogEntry)
+
 	// BaseDir is the base directory for log files.
 	BaseDir string
 
@@ -163,6 +172,9 @@ func newQueryLog(conf Config) (l *queryLog, err error) {
 		logger:     conf.Logger,
 		findClient: findClient,
 
+		onStorageError: conf.OnStorageError,
+		onAdd:          conf.OnAdd,
+
 		buffer: container.NewRingBuffer[*logEntry](memSize),
 
 		conf:    &Config{},
@@ -81,6 +81,12 @@ func (l *queryLog) flushToFile(ctx context.Context, b *bytes.Buffer) (err error)
 	l.fileWriteLock.Lock()
 	defer l.fileWriteLock.Unlock()
 
+	defer func() {
+		if err != nil {
+			l.reportStorageError(ctx, err)
+		}
+	}()
+
 	filename := l.logFile
 
 	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_APPEND, aghos.DefaultPermFile)
@@ -100,6 +106,13 @@ func (l *queryLog) flushToFile(ctx context.Context, b *bytes.Buffer) (err error)
 	return nil
 }
 
+// reportStorageError passes err to the storage error handler, if any.
+func (l *queryLog) reportStorageError(ctx context.Context, err error) {
+	if l.onStorageError != nil {
+		l.onStorageError(ctx, err)
+	}
+}
+
 func (l *queryLog) rotate(ctx context.Context) error {
 	from := l.logFile
 	to := l.logFile + ".1"
@@ -199,6 +212,7 @@ func (l *queryLog) checkAndRotate(ctx context.Context) {
 	err = l.rotate(ctx)
 	if err != nil {
 		l.logger.ErrorContext(ctx, "rotating", slogutil.KeyError, err)
+		l.reportStorageError(ctx, err)
 
 		return
 	}
@@ -1,6 +1,9 @@
 package querylog
 
 import (
+	"slices"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghnet"
 	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
 )
 
@@ -20,25 +23,35 @@ func (l *queryLog) GetRecentQueries(limit int) []notifications.QueryLogEntry {
 			return false
 		}
 
-		blocked := entry.Result.IsFiltered
-		client := ""
-		if entry.IP != nil {
-			client = entry.IP.String()
-		}
-		if entry.ClientID != "" {
-			client = entry.ClientID
-		}
-
-		entries = append(entries, notifications.QueryLogEntry{
-			Domain:   entry.QHost,
-			Client:   client,
-			Blocked:  blocked,
-			Duration: entry.Elapsed,
-			Time:     entry.Time,
-		})
+		entries = append(entries, entry.summary(nil))
 
 		return true
 	})
 
 	return entries
 }
+
+// summary returns the short summary of e used in the notifications.  If
+// anonFunc is not nil, it's applied to the client address.
+func (e *logEntry) summary(anonFunc aghnet.IPMutFunc) (s notifications.QueryLogEntry) {
+	client := ""
+	if e.IP != nil {
+		ip := slices.Clone(e.IP)
+		if anonFunc != nil {
+			anonFunc(ip)
+		}
+
+		client = ip.String()
+	}
+	if e.ClientID != "" {
+		client = e.ClientID
+	}
+
+	return notifications.QueryLogEntry{
+		Domain:   e.QHost,
+		Client:   client,
+		Blocked:  e.Result.IsFiltered,
+		Duration: e.Elapsed,
+		Time:     e.Time,
+	}
+}
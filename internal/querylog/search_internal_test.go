@@ -99,3 +99,105 @@ func TestQueryLog_Search_findClient(t *testing.T) {
 
 	assert.Equal(t, knownClientName, gotClient.Name)
 }
+
+func TestQueryLog_Search_clientTag(t *testing.T) {
+	const taggedClientID = "client-1"
+
+	taggedClient := &Client{
+		Name:  "Tagged Client",
+		Notes: "Living room",
+		Tags:  []string{"device_tv", "Family"},
+	}
+
+	findClient := func(ids []string) (c *Client, _ error) {
+		if len(ids) > 0 && ids[0] == taggedClientID {
+			return taggedClient, nil
+		}
+
+		return nil, nil
+	}
+
+	l, err := newQueryLog(Config{
+		Logger:      testLogger,
+		FindClient:  findClient,
+		BaseDir:     t.TempDir(),
+		RotationIvl: timeutil.Day,
+		MemSize:     100,
+		Enabled:     true,
+		FileEnabled: true,
+	})
+	require.NoError(t, err)
+
+	ctx := testutil.ContextWithTimeout(t, testTimeout)
+	testutil.CleanupAndRequireSuccess(t, func() (err error) {
+		return l.Shutdown(ctx)
+	})
+
+	q := &dns.Msg{
+		Question: []dns.Question{{
+			Name: "example.com",
+		}},
+	}
+
+	l.Add(&AddParams{
+		Question: q,
+		ClientID: taggedClientID,
+		ClientIP: net.IP{1, 2, 3, 4},
+	})
+
+	l.Add(&AddParams{
+		Question: q,
+		ClientID: "client-2",
+		ClientIP: net.IP{1, 2, 3, 5},
+	})
+
+	testCases := []struct {
+		name    string
+		value   string
+		wantLen int
+		strict  bool
+	}{{
+		name:    "strict",
+		value:   "family",
+		wantLen: 1,
+		strict:  true,
+	}, {
+		name:    "strict_partial",
+		value:   "fam",
+		wantLen: 0,
+		strict:  true,
+	}, {
+		name:    "non_strict",
+		value:   "fam",
+		wantLen: 1,
+		strict:  false,
+	}, {
+		name:    "not_found",
+		value:   "office",
+		wantLen: 0,
+		strict:  false,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sp := &searchParams{
+				searchCriteria: []searchCriterion{{
+					criterionType: ctClientTag,
+					value:         tc.value,
+					strict:        tc.strict,
+				}},
+				olderThan: time.Now().Add(10 * time.Second),
+				limit:     10,
+			}
+
+			entries, _ := l.search(ctx, sp)
+			require.Len(t, entries, tc.wantLen)
+
+			for _, e := range entries {
+				require.NotNil(t, e.client)
+
+				assert.Equal(t, taggedClient.Notes, e.client.Notes)
+			}
+		})
+	}
+}
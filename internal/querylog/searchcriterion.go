@@ -30,6 +30,10 @@ const (
 
 	// ctReason is for searching by the filtering reason.
 	ctReason
+
+	// ctClientTag is for searching by the tags of the persistent client, both
+	// predefined and custom ones.
+	ctClientTag
 )
 
 const (
@@ -78,8 +82,8 @@ var reasonCodes = [...]string{
 // searchCriterion is a search criterion that is used to match a record.
 type searchCriterion struct {
 	// value is the target value for searching.  If
-	// [searchCriterion.criterionType] is [ctTerm] or [ctFilteringStatus] value
-	// must not be empty.
+	// [searchCriterion.criterionType] is [ctTerm], [ctFilteringStatus], or
+	// [ctClientTag] value must not be empty.
 	value string
 
 	// asciiVal is the ASCII representation of value for matching IDNA domain
@@ -94,6 +98,7 @@ type searchCriterion struct {
 	//	- [ctTerm]
 	//	- [ctFilteringStatus]
 	//	- [ctReason]
+	//	- [ctClientTag]
 	criterionType criterionType
 
 	// strict, if true, means that the criterion must be applied to the whole
@@ -174,6 +179,11 @@ func (c *searchCriterion) quickMatch(
 		}
 
 		return slices.Contains(c.values, filtering.Reason(idx).String())
+	case ctClientTag:
+		ip := readJSONValue(line, `"IP":"`)
+		clientID := readJSONValue(line, `"CID":"`)
+
+		return c.ctClientTagCase(findClient(ctx, logger, clientID, ip))
 	default:
 		return true
 	}
@@ -191,11 +201,29 @@ func (c *searchCriterion) match(entry *logEntry) bool {
 		// TODO(f.setrakov): Consider comparing [filtering.Reason] instead of
 		// strings.
 		return slices.Contains(c.values, entry.Result.Reason.String())
+	case ctClientTag:
+		return c.ctClientTagCase(entry.client)
 	}
 
 	return false
 }
 
+// ctClientTagCase returns true if cli has a tag matching the value.  cli may be
+// nil.
+func (c *searchCriterion) ctClientTagCase(cli *Client) (ok bool) {
+	if cli == nil {
+		return false
+	}
+
+	return slices.ContainsFunc(cli.Tags, func(t string) (found bool) {
+		if c.strict {
+			return strings.EqualFold(t, c.value)
+		}
+
+		return stringutil.ContainsFold(t, c.value)
+	})
+}
+
 func (c *searchCriterion) ctDomainOrClientCase(e *logEntry) bool {
 	clientID := e.ClientID
 	host := e.QHost
@@ -0,0 +1,67 @@
+package stats
+
+import (
+	"sync"
+)
+
+// maxBlockedClients is the maximum number of clients the blocked requests are
+// counted for separately between the calls of the take method.  The requests
+// of the other clients are only included into the total.
+const maxBlockedClients = 10_000
+
+// blockedCounter counts the blocked requests since the previous call of its
+// take method.
+type blockedCounter struct {
+	// mu protects total and byClient.
+	mu *sync.Mutex
+
+	// byClient stores the number of blocked requests from each client.
+	byClient map[string]uint64
+
+	// total stores the total number of blocked requests.
+	total uint64
+}
+
+// newBlockedCounter returns a new properly initialized *blockedCounter.
+func newBlockedCounter() (c *blockedCounter) {
+	return &blockedCounter{
+		mu:       &sync.Mutex{},
+		byClient: map[string]uint64{},
+	}
+}
+
+// isBlocked returns true if res means that the request has been blocked.
+func isBlocked(res Result) (ok bool) {
+	return res == RFiltered || res == RSafeBrowsing || res == RParental
+}
+
+// add counts the entry, if it's blocked.
+func (c *blockedCounter) add(e *Entry) {
+	if !isBlocked(e.Result) {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.total++
+	if _, ok := c.byClient[e.Client]; ok || len(c.byClient) < maxBlockedClients {
+		c.byClient[e.Client]++
+	}
+}
+
+// take returns the counts and resets them.
+func (c *blockedCounter) take() (total uint64, byClient map[string]uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	total, byClient = c.total, c.byClient
+	c.total, c.byClient = 0, make(map[string]uint64, len(byClient))
+
+	return total, byClient
+}
+
+// TakeBlockedCounts implements the [Interface] interface for *StatsCtx.
+func (s *StatsCtx) TakeBlockedCounts() (total uint64, byClient map[string]uint64) {
+	return s.blocked.take()
+}
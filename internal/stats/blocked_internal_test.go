@@ -0,0 +1,25 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockedCounter(t *testing.T) {
+	c := newBlockedCounter()
+
+	c.add(&Entry{Client: "1.2.3.4", Result: RFiltered})
+	c.add(&Entry{Client: "1.2.3.4", Result: RParental})
+	c.add(&Entry{Client: "5.6.7.8", Result: RSafeBrowsing})
+	c.add(&Entry{Client: "5.6.7.8", Result: RNotFiltered})
+	c.add(&Entry{Client: "5.6.7.8", Result: RSafeSearch})
+
+	total, byClient := c.take()
+	assert.Equal(t, uint64(3), total)
+	assert.Equal(t, map[string]uint64{"1.2.3.4": 2, "5.6.7.8": 1}, byClient)
+
+	total, byClient = c.take()
+	assert.Zero(t, total)
+	assert.Empty(t, byClient)
+}
@@ -100,7 +100,7 @@ func (s *StatsCtx) handleStats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp.System = systeminfo.Collect()
+	resp.System = systeminfo.Collect(ctx)
 
 	aghhttp.WriteJSONResponseOK(ctx, l, w, r, resp)
 }
@@ -0,0 +1,66 @@
+package stats
+
+import (
+	"sync"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+)
+
+// perfCounter counts the DNS performance data since the previous call of its
+// take method.
+type perfCounter struct {
+	// mu protects counts.
+	mu *sync.Mutex
+
+	// counts stores the current counts.
+	counts notifications.DNSPerfCounts
+}
+
+// newPerfCounter returns a new properly initialized *perfCounter.
+func newPerfCounter() (c *perfCounter) {
+	return &perfCounter{
+		mu: &sync.Mutex{},
+	}
+}
+
+// add counts the entry.  Only the successful upstream queries, which haven't
+// been answered from the cache, are included into the upstream time.
+func (c *perfCounter) add(e *Entry) {
+	var upstreams uint64
+	var upstreamTime time.Duration
+	for _, us := range e.UpstreamStats {
+		if us.Error != nil || us.IsCached {
+			continue
+		}
+
+		upstreams++
+		upstreamTime += us.QueryDuration
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.counts.Queries++
+	if e.ServFail {
+		c.counts.ServFail++
+	}
+
+	c.counts.UpstreamQueries += upstreams
+	c.counts.UpstreamTime += upstreamTime
+}
+
+// take returns the counts and resets them.
+func (c *perfCounter) take() (counts notifications.DNSPerfCounts) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counts, c.counts = c.counts, notifications.DNSPerfCounts{}
+
+	return counts
+}
+
+// TakeDNSPerfCounts implements the [Interface] interface for *StatsCtx.
+func (s *StatsCtx) TakeDNSPerfCounts() (c notifications.DNSPerfCounts) {
+	return s.perf.take()
+}
@@ -0,0 +1,44 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+	"github.com/AdguardTeam/dnsproxy/proxy"
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPerfCounter(t *testing.T) {
+	c := newPerfCounter()
+
+	c.add(&Entry{
+		UpstreamStats: []*proxy.UpstreamStatistics{{
+			QueryDuration: 30 * time.Millisecond,
+		}, {
+			Error:         errors.Error("timeout"),
+			QueryDuration: time.Second,
+		}},
+	})
+	c.add(&Entry{
+		UpstreamStats: []*proxy.UpstreamStatistics{{
+			QueryDuration: 10 * time.Millisecond,
+		}},
+		ServFail: true,
+	})
+	c.add(&Entry{
+		UpstreamStats: []*proxy.UpstreamStatistics{{
+			IsCached: true,
+		}},
+	})
+
+	assert.Equal(t, notifications.DNSPerfCounts{
+		Queries:         3,
+		ServFail:        1,
+		UpstreamQueries: 2,
+		UpstreamTime:    40 * time.Millisecond,
+	}, c.take())
+
+	assert.Zero(t, c.take())
+}
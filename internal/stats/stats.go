@@ -17,6 +17,7 @@ import (
 	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
 	"github.com/AdguardTeam/AdGuardHome/internal/aghnet"
 	"github.com/AdguardTeam/AdGuardHome/internal/aghos"
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
 	"github.com/AdguardTeam/golibs/errors"
 	"github.com/AdguardTeam/golibs/logutil/slogutil"
 	"github.com/AdguardTeam/golibs/timeutil"
@@ -71,6 +72,10 @@ type Config struct {
 	// and matches them.
 	Ignored *aghnet.IgnoreEngine
 
+	// OnStorageError, if not nil, is called when writing to the database
+	// fails or its contents are found to be corrupted.
+	OnStorageError func(ctx context.Context, err error)
+
 	// Filename is the name of the database file.
 	//
 	// TODO(f.setrakov): Move the work with DB into a separate entity with
@@ -106,6 +111,18 @@ type Interface interface {
 
 	// GetCurrentStats returns aggregate DNS query statistics.
 	GetCurrentStats() (numQueries, numBlocked, numSafeBrowsing, numParental uint64, avgProcessingTime float64)
+
+	// TakeBlockedCounts returns the total number of blocked requests and the
+	// numbers of blocked requests by client since the previous call.
+	TakeBlockedCounts() (total uint64, byClient map[string]uint64)
+
+	// TakeDNSPerfCounts returns the DNS performance counters since the
+	// previous call.
+	TakeDNSPerfCounts() (c notifications.DNSPerfCounts)
+
+	// StatsSummary returns the statistics for the last period with at most
+	// limit entries in each top list.
+	StatsSummary(period time.Duration, limit int) (sum notifications.StatsSummary, ok bool)
 }
 
 // StatsCtx collects the statistics and flushes it to the database.  Its default
@@ -120,6 +137,12 @@ type StatsCtx struct {
 	// curr is the actual statistics collection result.
 	curr *unit
 
+	// blocked counts the blocked requests for the spike detection.
+	blocked *blockedCounter
+
+	// perf counts the DNS performance data for the DNS performance alerts.
+	perf *perfCounter
+
 	// db is the opened statistics database, if any.
 	db atomic.Pointer[bbolt.DB]
 
@@ -143,6 +166,10 @@ type StatsCtx struct {
 	// shouldCountClient returns client's ignore setting.
 	shouldCountClient func([]string) bool
 
+	// onStorageError, if not nil, is called when writing to the database
+	// fails or its contents are found to be corrupted.
+	onStorageError func(ctx context.Context, err error)
+
 	// filename is the name of database file.
 	filename string
 
@@ -170,6 +197,8 @@ func New(conf Config) (s *StatsCtx, err error) {
 	s = &StatsCtx{
 		logger:         conf.Logger,
 		currMu:         &sync.RWMutex{},
+		blocked:        newBlockedCounter(),
+		perf:           newPerfCounter(),
 		httpReg:        conf.HTTPReg,
 		configModifier: conf.ConfigModifier,
 		filename:       conf.Filename,
@@ -177,6 +206,7 @@ func New(conf Config) (s *StatsCtx, err error) {
 		confMu:            &sync.RWMutex{},
 		ignored:           conf.Ignored,
 		shouldCountClient: conf.ShouldCountClient,
+		onStorageError:    conf.OnStorageError,
 		limit:             conf.Limit,
 		enabled:           conf.Enabled,
 	}
@@ -303,6 +333,8 @@ func (s *StatsCtx) Update(e *Entry) {
 	}
 
 	s.curr.add(e)
+	s.blocked.add(e)
+	s.perf.add(e)
 }
 
 // WriteDiskConfig implements the [Interface] interface for *StatsCtx.
@@ -456,12 +488,14 @@ func (s *StatsCtx) flushDB(id, limit uint32, ptr *unit) (cont bool, sleepFor tim
 	tx, err := db.Begin(true)
 	if err != nil {
 		s.logger.Error("opening transaction", slogutil.KeyError, err)
+		s.reportStorageError(fmt.Errorf("opening transaction: %w", err))
 
 		return true, 0
 	}
 	defer func() {
 		if err = finishTxn(tx, isCommitable); err != nil {
 			s.logger.Error("finishing transaction", slogutil.KeyError, err)
+			s.reportStorageError(fmt.Errorf("finishing transaction: %w", err))
 		}
 	}()
 
@@ -471,6 +505,7 @@ func (s *StatsCtx) flushDB(id, limit uint32, ptr *unit) (cont bool, sleepFor tim
 	flushErr := s.flushUnitToDB(udb, tx, ptr.id)
 	if flushErr != nil {
 		s.logger.Error("flushing unit", slogutil.KeyError, flushErr)
+		s.reportStorageError(fmt.Errorf("flushing unit: %w", flushErr))
 		isCommitable = false
 	}
 
@@ -483,6 +518,7 @@ func (s *StatsCtx) flushDB(id, limit uint32, ptr *unit) (cont bool, sleepFor tim
 		if !errors.Is(delErr, bbolterrors.ErrBucketNotFound) {
 			isCommitable = false
 			lvl = slog.LevelError
+			s.reportStorageError(fmt.Errorf("deleting bucket: %w", delErr))
 		}
 
 		s.logger.Log(context.TODO(), lvl, "deleting bucket", slogutil.KeyError, delErr)
@@ -558,6 +594,7 @@ func (s *StatsCtx) clear() (err error) {
 	err = s.openDB()
 	if err != nil {
 		s.logger.Error("opening database", slogutil.KeyError, err)
+		s.reportStorageError(fmt.Errorf("opening database: %w", err))
 	}
 
 	// Use defer to unlock the mutex as soon as possible.
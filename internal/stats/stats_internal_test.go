@@ -2,6 +2,7 @@ package stats
 
 import (
 	"cmp"
+	"context"
 	"fmt"
 	"path/filepath"
 	"sync"
@@ -16,6 +17,7 @@ import (
 	"github.com/AdguardTeam/golibs/timeutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
 )
 
 // testLogger is the common logger for tests.
@@ -189,3 +191,36 @@ func TestStatsCtx_DataFromUnits_month(t *testing.T) {
 		require.NotNil(t, data)
 	}
 }
+
+func TestStatsCtx_loadUnitFromDB_corrupted(t *testing.T) {
+	const id uint32 = 1
+
+	var reported []error
+	s := newTestStatsCtx(t, Config{
+		UnitID:  func() (id uint32) { return 2 },
+		Enabled: true,
+		OnStorageError: func(_ context.Context, err error) {
+			reported = append(reported, err)
+		},
+	})
+	testutil.CleanupAndRequireSuccess(t, s.Close)
+
+	db := s.db.Load()
+	err := db.Update(func(tx *bbolt.Tx) (txErr error) {
+		bkt, txErr := tx.CreateBucketIfNotExists(idToUnitName(id))
+		require.NoError(t, txErr)
+
+		return bkt.Put([]byte{0}, []byte("not a unit"))
+	})
+	require.NoError(t, err)
+
+	err = db.View(func(tx *bbolt.Tx) (txErr error) {
+		assert.Nil(t, s.loadUnitFromDB(tx, id))
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, reported, 1)
+	assert.ErrorIs(t, reported[0], ErrCorrupted)
+}
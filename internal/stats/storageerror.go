@@ -0,0 +1,38 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AdguardTeam/golibs/errors"
+	bbolterrors "go.etcd.io/bbolt/errors"
+)
+
+// ErrCorrupted is wrapped into the errors passed to the storage error handler
+// when the database or its contents are found to be corrupted.
+const ErrCorrupted errors.Error = "statistics database is corrupted"
+
+// reportStorageError passes err to the storage error handler, if any.  The
+// errors caused by the database corruption are wrapped with [ErrCorrupted].
+// The handler is called with a background context, since the database is
+// written by the background flushes as well.
+func (s *StatsCtx) reportStorageError(err error) {
+	if s.onStorageError == nil {
+		return
+	}
+
+	if isCorruption(err) {
+		err = fmt.Errorf("%w: %w", ErrCorrupted, err)
+	}
+
+	s.onStorageError(context.Background(), err)
+}
+
+// isCorruption returns true if err is returned by the database because its
+// file is corrupted.
+func isCorruption(err error) (ok bool) {
+	return errors.Is(err, bbolterrors.ErrInvalid) ||
+		errors.Is(err, bbolterrors.ErrChecksum) ||
+		errors.Is(err, bbolterrors.ErrVersionMismatch) ||
+		errors.Is(err, bbolterrors.ErrInvalidMapping)
+}
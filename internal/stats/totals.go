@@ -1,5 +1,11 @@
 package stats
 
+import (
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/notifications"
+)
+
 // GetCurrentStats returns aggregate DNS query statistics over the configured
 // retention period.
 func (s *StatsCtx) GetCurrentStats() (numQueries, numBlocked, numSafeBrowsing, numParental uint64, avgProcessingTime float64) {
@@ -23,3 +29,71 @@ func (s *StatsCtx) GetCurrentStats() (numQueries, numBlocked, numSafeBrowsing, n
 		resp.NumReplacedParental,
 		resp.AvgProcessingTime
 }
+
+// type check
+var _ notifications.StatsSummaryProvider = (*StatsCtx)(nil)
+
+// StatsSummary implements the [notifications.StatsSummaryProvider] interface
+// for *StatsCtx.  The period is truncated to the retention period of the
+// statistics, and ok is false if the statistics are disabled.
+func (s *StatsCtx) StatsSummary(period time.Duration, limit int) (sum notifications.StatsSummary, ok bool) {
+	var resp *StatsResp
+	func() {
+		s.confMu.RLock()
+		defer s.confMu.RUnlock()
+
+		if !s.enabled {
+			return
+		}
+
+		period = min(period, s.limit)
+		resp, ok = s.getData(uint32(period.Hours()))
+	}()
+
+	if !ok || resp == nil {
+		return notifications.StatsSummary{}, false
+	}
+
+	sum = notifications.StatsSummary{
+		TopClients:        topEntries(resp.TopClients, limit),
+		TopBlocked:        topEntries(resp.TopBlocked, limit),
+		Period:            period,
+		AvgProcessingTime: secondsToDuration(resp.AvgProcessingTime),
+		NumQueries:        resp.NumDNSQueries,
+		NumBlocked: resp.NumBlockedFiltering +
+			resp.NumReplacedSafebrowsing +
+			resp.NumReplacedParental,
+	}
+
+	for _, m := range resp.TopUpstreamsAvgTime[:min(limit, len(resp.TopUpstreamsAvgTime))] {
+		for addr, avg := range m {
+			sum.Upstreams = append(sum.Upstreams, notifications.UpstreamLatency{
+				Address: addr,
+				AvgTime: secondsToDuration(avg),
+			})
+		}
+	}
+
+	return sum, true
+}
+
+// topEntries converts at most limit entries of the top list into the
+// notifications ones.
+func topEntries(top []topAddrs, limit int) (entries []notifications.StatsTopEntry) {
+	for _, m := range top[:min(limit, len(top))] {
+		for name, count := range m {
+			entries = append(entries, notifications.StatsTopEntry{
+				Name:  name,
+				Count: count,
+			})
+		}
+	}
+
+	return entries
+}
+
+// secondsToDuration converts the floating-point number of seconds into
+// a duration.
+func secondsToDuration(sec float64) (d time.Duration) {
+	return time.Duration(sec * float64(time.Second))
+}
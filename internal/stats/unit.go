@@ -88,6 +88,9 @@ type Entry struct {
 
 	// DNSSEC is true if the response had the AD (Authenticated Data) bit set.
 	DNSSEC bool
+
+	// ServFail is true if the response had the SERVFAIL response code.
+	ServFail bool
 }
 
 // validate returns an error if entry is not valid.
@@ -336,6 +339,7 @@ func (s *StatsCtx) loadUnitFromDB(tx *bbolt.Tx, id uint32) (udb *unitDB) {
 	err := gob.NewDecoder(&buf).Decode(udb)
 	if err != nil {
 		s.logger.Error("gob decode", slogutil.KeyError, err)
+		s.reportStorageError(fmt.Errorf("%w: decoding unit %d: %w", ErrCorrupted, id, err))
 
 		return nil
 	}
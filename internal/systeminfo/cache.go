@@ -0,0 +1,107 @@
+package systeminfo
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// probeTimeout is the maximum duration of a single probe of the system
+// metrics.
+const probeTimeout = 2 * time.Second
+
+// cacheTTL is the duration, during which the collected snapshot is returned to
+// the callers instead of collecting a new one.
+const cacheTTL = 2 * time.Second
+
+var (
+	// cacheMu protects cached and cachedAt.  It's held during the collection,
+	// so that the concurrent callers wait for a single collection instead of
+	// starting their own.
+	cacheMu sync.Mutex
+
+	// cached is the last collected snapshot.
+	cached Info
+
+	// cachedAt is the time when cached has been collected.
+	cachedAt time.Time
+)
+
+// Collect returns a snapshot of the host system metrics.  The snapshot is
+// cached for a short time, so the concurrent callers share a single
+// collection.  Each probe is limited by a timeout and by ctx.  In case of
+// errors, it falls back to zero values for the affected fields while still
+// returning any other available information.
+func Collect(ctx context.Context) (info Info) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	if !cachedAt.IsZero() && time.Since(cachedAt) < cacheTTL {
+		return cached.clone()
+	}
+
+	info = collect(ctx)
+
+	// Don't cache the snapshot, which probes have been interrupted by the
+	// canceled ctx.
+	if ctx.Err() == nil {
+		cached, cachedAt = info, time.Now()
+	}
+
+	return info.clone()
+}
+
+// clone returns a deep copy of info.
+func (info Info) clone() (c Info) {
+	c = info
+	c.LocalIPs = slices.Clone(info.LocalIPs)
+	c.AllDisks = slices.Clone(info.AllDisks)
+	c.Interfaces = slices.Clone(info.Interfaces)
+	for i, iface := range c.Interfaces {
+		c.Interfaces[i].Addrs = slices.Clone(iface.Addrs)
+	}
+
+	c.InterfaceChanges = slices.Clone(info.InterfaceChanges)
+	c.TopCPUProcesses = slices.Clone(info.TopCPUProcesses)
+	c.TopMemProcesses = slices.Clone(info.TopMemProcesses)
+
+	c.DiskHealth = slices.Clone(info.DiskHealth)
+	for i, h := range c.DiskHealth {
+		c.DiskHealth[i].Paths = slices.Clone(h.Paths)
+	}
+
+	if info.Power != nil {
+		p := *info.Power
+		c.Power = &p
+	}
+
+	return c
+}
+
+// probeResult is the result of a single probe.
+type probeResult[T any] struct {
+	val T
+	err error
+}
+
+// probe calls f with the probe timeout and returns its result.  Since some of
+// the probes ignore the context, f is called in a separate goroutine, which is
+// abandoned when the timeout is exceeded.
+func probe[T any](ctx context.Context, f func(ctx context.Context) (T, error)) (val T, err error) {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	resCh := make(chan probeResult[T], 1)
+	go func() {
+		v, fErr := f(ctx)
+		resCh <- probeResult[T]{val: v, err: fErr}
+	}()
+
+	select {
+	case res := <-resCh:
+		return res.val, res.err
+	case <-ctx.Done():
+		return val, ctx.Err()
+	}
+}
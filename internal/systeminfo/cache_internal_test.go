@@ -0,0 +1,53 @@
+package systeminfo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestProbe(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		got, err := probe(context.Background(), func(_ context.Context) (int, error) {
+			return 42, nil
+		})
+		if err != nil || got != 42 {
+			t.Errorf("got %d, %v, want 42, nil", got, err)
+		}
+	})
+
+	t.Run("hung", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		release := make(chan struct{})
+		defer close(release)
+
+		got, err := probe(ctx, func(_ context.Context) (int, error) {
+			<-release
+
+			return 42, nil
+		})
+		if !errors.Is(err, context.DeadlineExceeded) || got != 0 {
+			t.Errorf("got %d, %v, want 0, %v", got, err, context.DeadlineExceeded)
+		}
+	})
+}
+
+func TestInfo_clone(t *testing.T) {
+	info := Info{
+		LocalIPs:   []string{"192.168.1.2"},
+		AllDisks:   []DiskInfo{{Path: "/"}},
+		Interfaces: []InterfaceIO{{Name: "eth0"}},
+	}
+
+	c := info.clone()
+	c.LocalIPs[0] = ""
+	c.AllDisks[0].Path = ""
+	c.Interfaces[0].Name = ""
+
+	if info.LocalIPs[0] == "" || info.AllDisks[0].Path == "" || info.Interfaces[0].Name == "" {
+		t.Errorf("clone shares data with the original: %+v", info)
+	}
+}
@@ -0,0 +1,225 @@
+package systeminfo
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cgroupRoot is the mount point of the cgroup file system.  Inside a container
+// with its own cgroup namespace, it's the cgroup of the container.
+const cgroupRoot = "/sys/fs/cgroup"
+
+// cgroupLimits are the resource limits and usage of a cgroup.
+type cgroupLimits struct {
+	// memLimit is the memory limit in bytes.  Zero means no limit.
+	memLimit uint64
+
+	// memUsed is the memory usage in bytes excluding the inactive page cache,
+	// the same way "docker stats" computes it.
+	memUsed uint64
+
+	// cpuQuota is the CPU quota in CPUs.  Zero means no quota.
+	cpuQuota float64
+
+	// cpuTime is the cumulative CPU time used by the cgroup.
+	cpuTime time.Duration
+}
+
+// readCgroupLimits reads the limits of the cgroup mounted at root, either v2
+// or v1.
+func readCgroupLimits(root string) (l cgroupLimits, err error) {
+	if _, err = os.Stat(filepath.Join(root, "cgroup.controllers")); err == nil {
+		return readCgroupV2Limits(root)
+	}
+
+	return readCgroupV1Limits(root)
+}
+
+// readCgroupV2Limits reads the limits of the cgroup v2 mounted at root.
+func readCgroupV2Limits(root string) (l cgroupLimits, err error) {
+	limit, err := readCgroupValue(filepath.Join(root, "memory.max"))
+	if err != nil {
+		return l, err
+	}
+
+	l.memLimit = limit
+	if l.memLimit > 0 {
+		var used uint64
+		used, err = readCgroupValue(filepath.Join(root, "memory.current"))
+		if err != nil {
+			return l, err
+		}
+
+		inactive, _ := readCgroupStat(filepath.Join(root, "memory.stat"), "inactive_file")
+		l.memUsed = used - min(inactive, used)
+	}
+
+	// The format of cpu.max is "$MAX $PERIOD", where $MAX may be "max".
+	if data, rErr := os.ReadFile(filepath.Join(root, "cpu.max")); rErr == nil {
+		fields := strings.Fields(string(data))
+		if len(fields) == 2 && fields[0] != "max" {
+			l.cpuQuota = parseCPUQuota(fields[0], fields[1])
+		}
+	}
+
+	usec, err := readCgroupStat(filepath.Join(root, "cpu.stat"), "usage_usec")
+	if err == nil {
+		l.cpuTime = time.Duration(usec) * time.Microsecond
+	}
+
+	return l, nil
+}
+
+// readCgroupV1Limits reads the limits of the cgroups v1 mounted at root.
+func readCgroupV1Limits(root string) (l cgroupLimits, err error) {
+	memDir := filepath.Join(root, "memory")
+	limit, err := readCgroupValue(filepath.Join(memDir, "memory.limit_in_bytes"))
+	if err != nil {
+		return l, err
+	}
+
+	// The absence of the limit is reported as the maximum page-aligned int64.
+	if limit < math.MaxInt64&^0xfff {
+		l.memLimit = limit
+
+		var used uint64
+		used, err = readCgroupValue(filepath.Join(memDir, "memory.usage_in_bytes"))
+		if err != nil {
+			return l, err
+		}
+
+		inactive, _ := readCgroupStat(filepath.Join(memDir, "memory.stat"), "total_inactive_file")
+		l.memUsed = used - min(inactive, used)
+	}
+
+	cpuDir := filepath.Join(root, "cpu")
+	quota, qErr := os.ReadFile(filepath.Join(cpuDir, "cpu.cfs_quota_us"))
+	period, pErr := os.ReadFile(filepath.Join(cpuDir, "cpu.cfs_period_us"))
+	if qErr == nil && pErr == nil {
+		l.cpuQuota = parseCPUQuota(strings.TrimSpace(string(quota)), strings.TrimSpace(string(period)))
+	}
+
+	nsec, err := readCgroupValue(filepath.Join(root, "cpuacct", "cpuacct.usage"))
+	if err == nil {
+		l.cpuTime = time.Duration(nsec)
+	}
+
+	return l, nil
+}
+
+// readCgroupValue reads a single number from the cgroup file at path.  "max"
+// is returned as zero.
+func readCgroupValue(path string) (v uint64, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	s := strings.TrimSpace(string(data))
+	if s == "max" {
+		return 0, nil
+	}
+
+	v, err = strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing %q: %w", path, err)
+	}
+
+	return v, nil
+}
+
+// readCgroupStat returns the value of key from the flat keyed cgroup file at
+// path, like memory.stat.
+func readCgroupStat(path, key string) (v uint64, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		k, val, ok := strings.Cut(s.Text(), " ")
+		if ok && k == key {
+			return strconv.ParseUint(strings.TrimSpace(val), 10, 64)
+		}
+	}
+
+	if err = s.Err(); err != nil {
+		return 0, err
+	}
+
+	return 0, fmt.Errorf("no %q in %q", key, path)
+}
+
+// parseCPUQuota returns the quota in CPUs from the quota and period in
+// microseconds.  It returns zero if there is no quota.
+func parseCPUQuota(quota, period string) (cpus float64) {
+	q, err := strconv.ParseInt(quota, 10, 64)
+	if err != nil || q <= 0 {
+		return 0
+	}
+
+	p, err := strconv.ParseInt(period, 10, 64)
+	if err != nil || p <= 0 {
+		return 0
+	}
+
+	return float64(q) / float64(p)
+}
+
+// cgroupCPUSample is the CPU time of the cgroup at a point in time.
+type cgroupCPUSample struct {
+	time    time.Time
+	cpuTime time.Duration
+}
+
+var (
+	// lastCgroupCPUMu protects lastCgroupCPU.
+	lastCgroupCPUMu sync.Mutex
+
+	// lastCgroupCPU is the sample of the previous collection.
+	lastCgroupCPU cgroupCPUSample
+)
+
+// applyCgroupLimits makes the memory and CPU usage of info relative to the
+// limits of the container, if they are lower than the resources of the host.
+func applyCgroupLimits(info *Info, l cgroupLimits, now time.Time) {
+	if l.memLimit > 0 && l.memLimit < info.MemoryTotal {
+		info.ContainerLimited = true
+		info.MemoryTotal = l.memLimit
+		info.MemoryUsed = min(l.memUsed, l.memLimit)
+		info.MemoryFree = l.memLimit - info.MemoryUsed
+		info.MemoryUsage = float64(info.MemoryUsed) / float64(l.memLimit) * 100
+	}
+
+	if l.cpuQuota <= 0 || l.cpuQuota >= float64(info.NumCPU) {
+		return
+	}
+
+	info.ContainerLimited = true
+	info.CPUQuota = l.cpuQuota
+
+	lastCgroupCPUMu.Lock()
+	prev := lastCgroupCPU
+	lastCgroupCPU = cgroupCPUSample{time: now, cpuTime: l.cpuTime}
+	lastCgroupCPUMu.Unlock()
+
+	elapsed := now.Sub(prev.time)
+	if prev.time.IsZero() || elapsed <= 0 || l.cpuTime < prev.cpuTime {
+		// Scale the usage of the host on the first collection.
+		info.CPUUsage = min(info.CPUUsage*float64(info.NumCPU)/l.cpuQuota, 100)
+
+		return
+	}
+
+	used := float64(l.cpuTime-prev.cpuTime) / float64(elapsed)
+	info.CPUUsage = min(used/l.cpuQuota*100, 100)
+}
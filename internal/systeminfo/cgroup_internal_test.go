@@ -0,0 +1,141 @@
+package systeminfo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeFiles creates the files with the contents from files under root.
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+
+	for name, data := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadCgroupLimits(t *testing.T) {
+	testCases := []struct {
+		files map[string]string
+		name  string
+		want  cgroupLimits
+	}{{
+		files: map[string]string{
+			"cgroup.controllers": "cpu memory\n",
+			"memory.max":         "536870912\n",
+			"memory.current":     "209715200\n",
+			"memory.stat":        "anon 100\ninactive_file 10485760\n",
+			"cpu.max":            "150000 100000\n",
+			"cpu.stat":           "usage_usec 2000000\nuser_usec 1000000\n",
+		},
+		name: "v2",
+		want: cgroupLimits{
+			memLimit: 512 << 20,
+			memUsed:  190 << 20,
+			cpuQuota: 1.5,
+			cpuTime:  2 * time.Second,
+		},
+	}, {
+		files: map[string]string{
+			"cgroup.controllers": "cpu memory\n",
+			"memory.max":         "max\n",
+			"cpu.max":            "max 100000\n",
+		},
+		name: "v2_unlimited",
+		want: cgroupLimits{},
+	}, {
+		files: map[string]string{
+			"memory/memory.limit_in_bytes": "1073741824\n",
+			"memory/memory.usage_in_bytes": "104857600\n",
+			"memory/memory.stat":           "total_inactive_file 0\n",
+			"cpu/cpu.cfs_quota_us":         "50000\n",
+			"cpu/cpu.cfs_period_us":        "100000\n",
+			"cpuacct/cpuacct.usage":        "3000000000\n",
+		},
+		name: "v1",
+		want: cgroupLimits{
+			memLimit: 1 << 30,
+			memUsed:  100 << 20,
+			cpuQuota: 0.5,
+			cpuTime:  3 * time.Second,
+		},
+	}, {
+		files: map[string]string{
+			"memory/memory.limit_in_bytes": "9223372036854771712\n",
+			"cpu/cpu.cfs_quota_us":         "-1\n",
+			"cpu/cpu.cfs_period_us":        "100000\n",
+		},
+		name: "v1_unlimited",
+		want: cgroupLimits{},
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeFiles(t, root, tc.files)
+
+			got, err := readCgroupLimits(root)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tc.want {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestApplyCgroupLimits(t *testing.T) {
+	lastCgroupCPU = cgroupCPUSample{}
+	t.Cleanup(func() { lastCgroupCPU = cgroupCPUSample{} })
+
+	info := Info{
+		NumCPU:      8,
+		CPUUsage:    10,
+		MemoryTotal: 64 << 30,
+		MemoryUsed:  32 << 30,
+		MemoryUsage: 50,
+	}
+
+	now := time.Now()
+	l := cgroupLimits{
+		memLimit: 1 << 30,
+		memUsed:  800 << 20,
+		cpuQuota: 2,
+		cpuTime:  10 * time.Second,
+	}
+
+	applyCgroupLimits(&info, l, now)
+
+	if !info.ContainerLimited || info.MemoryTotal != 1<<30 || info.MemoryFree != 224<<20 {
+		t.Errorf("memory is not limited: %+v", info)
+	}
+
+	// The host usage of 10% of 8 CPUs is 40% of the quota of 2 CPUs.
+	if info.CPUUsage != 40 || info.CPUQuota != 2 {
+		t.Errorf("got cpu usage %v, quota %v, want 40, 2", info.CPUUsage, info.CPUQuota)
+	}
+
+	// One CPU second per second is a half of the quota.
+	l.cpuTime += 2 * time.Second
+	applyCgroupLimits(&info, l, now.Add(2*time.Second))
+	if info.CPUUsage != 50 {
+		t.Errorf("got cpu usage %v, want 50", info.CPUUsage)
+	}
+
+	host := Info{NumCPU: 2, CPUUsage: 10, MemoryTotal: 1 << 30}
+	applyCgroupLimits(&host, cgroupLimits{memLimit: 2 << 30, cpuQuota: 4}, now)
+	if host.ContainerLimited || host.CPUUsage != 10 {
+		t.Errorf("limits above the host resources are applied: %+v", host)
+	}
+}
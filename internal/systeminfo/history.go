@@ -0,0 +1,109 @@
+package systeminfo
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Sample is a point of the history of the system metrics.  The network
+// counters are cumulative, the clients compute the rates.
+type Sample struct {
+	Time         time.Time `json:"time"`
+	CPUUsage     float64   `json:"cpu_usage"`
+	CPUTemp      float64   `json:"cpu_temp"`
+	MemoryUsage  float64   `json:"memory_usage"`
+	SwapUsage    float64   `json:"swap_usage"`
+	DiskUsage    float64   `json:"disk_usage"`
+	LoadAvg1     float64   `json:"load_avg_1"`
+	NetBytesSent uint64    `json:"net_bytes_sent"`
+	NetBytesRecv uint64    `json:"net_bytes_recv"`
+}
+
+// newSample returns the sample of info taken at now.
+func newSample(info Info, now time.Time) (s Sample) {
+	return Sample{
+		Time:         now,
+		CPUUsage:     info.CPUUsage,
+		CPUTemp:      info.CPUTemp,
+		MemoryUsage:  info.MemoryUsage,
+		SwapUsage:    info.SwapUsage,
+		DiskUsage:    info.DiskUsage,
+		LoadAvg1:     info.LoadAvg1,
+		NetBytesSent: info.NetBytesSent,
+		NetBytesRecv: info.NetBytesRecv,
+	}
+}
+
+// History is an in-memory ring buffer of the samples of the system metrics.
+// It's safe for concurrent use.
+type History struct {
+	// mu protects samples and next.
+	mu *sync.Mutex
+
+	// samples are the stored samples.  Its length grows up to its capacity,
+	// after which the oldest samples are overwritten.
+	samples []Sample
+
+	// next is the index of samples, at which the next sample is stored once
+	// samples is full.
+	next int
+}
+
+// NewHistory returns a new history, which keeps at most size of the latest
+// samples.  size must be positive.
+func NewHistory(size int) (h *History) {
+	return &History{
+		mu:      &sync.Mutex{},
+		samples: make([]Sample, 0, size),
+	}
+}
+
+// Add stores s, overwriting the oldest sample if the history is full.
+func (h *History) Add(s Sample) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.samples) < cap(h.samples) {
+		h.samples = append(h.samples, s)
+
+		return
+	}
+
+	h.samples[h.next] = s
+	h.next = (h.next + 1) % len(h.samples)
+}
+
+// Samples returns the stored samples taken after since, oldest first.
+func (h *History) Samples(since time.Time) (samples []Sample) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	samples = make([]Sample, 0, len(h.samples))
+	for _, part := range [][]Sample{h.samples[h.next:], h.samples[:h.next]} {
+		for _, s := range part {
+			if s.Time.After(since) {
+				samples = append(samples, s)
+			}
+		}
+	}
+
+	return samples
+}
+
+// Run collects a sample every interval until ctx is canceled.  interval must
+// be positive.
+func (h *History) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		h.Add(newSample(Collect(ctx), time.Now()))
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
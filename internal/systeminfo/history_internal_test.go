@@ -0,0 +1,30 @@
+package systeminfo
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	start := time.Now()
+	sampleAt := func(i int) (s Sample) {
+		return Sample{Time: start.Add(time.Duration(i) * time.Minute), CPUUsage: float64(i)}
+	}
+
+	h := NewHistory(3)
+	for i := range 5 {
+		h.Add(sampleAt(i))
+	}
+
+	// The two oldest samples are overwritten.
+	want := []Sample{sampleAt(2), sampleAt(3), sampleAt(4)}
+	if got := h.Samples(time.Time{}); !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	want = []Sample{sampleAt(4)}
+	if got := h.Samples(sampleAt(3).Time); !slices.Equal(got, want) {
+		t.Errorf("got %v since the fourth sample, want %v", got, want)
+	}
+}
@@ -0,0 +1,135 @@
+package systeminfo
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// InterfaceChangeType is the type of a change of a network interface.
+type InterfaceChangeType string
+
+// InterfaceChangeType values.
+const (
+	InterfaceLinkUp      InterfaceChangeType = "link_up"
+	InterfaceLinkDown    InterfaceChangeType = "link_down"
+	InterfaceAddrAdded   InterfaceChangeType = "address_added"
+	InterfaceAddrRemoved InterfaceChangeType = "address_removed"
+)
+
+// InterfaceChange is a change of the link state or of the addresses of a
+// network interface.
+type InterfaceChange struct {
+	// Time is the time, when the change has been detected.
+	Time time.Time `json:"time"`
+
+	// Type is the type of the change.
+	Type InterfaceChangeType `json:"type"`
+
+	// Interface is the name of the interface, like "eth0".
+	Interface string `json:"interface"`
+
+	// Addr is the added or the removed address.  It's empty for the link
+	// changes.
+	Addr string `json:"addr,omitempty"`
+}
+
+// maxInterfaceChanges is the maximum number of the latest interface changes
+// reported in [Info.InterfaceChanges].
+const maxInterfaceChanges = 50
+
+// ifaceState is the state of a network interface.
+type ifaceState struct {
+	// addrs are the sorted addresses of the interface.
+	addrs []string
+
+	// up is true if the interface is up and has a carrier.
+	up bool
+}
+
+var (
+	// ifaceMu protects ifaceStates and ifaceChanges.
+	ifaceMu sync.Mutex
+
+	// ifaceStates are the states of the interfaces from the last scan by
+	// name.  It's nil before the first scan.
+	ifaceStates map[string]ifaceState
+
+	// ifaceChanges are the latest changes of the interfaces, oldest first.
+	ifaceChanges []InterfaceChange
+)
+
+// collectInterfaceChanges records the changes of the network interfaces of
+// info since the previous collection and sets the latest changes of info.
+// The first collection only records the states.
+func collectInterfaceChanges(info *Info) {
+	// Don't report all the interfaces as removed, if their probe has failed.
+	if len(info.Interfaces) == 0 {
+		return
+	}
+
+	cur := make(map[string]ifaceState, len(info.Interfaces))
+	for _, iface := range info.Interfaces {
+		if !iface.Loopback {
+			cur[iface.Name] = ifaceState{addrs: iface.Addrs, up: iface.Up}
+		}
+	}
+
+	ifaceMu.Lock()
+	defer ifaceMu.Unlock()
+
+	if ifaceStates != nil {
+		ifaceChanges = append(ifaceChanges, diffInterfaces(ifaceStates, cur, time.Now())...)
+		if n := len(ifaceChanges) - maxInterfaceChanges; n > 0 {
+			ifaceChanges = slices.Delete(ifaceChanges, 0, n)
+		}
+	}
+
+	ifaceStates = cur
+	info.InterfaceChanges = slices.Clone(ifaceChanges)
+}
+
+// diffInterfaces returns the changes from prev to cur detected at now, sorted
+// by the name of the interface.  The interfaces missing from one of the maps
+// are considered down and having no addresses.
+func diffInterfaces(prev, cur map[string]ifaceState, now time.Time) (changes []InterfaceChange) {
+	names := slices.Collect(maps.Keys(cur))
+	for name := range prev {
+		if _, ok := cur[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		p, c := prev[name], cur[name]
+		change := InterfaceChange{Time: now, Interface: name}
+
+		if p.up != c.up {
+			change.Type = InterfaceLinkDown
+			if c.up {
+				change.Type = InterfaceLinkUp
+			}
+
+			changes = append(changes, change)
+		}
+
+		for _, addr := range p.addrs {
+			if _, ok := slices.BinarySearch(c.addrs, addr); !ok {
+				change.Type, change.Addr = InterfaceAddrRemoved, addr
+				changes = append(changes, change)
+			}
+		}
+
+		for _, addr := range c.addrs {
+			if _, ok := slices.BinarySearch(p.addrs, addr); !ok {
+				change.Type, change.Addr = InterfaceAddrAdded, addr
+				changes = append(changes, change)
+			}
+		}
+	}
+
+	return changes
+}
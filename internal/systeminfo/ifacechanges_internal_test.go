@@ -0,0 +1,74 @@
+package systeminfo
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDiffInterfaces(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		prev map[string]ifaceState
+		cur  map[string]ifaceState
+		name string
+		want []InterfaceChange
+	}{{
+		prev: map[string]ifaceState{"eth0": {up: true, addrs: []string{"192.168.1.2"}}},
+		cur:  map[string]ifaceState{"eth0": {up: true, addrs: []string{"192.168.1.2"}}},
+		name: "same",
+		want: nil,
+	}, {
+		prev: map[string]ifaceState{"eth0": {up: true, addrs: []string{"192.168.1.2"}}},
+		cur:  map[string]ifaceState{"eth0": {up: false}},
+		name: "link_down",
+		want: []InterfaceChange{{
+			Time:      now,
+			Type:      InterfaceLinkDown,
+			Interface: "eth0",
+		}, {
+			Time:      now,
+			Type:      InterfaceAddrRemoved,
+			Interface: "eth0",
+			Addr:      "192.168.1.2",
+		}},
+	}, {
+		prev: map[string]ifaceState{"eth0": {up: true, addrs: []string{"192.168.1.2"}}},
+		cur:  map[string]ifaceState{"eth0": {up: true, addrs: []string{"192.168.1.3"}}},
+		name: "address_changed",
+		want: []InterfaceChange{{
+			Time:      now,
+			Type:      InterfaceAddrRemoved,
+			Interface: "eth0",
+			Addr:      "192.168.1.2",
+		}, {
+			Time:      now,
+			Type:      InterfaceAddrAdded,
+			Interface: "eth0",
+			Addr:      "192.168.1.3",
+		}},
+	}, {
+		prev: map[string]ifaceState{"wg0": {up: true}},
+		cur:  map[string]ifaceState{"eth0": {up: true}},
+		name: "replaced",
+		want: []InterfaceChange{{
+			Time:      now,
+			Type:      InterfaceLinkUp,
+			Interface: "eth0",
+		}, {
+			Time:      now,
+			Type:      InterfaceLinkDown,
+			Interface: "wg0",
+		}},
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := diffInterfaces(tc.prev, tc.cur, now)
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package systeminfo
+
+import (
+	"context"
+	"net"
+	"slices"
+
+	gopsNet "github.com/shirou/gopsutil/v4/net"
+)
+
+// InterfaceIO describes the cumulative I/O counters of a single network
+// interface.
+type InterfaceIO struct {
+	Name      string `json:"name"`
+	BytesSent uint64 `json:"bytes_sent"`
+	BytesRecv uint64 `json:"bytes_recv"`
+	Loopback  bool   `json:"loopback"`
+
+	// Up is true if the interface is up and has a carrier.
+	Up bool `json:"up"`
+
+	// Addrs are the sorted addresses of the interface, excluding the
+	// link-local ones.
+	Addrs []string `json:"addrs,omitempty"`
+}
+
+// collectInterfaces returns the I/O counters of each network interface.
+func collectInterfaces(ctx context.Context) []InterfaceIO {
+	counters, err := probe(ctx, func(ctx context.Context) ([]gopsNet.IOCountersStat, error) {
+		return gopsNet.IOCountersWithContext(ctx, true)
+	})
+	if err != nil {
+		return nil
+	}
+
+	ifaces := make(map[string]net.Interface)
+	if list, ifErr := net.Interfaces(); ifErr == nil {
+		for _, iface := range list {
+			ifaces[iface.Name] = iface
+		}
+	}
+
+	res := make([]InterfaceIO, 0, len(counters))
+	for _, c := range counters {
+		iface, ok := ifaces[c.Name]
+
+		var addrs []string
+		if ok {
+			addrs = interfaceAddrs(iface)
+		}
+
+		res = append(res, InterfaceIO{
+			Name:      c.Name,
+			BytesSent: c.BytesSent,
+			BytesRecv: c.BytesRecv,
+			Loopback:  iface.Flags&net.FlagLoopback != 0,
+			Up:        iface.Flags&net.FlagUp != 0 && iface.Flags&net.FlagRunning != 0,
+			Addrs:     addrs,
+		})
+	}
+
+	return res
+}
+
+// interfaceAddrs returns the sorted addresses of iface, excluding the
+// link-local ones.
+func interfaceAddrs(iface net.Interface) (addrs []string) {
+	// Treat the errors as if the interface had no addresses.
+	ifaceAddrs, _ := iface.Addrs()
+	for _, addr := range ifaceAddrs {
+		ip, isIP := addrToIP(addr)
+		if isIP && !ip.IsLinkLocalUnicast() {
+			addrs = append(addrs, ip.String())
+		}
+	}
+
+	slices.Sort(addrs)
+
+	return addrs
+}
@@ -2,11 +2,15 @@
 
 package systeminfo
 
-import "github.com/shirou/gopsutil/v4/load"
+import (
+	"context"
+
+	"github.com/shirou/gopsutil/v4/load"
+)
 
 // collectLoadAvg returns 1, 5, and 15 minute load averages on Unix systems.
-func collectLoadAvg() (float64, float64, float64) {
-	avg, err := load.Avg()
+func collectLoadAvg(ctx context.Context) (float64, float64, float64) {
+	avg, err := probe(ctx, load.AvgWithContext)
 	if err != nil || avg == nil {
 		return 0, 0, 0
 	}
@@ -2,7 +2,9 @@
 
 package systeminfo
 
+import "context"
+
 // collectLoadAvg returns zeros on Windows as load average is not available.
-func collectLoadAvg() (float64, float64, float64) {
+func collectLoadAvg(_ context.Context) (float64, float64, float64) {
 	return 0, 0, 0
 }
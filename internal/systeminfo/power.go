@@ -0,0 +1,241 @@
+package systeminfo
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The sources of [PowerInfo].
+const (
+	PowerSourceNUT     = "nut"
+	PowerSourceBattery = "battery"
+)
+
+// PowerInfo is the state of the UPS or the battery powering the host.
+type PowerInfo struct {
+	// Source is either [PowerSourceNUT] or [PowerSourceBattery].
+	Source string `json:"source"`
+
+	// Name is the name of the UPS or of the battery, like "BAT0".
+	Name string `json:"name"`
+
+	// Charge is the battery charge in percent.
+	Charge float64 `json:"charge"`
+
+	// RuntimeSeconds is the estimated remaining runtime on battery.  Zero
+	// means that it's not reported.
+	RuntimeSeconds int64 `json:"runtime_seconds"`
+
+	// OnBattery is true if the host is powered by the battery.
+	OnBattery bool `json:"on_battery"`
+
+	// LowBattery is true if the battery is low.
+	LowBattery bool `json:"low_battery"`
+}
+
+// PowerConfig is the configuration of the power probe.
+type PowerConfig struct {
+	// NUTAddress is the address of the NUT (Network UPS Tools) server, like
+	// "127.0.0.1:3493".  If it's empty, NUT isn't queried.
+	NUTAddress string
+
+	// NUTUPS is the name of the UPS on the NUT server.
+	NUTUPS string
+
+	// Battery enables the probe of the battery of the host, if NUT isn't
+	// used.  Only Linux is currently supported.
+	Battery bool
+}
+
+// nutDefaultPort is the default port of the NUT server.
+const nutDefaultPort = "3493"
+
+var (
+	// powerMu protects powerConf.
+	powerMu sync.Mutex
+
+	// powerConf is the current configuration of the power probe.
+	powerConf PowerConfig
+)
+
+// SetPowerConfig sets the configuration of the power probe reported in
+// [Info.Power].  conf must not be nil.
+func SetPowerConfig(conf *PowerConfig) {
+	powerMu.Lock()
+	powerConf = *conf
+	powerMu.Unlock()
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	cachedAt = time.Time{}
+}
+
+// collectPower sets the power state of info, if enabled.
+func collectPower(ctx context.Context, info *Info) {
+	powerMu.Lock()
+	conf := powerConf
+	powerMu.Unlock()
+
+	var p *PowerInfo
+	var err error
+	if conf.NUTAddress != "" {
+		p, err = probe(ctx, func(ctx context.Context) (p *PowerInfo, err error) {
+			return queryNUT(ctx, conf.NUTAddress, conf.NUTUPS)
+		})
+	} else if conf.Battery {
+		p, err = readBattery(powerSupplyRoot)
+	}
+
+	if err == nil {
+		info.Power = p
+	}
+}
+
+// queryNUT returns the state of the UPS from the NUT server at addr.
+func queryNUT(ctx context.Context, addr, ups string) (p *PowerInfo, err error) {
+	if _, _, err = net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, nutDefaultPort)
+	}
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to nut: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	_, err = fmt.Fprintf(conn, "LIST VAR %s\n", ups)
+	if err != nil {
+		return nil, fmt.Errorf("writing to nut: %w", err)
+	}
+
+	vars, err := readNUTVars(bufio.NewScanner(conn), ups)
+	if err != nil {
+		return nil, err
+	}
+
+	return newNUTPowerInfo(ups, vars), nil
+}
+
+// readNUTVars reads the response of the NUT server to the LIST VAR command.
+func readNUTVars(sc *bufio.Scanner, ups string) (vars map[string]string, err error) {
+	vars = map[string]string{}
+	end := "END LIST VAR " + ups
+	for sc.Scan() {
+		line := sc.Text()
+		if line == end {
+			return vars, nil
+		} else if msg, ok := strings.CutPrefix(line, "ERR "); ok {
+			return nil, fmt.Errorf("nut: %s", msg)
+		}
+
+		// The format is `VAR <ups> <name> "<value>"`.
+		rest, ok := strings.CutPrefix(line, "VAR "+ups+" ")
+		if !ok {
+			continue
+		}
+
+		name, val, ok := strings.Cut(rest, " ")
+		if ok {
+			vars[name] = strings.Trim(val, `"`)
+		}
+	}
+
+	if err = sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading from nut: %w", err)
+	}
+
+	return nil, fmt.Errorf("nut: unexpected end of response")
+}
+
+// newNUTPowerInfo returns the power state from the NUT variables of the UPS.
+func newNUTPowerInfo(ups string, vars map[string]string) (p *PowerInfo) {
+	p = &PowerInfo{
+		Source: PowerSourceNUT,
+		Name:   ups,
+	}
+
+	p.Charge, _ = strconv.ParseFloat(vars["battery.charge"], 64)
+	p.RuntimeSeconds, _ = strconv.ParseInt(vars["battery.runtime"], 10, 64)
+
+	// The status is a space-separated list of flags, like "OB LB".
+	for _, flag := range strings.Fields(vars["ups.status"]) {
+		switch flag {
+		case "OB":
+			p.OnBattery = true
+		case "LB":
+			p.LowBattery = true
+		}
+	}
+
+	return p
+}
+
+// powerSupplyRoot is the directory of the power supplies in the sysfs of
+// Linux.
+const powerSupplyRoot = "/sys/class/power_supply"
+
+// lowBatteryCharge is the charge of the battery in percent, below which it's
+// considered low.
+const lowBatteryCharge = 10
+
+// readBattery returns the state of the first battery from the power supplies
+// under root.  It returns nil if there is no battery.
+func readBattery(root string) (p *PowerInfo, err error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var mains, mainsOnline bool
+	for _, e := range entries {
+		dir := filepath.Join(root, e.Name())
+		switch readSysfsString(filepath.Join(dir, "type")) {
+		case "Mains":
+			mains = true
+			mainsOnline = mainsOnline || readSysfsString(filepath.Join(dir, "online")) == "1"
+		case "Battery":
+			// Skip the batteries of the peripherals, like the wireless mice.
+			if p != nil || readSysfsString(filepath.Join(dir, "scope")) == "Device" {
+				continue
+			}
+
+			p = &PowerInfo{
+				Source:    PowerSourceBattery,
+				Name:      e.Name(),
+				OnBattery: readSysfsString(filepath.Join(dir, "status")) == "Discharging",
+			}
+			p.Charge, _ = strconv.ParseFloat(readSysfsString(filepath.Join(dir, "capacity")), 64)
+			p.LowBattery = p.Charge > 0 && p.Charge < lowBatteryCharge
+		}
+	}
+
+	if p != nil && mains && !mainsOnline {
+		p.OnBattery = true
+	}
+
+	return p, nil
+}
+
+// readSysfsString returns the trimmed contents of the sysfs file at path or an
+// empty string, if it can't be read.
+func readSysfsString(path string) (s string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(data))
+}
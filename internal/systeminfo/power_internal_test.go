@@ -0,0 +1,105 @@
+package systeminfo
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQueryNUT(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+
+	go func() {
+		conn, aErr := l.Accept()
+		if aErr != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		if line != "LIST VAR ups\n" {
+			_, _ = conn.Write([]byte("ERR UNKNOWN-COMMAND\n"))
+
+			return
+		}
+
+		_, _ = conn.Write([]byte(strings.Join([]string{
+			"BEGIN LIST VAR ups",
+			`VAR ups battery.charge "87"`,
+			`VAR ups battery.runtime "1260"`,
+			`VAR ups ups.status "OB DISCHRG"`,
+			"END LIST VAR ups",
+			"",
+		}, "\n")))
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	p, err := queryNUT(ctx, l.Addr().String(), "ups")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := PowerInfo{
+		Source:         PowerSourceNUT,
+		Name:           "ups",
+		Charge:         87,
+		RuntimeSeconds: 1260,
+		OnBattery:      true,
+	}
+	if *p != want {
+		t.Errorf("got %+v, want %+v", *p, want)
+	}
+}
+
+func TestReadNUTVars_error(t *testing.T) {
+	sc := bufio.NewScanner(strings.NewReader("ERR UNKNOWN-UPS\n"))
+	if _, err := readNUTVars(sc, "ups"); err == nil {
+		t.Error("got no error for the unknown ups")
+	}
+}
+
+func TestReadBattery(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"AC/type":         "Mains\n",
+		"AC/online":       "0\n",
+		"BAT0/type":       "Battery\n",
+		"BAT0/status":     "Unknown\n",
+		"BAT0/capacity":   "7\n",
+		"A_mouse/type":    "Battery\n",
+		"A_mouse/scope":   "Device\n",
+		"A_mouse/status":  "Discharging\n",
+		"ucsi-source/foo": "",
+	})
+
+	p, err := readBattery(root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := PowerInfo{
+		Source:     PowerSourceBattery,
+		Name:       "BAT0",
+		Charge:     7,
+		OnBattery:  true,
+		LowBattery: true,
+	}
+	if p == nil || *p != want {
+		t.Errorf("got %+v, want %+v", p, want)
+	}
+
+	p, err = readBattery(filepath.Join(root, "AC"))
+	if err != nil || p != nil {
+		t.Errorf("got %+v, %v without batteries, want nil, nil", p, err)
+	}
+}
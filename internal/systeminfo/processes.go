@@ -0,0 +1,172 @@
+package systeminfo
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// ProcessUsage is the resource usage of a single process.
+type ProcessUsage struct {
+	Name string `json:"name"`
+
+	// CPUPercent is the CPU usage since the previous collection in percent of
+	// a single CPU, so it may exceed 100 on multi-core hosts.
+	CPUPercent float64 `json:"cpu_percent"`
+
+	// MemBytes is the resident set size.
+	MemBytes uint64 `json:"mem_bytes"`
+
+	PID int32 `json:"pid"`
+}
+
+var (
+	// topProcsMu protects topProcsNum, prevProcTimes, and prevProcsAt.
+	topProcsMu sync.Mutex
+
+	// topProcsNum is the number of the top processes reported in [Info].
+	// Zero disables the probe.
+	topProcsNum int
+
+	// prevProcTimes are the cumulative CPU times of the processes in seconds
+	// by PID at the previous collection.
+	prevProcTimes map[int32]float64
+
+	// prevProcsAt is the time of the previous collection.
+	prevProcsAt time.Time
+)
+
+// SetTopProcesses sets the number of the processes with the highest CPU and
+// memory usage reported in [Info.TopCPUProcesses] and [Info.TopMemProcesses].
+// Zero disables the probe, which is the default, since it has to inspect each
+// process of the host.
+func SetTopProcesses(n int) {
+	topProcsMu.Lock()
+	topProcsNum = n
+	prevProcTimes, prevProcsAt = nil, time.Time{}
+	topProcsMu.Unlock()
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	cachedAt = time.Time{}
+}
+
+// topProcesses is the result of the top processes probe.
+type topProcesses struct {
+	byCPU []ProcessUsage
+	byMem []ProcessUsage
+}
+
+// collectTopProcesses sets the top processes of info, if enabled.
+func collectTopProcesses(ctx context.Context, info *Info) {
+	topProcsMu.Lock()
+	n := topProcsNum
+	topProcsMu.Unlock()
+
+	if n <= 0 {
+		return
+	}
+
+	top, err := probe(ctx, func(ctx context.Context) (top topProcesses, err error) {
+		return scanProcesses(ctx, n)
+	})
+	if err != nil {
+		return
+	}
+
+	info.TopCPUProcesses, info.TopMemProcesses = top.byCPU, top.byMem
+}
+
+// scanProcesses inspects each process of the host and returns n of them with
+// the highest CPU and memory usage.  The processes, which can't be inspected,
+// like the exited ones, are skipped.
+func scanProcesses(ctx context.Context, n int) (top topProcesses, err error) {
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return top, err
+	}
+
+	now := time.Now()
+	usages := make([]ProcessUsage, 0, len(procs))
+	times := make(map[int32]float64, len(procs))
+	byPID := make(map[int32]*process.Process, len(procs))
+	for _, p := range procs {
+		if ctx.Err() != nil {
+			return top, ctx.Err()
+		}
+
+		t, tErr := p.TimesWithContext(ctx)
+		if tErr != nil {
+			continue
+		}
+
+		u := ProcessUsage{PID: p.Pid}
+		if m, mErr := p.MemoryInfoWithContext(ctx); mErr == nil && m != nil {
+			u.MemBytes = m.RSS
+		}
+
+		times[p.Pid] = t.User + t.System
+		byPID[p.Pid] = p
+		usages = append(usages, u)
+	}
+
+	topProcsMu.Lock()
+	prev, prevAt := prevProcTimes, prevProcsAt
+	prevProcTimes, prevProcsAt = times, now
+	topProcsMu.Unlock()
+
+	setCPUPercents(usages, times, prev, now.Sub(prevAt))
+	top = rankProcesses(usages, n)
+
+	for _, list := range [][]ProcessUsage{top.byCPU, top.byMem} {
+		for i := range list {
+			list[i].Name, _ = byPID[list[i].PID].NameWithContext(ctx)
+		}
+	}
+
+	return top, nil
+}
+
+// setCPUPercents sets the CPU usage of usages from the difference between
+// their cumulative CPU times and the ones collected elapsed ago.  The usage is
+// left zero if there is no previous time, like on the first collection.
+func setCPUPercents(usages []ProcessUsage, times, prev map[int32]float64, elapsed time.Duration) {
+	if prev == nil || elapsed <= 0 {
+		return
+	}
+
+	for i, u := range usages {
+		prevTime, ok := prev[u.PID]
+		if !ok || times[u.PID] < prevTime {
+			continue
+		}
+
+		usages[i].CPUPercent = (times[u.PID] - prevTime) / elapsed.Seconds() * 100
+	}
+}
+
+// rankProcesses returns n usages with the highest CPU and memory usage.  The
+// idle processes aren't included into the CPU list.
+func rankProcesses(usages []ProcessUsage, n int) (top topProcesses) {
+	byCPU := slices.DeleteFunc(slices.Clone(usages), func(u ProcessUsage) bool {
+		return u.CPUPercent <= 0
+	})
+	slices.SortFunc(byCPU, func(a, b ProcessUsage) int {
+		return cmp.Compare(b.CPUPercent, a.CPUPercent)
+	})
+
+	byMem := slices.Clone(usages)
+	slices.SortFunc(byMem, func(a, b ProcessUsage) int {
+		return cmp.Compare(b.MemBytes, a.MemBytes)
+	})
+
+	return topProcesses{
+		byCPU: slices.Clip(byCPU[:min(n, len(byCPU))]),
+		byMem: slices.Clip(byMem[:min(n, len(byMem))]),
+	}
+}
@@ -0,0 +1,45 @@
+package systeminfo
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRankProcesses(t *testing.T) {
+	usages := []ProcessUsage{
+		{PID: 1, MemBytes: 10},
+		{PID: 2, MemBytes: 30},
+		{PID: 3, MemBytes: 20},
+	}
+
+	times := map[int32]float64{1: 11, 2: 20, 3: 30.5}
+	prev := map[int32]float64{1: 10, 2: 20, 3: 30}
+	setCPUPercents(usages, times, prev, 2*time.Second)
+
+	top := rankProcesses(usages, 2)
+
+	// The idle process 2 isn't in the CPU list.
+	wantCPU := []ProcessUsage{
+		{PID: 1, MemBytes: 10, CPUPercent: 50},
+		{PID: 3, MemBytes: 20, CPUPercent: 25},
+	}
+	if !slices.Equal(top.byCPU, wantCPU) {
+		t.Errorf("got by cpu %v, want %v", top.byCPU, wantCPU)
+	}
+
+	wantMem := []int32{2, 3}
+	gotMem := []int32{top.byMem[0].PID, top.byMem[1].PID}
+	if !slices.Equal(gotMem, wantMem) {
+		t.Errorf("got by memory %v, want %v", gotMem, wantMem)
+	}
+}
+
+func TestSetCPUPercents_first(t *testing.T) {
+	usages := []ProcessUsage{{PID: 1}}
+	setCPUPercents(usages, map[int32]float64{1: 10}, nil, 0)
+
+	if usages[0].CPUPercent != 0 {
+		t.Errorf("got %v without previous times, want 0", usages[0].CPUPercent)
+	}
+}
@@ -0,0 +1,227 @@
+package systeminfo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The special values of the public IP address lookup providers.
+const (
+	// PublicIPProviderIpify is the name of the built-in ipify provider.
+	PublicIPProviderIpify = "ipify"
+
+	// PublicIPDisabled turns the public IP address lookup off.
+	PublicIPDisabled = "disabled"
+)
+
+const (
+	publicIPPrimaryURL   = "https://api64.ipify.org?format=text"
+	publicIPSecondaryURL = "https://api.ipify.org?format=text"
+	publicIPCacheTTL     = 30 * time.Minute
+	publicIPReqTimeout   = 2 * time.Second
+)
+
+// PublicIPConfig is the configuration of the public IP address lookup.
+type PublicIPConfig struct {
+	// Client is used to send the lookup requests, so that it's the one to
+	// respect the proxy settings.  [http.DefaultClient] is used if it's nil.
+	Client *http.Client
+
+	// Providers are the services tried in order.  Each is either
+	// [PublicIPProviderIpify] or the URL returning the address as plain text.
+	// The single [PublicIPDisabled] turns the lookup off.  The ipify provider
+	// is used if it's empty.  See [ValidatePublicIPProviders].
+	Providers []string
+}
+
+var (
+	// publicIPMu protects publicIPClient, publicIPURLs, publicIPValue,
+	// publicIPFetched, publicIPPrevious, and publicIPChangedAt.
+	publicIPMu      sync.RWMutex
+	publicIPClient  = http.DefaultClient
+	publicIPURLs    = []string{publicIPPrimaryURL, publicIPSecondaryURL}
+	publicIPValue   string
+	publicIPFetched time.Time
+
+	// publicIPPrevious is the address looked up before the last change of
+	// the public IP address, if any.
+	publicIPPrevious string
+
+	// publicIPChangedAt is the time of the last change of the public IP
+	// address.  It's zero if the address hasn't changed since the start.
+	publicIPChangedAt time.Time
+)
+
+// publicIPChange is the last change of the public IP address.
+type publicIPChange struct {
+	// previous is the address before the change.
+	previous string
+
+	// at is the time of the change.  It's zero if there has been no change.
+	at time.Time
+}
+
+// lastPublicIPChange returns the last change of the public IP address.
+func lastPublicIPChange() (c publicIPChange) {
+	publicIPMu.RLock()
+	defer publicIPMu.RUnlock()
+
+	return publicIPChange{previous: publicIPPrevious, at: publicIPChangedAt}
+}
+
+// ValidatePublicIPProviders returns an error if providers are invalid.
+func ValidatePublicIPProviders(providers []string) (err error) {
+	if slices.Contains(providers, PublicIPDisabled) {
+		if len(providers) > 1 {
+			return fmt.Errorf("%q must be the only provider", PublicIPDisabled)
+		}
+
+		return nil
+	}
+
+	for i, p := range providers {
+		if p == PublicIPProviderIpify {
+			continue
+		}
+
+		u, uErr := url.Parse(p)
+		if uErr != nil {
+			return fmt.Errorf("provider at index %d: %w", i, uErr)
+		} else if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("provider at index %d: %q is not an http or https url", i, p)
+		}
+	}
+
+	return nil
+}
+
+// SetPublicIPConfig applies the public IP address lookup configuration and
+// drops the previously looked up address.  conf must not be nil.
+func SetPublicIPConfig(conf *PublicIPConfig) (err error) {
+	err = ValidatePublicIPProviders(conf.Providers)
+	if err != nil {
+		return fmt.Errorf("public ip providers: %w", err)
+	}
+
+	client := conf.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	publicIPMu.Lock()
+	publicIPClient = client
+	publicIPURLs = providerURLs(conf.Providers)
+	publicIPValue, publicIPFetched = "", time.Time{}
+	publicIPPrevious, publicIPChangedAt = "", time.Time{}
+	publicIPMu.Unlock()
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	cachedAt = time.Time{}
+
+	return nil
+}
+
+// providerURLs returns the lookup URLs of the valid providers.
+func providerURLs(providers []string) (urls []string) {
+	if len(providers) == 0 {
+		providers = []string{PublicIPProviderIpify}
+	}
+
+	for _, p := range providers {
+		switch p {
+		case PublicIPDisabled:
+			return nil
+		case PublicIPProviderIpify:
+			urls = append(urls, publicIPPrimaryURL, publicIPSecondaryURL)
+		default:
+			urls = append(urls, p)
+		}
+	}
+
+	return urls
+}
+
+// lookupPublicIP returns the public IP address of the host from the first
+// provider responding with one.  The address is cached.  It returns an empty
+// string if the lookup is disabled.  The changes of the address are recorded.
+func lookupPublicIP(ctx context.Context) string {
+	publicIPMu.RLock()
+	client, urls := publicIPClient, publicIPURLs
+	val := publicIPValue
+	fresh := time.Since(publicIPFetched) < publicIPCacheTTL
+	publicIPMu.RUnlock()
+
+	if fresh && val != "" {
+		return val
+	}
+
+	ip := ""
+	for _, u := range urls {
+		ip = fetchPublicIP(ctx, client, u)
+		if ip != "" {
+			break
+		}
+	}
+
+	if ip == "" {
+		return val
+	}
+
+	now := time.Now()
+
+	publicIPMu.Lock()
+	if publicIPValue != "" && publicIPValue != ip {
+		publicIPPrevious, publicIPChangedAt = publicIPValue, now
+	}
+	publicIPValue = ip
+	publicIPFetched = now
+	publicIPMu.Unlock()
+
+	return ip
+}
+
+func fetchPublicIP(ctx context.Context, client *http.Client, url string) string {
+	ctx, cancel := context.WithTimeout(ctx, publicIPReqTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return ""
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ""
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 128))
+	if err != nil {
+		return ""
+	}
+
+	text := strings.TrimSpace(string(body))
+	if text == "" {
+		return ""
+	}
+
+	if _, err = netip.ParseAddr(text); err != nil {
+		return ""
+	}
+
+	return text
+}
@@ -0,0 +1,130 @@
+package systeminfo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestValidatePublicIPProviders(t *testing.T) {
+	testCases := []struct {
+		name      string
+		providers []string
+		wantErr   bool
+	}{{
+		name:      "empty",
+		providers: nil,
+		wantErr:   false,
+	}, {
+		name:      "disabled",
+		providers: []string{PublicIPDisabled},
+		wantErr:   false,
+	}, {
+		name:      "custom",
+		providers: []string{"https://ip.example.org/plain", PublicIPProviderIpify},
+		wantErr:   false,
+	}, {
+		name:      "disabled_with_others",
+		providers: []string{PublicIPDisabled, PublicIPProviderIpify},
+		wantErr:   true,
+	}, {
+		name:      "bad_scheme",
+		providers: []string{"ftp://ip.example.org"},
+		wantErr:   true,
+	}, {
+		name:      "unknown_name",
+		providers: []string{"ifconfig"},
+		wantErr:   true,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidatePublicIPProviders(tc.providers)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("got error %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestLookupPublicIP(t *testing.T) {
+	t.Cleanup(func() {
+		_ = SetPublicIPConfig(&PublicIPConfig{})
+	})
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(failing.Close)
+
+	working := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("203.0.113.1\n"))
+	}))
+	t.Cleanup(working.Close)
+
+	ctx := context.Background()
+
+	err := SetPublicIPConfig(&PublicIPConfig{
+		Providers: []string{failing.URL, working.URL},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := lookupPublicIP(ctx); got != "203.0.113.1" {
+		t.Errorf("got %q, want %q", got, "203.0.113.1")
+	}
+
+	err = SetPublicIPConfig(&PublicIPConfig{
+		Providers: []string{PublicIPDisabled},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := lookupPublicIP(ctx); got != "" {
+		t.Errorf("got %q with the lookup disabled", got)
+	}
+}
+
+func TestLookupPublicIP_change(t *testing.T) {
+	t.Cleanup(func() {
+		_ = SetPublicIPConfig(&PublicIPConfig{})
+	})
+
+	ips := make(chan string, 2)
+	ips <- "203.0.113.1"
+	ips <- "203.0.113.2"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(<-ips))
+	}))
+	t.Cleanup(srv.Close)
+
+	err := SetPublicIPConfig(&PublicIPConfig{Providers: []string{srv.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	_ = lookupPublicIP(ctx)
+	if c := lastPublicIPChange(); !c.at.IsZero() {
+		t.Errorf("got change %+v after the first lookup", c)
+	}
+
+	// Expire the cached address.
+	publicIPMu.Lock()
+	publicIPFetched = time.Time{}
+	publicIPMu.Unlock()
+
+	if got := lookupPublicIP(ctx); got != "203.0.113.2" {
+		t.Errorf("got %q, want %q", got, "203.0.113.2")
+	}
+
+	c := lastPublicIPChange()
+	if c.previous != "203.0.113.1" || c.at.IsZero() {
+		t.Errorf("got change %+v, want from %q", c, "203.0.113.1")
+	}
+}
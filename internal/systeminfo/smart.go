@@ -0,0 +1,314 @@
+package systeminfo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AdguardTeam/golibs/osutil/executil"
+	"github.com/shirou/gopsutil/v4/disk"
+)
+
+// DiskHealth is the SMART health status of a drive.
+type DiskHealth struct {
+	// Device is the path of the device, like "/dev/sda".
+	Device string `json:"device"`
+
+	Model string `json:"model"`
+
+	// Paths are the watched paths, which are on the drive, like the directory
+	// of the query log.
+	Paths []string `json:"paths,omitempty"`
+
+	// Temperature is the current temperature in degrees Celsius.  Zero means
+	// that it's not reported.
+	Temperature float64 `json:"temperature"`
+
+	// ReallocatedSectors is the raw value of the reallocated sectors count,
+	// which is only reported by the ATA drives.  -1 means that it's not
+	// reported.
+	ReallocatedSectors int64 `json:"reallocated_sectors"`
+
+	// Passed is true if the overall health self-assessment has passed.
+	Passed bool `json:"passed"`
+}
+
+// Degraded returns true if the drive has failed the health self-assessment or
+// has reallocated sectors.
+func (h DiskHealth) Degraded() (ok bool) {
+	return !h.Passed || h.ReallocatedSectors > 0
+}
+
+// SMARTConfig is the configuration of the SMART health probe.
+type SMARTConfig struct {
+	// CmdCons is used to run smartctl from smartmontools.  If it's nil, the
+	// probe is disabled.
+	CmdCons executil.CommandConstructor
+
+	// WatchPaths are the paths, which drives are reported in
+	// [DiskHealth.Paths].
+	WatchPaths []string
+}
+
+const (
+	// smartctlCmd is the command of smartmontools that reads the SMART data.
+	smartctlCmd = "smartctl"
+
+	// smartRefreshInterval is the interval between the runs of smartctl,
+	// since it may be slow and wake up the sleeping drives.
+	smartRefreshInterval = 10 * time.Minute
+
+	// smartTimeout is the maximum duration of a single refresh.
+	smartTimeout = time.Minute
+
+	// smartAttrReallocated is the ID of the Reallocated_Sector_Ct ATA SMART
+	// attribute.
+	smartAttrReallocated = 5
+)
+
+var (
+	// smartMu protects smartConf, smartGen, smartHealth, smartAt, and
+	// smartRunning.
+	smartMu sync.Mutex
+
+	// smartConf is the current configuration of the probe.
+	smartConf SMARTConfig
+
+	// smartGen is incremented on each change of smartConf, so that the results
+	// of the refreshes started before it are discarded.
+	smartGen uint64
+
+	// smartHealth are the results of the last refresh.
+	smartHealth []DiskHealth
+
+	// smartAt is the time of the last refresh.
+	smartAt time.Time
+
+	// smartRunning is true while a refresh is in progress.
+	smartRunning bool
+)
+
+// SetSMARTConfig sets the configuration of the SMART health probe reported in
+// [Info.DiskHealth].  conf must not be nil.
+func SetSMARTConfig(conf *SMARTConfig) {
+	smartMu.Lock()
+	defer smartMu.Unlock()
+
+	smartConf = SMARTConfig{
+		CmdCons:    conf.CmdCons,
+		WatchPaths: slices.Clone(conf.WatchPaths),
+	}
+	smartGen++
+	smartHealth, smartAt = nil, time.Time{}
+}
+
+// collectDiskHealth sets the SMART health of the drives of info from the last
+// refresh and starts a new one in the background, if the results are stale.
+func collectDiskHealth(info *Info) {
+	smartMu.Lock()
+	defer smartMu.Unlock()
+
+	if smartConf.CmdCons == nil {
+		return
+	}
+
+	info.DiskHealth = slices.Clone(smartHealth)
+	if smartRunning || time.Since(smartAt) < smartRefreshInterval {
+		return
+	}
+
+	smartRunning = true
+	go refreshDiskHealth(smartConf, smartGen)
+}
+
+// refreshDiskHealth runs smartctl for each drive and stores the results, if
+// gen is still the current generation of the configuration.  It's intended to
+// be used as a goroutine.
+func refreshDiskHealth(conf SMARTConfig, gen uint64) {
+	ctx, cancel := context.WithTimeout(context.Background(), smartTimeout)
+	defer cancel()
+
+	health := scanDiskHealth(ctx, conf)
+
+	smartMu.Lock()
+	defer smartMu.Unlock()
+
+	smartRunning = false
+	if gen != smartGen {
+		return
+	}
+
+	smartHealth, smartAt = health, time.Now()
+}
+
+// scanDiskHealth returns the SMART health of the drives found by smartctl.
+// The drives, which can't be opened, are skipped.
+func scanDiskHealth(ctx context.Context, conf SMARTConfig) (health []DiskHealth) {
+	out, err := runSmartctl(ctx, conf.CmdCons, "--scan", "--json")
+	if err != nil {
+		return nil
+	}
+
+	var scan struct {
+		Devices []struct {
+			Name string `json:"name"`
+			Type string `json:"type"`
+		} `json:"devices"`
+	}
+	if err = json.Unmarshal(out, &scan); err != nil {
+		return nil
+	}
+
+	parts, _ := disk.PartitionsWithContext(ctx, true)
+	for _, d := range scan.Devices {
+		out, err = runSmartctl(
+			ctx,
+			conf.CmdCons,
+			"--json",
+			"--info",
+			"--health",
+			"--attributes",
+			"--device",
+			d.Type,
+			d.Name,
+		)
+		if err != nil {
+			continue
+		}
+
+		h, pErr := parseSmartctlOutput(d.Name, out)
+		if pErr != nil {
+			continue
+		}
+
+		for _, p := range conf.WatchPaths {
+			if dev := partitionDevice(parts, p); dev != "" && deviceHolds(d.Name, dev) {
+				h.Paths = append(h.Paths, p)
+			}
+		}
+
+		health = append(health, h)
+	}
+
+	return health
+}
+
+// runSmartctl runs smartctl with args and returns its output.  The exit status
+// of smartctl is a bit mask, in which only the two lowest bits mean that
+// there's no valid output.
+func runSmartctl(
+	ctx context.Context,
+	cmdCons executil.CommandConstructor,
+	args ...string,
+) (out []byte, err error) {
+	var stdout bytes.Buffer
+	err = executil.Run(ctx, cmdCons, &executil.CommandConfig{
+		Path:   smartctlCmd,
+		Args:   args,
+		Stdout: &stdout,
+	})
+	if err != nil {
+		code, ok := executil.ExitCodeFromError(err)
+		if !ok || code&0b11 != 0 {
+			return nil, fmt.Errorf("running %s: %w", smartctlCmd, err)
+		}
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// parseSmartctlOutput parses the JSON output of smartctl about the device.
+func parseSmartctlOutput(device string, out []byte) (h DiskHealth, err error) {
+	var data struct {
+		SMARTStatus *struct {
+			Passed bool `json:"passed"`
+		} `json:"smart_status"`
+		ModelName   string `json:"model_name"`
+		Temperature struct {
+			Current float64 `json:"current"`
+		} `json:"temperature"`
+		ATAAttributes struct {
+			Table []struct {
+				Raw struct {
+					Value int64 `json:"value"`
+				} `json:"raw"`
+				ID int `json:"id"`
+			} `json:"table"`
+		} `json:"ata_smart_attributes"`
+	}
+	if err = json.Unmarshal(out, &data); err != nil {
+		return h, fmt.Errorf("parsing %s output: %w", smartctlCmd, err)
+	} else if data.SMARTStatus == nil {
+		return h, fmt.Errorf("no health status of %q", device)
+	}
+
+	h = DiskHealth{
+		Device:             device,
+		Model:              data.ModelName,
+		Temperature:        data.Temperature.Current,
+		ReallocatedSectors: -1,
+		Passed:             data.SMARTStatus.Passed,
+	}
+
+	for _, attr := range data.ATAAttributes.Table {
+		if attr.ID == smartAttrReallocated {
+			h.ReallocatedSectors = attr.Raw.Value
+		}
+	}
+
+	return h, nil
+}
+
+// partitionDevice returns the device of the partition from parts mounted at
+// the longest prefix of path.
+func partitionDevice(parts []disk.PartitionStat, path string) (dev string) {
+	path = filepath.Clean(path)
+
+	longest := -1
+	for _, p := range parts {
+		mp := filepath.Clean(p.Mountpoint)
+		if len(mp) <= longest || !isWithin(path, mp) {
+			continue
+		}
+
+		longest, dev = len(mp), p.Device
+	}
+
+	return dev
+}
+
+// isWithin returns true if path is dir or is within it.
+func isWithin(path, dir string) (ok bool) {
+	rel, err := filepath.Rel(dir, path)
+
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// driveName matches the name of the partition device and captures the name of
+// its drive, like "sda" in "sda1" and "nvme0n1" in "nvme0n1p1".
+var driveName = regexp.MustCompile(`^(nvme\d+n\d+|mmcblk\d+|(?:s|v|xv|h)d[a-z]+)(?:p?\d+)?$`)
+
+// nvmeNamespace matches the namespace of the NVMe drive, like "n1" in
+// "nvme0n1", since smartctl reports the controllers, like "nvme0".
+var nvmeNamespace = regexp.MustCompile(`^(nvme\d+)n\d+$`)
+
+// deviceHolds returns true if the partition device partDev is on the drive
+// device reported by smartctl.  The devices of the other kinds, like the
+// device mapper ones, are only compared as is.
+func deviceHolds(device, partDev string) (ok bool) {
+	drive := filepath.Base(partDev)
+	if m := driveName.FindStringSubmatch(drive); m != nil {
+		drive = m[1]
+	}
+
+	name := filepath.Base(device)
+
+	return drive == name || nvmeNamespace.ReplaceAllString(drive, "$1") == name
+}
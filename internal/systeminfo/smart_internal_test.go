@@ -0,0 +1,137 @@
+package systeminfo
+
+import (
+	"testing"
+
+	"github.com/shirou/gopsutil/v4/disk"
+)
+
+func TestParseSmartctlOutput(t *testing.T) {
+	testCases := []struct {
+		name    string
+		out     string
+		want    DiskHealth
+		wantErr bool
+	}{{
+		name: "ata",
+		out: `{
+			"model_name": "WDC WD40EFRX",
+			"smart_status": {"passed": true},
+			"temperature": {"current": 38},
+			"ata_smart_attributes": {"table": [
+				{"id": 1, "raw": {"value": 0}},
+				{"id": 5, "raw": {"value": 8}}
+			]}
+		}`,
+		want: DiskHealth{
+			Device:             "/dev/sda",
+			Model:              "WDC WD40EFRX",
+			Temperature:        38,
+			ReallocatedSectors: 8,
+			Passed:             true,
+		},
+		wantErr: false,
+	}, {
+		name: "nvme",
+		out:  `{"model_name": "Samsung SSD 980", "smart_status": {"passed": false}}`,
+		want: DiskHealth{
+			Device:             "/dev/sda",
+			Model:              "Samsung SSD 980",
+			ReallocatedSectors: -1,
+			Passed:             false,
+		},
+		wantErr: false,
+	}, {
+		name:    "no_status",
+		out:     `{"model_name": "USB Flash"}`,
+		want:    DiskHealth{},
+		wantErr: true,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseSmartctlOutput("/dev/sda", []byte(tc.out))
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("got error %v, want error: %t", err, tc.wantErr)
+			}
+
+			if got.Device != tc.want.Device ||
+				got.Model != tc.want.Model ||
+				got.Temperature != tc.want.Temperature ||
+				got.ReallocatedSectors != tc.want.ReallocatedSectors ||
+				got.Passed != tc.want.Passed {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDeviceHolds(t *testing.T) {
+	testCases := []struct {
+		device  string
+		partDev string
+		want    bool
+	}{{
+		device:  "/dev/sda",
+		partDev: "/dev/sda1",
+		want:    true,
+	}, {
+		device:  "/dev/sda",
+		partDev: "/dev/sdb1",
+		want:    false,
+	}, {
+		device:  "/dev/nvme0",
+		partDev: "/dev/nvme0n1p2",
+		want:    true,
+	}, {
+		device:  "/dev/nvme1",
+		partDev: "/dev/nvme0n1p2",
+		want:    false,
+	}, {
+		device:  "/dev/mmcblk0",
+		partDev: "/dev/mmcblk0p2",
+		want:    true,
+	}, {
+		device:  "/dev/sda",
+		partDev: "/dev/mapper/vg-root",
+		want:    false,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.partDev, func(t *testing.T) {
+			if got := deviceHolds(tc.device, tc.partDev); got != tc.want {
+				t.Errorf("deviceHolds(%q, %q) = %t, want %t", tc.device, tc.partDev, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPartitionDevice(t *testing.T) {
+	parts := []disk.PartitionStat{
+		{Device: "/dev/sda1", Mountpoint: "/"},
+		{Device: "/dev/sdb1", Mountpoint: "/var"},
+		{Device: "/dev/sdc1", Mountpoint: "/var/lib/adguard"},
+	}
+
+	testCases := []struct {
+		path string
+		want string
+	}{{
+		path: "/var/lib/adguard/data",
+		want: "/dev/sdc1",
+	}, {
+		path: "/var/lib/adguardhome",
+		want: "/dev/sdb1",
+	}, {
+		path: "/opt",
+		want: "/dev/sda1",
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			if got := partitionDevice(parts, tc.path); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -1,13 +1,14 @@
 package systeminfo
 
 import (
+	"context"
 	"fmt"
-	"io"
+	"io/fs"
 	"net"
-	"net/http"
 	"net/netip"
 	"os"
 	"runtime"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -41,6 +42,7 @@ type Info struct {
 	NumCPU        int      `json:"num_cpu"`
 	CPUModel      string   `json:"cpu_model"`
 	CPUUsage      float64  `json:"cpu_usage"`
+	CPUTemp       float64  `json:"cpu_temp"`
 	MemoryTotal   uint64   `json:"memory_total"`
 	MemoryUsed    uint64   `json:"memory_used"`
 	MemoryUsage   float64  `json:"memory_usage"`
@@ -54,6 +56,11 @@ type Info struct {
 	PublicIP      string   `json:"public_ip"`
 	UptimeSeconds uint64   `json:"uptime_seconds"`
 
+	// The last change of the public IP address (RFC 3339), if it has changed
+	// since the start.
+	PreviousPublicIP  string `json:"previous_public_ip,omitempty"`
+	PublicIPChangedAt string `json:"public_ip_changed_at,omitempty"`
+
 	// Swap memory.
 	SwapTotal uint64  `json:"swap_total"`
 	SwapUsed  uint64  `json:"swap_used"`
@@ -69,6 +76,13 @@ type Info struct {
 	IsContainer bool   `json:"is_container"`
 	HostOS      string `json:"host_os,omitempty"`
 
+	// Container resource limits.  If ContainerLimited is true, the memory
+	// and CPU usage are relative to the cgroup limits of the container
+	// instead of the resources of the host.  CPUQuota is in CPUs, zero means
+	// no quota.
+	ContainerLimited bool    `json:"container_limited"`
+	CPUQuota         float64 `json:"cpu_quota"`
+
 	// Current server time (RFC 3339).
 	SystemTime string `json:"system_time"`
 
@@ -96,12 +110,30 @@ type Info struct {
 	NetDropsIn     uint64 `json:"net_drops_in"`
 	ActiveConns    int    `json:"active_conns"`
 
+	// Raw network I/O counters by interface (cumulative totals, Manager
+	// computes rates).
+	Interfaces []InterfaceIO `json:"interfaces"`
+
+	// Latest changes of the link state and of the addresses of the network
+	// interfaces, oldest first.
+	InterfaceChanges []InterfaceChange `json:"interface_changes,omitempty"`
+
 	// Process info (self and total).
 	TotalProcesses int     `json:"total_processes"`
 	SelfCPUPercent float64 `json:"self_cpu_percent"`
 	SelfMemBytes   uint64  `json:"self_mem_bytes"`
 	SelfOpenFiles  int32   `json:"self_open_files"`
 	SelfThreads    int32   `json:"self_threads"`
+
+	// Top processes, if enabled with [SetTopProcesses].
+	TopCPUProcesses []ProcessUsage `json:"top_cpu_processes,omitempty"`
+	TopMemProcesses []ProcessUsage `json:"top_mem_processes,omitempty"`
+
+	// SMART health of the drives, if enabled with [SetSMARTConfig].
+	DiskHealth []DiskHealth `json:"disk_health,omitempty"`
+
+	// UPS or battery state, if enabled with [SetPowerConfig].
+	Power *PowerInfo `json:"power,omitempty"`
 }
 
 // skipFS contains pseudo-filesystem types that should always be excluded from
@@ -117,6 +149,19 @@ var skipFS = map[string]bool{
 	"cgroup":      true,
 	"cgroup2":     true,
 	"fuse.lxcfs":  true,
+	"devpts":      true,
+	"mqueue":      true,
+	"debugfs":     true,
+	"tracefs":     true,
+	"securityfs":  true,
+	"pstore":      true,
+	"bpf":         true,
+	"efivarfs":    true,
+	"autofs":      true,
+	"configfs":    true,
+	"hugetlbfs":   true,
+	"ramfs":       true,
+	"binfmt_misc": true,
 }
 
 // containerFS contains filesystem types used by container runtimes (overlay,
@@ -127,10 +172,8 @@ var containerFS = map[string]bool{
 	"aufs":    true,
 }
 
-// Collect returns a snapshot of the host system metrics.  In case of errors,
-// it falls back to zero values for the affected fields while still returning
-// any other available information.
-func Collect() Info {
+// collect returns a snapshot of the host system metrics collected with ctx.
+func collect(ctx context.Context) Info {
 	info := Info{
 		OS:     runtime.GOOS,
 		Arch:   runtime.GOARCH,
@@ -138,7 +181,7 @@ func Collect() Info {
 	}
 
 	containerOS := ""
-	if hi, err := host.Info(); err == nil {
+	if hi, err := probe(ctx, host.InfoWithContext); err == nil {
 		info.Hostname = hi.Hostname
 		info.UptimeSeconds = hi.Uptime
 		if hi.PlatformVersion != "" {
@@ -164,32 +207,34 @@ func Collect() Info {
 	info.SystemTime = time.Now().Format(time.RFC3339)
 
 	// Kernel version.
-	if kv, err := host.KernelVersion(); err == nil {
+	if kv, err := probe(ctx, host.KernelVersionWithContext); err == nil {
 		info.KernelVersion = kv
 	}
 
 	// Boot time.
-	if bt, err := host.BootTime(); err == nil {
+	if bt, err := probe(ctx, host.BootTimeWithContext); err == nil {
 		info.BootTime = bt
 	}
 
 	// Virtualization platform.
-	if virt, _, err := host.Virtualization(); err == nil && virt != "" {
+	if virt, err := probe(ctx, virtualization); err == nil && virt != "" {
 		info.VirtPlatform = virt
 	}
 
-	if cpuInfos, err := cpu.Info(); err == nil && len(cpuInfos) > 0 {
+	if cpuInfos, err := probe(ctx, cpu.InfoWithContext); err == nil && len(cpuInfos) > 0 {
 		info.CPUModel = cpuInfos[0].ModelName
 		if info.CPUModel == "" {
 			info.CPUModel = fmt.Sprintf("CPU %d", cpuInfos[0].CPU)
 		}
 	}
 
-	if usages, err := cpu.Percent(0, false); err == nil && len(usages) > 0 {
+	if usages, err := probe(ctx, cpuPercent); err == nil && len(usages) > 0 {
 		info.CPUUsage = usages[0]
 	}
 
-	if vm, err := mem.VirtualMemory(); err == nil {
+	info.CPUTemp = collectCPUTemperature(ctx)
+
+	if vm, err := probe(ctx, mem.VirtualMemoryWithContext); err == nil {
 		info.MemoryTotal = vm.Total
 		info.MemoryUsed = vm.Used
 		info.MemoryUsage = vm.UsedPercent
@@ -201,30 +246,36 @@ func Collect() Info {
 		}
 	}
 
+	if info.IsContainer {
+		if l, err := readCgroupLimits(cgroupRoot); err == nil {
+			applyCgroupLimits(&info, l, time.Now())
+		}
+	}
+
 	// Swap memory.
-	if sw, err := mem.SwapMemory(); err == nil {
+	if sw, err := probe(ctx, mem.SwapMemoryWithContext); err == nil {
 		info.SwapTotal = sw.Total
 		info.SwapUsed = sw.Used
 		info.SwapFree = sw.Free
 		info.SwapUsage = sw.UsedPercent
 	}
 
-	if du, err := disk.Usage(rootPath()); err == nil {
+	if du, err := primaryDiskUsage(ctx); err == nil {
 		info.DiskPath = du.Path
 		info.DiskTotal = du.Total
 		info.DiskUsed = du.Used
-		info.DiskUsage = du.UsedPercent
+		info.DiskUsage = du.UsagePercent
 		info.DiskFree = du.Free
 	}
 
 	// All disk partitions.
-	info.AllDisks = collectAllDisks()
+	info.AllDisks = collectAllDisks(ctx)
 
 	// Load average (platform-specific).
-	info.LoadAvg1, info.LoadAvg5, info.LoadAvg15 = collectLoadAvg()
+	info.LoadAvg1, info.LoadAvg5, info.LoadAvg15 = collectLoadAvg(ctx)
 
 	// Disk I/O counters (cumulative).
-	if counters, err := disk.IOCounters(); err == nil {
+	if counters, err := probe(ctx, diskIOCounters); err == nil {
 		for _, c := range counters {
 			info.DiskReadBytes += c.ReadBytes
 			info.DiskWriteBytes += c.WriteBytes
@@ -234,7 +285,7 @@ func Collect() Info {
 	}
 
 	// Network I/O counters (cumulative, aggregated across all interfaces).
-	if netCounters, err := gopsNet.IOCounters(false); err == nil && len(netCounters) > 0 {
+	if netCounters, err := probe(ctx, allNetIOCounters); err == nil && len(netCounters) > 0 {
 		c := netCounters[0]
 		info.NetBytesSent = c.BytesSent
 		info.NetBytesRecv = c.BytesRecv
@@ -245,23 +296,61 @@ func Collect() Info {
 		info.NetDropsIn = c.Dropin
 	}
 
+	info.Interfaces = collectInterfaces(ctx)
+	collectInterfaceChanges(&info)
+
 	// Active TCP connections.
-	if conns, err := gopsNet.Connections("tcp"); err == nil {
+	if conns, err := probe(ctx, tcpConnections); err == nil {
 		info.ActiveConns = len(conns)
 	}
 
 	// Process info.
-	collectProcessInfo(&info)
+	collectProcessInfo(ctx, &info)
+	collectTopProcesses(ctx, &info)
+	collectDiskHealth(&info)
+	collectPower(ctx, &info)
 
 	info.LocalIPs = collectLocalIPs()
-	info.PublicIP = lookupPublicIP()
+	info.PublicIP = lookupPublicIP(ctx)
+	if c := lastPublicIPChange(); !c.at.IsZero() {
+		info.PreviousPublicIP = c.previous
+		info.PublicIPChangedAt = c.at.Format(time.RFC3339)
+	}
 
 	return info
 }
 
+// virtualization returns the virtualization system of the host.
+func virtualization(ctx context.Context) (virt string, err error) {
+	virt, _, err = host.VirtualizationWithContext(ctx)
+
+	return virt, err
+}
+
+// cpuPercent returns the total CPU usage since the previous call.
+func cpuPercent(ctx context.Context) (usages []float64, err error) {
+	return cpu.PercentWithContext(ctx, 0, false)
+}
+
+// diskIOCounters returns the I/O counters of all disks.
+func diskIOCounters(ctx context.Context) (counters map[string]disk.IOCountersStat, err error) {
+	return disk.IOCountersWithContext(ctx)
+}
+
+// allNetIOCounters returns the I/O counters aggregated across all network
+// interfaces.
+func allNetIOCounters(ctx context.Context) (counters []gopsNet.IOCountersStat, err error) {
+	return gopsNet.IOCountersWithContext(ctx, false)
+}
+
+// tcpConnections returns the TCP connections of the host.
+func tcpConnections(ctx context.Context) (conns []gopsNet.ConnectionStat, err error) {
+	return gopsNet.ConnectionsWithContext(ctx, "tcp")
+}
+
 // collectAllDisks enumerates physical disk partitions and returns usage info.
-func collectAllDisks() []DiskInfo {
-	parts, err := disk.Partitions(false)
+func collectAllDisks(ctx context.Context) []DiskInfo {
+	parts, err := probe(ctx, physicalPartitions)
 	if err != nil {
 		return nil
 	}
@@ -283,53 +372,134 @@ func collectAllDisks() []DiskInfo {
 		}
 		seen[p.Mountpoint] = true
 
-		du, duErr := disk.Usage(p.Mountpoint)
+		if !isDirectory(ctx, p.Mountpoint) {
+			continue
+		}
+
+		du, duErr := DiskUsage(ctx, p.Mountpoint)
 		if duErr != nil || du.Total == 0 {
 			continue
 		}
 
-		disks = append(disks, DiskInfo{
-			Path:         du.Path,
-			Total:        du.Total,
-			Used:         du.Used,
-			Free:         du.Free,
-			UsagePercent: du.UsedPercent,
-			Filesystem:   p.Fstype,
-		})
+		du.Filesystem = p.Fstype
+		disks = append(disks, du)
 	}
 
+	slices.SortFunc(disks, func(a, b DiskInfo) (res int) {
+		return strings.Compare(a.Path, b.Path)
+	})
+
 	return disks
 }
 
+// isDirectory returns true if path is a directory.  The files bind-mounted by
+// the container runtimes, like /etc/hosts, aren't, and their usage is the one
+// of the host file system.
+func isDirectory(ctx context.Context, path string) (ok bool) {
+	fi, err := probe(ctx, func(_ context.Context) (fs.FileInfo, error) {
+		return os.Stat(path)
+	})
+
+	return err == nil && fi.IsDir()
+}
+
+// physicalPartitions returns the physical disk partitions.
+func physicalPartitions(ctx context.Context) (parts []disk.PartitionStat, err error) {
+	return disk.PartitionsWithContext(ctx, false)
+}
+
+// DiskUsage returns usage info for the disk partition containing path.  A hung
+// file system, such as an unreachable network share, is reported as an error
+// after the probe timeout.
+func DiskUsage(ctx context.Context, path string) (DiskInfo, error) {
+	du, err := probe(ctx, func(ctx context.Context) (*disk.UsageStat, error) {
+		return disk.UsageWithContext(ctx, path)
+	})
+	if err != nil {
+		return DiskInfo{}, fmt.Errorf("disk usage of %q: %w", path, err)
+	}
+
+	return DiskInfo{
+		Path:         du.Path,
+		Total:        du.Total,
+		Used:         du.Used,
+		Free:         du.Free,
+		UsagePercent: du.UsedPercent,
+		Filesystem:   du.Fstype,
+	}, nil
+}
+
 // collectProcessInfo gathers total process count and self-process metrics.
-func collectProcessInfo(info *Info) {
-	if pids, err := process.Pids(); err == nil {
+func collectProcessInfo(ctx context.Context, info *Info) {
+	if pids, err := probe(ctx, process.PidsWithContext); err == nil {
 		info.TotalProcesses = len(pids)
 	}
 
 	pid := int32(os.Getpid())
-	proc, err := process.NewProcess(pid)
+	proc, err := process.NewProcessWithContext(ctx, pid)
 	if err != nil {
 		return
 	}
 
-	if cpuPct, cpuErr := proc.CPUPercent(); cpuErr == nil {
+	if cpuPct, cpuErr := probe(ctx, proc.CPUPercentWithContext); cpuErr == nil {
 		info.SelfCPUPercent = cpuPct
 	}
 
-	if memInfo, memErr := proc.MemoryInfo(); memErr == nil && memInfo != nil {
+	if memInfo, memErr := probe(ctx, proc.MemoryInfoWithContext); memErr == nil && memInfo != nil {
 		info.SelfMemBytes = memInfo.RSS
 	}
 
-	if fds, fdErr := proc.NumFDs(); fdErr == nil {
+	if fds, fdErr := probe(ctx, proc.NumFDsWithContext); fdErr == nil {
 		info.SelfOpenFiles = fds
 	}
 
-	if threads, tErr := proc.NumThreads(); tErr == nil {
+	if threads, tErr := probe(ctx, proc.NumThreadsWithContext); tErr == nil {
 		info.SelfThreads = threads
 	}
 }
 
+var (
+	// diskPathMu protects diskPath.
+	diskPathMu sync.RWMutex
+
+	// diskPath is the path, which disk usage is reported in the Disk fields
+	// of [Info].
+	diskPath string
+)
+
+// SetDiskPath sets the path, the usage of the disk partition containing which
+// is reported in the Disk fields of [Info], like the working directory of
+// AdGuard Home.  The root of the system drive is used if path is empty or its
+// usage can't be collected.  The usage of the other partitions is reported in
+// [Info.AllDisks].
+func SetDiskPath(path string) {
+	diskPathMu.Lock()
+	diskPath = path
+	diskPathMu.Unlock()
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	cachedAt = time.Time{}
+}
+
+// primaryDiskUsage returns the usage of the disk partition containing the path
+// set with [SetDiskPath] or the root of the system drive.
+func primaryDiskUsage(ctx context.Context) (du DiskInfo, err error) {
+	diskPathMu.RLock()
+	path := diskPath
+	diskPathMu.RUnlock()
+
+	if path != "" {
+		du, err = DiskUsage(ctx, path)
+		if err == nil {
+			return du, nil
+		}
+	}
+
+	return DiskUsage(ctx, rootPath())
+}
+
 func rootPath() string {
 	if runtime.GOOS != "windows" {
 		return "/"
@@ -443,73 +613,3 @@ func makeUniqueSorted(src []string) []string {
 
 	return dst
 }
-
-const (
-	publicIPPrimaryURL   = "https://api64.ipify.org?format=text"
-	publicIPSecondaryURL = "https://api.ipify.org?format=text"
-	publicIPCacheTTL     = 30 * time.Minute
-	publicIPReqTimeout   = 2 * time.Second
-)
-
-var (
-	publicIPMu      sync.RWMutex
-	publicIPValue   string
-	publicIPFetched time.Time
-)
-
-func lookupPublicIP() string {
-	publicIPMu.RLock()
-	val := publicIPValue
-	fresh := time.Since(publicIPFetched) < publicIPCacheTTL
-	publicIPMu.RUnlock()
-
-	if fresh && val != "" {
-		return val
-	}
-
-	ip := fetchPublicIP(publicIPPrimaryURL)
-	if ip == "" {
-		ip = fetchPublicIP(publicIPSecondaryURL)
-	}
-
-	if ip == "" {
-		return val
-	}
-
-	publicIPMu.Lock()
-	publicIPValue = ip
-	publicIPFetched = time.Now()
-	publicIPMu.Unlock()
-
-	return ip
-}
-
-func fetchPublicIP(url string) string {
-	client := http.Client{Timeout: publicIPReqTimeout}
-
-	resp, err := client.Get(url)
-	if err != nil {
-		return ""
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return ""
-	}
-
-	body, err := io.ReadAll(io.LimitReader(resp.Body, 128))
-	if err != nil {
-		return ""
-	}
-
-	text := strings.TrimSpace(string(body))
-	if text == "" {
-		return ""
-	}
-
-	if _, err = netip.ParseAddr(text); err != nil {
-		return ""
-	}
-
-	return text
-}
@@ -0,0 +1,56 @@
+package systeminfo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsDirectory(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "hosts")
+
+	err := os.WriteFile(file, []byte("127.0.0.1 localhost\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if !isDirectory(ctx, dir) {
+		t.Errorf("%q is not a directory", dir)
+	}
+
+	if isDirectory(ctx, file) {
+		t.Errorf("%q is a directory", file)
+	}
+
+	if isDirectory(ctx, filepath.Join(dir, "missing")) {
+		t.Error("missing path is a directory")
+	}
+}
+
+func TestPrimaryDiskUsage(t *testing.T) {
+	t.Cleanup(func() { SetDiskPath("") })
+
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	SetDiskPath(dir)
+
+	du, err := primaryDiskUsage(ctx)
+	if err != nil {
+		t.Fatal(err)
+	} else if du.Path != dir {
+		t.Errorf("got path %q, want %q", du.Path, dir)
+	}
+
+	SetDiskPath(filepath.Join(dir, "missing"))
+
+	du, err = primaryDiskUsage(ctx)
+	if err != nil {
+		t.Fatal(err)
+	} else if du.Path != rootPath() {
+		t.Errorf("got path %q, want %q", du.Path, rootPath())
+	}
+}
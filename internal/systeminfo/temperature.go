@@ -0,0 +1,49 @@
+package systeminfo
+
+import (
+	"context"
+	"strings"
+
+	"github.com/shirou/gopsutil/v4/sensors"
+)
+
+// cpuSensorKeys are the substrings of the keys of the sensors measuring the
+// temperature of the CPU in the order of preference.  The keys are like
+// "coretemp_package_id_0" on Intel, "k10temp_tctl" on AMD, and "cpu-thermal"
+// on Raspberry Pi.
+var cpuSensorKeys = []string{
+	"package",
+	"k10temp",
+	"coretemp",
+	"cpu",
+	"soc",
+}
+
+// collectCPUTemperature returns the temperature of the CPU in degrees Celsius.
+// It returns zero if there are no temperature sensors.
+func collectCPUTemperature(ctx context.Context) float64 {
+	// The error may also contain the warnings about some of the sensors, so
+	// use whatever has been read.
+	temps, _ := probe(ctx, sensors.TemperaturesWithContext)
+
+	return cpuTemperature(temps)
+}
+
+// cpuTemperature returns the temperature of the most appropriate CPU sensor
+// of temps, or the highest one of temps if there are no CPU sensors.
+func cpuTemperature(temps []sensors.TemperatureStat) float64 {
+	for _, sub := range cpuSensorKeys {
+		for _, t := range temps {
+			if t.Temperature > 0 && strings.Contains(strings.ToLower(t.SensorKey), sub) {
+				return t.Temperature
+			}
+		}
+	}
+
+	var highest float64
+	for _, t := range temps {
+		highest = max(highest, t.Temperature)
+	}
+
+	return highest
+}
@@ -0,0 +1,160 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghalg"
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/netutil/urlutil"
+)
+
+// Update channels.
+const (
+	// ChannelStable is the channel of the stable releases.
+	ChannelStable = "stable"
+
+	// ChannelBeta is the channel of the beta releases.
+	ChannelBeta = "beta"
+
+	// ChannelEdge is the channel of the builds of the development branch.
+	ChannelEdge = "edge"
+)
+
+// Channels returns the valid update channels.
+func Channels() (chs []string) {
+	return []string{ChannelStable, ChannelBeta, ChannelEdge}
+}
+
+// ValidateChannel returns an error if ch isn't a valid update channel.
+func ValidateChannel(ch string) (err error) {
+	if !slices.Contains(Channels(), ch) {
+		return fmt.Errorf("update channel: %w: %q", errors.ErrBadEnumValue, ch)
+	}
+
+	return nil
+}
+
+// VersionURL returns the URL of the version announcement for the update
+// channel ch.  The stable releases are announced by the latest GitHub release,
+// and the other channels are announced by the releases with the tags named
+// after them, which are moved with each build.
+func VersionURL(ch string) (u *url.URL) {
+	var dir string
+	switch ch {
+	case ChannelBeta, ChannelEdge:
+		dir = path.Join("download", ch)
+	default:
+		dir = path.Join("latest", "download")
+	}
+
+	return &url.URL{
+		Scheme: urlutil.SchemeHTTPS,
+		Host:   "github.com",
+		Path:   path.Join("/quydang04", "AdGuardHome", "releases", dir, "version.json"),
+	}
+}
+
+// Channel returns the current update channel.
+func (u *Updater) Channel() (ch string) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return u.channel
+}
+
+// SetChannel sets the update channel.  Unless a custom version announcement
+// URL is used, the announcement of ch is checked from then on.  The results of
+// the previous checks are discarded.  ch must be valid.
+func (u *Updater) SetChannel(ch string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.channel = ch
+	if !u.customURL {
+		u.versionCheckURL = VersionURL(ch).String()
+	}
+
+	u.prevCheckTime = time.Time{}
+	u.prevCheckResult = VersionInfo{}
+	u.prevCheckError = nil
+	u.newVersion = ""
+	u.packageURL = ""
+}
+
+// ChannelVersion is the latest version available in an update channel.
+type ChannelVersion struct {
+	VersionInfo
+
+	// Channel is the update channel.
+	Channel string `json:"channel"`
+
+	// Error is the error of the check of the channel, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// ChannelVersions returns the latest versions available in all update
+// channels.  The errors of the checks are reported in the results.  If a
+// custom version announcement URL is used, only the current channel is
+// checked.  It doesn't change the update, which is installed by
+// [Updater.Update].
+func (u *Updater) ChannelVersions(ctx context.Context) (cvs []*ChannelVersion) {
+	u.mu.RLock()
+	cur, customURL, curURL := u.channel, u.customURL, u.versionCheckURL
+	u.mu.RUnlock()
+
+	chs := Channels()
+	if customURL {
+		chs = []string{cur}
+	}
+
+	cvs = make([]*ChannelVersion, 0, len(chs))
+	for _, ch := range chs {
+		vcu := VersionURL(ch).String()
+		if customURL {
+			vcu = curURL
+		}
+
+		cv := &ChannelVersion{
+			Channel: ch,
+		}
+
+		var err error
+		cv.VersionInfo, err = u.availableVersion(ctx, vcu)
+		if err != nil {
+			cv.Error = err.Error()
+		}
+
+		cvs = append(cvs, cv)
+	}
+
+	return cvs
+}
+
+// availableVersion returns the version announced at vcu without changing the
+// state of u.
+func (u *Updater) availableVersion(ctx context.Context, vcu string) (vi VersionInfo, err error) {
+	body, err := u.fetch(ctx, vcu, maxVersionRespSize.Bytes())
+	if err != nil {
+		return VersionInfo{}, fmt.Errorf("fetching version: %w", err)
+	}
+
+	versionJSON, err := decodeVersionJSON(body)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return VersionInfo{}, err
+	}
+
+	_, _, found := u.downloadURL(ctx, versionJSON)
+
+	return VersionInfo{
+		NewVersion:      versionJSON["version"],
+		Announcement:    versionJSON["announcement"],
+		AnnouncementURL: versionJSON["announcement_url"],
+		CanAutoUpdate:   aghalg.BoolToNullBool(found && versionJSON["version"] != u.version),
+	}, nil
+}
@@ -0,0 +1,120 @@
+package updater_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghalg"
+	"github.com/AdguardTeam/AdGuardHome/internal/updater"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionURL(t *testing.T) {
+	t.Parallel()
+
+	const prefix = "https://github.com/quydang04/AdGuardHome/releases/"
+
+	testCases := []struct {
+		name string
+		ch   string
+		want string
+	}{{
+		name: "stable",
+		ch:   updater.ChannelStable,
+		want: prefix + "latest/download/version.json",
+	}, {
+		name: "beta",
+		ch:   updater.ChannelBeta,
+		want: prefix + "download/beta/version.json",
+	}, {
+		name: "edge",
+		ch:   updater.ChannelEdge,
+		want: prefix + "download/edge/version.json",
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.want, updater.VersionURL(tc.ch).String())
+		})
+	}
+
+	assert.Equal(t, updater.VersionURL(updater.ChannelStable), updater.DefaultVersionURL())
+}
+
+func TestValidateChannel(t *testing.T) {
+	t.Parallel()
+
+	for _, ch := range updater.Channels() {
+		assert.NoError(t, updater.ValidateChannel(ch))
+	}
+
+	err := updater.ValidateChannel("nightly")
+	testutil.AssertErrorMsg(t, `update channel: bad enum value: "nightly"`, err)
+}
+
+func TestUpdater_ChannelVersions(t *testing.T) {
+	t.Parallel()
+
+	const jsonData = `{
+  "version": "v0.104.0",
+  "announcement": "AdGuard Home v0.104.0 is now available!",
+  "announcement_url": "https://github.com/AdguardTeam/AdGuardHome/internal/releases",
+  "download_linux_amd64": "https://example.com/AdGuardHome_linux_amd64.tar.gz"
+}`
+
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		_, _ = fmt.Fprint(w, jsonData)
+	}))
+	t.Cleanup(srv.Close)
+
+	versionCheckURL, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	u := updater.NewUpdater(&updater.Config{
+		Client:          srv.Client(),
+		Logger:          testLogger,
+		Version:         "v0.103.0",
+		Channel:         updater.ChannelStable,
+		GOARCH:          "amd64",
+		GOOS:            "linux",
+		VersionCheckURL: versionCheckURL,
+	})
+
+	ctx := testutil.ContextWithTimeout(t, testTimeout)
+	_, err = u.VersionInfo(ctx, false)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), requests.Load())
+
+	u.SetChannel(updater.ChannelBeta)
+	assert.Equal(t, updater.ChannelBeta, u.Channel())
+
+	// Only the current channel is checked with a custom URL.
+	cvs := u.ChannelVersions(ctx)
+	require.Len(t, cvs, 1)
+
+	assert.Equal(t, &updater.ChannelVersion{
+		VersionInfo: updater.VersionInfo{
+			NewVersion:      "v0.104.0",
+			Announcement:    "AdGuard Home v0.104.0 is now available!",
+			AnnouncementURL: "https://github.com/AdguardTeam/AdGuardHome/internal/releases",
+			CanAutoUpdate:   aghalg.NBTrue,
+		},
+		Channel: updater.ChannelBeta,
+	}, cvs[0])
+
+	// The cached result is discarded when the channel is changed.
+	_, err = u.VersionInfo(ctx, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(3), requests.Load())
+}
@@ -8,6 +8,7 @@ import (
 	"maps"
 	"net/http"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/AdguardTeam/AdGuardHome/internal/aghalg"
@@ -94,21 +95,10 @@ func (u *Updater) parseVersionResponse(
 	info := VersionInfo{
 		CanAutoUpdate: aghalg.NBFalse,
 	}
-	versionJSON := map[string]string{
-		"version":          "",
-		"announcement":     "",
-		"announcement_url": "",
-	}
-	err = json.Unmarshal(data, &versionJSON)
+	versionJSON, err := decodeVersionJSON(data)
 	if err != nil {
-		return info, fmt.Errorf("version.json: %w", err)
-	}
-
-	for k, v := range versionJSON {
-		err = validate.NotEmpty("version_json_value", v)
-		if err != nil {
-			return info, fmt.Errorf("bad value for %q key: %w", k, err)
-		}
+		// Don't wrap the error since it's informative enough as is.
+		return info, err
 	}
 
 	info.NewVersion = versionJSON["version"]
@@ -120,6 +110,8 @@ func (u *Updater) parseVersionResponse(
 		return info, fmt.Errorf("version.json: bad key %q: %w", key, errors.ErrNoValue)
 	}
 
+	u.setVerificationURLs(versionJSON, key)
+
 	isNewVersion := info.NewVersion != u.version
 	if isNewVersion {
 		u.logger.InfoContext(
@@ -144,6 +136,20 @@ func (u *Updater) parseVersionResponse(
 	return info, nil
 }
 
+// setVerificationURLs sets the optional URLs of the checksums, their
+// signature, and the delta update from versionObj.  dlKey is the key of the
+// package download URL there.  The delta is only used if it's made against the
+// current version.
+func (u *Updater) setVerificationURLs(versionObj map[string]string, dlKey string) {
+	u.checksumsURL = versionObj["checksums_url"]
+	u.signatureURL = versionObj["checksums_signature_url"]
+
+	u.deltaURL = ""
+	if versionObj["delta_from"] == u.version {
+		u.deltaURL = versionObj["delta"+strings.TrimPrefix(dlKey, "download")]
+	}
+}
+
 // downloadURL returns the download URL for current build as well as its key in
 // versionObj.  If the key is not found, it additionally prints an informative
 // log message.
@@ -184,3 +190,26 @@ func isMIPS(arch string) (ok bool) {
 		return false
 	}
 }
+
+// decodeVersionJSON decodes the version announcement and checks that the
+// required values are present and that none of the values are empty.
+func decodeVersionJSON(data []byte) (versionJSON map[string]string, err error) {
+	versionJSON = map[string]string{
+		"version":          "",
+		"announcement":     "",
+		"announcement_url": "",
+	}
+	err = json.Unmarshal(data, &versionJSON)
+	if err != nil {
+		return nil, fmt.Errorf("version.json: %w", err)
+	}
+
+	for k, v := range versionJSON {
+		err = validate.NotEmpty("version_json_value", v)
+		if err != nil {
+			return nil, fmt.Errorf("bad value for %q key: %w", k, err)
+		}
+	}
+
+	return versionJSON, nil
+}
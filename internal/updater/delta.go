@@ -0,0 +1,174 @@
+package updater
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghos"
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// deltaMagic is the signature at the beginning of the delta files.
+//
+// The delta file consists of the magic, the SHA-256 checksum of the target
+// executable, and the gzip-compressed stream of instructions.  Each
+// instruction starts with an opcode byte, see [deltaOpCopy] and [deltaOpAdd].
+const deltaMagic = "AGHDLT01"
+
+// Delta instruction opcodes.
+const (
+	// deltaOpCopy is followed by the uvarint offset and the uvarint length of
+	// the data to copy from the current executable.
+	deltaOpCopy byte = 0
+
+	// deltaOpAdd is followed by the uvarint length and the literal data.
+	deltaOpAdd byte = 1
+)
+
+// errBadDelta is returned when the delta can't be applied.
+const errBadDelta errors.Error = "bad delta"
+
+// applyDelta reconstructs the target executable from the current one and the
+// delta.
+func applyDelta(current, delta []byte) (target []byte, err error) {
+	hdrLen := len(deltaMagic) + sha256.Size
+	if len(delta) < hdrLen || string(delta[:len(deltaMagic)]) != deltaMagic {
+		return nil, fmt.Errorf("%w: no header", errBadDelta)
+	}
+
+	wantSum := delta[len(deltaMagic):hdrLen]
+
+	zr, err := gzip.NewReader(bytes.NewReader(delta[hdrLen:]))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errBadDelta, err)
+	}
+	defer func() { err = errors.WithDeferred(err, zr.Close()) }()
+
+	r := bufio.NewReader(io.LimitReader(zr, MaxPackageFileSize))
+	buf := &bytes.Buffer{}
+	for {
+		var op byte
+		op, err = r.ReadByte()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%w: reading opcode: %w", errBadDelta, err)
+		}
+
+		err = applyDeltaOp(buf, r, op, current)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errBadDelta, err)
+		}
+	}
+
+	target = buf.Bytes()
+	gotSum := sha256.Sum256(target)
+	if !bytes.Equal(gotSum[:], wantSum) {
+		return nil, fmt.Errorf("%w: target checksum mismatch", errBadDelta)
+	}
+
+	return target, nil
+}
+
+// applyDeltaOp writes the result of a single instruction with the opcode op
+// into buf.
+func applyDeltaOp(buf *bytes.Buffer, r *bufio.Reader, op byte, current []byte) (err error) {
+	switch op {
+	case deltaOpCopy:
+		var off, n uint64
+		off, err = binary.ReadUvarint(r)
+		if err != nil {
+			return fmt.Errorf("reading offset: %w", err)
+		}
+
+		n, err = binary.ReadUvarint(r)
+		if err != nil {
+			return fmt.Errorf("reading length: %w", err)
+		}
+
+		if off > uint64(len(current)) || n > uint64(len(current))-off {
+			return fmt.Errorf("copy of %d bytes at %d is out of range", n, off)
+		}
+
+		buf.Write(current[off : off+n])
+	case deltaOpAdd:
+		var n uint64
+		n, err = binary.ReadUvarint(r)
+		if err != nil {
+			return fmt.Errorf("reading length: %w", err)
+		}
+
+		_, err = io.CopyN(buf, r, int64(n))
+		if err != nil {
+			return fmt.Errorf("reading data: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown opcode %d", op)
+	}
+
+	if buf.Len() > MaxPackageFileSize {
+		return errors.Error("target is too large")
+	}
+
+	return nil
+}
+
+// errUnsignedDelta is returned when the checksums of the release aren't signed
+// with a trusted key, in which case the delta is only vouched for by the
+// checksum embedded in it.
+const errUnsignedDelta errors.Error = "delta updates require signed checksums"
+
+// downloadDelta downloads the delta update, verifies it against sums, and
+// reconstructs the new executable in the update directory.  signed is true if
+// sums are signed with a trusted key, otherwise the delta is rejected.
+func (u *Updater) downloadDelta(ctx context.Context, sums checksums, signed bool) (err error) {
+	if !signed {
+		return errUnsignedDelta
+	}
+
+	u.logger.InfoContext(ctx, "downloading delta", "url", u.deltaURL)
+
+	delta, err := u.fetch(ctx, u.deltaURL, MaxPackageFileSize)
+	if err != nil {
+		return fmt.Errorf("fetching delta: %w", err)
+	}
+
+	err = sums.verify(path.Base(u.deltaURL), delta)
+	if err != nil {
+		return fmt.Errorf("verifying delta: %w", err)
+	}
+
+	current, err := os.ReadFile(u.currentExeName)
+	if err != nil {
+		return fmt.Errorf("reading current executable: %w", err)
+	}
+
+	target, err := applyDelta(current, delta)
+	if err != nil {
+		// Don't wrap the error, because it's informative enough as is.
+		return err
+	}
+
+	err = os.MkdirAll(u.updateDir, aghos.DefaultPermDir)
+	if err != nil {
+		return fmt.Errorf("creating update dir: %w", err)
+	}
+
+	err = os.WriteFile(u.updateExeName, target, aghos.DefaultPermExe)
+	if err != nil {
+		return fmt.Errorf("writing executable: %w", err)
+	}
+
+	// Only the executable is updated, so keep the current supporting files.
+	u.unpackedFiles = nil
+
+	return nil
+}
@@ -0,0 +1,255 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/aghos"
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+)
+
+const (
+	// backupDirName is the name of the backup directory within the working
+	// directory.
+	backupDirName = "agh-backup"
+
+	// backupConfName is the name of the configuration file backup within the
+	// backup directory.
+	backupConfName = "AdGuardHome.yaml"
+
+	// backupDataDirName is the name of the directory within the backup
+	// directory, which contains the snapshots of the data files.
+	backupDataDirName = "data"
+
+	// pendingStateName is the name of the file within the backup directory,
+	// which contains the state of the update awaiting verification.
+	pendingStateName = "update.json"
+
+	// backupInfoName is the name of the file within the backup directory,
+	// which contains the information about the backed up version.
+	backupInfoName = "backup.json"
+)
+
+// BackupInfo is the information about the version kept in the backup
+// directory, to which the update can be rolled back.
+type BackupInfo struct {
+	// Time is the time, when the backup has been made.
+	Time time.Time `json:"time"`
+
+	// Version is the backed up version.
+	Version string `json:"version"`
+}
+
+// PendingUpdate is the state of an update, which has been installed, but not
+// yet verified by the new version.
+type PendingUpdate struct {
+	// FromVersion is the version, which has been replaced.
+	FromVersion string `json:"from_version"`
+
+	// ToVersion is the version, which has been installed.
+	ToVersion string `json:"to_version"`
+
+	// Attempts is the number of times the installed version has been started
+	// without being verified.
+	Attempts uint `json:"attempts"`
+}
+
+// backupDirPath returns the path to the backup directory.
+func (u *Updater) backupDirPath() (p string) {
+	return filepath.Join(u.workDir, backupDirName)
+}
+
+// pendingStatePath returns the path to the state file of the pending update.
+func (u *Updater) pendingStatePath() (p string) {
+	return filepath.Join(u.backupDirPath(), pendingStateName)
+}
+
+// backupDataPath returns the path to the snapshot of the data file at p.
+func (u *Updater) backupDataPath(p string) (snapshot string) {
+	return filepath.Join(u.backupDirPath(), backupDataDirName, filepath.Base(p))
+}
+
+// snapshotDataFiles copies the data files into the backup directory.  Missing
+// data files are skipped.
+func (u *Updater) snapshotDataFiles(ctx context.Context) (err error) {
+	for _, p := range u.dataFiles {
+		dst := u.backupDataPath(p)
+		err = os.MkdirAll(filepath.Dir(dst), aghos.DefaultPermDir)
+		if err != nil {
+			return fmt.Errorf("creating data backup dir: %w", err)
+		}
+
+		err = copyFile(p, dst, aghos.DefaultPermFile)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("backing up %q: %w", p, err)
+		}
+
+		u.logger.DebugContext(ctx, "backed up data file", "from", p, "to", dst)
+	}
+
+	return nil
+}
+
+// writeBackupInfo saves the information about the current version into the
+// backup directory.
+func (u *Updater) writeBackupInfo() (err error) {
+	b, err := json.Marshal(&BackupInfo{
+		Time:    time.Now(),
+		Version: u.version,
+	})
+	if err != nil {
+		return fmt.Errorf("encoding backup info: %w", err)
+	}
+
+	return os.WriteFile(filepath.Join(u.backupDirPath(), backupInfoName), b, aghos.DefaultPermFile)
+}
+
+// Backup returns the information about the version, to which the update can be
+// rolled back.  bi is nil if there is no such version.
+func (u *Updater) Backup() (bi *BackupInfo, err error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	backupDir := u.backupDirPath()
+	_, err = os.Stat(filepath.Join(backupDir, filepath.Base(u.execPath)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("checking backup executable: %w", err)
+	}
+
+	// The information is removed on rollback, even if the executable is kept.
+	b, err := os.ReadFile(filepath.Join(backupDir, backupInfoName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading backup info: %w", err)
+	}
+
+	bi = &BackupInfo{}
+	err = json.Unmarshal(b, bi)
+	if err != nil {
+		return nil, fmt.Errorf("decoding backup info: %w", err)
+	}
+
+	return bi, nil
+}
+
+// writePendingState saves the state of the just installed update.
+func (u *Updater) writePendingState(pu *PendingUpdate) (err error) {
+	b, err := json.Marshal(pu)
+	if err != nil {
+		return fmt.Errorf("encoding state: %w", err)
+	}
+
+	return os.WriteFile(u.pendingStatePath(), b, aghos.DefaultPermFile)
+}
+
+// PendingUpdate returns the state of the installed but not yet verified
+// update and counts the current start of AdGuard Home as another verification
+// attempt.  pu is nil if there is no such update.
+func (u *Updater) PendingUpdate(ctx context.Context) (pu *PendingUpdate, err error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	b, err := os.ReadFile(u.pendingStatePath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading state: %w", err)
+	}
+
+	pu = &PendingUpdate{}
+	err = json.Unmarshal(b, pu)
+	if err != nil {
+		return nil, fmt.Errorf("decoding state: %w", err)
+	}
+
+	pu.Attempts++
+	err = u.writePendingState(pu)
+	if err != nil {
+		return nil, fmt.Errorf("saving state: %w", err)
+	}
+
+	u.logger.DebugContext(ctx, "found pending update", "state", pu)
+
+	return pu, nil
+}
+
+// Confirm marks the pending update as verified, so that it's not rolled back.
+func (u *Updater) Confirm(ctx context.Context) (err error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	err = os.Remove(u.pendingStatePath())
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("removing state: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "update confirmed")
+
+	return nil
+}
+
+// Rollback restores the executable, the configuration file, and the data files
+// from the backup made before the pending update.  The caller is responsible
+// for restarting AdGuard Home afterwards.
+func (u *Updater) Rollback(ctx context.Context) (err error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.logger.InfoContext(ctx, "rolling back update")
+
+	backupDir := u.backupDirPath()
+	backupExe := filepath.Join(backupDir, filepath.Base(u.execPath))
+	_, err = os.Stat(backupExe)
+	if err != nil {
+		return fmt.Errorf("checking backup executable: %w", err)
+	}
+
+	if u.goos == "windows" {
+		// Use copy, since renaming fails with "File in use" error.
+		err = copyFile(backupExe, u.execPath, aghos.DefaultPermExe)
+	} else {
+		err = os.Rename(backupExe, u.execPath)
+	}
+	if err != nil {
+		return fmt.Errorf("restoring executable: %w", err)
+	}
+
+	err = copyFile(filepath.Join(backupDir, backupConfName), u.confName, aghos.DefaultPermFile)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("restoring config: %w", err)
+	}
+
+	for _, p := range u.dataFiles {
+		err = copyFile(u.backupDataPath(p), p, aghos.DefaultPermFile)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("restoring %q: %w", p, err)
+		}
+	}
+
+	err = os.Remove(u.pendingStatePath())
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		u.logger.WarnContext(ctx, "removing update state", slogutil.KeyError, err)
+	}
+
+	err = os.Remove(filepath.Join(backupDir, backupInfoName))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		u.logger.WarnContext(ctx, "removing backup info", slogutil.KeyError, err)
+	}
+
+	u.logger.InfoContext(ctx, "update rolled back", "exec_path", u.execPath)
+
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"crypto/ed25519"
 	"fmt"
 	"io"
 	"io/fs"
@@ -14,7 +15,6 @@ import (
 	"net/http"
 	"net/url"
 	"os"
-	"path"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -23,9 +23,7 @@ import (
 	"github.com/AdguardTeam/AdGuardHome/internal/aghos"
 	"github.com/AdguardTeam/AdGuardHome/internal/version"
 	"github.com/AdguardTeam/golibs/errors"
-	"github.com/AdguardTeam/golibs/ioutil"
 	"github.com/AdguardTeam/golibs/logutil/slogutil"
-	"github.com/AdguardTeam/golibs/netutil/urlutil"
 	"github.com/AdguardTeam/golibs/osutil/executil"
 )
 
@@ -37,20 +35,36 @@ type Updater struct {
 	cmdCons executil.CommandConstructor
 
 	version string
-	channel string
 	goarch  string
 	goos    string
 	goarm   string
 	gomips  string
 
-	workDir         string
-	confName        string
-	execPath        string
-	versionCheckURL string
+	workDir  string
+	confName string
+	execPath string
+
+	// dataFiles are the absolute paths to the data files, which are backed up
+	// before the update and restored on rollback.
+	dataFiles []string
+
+	// signingKeys are the trusted keys for the checksums of the releases.
+	signingKeys []ed25519.PublicKey
 
 	// mu protects all fields below.
 	mu *sync.RWMutex
 
+	// channel is the current update channel.
+	channel string
+
+	// versionCheckURL is the URL of the version announcement of channel,
+	// unless customURL is true.
+	versionCheckURL string
+
+	// customURL is true if versionCheckURL is set by the user and therefore
+	// doesn't depend on channel.
+	customURL bool
+
 	// TODO(a.garipov): See if all of these fields actually have to be in
 	// this struct.
 	currentExeName string // current binary executable
@@ -61,8 +75,15 @@ type Updater struct {
 	updateExeName  string // "workDir/agh-update-v0.103.0/AdGuardHome[.exe]"
 	unpackedFiles  []string
 
-	newVersion string
-	packageURL string
+	newVersion   string
+	packageURL   string
+	deltaURL     string
+	checksumsURL string
+	signatureURL string
+
+	// verification is the result of the verification of the last downloaded
+	// update.
+	verification *Verification
 
 	// Cached fields to prevent too many API requests.
 	prevCheckError  error
@@ -77,11 +98,7 @@ type Updater struct {
 // the update feed points at the latest release's version.json asset there
 // instead.
 func DefaultVersionURL() *url.URL {
-	return &url.URL{
-		Scheme: urlutil.SchemeHTTPS,
-		Host:   "github.com",
-		Path:   path.Join("quydang04", "AdGuardHome", "releases", "latest", "download", "version.json"),
-	}
+	return VersionURL(ChannelStable)
 }
 
 // Config is the AdGuard Home updater configuration.
@@ -92,8 +109,8 @@ type Config struct {
 	// Logger is used for logging the update process.  It must not be nil.
 	Logger *slog.Logger
 
-	// VersionCheckURL is URL to the latest version announcement.  It must not
-	// be nil, see [DefaultVersionURL].
+	// VersionCheckURL is the custom URL of the latest version announcement.
+	// If nil, the announcement of Channel is used, see [VersionURL].
 	VersionCheckURL *url.URL
 
 	// CommandConstructor is used to run external commands.  It must not be nil.
@@ -102,8 +119,8 @@ type Config struct {
 	// Version is the current AdGuard Home version.  It must not be empty.
 	Version string
 
-	// Channel is the current AdGuard Home update channel.  It must be a valid
-	// channel, see [version.ChannelBeta] and the related constants.
+	// Channel is the initial update channel.  It must be a valid channel, see
+	// [ChannelStable] and the related constants.
 	Channel string
 
 	// GOARCH is the current CPU architecture.  It must not be empty and must be
@@ -132,10 +149,28 @@ type Config struct {
 
 	// ExecPath is path to the executable file.  It must not be empty.
 	ExecPath string
+
+	// DataFiles are the absolute paths to the data files, such as databases,
+	// which are backed up before the update and restored on rollback.  The
+	// base names of the files must be unique.
+	DataFiles []string
+
+	// SigningKeys are the trusted Ed25519 keys, one of which must sign the
+	// checksums of the release files.  If empty, the authenticity of the
+	// updates isn't verified: the checksums, if the release publishes them,
+	// only protect the integrity of the downloads, and the delta updates
+	// aren't used.
+	SigningKeys []ed25519.PublicKey
 }
 
 // NewUpdater creates a new Updater.  conf must not be nil.
 func NewUpdater(conf *Config) *Updater {
+	customURL := conf.VersionCheckURL != nil
+	vcu := conf.VersionCheckURL
+	if !customURL {
+		vcu = VersionURL(conf.Channel)
+	}
+
 	return &Updater{
 		client: conf.Client,
 		logger: conf.Logger,
@@ -143,18 +178,23 @@ func NewUpdater(conf *Config) *Updater {
 		cmdCons: conf.CommandConstructor,
 
 		version: conf.Version,
-		channel: conf.Channel,
 		goarch:  conf.GOARCH,
 		goos:    conf.GOOS,
 		goarm:   conf.GOARM,
 		gomips:  conf.GOMIPS,
 
-		confName:        conf.ConfName,
-		workDir:         conf.WorkDir,
-		execPath:        conf.ExecPath,
-		versionCheckURL: conf.VersionCheckURL.String(),
+		confName: conf.ConfName,
+		workDir:  conf.WorkDir,
+		execPath: conf.ExecPath,
+
+		dataFiles:   conf.DataFiles,
+		signingKeys: conf.SigningKeys,
 
 		mu: &sync.RWMutex{},
+
+		channel:         conf.Channel,
+		versionCheckURL: vcu.String(),
+		customURL:       customURL,
 	}
 }
 
@@ -176,14 +216,16 @@ func (u *Updater) Update(ctx context.Context, firstRun bool) (err error) {
 
 	defer u.clean(ctx)
 
-	err = u.downloadPackageFile(ctx)
+	isDelta, err := u.download(ctx)
 	if err != nil {
-		return fmt.Errorf("downloading package file: %w", err)
+		return fmt.Errorf("downloading: %w", err)
 	}
 
-	err = u.unpack(ctx)
-	if err != nil {
-		return fmt.Errorf("unpacking: %w", err)
+	if !isDelta {
+		err = u.unpack(ctx)
+		if err != nil {
+			return fmt.Errorf("unpacking: %w", err)
+		}
 	}
 
 	if !firstRun {
@@ -203,6 +245,14 @@ func (u *Updater) Update(ctx context.Context, firstRun bool) (err error) {
 		return fmt.Errorf("replacing: %w", err)
 	}
 
+	err = u.writePendingState(&PendingUpdate{
+		FromVersion: u.version,
+		ToVersion:   u.newVersion,
+	})
+	if err != nil {
+		return fmt.Errorf("saving update state: %w", err)
+	}
+
 	return nil
 }
 
@@ -235,7 +285,7 @@ func (u *Updater) prepare(ctx context.Context) (err error) {
 	}
 
 	u.packageName = filepath.Join(u.updateDir, pkgNameOnly)
-	u.backupDir = filepath.Join(u.workDir, "agh-backup")
+	u.backupDir = u.backupDirPath()
 
 	updateExeName := "AdGuardHome"
 	if u.goos == "windows" {
@@ -325,19 +375,35 @@ func (u *Updater) check(ctx context.Context) (err error) {
 	return nil
 }
 
-// backup makes a backup of the current configuration and supporting files.  It
-// ignores the configuration file if firstRun is true.
+// backup makes a backup of the current configuration, data, and supporting
+// files.  It ignores the configuration file if firstRun is true.
 func (u *Updater) backup(ctx context.Context, firstRun bool) (err error) {
 	u.logger.InfoContext(ctx, "backing up current configuration")
 
 	_ = os.Mkdir(u.backupDir, aghos.DefaultPermDir)
+
+	// Remove the leftovers of the previous updates so that a rollback doesn't
+	// restore them.
+	_ = os.Remove(filepath.Join(u.backupDir, backupConfName))
+	_ = os.RemoveAll(filepath.Join(u.backupDir, backupDataDirName))
+
 	if !firstRun {
-		err = copyFile(u.confName, filepath.Join(u.backupDir, "AdGuardHome.yaml"), aghos.DefaultPermFile)
+		err = copyFile(u.confName, filepath.Join(u.backupDir, backupConfName), aghos.DefaultPermFile)
 		if err != nil {
 			return fmt.Errorf("copyFile() failed: %w", err)
 		}
 	}
 
+	err = u.snapshotDataFiles(ctx)
+	if err != nil {
+		return fmt.Errorf("backing up data files: %w", err)
+	}
+
+	err = u.writeBackupInfo()
+	if err != nil {
+		return fmt.Errorf("saving backup info: %w", err)
+	}
+
 	wd := u.workDir
 	err = u.copySupportingFiles(ctx, u.unpackedFiles, wd, u.backupDir)
 	if err != nil {
@@ -399,27 +465,72 @@ func (u *Updater) clean(ctx context.Context) {
 // approximately 9 MiB.
 const MaxPackageFileSize = 32 * 1024 * 1024
 
-// Download package file and save it to disk
-func (u *Updater) downloadPackageFile(ctx context.Context) (err error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.packageURL, nil)
+// download downloads and verifies the update, preferring the delta one, if
+// it's available.  isDelta is true if the new executable has been
+// reconstructed from the delta and there is no package to unpack.
+func (u *Updater) download(ctx context.Context) (isDelta bool, err error) {
+	v := &Verification{
+		Version: u.newVersion,
+		Method:  DownloadMethodFull,
+	}
+
+	defer func() {
+		v.Time = time.Now()
+		if err != nil {
+			v.Status, v.Error = VerificationStatusFailed, err.Error()
+		}
+
+		u.verification = v
+	}()
+
+	sums, signed, err := u.fetchChecksums(ctx)
 	if err != nil {
-		return fmt.Errorf("constructing package request: %w", err)
+		return false, fmt.Errorf("verifying release: %w", err)
 	}
 
-	resp, err := u.client.Do(req)
+	switch {
+	case signed:
+		v.Status = VerificationStatusVerified
+	case sums != nil:
+		v.Status = VerificationStatusChecksum
+	default:
+		v.Status = VerificationStatusUnverified
+		u.logger.WarnContext(ctx, "release publishes no checksums; skipping verification")
+	}
+
+	if u.deltaURL != "" {
+		err = u.downloadDelta(ctx, sums, signed)
+		if err == nil {
+			v.Method = DownloadMethodDelta
+
+			return true, nil
+		}
+
+		u.logger.WarnContext(ctx, "delta update failed; using full package", slogutil.KeyError, err)
+	}
+
+	err = u.downloadPackageFile(ctx, sums)
 	if err != nil {
-		return fmt.Errorf("requesting package: %w", err)
+		return false, fmt.Errorf("downloading package file: %w", err)
 	}
-	defer func() { err = errors.WithDeferred(err, resp.Body.Close()) }()
 
-	r := ioutil.LimitReader(resp.Body, MaxPackageFileSize)
+	return false, nil
+}
+
+// downloadPackageFile downloads the package file, verifies it against sums,
+// and saves it to disk.
+func (u *Updater) downloadPackageFile(ctx context.Context, sums checksums) (err error) {
+	u.logger.InfoContext(ctx, "downloading package", "url", u.packageURL)
 
-	u.logger.InfoContext(ctx, "reading http body")
+	body, err := u.fetch(ctx, u.packageURL, MaxPackageFileSize)
+	if err != nil {
+		return fmt.Errorf("fetching package: %w", err)
+	}
 
-	// This use of ReadAll is now safe, because we limited body's Reader.
-	body, err := io.ReadAll(r)
+	_, pkgNameOnly := filepath.Split(u.packageURL)
+	err = sums.verify(pkgNameOnly, body)
 	if err != nil {
-		return fmt.Errorf("io.ReadAll() failed: %w", err)
+		return fmt.Errorf("verifying package: %w", err)
 	}
 
 	err = os.Mkdir(u.updateDir, aghos.DefaultPermDir)
@@ -76,7 +76,7 @@ func TestUpdater_internal(t *testing.T) {
 		u.packageURL = fakeURL.String()
 
 		require.NoError(t, u.prepare(newCtx(t)))
-		require.NoError(t, u.downloadPackageFile(newCtx(t)))
+		require.NoError(t, u.downloadPackageFile(newCtx(t), nil))
 		require.NoError(t, u.unpack(newCtx(t)))
 		require.NoError(t, u.backup(newCtx(t), false))
 		require.NoError(t, u.replace(newCtx(t)))
@@ -1,6 +1,12 @@
 package updater_test
 
 import (
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -9,6 +15,7 @@ import (
 	"path"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"testing"
 	"time"
 
@@ -149,3 +156,305 @@ func TestUpdater_Update(t *testing.T) {
 		assert.ErrorAs(t, err, &urlErr)
 	})
 }
+
+func TestUpdater_Rollback(t *testing.T) {
+	const jsonData = `{
+  "version": "v0.103.0-beta.2",
+  "announcement": "AdGuard Home v0.103.0-beta.2 is now available!",
+  "announcement_url": "https://github.com/AdguardTeam/AdGuardHome/internal/releases",
+  "selfupdate_min_version": "v0.0",
+  "download_linux_amd64": "%s"
+}`
+
+	const packagePath = "/AdGuardHome.tar.gz"
+
+	wd := t.TempDir()
+
+	exePath := filepath.Join(wd, "AdGuardHome")
+	yamlPath := filepath.Join(wd, "AdGuardHome.yaml")
+	dbPath := filepath.Join(wd, "sessions.db")
+
+	require.NoError(t, os.WriteFile(exePath, []byte("AdGuardHome"), 0o755))
+	require.NoError(t, os.WriteFile(yamlPath, []byte("AdGuardHome.yaml"), 0o644))
+	require.NoError(t, os.WriteFile(dbPath, []byte("sessions.db"), 0o644))
+
+	pkgData, err := os.ReadFile("testdata/AdGuardHome_unix.tar.gz")
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(packagePath, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(pkgData)
+	})
+
+	const versionPath = "/version.json"
+	mux.HandleFunc(versionPath, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, jsonData, "http://"+r.Host+packagePath)
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	versionCheckURL, err := url.Parse(srv.URL + versionPath)
+	require.NoError(t, err)
+
+	u := updater.NewUpdater(&updater.Config{
+		Client:             srv.Client(),
+		Logger:             testLogger,
+		CommandConstructor: testCmdCons,
+		GOARCH:             "amd64",
+		GOOS:               "linux",
+		Version:            "v0.103.0",
+		ConfName:           yamlPath,
+		WorkDir:            wd,
+		ExecPath:           exePath,
+		VersionCheckURL:    versionCheckURL,
+		DataFiles:          []string{dbPath},
+	})
+
+	ctx := testutil.ContextWithTimeout(t, testTimeout)
+	pu, err := u.PendingUpdate(ctx)
+	require.NoError(t, err)
+
+	assert.Nil(t, pu)
+
+	bi, err := u.Backup()
+	require.NoError(t, err)
+
+	assert.Nil(t, bi)
+
+	_, err = u.VersionInfo(ctx, false)
+	require.NoError(t, err)
+
+	err = u.Update(ctx, true)
+	require.NoError(t, err)
+
+	bi, err = u.Backup()
+	require.NoError(t, err)
+	require.NotNil(t, bi)
+
+	assert.Equal(t, "v0.103.0", bi.Version)
+
+	// Emulate the changes made by the new version.
+	require.NoError(t, os.WriteFile(yamlPath, []byte("new.yaml"), 0o644))
+	require.NoError(t, os.WriteFile(dbPath, []byte("new.db"), 0o644))
+
+	pu, err = u.PendingUpdate(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, pu)
+
+	assert.Equal(t, &updater.PendingUpdate{
+		FromVersion: "v0.103.0",
+		ToVersion:   "v0.103.0-beta.2",
+		Attempts:    1,
+	}, pu)
+
+	pu, err = u.PendingUpdate(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, pu)
+
+	assert.Equal(t, uint(2), pu.Attempts)
+
+	err = u.Rollback(ctx)
+	require.NoError(t, err)
+
+	d, err := os.ReadFile(exePath)
+	require.NoError(t, err)
+
+	assert.Equal(t, "AdGuardHome", string(d))
+
+	d, err = os.ReadFile(dbPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, "sessions.db", string(d))
+
+	// The configuration file is not backed up on the first run.
+	d, err = os.ReadFile(yamlPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, "new.yaml", string(d))
+
+	pu, err = u.PendingUpdate(ctx)
+	require.NoError(t, err)
+
+	assert.Nil(t, pu)
+
+	bi, err = u.Backup()
+	require.NoError(t, err)
+
+	assert.Nil(t, bi)
+
+	err = u.Confirm(ctx)
+	assert.NoError(t, err)
+}
+
+// newTestDelta returns a delta, which appends suffix to current.
+func newTestDelta(tb testing.TB, current []byte, suffix string) (delta []byte) {
+	tb.Helper()
+
+	target := append(slices.Clone(current), suffix...)
+	sum := sha256.Sum256(target)
+
+	ops := []byte{0}
+	ops = binary.AppendUvarint(ops, 0)
+	ops = binary.AppendUvarint(ops, uint64(len(current)))
+	ops = append(ops, 1)
+	ops = binary.AppendUvarint(ops, uint64(len(suffix)))
+	ops = append(ops, suffix...)
+
+	buf := bytes.NewBufferString("AGHDLT01")
+	buf.Write(sum[:])
+
+	zw := gzip.NewWriter(buf)
+	_, err := zw.Write(ops)
+	require.NoError(tb, err)
+	require.NoError(tb, zw.Close())
+
+	return buf.Bytes()
+}
+
+func TestUpdater_Update_verification(t *testing.T) {
+	const jsonData = `{
+  "version": "v0.103.0-beta.2",
+  "announcement": "AdGuard Home v0.103.0-beta.2 is now available!",
+  "announcement_url": "https://github.com/AdguardTeam/AdGuardHome/internal/releases",
+  "selfupdate_min_version": "v0.0",
+  "download_linux_amd64": "%[1]s/AdGuardHome.tar.gz",
+  "delta_from": "v0.103.0",
+  "delta_linux_amd64": "%[1]s/AdGuardHome_linux_amd64.delta",
+  "checksums_url": "%[1]s/checksums.txt",
+  "checksums_signature_url": "%[1]s/checksums.txt.sig"
+}`
+
+	pkgData, err := os.ReadFile("testdata/AdGuardHome_unix.tar.gz")
+	require.NoError(t, err)
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	exeData := []byte("AdGuardHome")
+	deltaData := newTestDelta(t, exeData, " v2")
+
+	newChecksums := func(delta []byte) (data []byte) {
+		pkgSum, deltaSum := sha256.Sum256(pkgData), sha256.Sum256(delta)
+
+		return fmt.Appendf(nil, "%x  AdGuardHome.tar.gz\n%x *AdGuardHome_linux_amd64.delta\n", pkgSum, deltaSum)
+	}
+
+	testCases := []struct {
+		name       string
+		delta      []byte
+		sigKey     ed25519.PrivateKey
+		keys       []ed25519.PublicKey
+		wantExe    string
+		wantStatus string
+		wantMethod string
+		wantErr    bool
+	}{{
+		name:       "delta",
+		delta:      deltaData,
+		sigKey:     priv,
+		keys:       []ed25519.PublicKey{pub},
+		wantExe:    "AdGuardHome v2",
+		wantStatus: updater.VerificationStatusVerified,
+		wantMethod: updater.DownloadMethodDelta,
+		wantErr:    false,
+	}, {
+		name:       "bad_delta",
+		delta:      newTestDelta(t, []byte("other"), " v2"),
+		sigKey:     priv,
+		keys:       []ed25519.PublicKey{pub},
+		wantExe:    "#!/bin/sh\n\nexit 0\n",
+		wantStatus: updater.VerificationStatusVerified,
+		wantMethod: updater.DownloadMethodFull,
+		wantErr:    false,
+	}, {
+		name:       "bad_signature",
+		delta:      deltaData,
+		sigKey:     ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)),
+		keys:       []ed25519.PublicKey{pub},
+		wantExe:    "AdGuardHome",
+		wantStatus: updater.VerificationStatusFailed,
+		wantMethod: updater.DownloadMethodFull,
+		wantErr:    true,
+	}, {
+		name:       "unsigned_delta",
+		delta:      deltaData,
+		sigKey:     priv,
+		keys:       nil,
+		wantExe:    "#!/bin/sh\n\nexit 0\n",
+		wantStatus: updater.VerificationStatusChecksum,
+		wantMethod: updater.DownloadMethodFull,
+		wantErr:    false,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sums := newChecksums(tc.delta)
+			sig := base64.StdEncoding.EncodeToString(ed25519.Sign(tc.sigKey, sums))
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/version.json", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = fmt.Fprintf(w, jsonData, "http://"+r.Host)
+			})
+			for p, data := range map[string][]byte{
+				"/AdGuardHome.tar.gz":            pkgData,
+				"/AdGuardHome_linux_amd64.delta": tc.delta,
+				"/checksums.txt":                 sums,
+				"/checksums.txt.sig":             []byte(sig),
+			} {
+				mux.HandleFunc(p, func(w http.ResponseWriter, _ *http.Request) {
+					_, _ = w.Write(data)
+				})
+			}
+
+			srv := httptest.NewServer(mux)
+			t.Cleanup(srv.Close)
+
+			versionCheckURL, pErr := url.Parse(srv.URL + "/version.json")
+			require.NoError(t, pErr)
+
+			wd := t.TempDir()
+			exePath := filepath.Join(wd, "AdGuardHome")
+			require.NoError(t, os.WriteFile(exePath, exeData, 0o755))
+
+			u := updater.NewUpdater(&updater.Config{
+				Client:             srv.Client(),
+				Logger:             testLogger,
+				CommandConstructor: testCmdCons,
+				GOARCH:             "amd64",
+				GOOS:               "linux",
+				Version:            "v0.103.0",
+				ConfName:           filepath.Join(wd, "AdGuardHome.yaml"),
+				WorkDir:            wd,
+				ExecPath:           exePath,
+				VersionCheckURL:    versionCheckURL,
+				SigningKeys:        tc.keys,
+			})
+
+			assert.Nil(t, u.Verification())
+
+			ctx := testutil.ContextWithTimeout(t, testTimeout)
+			_, err = u.VersionInfo(ctx, false)
+			require.NoError(t, err)
+
+			err = u.Update(ctx, true)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			d, rErr := os.ReadFile(exePath)
+			require.NoError(t, rErr)
+
+			assert.Equal(t, tc.wantExe, string(d))
+
+			v := u.Verification()
+			require.NotNil(t, v)
+
+			assert.Equal(t, tc.wantStatus, v.Status)
+			assert.Equal(t, tc.wantMethod, v.Method)
+			assert.Equal(t, "v0.103.0-beta.2", v.Version)
+		})
+	}
+}
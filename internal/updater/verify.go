@@ -0,0 +1,241 @@
+package updater
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/ioutil"
+)
+
+// Verification statuses of the downloaded update.
+const (
+	// VerificationStatusVerified means that the checksum of the update matches
+	// the published one and the list of checksums is signed with one of the
+	// trusted keys.
+	VerificationStatusVerified = "verified"
+
+	// VerificationStatusChecksum means that the checksum of the update matches
+	// the published one, but no trusted keys are configured.
+	VerificationStatusChecksum = "checksum"
+
+	// VerificationStatusUnverified means that the release doesn't publish
+	// any checksums and no trusted keys are configured.
+	VerificationStatusUnverified = "unverified"
+
+	// VerificationStatusFailed means that the update has been rejected.
+	VerificationStatusFailed = "failed"
+)
+
+// Methods of downloading the update.
+const (
+	// DownloadMethodFull means that the full package has been downloaded.
+	DownloadMethodFull = "full"
+
+	// DownloadMethodDelta means that the delta against the current executable
+	// has been downloaded.
+	DownloadMethodDelta = "delta"
+)
+
+// Verification is the result of the verification of the last downloaded
+// update.
+type Verification struct {
+	// Time is the time of the verification.
+	Time time.Time `json:"time"`
+
+	// Version is the version of the update.
+	Version string `json:"version"`
+
+	// Status is the verification status, see [VerificationStatusVerified]
+	// and the related constants.
+	Status string `json:"status"`
+
+	// Method is the method of downloading the update, see
+	// [DownloadMethodFull] and [DownloadMethodDelta].
+	Method string `json:"method"`
+
+	// Error is the reason of the failure, if any.
+	Error string `json:"error,omitempty"`
+}
+
+const (
+	// errNoChecksums is returned when the trusted keys are configured, but the
+	// release doesn't publish the signed checksums.
+	errNoChecksums errors.Error = "release has no signed checksums"
+
+	// errBadSignature is returned when the checksums aren't signed with any of
+	// the trusted keys.
+	errBadSignature errors.Error = "checksums signature doesn't match any trusted key"
+)
+
+// maxChecksumsSize is the maximum size of the checksums and the signature
+// files.
+const maxChecksumsSize = 64 * 1024
+
+// Verification returns the result of the verification of the last downloaded
+// update, or nil if there were no updates.
+func (u *Updater) Verification() (v *Verification) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	if u.verification == nil {
+		return nil
+	}
+
+	cp := *u.verification
+
+	return &cp
+}
+
+// checksums is a set of SHA-256 checksums of the release files by their base
+// names.
+type checksums map[string][]byte
+
+// fetchChecksums downloads and, if there are trusted keys, authenticates the
+// checksums of the release files.  sums is nil if the release doesn't publish
+// any checksums and there are no trusted keys.
+func (u *Updater) fetchChecksums(ctx context.Context) (sums checksums, signed bool, err error) {
+	if u.checksumsURL == "" {
+		if len(u.signingKeys) > 0 {
+			return nil, false, errNoChecksums
+		}
+
+		return nil, false, nil
+	}
+
+	data, err := u.fetch(ctx, u.checksumsURL, maxChecksumsSize)
+	if err != nil {
+		return nil, false, fmt.Errorf("fetching checksums: %w", err)
+	}
+
+	if len(u.signingKeys) > 0 {
+		if u.signatureURL == "" {
+			return nil, false, errNoChecksums
+		}
+
+		err = u.checkSignature(ctx, data)
+		if err != nil {
+			return nil, false, fmt.Errorf("checking signature: %w", err)
+		}
+
+		signed = true
+	}
+
+	sums, err = parseChecksums(data)
+	if err != nil {
+		return nil, false, fmt.Errorf("parsing checksums: %w", err)
+	}
+
+	return sums, signed, nil
+}
+
+// checkSignature returns an error if data isn't signed by any of the trusted
+// keys.
+func (u *Updater) checkSignature(ctx context.Context, data []byte) (err error) {
+	sig, err := u.fetch(ctx, u.signatureURL, maxChecksumsSize)
+	if err != nil {
+		// Don't wrap the error, because it's informative enough as is.
+		return err
+	}
+
+	if len(sig) != ed25519.SignatureSize {
+		sig, err = base64.StdEncoding.DecodeString(string(bytes.TrimSpace(sig)))
+		if err != nil {
+			return fmt.Errorf("decoding signature: %w", err)
+		}
+	}
+
+	for _, key := range u.signingKeys {
+		if ed25519.Verify(key, data, sig) {
+			return nil
+		}
+	}
+
+	return errBadSignature
+}
+
+// parseChecksums parses data in the format of the sha256sum utility.
+func parseChecksums(data []byte) (sums checksums, err error) {
+	sums = checksums{}
+
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for lineNum := 1; s.Scan(); lineNum++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" {
+			continue
+		}
+
+		sumHex, name, ok := strings.Cut(line, " ")
+		if !ok {
+			return nil, fmt.Errorf("line %d: no file name", lineNum)
+		}
+
+		var sum []byte
+		sum, err = hex.DecodeString(sumHex)
+		if err != nil || len(sum) != sha256.Size {
+			return nil, fmt.Errorf("line %d: bad sha256 checksum %q", lineNum, sumHex)
+		}
+
+		// Binary mode is marked with an asterisk.
+		name = strings.TrimPrefix(strings.TrimSpace(name), "*")
+		sums[name] = sum
+	}
+
+	return sums, s.Err()
+}
+
+// verify returns an error if sums is not nil and doesn't contain the matching
+// checksum of data for the file name.
+func (sums checksums) verify(name string, data []byte) (err error) {
+	if sums == nil {
+		return nil
+	}
+
+	want, ok := sums[name]
+	if !ok {
+		return fmt.Errorf("no checksum for %q", name)
+	}
+
+	got := sha256.Sum256(data)
+	if !bytes.Equal(got[:], want) {
+		return fmt.Errorf("checksum mismatch for %q: got %x, want %x", name, got, want)
+	}
+
+	return nil
+}
+
+// fetch downloads the resource at rawURL reading at most limit bytes.
+func (u *Updater) fetch(ctx context.Context, rawURL string, limit uint64) (data []byte, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("constructing request: %w", err)
+	}
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("requesting: %w", err)
+	}
+	defer func() { err = errors.WithDeferred(err, resp.Body.Close()) }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got status code %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	// This use of ReadAll is safe, because we limited body's Reader.
+	data, err = io.ReadAll(ioutil.LimitReader(resp.Body, limit))
+	if err != nil {
+		return nil, fmt.Errorf("reading body: %w", err)
+	}
+
+	return data, nil
+}
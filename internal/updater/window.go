@@ -0,0 +1,100 @@
+package updater
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MaintenanceWindow is a recurring period of local time during which automatic
+// updates are allowed.
+type MaintenanceWindow struct {
+	// Days are the weekdays on which the window starts.  If empty, the window
+	// starts every day.
+	Days []time.Weekday
+
+	// Start is the offset of the start of the window from midnight.
+	Start time.Duration
+
+	// End is the offset of the end of the window from midnight.  If it's less
+	// than Start, the window ends on the next day.
+	End time.Duration
+}
+
+// ParseMaintenanceWindow parses the window from the English names of weekdays,
+// case-insensitive, and the start and end times in the "15:04" format.
+func ParseMaintenanceWindow(days []string, start, end string) (w *MaintenanceWindow, err error) {
+	w = &MaintenanceWindow{}
+	for _, d := range days {
+		wd, ok := parseWeekday(d)
+		if !ok {
+			return nil, fmt.Errorf("bad weekday %q", d)
+		}
+
+		w.Days = append(w.Days, wd)
+	}
+
+	w.Start, err = parseTimeOfDay(start)
+	if err != nil {
+		return nil, fmt.Errorf("start: %w", err)
+	}
+
+	w.End, err = parseTimeOfDay(end)
+	if err != nil {
+		return nil, fmt.Errorf("end: %w", err)
+	}
+
+	if w.Start == w.End {
+		return nil, fmt.Errorf("window %s-%s is empty", start, end)
+	}
+
+	return w, nil
+}
+
+// parseWeekday parses the English name of a weekday.
+func parseWeekday(s string) (wd time.Weekday, ok bool) {
+	for wd = time.Sunday; wd <= time.Saturday; wd++ {
+		if strings.EqualFold(s, wd.String()) {
+			return wd, true
+		}
+	}
+
+	return 0, false
+}
+
+// parseTimeOfDay parses s in the "15:04" format and returns its offset from
+// midnight.
+func parseTimeOfDay(s string) (d time.Duration, err error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		// Don't wrap the error, because it's informative enough as is.
+		return 0, err
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Contains returns true if t is within the window in t's location.
+func (w *MaintenanceWindow) Contains(t time.Time) (ok bool) {
+	y, m, d := t.Date()
+	sinceMidnight := t.Sub(time.Date(y, m, d, 0, 0, 0, 0, t.Location()))
+
+	if w.Start < w.End {
+		return w.startsOn(t.Weekday()) && sinceMidnight >= w.Start && sinceMidnight < w.End
+	}
+
+	// The window crosses midnight.
+	if sinceMidnight >= w.Start {
+		return w.startsOn(t.Weekday())
+	}
+
+	prev := (t.Weekday() + 6) % 7
+
+	return sinceMidnight < w.End && w.startsOn(prev)
+}
+
+// startsOn returns true if the window starts on wd.
+func (w *MaintenanceWindow) startsOn(wd time.Weekday) (ok bool) {
+	return len(w.Days) == 0 || slices.Contains(w.Days, wd)
+}
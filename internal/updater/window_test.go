@@ -0,0 +1,129 @@
+package updater_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/AdGuardHome/internal/updater"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMaintenanceWindow(t *testing.T) {
+	testCases := []struct {
+		want       *updater.MaintenanceWindow
+		name       string
+		start      string
+		end        string
+		wantErrMsg string
+		days       []string
+	}{{
+		want: &updater.MaintenanceWindow{
+			Days:  []time.Weekday{time.Sunday},
+			Start: 3 * time.Hour,
+			End:   4 * time.Hour,
+		},
+		name:       "success",
+		start:      "03:00",
+		end:        "04:00",
+		wantErrMsg: "",
+		days:       []string{"sunday"},
+	}, {
+		want: &updater.MaintenanceWindow{
+			Start: 23*time.Hour + 30*time.Minute,
+			End:   time.Hour,
+		},
+		name:       "every_day",
+		start:      "23:30",
+		end:        "01:00",
+		wantErrMsg: "",
+		days:       nil,
+	}, {
+		want:       nil,
+		name:       "bad_day",
+		start:      "03:00",
+		end:        "04:00",
+		wantErrMsg: `bad weekday "sun"`,
+		days:       []string{"sun"},
+	}, {
+		want:       nil,
+		name:       "bad_start",
+		start:      "3am",
+		end:        "04:00",
+		wantErrMsg: `start: parsing time "3am" as "15:04": cannot parse "am" as ":"`,
+		days:       nil,
+	}, {
+		want:       nil,
+		name:       "empty",
+		start:      "03:00",
+		end:        "03:00",
+		wantErrMsg: "window 03:00-03:00 is empty",
+		days:       nil,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w, err := updater.ParseMaintenanceWindow(tc.days, tc.start, tc.end)
+			testutil.AssertErrorMsg(t, tc.wantErrMsg, err)
+
+			assert.Equal(t, tc.want, w)
+		})
+	}
+}
+
+func TestMaintenanceWindow_Contains(t *testing.T) {
+	sunday, err := updater.ParseMaintenanceWindow([]string{"Sunday"}, "03:00", "04:00")
+	require.NoError(t, err)
+
+	overnight, err := updater.ParseMaintenanceWindow([]string{"Saturday"}, "23:00", "01:00")
+	require.NoError(t, err)
+
+	// 2024-06-02 is a Sunday.
+	at := func(day, hour, minute int) (t time.Time) {
+		return time.Date(2024, time.June, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	testCases := []struct {
+		w    *updater.MaintenanceWindow
+		at   time.Time
+		name string
+		want bool
+	}{{
+		w:    sunday,
+		at:   at(2, 3, 30),
+		name: "inside",
+		want: true,
+	}, {
+		w:    sunday,
+		at:   at(2, 4, 0),
+		name: "end",
+		want: false,
+	}, {
+		w:    sunday,
+		at:   at(3, 3, 30),
+		name: "other_day",
+		want: false,
+	}, {
+		w:    overnight,
+		at:   at(1, 23, 30),
+		name: "overnight_before_midnight",
+		want: true,
+	}, {
+		w:    overnight,
+		at:   at(2, 0, 30),
+		name: "overnight_after_midnight",
+		want: true,
+	}, {
+		w:    overnight,
+		at:   at(1, 0, 30),
+		name: "overnight_other_day",
+		want: false,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.w.Contains(tc.at))
+		})
+	}
+}
@@ -14,9 +14,10 @@ import (
 
 // Embed the prebuilt client here since we strive to keep .go files inside the
 // internal directory and the embed package is unable to embed files located
-// outside of the same or underlying directory.
+// outside of the same or underlying directory.  The translation files are used
+// to localize the notifications.
 
-//go:embed build
+//go:embed build client/src/__locales/*.json
 var clientBuildFS embed.FS
 
 func main() {
@@ -1,5 +1,5 @@
 // translations downloads translations, uploads translations, prints summary
-// for translations, prints unused strings.
+// for translations, prints unused strings, validates translations.
 package main
 
 import (
@@ -94,7 +94,10 @@ func main() {
 		errors.Check(unused(ctx, l, homeConf.LocalizableFiles[0]))
 	case "upload":
 		cli = errors.Must(newTwoskyClient(homeConf))
-		errors.Check(cli.upload())
+		errors.Check(cli.upload(ctx, l))
+	case "validate":
+		cli = errors.Must(newTwoskyClient(homeConf))
+		errors.Check(cli.validate())
 	case "auto-add":
 		errors.Check(autoAdd(ctx, l, homeConf.LocalizableFiles[0]))
 	default:
@@ -115,8 +118,10 @@ Commands:
         Download translations.  count is a number of concurrent downloads.
   unused
         Print unused strings.
-  upload
-        Upload translations.
+  upload [-n <count>]
+        Upload translations.  count is a number of concurrent uploads.
+  validate
+        Check locales for missing strings and mismatched placeholders.
   auto-add
 		Add locales with additions to the git and restore locales with
 		deletions.`
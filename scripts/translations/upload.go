@@ -2,8 +2,11 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"flag"
 	"fmt"
 	"io"
+	"log/slog"
 	"maps"
 	"mime/multipart"
 	"net/http"
@@ -11,23 +14,111 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"sync"
 
 	"github.com/AdguardTeam/AdGuardHome/internal/aghhttp"
 	"github.com/AdguardTeam/golibs/errors"
 	"github.com/AdguardTeam/golibs/httphdr"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+	"github.com/AdguardTeam/golibs/syncutil"
+	"github.com/AdguardTeam/golibs/validate"
 )
 
-// upload uploads the base locale file.
-func (c *twoskyClient) upload() (err error) {
+// upload uploads the base locale files concurrently.
+func (c *twoskyClient) upload(ctx context.Context, l *slog.Logger) (err error) {
 	defer func() { err = errors.Annotate(err, "upload: %w") }()
 
+	numWorker, err := parseUploadArgs()
+	if err != nil {
+		usage(err.Error())
+	}
+
+	wg := &sync.WaitGroup{}
+	pathCh := make(chan string, numWorker)
+
+	uw := &uploadWorker{
+		ctx:    ctx,
+		l:      l,
+		cli:    c,
+		failed: syncutil.NewMap[string, struct{}](),
+		pathCh: pathCh,
+	}
+
+	for range numWorker {
+		wg.Go(uw.run)
+	}
+
+	for _, baseFile := range c.localizableFiles {
+		pathCh <- baseFile
+	}
+
+	close(pathCh)
+	wg.Wait()
+
+	var failed []string
+	for k := range uw.failed.Range {
+		failed = append(failed, k)
+	}
+
+	if len(failed) > 0 {
+		slices.Sort(failed)
+
+		return fmt.Errorf("failed to upload %q", failed)
+	}
+
+	return nil
+}
+
+// parseUploadArgs parses command-line arguments for the upload command.
+func parseUploadArgs() (numWorker int, err error) {
+	flagSet := flag.NewFlagSet("upload", flag.ExitOnError)
+	flagSet.IntVar(&numWorker, "n", 1, "number of concurrent uploads")
+
+	err = flagSet.Parse(os.Args[2:])
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return 0, err
+	}
+
+	return numWorker, validate.Positive("count", numWorker)
+}
+
+// uploadWorker is a worker for uploading the base locale files.  It uses paths
+// received from the channel to upload files.  Failures are stored in the
+// failed map.  All fields must not be nil.
+type uploadWorker struct {
+	ctx    context.Context
+	l      *slog.Logger
+	cli    *twoskyClient
+	failed *syncutil.Map[string, struct{}]
+	pathCh <-chan string
+}
+
+// run handles the channel of paths, one by one.  It returns when the channel is
+// closed.  It's used to be run in a separate goroutine.
+func (w *uploadWorker) run() {
+	for basePath := range w.pathCh {
+		err := w.cli.uploadFile(basePath)
+		if err != nil {
+			w.l.ErrorContext(w.ctx, "upload worker", "path", basePath, slogutil.KeyError, err)
+			w.failed.Store(basePath, struct{}{})
+
+			continue
+		}
+
+		fmt.Println(basePath)
+	}
+}
+
+// uploadFile uploads the base locale file at basePath.
+func (c *twoskyClient) uploadFile(basePath string) (err error) {
 	uploadURI := c.uri.JoinPath("upload")
-	basePath := filepath.Join(localesDirHome, defaultBaseFile)
+	fileName := filepath.Base(basePath)
 
 	formData := map[string]string{
 		"format":   "json",
 		"language": string(c.baseLang),
-		"filename": defaultBaseFile,
+		"filename": fileName,
 		"project":  c.projectID,
 	}
 
@@ -69,7 +160,7 @@ func prepareMultipartMsg(
 	h := make(textproto.MIMEHeader)
 	h.Set(httphdr.ContentType, aghhttp.HdrValApplicationJSON)
 
-	d := fmt.Sprintf("form-data; name=%q; filename=%q", "file", defaultBaseFile)
+	d := fmt.Sprintf("form-data; name=%q; filename=%q", "file", filepath.Base(basePath))
 	h.Set(httphdr.ContentDisposition, d)
 
 	fw, err = w.CreatePart(h)
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// placeholderRe matches the interpolation placeholders, like "{{count}}", and
+// the component tags, like "<0>" and "</0>", of the translations.
+var placeholderRe = regexp.MustCompile(`\{\{\s*[^{}\s]+\s*\}\}|</?\d+>`)
+
+// localeReport is the list of problems found in a single locale.
+type localeReport struct {
+	// path is the path to the locale file.
+	path string
+
+	// problems are the descriptions of the problems found.
+	problems []string
+}
+
+// validate checks every locale of every localizable file for missing text
+// labels and mismatched placeholders against the base locale and prints the
+// report.  Missing text labels are only an error for the languages that need
+// to be fully translated, see [blockerLangCodes].
+func (c *twoskyClient) validate() (err error) {
+	defer func() { err = errors.Annotate(err, "validate: %w") }()
+
+	var reports []*localeReport
+	for _, basePath := range c.localizableFiles {
+		var fileReports []*localeReport
+		fileReports, err = c.validateFile(basePath)
+		if err != nil {
+			return fmt.Errorf("base file %q: %w", basePath, err)
+		}
+
+		reports = append(reports, fileReports...)
+	}
+
+	for _, r := range reports {
+		fmt.Printf("%s:\n", r.path)
+		for _, p := range r.problems {
+			fmt.Printf("  %s\n", p)
+		}
+	}
+
+	if len(reports) > 0 {
+		return fmt.Errorf("%d locales are invalid", len(reports))
+	}
+
+	return nil
+}
+
+// validateFile returns the reports for the locales of the base file at
+// basePath, which contain problems.
+func (c *twoskyClient) validateFile(basePath string) (reports []*localeReport, err error) {
+	baseLoc, err := readLocales(basePath)
+	if err != nil {
+		// Don't wrap the error since it's informative enough as is.
+		return nil, err
+	}
+
+	dir := filepath.Dir(basePath)
+	for _, lang := range c.langs {
+		if lang == c.baseLang {
+			continue
+		}
+
+		path := filepath.Join(dir, string(lang)+".json")
+
+		var loc locales
+		loc, err = readLocales(path)
+		if err != nil {
+			reports = append(reports, &localeReport{
+				path:     path,
+				problems: []string{fmt.Sprintf("reading: %s", err)},
+			})
+
+			continue
+		}
+
+		problems := validateLocale(baseLoc, loc, slices.Contains(blockerLangCodes, lang))
+		if len(problems) > 0 {
+			reports = append(reports, &localeReport{
+				path:     path,
+				problems: problems,
+			})
+		}
+	}
+
+	return reports, nil
+}
+
+// validateLocale returns the sorted descriptions of the problems of loc
+// compared to baseLoc.  If isBlocker is true, missing text labels are reported
+// as well.
+func validateLocale(baseLoc, loc locales, isBlocker bool) (problems []string) {
+	var missing []string
+	for _, label := range slices.Sorted(maps.Keys(baseLoc)) {
+		val, ok := loc[label]
+		if !ok {
+			if isBlocker {
+				missing = append(missing, string(label))
+			}
+
+			continue
+		}
+
+		want, got := placeholders(baseLoc[label]), placeholders(val)
+		if !slices.Equal(want, got) {
+			problems = append(problems, fmt.Sprintf(
+				"%q: placeholders mismatch: got %q, want %q",
+				label,
+				got,
+				want,
+			))
+		}
+	}
+
+	if len(missing) > 0 {
+		problems = append(problems, fmt.Sprintf("missing: %s", strings.Join(missing, ", ")))
+	}
+
+	return problems
+}
+
+// placeholders returns the sorted placeholders of the translation s with the
+// whitespace removed.
+func placeholders(s string) (res []string) {
+	res = placeholderRe.FindAllString(s, -1)
+	for i, p := range res {
+		res[i] = strings.Join(strings.Fields(p), "")
+	}
+
+	slices.Sort(res)
+
+	return res
+}